@@ -15,6 +15,7 @@ import (
 
 	"github.com/roborev-dev/roborev/internal/agent"
 	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/daemon"
 	"github.com/roborev-dev/roborev/internal/git"
 	"github.com/roborev-dev/roborev/internal/prompt/analyze"
 	"github.com/roborev-dev/roborev/internal/storage"
@@ -511,21 +512,21 @@ func enqueueAnalysisJob(repoRoot, prompt, outputPrefix, label string, opts analy
 		"agentic":       true, // Agentic mode needed for reading files when prompt exceeds size limit
 	})
 
-	resp, err := http.Post(serverAddr+"/api/enqueue", "application/json", bytes.NewReader(reqBody))
+	resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/enqueue", reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("enqueue failed: %s", readErrorBody(resp.Body, resp.Status))
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("enqueue failed: %s", body)
-	}
-
 	var job storage.ReviewJob
 	if err := json.Unmarshal(body, &job); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -685,9 +686,9 @@ func waitForAnalysisJob(ctx context.Context, serverAddr string, jobID int64) (*s
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
+			msg := readErrorBody(resp.Body, resp.Status)
 			resp.Body.Close()
-			return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, body)
+			return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, msg)
 		}
 
 		var jobsResp struct {
@@ -719,8 +720,8 @@ func waitForAnalysisJob(ctx context.Context, serverAddr string, jobID int64) (*s
 			defer reviewResp.Body.Close()
 
 			if reviewResp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(reviewResp.Body)
-				return nil, fmt.Errorf("fetch review (%d): %s", reviewResp.StatusCode, body)
+				msg := readErrorBody(reviewResp.Body, reviewResp.Status)
+				return nil, fmt.Errorf("fetch review (%d): %s", reviewResp.StatusCode, msg)
 			}
 
 			var review storage.Review
@@ -856,8 +857,8 @@ func markJobAddressed(serverAddr string, jobID int64) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("mark addressed failed: %s", body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("mark addressed failed: %s", msg)
 	}
 	return nil
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+func applySuggestionCmd() *cobra.Command {
+	var (
+		repoPath string
+		commit   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply-suggestion <job_id>",
+		Short: "Apply a patch suggested in a review",
+		Long: `Apply the patch a review agent suggested inline in its output.
+
+When a review contains a fenced diff block, roborev stores it as the job's
+patch. This command applies that patch to your working tree without
+running a separate fix job. By default the changes are left uncommitted
+for you to inspect; use --commit to commit them.
+
+Examples:
+  roborev apply-suggestion 42
+  roborev apply-suggestion 42 --commit
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || jobID <= 0 {
+				return fmt.Errorf("invalid job ID: %s", args[0])
+			}
+
+			if repoPath == "" {
+				repoPath = "."
+			}
+			root, err := git.GetRepoRoot(repoPath)
+			if err != nil {
+				return fmt.Errorf("not a git repository: %w", err)
+			}
+
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+			addr := getDaemonAddr()
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			patch, err := fetchJobPatch(ctx, addr, jobID)
+			if err != nil {
+				return err
+			}
+			if err := applySuggestedPatch(root, patch); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if !commit {
+				fmt.Fprintf(out, "Applied suggestion from job #%d (uncommitted)\n", jobID)
+				return nil
+			}
+			msg := fmt.Sprintf("fix: apply roborev suggestion from job #%d", jobID)
+			if job, err := fetchJob(ctx, addr, jobID); err == nil && job.GitRef != "" && !job.IsDirtyJob() {
+				msg = fmt.Sprintf("fix: apply roborev suggestion for %s (job #%d)", git.ShortSHA(job.GitRef), jobID)
+			}
+			if err := commitPatch(root, patch, msg); err != nil {
+				return fmt.Errorf("patch applied but commit failed: %w", err)
+			}
+			fmt.Fprintf(out, "Applied and committed suggestion from job #%d\n", jobID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repoPath, "repo", "", "path to git repository (default: current directory)")
+	cmd.Flags().BoolVar(&commit, "commit", false, "commit the applied changes")
+
+	return cmd
+}
+
+// applySuggestedPatch applies patch to the working tree at repoPath after
+// checking that it applies cleanly and won't clobber uncommitted edits.
+func applySuggestedPatch(repoPath, patch string) error {
+	files, err := patchFiles(patch)
+	if err != nil {
+		return err
+	}
+	dirty, err := dirtyPatchFiles(repoPath, files)
+	if err != nil {
+		return fmt.Errorf("checking dirty files: %w", err)
+	}
+	if len(dirty) > 0 {
+		return fmt.Errorf("uncommitted changes in patch files: %s — stash or commit first", strings.Join(dirty, ", "))
+	}
+	if err := worktree.CheckPatch(repoPath, patch); err != nil {
+		var conflictErr *worktree.PatchConflictError
+		if errors.As(err, &conflictErr) {
+			return fmt.Errorf("suggested patch does not apply to the current tree: %w", err)
+		}
+		return err
+	}
+	return worktree.ApplyPatch(repoPath, patch)
+}
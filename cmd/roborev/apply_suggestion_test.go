@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestApplySuggestedPatch(t *testing.T) {
+	repo := newTestGitRepo(t)
+	repo.CommitFile("greet.txt", "hello\n", "initial commit")
+
+	review := "- Medium: greeting is incomplete\n\n" +
+		"```diff\n" +
+		"--- a/greet.txt\n" +
+		"+++ b/greet.txt\n" +
+		"@@ -1 +1 @@\n" +
+		"-hello\n" +
+		"+hello, world\n" +
+		"```\n"
+	patch := storage.ExtractSuggestedPatch(review)
+	if patch == "" {
+		t.Fatal("expected a patch to be extracted")
+	}
+
+	if err := applySuggestedPatch(repo.Dir, patch); err != nil {
+		t.Fatalf("applySuggestedPatch failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(repo.Dir, "greet.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello, world\n" {
+		t.Errorf("greet.txt = %q after apply", data)
+	}
+
+	// Applying again overlaps the now-dirty file and must be refused.
+	err = applySuggestedPatch(repo.Dir, patch)
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Errorf("expected dirty-file error, got %v", err)
+	}
+}
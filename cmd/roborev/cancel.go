@@ -0,0 +1,170 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func cancelCmd() *cobra.Command {
+	var (
+		all            bool
+		queued         bool
+		running        bool
+		includeRunning bool
+		repoPath       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cancel [job_id]",
+		Short: "Cancel a queued or running job, or all of a repo's queued jobs",
+		Long: `Cancel a queued or running job. Running jobs have their agent stopped.
+
+With --all, cancels every queued job in the current repo (or --repo) in
+one step, e.g. after a bad config queued a batch of reviews. Running
+jobs are left alone unless --include-running is given; --running
+cancels only running jobs.
+
+Examples:
+  roborev cancel 42
+  roborev cancel --all
+  roborev cancel --all --include-running --repo ~/src/api
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !all {
+				if len(args) == 0 {
+					return fmt.Errorf("specify a job ID to cancel, or --all")
+				}
+				if queued || running || includeRunning || repoPath != "" {
+					return fmt.Errorf("--queued, --running, --include-running, and --repo require --all")
+				}
+				jobID, err := strconv.ParseInt(args[0], 10, 64)
+				if err != nil || jobID <= 0 {
+					return fmt.Errorf("invalid job ID: %s", args[0])
+				}
+				if err := postCancel("/api/job/cancel", daemon.CancelJobRequest{JobID: jobID}, nil); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Canceled job %d\n", jobID)
+				return nil
+			}
+
+			if len(args) > 0 {
+				return fmt.Errorf("--all cancels many jobs and takes no job ID")
+			}
+			if running && (queued || includeRunning) {
+				return fmt.Errorf("--running is mutually exclusive with --queued and --include-running")
+			}
+			req := daemon.CancelJobsBulkRequest{Status: storage.JobStatusQueued}
+			label := "queued"
+			switch {
+			case running:
+				req.Status, label = storage.JobStatusRunning, "running"
+			case includeRunning:
+				req.Status, label = "", "queued or running"
+			}
+
+			if repoPath == "" {
+				repoPath = "."
+			}
+			root, err := git.GetMainRepoRoot(repoPath)
+			if err != nil {
+				return fmt.Errorf("not a git repository: %s", repoPath)
+			}
+			repoID, err := lookupRepoID(root)
+			if errors.Is(err, sql.ErrNoRows) {
+				fmt.Fprintf(cmd.OutOrStdout(), "No %s jobs to cancel\n", label)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			req.RepoID = repoID
+
+			var result struct {
+				Canceled int `json:"canceled"`
+			}
+			if err := postCancel("/api/job/cancel-bulk", req, &result); err != nil {
+				return err
+			}
+			if result.Canceled == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No %s jobs to cancel\n", label)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Canceled %d %s job(s)\n", result.Canceled, label)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "cancel every queued job in the repo")
+	cmd.Flags().BoolVar(&queued, "queued", false, "with --all, cancel only queued jobs (the default)")
+	cmd.Flags().BoolVar(&running, "running", false, "with --all, cancel only running jobs")
+	cmd.Flags().BoolVar(&includeRunning, "include-running", false, "with --all, also cancel running jobs")
+	cmd.Flags().StringVar(&repoPath, "repo", "", "with --all, cancel jobs for this repo path (default: current repo)")
+
+	return cmd
+}
+
+// lookupRepoID returns the ID of the repo at root, or sql.ErrNoRows if
+// roborev has never recorded a job for it.
+func lookupRepoID(root string) (int64, error) {
+	dbPath := storage.DefaultDBPath()
+	if dbPath == "" {
+		return 0, fmt.Errorf("cannot determine database path")
+	}
+	db, err := storage.Open(dbPath)
+	if err != nil {
+		return 0, fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	repo, err := db.GetRepoByPath(root)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("look up repo: %w", err)
+	}
+	return repo.ID, nil
+}
+
+// postCancel sends a cancel request to the daemon, decoding the response
+// into result when it is non-nil.
+func postCancel(path string, req, result any) error {
+	if err := ensureDaemon(); err != nil {
+		return fmt.Errorf("daemon not running: %w", err)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := daemon.PostWithRetry(client, getDaemonAddr()+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("cancel failed: %s", msg)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestCancelCmd(t *testing.T) {
+	repo := newTestGitRepo(t)
+
+	var gotPath string
+	var gotSingle daemon.CancelJobRequest
+	var gotBulk daemon.CancelJobsBulkRequest
+	_, cleanup := setupMockDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		switch r.URL.Path {
+		case "/api/job/cancel":
+			if err := json.NewDecoder(r.Body).Decode(&gotSingle); err != nil {
+				t.Errorf("decode request: %v", err)
+			}
+			if gotSingle.JobID == 99 {
+				respondJSON(w, http.StatusNotFound, map[string]any{
+					"error": map[string]string{"code": "not_found", "message": "job not found"},
+				})
+				return
+			}
+			respondJSON(w, http.StatusOK, map[string]any{"success": true})
+		case "/api/job/cancel-bulk":
+			if err := json.NewDecoder(r.Body).Decode(&gotBulk); err != nil {
+				t.Errorf("decode request: %v", err)
+			}
+			respondJSON(w, http.StatusOK, map[string]int{"canceled": 3})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer cleanup()
+
+	db, err := storage.Open(storage.DefaultDBPath())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	dbRepo, err := db.GetOrCreateRepo(repo.Dir)
+	db.Close()
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := cancelCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	t.Run("single job", func(t *testing.T) {
+		out, err := run("42")
+		if err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+		if gotPath != "/api/job/cancel" || gotSingle.JobID != 42 {
+			t.Errorf("request = %s %+v", gotPath, gotSingle)
+		}
+		if !strings.Contains(out, "Canceled job 42") {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("daemon error shows only the message", func(t *testing.T) {
+		_, err := run("99")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if !strings.Contains(err.Error(), "job not found") || strings.Contains(err.Error(), `{"error"`) {
+			t.Errorf("error = %q, want the envelope message without raw JSON", err)
+		}
+	})
+
+	t.Run("all queued in repo", func(t *testing.T) {
+		out, err := run("--all", "--queued", "--repo", repo.Dir)
+		if err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+		if gotPath != "/api/job/cancel-bulk" || gotBulk.RepoID != dbRepo.ID || gotBulk.Status != storage.JobStatusQueued {
+			t.Errorf("request = %s %+v, want repo %d queued", gotPath, gotBulk, dbRepo.ID)
+		}
+		if !strings.Contains(out, "Canceled 3 queued job(s)") {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("all defaults to queued", func(t *testing.T) {
+		gotBulk = daemon.CancelJobsBulkRequest{}
+		if _, err := run("--all", "--repo", repo.Dir); err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+		if gotBulk.Status != storage.JobStatusQueued {
+			t.Errorf("status = %q, want queued so running jobs are left alone", gotBulk.Status)
+		}
+	})
+
+	t.Run("include running", func(t *testing.T) {
+		gotBulk = daemon.CancelJobsBulkRequest{}
+		out, err := run("--all", "--include-running", "--repo", repo.Dir)
+		if err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+		if gotBulk.Status != "" || gotBulk.RepoID != dbRepo.ID {
+			t.Errorf("request = %+v, want both statuses for repo %d", gotBulk, dbRepo.ID)
+		}
+		if !strings.Contains(out, "Canceled 3 queued or running job(s)") {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("unknown repo has nothing to cancel", func(t *testing.T) {
+		gotPath = ""
+		out, err := run("--all", "--repo", newTestGitRepo(t).Dir)
+		if err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+		if gotPath != "" {
+			t.Errorf("unexpected daemon request to %s", gotPath)
+		}
+		if !strings.Contains(out, "No queued jobs to cancel") {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	for _, tc := range []struct {
+		args    []string
+		wantErr string
+	}{
+		{nil, "specify a job ID"},
+		{[]string{"abc"}, "invalid job ID"},
+		{[]string{"42", "--queued"}, "require --all"},
+		{[]string{"42", "--all"}, "takes no job ID"},
+		{[]string{"42", "--include-running"}, "require --all"},
+		{[]string{"--all", "--queued", "--running"}, "mutually exclusive"},
+		{[]string{"--all", "--running", "--include-running"}, "mutually exclusive"},
+	} {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			if _, err := run(tc.args...); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// noColor is set by the global --no-color flag.
+var noColor bool
+
+// colorDisabled reports whether the user opted out of colored output via
+// --no-color or a non-empty NO_COLOR environment variable (no-color.org).
+func colorDisabled() bool {
+	return noColor || os.Getenv("NO_COLOR") != ""
+}
+
+// colorEnabled reports whether styled output should be written to w: it
+// must be a terminal and color must not be disabled.
+func colorEnabled(w io.Writer) bool {
+	return !colorDisabled() && writerIsTerminal(w)
+}
@@ -195,8 +195,8 @@ func fetchJobBatch(ctx context.Context, ids []int64) (map[int64]storage.JobWithR
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("batch fetch failed (%d): %s", resp.StatusCode, body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return nil, fmt.Errorf("batch fetch failed (%d): %s", resp.StatusCode, msg)
 	}
 
 	var batchResp struct {
@@ -532,21 +532,21 @@ func enqueueCompactJob(repoRoot, prompt, outputPrefix, label, branch string, opt
 		return nil, fmt.Errorf("marshal enqueue request: %w", err)
 	}
 
-	resp, err := http.Post(serverAddr+"/api/enqueue", "application/json", bytes.NewReader(reqBody))
+	resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/enqueue", reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("connect to daemon: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("enqueue failed: %s", readErrorBody(resp.Body, resp.Status))
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("enqueue failed: %s", body)
-	}
-
 	var job storage.ReviewJob
 	if err := json.Unmarshal(body, &job); err != nil {
 		return nil, fmt.Errorf("parse response: %w", err)
@@ -589,15 +589,15 @@ func cancelJob(serverAddr string, jobID int64) error {
 	if err != nil {
 		return fmt.Errorf("marshal cancel request: %w", err)
 	}
-	resp, err := http.Post(serverAddr+"/api/job/cancel", "application/json", bytes.NewReader(reqBody))
+	resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/job/cancel", reqBody)
 	if err != nil {
 		return fmt.Errorf("connect to daemon: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("cancel failed: %s", body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("cancel failed: %s", msg)
 	}
 
 	return nil
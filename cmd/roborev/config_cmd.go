@@ -1,16 +1,24 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/daemon"
 	"github.com/roborev-dev/roborev/internal/git"
 	"github.com/spf13/cobra"
 )
@@ -181,7 +189,10 @@ func configCmd() *cobra.Command {
 
 	cmd.AddCommand(configGetCmd())
 	cmd.AddCommand(configSetCmd())
+	cmd.AddCommand(configUnsetCmd())
 	cmd.AddCommand(configListCmd())
+	cmd.AddCommand(configDiffCmd())
+	cmd.AddCommand(configCopyCmd())
 
 	return cmd
 }
@@ -214,22 +225,57 @@ func configGetCmd() *cobra.Command {
 }
 
 func configSetCmd() *cobra.Command {
-	var globalFlag, localFlag bool
+	var globalFlag, localFlag, unset, apply bool
 
 	cmd := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a configuration value",
-		Args:  cobra.ExactArgs(2),
+		Long: `Set a configuration value.
+
+With --unset, takes only <key> and removes it from the config file (like
+'roborev config unset'), pruning tables left empty.
+
+With --apply, a new max_workers value is also applied to a running daemon
+without a restart. Workers being retired finish their current job first.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if unset {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key, value := args[0], args[1]
+			key := args[0]
 
 			scope, err := determineScope(globalFlag, localFlag)
 			if err != nil {
 				return err
 			}
+			var workers int
+			if apply {
+				if key != "max_workers" || unset {
+					return fmt.Errorf("--apply only supports setting max_workers")
+				}
+				if workers, err = strconv.Atoi(args[1]); err != nil || workers < daemon.MinWorkers || workers > daemon.MaxWorkers {
+					return fmt.Errorf("max_workers must be between %d and %d", daemon.MinWorkers, daemon.MaxWorkers)
+				}
+			}
+
+			// write sets or removes key in the config file at path
+			write := func(path string, isGlobal bool) error {
+				if unset {
+					return unsetConfigKey(path, key, isGlobal)
+				}
+				return setConfigKey(path, key, args[1], isGlobal)
+			}
 
 			if scope == scopeGlobal {
-				return setConfigKey(config.GlobalConfigPath(), key, value, true)
+				if err := write(config.GlobalConfigPath(), true); err != nil {
+					return err
+				}
+				if apply {
+					return applyMaxWorkers(cmd.OutOrStdout(), workers)
+				}
+				return nil
 			}
 
 			// Default (and --local): set in local config
@@ -240,19 +286,88 @@ func configSetCmd() *cobra.Command {
 				}
 				return err
 			}
-			localPath := filepath.Join(repoPath, ".roborev.toml")
-			return setConfigKey(localPath, key, value, false)
+			return write(filepath.Join(repoPath, ".roborev.toml"), false)
 		},
 	}
 
 	cmd.Flags().BoolVar(&globalFlag, "global", false, "set in global config")
 	cmd.Flags().BoolVar(&localFlag, "local", false, "set in local repo config (default)")
+	cmd.Flags().BoolVar(&unset, "unset", false, "remove <key> from the config file instead of setting it")
+	cmd.Flags().BoolVar(&apply, "apply", false, "apply a new max_workers to the running daemon")
+
+	return cmd
+}
+
+// applyMaxWorkers asks a running daemon to resize its worker pool. Without
+// a running daemon the new value simply takes effect on the next start.
+func applyMaxWorkers(out io.Writer, n int) error {
+	info, err := daemon.GetAnyRunningDaemon()
+	if err != nil {
+		fmt.Fprintln(out, "No running daemon; max_workers takes effect on next start")
+		return nil
+	}
+
+	body, err := json.Marshal(daemon.ResizeWorkersRequest{Workers: n})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(fmt.Sprintf("http://%s/api/workers/resize", info.Addr), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("resize workers: %s", msg)
+	}
+	var result daemon.ResizeWorkersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	fmt.Fprintf(out, "Daemon workers: %d -> %d\n", result.Previous, result.Workers)
+	return nil
+}
+
+func configUnsetCmd() *cobra.Command {
+	var globalFlag, localFlag bool
+
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a configuration value",
+		Long:  "Remove a key from a config file so its value falls back to the default.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scope, err := determineScope(globalFlag, localFlag)
+			if err != nil {
+				return err
+			}
+
+			if scope == scopeGlobal {
+				return unsetConfigKey(config.GlobalConfigPath(), args[0], true)
+			}
+
+			// Default (and --local): unset in local config
+			repoPath, err := requireRepoRoot()
+			if err != nil {
+				if errors.Is(err, errNotGitRepository) {
+					return fmt.Errorf("%w (use --global for global config)", errNotGitRepository)
+				}
+				return err
+			}
+			return unsetConfigKey(filepath.Join(repoPath, ".roborev.toml"), args[0], false)
+		},
+	}
+
+	cmd.Flags().BoolVar(&globalFlag, "global", false, "unset in global config")
+	cmd.Flags().BoolVar(&localFlag, "local", false, "unset in local repo config (default)")
 
 	return cmd
 }
 
 func configListCmd() *cobra.Command {
-	var globalFlag, localFlag, showOrigin bool
+	var globalFlag, localFlag, showOrigin, asJSON, showSecrets bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -262,14 +377,18 @@ func configListCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if showSecrets && !asJSON {
+				return fmt.Errorf("--show-secrets requires --json")
+			}
+			format := listFormat{showOrigin: showOrigin, json: asJSON, showSecrets: showSecrets}
 
 			switch scope {
 			case scopeGlobal:
-				return listGlobalConfig()
+				return listGlobalConfig(format)
 			case scopeLocal:
-				return listLocalConfig()
+				return listLocalConfig(format)
 			default:
-				return listMergedConfig(showOrigin)
+				return listMergedConfig(format)
 			}
 		},
 	}
@@ -277,11 +396,28 @@ func configListCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&globalFlag, "global", false, "list global config only")
 	cmd.Flags().BoolVar(&localFlag, "local", false, "list local repo config only")
 	cmd.Flags().BoolVar(&showOrigin, "show-origin", false, "show where each value comes from (global/local/default)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output key/value/origin objects as a JSON array")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "with --json, include sensitive values unmasked")
 
 	return cmd
 }
 
-func listGlobalConfig() error {
+// listFormat controls how config list renders its entries.
+type listFormat struct {
+	showOrigin  bool
+	json        bool
+	showSecrets bool
+}
+
+// configEntryJSON is one entry of config list --json output. Value holds
+// booleans and numbers as native JSON types.
+type configEntryJSON struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Origin string `json:"origin"`
+}
+
+func listGlobalConfig(format listFormat) error {
 	cfg, err := config.LoadGlobal()
 	if err != nil {
 		return fmt.Errorf("load global config: %w", err)
@@ -290,11 +426,15 @@ func listGlobalConfig() error {
 	if err != nil {
 		return fmt.Errorf("load global config: %w", err)
 	}
-	printKeyValues(config.ListExplicitKeys(cfg, raw))
+	kvs := config.ListExplicitKeys(cfg, raw)
+	if format.json {
+		return printConfigJSON(os.Stdout, withOrigin(kvs, "global"), cfg.SensitiveKeys, format.showSecrets)
+	}
+	printKeyValues(kvs, cfg.SensitiveKeys)
 	return nil
 }
 
-func listLocalConfig() error {
+func listLocalConfig(format listFormat) error {
 	repoPath, err := requireRepoRoot()
 	if err != nil {
 		return err
@@ -310,11 +450,19 @@ func listLocalConfig() error {
 	if err != nil {
 		return fmt.Errorf("load repo config: %w", err)
 	}
-	printKeyValues(config.ListExplicitKeys(repoCfg, raw))
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("load global config: %w", err)
+	}
+	kvs := config.ListExplicitKeys(repoCfg, raw)
+	if format.json {
+		return printConfigJSON(os.Stdout, withOrigin(kvs, "local"), cfg.SensitiveKeys, format.showSecrets)
+	}
+	printKeyValues(kvs, cfg.SensitiveKeys)
 	return nil
 }
 
-func listMergedConfig(showOrigin bool) error {
+func listMergedConfig(format listFormat) error {
 	cfg, err := config.LoadGlobal()
 	if err != nil {
 		return fmt.Errorf("load global config: %w", err)
@@ -339,11 +487,14 @@ func listMergedConfig(showOrigin bool) error {
 	}
 
 	kvos := config.MergedConfigWithOrigin(cfg, repoCfg, rawGlobal, rawRepo)
-	if showOrigin {
+	if format.json {
+		return printConfigJSON(os.Stdout, kvos, cfg.SensitiveKeys, format.showSecrets)
+	}
+	if format.showOrigin {
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 		for _, kvo := range kvos {
 			val := kvo.Value
-			if config.IsSensitiveKey(kvo.Key) {
+			if config.IsSensitiveKey(kvo.Key, cfg.SensitiveKeys...) {
 				val = config.MaskValue(val)
 			}
 			fmt.Fprintf(w, "%s\t%s\t%s\n", kvo.Origin, kvo.Key, val)
@@ -353,7 +504,7 @@ func listMergedConfig(showOrigin bool) error {
 
 	for _, kvo := range kvos {
 		val := kvo.Value
-		if config.IsSensitiveKey(kvo.Key) {
+		if config.IsSensitiveKey(kvo.Key, cfg.SensitiveKeys...) {
 			val = config.MaskValue(val)
 		}
 		fmt.Printf("%s=%s\n", kvo.Key, val)
@@ -361,11 +512,250 @@ func listMergedConfig(showOrigin bool) error {
 	return nil
 }
 
-// printKeyValues prints key-value pairs, masking sensitive values
-func printKeyValues(kvs []config.KeyValue) {
+// withOrigin tags every key-value pair from a single config file with origin.
+func withOrigin(kvs []config.KeyValue, origin string) []config.KeyValueOrigin {
+	kvos := make([]config.KeyValueOrigin, len(kvs))
+	for i, kv := range kvs {
+		kvos[i] = config.KeyValueOrigin{Key: kv.Key, Value: kv.Value, Origin: origin}
+	}
+	return kvos
+}
+
+// printConfigJSON writes kvos as a JSON array of configEntryJSON. Sensitive
+// values, including keys matching extraSensitive, are masked unless
+// showSecrets is set.
+func printConfigJSON(w io.Writer, kvos []config.KeyValueOrigin, extraSensitive []string, showSecrets bool) error {
+	entries := make([]configEntryJSON, 0, len(kvos))
+	for _, kvo := range kvos {
+		var val any
+		if !showSecrets && config.IsSensitiveKey(kvo.Key, extraSensitive...) {
+			val = config.MaskValue(kvo.Value)
+		} else {
+			val = config.NativeValue(kvo.Key, kvo.Value)
+		}
+		entries = append(entries, configEntryJSON{Key: kvo.Key, Value: val, Origin: kvo.Origin})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+func configDiffCmd() *cobra.Command {
+	var repos []string
+
+	cmd := &cobra.Command{
+		Use:   "diff --repo <a> --repo <b>",
+		Short: "Show effective config differences between two repos",
+		Long: `Compare the effective (global merged with local) configuration of two
+repositories and list every key whose value differs. Sensitive values
+are masked.
+
+Example:
+  roborev config diff --repo ~/src/api --repo ~/src/web`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(repos) != 2 {
+				return fmt.Errorf("exactly two --repo flags are required")
+			}
+			a, err := resolveRepoArg(repos[0])
+			if err != nil {
+				return err
+			}
+			b, err := resolveRepoArg(repos[1])
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return fmt.Errorf("load global config: %w", err)
+			}
+			rawGlobal, _ := config.LoadRawGlobal()
+
+			kvsA, err := mergedConfigForRepo(cfg, rawGlobal, a)
+			if err != nil {
+				return err
+			}
+			kvsB, err := mergedConfigForRepo(cfg, rawGlobal, b)
+			if err != nil {
+				return err
+			}
+
+			return printConfigDiff(cmd.OutOrStdout(), a, b, diffConfigs(kvsA, kvsB), cfg.SensitiveKeys)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&repos, "repo", nil, "repository to compare (specify twice)")
+
+	return cmd
+}
+
+func configCopyCmd() *cobra.Command {
+	var from, to string
+
+	cmd := &cobra.Command{
+		Use:   "copy --from <repo> --to <repo>",
+		Short: "Copy one repo's local settings to another",
+		Long: `Copy the settings in one repository's .roborev.toml into another's.
+Keys set in the source replace the same keys in the destination; other
+destination keys are kept.
+
+Example:
+  roborev config copy --from ~/src/api --to ~/src/web`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" || to == "" {
+				return fmt.Errorf("--from and --to are required")
+			}
+			src, err := resolveRepoArg(from)
+			if err != nil {
+				return err
+			}
+			dst, err := resolveRepoArg(to)
+			if err != nil {
+				return err
+			}
+			if src == dst {
+				return fmt.Errorf("--from and --to are the same repository")
+			}
+
+			keys, err := copyLocalConfig(src, dst)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Copied %d setting(s) from %s to %s\n", len(keys), src, dst)
+			for _, key := range keys {
+				fmt.Fprintf(out, "  %s\n", key)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "repository to copy settings from")
+	cmd.Flags().StringVar(&to, "to", "", "repository to copy settings to")
+
+	return cmd
+}
+
+// resolveRepoArg returns the repository root containing path.
+func resolveRepoArg(path string) (string, error) {
+	root, err := repoRootFromGit(path)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, errNotGitRepository)
+	}
+	return root, nil
+}
+
+// mergedConfigForRepo returns the effective config for repoPath: the global
+// config overlaid with the repo's .roborev.toml.
+func mergedConfigForRepo(cfg *config.Config, rawGlobal map[string]any, repoPath string) ([]config.KeyValueOrigin, error) {
+	repoCfg, err := config.LoadRepoConfig(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("load repo config for %s: %w", repoPath, err)
+	}
+	rawRepo, err := config.LoadRawRepo(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("load repo config for %s: %w", repoPath, err)
+	}
+	return config.MergedConfigWithOrigin(cfg, repoCfg, rawGlobal, rawRepo), nil
+}
+
+// configDifference is one key whose effective value differs between two
+// repos. A missing value means the key is unset in that repo.
+type configDifference struct {
+	Key  string
+	A, B string
+}
+
+// diffConfigs returns the keys whose values differ between a and b, in
+// key order.
+func diffConfigs(a, b []config.KeyValueOrigin) []configDifference {
+	valsA := make(map[string]string, len(a))
+	valsB := make(map[string]string, len(b))
+	keys := make(map[string]bool)
+	for _, kv := range a {
+		valsA[kv.Key] = kv.Value
+		keys[kv.Key] = true
+	}
+	for _, kv := range b {
+		valsB[kv.Key] = kv.Value
+		keys[kv.Key] = true
+	}
+
+	var diffs []configDifference
+	for key := range keys {
+		va, okA := valsA[key]
+		vb, okB := valsB[key]
+		if okA == okB && va == vb {
+			continue
+		}
+		diffs = append(diffs, configDifference{Key: key, A: va, B: vb})
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
+
+// printConfigDiff writes a key / repo A / repo B table, masking sensitive
+// values and keys matching extraSensitive.
+func printConfigDiff(w io.Writer, repoA, repoB string, diffs []configDifference, extraSensitive []string) error {
+	if len(diffs) == 0 {
+		fmt.Fprintln(w, "No differences")
+		return nil
+	}
+	display := func(key, val string) string {
+		if val == "" {
+			return "(unset)"
+		}
+		if config.IsSensitiveKey(key, extraSensitive...) {
+			return config.MaskValue(val)
+		}
+		return val
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "KEY\t%s\t%s\n", filepath.Base(repoA), filepath.Base(repoB))
+	for _, d := range diffs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", d.Key, display(d.Key, d.A), display(d.Key, d.B))
+	}
+	return tw.Flush()
+}
+
+// copyLocalConfig copies the top-level settings in src's .roborev.toml into
+// dst's, replacing keys dst already sets. Returns the copied keys in order.
+func copyLocalConfig(src, dst string) ([]string, error) {
+	srcRaw, err := loadRawConfig(filepath.Join(src, ".roborev.toml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(srcRaw) == 0 {
+		return nil, fmt.Errorf("no local config (.roborev.toml) found in %s", src)
+	}
+
+	dstPath := filepath.Join(dst, ".roborev.toml")
+	dstRaw, err := loadRawConfig(dstPath)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(srcRaw))
+	for key, val := range srcRaw {
+		dstRaw[key] = val
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := atomicWriteConfig(dstPath, dstRaw, false); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// printKeyValues prints key-value pairs, masking sensitive values and keys
+// matching extraSensitive
+func printKeyValues(kvs []config.KeyValue, extraSensitive []string) {
 	for _, kv := range kvs {
 		val := kv.Value
-		if config.IsSensitiveKey(kv.Key) {
+		if config.IsSensitiveKey(kv.Key, extraSensitive...) {
 			val = config.MaskValue(val)
 		}
 		fmt.Printf("%s=%s\n", kv.Key, val)
@@ -391,6 +781,22 @@ func setConfigKey(path, key, value string, isGlobal bool) error {
 	return atomicWriteConfig(path, raw, isGlobal)
 }
 
+// unsetConfigKey removes a key from a TOML file, pruning tables left
+// empty, and rewrites the file atomically. It fails if the key is not set
+// in the file.
+func unsetConfigKey(path, key string, isGlobal bool) error {
+	raw, err := loadRawConfig(path)
+	if err != nil {
+		return err
+	}
+
+	if !deleteRawMapKey(raw, key) {
+		return fmt.Errorf("key %q is not set in %s", key, path)
+	}
+
+	return atomicWriteConfig(path, raw, isGlobal)
+}
+
 // validateKeyForScope validates a key against the appropriate config struct
 // and returns the populated struct for type coercion.
 func validateKeyForScope(key, value string, isGlobal bool) (any, error) {
@@ -514,6 +920,28 @@ func setRawMapKey(m map[string]any, key string, value any) {
 	current[parts[len(parts)-1]] = value
 }
 
+// deleteRawMapKey removes a dot-separated key from a nested map and
+// deletes any tables it leaves empty. It reports whether the key existed.
+func deleteRawMapKey(m map[string]any, key string) bool {
+	head, rest, nested := strings.Cut(key, ".")
+	if !nested {
+		if _, ok := m[head]; !ok {
+			return false
+		}
+		delete(m, head)
+		return true
+	}
+
+	sub, ok := m[head].(map[string]any)
+	if !ok || !deleteRawMapKey(sub, rest) {
+		return false
+	}
+	if len(sub) == 0 {
+		delete(m, head)
+	}
+	return true
+}
+
 // coerceValue uses the typed config struct to determine the correct TOML type
 // for the given key's value.
 func coerceValue(validationCfg any, key, rawVal string) any {
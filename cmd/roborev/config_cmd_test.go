@@ -1,14 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/BurntSushi/toml"
+	"github.com/roborev-dev/roborev/internal/config"
 )
 
 func setupConfigFile(t *testing.T) string {
@@ -307,7 +311,7 @@ func TestListMergedConfigRepoResolutionError(t *testing.T) {
 	env.SetGitError(errors.New(errGitStub))
 	env.SetWorkingDirError(errors.New(errCwdStub))
 
-	err := listMergedConfig(false)
+	err := listMergedConfig(listFormat{})
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -356,6 +360,142 @@ func TestSetConfigKeyNestedCreation(t *testing.T) {
 	assertConfigValue(t, path, "ci.poll_interval", "10m")
 }
 
+func TestUnsetConfigKey(t *testing.T) {
+	path := setupConfigFile(t)
+
+	for key, val := range map[string]string{
+		"default_agent":    "gemini",
+		"ci.poll_interval": "10m",
+		"ci.enabled":       "true",
+		"sync.enabled":     "true",
+	} {
+		if err := setConfigKey(path, key, val, true); err != nil {
+			t.Fatalf("setConfigKey %s: %v", key, err)
+		}
+	}
+
+	t.Run("TopLevel", func(t *testing.T) {
+		if err := unsetConfigKey(path, "default_agent", true); err != nil {
+			t.Fatalf("unsetConfigKey: %v", err)
+		}
+		if _, ok := readTOML(t, path)["default_agent"]; ok {
+			t.Error("default_agent still present after unset")
+		}
+	})
+
+	t.Run("NestedKeepsSiblings", func(t *testing.T) {
+		if err := unsetConfigKey(path, "ci.poll_interval", true); err != nil {
+			t.Fatalf("unsetConfigKey: %v", err)
+		}
+		assertConfigValue(t, path, "ci.poll_interval", nil)
+		assertConfigValue(t, path, "ci.enabled", true)
+	})
+
+	t.Run("PrunesEmptyTable", func(t *testing.T) {
+		if err := unsetConfigKey(path, "sync.enabled", true); err != nil {
+			t.Fatalf("unsetConfigKey: %v", err)
+		}
+		if _, ok := readTOML(t, path)["sync"]; ok {
+			t.Error("empty sync table not pruned")
+		}
+		assertConfigValue(t, path, "ci.enabled", true)
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		for _, key := range []string{"default_agent", "ci.poll_interval", "nonexistent.key"} {
+			err := unsetConfigKey(path, key, true)
+			if err == nil || !strings.Contains(err.Error(), "is not set") {
+				t.Errorf("unset %s: expected not-set error, got %v", key, err)
+			}
+		}
+	})
+}
+
+func TestConfigSetUnsetFlag(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("ROBOREV_DATA_DIR", dataDir)
+	path := filepath.Join(dataDir, "config.toml")
+
+	if err := setConfigKey(path, "ci.repos", "org/repo1,org/repo2", true); err != nil {
+		t.Fatalf("setConfigKey: %v", err)
+	}
+	if err := setConfigKey(path, "default_agent", "gemini", true); err != nil {
+		t.Fatalf("setConfigKey: %v", err)
+	}
+
+	run := func(args ...string) error {
+		cmd := configSetCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := run("--global", "--unset", "ci.repos"); err != nil {
+		t.Fatalf("config set --unset: %v", err)
+	}
+	raw := readTOML(t, path)
+	if _, ok := raw["ci"]; ok {
+		t.Errorf("empty ci table left behind: %v", raw)
+	}
+	assertConfigValue(t, path, "default_agent", "gemini")
+
+	if err := run("--global", "--unset", "ci.repos"); err == nil || !strings.Contains(err.Error(), "is not set") {
+		t.Errorf("expected not-set error for a missing key, got %v", err)
+	}
+	if err := run("--global", "--unset", "default_agent", "codex"); err == nil {
+		t.Error("expected --unset to reject a value argument")
+	}
+}
+
+func TestConfigSetApplyMaxWorkers(t *testing.T) {
+	var gotReq map[string]any
+	_, cleanup := setupMockDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/workers/resize" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		respondJSON(w, http.StatusOK, map[string]int{"workers": 6, "previous": 4})
+	}))
+	defer cleanup()
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := configSetCmd()
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--global", "--apply", "max_workers", "6")
+	if err != nil {
+		t.Fatalf("config set --apply: %v", err)
+	}
+	assertConfigValue(t, config.GlobalConfigPath(), "max_workers", int64(6))
+	if gotReq["workers"] != float64(6) {
+		t.Errorf("resize request = %v, want workers=6", gotReq)
+	}
+	if !strings.Contains(out, "4 -> 6") {
+		t.Errorf("output %q, want the resize reported", out)
+	}
+
+	gotReq = nil
+	if _, err := run("--global", "--apply", "max_workers", "0"); err == nil {
+		t.Error("expected an out-of-range count to be rejected")
+	}
+	if _, err := run("--global", "--apply", "default_agent", "codex"); err == nil {
+		t.Error("expected --apply to reject keys other than max_workers")
+	}
+	if gotReq != nil {
+		t.Errorf("rejected values reached the daemon: %v", gotReq)
+	}
+	assertConfigValue(t, config.GlobalConfigPath(), "max_workers", int64(6))
+}
+
 func TestSetConfigKeyInvalidKey(t *testing.T) {
 	path := setupConfigFile(t)
 
@@ -493,7 +633,7 @@ func TestListMergedConfigMalformedLocalConfig(t *testing.T) {
 	env := newStubRepoEnv(t)
 	env.SetGitRoot(repoDir)
 
-	err := listMergedConfig(false)
+	err := listMergedConfig(listFormat{})
 	if err == nil {
 		t.Fatal("expected error for malformed local config")
 	}
@@ -502,6 +642,70 @@ func TestListMergedConfigMalformedLocalConfig(t *testing.T) {
 	}
 }
 
+func TestListMergedConfigJSON(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("ROBOREV_DATA_DIR", dataDir)
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte(strings.Join([]string{
+		`max_workers = 8`,
+		`anthropic_api_key = "sk-ant-secret1234"`,
+		``,
+		`[sync]`,
+		`enabled = true`,
+	}, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("write global config: %v", err)
+	}
+	repoDir := createFakeGitRepo(t)
+	if err := os.WriteFile(filepath.Join(repoDir, ".roborev.toml"), []byte("agent = \"codex\"\n"), 0644); err != nil {
+		t.Fatalf("write local config: %v", err)
+	}
+	env := newStubRepoEnv(t)
+	env.SetGitRoot(repoDir)
+
+	decode := func(format listFormat) map[string]configEntryJSON {
+		t.Helper()
+		output := captureOutput(t, func() error { return listMergedConfig(format) })
+		var entries []configEntryJSON
+		if err := json.Unmarshal([]byte(output), &entries); err != nil {
+			t.Fatalf("unmarshal output: %v\n%s", err, output)
+		}
+		byKey := make(map[string]configEntryJSON, len(entries))
+		for _, e := range entries {
+			byKey[e.Key] = e
+		}
+		return byKey
+	}
+
+	entries := decode(listFormat{json: true})
+	if got := entries["max_workers"]; got.Value != float64(8) || got.Origin != "global" {
+		t.Errorf("max_workers = %+v, want numeric 8 from global", got)
+	}
+	if got := entries["sync.enabled"]; got.Value != true {
+		t.Errorf("sync.enabled = %#v, want boolean true", got.Value)
+	}
+	if got := entries["agent"]; got.Value != "codex" || got.Origin != "local" {
+		t.Errorf("agent = %+v, want codex from local", got)
+	}
+	if got := entries["anthropic_api_key"]; got.Value != "****1234" {
+		t.Errorf("anthropic_api_key = %#v, want masked value", got.Value)
+	}
+
+	entries = decode(listFormat{json: true, showSecrets: true})
+	if got := entries["anthropic_api_key"]; got.Value != "sk-ant-secret1234" {
+		t.Errorf("anthropic_api_key with --show-secrets = %#v, want unmasked value", got.Value)
+	}
+}
+
+func TestConfigListShowSecretsRequiresJSON(t *testing.T) {
+	cmd := configListCmd()
+	cmd.SetArgs([]string{"--show-secrets"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--show-secrets requires --json") {
+		t.Fatalf("expected --show-secrets requires --json error, got %v", err)
+	}
+}
+
 func TestListGlobalConfigExplicitKeys(t *testing.T) {
 	dataDir := t.TempDir()
 	t.Setenv("ROBOREV_DATA_DIR", dataDir)
@@ -522,7 +726,7 @@ func TestListGlobalConfigExplicitKeys(t *testing.T) {
 	}
 
 	// Capture stdout
-	output := captureOutput(t, listGlobalConfig)
+	output := captureOutput(t, func() error { return listGlobalConfig(listFormat{}) })
 
 	// Explicit default-valued key should be shown
 	if !strings.Contains(output, "max_workers=4") {
@@ -566,7 +770,7 @@ func TestListLocalConfigExplicitKeys(t *testing.T) {
 	env.SetGitRoot(repoDir)
 
 	// Capture stdout
-	output := captureOutput(t, listLocalConfig)
+	output := captureOutput(t, func() error { return listLocalConfig(listFormat{}) })
 
 	// Explicit key should be shown
 	if !strings.Contains(output, "agent=claude-code") {
@@ -608,3 +812,113 @@ func TestGetValueForScopeMergedRepoOnlyKeyNotSet(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestConfigDiff(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("ROBOREV_DATA_DIR", dataDir)
+	if err := os.WriteFile(filepath.Join(dataDir, "config.toml"), []byte("review_agent = \"global-agent\"\n"), 0644); err != nil {
+		t.Fatalf("write global config: %v", err)
+	}
+
+	repoA := createFakeGitRepo(t)
+	repoB := createFakeGitRepo(t)
+	if err := os.WriteFile(filepath.Join(repoA, ".roborev.toml"), []byte("agent = \"codex\"\nreview_context_count = 3\n"), 0644); err != nil {
+		t.Fatalf("write repo A config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoB, ".roborev.toml"), []byte("agent = \"claude-code\"\nreview_context_count = 3\nreview_agent = \"local-agent\"\n"), 0644); err != nil {
+		t.Fatalf("write repo B config: %v", err)
+	}
+
+	newStubRepoEnv(t)
+	repoRootFromGit = func(path string) (string, error) { return path, nil }
+
+	cmd := configDiffCmd()
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--repo", repoA, "--repo", repoB})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config diff: %v", err)
+	}
+
+	var diffKeys []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n")[1:] {
+		diffKeys = append(diffKeys, strings.Fields(line)[0])
+	}
+	if got, want := strings.Join(diffKeys, ","), "agent,review_agent"; got != want {
+		t.Errorf("differing keys = %s, want %s\n%s", got, want, out.String())
+	}
+	if !strings.Contains(out.String(), "global-agent") || !strings.Contains(out.String(), "local-agent") {
+		t.Errorf("expected both review_agent values in output:\n%s", out.String())
+	}
+}
+
+func TestPrintConfigDiffMasksSensitiveValues(t *testing.T) {
+	var out strings.Builder
+	err := printConfigDiff(&out, "/src/a", "/src/b", []configDifference{
+		{Key: "anthropic_api_key", A: "sk-ant-aaaaaaaa1111", B: "sk-ant-bbbbbbbb2222"},
+		{Key: "agent", A: "codex"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("printConfigDiff: %v", err)
+	}
+	if strings.Contains(out.String(), "sk-ant-") {
+		t.Errorf("sensitive values should be masked:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "(unset)") {
+		t.Errorf("expected unset marker for missing value:\n%s", out.String())
+	}
+}
+
+func TestPrintConfigDiffMasksCustomSensitiveKeys(t *testing.T) {
+	var out strings.Builder
+	err := printConfigDiff(&out, "/src/a", "/src/b", []configDifference{
+		{Key: "post_review_hook", A: "https://hooks.example/t0ken-abcd"},
+	}, []string{"post_*"})
+	if err != nil {
+		t.Fatalf("printConfigDiff: %v", err)
+	}
+	if strings.Contains(out.String(), "t0ken") {
+		t.Errorf("custom sensitive value should be masked:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "****abcd") {
+		t.Errorf("expected last 4 characters to be kept:\n%s", out.String())
+	}
+}
+
+func TestConfigCopy(t *testing.T) {
+	src := createFakeGitRepo(t)
+	dst := createFakeGitRepo(t)
+	if err := os.WriteFile(filepath.Join(src, ".roborev.toml"), []byte("agent = \"codex\"\nexcluded_branches = [\"wip\"]\n"), 0644); err != nil {
+		t.Fatalf("write source config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dst, ".roborev.toml"), []byte("agent = \"claude-code\"\nreview_guidelines = \"keep me\"\n"), 0644); err != nil {
+		t.Fatalf("write destination config: %v", err)
+	}
+
+	keys, err := copyLocalConfig(src, dst)
+	if err != nil {
+		t.Fatalf("copyLocalConfig: %v", err)
+	}
+	if got := strings.Join(keys, ","); got != "agent,excluded_branches" {
+		t.Errorf("copied keys = %s, want agent,excluded_branches", got)
+	}
+
+	dstPath := filepath.Join(dst, ".roborev.toml")
+	assertConfigValue(t, dstPath, "agent", "codex")
+	assertConfigValue(t, dstPath, "review_guidelines", "keep me")
+	if branches, ok := readTOML(t, dstPath)["excluded_branches"].([]any); !ok || len(branches) != 1 || branches[0] != "wip" {
+		t.Errorf("excluded_branches = %v, want [wip]", readTOML(t, dstPath)["excluded_branches"])
+	}
+}
+
+func TestConfigCopyRequiresSourceConfig(t *testing.T) {
+	src := createFakeGitRepo(t)
+	dst := createFakeGitRepo(t)
+
+	if _, err := copyLocalConfig(src, dst); err == nil || !strings.Contains(err.Error(), "no local config") {
+		t.Fatalf("expected missing source config error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".roborev.toml")); !os.IsNotExist(err) {
+		t.Errorf("destination config should not be created, stat err = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func dbCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Database maintenance commands",
+	}
+	cmd.AddCommand(dbVacuumCmd())
+	return cmd
+}
+
+func dbVacuumCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "vacuum",
+		Short: "Compact the review database",
+		Long: `Runs VACUUM and PRAGMA optimize on the daemon's database to reclaim
+space left behind by deleted jobs and reviews. New jobs are not started
+while the vacuum runs; jobs already running are unaffected.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+
+			addr := getDaemonAddr()
+			// VACUUM rewrites the whole file, which can take a while on large databases
+			client := &http.Client{Timeout: 10 * time.Minute}
+			resp, err := client.Post(addr+"/api/db/vacuum", "application/json", nil)
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("vacuum failed: %s", msg)
+			}
+
+			var result storage.VacuumResult
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+
+			fmt.Printf("Vacuumed database: %s -> %s (reclaimed %s)\n",
+				formatByteSize(result.SizeBefore),
+				formatByteSize(result.SizeAfter),
+				formatByteSize(max(result.SizeBefore-result.SizeAfter, 0)))
+			return nil
+		},
+	}
+}
+
+// formatByteSize renders a byte count using binary units (KiB, MiB, ...).
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
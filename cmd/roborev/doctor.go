@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/agent"
+	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// doctorCheck is one line of the doctor report.
+type doctorCheck struct {
+	name   string
+	status doctorStatus
+	detail string
+}
+
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorFail
+	doctorSkip // Informational, e.g. an unconfigured agent that isn't installed
+)
+
+// doctorSection groups related checks under a heading.
+type doctorSection struct {
+	title  string
+	checks []doctorCheck
+}
+
+// doctorWorkflows are the workflows whose configured agents doctor checks.
+var doctorWorkflows = []string{"review", "refine", "fix", "security", "design"}
+
+func doctorCmd() *cobra.Command {
+	var timeoutSecs int
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check agents, the daemon, and the database",
+		Long: `Check that roborev is ready to run reviews.
+
+For every known agent, checks that its executable is on PATH and responds
+to --version. A missing agent is a failure only if it is configured as
+default_agent or a workflow agent (globally or in the current repo's
+.roborev.toml). Also checks that the daemon is reachable and that the
+database path is usable.
+
+Exits non-zero if any check fails. Use 'roborev check-agents' to run a
+full review smoke test against each agent.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			repoPath, _ := git.GetRepoRoot(".")
+
+			timeout := time.Duration(timeoutSecs) * time.Second
+			sections := []doctorSection{
+				{"Agents", checkDoctorAgents(timeout, configuredAgents(repoPath, cfg))},
+				{"Daemon", []doctorCheck{checkDoctorDaemon()}},
+				{"Database", []doctorCheck{checkDoctorDB(storage.DefaultDBPath())}},
+			}
+
+			out := cmd.OutOrStdout()
+			if failed := printDoctorReport(out, sections, colorEnabled(out)); failed > 0 {
+				return fmt.Errorf("%d check(s) failed", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.SilenceUsage = true
+	cmd.Flags().IntVar(&timeoutSecs, "timeout", 5, "timeout in seconds for each agent's version probe")
+
+	return cmd
+}
+
+// configuredAgents returns the canonical names of the agents set by
+// default_agent or a workflow agent in the global or repo config.
+func configuredAgents(repoPath string, cfg *config.Config) map[string]bool {
+	configured := make(map[string]bool)
+	for _, workflow := range doctorWorkflows {
+		if name := config.ConfiguredAgentForWorkflow(repoPath, cfg, workflow, ""); name != "" {
+			configured[agent.CanonicalName(name)] = true
+		}
+	}
+	return configured
+}
+
+// checkDoctorAgents checks every registered agent plus any configured name
+// that isn't registered, probing installed agents for their version.
+func checkDoctorAgents(timeout time.Duration, configured map[string]bool) []doctorCheck {
+	names := agent.Available()
+	for name := range configured {
+		if _, err := agent.Get(name); err != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var checks []doctorCheck
+	installed := 0
+	for _, name := range names {
+		if name == "test" {
+			continue
+		}
+		label := name
+		if configured[name] {
+			label += " (configured)"
+		}
+
+		if err := agent.CheckAgentAvailable(name); err != nil {
+			if configured[name] {
+				checks = append(checks, doctorCheck{label, doctorFail, err.Error()})
+			} else {
+				checks = append(checks, doctorCheck{label, doctorSkip, "not installed"})
+			}
+			continue
+		}
+		installed++
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		version, err := agent.ProbeAgentVersion(ctx, name)
+		cancel()
+		if err != nil {
+			checks = append(checks, doctorCheck{label, doctorFail, err.Error()})
+			continue
+		}
+		detail := version
+		if a, _ := agent.Get(name); a != nil {
+			if ca, ok := a.(agent.CommandAgent); ok {
+				if path, err := exec.LookPath(ca.CommandName()); err == nil {
+					detail += " (" + path + ")"
+				}
+			}
+		}
+		checks = append(checks, doctorCheck{label, doctorOK, detail})
+	}
+
+	if installed == 0 {
+		checks = append(checks, doctorCheck{"any agent", doctorFail, "no agents installed (install one of: codex, claude-code, gemini, copilot, opencode, cursor, droid)"})
+	}
+	return checks
+}
+
+// checkDoctorDaemon checks that a daemon is running and answers /api/status.
+func checkDoctorDaemon() doctorCheck {
+	info, err := daemon.GetAnyRunningDaemon()
+	if err != nil {
+		return doctorCheck{"daemon", doctorFail, "not running (start with: roborev daemon start)"}
+	}
+	addr := fmt.Sprintf("http://%s", info.Addr)
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(addr + "/api/status")
+	if err != nil {
+		return doctorCheck{"daemon", doctorFail, fmt.Sprintf("not reachable at %s: %v", addr, err)}
+	}
+	defer resp.Body.Close()
+
+	var status storage.DaemonStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return doctorCheck{"daemon", doctorFail, fmt.Sprintf("bad status response from %s: %v", addr, err)}
+	}
+	return doctorCheck{"daemon", doctorOK, fmt.Sprintf("running at %s [%s]", addr, status.Version)}
+}
+
+// checkDoctorDB checks that the database file at path is readable, or that
+// it can be created if it doesn't exist yet.
+func checkDoctorDB(path string) doctorCheck {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return doctorCheck{"database", doctorOK, path + " (not created yet; the daemon creates it on start)"}
+	}
+	if err != nil {
+		return doctorCheck{"database", doctorFail, err.Error()}
+	}
+	if info.IsDir() {
+		return doctorCheck{"database", doctorFail, path + " is a directory"}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return doctorCheck{"database", doctorFail, err.Error()}
+	}
+	f.Close()
+	return doctorCheck{"database", doctorOK, path}
+}
+
+// printDoctorReport writes each section's checks with a green OK or red
+// FAIL marker and returns the number of failed checks.
+func printDoctorReport(w io.Writer, sections []doctorSection, color bool) int {
+	mark := func(s doctorStatus) string {
+		text, code := "OK  ", "32"
+		switch s {
+		case doctorFail:
+			text, code = "FAIL", "31"
+		case doctorSkip:
+			text, code = "-   ", "2"
+		}
+		if !color {
+			return text
+		}
+		return "\x1b[" + code + "m" + text + "\x1b[0m"
+	}
+
+	passed, failed := 0, 0
+	for i, sec := range sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s:\n", sec.title)
+		for _, c := range sec.checks {
+			first, rest, _ := strings.Cut(c.detail, "\n")
+			fmt.Fprintf(w, "  %s  %-26s %s\n", mark(c.status), c.name, first)
+			for line := range strings.SplitSeq(rest, "\n") {
+				if line != "" {
+					fmt.Fprintf(w, "%35s%s\n", "", line)
+				}
+			}
+			switch c.status {
+			case doctorOK:
+				passed++
+			case doctorFail:
+				failed++
+			}
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", passed, failed)
+	return failed
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckDoctorAgents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub agents are shell scripts")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "claude"), []byte("#!/bin/sh\necho 'claude 9.9.9'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	checks := checkDoctorAgents(5*time.Second, map[string]bool{"codex": true, "bogus": true})
+	byName := make(map[string]doctorCheck)
+	for _, c := range checks {
+		byName[c.name] = c
+	}
+
+	if c := byName["claude-code"]; c.status != doctorOK || !strings.Contains(c.detail, "claude 9.9.9") {
+		t.Errorf("claude-code = %+v, want OK with version", c)
+	}
+	if c := byName["codex (configured)"]; c.status != doctorFail || !strings.Contains(c.detail, "not found in PATH") {
+		t.Errorf("codex = %+v, want FAIL for configured missing agent", c)
+	}
+	if c := byName["bogus (configured)"]; c.status != doctorFail || !strings.Contains(c.detail, "unknown agent") {
+		t.Errorf("bogus = %+v, want FAIL for unknown configured agent", c)
+	}
+	if c := byName["gemini"]; c.status != doctorSkip {
+		t.Errorf("gemini = %+v, want skipped when not installed or configured", c)
+	}
+	if _, ok := byName["test"]; ok {
+		t.Error("test agent should not be listed")
+	}
+}
+
+func TestCheckDoctorAgentsNoneInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	checks := checkDoctorAgents(time.Second, nil)
+	last := checks[len(checks)-1]
+	if last.status != doctorFail || !strings.Contains(last.detail, "no agents installed") {
+		t.Errorf("last check = %+v, want no-agents failure", last)
+	}
+}
+
+func TestCheckDoctorDB(t *testing.T) {
+	dir := t.TempDir()
+
+	if c := checkDoctorDB(filepath.Join(dir, "reviews.db")); c.status != doctorOK || !strings.Contains(c.detail, "not created yet") {
+		t.Errorf("missing db = %+v, want OK (not created yet)", c)
+	}
+
+	dbPath := filepath.Join(dir, "existing.db")
+	if err := os.WriteFile(dbPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c := checkDoctorDB(dbPath); c.status != doctorOK || c.detail != dbPath {
+		t.Errorf("existing db = %+v, want OK", c)
+	}
+
+	if c := checkDoctorDB(dir); c.status != doctorFail {
+		t.Errorf("directory = %+v, want FAIL", c)
+	}
+}
+
+func TestPrintDoctorReport(t *testing.T) {
+	sections := []doctorSection{
+		{"Agents", []doctorCheck{
+			{"codex", doctorOK, "codex 1.0"},
+			{"gemini", doctorSkip, "not installed"},
+			{"claude-code (configured)", doctorFail, "not found\nInstall it"},
+		}},
+		{"Daemon", []doctorCheck{{"daemon", doctorFail, "not running"}}},
+	}
+
+	var buf bytes.Buffer
+	failed := printDoctorReport(&buf, sections, false)
+	if failed != 2 {
+		t.Errorf("failed = %d, want 2", failed)
+	}
+	out := buf.String()
+	for _, want := range []string{"Agents:\n", "  OK    codex", "  -     gemini", "  FAIL  claude-code (configured)", "\n" + strings.Repeat(" ", 35) + "Install it\n", "Daemon:\n", "1 passed, 2 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Error("report should not contain escape codes without color")
+	}
+
+	buf.Reset()
+	printDoctorReport(&buf, sections, true)
+	if !strings.Contains(buf.String(), "\x1b[32mOK") || !strings.Contains(buf.String(), "\x1b[31mFAIL") {
+		t.Errorf("colored report missing green OK or red FAIL:\n%q", buf.String())
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/review"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+//go:embed export_bundle.html.tmpl
+var bundleTemplateText string
+
+var bundleTemplate = template.Must(template.New("bundle").Parse(bundleTemplateText))
+
+// maxBundleDiffSize caps each review's diff in an HTML bundle so a large
+// range doesn't bloat the report.
+const maxBundleDiffSize = 200 * 1024
+
+func exportCmd() *cobra.Command {
+	var (
+		bundle   bool
+		output   string
+		repoPath string
+		branch   string
+		since    string
+		noDiffs  bool
+		format   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [job_id|sha]",
+		Short: "Export completed reviews",
+		Long: `Export completed reviews for sharing outside roborev.
+
+With a job ID or commit SHA, exports that one review as Markdown (the
+review output under a metadata header, followed by any comments) or
+JSON (the full review with parsed findings and comments). It is written
+to stdout unless --output (or --out) is given.
+
+--bundle writes a single self-contained HTML report with verdicts,
+summaries, findings, and highlighted diffs. It has no external assets,
+so it can be attached to an email or opened offline.
+
+Diffs for commit and range reviews are read from the repository at
+export time; reviews whose commits are gone are exported without one.
+
+Examples:
+  roborev export 42 --format md --out review-42.md
+  roborev export abc1234 --format json
+  roborev export --bundle
+  roborev export --bundle --repo . --since 7d -o weekly.html
+  roborev export --bundle --branch feature-x -o - > report.html
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if bundle {
+					return fmt.Errorf("--bundle exports many reviews and takes no job ID or SHA")
+				}
+				out := ""
+				if cmd.Flags().Changed("output") {
+					out = output
+				}
+				return exportSingleReview(cmd, args[0], format, out)
+			}
+			if !bundle {
+				return fmt.Errorf("specify a job ID or SHA to export, or --bundle")
+			}
+
+			var opts storage.ExportOptions
+			var filters []string
+			if repoPath != "" {
+				root, err := git.GetMainRepoRoot(repoPath)
+				if err != nil {
+					return fmt.Errorf("not a git repository: %s", repoPath)
+				}
+				opts.RepoPath = root
+				filters = append(filters, "repo "+root)
+			}
+			if branch != "" {
+				opts.Branch = branch
+				filters = append(filters, "branch "+branch)
+			}
+			if since != "" {
+				t, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				opts.Since = t
+				filters = append(filters, "since "+t.Format("2006-01-02 15:04"))
+			}
+
+			dbPath := storage.DefaultDBPath()
+			if dbPath == "" {
+				return fmt.Errorf("cannot determine database path")
+			}
+			db, err := storage.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			reviews, err := db.ExportReviews(opts)
+			if err != nil {
+				return fmt.Errorf("export reviews: %w", err)
+			}
+
+			diffFn := exportDiff
+			if noDiffs {
+				diffFn = nil
+			}
+			data := buildBundle(reviews, diffFn, time.Now(), strings.Join(filters, ", "))
+
+			if output == "-" {
+				return writeBundle(cmd.OutOrStdout(), data)
+			}
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", output, err)
+			}
+			if err := writeBundle(f, data); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("write %s: %w", output, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d reviews to %s\n", len(reviews), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&bundle, "bundle", false, "write a self-contained HTML report")
+	cmd.Flags().StringVarP(&output, "output", "o", "roborev-report.html", "output file (- for stdout)")
+	cmd.Flags().StringVar(&repoPath, "repo", "", "only export reviews for this repo path")
+	cmd.Flags().StringVar(&branch, "branch", "", "only export reviews on this branch")
+	cmd.Flags().StringVar(&since, "since", "", "only export reviews finished within this duration (e.g. 24h, 7d) or since a date")
+	cmd.Flags().BoolVar(&noDiffs, "no-diffs", false, "leave diffs out of the report")
+	cmd.Flags().StringVar(&format, "format", "md", "format for a single review: md or json")
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "out" {
+			name = "output"
+		}
+		return pflag.NormalizedName(name)
+	})
+
+	return cmd
+}
+
+// exportSingleReview writes the review for a job ID or commit SHA in the
+// given format to out, or to stdout when out is empty or "-".
+func exportSingleReview(cmd *cobra.Command, arg, format, out string) error {
+	if format != "md" && format != "json" {
+		return fmt.Errorf("invalid --format %q (want md or json)", format)
+	}
+
+	dbPath := storage.DefaultDBPath()
+	if dbPath == "" {
+		return fmt.Errorf("cannot determine database path")
+	}
+	db, err := storage.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	rv, err := lookupReview(db, arg)
+	if err != nil {
+		return err
+	}
+
+	comments, err := db.GetCommentsForJob(rv.JobID)
+	if err != nil {
+		return fmt.Errorf("load comments: %w", err)
+	}
+
+	var data []byte
+	if format == "json" {
+		if data, err = review.ExportReviewJSON(*rv, comments); err != nil {
+			return err
+		}
+	} else {
+		data = []byte(review.ExportReview(*rv, comments))
+	}
+
+	if out == "" || out == "-" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", out, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported review for job %d to %s\n", rv.JobID, out)
+	return nil
+}
+
+// lookupReview finds the review for arg the way "roborev show" does: a
+// ref that resolves in the current repo is a commit, otherwise a number
+// is a job ID.
+func lookupReview(db *storage.DB, arg string) (*storage.Review, error) {
+	sha := ""
+	if root, err := git.GetRepoRoot("."); err == nil {
+		if resolved, err := git.ResolveSHA(root, arg); err == nil {
+			sha = resolved
+		}
+	}
+	if sha == "" {
+		if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+			review, err := db.GetReviewByJobID(id)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("no review found for job %d", id)
+			}
+			return review, err
+		}
+		sha = arg
+	}
+	review, err := db.GetReviewByCommitSHA(sha)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no review found for %s", git.ShortSHA(sha))
+	}
+	if errors.Is(err, storage.ErrAmbiguousSHA) {
+		return nil, fmt.Errorf("%w (use more characters of the SHA)", err)
+	}
+	return review, err
+}
+
+// bundleData is the root value for the HTML bundle template.
+type bundleData struct {
+	Title     string
+	Generated string
+	Filters   string
+	Passed    int
+	Failed    int
+	Reviews   []bundleReview
+}
+
+type bundleReview struct {
+	storage.ExportedReview
+	Ref       string
+	Finished  string
+	Passed    bool
+	Findings  []storage.Finding
+	DiffLines []diffLine
+	DiffNote  string
+}
+
+// diffLine is one diff line with the CSS class used to highlight it.
+type diffLine struct {
+	Class string
+	Text  string
+}
+
+// buildBundle prepares reviews for the bundle template. diffFn loads each
+// review's diff; nil leaves diffs out.
+func buildBundle(reviews []storage.ExportedReview, diffFn func(storage.ExportedReview) (string, error), now time.Time, filters string) bundleData {
+	data := bundleData{
+		Title:     "roborev review report",
+		Generated: now.Format("2006-01-02 15:04 MST"),
+		Filters:   filters,
+		Reviews:   make([]bundleReview, 0, len(reviews)),
+	}
+	for _, r := range reviews {
+		br := bundleReview{
+			ExportedReview: r,
+			Ref:            shortRef(r.GitRef),
+			Passed:         r.Verdict == "P",
+			Findings:       storage.ExtractFindings(r.Output),
+		}
+		if !r.FinishedAt.IsZero() {
+			br.Finished = r.FinishedAt.Local().Format("2006-01-02 15:04")
+		}
+		if br.Passed {
+			data.Passed++
+		} else {
+			data.Failed++
+		}
+		if diffFn != nil {
+			diff, err := diffFn(r)
+			switch {
+			case err != nil:
+				br.DiffNote = "Diff unavailable: " + err.Error()
+			case len(diff) > maxBundleDiffSize:
+				br.DiffLines = classifyDiff(diff[:maxBundleDiffSize])
+				br.DiffNote = fmt.Sprintf("Diff truncated to %d KB.", maxBundleDiffSize/1024)
+			default:
+				br.DiffLines = classifyDiff(diff)
+			}
+		}
+		data.Reviews = append(data.Reviews, br)
+	}
+	return data
+}
+
+func writeBundle(w io.Writer, data bundleData) error {
+	if err := bundleTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("render bundle: %w", err)
+	}
+	return nil
+}
+
+// exportDiff returns the diff a review covered: the stored diff for dirty
+// reviews, or one read from the repo for commit and range reviews. Task
+// jobs have no diff.
+func exportDiff(r storage.ExportedReview) (string, error) {
+	switch {
+	case r.DiffContent != "":
+		return r.DiffContent, nil
+	case r.JobType == storage.JobTypeRange:
+		return git.GetRangeDiff(r.RepoPath, r.GitRef)
+	case r.JobType == storage.JobTypeReview:
+		return git.GetDiff(r.RepoPath, r.GitRef)
+	}
+	return "", nil
+}
+
+// classifyDiff splits a unified diff into lines tagged for highlighting.
+func classifyDiff(diff string) []diffLine {
+	diff = strings.TrimRight(diff, "\n")
+	if diff == "" {
+		return nil
+	}
+	raw := strings.Split(diff, "\n")
+	lines := make([]diffLine, 0, len(raw))
+	for _, text := range raw {
+		var class string
+		switch {
+		case strings.HasPrefix(text, "diff --git"), strings.HasPrefix(text, "commit "),
+			strings.HasPrefix(text, "+++ "), strings.HasPrefix(text, "--- "):
+			class = "file"
+		case strings.HasPrefix(text, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(text, "+"):
+			class = "add"
+		case strings.HasPrefix(text, "-"):
+			class = "del"
+		}
+		lines = append(lines, diffLine{Class: class, Text: text})
+	}
+	return lines
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestWriteBundle(t *testing.T) {
+	finished := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	reviews := []storage.ExportedReview{
+		{
+			JobID: 41, RepoName: "api", GitRef: "abc1234def", Agent: "codex", JobType: storage.JobTypeReview,
+			FinishedAt: finished, Verdict: "P", Output: "No issues found.",
+		},
+		{
+			JobID: 42, RepoName: "api", GitRef: "dirty", Agent: "claude-code", JobType: storage.JobTypeDirty,
+			FinishedAt: finished, Verdict: "F", Summary: "Unsafe query",
+			Output:      "- High: query uses <user> input unescaped",
+			DiffContent: "diff --git a/q.go b/q.go\n@@ -1 +1 @@\n-old\n+new\n",
+		},
+		{
+			JobID: 43, RepoName: "web", GitRef: "fff0000", Agent: "codex", JobType: storage.JobTypeReview,
+			FinishedAt: finished, Verdict: "P", Output: "No issues found.",
+		},
+	}
+	diffFn := func(r storage.ExportedReview) (string, error) {
+		if r.JobID == 43 {
+			return "", errors.New("commit not found")
+		}
+		return exportDiff(storage.ExportedReview{DiffContent: r.DiffContent})
+	}
+
+	data := buildBundle(reviews, diffFn, finished, "repo /src/api")
+	if data.Passed != 2 || data.Failed != 1 {
+		t.Errorf("totals = %d pass / %d fail, want 2 / 1", data.Passed, data.Failed)
+	}
+
+	var buf bytes.Buffer
+	if err := writeBundle(&buf, data); err != nil {
+		t.Fatalf("writeBundle: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		`id="job-41"`, `id="job-42"`, `id="job-43"`,
+		`<a href="#job-42">#42</a>`,
+		`<span class="badge pass">PASS</span>`,
+		`<span class="badge fail">FAIL</span>`,
+		`<span class="sev high">high</span>`,
+		`<span class="add">&#43;new</span>`,
+		`<span class="del">-old</span>`,
+		`<span class="hunk">@@ -1 &#43;1 @@</span>`,
+		`&lt;user&gt;`,
+		"Diff unavailable: commit not found",
+		"repo /src/api",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("bundle missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<user>") {
+		t.Error("review output was not escaped")
+	}
+	for _, external := range []string{"<link", "<script", "src="} {
+		if strings.Contains(html, external) {
+			t.Errorf("bundle should be self-contained, found %q", external)
+		}
+	}
+}
+
+func TestClassifyDiff(t *testing.T) {
+	diff := "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n ctx\n-gone\n+added\n"
+	want := []string{"file", "file", "file", "hunk", "", "del", "add"}
+	got := classifyDiff(diff)
+	if len(got) != len(want) {
+		t.Fatalf("classifyDiff returned %d lines, want %d", len(got), len(want))
+	}
+	for i, class := range want {
+		if got[i].Class != class {
+			t.Errorf("line %d (%q) class = %q, want %q", i, got[i].Text, got[i].Class, class)
+		}
+	}
+}
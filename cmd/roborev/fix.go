@@ -14,6 +14,7 @@ import (
 
 	"github.com/roborev-dev/roborev/internal/agent"
 	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/daemon"
 	"github.com/roborev-dev/roborev/internal/git"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/spf13/cobra"
@@ -455,9 +456,9 @@ func queryUnaddressedJobs(
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		msg := readErrorBody(resp.Body, resp.Status)
 		return nil, fmt.Errorf(
-			"server error (%d): %s", resp.StatusCode, body,
+			"server error (%d): %s", resp.StatusCode, msg,
 		)
 	}
 
@@ -969,8 +970,8 @@ func fetchJob(ctx context.Context, serverAddr string, jobID int64) (*storage.Rev
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, msg)
 	}
 
 	var jobsResp struct {
@@ -1003,8 +1004,8 @@ func fetchReview(ctx context.Context, serverAddr string, jobID int64) (*storage.
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, msg)
 	}
 
 	var review storage.Review
@@ -1063,8 +1064,8 @@ func addJobResponse(serverAddr string, jobID int64, commenter, response string)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("add response failed: %s", body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("add response failed: %s", msg)
 	}
 	return nil
 }
@@ -1096,7 +1097,7 @@ func enqueueIfNeeded(serverAddr, repoPath, sha string) error {
 		"branch":    branchName,
 	})
 
-	resp, err := http.Post(serverAddr+"/api/enqueue", "application/json", bytes.NewReader(reqBody))
+	resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/enqueue", reqBody)
 	if err != nil {
 		return err
 	}
@@ -1104,8 +1105,8 @@ func enqueueIfNeeded(serverAddr, repoPath, sha string) error {
 
 	// 200 (skipped) and 201 (enqueued) are both fine
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("enqueue failed: %s", body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("enqueue failed: %s", msg)
 	}
 	return nil
 }
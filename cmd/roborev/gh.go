@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+func ghCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gh",
+		Short: "Post reviews to GitHub",
+		Long: `Post reviews to GitHub pull requests.
+
+Requires a token in config.toml:
+
+  [github]
+  token = "ghp_..."
+
+Reviews go to the repo's GitHub remote, or to github_repo = "owner/name"
+in its .roborev.toml. With a token set, the daemon also posts a completed
+review to the pull request whose head is the reviewed commit, once per
+head commit.`,
+	}
+	cmd.AddCommand(ghPostCmd())
+	return cmd
+}
+
+func ghPostCmd() *cobra.Command {
+	var repoFlag string
+
+	cmd := &cobra.Command{
+		Use:   "post <job_id>",
+		Short: "Post a review as a comment on its GitHub pull request",
+		Long: `Post a review as a comment on the open GitHub pull request whose head
+is the reviewed commit (the last commit, for a range review).
+
+Examples:
+  roborev gh post 42
+  roborev gh post 42 --repo owner/name`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || jobID <= 0 {
+				return fmt.Errorf("invalid job ID: %s", args[0])
+			}
+
+			cfg, err := config.LoadGlobal()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if cfg.GitHub.Token == "" {
+				return fmt.Errorf("github.token is not set (roborev config set github.token <token> --global)")
+			}
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			review, err := fetchReview(ctx, getDaemonAddr(), jobID)
+			if err != nil {
+				return fmt.Errorf("fetch review: %w", err)
+			}
+			if review.Job == nil || review.Job.IsDirtyJob() || review.Job.UsesStoredPrompt() {
+				return fmt.Errorf("job %d did not review a commit", jobID)
+			}
+
+			repo := repoFlag
+			if repo == "" {
+				repo = daemon.ResolveGitHubRepo(review.Job.RepoPath)
+			}
+			if repo == "" {
+				return fmt.Errorf("no GitHub repository for %s (set github_repo in .roborev.toml or pass --repo)", review.Job.RepoPath)
+			}
+			sha := daemon.HeadSHA(review.Job.GitRef)
+			number, err := daemon.NewGitHubPRCommenter(cfg.GitHub.Token).PostReview(ctx, repo, sha, daemon.GitHubReviewComment(review))
+			if errors.Is(err, daemon.ErrNoPullRequest) {
+				return fmt.Errorf("no open pull request on %s has %s as its head", repo, shortRef(sha))
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Posted review of job %d to %s#%d\n", jobID, repo, number)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repoFlag, "repo", "", "GitHub repository (owner/name) to post to")
+
+	return cmd
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -16,6 +17,23 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// fakeAgentCommands puts stub executables for the named commands first on
+// PATH so agent availability checks pass without the real agents installed.
+func fakeAgentCommands(t *testing.T, commands ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range commands {
+		path, script := filepath.Join(dir, name), "#!/bin/sh\necho stub\n"
+		if runtime.GOOS == "windows" {
+			path, script = path+".bat", "@echo stub\r\n"
+		}
+		if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
 // TestGitRepo wraps a temporary git repository for test use.
 type TestGitRepo struct {
 	Dir string
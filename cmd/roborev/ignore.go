@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func ignoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ignore",
+		Short: "Manage rules that suppress known-acceptable findings",
+		Long: `Manage ignore rules. Findings matching a rule don't count toward a
+FAIL verdict and are marked [suppressed] in the stored review.
+
+Rules live under [[ignore_rules]] in .roborev.toml (or the global config
+with --global). Global and per-repo rules both apply.`,
+	}
+	cmd.AddCommand(ignoreAddCmd())
+	cmd.AddCommand(ignoreListCmd())
+	return cmd
+}
+
+func ignoreAddCmd() *cobra.Command {
+	var (
+		pathGlob string
+		global   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add [pattern]",
+		Short: "Add an ignore rule",
+		Long: `Add a rule suppressing findings whose text contains pattern
+(case-insensitive). With --path, the finding must also mention a file
+matching the glob (full path or basename) or under the directory.
+
+Examples:
+  roborev ignore add "missing error check on Close"
+  roborev ignore add "sql injection" --path "internal/testdata/*"
+  roborev ignore add --path vendor/
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var rule config.IgnoreRule
+			if len(args) == 1 {
+				rule.Pattern = strings.TrimSpace(args[0])
+			}
+			rule.Path = strings.TrimSpace(pathGlob)
+			if rule.Pattern == "" && rule.Path == "" {
+				return fmt.Errorf("specify a pattern, --path, or both")
+			}
+			if rule.Path != "" {
+				if _, err := filepath.Match(rule.Path, ""); err != nil {
+					return fmt.Errorf("invalid --path glob %q: %w", rule.Path, err)
+				}
+			}
+
+			path, err := ignoreConfigPath(global)
+			if err != nil {
+				return err
+			}
+			added, err := addIgnoreRule(path, rule, global)
+			if err != nil {
+				return err
+			}
+			if !added {
+				fmt.Fprintf(cmd.OutOrStdout(), "Rule already present in %s\n", path)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Added ignore rule to %s: %s\n", path, formatIgnoreRule(rule))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pathGlob, "path", "", "only suppress findings mentioning a file matching this glob or directory")
+	cmd.Flags().BoolVar(&global, "global", false, "add to the global config instead of .roborev.toml")
+
+	return cmd
+}
+
+func ignoreListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List ignore rules that apply to the current repo",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			globalCfg, err := config.LoadGlobal()
+			if err != nil {
+				return fmt.Errorf("load global config: %w", err)
+			}
+			printIgnoreRules(out, "global", globalCfg.IgnoreRules)
+
+			root, err := repoRoot()
+			if err != nil {
+				return err
+			}
+			if root == "" {
+				return nil
+			}
+			repoCfg, err := config.LoadRepoConfig(root)
+			if err != nil {
+				return fmt.Errorf("load repo config: %w", err)
+			}
+			if repoCfg != nil {
+				printIgnoreRules(out, "local", repoCfg.IgnoreRules)
+			}
+			return nil
+		},
+	}
+}
+
+// ignoreConfigPath returns the config file ignore rules are written to.
+func ignoreConfigPath(global bool) (string, error) {
+	if global {
+		return config.GlobalConfigPath(), nil
+	}
+	root, err := requireRepoRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, ".roborev.toml"), nil
+}
+
+// addIgnoreRule appends rule to the [[ignore_rules]] array in the TOML file
+// at path, preserving other keys. Returns false if an identical rule exists.
+func addIgnoreRule(path string, rule config.IgnoreRule, isGlobal bool) (bool, error) {
+	raw, err := loadRawConfig(path)
+	if err != nil {
+		return false, err
+	}
+
+	var rules []map[string]any
+	switch existing := raw["ignore_rules"].(type) {
+	case nil:
+	case []map[string]any:
+		rules = existing
+	default:
+		return false, fmt.Errorf("%s: ignore_rules must be an array of tables", path)
+	}
+	for _, r := range rules {
+		pattern, _ := r["pattern"].(string)
+		glob, _ := r["path"].(string)
+		if strings.EqualFold(pattern, rule.Pattern) && glob == rule.Path {
+			return false, nil
+		}
+	}
+
+	entry := map[string]any{}
+	if rule.Pattern != "" {
+		entry["pattern"] = rule.Pattern
+	}
+	if rule.Path != "" {
+		entry["path"] = rule.Path
+	}
+	raw["ignore_rules"] = append(rules, entry)
+
+	return true, atomicWriteConfig(path, raw, isGlobal)
+}
+
+func printIgnoreRules(out io.Writer, scope string, rules []config.IgnoreRule) {
+	for _, r := range rules {
+		fmt.Fprintf(out, "%s\t%s\n", scope, formatIgnoreRule(r))
+	}
+}
+
+func formatIgnoreRule(r config.IgnoreRule) string {
+	var parts []string
+	if r.Pattern != "" {
+		parts = append(parts, fmt.Sprintf("pattern=%q", r.Pattern))
+	}
+	if r.Path != "" {
+		parts = append(parts, fmt.Sprintf("path=%q", r.Path))
+	}
+	return strings.Join(parts, " ")
+}
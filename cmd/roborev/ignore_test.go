@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/roborev-dev/roborev/internal/config"
+)
+
+func TestAddIgnoreRule(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".roborev.toml")
+	if err := os.WriteFile(path, []byte("agent = \"codex\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []config.IgnoreRule{
+		{Pattern: "false positive"},
+		{Pattern: "sql injection", Path: "testdata/"},
+	}
+	for _, r := range rules {
+		added, err := addIgnoreRule(path, r, false)
+		if err != nil {
+			t.Fatalf("addIgnoreRule: %v", err)
+		}
+		if !added {
+			t.Errorf("expected rule %+v to be added", r)
+		}
+	}
+
+	added, err := addIgnoreRule(path, config.IgnoreRule{Pattern: "False Positive"}, false)
+	if err != nil {
+		t.Fatalf("addIgnoreRule: %v", err)
+	}
+	if added {
+		t.Error("duplicate rule should not be added")
+	}
+
+	cfg, err := config.LoadRepoConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadRepoConfig: %v", err)
+	}
+	if cfg.Agent != "codex" {
+		t.Errorf("existing key lost, agent = %q", cfg.Agent)
+	}
+	if !slices.Equal(cfg.IgnoreRules, rules) {
+		t.Errorf("IgnoreRules = %+v, want %+v", cfg.IgnoreRules, rules)
+	}
+}
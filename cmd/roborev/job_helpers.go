@@ -127,8 +127,8 @@ func waitForJobCompletion(ctx context.Context, serverAddr string, jobID int64, o
 			defer reviewResp.Body.Close()
 
 			if reviewResp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(reviewResp.Body)
-				return nil, fmt.Errorf("fetch review (%d): %s", reviewResp.StatusCode, body)
+				msg := readErrorBody(reviewResp.Body, reviewResp.Status)
+				return nil, fmt.Errorf("fetch review (%d): %s", reviewResp.StatusCode, msg)
 			}
 
 			var review storage.Review
@@ -156,3 +156,36 @@ func waitForJobCompletion(ctx context.Context, serverAddr string, jobID int64, o
 		}
 	}
 }
+
+// fetchJobPatch retrieves the stored patch for a job from the daemon,
+// byte for byte as git apply expects it. Shared by apply-suggestion,
+// patch, and the TUI patch view.
+func fetchJobPatch(ctx context.Context, serverAddr string, jobID int64) (string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/job/patch?job_id=%d", serverAddr, jobID), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("job %d has no patch", jobID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("server error (%d): %s", resp.StatusCode, readErrorBody(resp.Body, resp.Status))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if len(body) == 0 {
+		return "", fmt.Errorf("job %d has no patch", jobID)
+	}
+	return string(body), nil
+}
@@ -91,6 +91,43 @@ func TestListCommand(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:       "verdict column distinguishes pass, fail, and none",
+			args:       []string{},
+			handler:    jobsHandler(withVerdicts(testJobs, "F", ""), false),
+			wantOutput: []string{"Verdict", "FAIL", "-"},
+		},
+		{
+			name:      "verdict filter passes through",
+			args:      []string{"--verdict", "fail"},
+			handler:   jobsHandler([]storage.ReviewJob{}, false),
+			wantQuery: []string{"verdict=fail"},
+		},
+		{
+			name:      "invalid verdict is rejected",
+			args:      []string{"--verdict", "maybe"},
+			handler:   jobsHandler([]storage.ReviewJob{}, false),
+			wantError: "invalid --verdict",
+		},
+		{
+			name:      "machine filter passes through",
+			args:      []string{"--machine", "machine-abc"},
+			handler:   jobsHandler([]storage.ReviewJob{}, false),
+			wantQuery: []string{"machine=machine-abc"},
+		},
+		{
+			name:         "local-only passes through",
+			args:         []string{"--local-only"},
+			handler:      jobsHandler([]storage.ReviewJob{}, false),
+			wantQuery:    []string{"local_only=true"},
+			notWantQuery: []string{"machine="},
+		},
+		{
+			name:      "machine with local-only is rejected",
+			args:      []string{"--machine", "machine-abc", "--local-only"},
+			handler:   jobsHandler([]storage.ReviewJob{}, false),
+			wantError: "cannot use --machine with --local-only",
+		},
 		{
 			name:       "has_more shows hint in tabular mode",
 			args:       []string{},
@@ -266,3 +303,15 @@ func TestListCommand(t *testing.T) {
 		})
 	}
 }
+
+// withVerdicts returns a copy of jobs with the given verdicts, "" for none.
+func withVerdicts(jobs []storage.ReviewJob, verdicts ...string) []storage.ReviewJob {
+	out := make([]storage.ReviewJob, len(jobs))
+	copy(out, jobs)
+	for i, v := range verdicts {
+		if v != "" {
+			out[i].Verdict = &v
+		}
+	}
+	return out
+}
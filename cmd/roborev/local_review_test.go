@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,16 +10,19 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/roborev-dev/roborev/internal/agent"
 	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/roborev-dev/roborev/internal/testutil"
 )
 
 // reviewHarness encapsulates a test git repo, cobra command, and output buffer.
 type reviewHarness struct {
-	t   *testing.T
-	Dir string
-	Cmd *cobra.Command
-	Out *bytes.Buffer
+	t    *testing.T
+	Dir  string
+	Repo *testutil.TestRepo
+	Cmd  *cobra.Command
+	Out  *bytes.Buffer
 }
 
 // newReviewHarness creates a harness with the real CLI command structure.
@@ -29,7 +33,7 @@ func newReviewHarness(t *testing.T) *reviewHarness {
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetErr(&out)
-	return &reviewHarness{t: t, Dir: repo.Root, Cmd: cmd, Out: &out}
+	return &reviewHarness{t: t, Dir: repo.Root, Repo: repo, Cmd: cmd, Out: &out}
 }
 
 // writeConfig writes a .roborev.toml in the repo directory.
@@ -163,6 +167,46 @@ func TestLocalReviewWithDirtyDiff(t *testing.T) {
 	h.assertOutputContains("Commit: dirty")
 }
 
+func TestLocalReviewAuthorFilter(t *testing.T) {
+	// newMixedHarness returns a harness whose HEAD~2..HEAD range holds one
+	// commit by Alice followed by one by Bob.
+	newMixedHarness := func(t *testing.T) *reviewHarness {
+		h := newReviewHarness(t)
+		for _, c := range []struct{ author, file string }{
+			{"Alice <alice@example.com>", "alice.go"},
+			{"Bob <bob@example.com>", "bob.go"},
+		} {
+			if err := os.WriteFile(filepath.Join(h.Dir, c.file), []byte("package main\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			h.Repo.RunGit("add", c.file)
+			h.Repo.RunGit("commit", "--author", c.author, "-m", "add "+c.file)
+		}
+		return h
+	}
+
+	t.Run("reviews matching commits", func(t *testing.T) {
+		h := newMixedHarness(t)
+		err := h.runCmd("HEAD~2..HEAD", "--author", "alice@example.com", "--agent", "test", "--reasoning", "fast")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		h.assertOutputContains("Reviewing 1 commits by alice@example.com")
+	})
+
+	t.Run("no matching commits", func(t *testing.T) {
+		h := newMixedHarness(t)
+		err := h.runCmd("HEAD~2..HEAD", "--author", "carol@example.com", "--agent", "test")
+		h.assertErrorContains(err, `no commits by "carol@example.com"`)
+	})
+
+	t.Run("requires range", func(t *testing.T) {
+		h := newMixedHarness(t)
+		err := h.runCmd("HEAD", "--author", "alice@example.com", "--agent", "test")
+		h.assertErrorContains(err, "--author requires a commit range")
+	})
+}
+
 func TestLocalReviewAgentResolution(t *testing.T) {
 	h := newReviewHarness(t)
 	h.writeConfig(`agent = "test"`)
@@ -294,6 +338,108 @@ func TestLocalReviewQuietMode(t *testing.T) {
 	}
 
 	h.assertOutputNotContains("Running")
+	h.assertOutputNotContains("Verdict")
+}
+
+func TestLocalReviewStoresReview(t *testing.T) {
+	t.Setenv("ROBOREV_DATA_DIR", t.TempDir())
+	h := newReviewHarness(t)
+
+	if err := h.run(runOpts{Agent: "test", Reasoning: "fast", Quiet: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	db, err := storage.Open(storage.DefaultDBPath())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	jobs, err := db.ListJobs("", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 stored job, got %d", len(jobs))
+	}
+	job := jobs[0]
+	head := h.Repo.RevParse("HEAD")
+	if job.Status != storage.JobStatusDone {
+		t.Errorf("status = %q, want done", job.Status)
+	}
+	if job.JobType != storage.JobTypeReview || job.GitRef != head {
+		t.Errorf("job = %s %s, want review of HEAD %s", job.JobType, job.GitRef, head)
+	}
+	review, err := db.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID: %v", err)
+	}
+	if review.Agent != "test" || review.Output == "" {
+		t.Errorf("review = agent %q output %q, want the test agent's output", review.Agent, review.Output)
+	}
+}
+
+func TestLocalReviewStoresStdinDiffAsDirty(t *testing.T) {
+	t.Setenv("ROBOREV_DATA_DIR", t.TempDir())
+	h := newReviewHarness(t)
+	edited := "diff --git a/file.txt b/file.txt\n+edited by hand\n"
+	h.Cmd.SetIn(strings.NewReader(edited))
+
+	if err := h.runCmd("--agent", "test", "--quiet", "--stdin", "--base", "HEAD"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	db, err := storage.Open(storage.DefaultDBPath())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	jobs, err := db.ListJobs("", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 stored job, got %d", len(jobs))
+	}
+	job := jobs[0]
+	if job.JobType != storage.JobTypeDirty || job.GitRef != "dirty" || job.CommitID == nil {
+		t.Errorf("job = %s %s commit %v, want a dirty job linked to HEAD", job.JobType, job.GitRef, job.CommitID)
+	}
+	if _, err := db.GetReviewByCommitSHA(h.Repo.RevParse("HEAD")); err == nil {
+		t.Error("stdin review should not be found as a review of HEAD")
+	}
+}
+
+func TestLocalReviewStreamsAndParsesVerdict(t *testing.T) {
+	a := agent.NewTestAgent()
+	a.Output = "- High: missing nil check in handler.go"
+	agent.Register(a)
+	t.Cleanup(func() { agent.Register(agent.NewTestAgent()) })
+
+	t.Run("command output", func(t *testing.T) {
+		h := newReviewHarness(t)
+		if err := h.run(runOpts{Agent: "test"}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		h.assertOutputContains("missing nil check in handler.go")
+		h.assertOutputContains("Verdict: FAIL")
+	})
+
+	t.Run("buffered output", func(t *testing.T) {
+		h := newReviewHarness(t)
+		var stream bytes.Buffer
+		output, err := runLocalAgent(context.Background(), a, h.Dir, "HEAD", "prompt", &stream)
+		if err != nil {
+			t.Fatalf("runLocalAgent: %v", err)
+		}
+		if !strings.Contains(stream.String(), a.Output) {
+			t.Errorf("expected output to be streamed, got %q", stream.String())
+		}
+		if got := storage.ParseVerdict(output); got != "F" {
+			t.Errorf("ParseVerdict(buffered) = %q, want F", got)
+		}
+	})
 }
 
 func TestLocalReviewSkipsDaemon(t *testing.T) {
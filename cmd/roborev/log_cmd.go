@@ -20,6 +20,7 @@ func logCmd() *cobra.Command {
 	var (
 		showPath  bool
 		rawOutput bool
+		full      bool
 	)
 
 	cmd := &cobra.Command{
@@ -33,9 +34,14 @@ printed as-is.
 
 Use --raw to print the original log bytes unchanged.
 
+Logs larger than 1 MiB are cut to their last 1 MiB, where failures
+show up; use --full to print the whole log. A running job's log
+shows whatever the agent has written so far.
+
 Examples:
   roborev log 42          # Human-friendly rendered output
   roborev log --raw 42    # Raw log bytes (JSONL)
+  roborev log --full 42   # Don't truncate large logs
   roborev log --path 42   # Print the log file path`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -60,8 +66,21 @@ Examples:
 			}
 			defer f.Close()
 
+			var r io.Reader = f
+			if !full {
+				var skipped int64
+				if r, skipped, err = tailJobLog(f, maxLogBytes); err != nil {
+					return fmt.Errorf("reading log: %w", err)
+				}
+				if skipped > 0 {
+					fmt.Fprintf(cmd.ErrOrStderr(),
+						"(log truncated: skipped the first %d bytes; use --full to show everything)\n",
+						skipped)
+				}
+			}
+
 			if rawOutput {
-				_, err := io.Copy(out, f)
+				_, err := io.Copy(out, r)
 				if isBrokenPipe(err) {
 					return nil
 				}
@@ -72,7 +91,7 @@ Examples:
 			}
 
 			err = renderJobLog(
-				f, out, writerIsTerminal(out),
+				r, out, writerIsTerminal(out),
 			)
 			if isBrokenPipe(err) {
 				return nil
@@ -89,11 +108,42 @@ Examples:
 		&rawOutput, "raw", false,
 		"print raw log bytes without formatting",
 	)
+	cmd.Flags().BoolVar(
+		&full, "full", false,
+		"print the whole log even when it is very large",
+	)
 
 	cmd.AddCommand(logCleanCmd())
 	return cmd
 }
 
+// maxLogBytes caps how much of a job log `roborev log` prints without --full.
+const maxLogBytes = 1 << 20
+
+// tailJobLog returns a reader over the last limit bytes of f, starting at
+// a line boundary so JSONL events aren't cut in half, and the number of
+// bytes skipped.
+func tailJobLog(f *os.File, limit int64) (io.Reader, int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	if fi.Size() <= limit {
+		return f, 0, nil
+	}
+	start := fi.Size() - limit
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	br := bufio.NewReader(f)
+	// Drop the partial line at the cut
+	partial, err := br.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, 0, err
+	}
+	return br, start + int64(len(partial)), nil
+}
+
 // renderJobLog reads a job log file and writes human-friendly
 // output. JSONL lines are processed through streamFormatter for
 // compact tool/text rendering. Non-JSON lines are printed as-is.
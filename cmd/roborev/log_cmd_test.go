@@ -255,6 +255,51 @@ func TestLogCmd_RawFlag(t *testing.T) {
 	}
 }
 
+func TestLogCmd_TruncatesLargeLog(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("ROBOREV_DATA_DIR", dir)
+
+	logDir := filepath.Join(dir, "logs", "jobs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	line := strings.Repeat("x", 99) + "\n"
+	content := "first line\n" + strings.Repeat(line, maxLogBytes/len(line)+10) + "last line\n"
+	if err := os.WriteFile(filepath.Join(logDir, "42.log"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, string) {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		cmd := logCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		cmd.SetArgs(args)
+		cmd.SilenceUsage = true
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("log %v: %v", args, err)
+		}
+		return out.String(), errOut.String()
+	}
+
+	out, note := run("--raw", "42")
+	if strings.Contains(out, "first line") || !strings.HasSuffix(out, "last line\n") {
+		t.Error("expected only the tail of a large log")
+	}
+	if len(out) > maxLogBytes || !strings.HasPrefix(out, line) {
+		t.Errorf("truncated output should be at most %d bytes of whole lines, got %d bytes", maxLogBytes, len(out))
+	}
+	if !strings.Contains(note, "--full") {
+		t.Errorf("expected a truncation note, got %q", note)
+	}
+
+	out, note = run("--raw", "--full", "42")
+	if out != content || note != "" {
+		t.Errorf("--full printed %d of %d bytes with note %q", len(out), len(content), note)
+	}
+}
+
 func TestLooksLikeJSON(t *testing.T) {
 	tests := []struct {
 		input string
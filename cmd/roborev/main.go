@@ -9,17 +9,21 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"text/tabwriter"
 	"time"
@@ -30,6 +34,7 @@ import (
 	"github.com/roborev-dev/roborev/internal/git"
 	"github.com/roborev-dev/roborev/internal/githook"
 	"github.com/roborev-dev/roborev/internal/prompt"
+	"github.com/roborev-dev/roborev/internal/review"
 	"github.com/roborev-dev/roborev/internal/skills"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/roborev-dev/roborev/internal/update"
@@ -40,11 +45,19 @@ import (
 var (
 	serverAddr string
 	verbose    bool
+	profile    string
+
+	// serverFlagSet records whether --server was given explicitly
+	serverFlagSet bool
 
 	// Polling intervals for waitForJob - exposed for testing
 	pollStartInterval = 1 * time.Second
 	pollMaxInterval   = 5 * time.Second
 
+	// eventFallbackInterval is how often waitForJob re-checks status while
+	// subscribed to job events, in case an event is dropped
+	eventFallbackInterval = 30 * time.Second
+
 	// setupSignalHandler allows tests to mock signal handling
 	setupSignalHandler = func() (chan os.Signal, func()) {
 		sigCh := make(chan os.Signal, 1)
@@ -66,6 +79,15 @@ func main() {
 
 	rootCmd.PersistentFlags().StringVar(&serverAddr, "server", "http://127.0.0.1:7373", "daemon server address")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also honors NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "use a separate config, database, and daemon (also honors "+config.ProfileEnvVar+")")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := applyProfile(cmd, profile); err != nil {
+			return err
+		}
+		registerCustomAgents(cmd.ErrOrStderr())
+		return nil
+	}
 
 	rootCmd.AddCommand(initCmd())
 	rootCmd.AddCommand(reviewCmd())
@@ -76,6 +98,7 @@ func main() {
 	rootCmd.AddCommand(commentCmd())
 	rootCmd.AddCommand(respondCmd()) // hidden alias for backward compatibility
 	rootCmd.AddCommand(addressCmd())
+	rootCmd.AddCommand(seenCmd())
 	rootCmd.AddCommand(installHookCmd())
 	rootCmd.AddCommand(uninstallHookCmd())
 	rootCmd.AddCommand(daemonCmd())
@@ -92,8 +115,27 @@ func main() {
 	rootCmd.AddCommand(syncCmd())
 	rootCmd.AddCommand(remapCmd())
 	rootCmd.AddCommand(checkAgentsCmd())
+	rootCmd.AddCommand(doctorCmd())
 	rootCmd.AddCommand(ciCmd())
+	rootCmd.AddCommand(ghCmd())
 	rootCmd.AddCommand(logCmd())
+	rootCmd.AddCommand(dbCmd())
+	rootCmd.AddCommand(trailerCmd())
+	rootCmd.AddCommand(cancelCmd())
+	rootCmd.AddCommand(pauseCmd())
+	rootCmd.AddCommand(resumeCmd())
+	rootCmd.AddCommand(retryCmd())
+	rootCmd.AddCommand(requeueCmd())
+	rootCmd.AddCommand(retryFailedCmd())
+	rootCmd.AddCommand(recoverCmd())
+	rootCmd.AddCommand(statsCmd())
+	rootCmd.AddCommand(ignoreCmd())
+	rootCmd.AddCommand(watchFilesCmd())
+	rootCmd.AddCommand(exportCmd())
+	rootCmd.AddCommand(pruneCmd())
+	rootCmd.AddCommand(applySuggestionCmd())
+	rootCmd.AddCommand(patchCmd())
+	rootCmd.AddCommand(searchCmd())
 	rootCmd.AddCommand(configCmd())
 	rootCmd.AddCommand(updateCmd())
 	rootCmd.AddCommand(versionCmd())
@@ -107,6 +149,55 @@ func main() {
 	}
 }
 
+// applyProfile activates the --profile flag, if given, by exporting it as
+// ROBOREV_PROFILE so config paths and spawned daemons pick it up. Under a
+// non-default profile without --server, the daemon address defaults to the
+// profile config's server_addr.
+func applyProfile(cmd *cobra.Command, name string) error {
+	if name != "" {
+		if err := config.ValidateProfileName(name); err != nil {
+			return err
+		}
+		if err := os.Setenv(config.ProfileEnvVar, name); err != nil {
+			return fmt.Errorf("set %s: %w", config.ProfileEnvVar, err)
+		}
+	} else if active := config.ActiveProfile(); active != "" {
+		if err := config.ValidateProfileName(active); err != nil {
+			return fmt.Errorf("%s: %w", config.ProfileEnvVar, err)
+		}
+	}
+
+	serverFlagSet = cmd.Flags().Changed("server")
+	if !profileIsolated() || serverFlagSet {
+		return nil
+	}
+	if cfg, err := config.LoadGlobal(); err == nil && cfg.ServerAddr != "" {
+		serverAddr = "http://" + cfg.ServerAddr
+	}
+	return nil
+}
+
+// registerCustomAgents makes the [agents.<name>] agents from the global
+// config known to this process, so --agent and agent checks accept them.
+// A config that fails to load is reported by the command that needs it.
+func registerCustomAgents(w io.Writer) {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return
+	}
+	if err := agent.SetCustomAgents(cfg.CustomAgentCommands()); err != nil {
+		fmt.Fprintf(w, "Warning: skipping invalid custom agents: %v\n", err)
+	}
+}
+
+// profileIsolated reports whether a non-default profile is active. Such a
+// profile's daemon is found only through its own runtime files, never by
+// probing a shared default address another profile's daemon may hold.
+func profileIsolated() bool {
+	active := config.ActiveProfile()
+	return active != "" && active != config.DefaultProfile
+}
+
 // getDaemonAddr returns the daemon address from runtime file or default
 func getDaemonAddr() string {
 	if info, err := daemon.GetAnyRunningDaemon(); err == nil {
@@ -160,8 +251,7 @@ func registerRepo(repoPath string) error {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		msg, _ := io.ReadAll(resp.Body)
-		return &registerRepoError{StatusCode: resp.StatusCode, Body: string(msg)}
+		return &registerRepoError{StatusCode: resp.StatusCode, Body: readErrorBody(resp.Body, resp.Status)}
 	}
 	return nil
 }
@@ -197,6 +287,12 @@ func ensureDaemon() error {
 		}
 	}
 
+	// Under a non-default profile the default address may belong to
+	// another profile's daemon, so only probe it when --server was given
+	if profileIsolated() && !serverFlagSet {
+		return startDaemon()
+	}
+
 	// Try default address - also check version from response
 	resp, err := client.Get(serverAddr + "/api/status")
 	if err == nil {
@@ -643,6 +739,14 @@ func daemonCmd() *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "resume",
+		Short: "Resume a paused queue (same as 'roborev resume')",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runResume(cmd.OutOrStdout())
+		},
+	})
+
 	cmd.AddCommand(daemonRunCmd())
 
 	return cmd
@@ -684,6 +788,15 @@ func daemonRunCmd() *cobra.Command {
 				os.Remove(oldDaemonPath) // Ignore errors silently
 			}
 
+			// Resolve default paths here rather than at flag definition so
+			// they follow the --profile flag
+			if dbPath == "" {
+				dbPath = storage.DefaultDBPath()
+			}
+			if configPath == "" {
+				configPath = config.GlobalConfigPath()
+			}
+
 			// Load configuration from specified path
 			cfg, err := config.LoadGlobalFrom(configPath)
 			if err != nil {
@@ -797,8 +910,8 @@ func daemonRunCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&dbPath, "db", storage.DefaultDBPath(), "path to sqlite database")
-	cmd.Flags().StringVar(&configPath, "config", config.GlobalConfigPath(), "path to config file")
+	cmd.Flags().StringVar(&dbPath, "db", "", "path to sqlite database (default: reviews.db in the data directory)")
+	cmd.Flags().StringVar(&configPath, "config", "", "path to config file (default: config.toml in the data directory)")
 	cmd.Flags().StringVar(&addr, "addr", "", "server address (overrides config)")
 	cmd.Flags().IntVar(&workers, "workers", 0, "number of workers (overrides config)")
 
@@ -819,11 +932,19 @@ func reviewCmd() *cobra.Command {
 		fast       bool
 		quiet      bool
 		dirty      bool
+		stash      string
 		wait       bool
 		branch     string
 		baseBranch string
 		since      string
 		local      bool
+		author     string
+		allBranch  bool
+		pattern    string
+		profile    string
+		stdin      bool
+		eachCommit bool
+		rerun      int64
 	)
 
 	cmd := &cobra.Command{
@@ -836,8 +957,11 @@ Examples:
   roborev review              # Review HEAD
   roborev review abc123       # Review specific commit
   roborev review abc123 def456  # Review range from abc123 to def456 (inclusive)
+  roborev review main..HEAD   # Review a range's combined diff as one job
+  roborev review main..HEAD --each-commit  # Review each commit in a range as its own job
   roborev review --dirty      # Review uncommitted changes
   roborev review --dirty --wait  # Review uncommitted changes and wait for result
+  roborev review --stash stash@{1}  # Review a stash entry before popping it
   roborev review --type design   # Design-focused review of HEAD
   roborev review --branch     # Review all commits on current branch since main
   roborev review --branch --base develop  # Review branch against develop
@@ -846,6 +970,13 @@ Examples:
   roborev review --since abc123  # Review commits since abc123 (exclusive)
   roborev review --type security   # Security-focused review of HEAD
   roborev review --branch --type security  # Security review of branch
+  roborev review abc123 def456 --author me@example.com  # Only commits by one author
+  roborev review --all-branches                     # Review every local branch tip not yet reviewed
+  roborev review --all-branches --pattern 'feat/*'  # Only branches matching a glob
+  roborev review --review-profile security  # Use [profiles.security] from .roborev.toml
+  roborev review --agent codex --model o3 --reasoning thorough  # One-off agent, model, and reasoning
+  roborev review --stdin --base abc123 < edited.diff  # Review an edited diff of abc123
+  roborev review --rerun 42 --wait  # Review job 42's ref again and compare verdicts
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// In quiet mode, suppress cobra's error output (hook uses &, so exit code doesn't matter)
@@ -854,6 +985,24 @@ Examples:
 				cmd.SilenceUsage = true
 			}
 
+			// A rerun repeats the original job's review, so it takes none
+			// of the options that pick what or how to review
+			if cmd.Flags().Changed("rerun") {
+				if rerun <= 0 {
+					return fmt.Errorf("invalid --rerun job ID: %d", rerun)
+				}
+				if len(args) > 0 {
+					return fmt.Errorf("cannot specify commits with --rerun")
+				}
+				for _, name := range []string{"repo", "sha", "agent", "model", "reasoning", "fast", "dirty", "stash", "branch", "base",
+					"stdin", "since", "local", "type", "author", "all-branches", "pattern", "each-commit", "review-profile"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--rerun cannot be combined with --%s", name)
+					}
+				}
+				return rerunReview(cmd, rerun, wait, quiet)
+			}
+
 			// --fast is shorthand for --reasoning fast (explicit --reasoning takes precedence)
 			reasoning = resolveReasoningWithFast(reasoning, fast, cmd.Flags().Changed("reasoning"))
 
@@ -871,8 +1020,9 @@ Examples:
 				return fmt.Errorf("not a git repository: %w", err)
 			}
 
-			// Skip during rebase to avoid reviewing every replayed commit
-			if git.IsRebaseInProgress(root) {
+			// Skip during rebase to avoid reviewing every replayed commit,
+			// unless skip_during_rebase is turned off
+			if git.IsRebaseInProgress(root) && skipDuringRebase(root) {
 				if !quiet {
 					cmd.Println("Skipping: rebase in progress")
 				}
@@ -895,12 +1045,62 @@ Examples:
 			if since != "" && len(args) > 0 {
 				return fmt.Errorf("cannot specify commits with --since")
 			}
+			if author != "" && dirty {
+				return fmt.Errorf("cannot use --author with --dirty")
+			}
+			if stash != "" && (len(args) > 0 || branch != "" || since != "" || dirty || author != "") {
+				return fmt.Errorf("--stash cannot be combined with commits, --branch, --since, --dirty, or --author")
+			}
+			if stdin {
+				if baseBranch == "" {
+					return fmt.Errorf("--stdin requires --base <commit> to record the review against")
+				}
+				if len(args) > 0 || branch != "" || since != "" || dirty || stash != "" || author != "" || allBranch {
+					return fmt.Errorf("--stdin cannot be combined with commits, --branch, --since, --dirty, --stash, --author, or --all-branches")
+				}
+			}
+			if allBranch {
+				if len(args) > 0 || branch != "" || since != "" || dirty || stash != "" || author != "" || local || wait {
+					return fmt.Errorf("--all-branches cannot be combined with commits, --branch, --since, --dirty, --stash, --author, --local, or --wait")
+				}
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("invalid --pattern %q: %w", pattern, err)
+				}
+			} else if pattern != "" {
+				return fmt.Errorf("--pattern requires --all-branches")
+			}
 
 			// Validate --type flag
 			if reviewType != "" && reviewType != "security" && reviewType != "design" {
 				return fmt.Errorf("invalid --type %q (valid: security, design)", reviewType)
 			}
 
+			// A profile from .roborev.toml fills in the agent, model, and
+			// reasoning that weren't given explicitly
+			if profile != "" {
+				repoCfg, err := config.LoadRepoConfig(root)
+				if err != nil {
+					return fmt.Errorf("load .roborev.toml: %w", err)
+				}
+				p, err := repoCfg.ReviewProfile(profile)
+				if err != nil {
+					return err
+				}
+				if agent == "" {
+					agent = p.Agent
+				}
+				if model == "" {
+					model = p.Model
+				}
+				if reasoning == "" {
+					reasoning = p.Reasoning
+				}
+			}
+
+			if err := validateAgentOptions(agent, reasoning); err != nil {
+				return err
+			}
+
 			// Auto-install/upgrade hooks when running from CLI
 			// (not when called from a hook via --quiet).
 			// Runs after validation so invalid args don't
@@ -909,6 +1109,44 @@ Examples:
 				autoInstallHooks(root)
 			}
 
+			// Resolve the stash entry before contacting the daemon so an
+			// invalid ref never enqueues anything
+			var stashRef string
+			if stash != "" {
+				stashRef, err = git.ResolveStashRef(root, stash)
+				if err != nil {
+					return fmt.Errorf("invalid --stash: %w", err)
+				}
+			}
+
+			// Read and check the --stdin diff before contacting the daemon
+			var stdinDiff, stdinBase string
+			if stdin {
+				stdinBase, err = git.ResolveSHA(root, baseBranch+"^{commit}")
+				if err != nil {
+					return fmt.Errorf("invalid --base %q: not a commit", baseBranch)
+				}
+				data, err := io.ReadAll(io.LimitReader(cmd.InOrStdin(), MaxDirtyDiffSize+1))
+				if err != nil {
+					return fmt.Errorf("read diff from stdin: %w", err)
+				}
+				if len(data) > MaxDirtyDiffSize {
+					return fmt.Errorf("stdin diff too large (max %d bytes)", MaxDirtyDiffSize)
+				}
+				if strings.TrimSpace(string(data)) == "" {
+					return fmt.Errorf("no diff on stdin")
+				}
+				stdinDiff = string(data)
+			}
+
+			// Fail fast when the chosen agent isn't installed. Hooks (--quiet)
+			// skip this and rely on the daemon's fallback.
+			if !quiet {
+				if err := checkReviewAgent(cmd.ErrOrStderr(), root, agent, reasoning, reviewType); err != nil {
+					return err
+				}
+			}
+
 			// Ensure daemon is running (skip for --local mode)
 			if !local {
 				if err := ensureDaemon(); err != nil {
@@ -916,8 +1154,39 @@ Examples:
 				}
 			}
 
+			// --each-commit splits a single "a..b" or "a...b" argument into
+			// one review per commit in the range
+			if eachCommit {
+				if len(args) != 1 || !git.IsRange(args[0]) {
+					return fmt.Errorf("--each-commit requires a single range argument like main..HEAD")
+				}
+				if author != "" || local {
+					return fmt.Errorf("--each-commit cannot be combined with --author or --local")
+				}
+				if wait {
+					return fmt.Errorf("--wait cannot be used when reviewing each commit in a range (use 'roborev review START END --wait' for a single range review)")
+				}
+				return enqueueRangeCommits(cmd, root, args[0], map[string]any{
+					"branch":      git.GetCurrentBranch(root),
+					"agent":       agent,
+					"model":       model,
+					"reasoning":   reasoning,
+					"review_type": reviewType,
+				}, quiet)
+			}
+
+			if allBranch {
+				return enqueueBranchTips(cmd, root, pattern, map[string]any{
+					"agent":       agent,
+					"model":       model,
+					"reasoning":   reasoning,
+					"review_type": reviewType,
+				}, quiet)
+			}
+
 			var gitRef string
 			var diffContent string
+			var rangeLabel string // The range argument as written, for display
 
 			if branch != "" {
 				// Branch review - review all commits since diverging from base
@@ -1018,17 +1287,75 @@ Examples:
 				}
 
 				gitRef = "dirty"
+			} else if stashRef != "" {
+				// Stash review - the stash against the commit it was made on
+				diffContent, err = git.GetStashDiff(root, stashRef)
+				if err != nil {
+					return fmt.Errorf("get stash diff: %w", err)
+				}
+				if len(diffContent) > MaxDirtyDiffSize {
+					return fmt.Errorf("stash diff too large (%d bytes, max %d bytes)", len(diffContent), MaxDirtyDiffSize)
+				}
+				if diffContent == "" {
+					return fmt.Errorf("no changes to review in %s (diff is empty)", stashRef)
+				}
+
+				gitRef = stashRef
+			} else if stdinDiff != "" {
+				// Edited diff, recorded against the base commit
+				gitRef = stdinBase
+				diffContent = stdinDiff
 			} else if len(args) >= 2 {
 				// Range: START END -> START^..END (inclusive)
 				gitRef = args[0] + "^.." + args[1]
 			} else if len(args) == 1 {
-				// Single commit
+				// Single commit, or an "a..b" / "a...b" range reviewed as
+				// one combined diff
 				gitRef = args[0]
+				if git.IsRange(gitRef) && author == "" {
+					commits, err := git.GetRangeCommits(root, gitRef)
+					if err != nil {
+						return fmt.Errorf("cannot get commits in %s: %w", gitRef, err)
+					}
+					if len(commits) == 0 {
+						if !quiet {
+							cmd.Printf("No commits in %s\n", gitRef)
+						}
+						return nil
+					}
+					rangeLabel = gitRef
+				}
 			} else {
 				// Default to HEAD
 				gitRef = sha
 			}
 
+			// Narrow a range to one author's commits, sending their
+			// patches as the diff to review
+			if author != "" {
+				if !git.IsRange(gitRef) {
+					return fmt.Errorf("--author requires a commit range")
+				}
+				commits, err := git.GetRangeCommitsByAuthor(root, gitRef, author)
+				if err != nil {
+					return fmt.Errorf("cannot get commits: %w", err)
+				}
+				if len(commits) == 0 {
+					return fmt.Errorf("no commits by %q in %s", author, gitRef)
+				}
+				diffContent, err = git.GetCommitsDiff(root, commits)
+				if err != nil {
+					return fmt.Errorf("get author diff: %w", err)
+				}
+				if len(diffContent) > MaxDirtyDiffSize {
+					return fmt.Errorf("diff for %d commits by %q too large (%d bytes, max %d bytes)",
+						len(commits), author, len(diffContent), MaxDirtyDiffSize)
+				}
+				if !quiet {
+					cmd.Printf("Reviewing %d commits by %s in %s\n", len(commits), author, gitRef)
+				}
+			}
+
 			// Get branch name for tracking. When --branch=<name> targets
 			// a different branch, use that name instead of the checked-out branch.
 			branchName := git.GetCurrentBranch(root)
@@ -1051,11 +1378,12 @@ Examples:
 				"reasoning":    reasoning,
 				"review_type":  reviewType,
 				"diff_content": diffContent,
+				"range_label":  rangeLabel,
 			}
 
 			reqBody, _ := json.Marshal(reqFields)
 
-			resp, err := http.Post(serverAddr+"/api/enqueue", "application/json", bytes.NewReader(reqBody))
+			resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/enqueue", reqBody)
 			if err != nil {
 				return fmt.Errorf("failed to connect to daemon: %w", err)
 			}
@@ -1078,23 +1406,27 @@ Examples:
 			}
 
 			if resp.StatusCode != http.StatusCreated {
-				return fmt.Errorf("review failed: %s", body)
+				return fmt.Errorf("review failed: %s", readErrorBody(bytes.NewReader(body), resp.Status))
 			}
 
+			// A job without an ID can't be waited on; fail rather than poll
+			// for a job that doesn't exist
 			var job storage.ReviewJob
-			_ = json.Unmarshal(body, &job)
+			if err := json.Unmarshal(body, &job); err != nil || job.ID == 0 {
+				return fmt.Errorf("invalid enqueue response: %s", body)
+			}
 
 			if !quiet {
 				if dirty {
 					cmd.Printf("Enqueued dirty review job %d (agent: %s)\n", job.ID, job.Agent)
 				} else {
-					cmd.Printf("Enqueued job %d for %s (agent: %s)\n", job.ID, shortRef(job.GitRef), job.Agent)
+					cmd.Printf("Enqueued job %d for %s (agent: %s)\n", job.ID, shortJobRef(job), job.Agent)
 				}
 			}
 
 			// If --wait, poll until job completes and show result
 			if wait {
-				err := waitForJob(cmd, serverAddr, job.ID, quiet)
+				err := waitForJob(context.Background(), cmd, serverAddr, job.ID, quiet)
 				// Only silence Cobra's error output for exitError (verdict-based exit codes)
 				// Keep error output for actual failures (network errors, job not found, etc.)
 				if _, isExitErr := err.(*exitError); isExitErr {
@@ -1110,25 +1442,201 @@ Examples:
 
 	cmd.Flags().StringVar(&repoPath, "repo", "", "path to git repository (default: current directory)")
 	cmd.Flags().StringVar(&sha, "sha", "HEAD", "commit SHA to review (used when no positional args)")
-	cmd.Flags().StringVar(&agent, "agent", "", "agent to use (codex, claude-code, gemini, copilot, opencode, cursor)")
+	cmd.Flags().StringVar(&agent, "agent", "", "agent to use for this review, overriding config (codex, claude-code, gemini, copilot, opencode, cursor, droid)")
 	cmd.Flags().StringVar(&model, "model", "", "model for agent (format varies: opencode uses provider/model, others use model name)")
 	cmd.Flags().StringVar(&reasoning, "reasoning", "", "reasoning level: thorough (default), standard, or fast")
 	cmd.Flags().BoolVar(&fast, "fast", false, "shorthand for --reasoning fast")
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "suppress output (for use in hooks)")
 	cmd.Flags().BoolVar(&dirty, "dirty", false, "review uncommitted changes instead of a commit")
+	cmd.Flags().StringVar(&stash, "stash", "", "review a stash entry (e.g. stash@{1}) against the commit it was stashed on")
 	cmd.Flags().BoolVar(&wait, "wait", false, "wait for review to complete and show result")
 	cmd.Flags().StringVar(&branch, "branch", "", "review all changes since branch diverged from base (optionally specify branch name)")
 	cmd.Flags().Lookup("branch").NoOptDefVal = "HEAD"
-	cmd.Flags().StringVar(&baseBranch, "base", "", "base branch for --branch comparison (default: auto-detect)")
+	cmd.Flags().StringVar(&baseBranch, "base", "", "base branch for --branch comparison (default: auto-detect), or the commit a --stdin diff is recorded against")
+	cmd.Flags().BoolVar(&stdin, "stdin", false, "review a diff read from stdin, recorded against the --base commit")
 	cmd.Flags().StringVar(&since, "since", "", "review commits since this commit (exclusive, like git's .. range)")
 	cmd.Flags().BoolVar(&local, "local", false, "run review locally without daemon (streams output to console)")
 	cmd.Flags().StringVar(&reviewType, "type", "", "review type (security, design) — changes system prompt")
+	cmd.Flags().StringVar(&author, "author", "", "with a range, review only commits whose author name or email contains this")
+	cmd.Flags().BoolVar(&allBranch, "all-branches", false, "review the tip of every local branch that has not been reviewed")
+	cmd.Flags().StringVar(&pattern, "pattern", "", "with --all-branches, only branches whose name matches this glob")
+	cmd.Flags().BoolVar(&eachCommit, "each-commit", false, "with a single a..b range, review each commit as its own job")
+	cmd.Flags().StringVar(&profile, "review-profile", "", "use the agent, model, and reasoning from [profiles.<name>] in .roborev.toml")
+	cmd.Flags().Int64Var(&rerun, "rerun", 0, "review a finished review job's ref again with the same agent, linked to that job")
 	registerAgentCompletion(cmd)
 	registerReasoningCompletion(cmd)
 
 	return cmd
 }
 
+// enqueueBranchTips enqueues a review of each local branch tip whose name
+// matches pattern (a path.Match glob; empty matches every branch). Tips that
+// already have a job, other than a failed or canceled one, are skipped, and
+// branches sharing a tip are reviewed once. fields holds the agent options
+// sent with each enqueue request.
+func enqueueBranchTips(cmd *cobra.Command, root, pattern string, fields map[string]any, quiet bool) error {
+	branches, err := git.ListLocalBranches(root)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var jobIDs []int64
+	for _, b := range branches {
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, b.Name); !ok {
+				continue
+			}
+		}
+		if seen[b.SHA] {
+			continue
+		}
+		seen[b.SHA] = true
+
+		existing, err := findJobForCommit(root, b.SHA)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", b.Name, err)
+		}
+		if existing != nil && existing.Status != storage.JobStatusFailed && existing.Status != storage.JobStatusCanceled {
+			if !quiet {
+				cmd.Printf("Skipping %s (%s): already reviewed in job %d\n", b.Name, git.ShortSHA(b.SHA), existing.ID)
+			}
+			continue
+		}
+
+		req := map[string]any{"repo_path": root, "git_ref": b.SHA, "branch": b.Name}
+		maps.Copy(req, fields)
+		reqBody, _ := json.Marshal(req)
+		resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/enqueue", reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to connect to daemon: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusCreated:
+			var job storage.ReviewJob
+			_ = json.Unmarshal(body, &job)
+			jobIDs = append(jobIDs, job.ID)
+			if !quiet {
+				cmd.Printf("Enqueued job %d for %s (%s)\n", job.ID, b.Name, git.ShortSHA(b.SHA))
+			}
+		case http.StatusOK:
+			if !quiet {
+				cmd.Printf("Skipping %s (%s): daemon skipped the review\n", b.Name, git.ShortSHA(b.SHA))
+			}
+		default:
+			return fmt.Errorf("review of %s failed: %s", b.Name, readErrorBody(bytes.NewReader(body), resp.Status))
+		}
+	}
+
+	if !quiet {
+		if len(jobIDs) == 0 {
+			cmd.Println("No unreviewed branch tips")
+		} else {
+			cmd.Printf("Enqueued %d review(s)\n", len(jobIDs))
+		}
+	}
+	return nil
+}
+
+// enqueueRangeCommits enqueues one review per commit in rangeRef, oldest
+// first, labeling each job with the range so they can be shown together.
+// An empty range is reported and is not an error.
+func enqueueRangeCommits(cmd *cobra.Command, root, rangeRef string, fields map[string]any, quiet bool) error {
+	commits, err := git.GetRangeCommits(root, rangeRef)
+	if err != nil {
+		return fmt.Errorf("cannot get commits in %s: %w", rangeRef, err)
+	}
+	if len(commits) == 0 {
+		if !quiet {
+			cmd.Printf("No commits in %s\n", rangeRef)
+		}
+		return nil
+	}
+
+	var jobIDs []int64
+	for _, sha := range commits {
+		req := map[string]any{"repo_path": root, "git_ref": sha, "range_label": rangeRef}
+		maps.Copy(req, fields)
+		reqBody, _ := json.Marshal(req)
+		resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/enqueue", reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to connect to daemon: %w", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusCreated:
+			var job storage.ReviewJob
+			_ = json.Unmarshal(body, &job)
+			jobIDs = append(jobIDs, job.ID)
+			if !quiet {
+				cmd.Printf("Enqueued job %d for %s\n", job.ID, git.ShortSHA(sha))
+			}
+		case http.StatusOK:
+			if !quiet {
+				cmd.Printf("Skipping %s: daemon skipped the review\n", git.ShortSHA(sha))
+			}
+		default:
+			return fmt.Errorf("review of %s failed: %s", git.ShortSHA(sha), readErrorBody(bytes.NewReader(body), resp.Status))
+		}
+	}
+
+	if !quiet {
+		cmd.Printf("Enqueued %d review(s) for %s\n", len(jobIDs), rangeRef)
+	}
+	return nil
+}
+
+// validateAgentOptions rejects an unknown agent name or reasoning level
+// before anything is sent to the daemon.
+func validateAgentOptions(agentName, reasoning string) error {
+	if agentName != "" {
+		if err := agent.ValidateName(agentName); err != nil {
+			return err
+		}
+	}
+	if _, err := config.NormalizeReasoning(reasoning); err != nil {
+		return fmt.Errorf("invalid --reasoning: %w (valid: thorough, standard, fast)", err)
+	}
+	return nil
+}
+
+// checkReviewAgent fails fast when an agent given by flag or profile is not
+// installed. An agent that comes from config only gets a warning written to
+// w, since the daemon falls back to another installed agent and may see a
+// different PATH.
+func checkReviewAgent(w io.Writer, repoPath, agentName, reasoning, reviewType string) error {
+	if agentName != "" {
+		return agent.CheckAgentAvailable(agentName)
+	}
+
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	reasoning, err = config.ResolveReviewReasoning(reasoning, repoPath)
+	if err != nil {
+		return fmt.Errorf("invalid reasoning: %w", err)
+	}
+	workflow := "review"
+	if !config.IsDefaultReviewType(reviewType) {
+		workflow = reviewType
+	}
+	agentName = config.ResolveAgentForWorkflow("", repoPath, cfg, workflow, reasoning)
+
+	if err := agent.CheckAgentAvailable(agentName); err != nil {
+		fmt.Fprintf(w, "Warning: %v\n", err)
+		if fallback, err := agent.GetAvailable(agentName); err == nil {
+			fmt.Fprintf(w, "The daemon will fall back to %s.\n", fallback.Name())
+		}
+	}
+	return nil
+}
+
 // runLocalReview runs a review directly without the daemon
 func runLocalReview(cmd *cobra.Command, repoPath, gitRef, diffContent, agentName, model, reasoning, reviewType string, quiet bool) error {
 	// Load config
@@ -1165,10 +1673,16 @@ func runLocalReview(cmd *cobra.Command, repoPath, gitRef, diffContent, agentName
 	reasoningLevel := agent.ParseReasoningLevel(reasoning)
 	a = a.WithReasoning(reasoningLevel).WithModel(model)
 
-	// Use consistent output writer, respecting --quiet
+	// Use consistent output writer, respecting --quiet. The agent stream is
+	// rendered through the stream formatter so progress shows as it arrives.
 	var out = cmd.OutOrStdout()
+	var stream io.Writer = io.Discard
+	var fmtr *streamFormatter
 	if quiet {
 		out = io.Discard
+	} else {
+		fmtr = newStreamFormatter(out, writerIsTerminal(out))
+		stream = fmtr
 	}
 
 	if !quiet {
@@ -1177,7 +1691,13 @@ func runLocalReview(cmd *cobra.Command, repoPath, gitRef, diffContent, agentName
 
 	// Build prompt
 	var reviewPrompt string
-	if diffContent != "" {
+	if diffContent != "" && git.IsRange(gitRef) {
+		// Range narrowed to selected commits
+		reviewPrompt, err = prompt.NewBuilder(nil).BuildRangeWithDiff(repoPath, gitRef, diffContent, 0, cfg.ReviewContextCount, a.Name(), reviewType)
+	} else if diffContent != "" && gitRef != "dirty" && !git.IsStashRef(gitRef) {
+		// Commit reviewed with a diff from --stdin
+		reviewPrompt, err = prompt.NewBuilder(nil).BuildWithDiff(repoPath, gitRef, diffContent, 0, cfg.ReviewContextCount, a.Name(), reviewType)
+	} else if diffContent != "" {
 		// Dirty review
 		reviewPrompt, err = prompt.NewBuilder(nil).BuildDirty(repoPath, diffContent, 0, cfg.ReviewContextCount, a.Name(), reviewType)
 	} else {
@@ -1187,24 +1707,175 @@ func runLocalReview(cmd *cobra.Command, repoPath, gitRef, diffContent, agentName
 		return fmt.Errorf("build prompt: %w", err)
 	}
 
-	// Run review with output writer
+	// Run review, streaming output while buffering it for parsing
 	ctx := context.Background()
-	_, err = a.Review(ctx, repoPath, gitRef, reviewPrompt, out)
+	output, err := runLocalAgent(ctx, a, repoPath, gitRef, reviewPrompt, stream)
+	if fmtr != nil {
+		fmtr.Flush()
+	}
 	if err != nil {
 		return fmt.Errorf("review failed: %w", err)
 	}
 
+	// Apply the repo's verdict gating, as the daemon does for stored reviews
+	policy := storage.PolicyFromGating(config.ResolveVerdictGating(repoPath, cfg))
+
+	// Store the review, even when --quiet suppresses the stream
+	if err := storeLocalReview(repoPath, gitRef, diffContent, storage.EnqueueOpts{
+		Agent:      a.Name(),
+		Model:      model,
+		Reasoning:  reasoning,
+		ReviewType: reviewType,
+	}, reviewPrompt, output, policy); err != nil {
+		return fmt.Errorf("store review: %w", err)
+	}
+
 	if !quiet {
 		fmt.Fprintln(out) // Final newline
+		if policy.Verdict(output) == "P" {
+			fmt.Fprintln(out, "Verdict: PASS")
+		} else {
+			fmt.Fprintln(out, "Verdict: FAIL")
+		}
 	}
 	return nil
 }
 
+// storeLocalReview records a review run by --local in the database, keyed
+// the same way the daemon keys an enqueued review of gitRef. opts carries
+// the agent settings; the repo, ref and job type are filled in here.
+func storeLocalReview(repoPath, gitRef, diffContent string, opts storage.EnqueueOpts, reviewPrompt, output string, policy storage.VerdictPolicy) error {
+	dbPath := storage.DefaultDBPath()
+	if dbPath == "" {
+		return fmt.Errorf("cannot determine database path")
+	}
+	db, err := storage.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	repoRoot, err := git.GetMainRepoRoot(repoPath)
+	if err != nil {
+		return err
+	}
+	repo, err := db.GetOrCreateRepo(repoRoot, config.ResolveRepoIdentity(repoRoot, nil))
+	if err != nil {
+		return fmt.Errorf("get repo: %w", err)
+	}
+	opts.RepoID = repo.ID
+	opts.Branch = git.GetCurrentBranch(repoPath)
+	opts.DiffContent = diffContent
+	if opts.ReviewType == "" {
+		opts.ReviewType = config.ReviewTypeDefault
+	}
+
+	switch {
+	case gitRef == "dirty" || git.IsStashRef(gitRef):
+		opts.GitRef = gitRef
+		opts.JobType = storage.JobTypeDirty
+	case git.IsRange(gitRef):
+		base, tip, err := git.ResolveRange(repoPath, gitRef)
+		if err != nil {
+			return err
+		}
+		opts.GitRef = tip
+		opts.RangeBase = base
+		opts.JobType = storage.JobTypeRange
+	default:
+		sha, err := git.ResolveSHA(repoPath, gitRef)
+		if err != nil {
+			return err
+		}
+		info, err := git.GetCommitInfo(repoRoot, sha)
+		if err != nil {
+			return fmt.Errorf("get commit info: %w", err)
+		}
+		commit, err := db.GetOrCreateCommit(repo.ID, sha, info.Author, info.Subject, info.Timestamp)
+		if err != nil {
+			return fmt.Errorf("get commit: %w", err)
+		}
+		opts.CommitID = commit.ID
+		opts.GitRef = sha
+		opts.JobType = storage.JobTypeReview
+		if diffContent != "" {
+			// A --stdin diff is linked to its base commit but isn't a
+			// review of that commit
+			opts.GitRef = "dirty"
+			opts.JobType = storage.JobTypeDirty
+		}
+	}
+
+	_, err = db.RecordLocalReview(opts, reviewPrompt, output, policy)
+	return err
+}
+
+// runLocalAgent runs the agent, copying its output to stream as it arrives
+// while also buffering it. Returns the agent's final result, falling back to
+// the buffered stream for agents that only report output incrementally.
+func runLocalAgent(ctx context.Context, a agent.Agent, repoPath, gitRef, reviewPrompt string, stream io.Writer) (string, error) {
+	var buf bytes.Buffer
+	result, err := a.Review(ctx, repoPath, gitRef, reviewPrompt, io.MultiWriter(stream, &buf))
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(result) == "" {
+		return buf.String(), nil
+	}
+	return result, nil
+}
+
+// subscribeJobEvents opens the daemon's /api/events stream for jobID and
+// returns a channel that receives a value whenever the job changes state.
+// It returns nil when the daemon does not serve the stream, so callers can
+// fall back to polling. The channel is closed when the stream ends.
+func subscribeJobEvents(ctx context.Context, serverAddr string, jobID int64) <-chan struct{} {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/events?job_id=%d", serverAddr, jobID), nil)
+	if err != nil {
+		return nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = 5 * time.Second
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body.Close()
+		return nil
+	}
+
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if !strings.HasPrefix(scanner.Text(), "event:") {
+				continue
+			}
+			select {
+			case ch <- struct{}{}:
+			default: // A wakeup is already pending
+			}
+		}
+	}()
+	return ch
+}
+
 // waitForJob polls until a job completes and displays the review
 // Uses the provided serverAddr to ensure we poll the same daemon that received the job.
-func waitForJob(cmd *cobra.Command, serverAddr string, jobID int64, quiet bool) error {
+// When the daemon serves /api/events, status is re-checked as job events
+// arrive instead of on a backoff timer. Polling stops with ctx's error when
+// ctx is done.
+func waitForJob(ctx context.Context, cmd *cobra.Command, serverAddr string, jobID int64, quiet bool) error {
 	client := &http.Client{Timeout: 5 * time.Second}
 
+	// Subscribe before the first status check so no transition is missed.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := subscribeJobEvents(ctx, serverAddr, jobID)
+
 	if !quiet {
 		cmd.Printf("Waiting for review to complete...")
 	}
@@ -1215,17 +1886,53 @@ func waitForJob(cmd *cobra.Command, serverAddr string, jobID int64, quiet bool)
 	unknownStatusCount := 0
 	const maxUnknownRetries = 10 // Give up after 10 consecutive unknown statuses
 
+	// sleep waits for the next job event or, without an event stream, the
+	// current poll interval (backing off), returning early with ctx's error
+	// if ctx is done first.
+	sleep := func() error {
+		if events != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case _, ok := <-events:
+				if !ok {
+					events = nil // Stream ended; fall back to polling
+				}
+			case <-time.After(eventFallbackInterval):
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+		if pollInterval < maxInterval {
+			pollInterval = min(
+				// 1.5x backoff
+				pollInterval*3/2, maxInterval)
+		}
+		return nil
+	}
+
 	for {
-		resp, err := client.Get(fmt.Sprintf("%s/api/jobs?id=%d", serverAddr, jobID))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/jobs?id=%d", serverAddr, jobID), nil)
 		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			return fmt.Errorf("failed to check job status: %w", err)
 		}
 
 		// Handle non-200 responses
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
+			msg := readErrorBody(resp.Body, resp.Status)
 			resp.Body.Close()
-			return fmt.Errorf("server error checking job status (%d): %s", resp.StatusCode, body)
+			return fmt.Errorf("server error checking job status (%d): %s", resp.StatusCode, msg)
 		}
 
 		var jobsResp struct {
@@ -1266,11 +1973,8 @@ func waitForJob(cmd *cobra.Command, serverAddr string, jobID int64, quiet bool)
 		case storage.JobStatusQueued, storage.JobStatusRunning:
 			// Still in progress, continue polling
 			unknownStatusCount = 0 // Reset counter on known status
-			time.Sleep(pollInterval)
-			if pollInterval < maxInterval {
-				pollInterval = min(
-					// 1.5x backoff
-					pollInterval*3/2, maxInterval)
+			if err := sleep(); err != nil {
+				return err
 			}
 
 		default:
@@ -1283,9 +1987,8 @@ func waitForJob(cmd *cobra.Command, serverAddr string, jobID int64, quiet bool)
 			if !quiet {
 				cmd.Printf("\n(unknown status %q, continuing to poll...)", job.Status)
 			}
-			time.Sleep(pollInterval)
-			if pollInterval < maxInterval {
-				pollInterval = min(pollInterval*3/2, maxInterval)
+			if err := sleep(); err != nil {
+				return err
 			}
 		}
 	}
@@ -1305,8 +2008,8 @@ func showReview(cmd *cobra.Command, addr string, jobID int64, quiet bool) error
 		return fmt.Errorf("no review found for job %d", jobID)
 	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("server error fetching review (%d): %s", resp.StatusCode, body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("server error fetching review (%d): %s", resp.StatusCode, msg)
 	}
 
 	var review storage.Review
@@ -1315,14 +2018,25 @@ func showReview(cmd *cobra.Command, addr string, jobID int64, quiet bool) error
 	}
 
 	if !quiet {
-		cmd.Printf("Review (by %s)\n", review.Agent)
+		cmd.Printf("Review (by %s)\n", review.ProducedBy())
+		if review.Job != nil && review.Job.CachedFromJobID != nil {
+			cmd.Printf("Reused from job %d, which reviewed an identical diff (review.dedup)\n", *review.Job.CachedFromJobID)
+		}
 		cmd.Println(strings.Repeat("-", 60))
 		cmd.Println(review.Output)
 	}
 
-	// Return exit code based on verdict
-	verdict := storage.ParseVerdict(review.Output)
-	if verdict == "F" {
+	// A reverted commit's findings no longer apply
+	if review.RevertedBy != "" {
+		if !quiet {
+			cmd.Printf("\nCommit was reverted by %s; review resolved\n", git.ShortSHA(review.RevertedBy))
+		}
+		return nil
+	}
+
+	// Return exit code based on the stored verdict, which honors the
+	// repo's verdict gating
+	if review.Verdict() == "F" {
 		// Use a special error that cobra will treat as exit code 1
 		return &exitError{code: 1}
 	}
@@ -1344,10 +2058,12 @@ func waitCmd() *cobra.Command {
 		shaFlag    string
 		forceJobID bool
 		quiet      bool
+		timeout    time.Duration
+		format     string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "wait [job_id|sha]",
+		Use:   "wait [job_id|sha]...",
 		Short: "Wait for an existing review job to complete",
 		Long: `Wait for an already-running review job to complete, without enqueuing a new one.
 
@@ -1357,19 +2073,29 @@ review to complete. The post-commit hook triggers the review, and the agent
 calls wait to block until the result is ready.
 
 The argument can be a job ID (numeric) or a git ref (commit SHA, branch, HEAD).
-If no argument is given, defaults to HEAD.
+A SHA prefix this clone can't resolve is matched against the daemon's jobs.
+If no argument is given, defaults to HEAD. Several job IDs can be given to wait
+for all of them concurrently; --timeout then applies to the whole batch.
+
+With --format json, the results are printed to stdout once every job has
+finished, as an array of {job_id, verdict, status, duration_ms} objects.
+The exit code is the same as with text output.
 
 Exit codes:
-  0  Review completed with verdict PASS
+  0  Review completed with verdict PASS (every review, for several jobs)
   1  Any failure (FAIL verdict, no job found, job error)
+  2  --timeout elapsed before the job(s) finished
 
 Examples:
   roborev wait                   # Wait for most recent job for HEAD
   roborev wait abc123            # Wait for most recent job for commit
   roborev wait 42                # Job ID (if "42" is not a valid git ref)
   roborev wait --job 42          # Force as job ID
-  roborev wait --sha HEAD~1      # Wait for job matching HEAD~1`,
-		Args: cobra.MaximumNArgs(1),
+  roborev wait --sha HEAD~1      # Wait for job matching HEAD~1
+  roborev wait --timeout 15m     # Give up (exit 2) after 15 minutes
+  roborev wait --job 42 43 44    # Wait for several jobs at once
+  roborev wait 42 43 --format json`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// In quiet mode, suppress cobra's error output
 			if quiet {
@@ -1384,11 +2110,46 @@ Examples:
 			if forceJobID && len(args) == 0 {
 				return fmt.Errorf("--job requires a job ID argument")
 			}
+			if timeout < 0 {
+				return fmt.Errorf("--timeout must not be negative")
+			}
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid --format %q (want text or json)", format)
+			}
+
+			if len(args) > 1 {
+				jobIDs := make([]int64, 0, len(args))
+				for _, arg := range args {
+					id, err := strconv.ParseInt(arg, 10, 64)
+					if err != nil || id <= 0 {
+						return fmt.Errorf("invalid job ID: %s (several arguments must all be job IDs)", arg)
+					}
+					jobIDs = append(jobIDs, id)
+				}
+				if err := ensureDaemon(); err != nil {
+					return fmt.Errorf("daemon not running: %w", err)
+				}
+
+				ctx := context.Background()
+				if timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, timeout)
+					defer cancel()
+				}
+				code := waitMultiple(ctx, cmd, getDaemonAddr(), jobIDs, quiet, format == "json")
+				if code != 0 {
+					cmd.SilenceErrors = true
+					cmd.SilenceUsage = true
+					return &exitError{code: code}
+				}
+				return nil
+			}
 
 			// Resolve the target to a job ID (local validation first,
 			// daemon contact deferred until actually needed)
 			var jobID int64
-			var ref string // git ref to resolve via findJobForCommit
+			var ref string      // git ref to resolve via findJobForCommit
+			var prefixOnly bool // ref is a SHA prefix for the daemon to expand
 
 			if shaFlag != "" {
 				ref = shaFlag
@@ -1409,9 +2170,14 @@ Examples:
 						}
 					}
 					if ref == "" {
-						// Not a valid git ref — try as numeric job ID
+						// Not a valid git ref — try as numeric job ID, then
+						// as a SHA prefix of a commit the daemon has a job
+						// for but this clone doesn't (e.g. since amended)
 						if id, err := strconv.ParseInt(arg, 10, 64); err == nil && id > 0 {
 							jobID = id
+						} else if storage.IsSHAPrefix(arg) {
+							ref = arg
+							prefixOnly = true
 						} else {
 							return fmt.Errorf("argument %q is not a valid git ref or job ID", arg)
 						}
@@ -1423,7 +2189,9 @@ Examples:
 
 			// Validate git ref before contacting daemon
 			var sha string
-			if ref != "" {
+			if prefixOnly {
+				sha = strings.ToLower(ref)
+			} else if ref != "" {
 				repoRoot, _ := git.GetRepoRoot(".")
 				resolved, err := git.ResolveSHA(repoRoot, ref)
 				if err != nil {
@@ -1448,7 +2216,9 @@ Examples:
 					return err
 				}
 				if job == nil {
-					if !quiet {
+					if format == "json" && !quiet {
+						fmt.Fprintln(cmd.OutOrStdout(), "[]")
+					} else if !quiet {
 						cmd.Printf("No job found for %s\n", ref)
 					}
 					cmd.SilenceErrors = true
@@ -1458,9 +2228,32 @@ Examples:
 				jobID = job.ID
 			}
 
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
 			addr := getDaemonAddr()
-			err := waitForJob(cmd, addr, jobID, quiet)
+			if format == "json" {
+				if code := waitMultiple(ctx, cmd, addr, []int64{jobID}, quiet, true); code != 0 {
+					cmd.SilenceErrors = true
+					cmd.SilenceUsage = true
+					return &exitError{code: code}
+				}
+				return nil
+			}
+			err := waitForJob(ctx, cmd, addr, jobID, quiet)
 			if err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					if !quiet {
+						cmd.Printf("\nTimed out after %s waiting for job %d\n", timeout, jobID)
+					}
+					cmd.SilenceErrors = true
+					cmd.SilenceUsage = true
+					return &exitError{code: 2}
+				}
 				// Map ErrJobNotFound to exit 1 with a user-facing message
 				// (waitForJob returns a plain error to stay compatible with reviewCmd)
 				if errors.Is(err, ErrJobNotFound) {
@@ -1483,12 +2276,111 @@ Examples:
 	cmd.Flags().StringVar(&shaFlag, "sha", "", "git ref to find the most recent job for")
 	cmd.Flags().BoolVar(&forceJobID, "job", false, "force argument to be treated as job ID")
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "suppress output (for use in hooks)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "give up and exit 2 if the job hasn't finished within this duration (0 = wait forever)")
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text or json")
 
 	return cmd
 }
 
+// waitResult is one job's entry in the output of wait --format json.
+type waitResult struct {
+	JobID      int64  `json:"job_id"`
+	Verdict    string `json:"verdict"` // The job's stored "P" or "F", or "" when it has none
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"` // How long the job ran; 0 if it didn't finish
+	Error      string `json:"error,omitempty"`
+}
+
+// waitMultiple waits for all jobIDs concurrently and prints one result line
+// per job, in argument order, or with jsonOut a JSON array of waitResults.
+// Cancelling ctx stops every remaining poll. Returns the wait exit code: 2
+// if any job was still pending when ctx expired, otherwise 1 if any job
+// failed, otherwise 0.
+func waitMultiple(ctx context.Context, cmd *cobra.Command, serverAddr string, jobIDs []int64, quiet, jsonOut bool) int {
+	errs := make([]error, len(jobIDs))
+	jobs := make([]*storage.ReviewJob, len(jobIDs))
+	var wg sync.WaitGroup
+	for i, id := range jobIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Always quiet: concurrent reviews would interleave their output
+			errs[i] = waitForJob(ctx, cmd, serverAddr, id, true)
+			if jsonOut {
+				// Report the final state even when ctx expired
+				fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+				defer cancel()
+				jobs[i], _ = fetchJob(fetchCtx, serverAddr, id)
+			}
+		}()
+	}
+	wg.Wait()
+
+	code := 0
+	results := make([]waitResult, 0, len(jobIDs))
+	for i, id := range jobIDs {
+		err := errs[i]
+		res := waitResult{JobID: id}
+		if job := jobs[i]; job != nil {
+			res.Status = string(job.Status)
+			if job.Verdict != nil {
+				res.Verdict = *job.Verdict
+			}
+			if job.StartedAt != nil && job.FinishedAt != nil {
+				res.DurationMs = job.FinishedAt.Sub(*job.StartedAt).Milliseconds()
+			}
+		}
+		var line string
+		switch {
+		case err == nil:
+			line = fmt.Sprintf("Job %d: PASS", id)
+		case errors.Is(err, context.DeadlineExceeded):
+			line = fmt.Sprintf("timed out waiting for job %d", id)
+			res.Error = "timed out"
+			code = 2
+		case errors.Is(err, ErrJobNotFound):
+			line = fmt.Sprintf("Job %d: not found", id)
+			res.Status = "not_found"
+		default:
+			var exitErr *exitError
+			if errors.As(err, &exitErr) {
+				line = fmt.Sprintf("Job %d: FAIL", id)
+			} else {
+				line = fmt.Sprintf("Job %d: %v", id, err)
+				res.Error = err.Error()
+			}
+		}
+		if err != nil && code == 0 {
+			code = 1
+		}
+		results = append(results, res)
+		if !quiet && !jsonOut {
+			cmd.Println(line)
+		}
+	}
+	if jsonOut && !quiet {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return 1
+		}
+	}
+	return code
+}
+
+// jobStatusLabel returns a job's status for CLI tables, calling out jobs
+// that failed because the agent rejected its credentials.
+func jobStatusLabel(j storage.ReviewJob) string {
+	if j.Status == storage.JobStatusFailed && review.IsAuthFailure(j.Error) {
+		return "auth failed"
+	}
+	return string(j.Status)
+}
+
 func statusCmd() *cobra.Command {
-	return &cobra.Command{
+	var byRepo bool
+
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show daemon and queue status",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -1502,7 +2394,11 @@ func statusCmd() *cobra.Command {
 
 			addr := getDaemonAddr()
 			client := &http.Client{Timeout: 2 * time.Second}
-			resp, err := client.Get(addr + "/api/status")
+			statusURL := addr + "/api/status"
+			if byRepo {
+				statusURL += "?by_repo=true"
+			}
+			resp, err := client.Get(statusURL)
 			if err != nil {
 				fmt.Println("Daemon: not running")
 				fmt.Println()
@@ -1538,6 +2434,21 @@ func statusCmd() *cobra.Command {
 			fmt.Printf("Workers: %d/%d active\n", status.ActiveWorkers, status.MaxWorkers)
 			fmt.Printf("Jobs:    %d queued, %d running, %d completed, %d failed\n",
 				status.QueuedJobs, status.RunningJobs, status.CompletedJobs, status.FailedJobs)
+			if status.QueuePausedReason != "" {
+				fmt.Printf("Queue:   PAUSED - %s\n", status.QueuePausedReason)
+				fmt.Println("         Fix the agent's credentials, then run 'roborev daemon resume'")
+			} else if status.QueuePausedByUser {
+				fmt.Println("Queue:   PAUSED - run 'roborev resume' to start new jobs")
+			}
+			if byRepo && len(status.QueueByRepo) > 0 {
+				fmt.Println()
+				fmt.Println("Queue by repo:")
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				for _, d := range status.QueueByRepo {
+					fmt.Fprintf(w, "  %s\t%d queued, %d running\n", d.RepoName, d.Queued, d.Running)
+				}
+				w.Flush()
+			}
 			fmt.Println()
 
 			// Display health status
@@ -1608,7 +2519,7 @@ func statusCmd() *cobra.Command {
 						repoDisplay += " [remote]"
 					}
 					fmt.Fprintf(w, "  %d\t%s\t%s\t%s\t%s\t%s\n",
-						j.ID, shortRef(j.GitRef), repoDisplay, j.Agent, j.Status, elapsed)
+						j.ID, shortRef(j.GitRef), repoDisplay, j.Agent, jobStatusLabel(j), elapsed)
 				}
 				w.Flush()
 			}
@@ -1629,6 +2540,10 @@ func statusCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&byRepo, "by-repo", false, "show queued and running jobs for each repo")
+
+	return cmd
 }
 
 func listCmd() *cobra.Command {
@@ -1638,6 +2553,10 @@ func listCmd() *cobra.Command {
 		limit      int
 		status     string
 		jsonOutput bool
+		unseen     bool
+		verdict    string
+		machine    string
+		localOnly  bool
 	)
 
 	cmd := &cobra.Command{
@@ -1652,8 +2571,22 @@ Examples:
   roborev list --json                 # Output as JSON
   roborev list --branch main          # Jobs for main branch
   roborev list --status done          # Only completed jobs
-  roborev list --limit 5              # Show at most 5 jobs`,
+  roborev list --limit 5              # Show at most 5 jobs
+  roborev list --unseen               # Reviews nobody has read yet
+  roborev list --verdict fail         # Only failing reviews
+  roborev list --local-only           # Hide jobs synced from other machines
+  roborev list --machine <id>         # Only jobs from one machine
+
+Reviews that no human has read yet are shown with a status of
+"done (unseen)"; see 'roborev seen'. The Verdict column is PASS, FAIL,
+or "-" for jobs with no verdict yet (--verdict pending).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if verdict != "" && !storage.IsVerdictFilter(verdict) {
+				return fmt.Errorf("invalid --verdict %q (valid: pass, fail, pending)", verdict)
+			}
+			if machine != "" && localOnly {
+				return fmt.Errorf("cannot use --machine with --local-only")
+			}
 			if err := ensureDaemon(); err != nil {
 				return fmt.Errorf("daemon not running: %w", err)
 			}
@@ -1697,6 +2630,18 @@ Examples:
 			if status != "" {
 				params.Set("status", status)
 			}
+			if unseen {
+				params.Set("seen", "false")
+			}
+			if verdict != "" {
+				params.Set("verdict", verdict)
+			}
+			if machine != "" {
+				params.Set("machine", machine)
+			}
+			if localOnly {
+				params.Set("local_only", "true")
+			}
 			params.Set("limit", strconv.Itoa(limit))
 
 			client := &http.Client{Timeout: 5 * time.Second}
@@ -1707,8 +2652,8 @@ Examples:
 			defer resp.Body.Close()
 
 			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				return fmt.Errorf("daemon returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("daemon returned %s: %s", resp.Status, msg)
 			}
 
 			var jobsResp struct {
@@ -1727,11 +2672,14 @@ Examples:
 
 			if len(jobsResp.Jobs) == 0 {
 				fmt.Println("No jobs found.")
+				if machine != "" {
+					printKnownMachines(client, addr, machine)
+				}
 				return nil
 			}
 
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(w, "ID\tSHA\tRepo\tAgent\tStatus\tTime\n")
+			fmt.Fprintf(w, "ID\tSHA\tRepo\tAgent\tStatus\tVerdict\tTime\tSummary\n")
 			for _, j := range jobsResp.Jobs {
 				elapsed := ""
 				if j.StartedAt != nil {
@@ -1741,8 +2689,20 @@ Examples:
 						elapsed = time.Since(*j.StartedAt).Round(time.Second).String() + "..."
 					}
 				}
-				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
-					j.ID, shortRef(j.GitRef), j.RepoName, j.Agent, j.Status, elapsed)
+				status := jobStatusLabel(j)
+				if j.Seen != nil && !*j.Seen {
+					status += " (unseen)"
+				}
+				verdictLabel := "-"
+				if j.Verdict != nil {
+					verdictLabel = "FAIL"
+					if *j.Verdict == "P" {
+						verdictLabel = "PASS"
+					}
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					j.ID, shortRef(j.GitRef), j.RepoName, j.Agent, status, verdictLabel, elapsed,
+					truncateString(stripControlChars(j.Summary), 60))
 			}
 			w.Flush()
 
@@ -1759,9 +2719,35 @@ Examples:
 	cmd.Flags().IntVar(&limit, "limit", 50, "max number of jobs to return")
 	cmd.Flags().StringVar(&status, "status", "", "filter by status (queued, running, done, failed)")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&unseen, "unseen", false, "only reviews no human has read yet")
+	cmd.Flags().StringVar(&verdict, "verdict", "", "filter by verdict (pass, fail, pending)")
+	cmd.Flags().StringVar(&machine, "machine", "", "only jobs created by this machine ID")
+	cmd.Flags().BoolVar(&localOnly, "local-only", false, "only jobs created on this machine")
 	return cmd
 }
 
+// printKnownMachines lists the daemon's known machine IDs when machine is
+// not one of them, so a mistyped --machine is easy to correct.
+func printKnownMachines(client *http.Client, addr, machine string) {
+	resp, err := client.Get(addr + "/api/status")
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	var status storage.DaemonStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil || len(status.MachineIDs) == 0 || slices.Contains(status.MachineIDs, machine) {
+		return
+	}
+	fmt.Printf("Unknown machine %q. Known machines:\n", machine)
+	for _, id := range status.MachineIDs {
+		label := ""
+		if id == status.MachineID {
+			label = " (this machine)"
+		}
+		fmt.Printf("  %s%s\n", id, label)
+	}
+}
+
 func showCmd() *cobra.Command {
 	var forceJobID bool
 	var showPrompt bool
@@ -1854,6 +2840,9 @@ Examples:
 			if resp.StatusCode == http.StatusNotFound {
 				return fmt.Errorf("no review found for %s", displayRef)
 			}
+			if resp.StatusCode == http.StatusConflict {
+				return ambiguousSHAError(resp, displayRef)
+			}
 
 			var review storage.Review
 			if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
@@ -1868,9 +2857,12 @@ Examples:
 
 			// Avoid redundant "job X (job X, ...)" output
 			if strings.HasPrefix(displayRef, "job ") {
-				fmt.Printf("Review for %s (by %s)\n", displayRef, review.Agent)
+				fmt.Printf("Review for %s (by %s)\n", displayRef, review.ProducedBy())
 			} else {
-				fmt.Printf("Review for %s (job %d, by %s)\n", displayRef, review.JobID, review.Agent)
+				fmt.Printf("Review for %s (job %d, by %s)\n", displayRef, review.JobID, review.ProducedBy())
+			}
+			if review.Job != nil && review.Job.CachedFromJobID != nil {
+				fmt.Printf("Reused from job %d, which reviewed an identical diff (review.dedup)\n", *review.Job.CachedFromJobID)
 			}
 			fmt.Println(strings.Repeat("-", 60))
 			if showPrompt {
@@ -1894,6 +2886,7 @@ func commentCmd() *cobra.Command {
 		commenter  string
 		message    string
 		forceJobID bool
+		replyTo    int64
 	)
 
 	cmd := &cobra.Command{
@@ -1909,7 +2902,8 @@ Examples:
   roborev comment 42 -m "Added missing error handling"
   roborev comment abc123 "Addressed by refactoring"
   roborev comment 42     # Opens editor for message
-  roborev comment --job 1234567 "msg"  # Force numeric arg as job ID`,
+  roborev comment --job 1234567 "msg"  # Force numeric arg as job ID
+  roborev comment 42 --reply-to 7 "Agreed, done"  # Reply to comment 7`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Ensure daemon is running
@@ -2005,6 +2999,12 @@ Examples:
 			} else {
 				reqData["sha"] = sha
 			}
+			if replyTo != 0 {
+				if jobID == 0 {
+					return fmt.Errorf("--reply-to requires a job ID")
+				}
+				reqData["parent_id"] = replyTo
+			}
 
 			reqBody, _ := json.Marshal(reqData)
 
@@ -2016,8 +3016,8 @@ Examples:
 			defer resp.Body.Close()
 
 			if resp.StatusCode != http.StatusCreated {
-				body, _ := io.ReadAll(resp.Body)
-				return fmt.Errorf("failed to add comment: %s", body)
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("failed to add comment: %s", msg)
 			}
 
 			fmt.Println("Comment added successfully")
@@ -2028,6 +3028,7 @@ Examples:
 	cmd.Flags().StringVar(&commenter, "commenter", "", "commenter name (default: $USER)")
 	cmd.Flags().StringVarP(&message, "message", "m", "", "comment message (opens editor if not provided)")
 	cmd.Flags().BoolVar(&forceJobID, "job", false, "force argument to be treated as job ID (not SHA)")
+	cmd.Flags().Int64Var(&replyTo, "reply-to", 0, "reply to the comment with this ID")
 
 	return cmd
 }
@@ -2072,8 +3073,8 @@ func addressCmd() *cobra.Command {
 			defer resp.Body.Close()
 
 			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				return fmt.Errorf("failed to mark review: %s", body)
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("failed to mark review: %s", msg)
 			}
 
 			if addressed {
@@ -2113,6 +3114,9 @@ func findJobForCommit(repoPath, sha string) (*storage.ReviewJob, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusConflict {
+		return nil, ambiguousSHAError(resp, sha)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("query for %s: server returned %s", sha, resp.Status)
 	}
@@ -2140,6 +3144,9 @@ func findJobForCommit(repoPath, sha string) (*storage.ReviewJob, error) {
 	}
 	defer fallbackResp.Body.Close()
 
+	if fallbackResp.StatusCode == http.StatusConflict {
+		return nil, ambiguousSHAError(fallbackResp, sha)
+	}
 	if fallbackResp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("fallback query for %s: server returned %s", sha, fallbackResp.Status)
 	}
@@ -2170,6 +3177,16 @@ func findJobForCommit(repoPath, sha string) (*storage.ReviewJob, error) {
 	return nil, nil
 }
 
+// ambiguousSHAError reports the daemon's 409 for a SHA prefix matching
+// several commits; its message lists the candidates.
+func ambiguousSHAError(resp *http.Response, ref string) error {
+	var errResp daemon.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error.Message == "" {
+		return fmt.Errorf("%s is ambiguous", ref)
+	}
+	return fmt.Errorf("%s (use more characters of the SHA)", errResp.Error.Message)
+}
+
 // waitForReview waits for a review to complete and returns it
 func waitForReview(jobID int64) (*storage.Review, error) {
 	return waitForReviewWithInterval(jobID, pollStartInterval)
@@ -2240,15 +3257,15 @@ func enqueueReview(repoPath, gitRef, agentName string) (int64, error) {
 		"agent":     agentName,
 	})
 
-	resp, err := http.Post(addr+"/api/enqueue", "application/json", bytes.NewReader(reqBody))
+	resp, err := daemon.PostWithRetry(http.DefaultClient, addr+"/api/enqueue", reqBody)
 	if err != nil {
 		return 0, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("enqueue failed: %s", body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return 0, fmt.Errorf("enqueue failed: %s", msg)
 	}
 
 	var job storage.ReviewJob
@@ -2348,8 +3365,8 @@ Examples:
 			defer resp.Body.Close()
 
 			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				return fmt.Errorf("stream failed: %s", body)
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("stream failed: %s", msg)
 			}
 
 			// Stream events - pass through lines directly to preserve all fields
@@ -2939,8 +3956,8 @@ func syncNowCmd() *cobra.Command {
 			}
 
 			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				return fmt.Errorf("sync failed: %s", string(body))
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("sync failed: %s", msg)
 			}
 
 			// Read streaming progress
@@ -3145,7 +4162,11 @@ func versionCmd() *cobra.Command {
 
 func shortRef(ref string) string {
 	// For ranges like "abc123..def456", show as "abc123..def456" (up to 17 chars)
+	// For stash entries, show "stash@{N}" as is
 	// For single SHAs, truncate to 7 chars
+	if git.IsStashRef(ref) {
+		return ref
+	}
 	if strings.Contains(ref, "..") {
 		if len(ref) > 17 {
 			return ref[:17]
@@ -3159,6 +4180,16 @@ func shortRef(ref string) string {
 // Task jobs (no CommitID, no DiffContent) display their GitRef directly (run, analyze, or custom label).
 // Regular review jobs display their GitRef shortened.
 func shortJobRef(job storage.ReviewJob) string {
+	// Range reviews show the range as the user wrote it
+	if job.JobType == storage.JobTypeRange && job.RangeLabel != "" {
+		switch job.RangeCommits {
+		case 0:
+			return job.RangeLabel
+		case 1:
+			return job.RangeLabel + " (1 commit)"
+		}
+		return fmt.Sprintf("%s (%d commits)", job.RangeLabel, job.RangeCommits)
+	}
 	// Task jobs are identified by: no CommitID, no DiffContent
 	// (Note: Prompt field is set for ALL jobs after worker starts, so can't use that)
 	if job.CommitID == nil && job.DiffContent == nil {
@@ -3181,6 +4212,20 @@ func formatAgentLabel(agent string, model string) string {
 	return agent
 }
 
+// reviewAgentLabel is formatAgentLabel for a review's job, naming the
+// fallback agent that wrote it, if any, in place of the job's agent and
+// model.
+func reviewAgentLabel(review *storage.Review) string {
+	if by := review.ProducedBy(); by != review.Agent {
+		return fmt.Sprintf("%s, fallback for %s", by, review.Agent)
+	}
+	model := ""
+	if review.Job != nil {
+		model = review.Job.Model
+	}
+	return formatAgentLabel(review.Agent, model)
+}
+
 // resolveReasoningWithFast returns the effective reasoning value, applying
 // the --fast shorthand only when --reasoning wasn't explicitly set.
 func resolveReasoningWithFast(reasoning string, fast bool, reasoningExplicitlySet bool) string {
@@ -3190,6 +4235,16 @@ func resolveReasoningWithFast(reasoning string, fast bool, reasoningExplicitlySe
 	return reasoning
 }
 
+// skipDuringRebase reports whether the review command should skip while a
+// rebase is in progress. An unreadable global config keeps the default.
+func skipDuringRebase(repoPath string) bool {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		cfg = nil
+	}
+	return config.ResolveSkipDuringRebase(repoPath, cfg)
+}
+
 // autoInstallHooks upgrades outdated hooks and installs
 // companion hooks (e.g. post-rewrite when post-commit
 // exists). It does NOT install hooks from scratch so that
@@ -20,10 +20,12 @@ import (
 	"time"
 
 	"github.com/roborev-dev/roborev/internal/agent"
+	"github.com/roborev-dev/roborev/internal/config"
 	"github.com/roborev-dev/roborev/internal/daemon"
 	"github.com/roborev-dev/roborev/internal/git"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/roborev-dev/roborev/internal/version"
+	"github.com/spf13/cobra"
 )
 
 // ============================================================================
@@ -1055,3 +1057,77 @@ func TestShortJobRef(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyProfileIsolatesProfiles(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("ROBOREV_DATA_DIR", dataDir)
+	t.Setenv(config.ProfileEnvVar, "")
+	oldAddr, oldFlagSet := serverAddr, serverFlagSet
+	t.Cleanup(func() { serverAddr, serverFlagSet = oldAddr, oldFlagSet })
+
+	profiles := map[string]string{"work": "127.0.0.1:7400", "personal": "127.0.0.1:7401"}
+	dbPaths := map[string]string{}
+	for name, addr := range profiles {
+		cfgPath := filepath.Join(config.ProfileDataDir(name), "config.toml")
+		if err := os.MkdirAll(filepath.Dir(cfgPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(cfgPath, []byte(fmt.Sprintf("server_addr = %q\n", addr)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().String("server", "http://127.0.0.1:7373", "")
+		serverAddr = "http://127.0.0.1:7373"
+		if err := applyProfile(cmd, name); err != nil {
+			t.Fatalf("applyProfile(%s): %v", name, err)
+		}
+		if got := config.ActiveProfile(); got != name {
+			t.Errorf("active profile = %q, want %q", got, name)
+		}
+		if serverAddr != "http://"+addr {
+			t.Errorf("profile %s: serverAddr = %q, want %q", name, serverAddr, "http://"+addr)
+		}
+
+		dbPaths[name] = storage.DefaultDBPath()
+		db, err := storage.Open(dbPaths[name])
+		if err != nil {
+			t.Fatalf("open %s db: %v", name, err)
+		}
+		if _, err := db.GetOrCreateRepo(filepath.Join(dataDir, "repo-"+name)); err != nil {
+			t.Fatalf("create repo: %v", err)
+		}
+		repos, err := db.ListRepos()
+		db.Close()
+		if err != nil {
+			t.Fatalf("list repos: %v", err)
+		}
+		if len(repos) != 1 {
+			t.Errorf("profile %s: expected only its own repo, got %d", name, len(repos))
+		}
+	}
+
+	if dbPaths["work"] == dbPaths["personal"] {
+		t.Errorf("profiles share database %s", dbPaths["work"])
+	}
+
+	t.Run("explicit server flag wins", func(t *testing.T) {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().StringVar(&serverAddr, "server", "", "")
+		if err := cmd.Flags().Set("server", "http://127.0.0.1:9999"); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyProfile(cmd, "work"); err != nil {
+			t.Fatalf("applyProfile: %v", err)
+		}
+		if serverAddr != "http://127.0.0.1:9999" {
+			t.Errorf("serverAddr = %q, want explicit --server", serverAddr)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		if err := applyProfile(&cobra.Command{Use: "test"}, "../escape"); err == nil {
+			t.Error("expected error for invalid profile name")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func patchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "patch <job_id>",
+		Short: "Print a fix job's patch",
+		Long: `Print the patch a fix job produced, exactly as stored, so it can be
+saved or piped to git apply. The TUI patch view's w key writes the same
+bytes to roborev-fix-<job_id>.patch.
+
+Examples:
+  roborev patch 42 > fix.patch
+  roborev patch 42 | git apply --check
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || jobID <= 0 {
+				return fmt.Errorf("invalid job ID: %s", args[0])
+			}
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			patch, err := fetchJobPatch(ctx, getDaemonAddr(), jobID)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(cmd.OutOrStdout(), patch)
+			return err
+		},
+	}
+}
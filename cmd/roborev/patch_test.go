@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPatchCmd(t *testing.T) {
+	// No trailing newline: the output must match the stored bytes exactly
+	patch := "--- a/greet.txt\n+++ b/greet.txt\n@@ -1 +1 @@\n-hello\n+hello, world"
+	_, cleanup := setupMockDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/job/patch" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("job_id") {
+		case "42":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(patch))
+		default:
+			http.Error(w, "no patch available for this job", http.StatusNotFound)
+		}
+	}))
+	defer cleanup()
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := patchCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("42")
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if out != patch {
+		t.Errorf("output = %q, want %q", out, patch)
+	}
+
+	if _, err := run("43"); err == nil || !strings.Contains(err.Error(), "job 43 has no patch") {
+		t.Errorf("expected no-patch error, got %v", err)
+	}
+	if _, err := run("abc"); err == nil || !strings.Contains(err.Error(), "invalid job ID") {
+		t.Errorf("expected invalid job ID error, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+func pauseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause",
+		Short: "Stop the daemon from starting new jobs",
+		Long: `Stop the daemon from starting new jobs without stopping the daemon.
+
+Running jobs finish normally, and new reviews keep queueing until
+'roborev resume'. The pause is remembered across daemon restarts.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var result daemon.PauseQueueResponse
+			if err := postQueueControl("/api/pause", &result); err != nil {
+				return fmt.Errorf("pause failed: %w", err)
+			}
+			if result.Paused {
+				fmt.Fprintln(cmd.OutOrStdout(), "Queue paused; running jobs will finish")
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), "Queue was already paused")
+			}
+			return nil
+		},
+	}
+}
+
+func resumeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume",
+		Short: "Let the daemon start new jobs again after a pause",
+		Long: `Let the daemon start new jobs again after 'roborev pause', or after
+an agent authentication failure paused the queue (pause_on_auth_failure).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runResume(cmd.OutOrStdout())
+		},
+	}
+}
+
+// runResume resumes the daemon's queue and reports whether it was paused.
+func runResume(out io.Writer) error {
+	var result daemon.ResumeQueueResponse
+	if err := postQueueControl("/api/resume", &result); err != nil {
+		return fmt.Errorf("resume failed: %w", err)
+	}
+	if result.Resumed {
+		fmt.Fprintln(out, "Queue resumed")
+	} else {
+		fmt.Fprintln(out, "Queue was not paused")
+	}
+	return nil
+}
+
+// postQueueControl sends a bodiless POST to a daemon queue endpoint and
+// decodes the response into result.
+func postQueueControl(path string, result any) error {
+	if err := ensureDaemon(); err != nil {
+		return fmt.Errorf("daemon not running: %w", err)
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(getDaemonAddr()+path, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("%s", msg)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPauseResumeCmds(t *testing.T) {
+	paused := false
+	_, cleanup := setupMockDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("%s %s, want POST", r.Method, r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/api/pause":
+			respondJSON(w, http.StatusOK, map[string]bool{"paused": !paused})
+			paused = true
+		case "/api/resume":
+			respondJSON(w, http.StatusOK, map[string]bool{"resumed": paused})
+			paused = false
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer cleanup()
+
+	for _, step := range []struct {
+		name string
+		cmd  func() *cobra.Command
+		want string
+	}{
+		{"pause", pauseCmd, "Queue paused"},
+		{"pause again", pauseCmd, "Queue was already paused"},
+		{"resume", resumeCmd, "Queue resumed"},
+		{"resume again", resumeCmd, "Queue was not paused"},
+	} {
+		var out bytes.Buffer
+		cmd := step.cmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs(nil)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if !strings.Contains(out.String(), step.want) {
+			t.Errorf("%s: output %q, want %q", step.name, out.String(), step.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func pruneCmd() *cobra.Command {
+	var (
+		olderThan   string
+		keepLast    int
+		keepApplied bool
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete old finished jobs and their reviews",
+		Long: `Delete finished jobs (done, failed, canceled, applied, rebased) that
+finished before the cutoff, or that fall outside the newest --keep-last
+finished jobs of their repo, along with their reviews and comments. Queued
+and running jobs are never deleted.
+
+Pruning runs in a single transaction and is safe while the daemon is
+running. Run 'roborev db vacuum' afterwards to shrink the database file.
+
+Examples:
+  roborev prune --older-than 30d
+  roborev prune --older-than 90d --keep-applied
+  roborev prune --keep-last 100
+  roborev prune --older-than 90d --keep-last 100 --dry-run
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keepLast < 0 {
+				return fmt.Errorf("--keep-last must be positive")
+			}
+			if olderThan == "" && keepLast <= 0 {
+				return fmt.Errorf("--older-than or --keep-last is required")
+			}
+			opts := storage.PruneOpts{KeepLast: keepLast, DryRun: dryRun}
+			now := time.Now()
+			var cutoff time.Time
+			if olderThan != "" {
+				var err error
+				cutoff, err = parseSince(olderThan, now)
+				if err != nil {
+					return fmt.Errorf("invalid --older-than value %q (use a duration like 72h or 30d, or a date like 2006-01-02)", olderThan)
+				}
+				opts.OlderThan = now.Sub(cutoff)
+			}
+
+			if keepApplied {
+				opts.KeepStatuses = append(opts.KeepStatuses, storage.JobStatusApplied)
+			}
+
+			dbPath := storage.DefaultDBPath()
+			if dbPath == "" {
+				return fmt.Errorf("cannot determine database path")
+			}
+			db, err := storage.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			result, err := db.PruneReviews(opts)
+			if err != nil {
+				return fmt.Errorf("prune jobs: %w", err)
+			}
+
+			verb := "Pruned"
+			if dryRun {
+				verb = "Would prune"
+			}
+			var limits []string
+			if olderThan != "" {
+				limits = append(limits, "finished before "+cutoff.Format("2006-01-02 15:04"))
+			}
+			if keepLast > 0 {
+				limits = append(limits, fmt.Sprintf("beyond the newest %d per repo", keepLast))
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %d jobs, %d reviews, %d comments %s\n",
+				verb, result.Jobs, result.Reviews, result.Comments, strings.Join(limits, " or "))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "delete jobs finished before this age (e.g. 72h, 30d) or date")
+	cmd.Flags().IntVar(&keepLast, "keep-last", 0, "delete finished jobs beyond the newest N per repo")
+	cmd.Flags().BoolVar(&keepApplied, "keep-applied", false, "keep jobs whose fixes were applied")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would be deleted without deleting anything")
+
+	return cmd
+}
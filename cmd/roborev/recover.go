@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+func recoverCmd() *cobra.Command {
+	var fail bool
+
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Requeue jobs stuck in running",
+		Long: `Find jobs marked running that no daemon worker is processing (for
+example after a crash) and requeue them. Jobs that a worker is actively
+running are left alone.
+
+The daemon already requeues running jobs when it starts; use this when
+jobs were orphaned without a restart.
+
+Examples:
+  roborev recover         # Requeue stuck jobs
+  roborev recover --fail  # Mark stuck jobs failed instead
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+
+			body, err := json.Marshal(daemon.RecoverJobsRequest{Fail: fail})
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: 30 * time.Second}
+			resp, err := client.Post(getDaemonAddr()+"/api/jobs/recover", "application/json", bytes.NewReader(body))
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("recover failed: %s", msg)
+			}
+
+			var result daemon.RecoverJobsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+
+			if result.Recovered == 0 {
+				fmt.Printf("No stuck jobs found (%d running)\n", result.Active)
+				return nil
+			}
+			fmt.Printf("Recovered %d stuck job(s): %s (%d still running)\n",
+				result.Recovered, result.Action, result.Active)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fail, "fail", false, "mark stuck jobs failed instead of requeuing them")
+
+	return cmd
+}
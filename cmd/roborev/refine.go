@@ -413,7 +413,7 @@ func runRefine(ctx RunContext, opts refineOptions) error {
 					fmt.Printf("Warning: review failed: %v\n", err)
 					continue // Loop back, will re-check
 				}
-				verdict := storage.ParseVerdict(review.Output)
+				verdict := review.Verdict()
 				if verdict == "F" && !review.Addressed {
 					currentFailedReview = review
 				} else if verdict == "P" {
@@ -466,7 +466,7 @@ func runRefine(ctx RunContext, opts refineOptions) error {
 					return fmt.Errorf("branch review failed: %w", err)
 				}
 
-				verdict := storage.ParseVerdict(review.Output)
+				verdict := review.Verdict()
 				if verdict == "P" {
 					fmt.Println("\nAll reviews passed! Branch is ready.")
 					return nil
@@ -633,7 +633,7 @@ func runRefine(ctx RunContext, opts refineOptions) error {
 			continue
 		}
 
-		verdict := storage.ParseVerdict(review.Output)
+		verdict := review.Verdict()
 		if verdict == "P" {
 			fmt.Println("New commit passed review!")
 			if err := client.MarkReviewAddressed(review.JobID); err != nil {
@@ -972,7 +972,7 @@ func findFailedReviewForBranch(client daemon.Client, commits []string, skip map[
 			continue
 		}
 
-		verdict := storage.ParseVerdict(review.Output)
+		verdict := review.Verdict()
 		if verdict == "F" {
 			return review, nil
 		}
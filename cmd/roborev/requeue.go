@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func requeueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "requeue <job_id>",
+		Short: "Put a failed or canceled job back in the queue",
+		Long: `Reset a failed or canceled job back to queued so it runs again, e.g.
+after an agent failed on a network blip. The job keeps its ID and its
+attempt count is incremented.
+
+Done and applied jobs cannot be requeued; use 'roborev retry' to run a
+finished review again as a new job.
+
+Examples:
+  roborev requeue 42
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || jobID <= 0 {
+				return fmt.Errorf("invalid job ID: %s", args[0])
+			}
+
+			dbPath := storage.DefaultDBPath()
+			if dbPath == "" {
+				return fmt.Errorf("cannot determine database path")
+			}
+			db, err := storage.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			err = checkRequeueable(db, jobID)
+			db.Close()
+			if err != nil {
+				return err
+			}
+
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+
+			body, err := json.Marshal(daemon.RequeueJobRequest{JobID: jobID})
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: 30 * time.Second}
+			resp, err := client.Post(getDaemonAddr()+"/api/job/requeue", "application/json", bytes.NewReader(body))
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("requeue failed: %s", msg)
+			}
+
+			var job storage.ReviewJob
+			if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			fmt.Printf("Requeued job %d (attempt %d)\n", job.ID, job.Attempts+1)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// checkRequeueable verifies that jobID exists and is failed or canceled,
+// so the daemon is only contacted for jobs it will accept.
+func checkRequeueable(db *storage.DB, jobID int64) error {
+	job, err := db.GetJobByID(jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("job %d not found", jobID)
+	}
+	if err != nil {
+		return fmt.Errorf("look up job %d: %w", jobID, err)
+	}
+	if !job.Status.Requeueable() {
+		return fmt.Errorf("job %d is %s; only failed or canceled jobs can be requeued", jobID, job.Status)
+	}
+	return nil
+}
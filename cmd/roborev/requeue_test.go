@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestCheckRequeueable(t *testing.T) {
+	db, err := storage.Open(filepath.Join(t.TempDir(), "reviews.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	repo, err := db.GetOrCreateRepo(t.TempDir())
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+	enqueue := func(sha string) *storage.ReviewJob {
+		t.Helper()
+		commit, err := db.GetOrCreateCommit(repo.ID, sha, "Author", "Subject", time.Now())
+		if err != nil {
+			t.Fatalf("GetOrCreateCommit: %v", err)
+		}
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		return job
+	}
+
+	canceled := enqueue("canceled")
+	if err := db.CancelJob(canceled.ID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	if err := checkRequeueable(db, canceled.ID); err != nil {
+		t.Errorf("canceled job: unexpected error %v", err)
+	}
+
+	done := enqueue("done")
+	if _, err := db.ClaimJob("worker-1"); err != nil {
+		t.Fatalf("ClaimJob: %v", err)
+	}
+	if err := db.CompleteJob(done.ID, "test", "prompt", "output"); err != nil {
+		t.Fatalf("CompleteJob: %v", err)
+	}
+	if err := checkRequeueable(db, done.ID); err == nil || !strings.Contains(err.Error(), "is done") {
+		t.Errorf("done job: expected 'is done' error, got %v", err)
+	}
+
+	if err := checkRequeueable(db, 99999); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing job: expected not found error, got %v", err)
+	}
+}
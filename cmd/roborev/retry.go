@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func retryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry <job_id>",
+		Short: "Enqueue a new run of a finished job",
+		Long: `Enqueue a new job that repeats a finished, failed, or canceled job with
+the same repo, commit, agent, model, and reasoning, e.g. after an agent
+crash. The original job and its review are kept; the new job records
+which job it retries.
+
+Jobs that are still queued or running cannot be retried.
+
+Examples:
+  roborev retry 42
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || jobID <= 0 {
+				return fmt.Errorf("invalid job ID: %s", args[0])
+			}
+
+			dbPath := storage.DefaultDBPath()
+			if dbPath == "" {
+				return fmt.Errorf("cannot determine database path")
+			}
+			db, err := storage.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			err = checkRetryable(db, jobID)
+			db.Close()
+			if err != nil {
+				return err
+			}
+
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+
+			body, err := json.Marshal(daemon.RetryJobRequest{JobID: jobID})
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: 30 * time.Second}
+			resp, err := client.Post(getDaemonAddr()+"/api/job/retry", "application/json", bytes.NewReader(body))
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusCreated {
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("retry failed: %s", msg)
+			}
+
+			var job storage.ReviewJob
+			if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			fmt.Printf("Enqueued job %d (retry of job %d)\n", job.ID, jobID)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// rerunReview enqueues a new review of jobID's ref via the daemon. With
+// wait, it shows the new review followed by the earlier job's verdict.
+func rerunReview(cmd *cobra.Command, jobID int64, wait, quiet bool) error {
+	if err := ensureDaemon(); err != nil {
+		return fmt.Errorf("daemon not running: %w", err)
+	}
+	addr := getDaemonAddr()
+
+	body, err := json.Marshal(daemon.RerunReviewRequest{JobID: jobID})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(addr+"/api/review/rerun", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("rerun failed: %s", msg)
+	}
+
+	var job storage.ReviewJob
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !quiet {
+		cmd.Printf("Enqueued job %d for %s (rerun of job %d, agent: %s)\n", job.ID, shortJobRef(job), jobID, job.Agent)
+	}
+	if !wait {
+		return nil
+	}
+
+	err = waitForJob(context.Background(), cmd, addr, job.ID, quiet)
+	if !quiet {
+		if prev, fetchErr := fetchJob(context.Background(), addr, jobID); fetchErr == nil && prev.Verdict != nil {
+			label := "FAIL"
+			if *prev.Verdict == "P" {
+				label = "PASS"
+			}
+			cmd.Printf("\nPrevious review (job %d): %s\n", jobID, label)
+		}
+	}
+	if _, isExitErr := err.(*exitError); isExitErr {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+	}
+	return err
+}
+
+// checkRetryable verifies that jobID exists and has finished, so the
+// daemon is only contacted for jobs it will accept.
+func checkRetryable(db *storage.DB, jobID int64) error {
+	job, err := db.GetJobByID(jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("job %d not found", jobID)
+	}
+	if err != nil {
+		return fmt.Errorf("look up job %d: %w", jobID, err)
+	}
+	if !job.Status.Retryable() {
+		return fmt.Errorf("job %d is %s; only done, failed, or canceled jobs can be retried", jobID, job.Status)
+	}
+	return nil
+}
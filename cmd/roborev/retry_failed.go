@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/spf13/cobra"
+)
+
+func retryFailedCmd() *cobra.Command {
+	var (
+		repoPath string
+		since    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retry-failed",
+		Short: "Requeue all failed jobs",
+		Long: `Requeue every failed job so it runs again, e.g. after fixing a broken
+agent install or a transient outage. Each requeued job's retry count is
+incremented; jobs that have already used all their retries are left failed.
+
+Examples:
+  roborev retry-failed                   # All repos
+  roborev retry-failed --repo .          # Only the current repo
+  roborev retry-failed --since 2h        # Only jobs that failed in the last 2 hours
+  roborev retry-failed --since 2025-06-01
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var req daemon.RetryFailedRequest
+			if repoPath != "" {
+				root, err := git.GetMainRepoRoot(repoPath)
+				if err != nil {
+					return fmt.Errorf("not a git repository: %s", repoPath)
+				}
+				req.RepoPath = root
+			}
+			if since != "" {
+				t, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				req.Since = &t
+			}
+
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+
+			body, err := json.Marshal(req)
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: 30 * time.Second}
+			resp, err := client.Post(getDaemonAddr()+"/api/jobs/retry-failed", "application/json", bytes.NewReader(body))
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("retry failed: %s", msg)
+			}
+
+			var result struct {
+				Requeued int `json:"requeued"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+
+			if result.Requeued == 0 {
+				fmt.Println("No failed jobs to retry")
+				return nil
+			}
+			fmt.Printf("Requeued %d failed job(s)\n", result.Requeued)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repoPath, "repo", "", "only retry jobs for this repo path")
+	cmd.Flags().StringVar(&since, "since", "", "only retry jobs that failed within this duration (e.g. 2h, 7d) or since a date (YYYY-MM-DD or RFC3339)")
+
+	return cmd
+}
+
+// parseSince parses a --since value as a duration before now (Go durations
+// plus a "d" suffix for days), a YYYY-MM-DD date in local time, or an
+// RFC3339 timestamp.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q (use a duration like 2h or 7d, or a date like 2006-01-02)", s)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestCheckRetryable(t *testing.T) {
+	db, err := storage.Open(filepath.Join(t.TempDir(), "reviews.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	repo, err := db.GetOrCreateRepo(t.TempDir())
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+	enqueue := func(sha string) *storage.ReviewJob {
+		t.Helper()
+		commit, err := db.GetOrCreateCommit(repo.ID, sha, "Author", "Subject", time.Now())
+		if err != nil {
+			t.Fatalf("GetOrCreateCommit: %v", err)
+		}
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		return job
+	}
+
+	canceled := enqueue("canceled")
+	if err := db.CancelJob(canceled.ID); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	if err := checkRetryable(db, canceled.ID); err != nil {
+		t.Errorf("canceled job: unexpected error %v", err)
+	}
+
+	queued := enqueue("queued")
+	if err := checkRetryable(db, queued.ID); err == nil || !strings.Contains(err.Error(), "is queued") {
+		t.Errorf("queued job: expected 'is queued' error, got %v", err)
+	}
+
+	if err := checkRetryable(db, 99999); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing job: expected not found error, got %v", err)
+	}
+}
@@ -4,11 +4,17 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -144,6 +150,95 @@ func TestEnqueueSkippedBranch(t *testing.T) {
 	})
 }
 
+func TestReviewSkipDuringRebase(t *testing.T) {
+	enqueued := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		enqueued++
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 1, Agent: "test"})
+	})
+
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	repo := newTestGitRepo(t)
+	repo.CommitFile("file.txt", "content", "initial commit")
+
+	// Simulate an interactive rebase in progress
+	if err := os.MkdirAll(filepath.Join(repo.Dir, ".git", "rebase-merge"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("skipped by default", func(t *testing.T) {
+		enqueued = 0
+		var stdout bytes.Buffer
+		cmd := reviewCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetArgs([]string{"--repo", repo.Dir})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("review during rebase should exit 0, got: %v", err)
+		}
+		if enqueued != 0 {
+			t.Errorf("expected no enqueue during rebase, got %d", enqueued)
+		}
+		if !strings.Contains(stdout.String(), "rebase in progress") {
+			t.Errorf("expected skip message, got: %q", stdout.String())
+		}
+	})
+
+	t.Run("enqueued when skip_during_rebase is false", func(t *testing.T) {
+		enqueued = 0
+		if err := os.WriteFile(filepath.Join(repo.Dir, ".roborev.toml"), []byte("skip_during_rebase = false\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Remove(filepath.Join(repo.Dir, ".roborev.toml")) })
+		cmd := reviewCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--quiet"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("review failed: %v", err)
+		}
+		if enqueued != 1 {
+			t.Errorf("expected 1 enqueue, got %d", enqueued)
+		}
+	})
+}
+
+func TestReviewWaitReportsReusedReview(t *testing.T) {
+	repo := newTestGitRepo(t)
+	repo.CommitFile("file.txt", "content", "initial commit")
+
+	sourceJob := int64(7)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "queued"})
+	})
+	mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		job := storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "done"}
+		respondJSON(w, http.StatusOK, map[string]any{"jobs": []storage.ReviewJob{job}, "has_more": false})
+	})
+	mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, storage.Review{ID: 1, JobID: 1, Agent: "test", Output: "No issues found.",
+			Job: &storage.ReviewJob{ID: 1, CachedFromJobID: &sourceJob}})
+	})
+
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+	setupFastPolling(t)
+
+	var stdout bytes.Buffer
+	cmd := reviewCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--repo", repo.Dir, "--wait"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("review --wait: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Reused from job 7") {
+		t.Errorf("expected the reuse to be reported, got:\n%s", stdout.String())
+	}
+}
+
 func TestWaitQuietVerdictExitCode(t *testing.T) {
 	setupFastPolling(t)
 
@@ -226,6 +321,89 @@ func TestWaitQuietVerdictExitCode(t *testing.T) {
 			t.Errorf("expected no stderr in quiet mode, got: %q", stderr.String())
 		}
 	})
+
+	t.Run("stored pass verdict exits 0 despite findings", func(t *testing.T) {
+		pass := "P"
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+			job := storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "queued"}
+			respondJSON(w, http.StatusCreated, job)
+		})
+		mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+			job := storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "done"}
+			respondJSON(w, http.StatusOK, map[string]any{"jobs": []storage.ReviewJob{job}, "has_more": false})
+		})
+		mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+			// The repo's fail_threshold made this medium finding pass
+			respondJSON(w, http.StatusOK, storage.Review{ID: 1, JobID: 1, Agent: "test", Output: "- Medium: unchecked error from Close",
+				Job: &storage.ReviewJob{ID: 1, Verdict: &pass}})
+		})
+
+		_, cleanup := setupMockDaemon(t, mux)
+		defer cleanup()
+
+		cmd := reviewCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--wait", "--quiet"})
+		if err := cmd.Execute(); err != nil {
+			t.Errorf("expected exit 0 for a stored pass verdict, got error: %v", err)
+		}
+	})
+
+	t.Run("failing review with every finding resolved exits 0", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+			job := storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "queued"}
+			respondJSON(w, http.StatusCreated, job)
+		})
+		mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+			job := storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "done"}
+			respondJSON(w, http.StatusOK, map[string]any{"jobs": []storage.ReviewJob{job}, "has_more": false})
+		})
+		mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+			fail := "F"
+			respondJSON(w, http.StatusOK, storage.Review{ID: 1, JobID: 1, Agent: "test", Output: "- Medium: unchecked error from Close",
+				Job: &storage.ReviewJob{ID: 1, Verdict: &fail}, ResolvedFindings: []int{0}})
+		})
+
+		_, cleanup := setupMockDaemon(t, mux)
+		defer cleanup()
+
+		cmd := reviewCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--wait", "--quiet"})
+		if err := cmd.Execute(); err != nil {
+			t.Errorf("expected exit 0 once every finding is resolved, got error: %v", err)
+		}
+	})
+
+	t.Run("failing review of a reverted commit exits 0", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+			job := storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "queued"}
+			respondJSON(w, http.StatusCreated, job)
+		})
+		mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+			job := storage.ReviewJob{ID: 1, GitRef: "abc123", Agent: "test", Status: "done"}
+			respondJSON(w, http.StatusOK, map[string]any{"jobs": []storage.ReviewJob{job}, "has_more": false})
+		})
+		mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+			respondJSON(w, http.StatusOK, storage.Review{ID: 1, JobID: 1, Agent: "test", Output: "Found 1 issue:\n1. Bug in foo.go", Addressed: true, RevertedBy: "def4567890"})
+		})
+
+		_, cleanup := setupMockDaemon(t, mux)
+		defer cleanup()
+
+		cmd := reviewCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--wait", "--quiet"})
+		if err := cmd.Execute(); err != nil {
+			t.Errorf("expected exit 0 for reverted commit, got error: %v", err)
+		}
+	})
 }
 
 func TestWaitForJobUnknownStatus(t *testing.T) {
@@ -467,6 +645,298 @@ func TestReviewSinceFlag(t *testing.T) {
 	})
 }
 
+func TestReviewStashFlag(t *testing.T) {
+	var received map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		gitRef, _ := received["git_ref"].(string)
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 1, GitRef: gitRef, Agent: "test"})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	repo := newTestGitRepo(t)
+	repo.CommitFile("file.txt", "initial\n", "initial")
+	repo.WriteFiles(map[string]string{"file.txt": "stashed work\n"})
+	repo.Run("stash")
+
+	t.Run("enqueues stash diff", func(t *testing.T) {
+		cmd := reviewCmd()
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--stash", "stash@{0}"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("review --stash failed: %v", err)
+		}
+		if received["git_ref"] != "stash@{0}" {
+			t.Errorf("git_ref = %v, want stash@{0}", received["git_ref"])
+		}
+		diff, _ := received["diff_content"].(string)
+		if !strings.Contains(diff, "+stashed work") {
+			t.Errorf("diff_content missing stashed change: %q", diff)
+		}
+	})
+
+	t.Run("invalid stash fails before enqueue", func(t *testing.T) {
+		received = nil
+		cmd := reviewCmd()
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--stash", "stash@{3}"})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "invalid --stash") {
+			t.Fatalf("expected invalid --stash error, got %v", err)
+		}
+		if received != nil {
+			t.Error("invalid stash should not be enqueued")
+		}
+	})
+
+	t.Run("stash and dirty are mutually exclusive", func(t *testing.T) {
+		cmd := reviewCmd()
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--stash", "stash@{0}", "--dirty"})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "--stash cannot be combined") {
+			t.Fatalf("expected mutual exclusion error, got %v", err)
+		}
+	})
+}
+
+func TestReviewRerunFlag(t *testing.T) {
+	setupFastPolling(t)
+	prevVerdict := "F"
+	var rerunOf int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/review/rerun", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			JobID int64 `json:"job_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		rerunOf = req.JobID
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 43, GitRef: "abc1234", Agent: "test", SupersedesJobID: &req.JobID})
+	})
+	mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		job := storage.ReviewJob{ID: 43, Agent: "test", Status: storage.JobStatusDone}
+		if r.URL.Query().Get("id") == "42" {
+			job = storage.ReviewJob{ID: 42, Agent: "test", Status: storage.JobStatusDone, Verdict: &prevVerdict}
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"jobs": []storage.ReviewJob{job}})
+	})
+	mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, storage.Review{JobID: 43, Agent: "test", Output: "No issues found."})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	t.Run("reruns and compares verdicts", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := reviewCmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--rerun", "42", "--wait"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("review --rerun failed: %v", err)
+		}
+		if rerunOf != 42 {
+			t.Errorf("rerun job_id = %d, want 42", rerunOf)
+		}
+		for _, want := range []string{"Enqueued job 43 for abc1234 (rerun of job 42", "No issues found.", "Previous review (job 42): FAIL"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("rejects review options", func(t *testing.T) {
+		cmd := reviewCmd()
+		cmd.SetArgs([]string{"--rerun", "42", "--dirty"})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "--rerun cannot be combined with --dirty") {
+			t.Fatalf("expected --dirty conflict, got %v", err)
+		}
+	})
+}
+
+func TestReviewStdinFlag(t *testing.T) {
+	var received map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		gitRef, _ := received["git_ref"].(string)
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 1, GitRef: gitRef, Agent: "test"})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	repo := newTestGitRepo(t)
+	baseSHA := repo.CommitFile("file.txt", "initial\n", "initial")
+	repo.CommitFile("file.txt", "later\n", "later")
+	edited := "diff --git a/file.txt b/file.txt\n-initial\n+edited by hand\n"
+
+	t.Run("enqueues stdin diff against base", func(t *testing.T) {
+		cmd := reviewCmd()
+		cmd.SetIn(strings.NewReader(edited))
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--stdin", "--base", "HEAD~1"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("review --stdin failed: %v", err)
+		}
+		if received["git_ref"] != baseSHA {
+			t.Errorf("git_ref = %v, want base %s", received["git_ref"], baseSHA)
+		}
+		if received["diff_content"] != edited {
+			t.Errorf("diff_content = %q, want the stdin diff", received["diff_content"])
+		}
+	})
+
+	for _, tt := range []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantErr string
+	}{
+		{"requires base", []string{"--stdin"}, edited, "--stdin requires --base"},
+		{"invalid base", []string{"--stdin", "--base", "nope"}, edited, "invalid --base"},
+		{"empty stdin", []string{"--stdin", "--base", "HEAD"}, "  \n", "no diff on stdin"},
+		{"exclusive with dirty", []string{"--stdin", "--base", "HEAD", "--dirty"}, edited, "--stdin cannot be combined"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			cmd := reviewCmd()
+			cmd.SilenceUsage = true
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			cmd.SetArgs(append([]string{"--repo", repo.Dir}, tt.args...))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if received != nil {
+				t.Error("nothing should be enqueued")
+			}
+		})
+	}
+}
+
+func TestReviewRangeArgEnqueuesEachCommit(t *testing.T) {
+	type enqueueReq struct {
+		GitRef     string `json:"git_ref"`
+		RangeLabel string `json:"range_label"`
+	}
+	var received []enqueueReq
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		var req enqueueReq
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req)
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: int64(len(received)), GitRef: req.GitRef, Agent: "test"})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	repo := newTestGitRepo(t)
+	base := repo.CommitFile("base.txt", "base", "base")
+	second := repo.CommitFile("second.txt", "second", "second")
+	third := repo.CommitFile("third.txt", "third", "third")
+
+	t.Run("one job per commit", func(t *testing.T) {
+		received = nil
+		var out bytes.Buffer
+		cmd := reviewCmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--each-commit", base + "..HEAD"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("review range failed: %v", err)
+		}
+		if len(received) != 2 {
+			t.Fatalf("expected 2 enqueued jobs, got %d", len(received))
+		}
+		for i, want := range []string{second, third} {
+			if received[i].GitRef != want {
+				t.Errorf("job %d: git_ref = %s, want %s", i, received[i].GitRef, want)
+			}
+			if received[i].RangeLabel != base+"..HEAD" {
+				t.Errorf("job %d: range_label = %q, want %q", i, received[i].RangeLabel, base+"..HEAD")
+			}
+		}
+		if !strings.Contains(out.String(), "Enqueued job 1") || !strings.Contains(out.String(), "Enqueued job 2") {
+			t.Errorf("expected job IDs in output, got %q", out.String())
+		}
+	})
+
+	t.Run("empty range exits cleanly", func(t *testing.T) {
+		received = nil
+		var out bytes.Buffer
+		cmd := reviewCmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--repo", repo.Dir, "HEAD..HEAD"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("empty range should not fail: %v", err)
+		}
+		if len(received) != 0 {
+			t.Errorf("expected no jobs, got %d", len(received))
+		}
+		if !strings.Contains(out.String(), "No commits in HEAD..HEAD") {
+			t.Errorf("expected empty range message, got %q", out.String())
+		}
+	})
+}
+
+func TestReviewRangeArgEnqueuesOneJob(t *testing.T) {
+	type enqueueReq struct {
+		GitRef     string `json:"git_ref"`
+		RangeLabel string `json:"range_label"`
+	}
+	var received []enqueueReq
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		var req enqueueReq
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req)
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{
+			ID: 7, GitRef: "aaa..bbb", JobType: storage.JobTypeRange,
+			RangeLabel: req.RangeLabel, RangeCommits: 2, Agent: "test",
+		})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	repo := newTestGitRepo(t)
+	base := repo.CommitFile("base.txt", "base", "base")
+	repo.CommitFile("second.txt", "second", "second")
+	repo.CommitFile("third.txt", "third", "third")
+
+	t.Run("combined diff in one job", func(t *testing.T) {
+		received = nil
+		var out bytes.Buffer
+		cmd := reviewCmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--repo", repo.Dir, base + "..HEAD"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("review range failed: %v", err)
+		}
+		if len(received) != 1 {
+			t.Fatalf("expected 1 enqueued job, got %d", len(received))
+		}
+		if received[0].GitRef != base+"..HEAD" || received[0].RangeLabel != base+"..HEAD" {
+			t.Errorf("git_ref = %q, range_label = %q, want both %q", received[0].GitRef, received[0].RangeLabel, base+"..HEAD")
+		}
+		if !strings.Contains(out.String(), "Enqueued job 7 for "+base+"..HEAD (2 commits)") {
+			t.Errorf("expected range and commit count in output, got %q", out.String())
+		}
+	})
+
+	t.Run("each-commit requires a range", func(t *testing.T) {
+		received = nil
+		cmd := reviewCmd()
+		cmd.SilenceUsage = true
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--each-commit", "HEAD"})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "--each-commit requires a single range") {
+			t.Fatalf("expected --each-commit range error, got %v", err)
+		}
+		if len(received) != 0 {
+			t.Errorf("expected no jobs, got %d", len(received))
+		}
+	})
+}
+
 func TestReviewBranchFlag(t *testing.T) {
 	t.Run("branch and dirty are mutually exclusive", func(t *testing.T) {
 		mux := http.NewServeMux()
@@ -585,6 +1055,103 @@ func TestReviewBranchFlag(t *testing.T) {
 	})
 }
 
+func TestReviewAllBranches(t *testing.T) {
+	repo := newTestGitRepo(t)
+	repo.Run("symbolic-ref", "HEAD", "refs/heads/main")
+	mainSHA := repo.CommitFile("file.txt", "content", "initial")
+	repo.Run("checkout", "-b", "feat/reviewed")
+	reviewedSHA := repo.CommitFile("r.txt", "r", "reviewed")
+	repo.Run("checkout", "-b", "feat/new", "main")
+	newSHA := repo.CommitFile("n.txt", "n", "new")
+	repo.Run("checkout", "-b", "other", "main")
+	otherSHA := repo.CommitFile("o.txt", "o", "other")
+	repo.Run("branch", "main-alias", "main")
+
+	setup := func(t *testing.T) *[]string {
+		t.Helper()
+		var enqueued []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+			var jobs []storage.ReviewJob
+			switch r.URL.Query().Get("git_ref") {
+			case reviewedSHA:
+				jobs = append(jobs, storage.ReviewJob{ID: 7, GitRef: reviewedSHA, Status: storage.JobStatusDone, RepoPath: repo.Dir})
+			case mainSHA:
+				// A failed job does not count as reviewed
+				jobs = append(jobs, storage.ReviewJob{ID: 8, GitRef: mainSHA, Status: storage.JobStatusFailed, RepoPath: repo.Dir})
+			}
+			respondJSON(w, http.StatusOK, map[string]any{"jobs": jobs})
+		})
+		mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				GitRef string `json:"git_ref"`
+				Branch string `json:"branch"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			enqueued = append(enqueued, req.Branch+"@"+req.GitRef)
+			respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: int64(100 + len(enqueued)), GitRef: req.GitRef, Agent: "test"})
+		})
+		_, cleanup := setupMockDaemon(t, mux)
+		t.Cleanup(cleanup)
+		return &enqueued
+	}
+
+	t.Run("enqueues unreviewed tips once each", func(t *testing.T) {
+		enqueued := setup(t)
+		var stdout bytes.Buffer
+		cmd := reviewCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--all-branches"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Branches sort by name; main-alias shares main's tip and is skipped.
+		want := []string{"feat/new@" + newSHA, "main@" + mainSHA, "other@" + otherSHA}
+		if strings.Join(*enqueued, ",") != strings.Join(want, ",") {
+			t.Errorf("enqueued = %v, want %v", *enqueued, want)
+		}
+		out := stdout.String()
+		if !strings.Contains(out, "feat/reviewed") || !strings.Contains(out, "already reviewed in job 7") {
+			t.Errorf("expected skip message for reviewed tip, got:\n%s", out)
+		}
+		if !strings.Contains(out, "Enqueued job 101") || !strings.Contains(out, "Enqueued 3 review(s)") {
+			t.Errorf("expected job IDs in output, got:\n%s", out)
+		}
+	})
+
+	t.Run("pattern restricts branches", func(t *testing.T) {
+		enqueued := setup(t)
+		cmd := reviewCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--all-branches", "--pattern", "feat/*"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"feat/new@" + newSHA}; strings.Join(*enqueued, ",") != strings.Join(want, ",") {
+			t.Errorf("enqueued = %v, want %v", *enqueued, want)
+		}
+	})
+
+	t.Run("pattern requires all-branches", func(t *testing.T) {
+		setup(t)
+		cmd := reviewCmd()
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--pattern", "feat/*"})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--pattern requires --all-branches") {
+			t.Errorf("expected --pattern error, got %v", err)
+		}
+	})
+
+	t.Run("all-branches rejects dirty", func(t *testing.T) {
+		setup(t)
+		cmd := reviewCmd()
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--all-branches", "--dirty"})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--all-branches cannot be combined") {
+			t.Errorf("expected combination error, got %v", err)
+		}
+	})
+}
+
 func TestReviewFastFlag(t *testing.T) {
 	t.Run("fast flag sets reasoning to fast", func(t *testing.T) {
 		reasoningChan := make(chan string, 1)
@@ -661,6 +1228,130 @@ func TestReviewFastFlag(t *testing.T) {
 	})
 }
 
+func TestReviewProfileFlag(t *testing.T) {
+	type enqueueFields struct {
+		Agent     string `json:"agent"`
+		Model     string `json:"model"`
+		Reasoning string `json:"reasoning"`
+	}
+	reqs := make(chan enqueueFields, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		var req enqueueFields
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reqs <- req
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 1, Agent: req.Agent})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	fakeAgentCommands(t, "codex", "claude")
+
+	repo := newTestGitRepo(t)
+	repo.CommitFile(".roborev.toml", "[profiles.security]\nagent = \"claude-code\"\nmodel = \"opus\"\nreasoning = \"standard\"\n", "add profiles")
+
+	tests := []struct {
+		name string
+		args []string
+		want enqueueFields
+	}{
+		{"profile fills agent options", nil, enqueueFields{"claude-code", "opus", "standard"}},
+		{"explicit flags win", []string{"--agent", "codex", "--fast"}, enqueueFields{"codex", "opus", "fast"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := reviewCmd()
+			cmd.SetArgs(append([]string{"--repo", repo.Dir, "--review-profile", "security"}, tt.args...))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			select {
+			case got := <-reqs:
+				if got != tt.want {
+					t.Errorf("enqueued %+v, want %+v", got, tt.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for enqueue request")
+			}
+		})
+	}
+
+	t.Run("unknown profile", func(t *testing.T) {
+		cmd := reviewCmd()
+		cmd.SilenceUsage = true
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--review-profile", "missing"})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), `no profile "missing"`) {
+			t.Fatalf("expected unknown profile error, got %v", err)
+		}
+		if len(reqs) != 0 {
+			t.Error("nothing should be enqueued for an unknown profile")
+		}
+	})
+}
+
+func TestReviewChecksAgentAvailable(t *testing.T) {
+	var enqueued atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		enqueued.Add(1)
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 1, Agent: "codex"})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	// Only git and a stub claude are on PATH, so codex is missing
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	stub := filepath.Join(dir, "claude")
+	if err := os.WriteFile(stub, []byte("#!/bin/sh\necho stub\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+filepath.Dir(gitPath))
+
+	repo := newTestGitRepo(t)
+	repo.CommitFile("a.txt", "a", "initial")
+
+	t.Run("explicit agent must be installed", func(t *testing.T) {
+		cmd := reviewCmd()
+		cmd.SilenceUsage = true
+		cmd.SetArgs([]string{"--repo", repo.Dir, "--agent", "codex"})
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), `"codex" was not found in PATH`) {
+			t.Fatalf("expected missing agent error, got %v", err)
+		}
+		if enqueued.Load() != 0 {
+			t.Error("nothing should be enqueued when the agent is missing")
+		}
+	})
+
+	t.Run("configured agent only warns", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("stub agent is a shell script")
+		}
+		cmd := reviewCmd()
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{"--repo", repo.Dir})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(stderr.String(), "Warning: agent codex is not installed") || !strings.Contains(stderr.String(), "fall back to claude-code") {
+			t.Errorf("expected fallback warning, got %q", stderr.String())
+		}
+		if enqueued.Load() != 1 {
+			t.Errorf("enqueued %d jobs, want 1", enqueued.Load())
+		}
+	})
+}
+
 func TestReviewInvalidArgsNoSideEffects(t *testing.T) {
 	mux := http.NewServeMux()
 	// Catch-all handler to fail the test if any request is made
@@ -695,3 +1386,192 @@ func TestReviewInvalidArgsNoSideEffects(t *testing.T) {
 		}
 	}
 }
+
+func TestReviewInvalidAgentOptionsFailEarly(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("daemon should not be contacted on invalid agent options")
+		w.WriteHeader(http.StatusOK)
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	repo := newTestGitRepo(t)
+	repo.CommitFile("a.txt", "a", "initial")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"unknown agent", []string{"--agent", "nope"}, `unknown agent "nope"`},
+		{"invalid reasoning", []string{"--reasoning", "extreme"}, "invalid --reasoning"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := reviewCmd()
+			cmd.SilenceUsage = true
+			cmd.SetArgs(append([]string{"--repo", repo.Dir}, tt.args...))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWaitForJobUsesEventStream(t *testing.T) {
+	// Without events, waitForJob would sleep far past the test timeout.
+	origStart, origMax, origFallback := pollStartInterval, pollMaxInterval, eventFallbackInterval
+	pollStartInterval, pollMaxInterval, eventFallbackInterval = time.Hour, time.Hour, time.Hour
+	t.Cleanup(func() {
+		pollStartInterval, pollMaxInterval, eventFallbackInterval = origStart, origMax, origFallback
+	})
+
+	var mu sync.Mutex
+	finished := false
+	polled := make(chan struct{}, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("job_id"); got != "1" {
+			t.Errorf("expected job_id=1, got %q", got)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ": connected\n\n")
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-polled:
+		case <-r.Context().Done():
+			return
+		}
+		mu.Lock()
+		finished = true
+		mu.Unlock()
+		fmt.Fprint(w, "event: review.completed\ndata: {\"job_id\":1}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		status := storage.JobStatusQueued
+		if finished {
+			status = storage.JobStatusDone
+		}
+		mu.Unlock()
+		select {
+		case polled <- struct{}{}:
+		default:
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"jobs": []storage.ReviewJob{{ID: 1, GitRef: "abc123", Agent: "test", Status: status}},
+		})
+	})
+	mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, storage.Review{ID: 1, JobID: 1, Agent: "test", Output: "No issues found."})
+	})
+	ts, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd := reviewCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := waitForJob(ctx, cmd, ts.URL, 1, false); err != nil {
+		t.Fatalf("waitForJob: %v", err)
+	}
+	if !strings.Contains(out.String(), "No issues found.") {
+		t.Errorf("expected review output, got %q", out.String())
+	}
+}
+
+func TestReviewWaitEnqueueFailure(t *testing.T) {
+	setupFastPolling(t)
+
+	repo := newTestGitRepo(t)
+	repo.CommitFile("file.txt", "content", "initial commit")
+
+	for _, tc := range []struct {
+		name    string
+		status  int
+		body    any
+		wantErr string
+	}{
+		{"daemon rejects enqueue", http.StatusBadRequest, map[string]string{"error": "unknown agent"}, "review failed"},
+		{"response without a job", http.StatusCreated, map[string]string{}, "invalid enqueue response"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var polled atomic.Bool
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+				respondJSON(w, tc.status, tc.body)
+			})
+			mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+				polled.Store(true)
+				respondJSON(w, http.StatusOK, map[string]any{"jobs": []storage.ReviewJob{}})
+			})
+			_, cleanup := setupMockDaemon(t, mux)
+			defer cleanup()
+
+			var stdout bytes.Buffer
+			cmd := reviewCmd()
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs([]string{"--repo", repo.Dir, "--wait", "--quiet"})
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+			if polled.Load() {
+				t.Error("expected no wait after a failed enqueue")
+			}
+		})
+	}
+}
+
+func TestReviewWaitWithAgentAndModel(t *testing.T) {
+	setupFastPolling(t)
+
+	repo := newTestGitRepo(t)
+	repo.CommitFile("file.txt", "content", "initial commit")
+
+	var enqueued map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/enqueue", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&enqueued); err != nil {
+			t.Errorf("decode enqueue: %v", err)
+		}
+		respondJSON(w, http.StatusCreated, storage.ReviewJob{ID: 7, GitRef: "abc123", Agent: "claude-code", Status: "queued"})
+	})
+	mux.HandleFunc("/api/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("id"); got != "7" {
+			t.Errorf("waited on job %q, want 7", got)
+		}
+		job := storage.ReviewJob{ID: 7, GitRef: "abc123", Agent: "claude-code", Status: "done"}
+		respondJSON(w, http.StatusOK, map[string]any{"jobs": []storage.ReviewJob{job}, "has_more": false})
+	})
+	mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, storage.Review{ID: 1, JobID: 7, Agent: "claude-code", Output: "No issues found."})
+	})
+	_, cleanup := setupMockDaemon(t, mux)
+	defer cleanup()
+
+	var stdout bytes.Buffer
+	cmd := reviewCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"--repo", repo.Dir, "--wait", "--agent", "claude-code", "--model", "opus"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("review --wait: %v", err)
+	}
+	if enqueued["agent"] != "claude-code" || enqueued["model"] != "opus" {
+		t.Errorf("enqueue agent=%v model=%v, want claude-code and opus", enqueued["agent"], enqueued["model"])
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "Enqueued job 7") || !strings.Contains(out, "No issues found.") {
+		t.Errorf("expected enqueue and review output, got: %q", out)
+	}
+}
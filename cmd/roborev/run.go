@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +11,7 @@ import (
 	"time"
 
 	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/daemon"
 	"github.com/roborev-dev/roborev/internal/git"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/spf13/cobra"
@@ -153,21 +153,21 @@ func runPrompt(cmd *cobra.Command, args []string, agentName, modelStr, reasoning
 		"agentic":       agentic,
 	})
 
-	resp, err := http.Post(serverAddr+"/api/enqueue", "application/json", bytes.NewReader(reqBody))
+	resp, err := daemon.PostWithRetry(http.DefaultClient, serverAddr+"/api/enqueue", reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to connect to daemon: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("enqueue failed: %s", readErrorBody(resp.Body, resp.Status))
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("enqueue failed: %s", body)
-	}
-
 	var job storage.ReviewJob
 	if err := json.Unmarshal(body, &job); err != nil {
 		return fmt.Errorf("failed to parse response: %w", err)
@@ -215,9 +215,9 @@ func waitForPromptJob(cmd *cobra.Command, serverAddr string, jobID int64, quiet
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
+			msg := readErrorBody(resp.Body, resp.Status)
 			resp.Body.Close()
-			return fmt.Errorf("server error checking job status (%d): %s", resp.StatusCode, body)
+			return fmt.Errorf("server error checking job status (%d): %s", resp.StatusCode, msg)
 		}
 
 		var jobsResp struct {
@@ -293,8 +293,8 @@ func showPromptResult(cmd *cobra.Command, addr string, jobID int64, quiet bool,
 		return fmt.Errorf("no result found for job %d", jobID)
 	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("server error fetching result (%d): %s", resp.StatusCode, body)
+		msg := readErrorBody(resp.Body, resp.Status)
+		return fmt.Errorf("server error fetching result (%d): %s", resp.StatusCode, msg)
 	}
 
 	var review storage.Review
@@ -307,7 +307,7 @@ func showPromptResult(cmd *cobra.Command, addr string, jobID int64, quiet bool,
 		if doneMsg != "" {
 			cmd.Print(doneMsg)
 		}
-		cmd.Printf("Result (by %s)\n", review.Agent)
+		cmd.Printf("Result (by %s)\n", review.ProducedBy())
 		cmd.Println(strings.Repeat("-", 60))
 		cmd.Println(review.Output)
 	}
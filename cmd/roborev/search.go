@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func searchCmd() *cobra.Command {
+	var (
+		limit  int
+		asJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search past review output",
+		Long: `Search the output of past reviews, most recently finished first.
+
+Every word in the query must appear in a review for it to match. Words
+are matched whole and case-insensitively; punctuation is not treated as
+search syntax.
+
+Examples:
+  roborev search loadConfig
+  roborev search "race condition" --limit 5
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := strings.Join(args, " ")
+			if strings.TrimSpace(query) == "" {
+				return storage.ErrEmptySearchQuery
+			}
+
+			dbPath := storage.DefaultDBPath()
+			if dbPath == "" {
+				return fmt.Errorf("cannot determine database path")
+			}
+			db, err := storage.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			results, err := db.SearchReviews(query, limit)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				for i := range results {
+					results[i].Snippet = highlightSnippet(results[i].Snippet, "", "")
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(results)
+			}
+			printSearchResults(out, results, colorEnabled(out))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 50, "maximum number of results")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output results as JSON")
+
+	return cmd
+}
+
+// printSearchResults writes one entry per review: the job ID, ref, and
+// commit subject, then the matching snippet. Matches are bold when color
+// is enabled and wrapped in ** otherwise.
+func printSearchResults(w io.Writer, results []storage.Review, color bool) {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No matching reviews")
+		return
+	}
+	start, end := "**", "**"
+	if color {
+		start, end = "\x1b[1m", "\x1b[0m"
+	}
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		header := fmt.Sprintf("#%d", r.JobID)
+		if r.Job != nil {
+			header += "  " + shortRef(r.Job.GitRef)
+			if r.Job.CommitSubject != "" {
+				header += "  " + r.Job.CommitSubject
+			}
+		}
+		fmt.Fprintln(w, header)
+		fmt.Fprintf(w, "    %s\n", highlightSnippet(r.Snippet, start, end))
+	}
+}
+
+// highlightSnippet collapses a search snippet onto one line and replaces
+// its match markers with start and end.
+func highlightSnippet(snippet, start, end string) string {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	return strings.NewReplacer(storage.SnippetMatchStart, start, storage.SnippetMatchEnd, end).Replace(snippet)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestPrintSearchResults(t *testing.T) {
+	snippet := "...the " + storage.SnippetMatchStart + "loadConfig" + storage.SnippetMatchEnd + "\nhelper ignores errors"
+	results := []storage.Review{
+		{JobID: 7, Snippet: snippet, Job: &storage.ReviewJob{GitRef: "abcdef1234567", CommitSubject: "Add config loader"}},
+		{JobID: 9, Snippet: snippet, Job: &storage.ReviewJob{GitRef: "abcdef1..1234567"}},
+	}
+
+	var buf bytes.Buffer
+	printSearchResults(&buf, results, false)
+	want := "#7  abcdef1  Add config loader\n" +
+		"    ...the **loadConfig** helper ignores errors\n" +
+		"\n" +
+		"#9  abcdef1..1234567\n" +
+		"    ...the **loadConfig** helper ignores errors\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	printSearchResults(&buf, nil, false)
+	if buf.String() != "No matching reviews\n" {
+		t.Errorf("empty results = %q", buf.String())
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func seenCmd() *cobra.Command {
+	var seenBy string
+
+	cmd := &cobra.Command{
+		Use:   "seen <job_id>",
+		Short: "Record that a human has read a review",
+		Long: `Record that you have read a job's review, for example to keep an audit
+trail that every review was looked at by a person. Opening a review in
+the TUI records this automatically.
+
+Only the first reader is kept; marking a review seen again leaves the
+original reader and time unchanged. Use 'roborev list --unseen' to find
+reviews nobody has read yet.
+
+Examples:
+  roborev seen 42
+  roborev seen 42 --by alice
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || jobID <= 0 {
+				return fmt.Errorf("invalid job ID: %s", args[0])
+			}
+			if seenBy == "" {
+				seenBy = os.Getenv("USER")
+				if seenBy == "" {
+					seenBy = "anonymous"
+				}
+			}
+
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+
+			body, err := json.Marshal(daemon.MarkReviewSeenRequest{JobID: jobID, SeenBy: seenBy})
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: 30 * time.Second}
+			resp, err := client.Post(getDaemonAddr()+"/api/review/seen", "application/json", bytes.NewReader(body))
+			if err != nil {
+				return fmt.Errorf("failed to connect to daemon: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode == http.StatusNotFound {
+				return fmt.Errorf("no review found for job %d", jobID)
+			}
+			if resp.StatusCode != http.StatusOK {
+				msg := readErrorBody(resp.Body, resp.Status)
+				return fmt.Errorf("mark seen failed: %s", msg)
+			}
+
+			var review storage.Review
+			if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			out := cmd.OutOrStdout()
+			if review.SeenBy != seenBy && review.SeenAt != nil {
+				fmt.Fprintf(out, "Job %d was already seen by %s at %s\n",
+					jobID, review.SeenBy, review.SeenAt.Local().Format("2006-01-02 15:04"))
+				return nil
+			}
+			fmt.Fprintf(out, "Job %d marked as seen by %s\n", jobID, seenBy)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&seenBy, "by", "", "name to record as the reader (default: $USER)")
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestSeenCmd(t *testing.T) {
+	firstSeen := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		args       []string
+		status     int
+		review     storage.Review
+		wantSeenBy string
+		wantOut    string
+		wantErr    string
+	}{
+		{
+			name:       "marks review seen",
+			args:       []string{"42", "--by", "alice"},
+			status:     http.StatusOK,
+			review:     storage.Review{JobID: 42, SeenBy: "alice", SeenAt: &firstSeen},
+			wantSeenBy: "alice",
+			wantOut:    "Job 42 marked as seen by alice",
+		},
+		{
+			name:       "reports earlier reader",
+			args:       []string{"42", "--by", "alice"},
+			status:     http.StatusOK,
+			review:     storage.Review{JobID: 42, SeenBy: "bob", SeenAt: &firstSeen},
+			wantSeenBy: "alice",
+			wantOut:    "Job 42 was already seen by bob",
+		},
+		{
+			name:       "missing review",
+			args:       []string{"42", "--by", "alice"},
+			status:     http.StatusNotFound,
+			wantSeenBy: "alice",
+			wantErr:    "no review found for job 42",
+		},
+		{
+			name:    "invalid job ID",
+			args:    []string{"abc"},
+			wantErr: "invalid job ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got daemon.MarkReviewSeenRequest
+			_, cleanup := setupMockDaemon(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/review/seen" || r.Method != http.MethodPost {
+					http.NotFound(w, r)
+					return
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				respondJSON(w, tt.status, tt.review)
+			}))
+			defer cleanup()
+
+			var out bytes.Buffer
+			cmd := seenCmd()
+			cmd.SetOut(&out)
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.SeenBy != tt.wantSeenBy {
+				t.Errorf("seen_by = %q, want %q", got.SeenBy, tt.wantSeenBy)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output %q does not contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func statsCmd() *cobra.Command {
+	var (
+		repoPath string
+		since    string
+		asJSON   bool
+		timing   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show review totals, pass rates, timing, and cost per agent",
+		Long: `Show finished job totals and review durations per agent, followed by
+review pass/fail counts and pass rates per agent and model, and token
+usage and cost per agent and per repo.
+
+Durations are measured from when a worker started a job to when it
+finished, over completed reviews. Jobs missing either timestamp are
+counted in totals but left out of timing. Pass rates cover completed
+reviews only; task and fix jobs have no verdict. Token usage and cost
+are recorded for agents that report them (codex, gemini, claude-code,
+cursor); reviews from other agents or older versions count as untracked.
+
+With --timing, show only the p50/p90/p99 durations of all completed
+jobs, which is useful for tuning job_timeout_minutes. With fewer than
+three timed jobs every percentile is the longest duration.
+
+Examples:
+  roborev stats
+  roborev stats --repo . --since 7d
+  roborev stats --json
+  roborev stats --timing --repo .
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var opts storage.StatsOptions
+			if repoPath != "" {
+				root, err := git.GetMainRepoRoot(repoPath)
+				if err != nil {
+					return fmt.Errorf("not a git repository: %s", repoPath)
+				}
+				opts.RepoPath = root
+			}
+			if since != "" {
+				t, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				opts.Since = t
+			}
+
+			dbPath := storage.DefaultDBPath()
+			if dbPath == "" {
+				return fmt.Errorf("cannot determine database path")
+			}
+			db, err := storage.Open(dbPath)
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			defer db.Close()
+
+			if timing {
+				if since != "" {
+					return fmt.Errorf("--since cannot be used with --timing")
+				}
+				return runTimingStats(cmd.OutOrStdout(), db, opts.RepoPath, asJSON)
+			}
+
+			stats, err := db.AggregateStats(opts)
+			if err != nil {
+				return fmt.Errorf("aggregate stats: %w", err)
+			}
+			reviews, err := db.GetReviewStats(opts)
+			if err != nil {
+				return fmt.Errorf("review stats: %w", err)
+			}
+			usage, err := db.UsageStats(opts.Since)
+			if err != nil {
+				return fmt.Errorf("usage stats: %w", err)
+			}
+			if opts.RepoPath != "" {
+				usage = slices.DeleteFunc(usage, func(u storage.UsageRow) bool {
+					return u.RepoPath != opts.RepoPath
+				})
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					*storage.AggregateStats
+					Reviews *storage.ReviewStats `json:"reviews"`
+					Usage   []storage.UsageRow   `json:"usage"`
+				}{stats, reviews, usage})
+			}
+			printStats(out, stats)
+			if reviews.Total > 0 {
+				fmt.Fprintln(out)
+				printReviewStats(out, reviews)
+			}
+			if hasUsage(usage) {
+				fmt.Fprintln(out)
+				printUsageStats(out, usage)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repoPath, "repo", "", "only include jobs for this repo path")
+	cmd.Flags().StringVar(&since, "since", "", "only include jobs finished within this duration (e.g. 24h, 7d) or since a date")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&timing, "timing", false, "show only p50/p90/p99 review durations")
+
+	return cmd
+}
+
+// runTimingStats prints duration percentiles for repoPath, or for all repos
+// when repoPath is empty.
+func runTimingStats(out io.Writer, db *storage.DB, repoPath string, asJSON bool) error {
+	var repoID int64
+	if repoPath != "" {
+		repo, err := db.GetRepoByPath(repoPath)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("no reviews recorded for %s", repoPath)
+			}
+			return fmt.Errorf("look up repo: %w", err)
+		}
+		repoID = repo.ID
+	}
+
+	p, err := db.GetDurationStats(repoID)
+	if err != nil {
+		return fmt.Errorf("duration stats: %w", err)
+	}
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	}
+	printDurationPercentiles(out, p)
+	return nil
+}
+
+// printDurationPercentiles renders p50/p90/p99 review durations.
+func printDurationPercentiles(out io.Writer, p *storage.DurationPercentiles) {
+	if p.Count == 0 {
+		fmt.Fprintln(out, "No timed reviews")
+		return
+	}
+	round := func(v time.Duration) string {
+		return v.Round(time.Second).String()
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "REVIEWS\tP50\tP90\tP99\n")
+	fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", p.Count, round(p.P50), round(p.P90), round(p.P99))
+	w.Flush()
+}
+
+// printStats renders stats as a table with one row per agent.
+func printStats(out io.Writer, stats *storage.AggregateStats) {
+	if stats.Total == 0 {
+		fmt.Fprintln(out, "No finished jobs")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "AGENT\tJOBS\tDONE\tFAILED\tAVG\tP50\tP95\n")
+	for _, a := range stats.Agents {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", a.Agent, a.Total, a.Done, a.Failed, formatTiming(a.Timing))
+	}
+	fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t%s\n", stats.Total, stats.Done, stats.Failed, formatTiming(stats.Timing))
+	w.Flush()
+}
+
+// printReviewStats renders pass/fail counts as a table with one row per
+// agent and model.
+func printReviewStats(out io.Writer, stats *storage.ReviewStats) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "AGENT\tMODEL\tREVIEWS\tPASS\tFAIL\tPASS RATE\n")
+	for _, m := range stats.Models {
+		model := m.Model
+		if model == "" {
+			model = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1f%%\n", m.Agent, model, m.Total, m.Passed, m.Failed, m.PassRate())
+	}
+	fmt.Fprintf(w, "TOTAL\t\t%d\t%d\t%d\t%.1f%%\n", stats.Total, stats.Passed, stats.Failed, stats.PassRate())
+	w.Flush()
+}
+
+// hasUsage reports whether any review in rows has recorded token usage.
+func hasUsage(rows []storage.UsageRow) bool {
+	for _, u := range rows {
+		if u.Reported > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// sumUsage merges rows that share the same key, keeping first-seen order.
+func sumUsage(rows []storage.UsageRow, key func(storage.UsageRow) string) []storage.UsageRow {
+	var merged []storage.UsageRow
+	index := make(map[string]int)
+	for _, u := range rows {
+		k := key(u)
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(merged)
+			merged = append(merged, storage.UsageRow{Agent: u.Agent, RepoPath: u.RepoPath, RepoName: u.RepoName})
+			i = len(merged) - 1
+		}
+		addUsage(&merged[i], u)
+	}
+	return merged
+}
+
+// addUsage adds u's counts and totals to dst.
+func addUsage(dst *storage.UsageRow, u storage.UsageRow) {
+	dst.Reviews += u.Reviews
+	dst.Reported += u.Reported
+	dst.InputTokens += u.InputTokens
+	dst.OutputTokens += u.OutputTokens
+	dst.CostUSD += u.CostUSD
+}
+
+// printUsageStats renders token usage and cost totals per agent, then per
+// repo.
+func printUsageStats(out io.Writer, rows []storage.UsageRow) {
+	var total storage.UsageRow
+	for _, u := range rows {
+		addUsage(&total, u)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "AGENT\tREVIEWS\tTRACKED\tINPUT\tOUTPUT\tCOST\n")
+	for _, u := range sumUsage(rows, func(u storage.UsageRow) string { return u.Agent }) {
+		fmt.Fprintf(w, "%s\t%s\n", u.Agent, formatUsage(u))
+	}
+	fmt.Fprintf(w, "TOTAL\t%s\n", formatUsage(total))
+	w.Flush()
+
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "REPO\tREVIEWS\tTRACKED\tINPUT\tOUTPUT\tCOST\n")
+	for _, u := range sumUsage(rows, func(u storage.UsageRow) string { return u.RepoPath }) {
+		fmt.Fprintf(w, "%s\t%s\n", u.RepoName, formatUsage(u))
+	}
+	w.Flush()
+}
+
+// formatUsage renders review counts, token totals, and cost as
+// tab-separated columns.
+func formatUsage(u storage.UsageRow) string {
+	return fmt.Sprintf("%d\t%d\t%d\t%d\t$%.2f", u.Reviews, u.Reported, u.InputTokens, u.OutputTokens, u.CostUSD)
+}
+
+// formatTiming renders avg/p50/p95 as tab-separated columns, or dashes when
+// no jobs were timed.
+func formatTiming(d storage.DurationStats) string {
+	if d.Count == 0 {
+		return "-\t-\t-"
+	}
+	round := func(v time.Duration) string {
+		return v.Round(time.Second).String()
+	}
+	return round(d.Avg) + "\t" + round(d.P50) + "\t" + round(d.P95)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestPrintStats(t *testing.T) {
+	var buf bytes.Buffer
+	printStats(&buf, &storage.AggregateStats{
+		Total: 3, Done: 2, Failed: 1,
+		Timing: storage.DurationStats{Count: 1, Avg: 90 * time.Second, P50: 90 * time.Second, P95: 90 * time.Second},
+		Agents: []storage.AgentStats{
+			{Agent: "codex", Total: 2, Done: 1, Failed: 1,
+				Timing: storage.DurationStats{Count: 1, Avg: 90 * time.Second, P50: 90 * time.Second, P95: 90 * time.Second}},
+			{Agent: "gemini", Total: 1, Done: 1},
+		},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, 2 agents, and total; got:\n%s", buf.String())
+	}
+	if f := strings.Fields(lines[1]); strings.Join(f, " ") != "codex 2 1 1 1m30s 1m30s 1m30s" {
+		t.Errorf("codex row = %q", lines[1])
+	}
+	if f := strings.Fields(lines[2]); strings.Join(f, " ") != "gemini 1 1 0 - - -" {
+		t.Errorf("untimed row = %q", lines[2])
+	}
+
+	buf.Reset()
+	printStats(&buf, &storage.AggregateStats{})
+	if !strings.Contains(buf.String(), "No finished jobs") {
+		t.Errorf("expected empty message, got %q", buf.String())
+	}
+}
+
+func TestPrintDurationPercentiles(t *testing.T) {
+	var buf bytes.Buffer
+	printDurationPercentiles(&buf, &storage.DurationPercentiles{
+		Count: 12, P50: 45 * time.Second, P90: 2 * time.Minute, P99: 5*time.Minute + 400*time.Millisecond,
+	})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and row; got:\n%s", buf.String())
+	}
+	if f := strings.Join(strings.Fields(lines[1]), " "); f != "12 45s 2m0s 5m0s" {
+		t.Errorf("row = %q", lines[1])
+	}
+
+	buf.Reset()
+	printDurationPercentiles(&buf, &storage.DurationPercentiles{})
+	if !strings.Contains(buf.String(), "No timed reviews") {
+		t.Errorf("expected empty message, got %q", buf.String())
+	}
+}
+
+func TestPrintReviewStats(t *testing.T) {
+	var buf bytes.Buffer
+	printReviewStats(&buf, &storage.ReviewStats{
+		VerdictCounts: storage.VerdictCounts{Total: 4, Passed: 3, Failed: 1},
+		Models: []storage.ModelReviewStats{
+			{Agent: "codex", VerdictCounts: storage.VerdictCounts{Total: 1, Passed: 1}},
+			{Agent: "codex", Model: "o3", VerdictCounts: storage.VerdictCounts{Total: 3, Passed: 2, Failed: 1}},
+		},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		"AGENT MODEL REVIEWS PASS FAIL PASS RATE",
+		"codex - 1 1 0 100.0%",
+		"codex o3 3 2 1 66.7%",
+		"TOTAL 4 3 1 75.0%",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got:\n%s", len(want), buf.String())
+	}
+	for i := range want {
+		if got := strings.Join(strings.Fields(lines[i]), " "); got != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestPrintUsageStats(t *testing.T) {
+	var buf bytes.Buffer
+	printUsageStats(&buf, []storage.UsageRow{
+		{Agent: "claude-code", RepoPath: "/src/api", RepoName: "api", Reviews: 2, Reported: 2, InputTokens: 3000, OutputTokens: 400, CostUSD: 0.25},
+		{Agent: "codex", RepoPath: "/src/api", RepoName: "api", Reviews: 3, Reported: 1, InputTokens: 1000, OutputTokens: 100},
+		{Agent: "codex", RepoPath: "/src/web", RepoName: "web", Reviews: 1, Reported: 1, InputTokens: 500, OutputTokens: 50},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		"AGENT REVIEWS TRACKED INPUT OUTPUT COST",
+		"claude-code 2 2 3000 400 $0.25",
+		"codex 4 2 1500 150 $0.00",
+		"TOTAL 6 4 4500 550 $0.25",
+		"",
+		"REPO REVIEWS TRACKED INPUT OUTPUT COST",
+		"api 5 3 4000 500 $0.25",
+		"web 1 1 500 50 $0.00",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got:\n%s", len(want), buf.String())
+	}
+	for i := range want {
+		if got := strings.Join(strings.Fields(lines[i]), " "); got != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	gansi "github.com/charmbracelet/glamour/ansi"
 	"github.com/charmbracelet/glamour/styles"
 	"github.com/charmbracelet/lipgloss"
+	xansi "github.com/charmbracelet/x/ansi"
 	"github.com/muesli/termenv"
 	"golang.org/x/term"
 )
@@ -51,6 +52,9 @@ type streamFormatter struct {
 	isTTY bool
 	width int // terminal width; 0 = no wrapping
 
+	// noColor strips ANSI styling from TTY output (--no-color, NO_COLOR)
+	noColor bool
+
 	glamourStyle gansi.StyleConfig // detected once at init
 
 	writeErr    error // first write error encountered during formatting
@@ -75,6 +79,7 @@ func newStreamFormatter(w io.Writer, isTTY bool) *streamFormatter {
 	if isTTY {
 		f.glamourStyle = sfGlamourStyle()
 		f.width = sfTerminalWidth(w)
+		f.noColor = colorDisabled()
 	}
 	return f
 }
@@ -564,6 +569,10 @@ func (f *streamFormatter) writef(format string, args ...any) {
 	if f.writeErr != nil || f.w == nil {
 		return
 	}
+	if f.noColor {
+		_, f.writeErr = io.WriteString(f.w, xansi.Strip(fmt.Sprintf(format, args...)))
+		return
+	}
 	_, f.writeErr = fmt.Fprintf(f.w, format, args...)
 }
 
@@ -635,9 +644,9 @@ func writerIsTerminal(w io.Writer) bool {
 }
 
 // printMarkdownOrPlain renders text as glamour-styled markdown when
-// writing to a TTY, or prints it as-is otherwise.
+// writing to a TTY with color enabled, or prints it as-is otherwise.
 func printMarkdownOrPlain(w io.Writer, text string) {
-	if !writerIsTerminal(w) {
+	if !colorEnabled(w) {
 		fmt.Fprintln(w, text)
 		return
 	}
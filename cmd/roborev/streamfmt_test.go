@@ -766,3 +766,50 @@ func TestStreamFormatter_SanitizesGeminiText(t *testing.T) {
 	fix.assertContains(t, "safe")
 	fix.assertNotContains(t, "title")
 }
+
+func TestStreamFormatter_NoColor(t *testing.T) {
+	text := eventAssistantText("**Summary**: call `Close()` here")
+	tool := eventAssistantToolUse("Read", map[string]any{"file_path": "main.go"})
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+	}{
+		{
+			name: "flag",
+			setup: func(t *testing.T) {
+				noColor = true
+				t.Cleanup(func() { noColor = false })
+			},
+		},
+		{
+			name:  "NO_COLOR env",
+			setup: func(t *testing.T) { t.Setenv("NO_COLOR", "1") },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup(t)
+			fix := newFixture(true)
+			fix.writeLine(tool)
+			fix.writeLine(text)
+			if raw := fix.buf.String(); strings.Contains(raw, "\x1b[") {
+				t.Errorf("expected no escape codes, got %q", raw)
+			}
+			fix.assertContains(t, "main.go")
+			fix.assertContains(t, "Summary")
+		})
+	}
+}
+
+func TestPrintMarkdownOrPlain_Piped(t *testing.T) {
+	var buf bytes.Buffer
+	printMarkdownOrPlain(&buf, "## Findings\n\n- **High**: unchecked error")
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no escape codes when piped, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "**High**") {
+		t.Errorf("expected raw markdown when piped, got %q", buf.String())
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// verdictTrailerKey is the git trailer recording a review's verdict.
+const verdictTrailerKey = "Roborev-Verdict"
+
+// trailerLinePattern matches a git trailer line ("Token: value").
+var trailerLinePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*:\s`)
+
+func trailerCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "trailer <job_id>",
+		Short: "Record a review verdict as a commit trailer",
+		Long: `Amend the reviewed commit's message to append a trailer with the
+review verdict, e.g.:
+
+  Roborev-Verdict: PASS
+
+The commit must be HEAD of the current repository and must not have been
+pushed to any remote; roborev refuses to rewrite other history. Staged
+changes are not included in the amended commit. Running it again is a
+no-op if the trailer is already up to date.
+
+Examples:
+  roborev trailer 123            # Amend HEAD with the verdict for job 123
+  roborev trailer 123 --dry-run  # Show the resulting message only
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || jobID <= 0 {
+				return fmt.Errorf("invalid job_id: %s", args[0])
+			}
+
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get working directory: %w", err)
+			}
+			repoRoot, err := git.GetRepoRoot(workDir)
+			if err != nil {
+				return fmt.Errorf("not in a git repository: %w", err)
+			}
+
+			if err := ensureDaemon(); err != nil {
+				return fmt.Errorf("daemon not running: %w", err)
+			}
+			return runTrailer(cmd, getDaemonAddr(), repoRoot, jobID, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the resulting commit message without amending")
+
+	return cmd
+}
+
+func runTrailer(cmd *cobra.Command, addr, repoRoot string, jobID int64, dryRun bool) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	job, err := fetchJob(ctx, addr, jobID)
+	if err != nil {
+		return fmt.Errorf("fetch job: %w", err)
+	}
+	if !job.HasViewableOutput() {
+		return fmt.Errorf("job %d has no completed review (status: %s)", jobID, job.Status)
+	}
+	if job.IsTaskJob() || job.IsDirtyJob() || job.IsFixJob() || git.IsRange(job.GitRef) {
+		return fmt.Errorf("job %d is not a single-commit review", jobID)
+	}
+
+	review, err := fetchReview(ctx, addr, jobID)
+	if err != nil {
+		return fmt.Errorf("fetch review: %w", err)
+	}
+
+	sha, err := git.ResolveSHA(repoRoot, job.GitRef)
+	if err != nil {
+		return fmt.Errorf("commit %s not found in this repository: %w", git.ShortSHA(job.GitRef), err)
+	}
+	head, err := git.ResolveSHA(repoRoot, "HEAD")
+	if err != nil {
+		return fmt.Errorf("resolve HEAD: %w", err)
+	}
+	if sha != head {
+		return fmt.Errorf("commit %s is not HEAD; refusing to rewrite history", git.ShortSHA(sha))
+	}
+	pushed, err := git.IsCommitPushed(repoRoot, sha)
+	if err != nil {
+		return err
+	}
+	if pushed {
+		return fmt.Errorf("commit %s has been pushed; refusing to rewrite history", git.ShortSHA(sha))
+	}
+
+	msg, err := git.GetCommitMessage(repoRoot, sha)
+	if err != nil {
+		return err
+	}
+	msg = strings.TrimRight(msg, " \t\r\n") + "\n"
+	newMsg := setTrailer(msg, verdictTrailerKey, reviewVerdictLabel(review))
+
+	if dryRun {
+		fmt.Fprint(cmd.OutOrStdout(), newMsg)
+		return nil
+	}
+	if newMsg == msg {
+		cmd.Printf("Commit %s already has an up-to-date %s trailer\n", git.ShortSHA(sha), verdictTrailerKey)
+		return nil
+	}
+
+	newSHA, err := git.AmendCommitMessage(repoRoot, newMsg)
+	if err != nil {
+		return err
+	}
+	// The amend skips hooks, so move the review to the new SHA here
+	if err := remapAmendedCommit(addr, repoRoot, sha, newSHA); err != nil {
+		cmd.PrintErrf("Warning: could not remap review to %s: %v\n", git.ShortSHA(newSHA), err)
+	}
+	cmd.Printf("Amended %s -> %s with %s: %s\n",
+		git.ShortSHA(sha), git.ShortSHA(newSHA), verdictTrailerKey, reviewVerdictLabel(review))
+	return nil
+}
+
+// remapAmendedCommit points the reviews of oldSHA at newSHA, as the
+// post-rewrite hook would after an amend.
+func remapAmendedCommit(addr, repoRoot, oldSHA, newSHA string) error {
+	patchID := git.GetPatchID(repoRoot, newSHA)
+	if patchID == "" {
+		return fmt.Errorf("no patch-id for %s", git.ShortSHA(newSHA))
+	}
+	info, err := git.GetCommitInfo(repoRoot, newSHA)
+	if err != nil {
+		return err
+	}
+	mainRoot, err := git.GetMainRepoRoot(repoRoot)
+	if err != nil {
+		mainRoot = repoRoot
+	}
+	_, err = daemon.NewHTTPClient(addr).Remap(daemon.RemapRequest{
+		RepoPath: mainRoot,
+		Mappings: []daemon.RemapMapping{{
+			OldSHA:    oldSHA,
+			NewSHA:    newSHA,
+			PatchID:   patchID,
+			Author:    info.Author,
+			Subject:   info.Subject,
+			Timestamp: info.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+		}},
+	})
+	return err
+}
+
+// reviewVerdictLabel returns "PASS" or "FAIL" for a review, preferring the
+// stored verdict over re-parsing the output.
+func reviewVerdictLabel(review *storage.Review) string {
+	if review.Verdict() == "P" {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+// setTrailer returns msg with a "key: value" trailer. If the final paragraph
+// is already a trailer block, an existing trailer with the same key
+// (case-insensitive) is replaced, otherwise the trailer is appended to the
+// block. If there is no trailer block, one is started after a blank line.
+func setTrailer(msg, key, value string) string {
+	trailer := key + ": " + value
+	lines := strings.Split(strings.TrimRight(msg, " \t\r\n"), "\n")
+
+	// Locate the final paragraph
+	start := len(lines)
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+
+	isTrailerBlock := start > 0 && start < len(lines)
+	for _, line := range lines[start:] {
+		if !trailerLinePattern.MatchString(line) {
+			isTrailerBlock = false
+			break
+		}
+	}
+
+	if !isTrailerBlock {
+		return strings.Join(lines, "\n") + "\n\n" + trailer + "\n"
+	}
+
+	prefix := strings.ToLower(key) + ":"
+	for i := start; i < len(lines); i++ {
+		if strings.HasPrefix(strings.ToLower(lines[i]), prefix) {
+			lines[i] = trailer
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	return strings.Join(append(lines, trailer), "\n") + "\n"
+}
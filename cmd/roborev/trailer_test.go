@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func TestSetTrailer(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{
+			name: "subject only",
+			msg:  "Fix parser\n",
+			want: "Fix parser\n\nRoborev-Verdict: PASS\n",
+		},
+		{
+			name: "subject with colon is not a trailer block",
+			msg:  "parser: fix crash\n",
+			want: "parser: fix crash\n\nRoborev-Verdict: PASS\n",
+		},
+		{
+			name: "body paragraph",
+			msg:  "Fix parser\n\nHandle empty input.\n",
+			want: "Fix parser\n\nHandle empty input.\n\nRoborev-Verdict: PASS\n",
+		},
+		{
+			name: "appends to existing trailer block",
+			msg:  "Fix parser\n\nSigned-off-by: A <a@example.com>\n",
+			want: "Fix parser\n\nSigned-off-by: A <a@example.com>\nRoborev-Verdict: PASS\n",
+		},
+		{
+			name: "replaces existing verdict",
+			msg:  "Fix parser\n\nroborev-verdict: FAIL\nSigned-off-by: A <a@example.com>\n",
+			want: "Fix parser\n\nRoborev-Verdict: PASS\nSigned-off-by: A <a@example.com>\n",
+		},
+		{
+			name: "already present is unchanged",
+			msg:  "Fix parser\n\nRoborev-Verdict: PASS\n",
+			want: "Fix parser\n\nRoborev-Verdict: PASS\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setTrailer(tt.msg, verdictTrailerKey, "PASS"); got != tt.want {
+				t.Errorf("setTrailer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// mockTrailerDaemon serves a single done review job for sha. Remap
+// requests are sent on the returned channel.
+func mockTrailerDaemon(t *testing.T, repoDir, sha, output string) (string, <-chan daemon.RemapRequest) {
+	t.Helper()
+	remaps := make(chan daemon.RemapRequest, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/jobs":
+			json.NewEncoder(w).Encode(map[string]any{
+				"jobs": []storage.ReviewJob{{
+					ID: 1, GitRef: sha, RepoPath: repoDir,
+					Status: storage.JobStatusDone, JobType: storage.JobTypeReview,
+				}},
+			})
+		case "/api/review":
+			json.NewEncoder(w).Encode(storage.Review{ID: 1, JobID: 1, Output: output})
+		case "/api/remap":
+			var req daemon.RemapRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			remaps <- req
+			json.NewEncoder(w).Encode(daemon.RemapResult{Remapped: len(req.Mappings)})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts.URL, remaps
+}
+
+func TestRunTrailer(t *testing.T) {
+	t.Run("appends trailer and is idempotent", func(t *testing.T) {
+		repo := newTestGitRepo(t)
+		sha := repo.CommitFile("a.txt", "a", "Add a")
+		addr, _ := mockTrailerDaemon(t, repo.Dir, sha, "No issues found.")
+
+		cmd, out := newTestCmd(t)
+		if err := runTrailer(cmd, addr, repo.Dir, 1, false); err != nil {
+			t.Fatalf("runTrailer: %v", err)
+		}
+		if !strings.Contains(out.String(), "Amended") {
+			t.Errorf("expected amend message, got %q", out.String())
+		}
+		msg := repo.Run("log", "-1", "--format=%B")
+		if msg != "Add a\n\nRoborev-Verdict: PASS" {
+			t.Errorf("unexpected message after amend: %q", msg)
+		}
+		amended := repo.Run("rev-parse", "HEAD")
+
+		// The job still points at the pre-amend SHA; rerun against the new HEAD
+		addr, _ = mockTrailerDaemon(t, repo.Dir, amended, "No issues found.")
+		cmd, out = newTestCmd(t)
+		if err := runTrailer(cmd, addr, repo.Dir, 1, false); err != nil {
+			t.Fatalf("second runTrailer: %v", err)
+		}
+		if !strings.Contains(out.String(), "already has") {
+			t.Errorf("expected no-op message, got %q", out.String())
+		}
+		if got := repo.Run("rev-parse", "HEAD"); got != amended {
+			t.Errorf("second run rewrote HEAD: %s -> %s", amended, got)
+		}
+		if n := strings.Count(repo.Run("log", "-1", "--format=%B"), "Roborev-Verdict"); n != 1 {
+			t.Errorf("expected 1 trailer, got %d", n)
+		}
+	})
+
+	t.Run("skips hooks and remaps review", func(t *testing.T) {
+		repo := newTestGitRepo(t)
+		sha := repo.CommitFile("a.txt", "a", "Add a")
+		addr, remaps := mockTrailerDaemon(t, repo.Dir, sha, "No issues found.")
+
+		marker := filepath.Join(t.TempDir(), "hook-ran")
+		hook := "#!/bin/sh\ntouch " + marker + "\n"
+		hooksDir := filepath.Join(repo.Dir, ".git", "hooks")
+		for _, name := range []string{"post-commit", "post-rewrite"} {
+			if err := os.WriteFile(filepath.Join(hooksDir, name), []byte(hook), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		cmd, _ := newTestCmd(t)
+		if err := runTrailer(cmd, addr, repo.Dir, 1, false); err != nil {
+			t.Fatalf("runTrailer: %v", err)
+		}
+		if _, err := os.Stat(marker); err == nil {
+			t.Error("amend ran a commit hook")
+		}
+
+		amended := repo.Run("rev-parse", "HEAD")
+		select {
+		case req := <-remaps:
+			if len(req.Mappings) != 1 || req.Mappings[0].OldSHA != sha || req.Mappings[0].NewSHA != amended {
+				t.Errorf("unexpected remap request: %+v", req)
+			}
+		default:
+			t.Error("review was not remapped to the amended commit")
+		}
+	})
+
+	t.Run("dry run does not amend", func(t *testing.T) {
+		repo := newTestGitRepo(t)
+		sha := repo.CommitFile("a.txt", "a", "Add a")
+		addr, _ := mockTrailerDaemon(t, repo.Dir, sha, "- High: nil dereference in a.go")
+
+		cmd, out := newTestCmd(t)
+		if err := runTrailer(cmd, addr, repo.Dir, 1, true); err != nil {
+			t.Fatalf("runTrailer: %v", err)
+		}
+		if out.String() != "Add a\n\nRoborev-Verdict: FAIL\n" {
+			t.Errorf("unexpected dry-run output: %q", out.String())
+		}
+		if got := repo.Run("rev-parse", "HEAD"); got != sha {
+			t.Errorf("dry run changed HEAD: %s -> %s", sha, got)
+		}
+	})
+
+	t.Run("refuses non-HEAD commit", func(t *testing.T) {
+		repo := newTestGitRepo(t)
+		sha := repo.CommitFile("a.txt", "a", "Add a")
+		repo.CommitFile("b.txt", "b", "Add b")
+		addr, _ := mockTrailerDaemon(t, repo.Dir, sha, "No issues found.")
+
+		cmd, _ := newTestCmd(t)
+		err := runTrailer(cmd, addr, repo.Dir, 1, false)
+		if err == nil || !strings.Contains(err.Error(), "not HEAD") {
+			t.Fatalf("expected not HEAD error, got %v", err)
+		}
+	})
+
+	t.Run("refuses pushed commit", func(t *testing.T) {
+		repo := newTestGitRepo(t)
+		sha := repo.CommitFile("a.txt", "a", "Add a")
+		repo.Run("update-ref", "refs/remotes/origin/main", sha)
+		addr, _ := mockTrailerDaemon(t, repo.Dir, sha, "No issues found.")
+
+		cmd, _ := newTestCmd(t)
+		err := runTrailer(cmd, addr, repo.Dir, 1, false)
+		if err == nil || !strings.Contains(err.Error(), "pushed") {
+			t.Fatalf("expected pushed error, got %v", err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,6 +32,7 @@ import (
 	"github.com/roborev-dev/roborev/internal/config"
 	"github.com/roborev-dev/roborev/internal/daemon"
 	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/review"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/roborev-dev/roborev/internal/update"
 	"github.com/roborev-dev/roborev/internal/version"
@@ -203,6 +205,7 @@ const (
 	tuiViewTasks           // Background fix tasks view
 	tuiViewWorktreeConfirm // Confirm creating a worktree to apply patch
 	tuiViewPatch           // Patch viewer for fix jobs
+	tuiViewCompare         // Side-by-side reviews of the same ref
 )
 
 // queuePrefetchBuffer is the number of extra rows to fetch beyond what's visible,
@@ -287,18 +290,27 @@ type tuiModel struct {
 	// Comment modal state
 	commentText     string  // The response text being typed
 	commentJobID    int64   // Job ID we're responding to
+	commentEditID   int64   // Comment being edited (0 = adding a new comment)
 	commentCommit   string  // Short commit SHA for display
 	commentFromView tuiView // View to return to after comment modal closes
 
 	// Active filter (applied to queue view)
-	activeRepoFilter   []string // Empty = show all, otherwise repo root_paths to filter by
-	activeBranchFilter string   // Empty = show all, otherwise branch name to filter by
-	filterStack        []string // Order of applied filters: "repo", "branch" - for escape to pop in order
-	hideAddressed      bool     // When true, hide jobs with addressed reviews
+	activeRepoFilter   []string      // Empty = show all, otherwise repo root_paths to filter by
+	activeBranchFilter string        // Empty = show all, otherwise branch name to filter by
+	filterStack        []string      // Order of applied filters: "repo", "branch" - for escape to pop in order
+	hideAddressed      bool          // When true, hide jobs with addressed reviews
+	hideRemote         bool          // When true, hide jobs synced from other machines
+	verdictFilter      verdictFilter // Show only jobs with this verdict (queue and tasks views)
+	queueSearch        string        // "/" search text matched against repo, branch, subject, and agent
+	queueSearchTyping  bool          // True while the "/" search input has focus
+	fixAppliedOnly     bool          // When true, the tasks view shows only applied/rebased fixes
 
 	// Display name cache (keyed by repo path)
 	displayNames map[string]string
 
+	// Global [repo_names] overrides (read-only after init)
+	repoNames map[string]string
+
 	// Branch name cache (keyed by job ID) - caches derived branches to avoid repeated git calls
 	branchNames map[int64]string
 
@@ -340,16 +352,17 @@ type tuiModel struct {
 	helpScroll   int     // Scroll position in help view
 
 	// Log view state
-	logJobID     int64            // Job being viewed
-	logLines     []logLine        // Buffer of output lines
-	logScroll    int              // Scroll position
-	logStreaming bool             // True if job is still running
-	logFromView  tuiView          // View to return to
-	logFollow    bool             // True if auto-scrolling to bottom (follow mode)
-	logOffset    int64            // Byte offset for next incremental fetch
-	logFmtr      *streamFormatter // Persistent formatter across polls
-	logLoading   bool             // True while a fetch is in-flight
-	logFetchSeq  uint64           // Monotonic seq to drop stale responses
+	logJobID         int64            // Job being viewed
+	logLines         []logLine        // Buffer of output lines
+	logScroll        int              // Scroll position
+	logStreaming     bool             // True if job is still running
+	logCancelConfirm bool             // True while asking to confirm cancelling the streaming job
+	logFromView      tuiView          // View to return to
+	logFollow        bool             // True if auto-scrolling to bottom (follow mode)
+	logOffset        int64            // Byte offset for next incremental fetch
+	logFmtr          *streamFormatter // Persistent formatter across polls
+	logLoading       bool             // True while a fetch is in-flight
+	logFetchSeq      uint64           // Monotonic seq to drop stale responses
 
 	// Glamour markdown render cache (pointer so View's value receiver can update it)
 	mdCache *markdownCache
@@ -362,6 +375,8 @@ type tuiModel struct {
 	// Fix task state
 	fixJobs        []storage.ReviewJob // Fix jobs for tasks view
 	fixSelectedIdx int                 // Selected index in tasks view
+	fixSort        fixSort             // Tasks view sort key
+	fixSortReverse bool                // Reverse the tasks view sort
 	fixPromptText  string              // Editable fix prompt text
 	fixPromptJobID int64               // Parent job ID for fix prompt modal
 	fixShowHelp    bool                // Show help overlay in tasks view
@@ -376,6 +391,16 @@ type tuiModel struct {
 
 	worktreeConfirmJobID  int64  // Job ID pending worktree-apply confirmation
 	worktreeConfirmBranch string // Branch name for worktree confirmation prompt
+
+	// Comparison view state (entered from review view)
+	compareColumns []comparisonColumn // Sibling reviews of the current review's ref
+	compareIdx     int                // Focused column (side by side) or tab
+	compareScroll  int                // Scroll offset shared by all columns
+
+	// Findings list (review view of a failing review)
+	findingSel      int          // Selected finding
+	findingExpanded map[int]bool // Findings whose bodies are shown
+	findingsRaw     bool         // Show the raw review output instead of the list
 }
 
 // pendingState tracks a pending addressed toggle with sequence number
@@ -478,6 +503,16 @@ type tuiCommentResultMsg struct {
 	jobID int64
 	err   error
 }
+type tuiFindingResolvedMsg struct {
+	jobID       int64
+	resolved    []int // Resolved finding indexes reported by the daemon
+	oldResolved []int // Resolved finding indexes to restore on error
+	err         error
+}
+type tuiPatchSavedMsg struct {
+	path string
+	err  error
+}
 type tuiClipboardResultMsg struct {
 	err  error
 	view tuiView // The view where copy was triggered (for flash attribution)
@@ -527,10 +562,18 @@ type ClipboardWriter interface {
 	WriteText(text string) error
 }
 
+// errNoClipboard is returned when no clipboard tool is installed, as on
+// a headless machine reached over SSH.
+var errNoClipboard = errors.New("no clipboard tool found (install xclip, xsel, or wl-clipboard)")
+
 // realClipboard implements ClipboardWriter using the system clipboard
+// (pbcopy on macOS, clip.exe on Windows, xclip/xsel/wl-copy on Linux)
 type realClipboard struct{}
 
 func (r *realClipboard) WriteText(text string) error {
+	if clipboard.Unsupported {
+		return errNoClipboard
+	}
 	return clipboard.WriteAll(text)
 }
 
@@ -567,6 +610,7 @@ func newTuiModel(serverAddr string, opts ...tuiOptions) tuiModel {
 	hideAddressed := false
 	autoFilterRepo := false
 	tabWidth := 2
+	var repoNames map[string]string
 	var cwdRepoRoot, cwdBranch string
 
 	if !skipExternalIO {
@@ -582,6 +626,7 @@ func newTuiModel(serverAddr string, opts ...tuiOptions) tuiModel {
 			if cfg.TabWidth > 0 {
 				tabWidth = cfg.TabWidth
 			}
+			repoNames = cfg.RepoNames
 		}
 
 		// Detect current repo/branch for filter sort priority
@@ -630,7 +675,8 @@ func newTuiModel(serverAddr string, opts ...tuiOptions) tuiModel {
 		lockedBranchFilter:     lockedBranch,
 		cwdRepoRoot:            cwdRepoRoot,
 		cwdBranch:              cwdBranch,
-		displayNames:           make(map[string]string),      // Cache display names to avoid disk reads on render
+		displayNames:           make(map[string]string), // Cache display names to avoid disk reads on render
+		repoNames:              repoNames,
 		branchNames:            make(map[int64]string),       // Cache derived branch names to avoid git calls on render
 		pendingAddressed:       make(map[int64]pendingState), // Track pending addressed changes (by job ID)
 		pendingReviewAddressed: make(map[int64]pendingState), // Track pending addressed changes (by review ID)
@@ -662,7 +708,7 @@ func (m *tuiModel) getDisplayName(repoPath, defaultName string) string {
 		return defaultName
 	}
 	// Cache miss - load from config (handles reviews for repos not in jobs list)
-	displayName := config.GetDisplayName(repoPath)
+	displayName := lookupDisplayName(m.repoNames, repoPath)
 	m.displayNames[repoPath] = displayName
 	if displayName != "" {
 		return displayName
@@ -678,10 +724,20 @@ func (m *tuiModel) updateDisplayNameCache(jobs []storage.ReviewJob) {
 			continue
 		}
 		// Always refresh to pick up config changes
-		m.displayNames[job.RepoPath] = config.GetDisplayName(job.RepoPath)
+		m.displayNames[job.RepoPath] = lookupDisplayName(m.repoNames, job.RepoPath)
 	}
 }
 
+// lookupDisplayName resolves a repo's configured display name: the global
+// [repo_names] table takes priority over the repo's own display_name.
+// Returns empty if neither is set.
+func lookupDisplayName(repoNames map[string]string, repoPath string) string {
+	if name := config.LookupRepoName(repoNames, repoPath); name != "" {
+		return name
+	}
+	return config.GetDisplayName(repoPath)
+}
+
 // getBranchForJob returns the branch name for a job, falling back to git lookup
 // if the stored branch is empty and the repo is available locally.
 // Results are cached to avoid repeated git calls on render.
@@ -705,7 +761,7 @@ func (m *tuiModel) getBranchForJob(job storage.ReviewJob) string {
 
 	// Fall back to git lookup if repo path exists locally and we have a SHA
 	// Only try if repo path is set and is not from a remote machine
-	if job.RepoPath == "" || (m.status.MachineID != "" && job.SourceMachineID != "" && job.SourceMachineID != m.status.MachineID) {
+	if job.RepoPath == "" || m.isRemoteJob(job) {
 		// Don't cache - repo might become available later
 		return ""
 	}
@@ -790,6 +846,9 @@ func (m tuiModel) fetchJobs() tea.Cmd {
 		if m.hideAddressed && !needsAllJobs {
 			params.Set("addressed", "false")
 		}
+		if m.hideRemote {
+			params.Set("local_only", "true")
+		}
 
 		// Exclude fix jobs — they belong in the Tasks view, not the queue
 		params.Set("exclude_job_type", "fix")
@@ -847,6 +906,9 @@ func (m tuiModel) fetchMoreJobs() tea.Cmd {
 		if m.hideAddressed {
 			params.Set("addressed", "false")
 		}
+		if m.hideRemote {
+			params.Set("local_only", "true")
+		}
 		params.Set("exclude_job_type", "fix")
 		url := fmt.Sprintf("%s/api/jobs?%s", m.serverAddr, params.Encode())
 		resp, err := m.client.Get(url)
@@ -908,6 +970,7 @@ func (m tuiModel) fetchRepos() tea.Cmd {
 	client := m.client
 	serverAddr := m.serverAddr
 	activeBranchFilter := m.activeBranchFilter // Constrain repos by active branch filter
+	repoNames := m.repoNames
 
 	return func() tea.Msg {
 		// Build URL with optional branch filter (URL-encoded)
@@ -945,7 +1008,7 @@ func (m tuiModel) fetchRepos() tea.Cmd {
 		displayNameMap := make(map[string]*repoFilterItem)
 		var displayNameOrder []string // Preserve order for stable display
 		for _, r := range reposResult.Repos {
-			displayName := config.GetDisplayName(r.RootPath)
+			displayName := lookupDisplayName(repoNames, r.RootPath)
 			if displayName == "" {
 				displayName = r.Name
 			}
@@ -1433,7 +1496,7 @@ func (m tuiModel) fetchCommitMsg(job *storage.ReviewJob) tea.Cmd {
 		}
 
 		// Handle dirty reviews (uncommitted changes)
-		if job.DiffContent != nil || job.IsDirtyJob() {
+		if job.IsDirtyJob() {
 			return tuiCommitMsgMsg{
 				jobID: jobID,
 				err:   fmt.Errorf("no commit message for uncommitted changes"),
@@ -1550,6 +1613,18 @@ func (m tuiModel) toggleAddressedForJob(jobID int64, currentState *bool) tea.Cmd
 	}
 }
 
+// markReviewSeen records that the user has read a job's review. Failures
+// are ignored: the review stays unseen and is marked again next time.
+func (m tuiModel) markReviewSeen(jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		_ = m.postJSON("/api/review/seen", map[string]any{
+			"job_id":  jobID,
+			"seen_by": tuiCommenter(),
+		}, nil)
+		return nil
+	}
+}
+
 // markParentAddressed marks the parent review job as addressed after a fix is applied.
 func (m tuiModel) markParentAddressed(parentJobID int64) tea.Cmd {
 	return func() tea.Msg {
@@ -1902,6 +1977,12 @@ func (m tuiModel) repoMatchesFilter(repoPath string) bool {
 	return slices.Contains(m.activeRepoFilter, repoPath)
 }
 
+// isRemoteJob reports whether job was created on another machine and
+// synced here.
+func (m tuiModel) isRemoteJob(job storage.ReviewJob) bool {
+	return m.status.MachineID != "" && job.SourceMachineID != "" && job.SourceMachineID != m.status.MachineID
+}
+
 // isJobVisible checks if a job passes all active filters
 func (m tuiModel) isJobVisible(job storage.ReviewJob) bool {
 	if len(m.activeRepoFilter) > 0 && !m.repoMatchesFilter(job.RepoPath) {
@@ -1910,6 +1991,15 @@ func (m tuiModel) isJobVisible(job storage.ReviewJob) bool {
 	if m.activeBranchFilter != "" && !m.branchMatchesFilter(job) {
 		return false
 	}
+	if !m.verdictFilter.matches(job) {
+		return false
+	}
+	if !m.queueSearchMatches(job) {
+		return false
+	}
+	if m.hideRemote && m.isRemoteJob(job) {
+		return false
+	}
 	if m.hideAddressed {
 		// Hide addressed reviews, failed jobs, and canceled jobs
 		// Check pendingAddressed first for optimistic updates (avoids flash on filter)
@@ -1981,9 +2071,9 @@ func (m *tuiModel) removeFilterFromStack(filterType string) {
 	m.filterStack = newStack
 }
 
-// getVisibleJobs returns jobs filtered by active filters (repo, branch, addressed)
+// getVisibleJobs returns jobs filtered by active filters (repo, branch, addressed, verdict)
 func (m tuiModel) getVisibleJobs() []storage.ReviewJob {
-	if len(m.activeRepoFilter) == 0 && m.activeBranchFilter == "" && !m.hideAddressed {
+	if len(m.activeRepoFilter) == 0 && m.activeBranchFilter == "" && !m.hideAddressed && !m.hideRemote && m.verdictFilter == verdictFilterAll && m.queueSearch == "" {
 		return m.jobs
 	}
 	var visible []storage.ReviewJob
@@ -2008,7 +2098,12 @@ func (m tuiModel) queueHelpRows() [][]string {
 	if !m.lockedRepoFilter || !m.lockedBranchFilter {
 		row2 = append(row2, "f: filter")
 	}
-	row2 = append(row2, "h: hide", "T: tasks", "?: help", "q: quit")
+	row2 = append(row2, "h: hide", "v: verdict")
+	// Offer the remote filter only once jobs from another machine exist
+	if len(m.status.MachineIDs) > 1 || m.hideRemote {
+		row2 = append(row2, "o: local")
+	}
+	row2 = append(row2, "T: tasks", "?: help", "q: quit")
 	return [][]string{row1, row2}
 }
 
@@ -2040,7 +2135,7 @@ func (m tuiModel) getVisibleSelectedIdx() int {
 	if m.selectedIdx < 0 {
 		return -1
 	}
-	if len(m.activeRepoFilter) == 0 && m.activeBranchFilter == "" && !m.hideAddressed {
+	if len(m.activeRepoFilter) == 0 && m.activeBranchFilter == "" && !m.hideAddressed && !m.hideRemote && m.verdictFilter == verdictFilterAll && m.queueSearch == "" {
 		return m.selectedIdx
 	}
 	count := 0
@@ -2165,11 +2260,21 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.currentBranch = msg.branchName
 		m.currentView = tuiViewReview
 		m.reviewScroll = 0
+		m.findingSel = 0
+		m.findingExpanded = nil
 		if m.reviewFixPanelPending && m.fixPromptJobID == msg.review.JobID {
 			m.reviewFixPanelPending = false
 			m.reviewFixPanelOpen = true
 			m.reviewFixPanelFocused = true
 		}
+		// Opening a review's detail counts as a human reading it
+		if msg.review.SeenAt == nil && msg.review.JobID > 0 {
+			m.mutateJob(msg.review.JobID, func(job *storage.ReviewJob) {
+				seen := true
+				job.Seen = &seen
+			})
+			return m, m.markReviewSeen(msg.review.JobID)
+		}
 
 	case tuiPromptMsg:
 		if msg.jobID != m.selectedJobID {
@@ -2433,15 +2538,23 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.commentJobID == msg.jobID {
 				m.commentText = ""
 				m.commentJobID = 0
+				m.commentEditID = 0
 			}
 			if m.currentView == tuiViewReview && m.currentReview != nil && m.currentReview.JobID == msg.jobID {
 				return m, m.fetchReview(msg.jobID)
 			}
 		}
 
+	case tuiFindingResolvedMsg:
+		return m.handleFindingResolvedMsg(msg)
+
 	case tuiClipboardResultMsg:
 		if msg.err != nil {
-			m.err = fmt.Errorf("copy failed: %w", msg.err)
+			// A flash rather than m.err: a missing clipboard isn't a
+			// daemon problem and shouldn't linger in the status bar
+			m.flashMessage = fmt.Sprintf("Copy failed: %v", msg.err)
+			m.flashExpiresAt = time.Now().Add(3 * time.Second)
+			m.flashView = msg.view
 		} else {
 			m.flashMessage = "Copied to clipboard"
 			m.flashExpiresAt = time.Now().Add(2 * time.Second)
@@ -2498,10 +2611,13 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
+			selectedID := m.selectedFixJobID()
 			m.fixJobs = msg.jobs
 			if m.fixSelectedIdx >= len(m.fixJobs) && len(m.fixJobs) > 0 {
 				m.fixSelectedIdx = len(m.fixJobs) - 1
 			}
+			m.sortFixJobs(selectedID)
+			m.normalizeFixSelection()
 		}
 
 	case tuiFixTriggerResultMsg:
@@ -2523,6 +2639,9 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.fetchFixJobs()
 		}
 
+	case tuiComparisonMsg:
+		return m.handleComparisonMsg(msg)
+
 	case tuiPatchMsg:
 		if msg.err != nil {
 			m.flashMessage = fmt.Sprintf("Patch fetch failed: %v", msg.err)
@@ -2535,6 +2654,15 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.currentView = tuiViewPatch
 		}
 
+	case tuiPatchSavedMsg:
+		if msg.err != nil {
+			m.flashMessage = fmt.Sprintf("Write failed: %v", msg.err)
+		} else {
+			m.flashMessage = "Wrote " + msg.path
+		}
+		m.flashExpiresAt = time.Now().Add(3 * time.Second)
+		m.flashView = tuiViewPatch
+
 	case tuiApplyPatchResultMsg:
 		if msg.needWorktree {
 			m.worktreeConfirmJobID = msg.jobID
@@ -2601,6 +2729,9 @@ func (m tuiModel) View() string {
 	if m.currentView == tuiViewPatch {
 		return m.renderPatchView()
 	}
+	if m.currentView == tuiViewCompare && len(m.compareColumns) > 0 {
+		return m.renderCompareView()
+	}
 	if m.currentView == tuiViewPrompt && m.currentReview != nil {
 		return m.renderPromptView()
 	}
@@ -2632,6 +2763,12 @@ func (m tuiModel) renderQueueView() string {
 	if m.hideAddressed {
 		title.WriteString(" [hiding addressed]")
 	}
+	if m.hideRemote {
+		title.WriteString(" [local only]")
+	}
+	if label := m.verdictFilter.label(); label != "" {
+		fmt.Fprintf(&title, " [v: %s]", label)
+	}
 	b.WriteString(tuiTitleStyle.Render(title.String()))
 	b.WriteString("\x1b[K\n") // Clear to end of line
 
@@ -2668,16 +2805,27 @@ func (m tuiModel) renderQueueView() string {
 		statusLine = fmt.Sprintf("Daemon: %s | Done: %d | Addressed: %d | Unaddressed: %d",
 			m.daemonVersion, done, addressed, unaddressed)
 	} else {
-		statusLine = fmt.Sprintf("Daemon: %s | Workers: %d/%d | Done: %d | Addressed: %d | Unaddressed: %d",
+		statusLine = fmt.Sprintf("Daemon: %s | Workers: %d/%d | %d queued, %d running | Done: %d | Addressed: %d | Unaddressed: %d",
 			m.daemonVersion,
 			m.status.ActiveWorkers, m.status.MaxWorkers,
+			m.status.QueuedJobs, m.status.RunningJobs,
 			done, addressed, unaddressed)
 	}
+	if m.queueSearchTyping {
+		statusLine += fmt.Sprintf(" | Search: /%s_", m.queueSearch)
+	} else if m.queueSearch != "" {
+		statusLine += fmt.Sprintf(" | Search: /%s (esc: clear)", m.queueSearch)
+	}
 	b.WriteString(tuiStatusStyle.Render(statusLine))
 	b.WriteString("\x1b[K\n") // Clear status line
 
-	// Update notification on line 3 (above the table)
-	if m.updateAvailable != "" {
+	// Paused queue or update notification on line 3 (above the table)
+	if m.status.QueuePausedReason != "" {
+		pausedMsg := fmt.Sprintf("Queue paused: %s - fix credentials, then run 'roborev daemon resume'", m.status.QueuePausedReason)
+		b.WriteString(tuiFailedStyle.Bold(true).Render(pausedMsg))
+	} else if m.status.QueuePausedByUser {
+		b.WriteString(tuiFailedStyle.Bold(true).Render("PAUSED - new jobs won't start until 'roborev resume'"))
+	} else if m.updateAvailable != "" {
 		updateStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "136", Dark: "226"}).Bold(true)
 		var updateMsg string
 		if m.updateIsDevBuild {
@@ -2708,7 +2856,7 @@ func (m tuiModel) renderQueueView() string {
 		if m.loadingJobs || m.loadingMore {
 			b.WriteString("Loading...")
 			b.WriteString("\x1b[K\n")
-		} else if len(m.activeRepoFilter) > 0 || m.hideAddressed {
+		} else if len(m.activeRepoFilter) > 0 || m.hideAddressed || m.hideRemote || m.verdictFilter != verdictFilterAll || m.queueSearch != "" {
 			b.WriteString("No jobs matching filters")
 			b.WriteString("\x1b[K\n")
 		} else {
@@ -2816,6 +2964,11 @@ func (m tuiModel) renderQueueView() string {
 	} else if m.flashMessage != "" && time.Now().Before(m.flashExpiresAt) && m.flashView == tuiViewQueue {
 		flashStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "28", Dark: "46"}) // Green
 		b.WriteString(flashStyle.Render(m.flashMessage))
+	} else if visibleSelectedIdx >= 0 && visibleSelectedIdx < len(visibleJobList) {
+		// Otherwise show the selected job's review summary (TL;DR)
+		if summary := visibleJobList[visibleSelectedIdx].Summary; summary != "" {
+			b.WriteString(tuiStatusStyle.Render(truncateString(stripControlChars(summary), max(1, m.width-1))))
+		}
 	}
 	b.WriteString("\x1b[K\n") // Clear to end of line
 
@@ -2883,6 +3036,10 @@ func (m tuiModel) renderJobLine(job storage.ReviewJob, selected bool, idWidth in
 	if !config.IsDefaultReviewType(job.ReviewType) {
 		ref = ref + " [" + job.ReviewType + "]"
 	}
+	// Commits enqueued from one range share its label
+	if job.RangeLabel != "" && job.JobType != storage.JobTypeRange {
+		ref = ref + " (" + job.RangeLabel + ")"
+	}
 	if len(ref) > colWidths.ref {
 		ref = ref[:max(1, colWidths.ref-3)] + "..."
 	}
@@ -2896,7 +3053,7 @@ func (m tuiModel) renderJobLine(job storage.ReviewJob, selected bool, idWidth in
 	// Use cached display name, falling back to RepoName
 	repo := m.getDisplayName(job.RepoPath, job.RepoName)
 	// Append [remote] indicator for jobs from other machines
-	if m.status.MachineID != "" && job.SourceMachineID != "" && job.SourceMachineID != m.status.MachineID {
+	if m.isRemoteJob(job) {
 		repo += " [R]"
 	}
 	if len(repo) > colWidths.repo {
@@ -2925,8 +3082,15 @@ func (m tuiModel) renderJobLine(job storage.ReviewJob, selected bool, idWidth in
 		}
 	}
 
-	// Color the status only when not selected (selection style should be uniform)
+	// Color the status only when not selected (selection style should be uniform).
+	// A trailing * flags a review no human has opened yet.
 	status := string(job.Status)
+	if job.Status == storage.JobStatusFailed && review.IsAuthFailure(job.Error) {
+		status = "authfail"
+	}
+	if job.Seen != nil && !*job.Seen {
+		status += "*"
+	}
 	var styledStatus string
 	if selected {
 		styledStatus = status
@@ -3032,6 +3196,77 @@ func stripControlChars(s string) string {
 
 // wrapText is in tui_helpers.go (uses runewidth for correct Unicode/wide char handling)
 
+// reviewCommitLine describes what a review covers for the review view
+// header: the commit's author, author date, and full subject, or the git
+// ref for range and ref-only jobs with no commit record. Dirty and task
+// jobs have nothing to show.
+func reviewCommitLine(job storage.ReviewJob) string {
+	if job.CommitAuthor != "" {
+		line := "Author: " + job.CommitAuthor
+		if job.CommitDate != nil {
+			line += " | " + job.CommitDate.Local().Format("2006-01-02 15:04")
+		}
+		if job.CommitSubject != "" {
+			line += " | " + job.CommitSubject
+		}
+		return line
+	}
+	if job.GitRef == "" || job.IsDirtyJob() || job.IsTaskJob() {
+		return ""
+	}
+	return "Ref: " + job.GitRef
+}
+
+// reviewRerunChain returns the chain of reruns (review --rerun) through
+// jobID among the loaded jobs with each one's verdict, oldest first and
+// the current job in brackets, e.g. "Reruns: #12 Fail → [#15 Pass]".
+// Returns "" if the job was never rerun and is not a rerun.
+func (m tuiModel) reviewRerunChain(jobID int64) string {
+	byID := make(map[int64]storage.ReviewJob, len(m.jobs))
+	rerunOf := make(map[int64]int64, len(m.jobs)) // earlier job ID -> newest rerun's ID
+	for _, j := range m.jobs {
+		byID[j.ID] = j
+		if j.SupersedesJobID != nil && j.ID > rerunOf[*j.SupersedesJobID] {
+			rerunOf[*j.SupersedesJobID] = j.ID
+		}
+	}
+
+	ids := []int64{jobID}
+	for {
+		j, ok := byID[ids[0]]
+		if !ok || j.SupersedesJobID == nil || slices.Contains(ids, *j.SupersedesJobID) {
+			break
+		}
+		ids = append([]int64{*j.SupersedesJobID}, ids...)
+	}
+	for next, ok := rerunOf[jobID]; ok && !slices.Contains(ids, next); next, ok = rerunOf[next] {
+		ids = append(ids, next)
+	}
+	if len(ids) == 1 {
+		return ""
+	}
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		label := fmt.Sprintf("#%d", id)
+		if j, ok := byID[id]; ok {
+			switch {
+			case j.Verdict != nil && *j.Verdict == "P":
+				label += " Pass"
+			case j.Verdict != nil && *j.Verdict == "F":
+				label += " Fail"
+			default:
+				label += " " + string(j.Status)
+			}
+		}
+		if id == jobID {
+			label = "[" + label + "]"
+		}
+		parts[i] = label
+	}
+	return "Reruns: " + strings.Join(parts, " → ")
+}
+
 func (m tuiModel) renderReviewView() string {
 	var b strings.Builder
 
@@ -3041,6 +3276,7 @@ func (m tuiModel) renderReviewView() string {
 	var title string
 	var titleLen int
 	var locationLineLen int
+	var commitLine, rerunLine string
 	if review.Job != nil {
 		ref := shortJobRef(*review.Job)
 		idStr := fmt.Sprintf("#%d ", review.Job.ID)
@@ -3051,7 +3287,7 @@ func (m tuiModel) renderReviewView() string {
 		}
 		repoStr := m.getDisplayName(review.Job.RepoPath, defaultName)
 
-		agentStr := formatAgentLabel(review.Agent, review.Job.Model)
+		agentStr := reviewAgentLabel(review)
 
 		title = fmt.Sprintf("Review %s%s (%s)", idStr, repoStr, agentStr)
 		titleLen = runewidth.StringWidth(title)
@@ -3078,6 +3314,20 @@ func (m tuiModel) renderReviewView() string {
 		b.WriteString(tuiStatusStyle.Render(locationLine))
 		b.WriteString("\x1b[K") // Clear to end of line
 
+		// Show the reviewed commit's author, date, and subject
+		if commitLine = reviewCommitLine(*review.Job); commitLine != "" {
+			b.WriteString("\n")
+			b.WriteString(tuiStatusStyle.Render(commitLine))
+			b.WriteString("\x1b[K") // Clear to end of line
+		}
+
+		// Show earlier and later runs of this review to compare verdicts
+		if rerunLine = m.reviewRerunChain(review.Job.ID); rerunLine != "" {
+			b.WriteString("\n")
+			b.WriteString(tuiStatusStyle.Render(rerunLine))
+			b.WriteString("\x1b[K") // Clear to end of line
+		}
+
 		// Show verdict and addressed status on next line (skip verdict for fix jobs)
 		hasVerdict := review.Job.Verdict != nil && *review.Job.Verdict != "" && !review.Job.IsFixJob()
 		if hasVerdict || review.Addressed {
@@ -3107,31 +3357,40 @@ func (m tuiModel) renderReviewView() string {
 		b.WriteString("\x1b[K\n") // Clear to end of line
 	}
 
-	// Build content: review output + responses
-	var content strings.Builder
-	content.WriteString(review.Output)
-
-	// Append responses if any
+	// Build comments text appended after the review content
+	var comments strings.Builder
 	if len(m.currentResponses) > 0 {
-		content.WriteString("\n\n--- Comments ---\n")
+		comments.WriteString("\n\n--- Comments ---\n")
 		for _, r := range m.currentResponses {
+			// Replies are quoted under the comment they answer; markdown
+			// rendering drops leading spaces, so indent with blockquotes.
+			indent := strings.Repeat("> ", r.Depth)
 			timestamp := r.CreatedAt.Format("Jan 02 15:04")
-			fmt.Fprintf(&content, "\n[%s] %s:\n", timestamp, r.Responder)
-			content.WriteString(r.Response)
-			content.WriteString("\n")
+			fmt.Fprintf(&comments, "\n%s[%s] %s:\n", indent, timestamp, r.Responder)
+			for line := range strings.SplitSeq(r.Response, "\n") {
+				comments.WriteString(indent + line + "\n")
+			}
 		}
 	}
 
-	// Render markdown content with glamour (cached), falling back to plain text wrapping.
+	// Failing reviews with parsed findings render as a findings list. Other
+	// reviews render markdown content with glamour (cached), falling back to
+	// plain text wrapping.
 	// wrapWidth caps at 100 for readability; maxWidth uses actual terminal width for truncation.
 	maxWidth := max(20, m.width-4)
 	wrapWidth := min(maxWidth, 100)
-	contentStr := content.String()
+	findings := m.listedFindings()
 	var lines []string
-	if m.mdCache != nil {
-		lines = m.mdCache.getReviewLines(contentStr, wrapWidth, maxWidth, review.ID)
-	} else {
-		lines = sanitizeLines(wrapText(contentStr, wrapWidth))
+	switch {
+	case len(findings) > 0:
+		lines, _ = renderFindingLines(findings, m.findingSel, m.findingExpanded, m.resolvedFindingSet(), wrapWidth)
+		if comments.Len() > 0 {
+			lines = append(lines, sanitizeLines(wrapText(strings.TrimPrefix(comments.String(), "\n"), wrapWidth))...)
+		}
+	case m.mdCache != nil:
+		lines = m.mdCache.getReviewLines(review.Output+comments.String(), wrapWidth, maxWidth, review.ID)
+	default:
+		lines = sanitizeLines(wrapText(review.Output+comments.String(), wrapWidth))
 	}
 
 	// Compute title line count based on actual title length
@@ -3142,9 +3401,14 @@ func (m tuiModel) renderReviewView() string {
 
 	// Help table rows
 	reviewHelpRows := [][]string{
-		{"p: prompt", "c: comment", "m: commit msg", "a: addressed", "y: copy", "F: fix"},
+		{"p: prompt", "c: comment", "m: commit msg", "a: addressed", "y: copy", "F: fix", "v: compare"},
 		{"↑/↓: scroll", "←/→: prev/next", "?: commands", "esc: back"},
 	}
+	if len(findings) > 0 {
+		reviewHelpRows[1] = []string{"↑/↓: select", "space: resolve", "enter: expand", "s: raw", "←/→: prev/next", "?: commands", "esc: back"}
+	} else if len(m.reviewFindings()) > 0 {
+		reviewHelpRows[1] = []string{"↑/↓: scroll", "s: findings", "←/→: prev/next", "?: commands", "esc: back"}
+	}
 	helpLines := len(reflowHelpRows(reviewHelpRows, m.width))
 
 	// Compute location line count (repo path + ref + branch can wrap)
@@ -3155,9 +3419,20 @@ func (m tuiModel) renderReviewView() string {
 			locationLines = (locationLineLen + m.width - 1) / m.width
 		}
 	}
+	commitLines := 0
+	for _, line := range []string{commitLine, rerunLine} {
+		if line == "" {
+			continue
+		}
+		if lineLen := runewidth.StringWidth(line); m.width > 0 && lineLen > m.width {
+			commitLines += (lineLen + m.width - 1) / m.width
+		} else {
+			commitLines++
+		}
+	}
 
-	// headerHeight = title + location line + status line (1) + help + verdict/addressed (0|1)
-	headerHeight := titleLines + locationLines + 1 + helpLines
+	// headerHeight = title + location line + commit and rerun lines + status line (1) + help + verdict/addressed (0|1)
+	headerHeight := titleLines + locationLines + commitLines + 1 + helpLines
 	hasVerdict := review.Job != nil && review.Job.Verdict != nil && *review.Job.Verdict != "" && !review.Job.IsFixJob()
 	if hasVerdict || review.Addressed {
 		headerHeight++ // Add 1 for verdict/addressed line
@@ -3172,6 +3447,7 @@ func (m tuiModel) renderReviewView() string {
 	maxScroll := max(len(lines)-visibleLines, 0)
 	if m.mdCache != nil {
 		m.mdCache.lastReviewMaxScroll = maxScroll
+		m.mdCache.lastReviewVisibleLines = visibleLines
 	}
 	start := max(min(m.reviewScroll, maxScroll), 0)
 	end := min(start+visibleLines, len(lines))
@@ -3281,7 +3557,7 @@ func (m tuiModel) renderPromptView() string {
 	if review.Job != nil {
 		ref := shortJobRef(*review.Job)
 		idStr := fmt.Sprintf("#%d ", review.Job.ID)
-		agentStr := formatAgentLabel(review.Agent, review.Job.Model)
+		agentStr := reviewAgentLabel(review)
 		title := fmt.Sprintf("Prompt %s%s (%s)", idStr, ref, agentStr)
 		b.WriteString(tuiTitleStyle.Render(title))
 	} else {
@@ -3487,8 +3763,11 @@ func (m tuiModel) renderRespondView() string {
 	var b strings.Builder
 
 	title := "Add Comment"
+	if m.commentEditID != 0 {
+		title = "Edit Comment"
+	}
 	if m.commentCommit != "" {
-		title = fmt.Sprintf("Add Comment (%s)", m.commentCommit)
+		title = fmt.Sprintf("%s (%s)", title, m.commentCommit)
 	}
 	b.WriteString(tuiTitleStyle.Render(title))
 	b.WriteString("\x1b[K\n\x1b[K\n") // Clear title and blank line
@@ -3545,25 +3824,30 @@ func (m tuiModel) renderRespondView() string {
 		linesWritten++
 	}
 
-	b.WriteString(renderHelpTable([][]string{
-		{"enter: submit", "esc: cancel"},
-	}, m.width))
+	helpRow := []string{"enter: submit", "esc: cancel"}
+	if m.commentEditID != 0 {
+		helpRow = []string{"enter: save", "ctrl+d: delete", "esc: cancel"}
+	}
+	b.WriteString(renderHelpTable([][]string{helpRow}, m.width))
 	b.WriteString("\x1b[K")
 	b.WriteString("\x1b[J") // Clear to end of screen to prevent artifacts
 
 	return b.String()
 }
 
+// tuiCommenter returns the name comments are posted under.
+func tuiCommenter() string {
+	if commenter := os.Getenv("USER"); commenter != "" {
+		return commenter
+	}
+	return "anonymous"
+}
+
 func (m tuiModel) submitComment(jobID int64, text string) tea.Cmd {
 	return func() tea.Msg {
-		commenter := os.Getenv("USER")
-		if commenter == "" {
-			commenter = "anonymous"
-		}
-
 		err := m.postJSON("/api/comment", map[string]any{
 			"job_id":    jobID,
-			"commenter": commenter,
+			"commenter": tuiCommenter(),
 			"comment":   strings.TrimSpace(text),
 		}, nil)
 		if err != nil {
@@ -3574,6 +3858,30 @@ func (m tuiModel) submitComment(jobID int64, text string) tea.Cmd {
 	}
 }
 
+func (m tuiModel) updateComment(jobID, commentID int64, text string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.postJSON("/api/comment/update", map[string]any{
+			"id":        commentID,
+			"commenter": tuiCommenter(),
+			"comment":   strings.TrimSpace(text),
+		}, nil)
+		if err != nil {
+			return tuiCommentResultMsg{jobID: jobID, err: fmt.Errorf("update comment: %w", err)}
+		}
+		return tuiCommentResultMsg{jobID: jobID}
+	}
+}
+
+func (m tuiModel) deleteComment(jobID, commentID int64) tea.Cmd {
+	return func() tea.Msg {
+		err := m.postJSON("/api/comment/delete", map[string]any{"id": commentID}, nil)
+		if err != nil {
+			return tuiCommentResultMsg{jobID: jobID, err: fmt.Errorf("delete comment: %w", err)}
+		}
+		return tuiCommentResultMsg{jobID: jobID}
+	}
+}
+
 func (m tuiModel) renderCommitMsgView() string {
 	var b strings.Builder
 
@@ -3651,9 +3959,12 @@ func (m tuiModel) renderLogView() string {
 	} else {
 		title = fmt.Sprintf("Log #%d", m.logJobID)
 	}
-	if m.logStreaming {
+	switch {
+	case m.logStreaming:
 		title += " " + tuiRunningStyle.Render("● live")
-	} else {
+	case job != nil && job.Status == storage.JobStatusCanceled:
+		title += " " + tuiCanceledStyle.Render("● canceled")
+	default:
 		title += " " + tuiDoneStyle.Render("● complete")
 	}
 	b.WriteString(tuiTitleStyle.Render(title))
@@ -3724,6 +4035,9 @@ func (m tuiModel) renderLogView() string {
 	} else {
 		status += " " + tuiStatusStyle.Render("[paused - G to follow]")
 	}
+	if m.logCancelConfirm {
+		status = tuiCanceledStyle.Render(fmt.Sprintf("Cancel job #%d? (y to cancel, any other key to keep running)", m.logJobID))
+	}
 	b.WriteString(tuiStatusStyle.Render(status))
 	b.WriteString("\x1b[K\n")
 
@@ -3769,6 +4083,9 @@ func helpLines() []string {
 			keys: []struct{ key, desc string }{
 				{"f", "Filter by repository/branch"},
 				{"h", "Toggle hide addressed/failed"},
+				{"v", "Cycle verdict filter (all/pass/fail/pending)"},
+				{"/", "Search by repo, branch, subject, or agent"},
+				{"o", "Toggle hide jobs from other machines [R]"},
 				{"esc", "Clear filters (one at a time)"},
 			},
 		},
@@ -3781,9 +4098,14 @@ func helpLines() []string {
 				{"p", "Switch to prompt view"},
 				{"a", "Toggle addressed"},
 				{"c", "Add comment"},
+				{"C", "Edit/delete your last comment"},
 				{"y", "Copy review to clipboard"},
 				{"m", "View commit message"},
 				{"F", "Trigger fix (opens inline panel)"},
+				{"v", "Compare with other agents' reviews"},
+				{"enter", "Expand/collapse selected finding (failing reviews)"},
+				{"space", "Resolve/unresolve selected finding"},
+				{"s", "Toggle findings list / raw output"},
 				{"esc/q", "Back to queue"},
 			},
 		},
@@ -3804,7 +4126,7 @@ func helpLines() []string {
 				{"←/→", "Previous / next log"},
 				{"PgUp/PgDn", "Page through output"},
 				{"g", "Toggle follow mode / jump to top"},
-				{"x", "Cancel job"},
+				{"x", "Cancel running job (asks to confirm)"},
 				{"esc/q", "Back to queue"},
 			},
 		},
@@ -3812,6 +4134,8 @@ func helpLines() []string {
 			group: "Tasks View",
 			keys: []struct{ key, desc string }{
 				{"↑/↓", "Navigate fix jobs"},
+				{"v", "Cycle verdict filter"},
+				{"s/S", "Cycle sort column / reverse sort"},
 				{"A", "Apply patch from completed fix"},
 				{"R", "Re-trigger fix (rebase)"},
 				{"l", "View agent log"},
@@ -3977,7 +4301,20 @@ to the current branch. Use = syntax for explicit values:
 func (m tuiModel) renderTasksView() string {
 	var b strings.Builder
 
-	b.WriteString(tuiTitleStyle.Render("roborev tasks (background fixes)"))
+	title := "roborev tasks (background fixes)"
+	if label := m.verdictFilter.label(); label != "" {
+		title += fmt.Sprintf(" [v: %s]", label)
+	}
+	if m.fixAppliedOnly {
+		title += " [applied]"
+	}
+	if label := m.fixSort.label(); label != "" {
+		if m.fixSortReverse {
+			label += ", reversed"
+		}
+		title += fmt.Sprintf(" [s: %s]", label)
+	}
+	b.WriteString(tuiTitleStyle.Render(title))
 	b.WriteString("\x1b[K\n")
 
 	// Help overlay
@@ -3985,8 +4322,13 @@ func (m tuiModel) renderTasksView() string {
 		return m.renderTasksHelpOverlay(&b)
 	}
 
-	if len(m.fixJobs) == 0 {
-		b.WriteString("\n  No fix tasks. Press F on a review to trigger a background fix.\n")
+	visibleFixJobs := m.visibleFixJobIndices()
+	if len(visibleFixJobs) == 0 {
+		if len(m.fixJobs) == 0 {
+			b.WriteString("\n  No fix tasks. Press F on a review to trigger a background fix.\n")
+		} else {
+			b.WriteString("\n  No tasks match the filters. Press v or a to change them.\n")
+		}
 		b.WriteString("\n")
 		b.WriteString(renderHelpTable([][]string{
 			{"T: back to queue", "F: fix review", "q: quit"},
@@ -3998,7 +4340,7 @@ func (m tuiModel) renderTasksView() string {
 	// Column layout: status, job, parent are fixed; ref and subject split remaining space.
 	const statusW = 8                                     // "canceled" is the longest
 	const idW = 5                                         // "#" + 4-digit number
-	const parentW = 11                                    // "fixes #NNNN"
+	const parentW = 17                                    // "applied → abc1234" or "fixes #NNNN"
 	fixedW := 2 + statusW + 1 + idW + 1 + parentW + 1 + 1 // prefix + inter-column spaces
 	flexW := max(m.width-fixedW, 15)
 	// Ref gets 25% of flexible space, subject gets 75%
@@ -4015,17 +4357,18 @@ func (m tuiModel) renderTasksView() string {
 
 	// Render each fix job
 	tasksHelpRows := [][]string{
-		{"enter: view", "p: patch", "A: apply", "l: log", "x: cancel", "r: refresh", "?: help", "T/esc: back"},
+		{"enter: view", "p: patch", "A: apply", "l: log", "x: cancel", "r: refresh", "v: verdict", "a: applied", "s/S: sort", "?: help", "T/esc: back"},
 	}
 	tasksHelpLines := len(reflowHelpRows(tasksHelpRows, m.width))
 	visibleRows := m.height - (6 + tasksHelpLines) // title + header + separator + status + scroll + help(N)
 	visibleRows = max(visibleRows, 1)
-	startIdx := 0
-	if m.fixSelectedIdx >= visibleRows {
-		startIdx = m.fixSelectedIdx - visibleRows + 1
+	selectedPos := max(slices.Index(visibleFixJobs, m.fixSelectedIdx), 0)
+	startPos := 0
+	if selectedPos >= visibleRows {
+		startPos = selectedPos - visibleRows + 1
 	}
 
-	for i := startIdx; i < len(m.fixJobs) && i < startIdx+visibleRows; i++ {
+	for _, i := range visibleFixJobs[startPos:min(startPos+visibleRows, len(visibleFixJobs))] {
 		job := m.fixJobs[i]
 
 		// Status label
@@ -4056,7 +4399,10 @@ func (m tuiModel) renderTasksView() string {
 		}
 
 		parentRef := ""
-		if job.ParentJobID != nil {
+		switch {
+		case job.Status == storage.JobStatusApplied && job.AppliedCommitSHA != "":
+			parentRef = "applied → " + git.ShortSHA(job.AppliedCommitSHA)
+		case job.ParentJobID != nil:
 			parentRef = fmt.Sprintf("fixes #%d", *job.ParentJobID)
 		}
 		ref := job.GitRef
@@ -4111,6 +4457,10 @@ func (m tuiModel) renderTasksHelpOverlay(b *strings.Builder) string {
 		"    F          Trigger a new fix from a review (from queue view)",
 		"    x          Cancel a queued or running job",
 		"    r          Refresh the task list",
+		"    v          Cycle verdict filter (all/pass/fail/pending)",
+		"    a          Show only applied fixes and the commits they landed as",
+		"    s          Cycle sort (status, queued time, elapsed, repo, none)",
+		"    S          Reverse the sort",
 		"    T/esc      Return to the main queue view",
 		"    ?          Toggle this help",
 		"",
@@ -4133,20 +4483,26 @@ func (m tuiModel) renderTasksHelpOverlay(b *strings.Builder) string {
 // fetchPatch fetches the patch for a fix job from the daemon.
 func (m tuiModel) fetchPatch(jobID int64) tea.Cmd {
 	return func() tea.Msg {
-		url := m.serverAddr + fmt.Sprintf("/api/job/patch?job_id=%d", jobID)
-		resp, err := m.client.Get(url)
-		if err != nil {
-			return tuiPatchMsg{jobID: jobID, err: err}
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return tuiPatchMsg{jobID: jobID, err: fmt.Errorf("no patch available (HTTP %d)", resp.StatusCode)}
-		}
-		data, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return tuiPatchMsg{jobID: jobID, err: err}
+		patch, err := fetchJobPatch(context.Background(), m.serverAddr, jobID)
+		return tuiPatchMsg{jobID: jobID, patch: patch, err: err}
+	}
+}
+
+// savePatch writes the patch being viewed to roborev-fix-<jobID>.patch in
+// the root of the fix job's repo, falling back to the current repo.
+func (m tuiModel) savePatch() tea.Cmd {
+	dir := m.cwdRepoRoot
+	for _, job := range m.fixJobs {
+		if job.ID == m.patchJobID && job.RepoPath != "" {
+			dir = job.RepoPath
+			break
 		}
-		return tuiPatchMsg{jobID: jobID, patch: string(data)}
+	}
+	patch, jobID := m.patchText, m.patchJobID
+	return func() tea.Msg {
+		path := filepath.Join(dir, fmt.Sprintf("roborev-fix-%d.patch", jobID))
+		err := os.WriteFile(path, []byte(patch), 0o644)
+		return tuiPatchSavedMsg{path: path, err: err}
 	}
 }
 
@@ -4197,8 +4553,14 @@ func (m tuiModel) renderPatchView() string {
 		}
 	}
 
+	if m.flashMessage != "" && time.Now().Before(m.flashExpiresAt) && m.flashView == tuiViewPatch {
+		flashStyle := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "28", Dark: "46"})
+		b.WriteString(flashStyle.Render(m.flashMessage))
+		b.WriteString("\x1b[K\n")
+	}
+
 	b.WriteString(renderHelpTable([][]string{
-		{"j/k/up/down: scroll", "esc: back to tasks"},
+		{"j/k/up/down: scroll", "w: write to file", "esc: back to tasks"},
 	}, m.width))
 	b.WriteString("\x1b[K\x1b[J")
 	return b.String()
@@ -4356,25 +4718,10 @@ func (m tuiModel) applyFixPatchInWorktree(jobID int64) tea.Cmd {
 // fetchPatchAndJob fetches the patch content and job details for a fix job.
 // Returns nil msg on success; a non-nil msg should be returned to the TUI immediately.
 func (m tuiModel) fetchPatchAndJob(jobID int64) (string, *storage.ReviewJob, *tuiApplyPatchResultMsg) {
-	url := m.serverAddr + fmt.Sprintf("/api/job/patch?job_id=%d", jobID)
-	resp, err := m.client.Get(url)
+	patch, err := fetchJobPatch(context.Background(), m.serverAddr, jobID)
 	if err != nil {
 		return "", nil, &tuiApplyPatchResultMsg{jobID: jobID, err: err}
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", nil, &tuiApplyPatchResultMsg{jobID: jobID, err: fmt.Errorf("no patch available")}
-	}
-
-	patchData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", nil, &tuiApplyPatchResultMsg{jobID: jobID, err: err}
-	}
-	patch := string(patchData)
-	if patch == "" {
-		return "", nil, &tuiApplyPatchResultMsg{jobID: jobID, err: fmt.Errorf("empty patch")}
-	}
 
 	jobDetail, jErr := m.fetchJobByID(jobID)
 	if jErr != nil {
@@ -4432,8 +4779,9 @@ func (m tuiModel) checkApplyCommitPatch(jobID int64, jobDetail *storage.ReviewJo
 			commitFailed: true, err: fmt.Errorf("patch applied but commit failed: %w", err)}
 	}
 
-	// Mark the fix job as applied on the server
-	if err := m.postJSON("/api/job/applied", map[string]any{"job_id": jobID}, nil); err != nil {
+	// Mark the fix job as applied on the server, recording the new commit
+	commitSHA, _ := git.ResolveSHA(targetDir, "HEAD")
+	if err := m.postJSON("/api/job/applied", map[string]any{"job_id": jobID, "commit_sha": commitSHA}, nil); err != nil {
 		return tuiApplyPatchResultMsg{jobID: jobID, parentJobID: parentJobID, success: true,
 			err: fmt.Errorf("patch applied and committed but failed to mark applied: %w", err)}
 	}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -818,6 +820,91 @@ func TestTUIRespondSuccessClearsOnlyMatchingJob(t *testing.T) {
 	}
 }
 
+func TestTUICommentEditKey(t *testing.T) {
+	t.Setenv("USER", "alice")
+
+	var gotPath string
+	var gotReq map[string]any
+	_, m := mockServerModel(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+	})
+	m.width = 80
+	m.height = 24
+	m.currentView = tuiViewReview
+	m.currentReview = &storage.Review{JobID: 1, Job: &storage.ReviewJob{ID: 1, GitRef: "abc1234"}}
+	m.currentResponses = []storage.Response{
+		{ID: 10, Responder: "alice", Response: "Older note"},
+		{ID: 11, Responder: "alice", Response: "Latest note"},
+		{ID: 12, Responder: "bob", Response: "Someone else"},
+	}
+
+	// C opens the modal on the user's most recent comment
+	m, _ = pressKey(m, 'C')
+	if m.currentView != tuiViewComment {
+		t.Fatalf("Expected tuiViewComment, got %v", m.currentView)
+	}
+	if m.commentEditID != 11 || m.commentText != "Latest note" {
+		t.Fatalf("Expected editing comment 11 with its text, got id=%d text=%q", m.commentEditID, m.commentText)
+	}
+	if !strings.Contains(stripANSI(m.renderRespondView()), "Edit Comment") {
+		t.Error("Expected Edit Comment title")
+	}
+
+	// Enter saves the edit through the update endpoint
+	m.commentText = "Edited note"
+	m, cmd := pressSpecial(m, tea.KeyEnter)
+	if cmd == nil {
+		t.Fatal("Expected a command from enter")
+	}
+	if msg, ok := cmd().(tuiCommentResultMsg); !ok || msg.err != nil {
+		t.Fatalf("Expected successful tuiCommentResultMsg, got %#v", msg)
+	}
+	if gotPath != "/api/comment/update" || gotReq["id"].(float64) != 11 || gotReq["commenter"] != "alice" || gotReq["comment"] != "Edited note" {
+		t.Errorf("Unexpected update request %s %v", gotPath, gotReq)
+	}
+
+	// ctrl+d deletes the comment being edited
+	m, _ = pressKey(m, 'C')
+	m, cmd = pressSpecial(m, tea.KeyCtrlD)
+	if m.currentView != tuiViewReview {
+		t.Errorf("Expected return to review view, got %v", m.currentView)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command from ctrl+d")
+	}
+	cmd()
+	if gotPath != "/api/comment/delete" || gotReq["id"].(float64) != 11 {
+		t.Errorf("Unexpected delete request %s %v", gotPath, gotReq)
+	}
+
+	// c after an abandoned edit starts a fresh comment
+	m, _ = pressKey(m, 'C')
+	m, _ = pressSpecial(m, tea.KeyEsc)
+	m, _ = pressKey(m, 'c')
+	if m.commentEditID != 0 || m.commentText != "" {
+		t.Errorf("Expected a fresh comment, got id=%d text=%q", m.commentEditID, m.commentText)
+	}
+}
+
+func TestTUICommentEditKeyNoOwnComment(t *testing.T) {
+	t.Setenv("USER", "alice")
+	m := setupTestModel(nil, func(m *tuiModel) {
+		m.currentView = tuiViewReview
+		m.currentReview = &storage.Review{JobID: 1}
+		m.currentResponses = []storage.Response{{ID: 12, Responder: "bob", Response: "Someone else"}}
+	})
+
+	m, _ = pressKey(m, 'C')
+	if m.currentView != tuiViewReview {
+		t.Errorf("Expected to stay in review view, got %v", m.currentView)
+	}
+	if m.flashMessage != "No comment of yours to edit" {
+		t.Errorf("Expected flash message, got %q", m.flashMessage)
+	}
+}
+
 func TestTUIRespondBackspaceMultiByte(t *testing.T) {
 	m := newTuiModel("http://localhost")
 	m.currentView = tuiViewComment
@@ -1059,3 +1146,38 @@ func withStartedAt(t time.Time) func(*storage.ReviewJob) {
 func withFinishedAt(t *time.Time) func(*storage.ReviewJob) {
 	return func(j *storage.ReviewJob) { j.FinishedAt = t }
 }
+
+func TestTUIPatchViewWriteToFile(t *testing.T) {
+	dir := t.TempDir()
+	patch := "--- a/greet.txt\n+++ b/greet.txt\n@@ -1 +1 @@\n-hello\n+hello, world\n"
+
+	m := newTuiModel("http://localhost")
+	m.currentView = tuiViewPatch
+	m.fixJobs = []storage.ReviewJob{makeJob(7, withRepoPath(dir))}
+	m.patchJobID = 7
+	m.patchText = patch
+
+	m, cmd := pressKey(m, 'w')
+	if cmd == nil {
+		t.Fatal("expected w to return a write command")
+	}
+	m, _ = updateModel(t, m, cmd())
+
+	path := filepath.Join(dir, "roborev-fix-7.patch")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read written patch: %v", err)
+	}
+	if string(data) != patch {
+		t.Errorf("written patch = %q, want %q", data, patch)
+	}
+	if m.flashMessage != "Wrote "+path || m.flashView != tuiViewPatch {
+		t.Errorf("flash = %q in view %v", m.flashMessage, m.flashView)
+	}
+
+	// Nothing to write without a patch
+	m.patchText = ""
+	if _, cmd := pressKey(m, 'w'); cmd != nil {
+		t.Error("expected no command when there is no patch")
+	}
+}
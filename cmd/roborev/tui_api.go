@@ -1,36 +1,23 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+
+	"github.com/roborev-dev/roborev/internal/daemon"
 )
 
 // errNotFound is returned by getJSON/postJSON for 404 responses.
 // Callers can detect it with errors.Is(err, errNotFound).
 var errNotFound = errors.New("not found")
 
-// readErrorBody reads a JSON error response body and returns the "error" field,
+// readErrorBody reads a JSON error response body and returns its message,
 // falling back to the raw body text or HTTP status.
 func readErrorBody(body io.Reader, status string) string {
-	data, err := io.ReadAll(io.LimitReader(body, 1024))
-	if err != nil || len(data) == 0 {
-		return status
-	}
-	var errResp struct {
-		Error string `json:"error"`
-	}
-	if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
-		return errResp.Error
-	}
-	if s := strings.TrimSpace(string(data)); s != "" {
-		return s
-	}
-	return status
+	return daemon.ReadErrorBody(body, status)
 }
 
 // getJSON performs a GET request and decodes the JSON response into out.
@@ -65,7 +52,7 @@ func (m tuiModel) postJSON(path string, in any, out any) error {
 		return fmt.Errorf("marshal request: %w", err)
 	}
 
-	resp, err := m.client.Post(m.serverAddr+path, "application/json", bytes.NewReader(body))
+	resp, err := daemon.PostWithRetry(m.client, m.serverAddr+path, body)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+// compareMinColumnWidth is the narrowest column the comparison view renders
+// side by side; below it the view shows one review at a time as tabs.
+const compareMinColumnWidth = 32
+
+// tuiCompareUniqueStyle highlights findings only one sibling reported.
+var tuiCompareUniqueStyle = lipgloss.NewStyle().Bold(true).
+	Foreground(lipgloss.AdaptiveColor{Light: "136", Dark: "226"}) // Yellow/Gold
+
+type tuiComparisonMsg struct {
+	jobID   int64 // The job the comparison was requested for
+	reviews []storage.Review
+	err     error
+}
+
+// comparisonFinding is a finding from one review in a comparison group.
+// unique is set when no sibling review reported the same finding.
+type comparisonFinding struct {
+	storage.Finding
+	unique bool
+}
+
+// comparisonColumn holds one sibling review for the comparison view.
+type comparisonColumn struct {
+	jobID    int64
+	agent    string
+	model    string
+	verdict  string // "P", "F", or "" when unknown
+	summary  string
+	findings []comparisonFinding
+}
+
+// findingLocationPattern matches file references such as "pkg/db.go:42".
+var findingLocationPattern = regexp.MustCompile(`(?:[\w.-]+/)*[\w-]+\.[A-Za-z][\w]{0,7}(?::\d+)?`)
+
+// findingKeys returns the keys used to match a finding across reviews: its
+// normalized text and, when it names a file with a path or line, that
+// location. Agents word the same issue differently, so a shared location
+// counts as the same finding.
+func findingKeys(f storage.Finding) []string {
+	text := strings.ToLower(f.Text)
+	keys := []string{"text:" + strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	}), " ")}
+	for _, loc := range findingLocationPattern.FindAllString(text, -1) {
+		if strings.ContainsAny(loc, "/:") {
+			keys = append(keys, "loc:"+loc)
+			break
+		}
+	}
+	return keys
+}
+
+// buildComparison assembles comparison columns from a comparison group,
+// flagging findings that no other review in the group reported.
+func buildComparison(reviews []storage.Review) []comparisonColumn {
+	columns := make([]comparisonColumn, len(reviews))
+	keys := make([][][]string, len(reviews))
+	// owners counts, per key, how many reviews reported it
+	owners := make(map[string]int)
+	for i, r := range reviews {
+		col := comparisonColumn{jobID: r.JobID, agent: r.ProducedBy(), summary: r.Summary}
+		if r.Job != nil {
+			if r.ProducedBy() == r.Agent { // A fallback agent ran without the job's model
+				col.model = r.Job.Model
+			}
+			if r.Job.Verdict != nil {
+				col.verdict = *r.Job.Verdict
+			}
+		}
+		seen := make(map[string]bool)
+		for _, f := range storage.ExtractFindings(r.Output) {
+			col.findings = append(col.findings, comparisonFinding{Finding: f})
+			fk := findingKeys(f)
+			keys[i] = append(keys[i], fk)
+			for _, k := range fk {
+				if !seen[k] {
+					seen[k] = true
+					owners[k]++
+				}
+			}
+		}
+		columns[i] = col
+	}
+	for i := range columns {
+		for j := range columns[i].findings {
+			unique := true
+			for _, k := range keys[i][j] {
+				if owners[k] > 1 {
+					unique = false
+					break
+				}
+			}
+			columns[i].findings[j].unique = unique && len(reviews) > 1
+		}
+	}
+	return columns
+}
+
+// comparisonLine is a line of comparison view content with its style.
+type comparisonLine struct {
+	text  string
+	style *lipgloss.Style
+}
+
+// comparisonColumnLines renders one column's content wrapped to width.
+func comparisonColumnLines(col comparisonColumn, width int) []comparisonLine {
+	var lines []comparisonLine
+	add := func(text string, style *lipgloss.Style) {
+		for _, l := range wrapText(text, width) {
+			lines = append(lines, comparisonLine{text: l, style: style})
+		}
+	}
+
+	switch col.verdict {
+	case "P":
+		add("Verdict: Pass", &tuiPassStyle)
+	case "F":
+		add("Verdict: Fail", &tuiFailStyle)
+	}
+	if len(col.findings) == 0 {
+		summary := col.summary
+		if summary == "" {
+			summary = "No findings"
+		}
+		add(summary, &tuiStatusStyle)
+		return lines
+	}
+	for _, f := range col.findings {
+		lines = append(lines, comparisonLine{})
+		text := sanitizeForDisplay(f.Text)
+		if f.unique {
+			add("* "+text, &tuiCompareUniqueStyle)
+		} else {
+			add(text, nil)
+		}
+	}
+	return lines
+}
+
+// comparisonHeader returns the label identifying a column's review.
+func comparisonHeader(col comparisonColumn) string {
+	if col.model != "" {
+		return fmt.Sprintf("#%d %s (%s)", col.jobID, col.agent, col.model)
+	}
+	return fmt.Sprintf("#%d %s", col.jobID, col.agent)
+}
+
+// padCell truncates or pads s to exactly width display cells.
+func padCell(s string, width int) string {
+	s = runewidth.Truncate(s, width, "")
+	return s + strings.Repeat(" ", width-runewidth.StringWidth(s))
+}
+
+func (l comparisonLine) render(width int) string {
+	cell := padCell(l.text, width)
+	if l.style != nil {
+		return l.style.Render(cell)
+	}
+	return cell
+}
+
+func (m tuiModel) fetchComparison(jobID int64) tea.Cmd {
+	return func() tea.Msg {
+		var result struct {
+			Reviews []storage.Review `json:"reviews"`
+		}
+		if err := m.getJSON(fmt.Sprintf("/api/review/comparison?job_id=%d", jobID), &result); err != nil {
+			return tuiComparisonMsg{jobID: jobID, err: err}
+		}
+		return tuiComparisonMsg{jobID: jobID, reviews: result.Reviews}
+	}
+}
+
+func (m tuiModel) handleCompareOpenKey() (tea.Model, tea.Cmd) {
+	if m.currentView != tuiViewReview || m.currentReview == nil || m.currentReview.Job == nil {
+		return m, nil
+	}
+	job := m.currentReview.Job
+	if job.JobType != storage.JobTypeReview && job.JobType != storage.JobTypeRange {
+		m.flashMessage = "Only commit and range reviews can be compared"
+		m.flashExpiresAt = time.Now().Add(2 * time.Second)
+		m.flashView = tuiViewReview
+		return m, nil
+	}
+	return m, m.fetchComparison(job.ID)
+}
+
+func (m tuiModel) handleComparisonMsg(msg tuiComparisonMsg) (tea.Model, tea.Cmd) {
+	if m.currentView != tuiViewReview || m.currentReview == nil || m.currentReview.JobID != msg.jobID {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.flashMessage = fmt.Sprintf("Comparison failed: %v", msg.err)
+		m.flashExpiresAt = time.Now().Add(3 * time.Second)
+		m.flashView = tuiViewReview
+		return m, nil
+	}
+	if len(msg.reviews) < 2 {
+		m.flashMessage = "No other reviews of this ref to compare"
+		m.flashExpiresAt = time.Now().Add(2 * time.Second)
+		m.flashView = tuiViewReview
+		return m, nil
+	}
+	m.compareColumns = buildComparison(msg.reviews)
+	m.compareIdx = 0
+	for i, col := range m.compareColumns {
+		if col.jobID == msg.jobID {
+			m.compareIdx = i
+		}
+	}
+	m.compareScroll = 0
+	m.currentView = tuiViewCompare
+	return m, nil
+}
+
+func (m tuiModel) handleCompareKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "v":
+		m.currentView = tuiViewReview
+		m.compareColumns = nil
+		m.compareScroll = 0
+		return m, nil
+	case "left", "h":
+		if m.compareIdx > 0 {
+			m.compareIdx--
+		}
+		if !m.compareSideBySide() {
+			m.compareScroll = 0
+		}
+		return m, nil
+	case "right", "l", "tab":
+		if m.compareIdx < len(m.compareColumns)-1 {
+			m.compareIdx++
+		}
+		if !m.compareSideBySide() {
+			m.compareScroll = 0
+		}
+		return m, nil
+	case "up", "k":
+		if m.compareScroll > 0 {
+			m.compareScroll--
+		}
+		return m, nil
+	case "down", "j":
+		m.compareScroll++
+		return m, nil
+	case "pgup":
+		m.compareScroll = max(0, m.compareScroll-m.compareVisibleLines())
+		return m, tea.ClearScreen
+	case "pgdown":
+		m.compareScroll += m.compareVisibleLines()
+		return m, tea.ClearScreen
+	case "home", "g":
+		m.compareScroll = 0
+		return m, nil
+	}
+	return m, nil
+}
+
+// compareSideBySide reports whether every review fits in its own column.
+func (m tuiModel) compareSideBySide() bool {
+	n := len(m.compareColumns)
+	return n > 0 && (m.width-3*(n-1))/n >= compareMinColumnWidth
+}
+
+// compareVisibleLines is the number of content rows in the comparison view:
+// title, column header, scroll indicator, and help take the rest.
+func (m tuiModel) compareVisibleLines() int {
+	return max(m.height-5, 1)
+}
+
+func (m tuiModel) renderCompareView() string {
+	var b strings.Builder
+
+	ref := ""
+	if m.currentReview != nil && m.currentReview.Job != nil {
+		ref = " " + shortRef(m.currentReview.Job.GitRef)
+	}
+	b.WriteString(tuiTitleStyle.Render(fmt.Sprintf("Compare reviews%s (%d agents)", ref, len(m.compareColumns))))
+	b.WriteString("\x1b[K\n")
+
+	var headers []string
+	var body [][]string
+	if m.compareSideBySide() {
+		n := len(m.compareColumns)
+		width := (m.width - 3*(n-1)) / n
+		columns := make([][]comparisonLine, n)
+		rows := 0
+		for i, col := range m.compareColumns {
+			columns[i] = comparisonColumnLines(col, width)
+			rows = max(rows, len(columns[i]))
+			header := padCell(comparisonHeader(col), width)
+			if i == m.compareIdx {
+				header = tuiSelectedStyle.Render(header)
+			}
+			headers = append(headers, header)
+		}
+		for r := range rows {
+			cells := make([]string, n)
+			for i := range columns {
+				var line comparisonLine
+				if r < len(columns[i]) {
+					line = columns[i][r]
+				}
+				cells[i] = line.render(width)
+			}
+			body = append(body, cells)
+		}
+	} else {
+		for i, col := range m.compareColumns {
+			tab := " " + comparisonHeader(col) + " "
+			if i == m.compareIdx {
+				tab = tuiSelectedStyle.Render(tab)
+			}
+			headers = append(headers, tab)
+		}
+		width := max(m.width, 20)
+		for _, line := range comparisonColumnLines(m.compareColumns[m.compareIdx], width) {
+			body = append(body, []string{line.render(width)})
+		}
+	}
+
+	separator := tuiStatusStyle.Render(" │ ")
+	if !m.compareSideBySide() {
+		separator = " "
+	}
+	header := strings.Join(headers, separator)
+	if !m.compareSideBySide() {
+		header = xansi.Truncate(header, m.width, "")
+	}
+	b.WriteString(header)
+	b.WriteString("\x1b[K\n")
+
+	visible := m.compareVisibleLines()
+	maxScroll := max(len(body)-visible, 0)
+	start := max(min(m.compareScroll, maxScroll), 0)
+	end := min(start+visible, len(body))
+	linesWritten := 0
+	for _, cells := range body[start:end] {
+		b.WriteString(strings.Join(cells, tuiStatusStyle.Render(" │ ")))
+		b.WriteString("\x1b[K\n")
+		linesWritten++
+	}
+	for linesWritten < visible {
+		b.WriteString("\x1b[K\n")
+		linesWritten++
+	}
+
+	if len(body) > visible {
+		b.WriteString(tuiStatusStyle.Render(fmt.Sprintf("[%d-%d of %d lines]", start+1, end, len(body))))
+	} else {
+		b.WriteString(tuiStatusStyle.Render("* unique to this review"))
+	}
+	b.WriteString("\x1b[K\n")
+
+	b.WriteString(renderHelpTable([][]string{
+		{"←/→: switch review", "↑/↓: scroll", "esc: back"},
+	}, m.width))
+	b.WriteString("\x1b[K\x1b[J")
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+// comparisonReview builds a completed review for comparison tests.
+func comparisonReview(jobID int64, agent, model, verdict, output string) storage.Review {
+	job := makeJob(jobID, withAgent(agent), withRef("abc1234"))
+	job.Model = model
+	job.JobType = storage.JobTypeReview
+	job.Verdict = &verdict
+	return *makeReview(jobID*10, &job, func(r *storage.Review) {
+		r.Agent = agent
+		r.Output = output
+	})
+}
+
+func comparisonFixture() []storage.Review {
+	return []storage.Review{
+		comparisonReview(1, "codex", "o3", "F",
+			"- High: SQL injection in internal/db/query.go:42\n\n- Low: typo in README"),
+		comparisonReview(2, "claude-code", "", "F",
+			"- Medium: query built by concatenation at internal/db/query.go:42\n\n- Medium: goroutine leak in worker.go:88"),
+		comparisonReview(3, "gemini", "", "P", "No issues found."),
+	}
+}
+
+func TestBuildComparison(t *testing.T) {
+	columns := buildComparison(comparisonFixture())
+	if len(columns) != 3 {
+		t.Fatalf("expected 3 columns, got %d", len(columns))
+	}
+
+	codex, claude, gemini := columns[0], columns[1], columns[2]
+	if codex.jobID != 1 || codex.agent != "codex" || codex.model != "o3" || codex.verdict != "F" {
+		t.Errorf("unexpected codex column: %+v", codex)
+	}
+	if gemini.verdict != "P" || len(gemini.findings) != 0 {
+		t.Errorf("unexpected gemini column: %+v", gemini)
+	}
+
+	type want struct {
+		severity string
+		unique   bool
+	}
+	check := func(col comparisonColumn, wants []want) {
+		t.Helper()
+		if len(col.findings) != len(wants) {
+			t.Fatalf("%s: expected %d findings, got %+v", col.agent, len(wants), col.findings)
+		}
+		for i, w := range wants {
+			f := col.findings[i]
+			if f.Severity != w.severity || f.unique != w.unique {
+				t.Errorf("%s finding %d = {%s unique=%v}, want {%s unique=%v}: %q",
+					col.agent, i, f.Severity, f.unique, w.severity, w.unique, f.Text)
+			}
+		}
+	}
+	// The query.go:42 finding is worded differently but shares a location.
+	check(codex, []want{{"high", false}, {"low", true}})
+	check(claude, []want{{"medium", false}, {"medium", true}})
+}
+
+func TestBuildComparisonSingleReviewHasNoUniqueFindings(t *testing.T) {
+	columns := buildComparison(comparisonFixture()[:1])
+	for _, f := range columns[0].findings {
+		if f.unique {
+			t.Errorf("finding %q marked unique without siblings", f.Text)
+		}
+	}
+}
+
+func TestTUIComparisonView(t *testing.T) {
+	fixture := comparisonFixture()
+	m := newTuiModel("http://localhost")
+	m.width = 120
+	m.height = 30
+	m.currentView = tuiViewReview
+	m.currentReview = &fixture[1]
+
+	m, _ = updateModel(t, m, tuiComparisonMsg{jobID: 2, reviews: fixture})
+	if m.currentView != tuiViewCompare {
+		t.Fatalf("expected compare view, got %v", m.currentView)
+	}
+	if m.compareIdx != 1 {
+		t.Errorf("expected focus on the current review's column, got %d", m.compareIdx)
+	}
+
+	out := stripANSI(m.View())
+	for _, want := range []string{"#1 codex (o3)", "#2 claude-code", "#3 gemini", "Verdict: Pass", "* - Low: typo in README"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("side-by-side view missing %q:\n%s", want, out)
+		}
+	}
+
+	m, _ = pressSpecial(m, tea.KeyRight)
+	m, _ = pressSpecial(m, tea.KeyRight)
+	if m.compareIdx != 2 {
+		t.Errorf("expected right to stop at last column, got %d", m.compareIdx)
+	}
+	m, _ = pressSpecial(m, tea.KeyLeft)
+	if m.compareIdx != 1 {
+		t.Errorf("expected left to move focus, got %d", m.compareIdx)
+	}
+
+	// Narrow terminals show one review at a time.
+	m.width = 60
+	out = stripANSI(m.View())
+	if !strings.Contains(out, "goroutine leak") || strings.Contains(out, "typo in README") {
+		t.Errorf("tab view should show only the focused review:\n%s", out)
+	}
+
+	m, _ = pressSpecial(m, tea.KeyEsc)
+	if m.currentView != tuiViewReview || m.compareColumns != nil {
+		t.Errorf("esc should return to review view and clear comparison state")
+	}
+}
+
+func TestTUIComparisonMsgWithoutSiblings(t *testing.T) {
+	fixture := comparisonFixture()
+	m := newTuiModel("http://localhost")
+	m.currentView = tuiViewReview
+	m.currentReview = &fixture[0]
+
+	m, _ = updateModel(t, m, tuiComparisonMsg{jobID: 1, reviews: fixture[:1]})
+	if m.currentView != tuiViewReview || !strings.Contains(m.flashMessage, "No other reviews") {
+		t.Errorf("expected flash in review view, got view=%v flash=%q", m.currentView, m.flashMessage)
+	}
+
+	m, _ = updateModel(t, m, tuiComparisonMsg{jobID: 1, err: errors.New("boom")})
+	if !strings.Contains(m.flashMessage, "boom") {
+		t.Errorf("expected error flash, got %q", m.flashMessage)
+	}
+
+	// A response for a review the user has left is ignored.
+	m.flashMessage = ""
+	m, _ = updateModel(t, m, tuiComparisonMsg{jobID: 99, reviews: fixture})
+	if m.currentView != tuiViewReview || m.flashMessage != "" {
+		t.Errorf("stale comparison response should be ignored")
+	}
+}
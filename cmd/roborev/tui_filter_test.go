@@ -3,8 +3,10 @@ package main
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/roborev-dev/roborev/internal/storage"
@@ -3112,3 +3114,367 @@ func TestTUIEscapeWithLockedFilters(t *testing.T) {
 		t.Errorf("Locked branch cleared by second escape: %q", m3.activeBranchFilter)
 	}
 }
+
+func withVerdict(v string) func(*storage.ReviewJob) {
+	return func(j *storage.ReviewJob) { j.Verdict = &v }
+}
+
+func TestTUIVerdictFilterCycle(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.width, m.height = 120, 30
+	m.currentView = tuiViewQueue
+	m.jobs = []storage.ReviewJob{
+		makeJob(1, withVerdict("P")),
+		makeJob(2, withVerdict("F")),
+		makeJob(3, withStatus(storage.JobStatusRunning)),
+		makeJob(4, withVerdict("P")),
+	}
+	m.selectedIdx = 0
+	m.selectedJobID = 1
+
+	steps := []struct {
+		filter     verdictFilter
+		visible    []int64
+		selectedID int64
+		header     string
+	}{
+		{verdictFilterPass, []int64{1, 4}, 1, "[v: pass]"},
+		{verdictFilterFail, []int64{2}, 2, "[v: fail]"},
+		{verdictFilterPending, []int64{3}, 3, "[v: pending]"},
+		{verdictFilterAll, []int64{1, 2, 3, 4}, 3, ""},
+	}
+	for _, step := range steps {
+		m, _ = pressKey(m, 'v')
+		if m.verdictFilter != step.filter {
+			t.Fatalf("verdictFilter = %v, want %v", m.verdictFilter, step.filter)
+		}
+		var ids []int64
+		for _, job := range m.getVisibleJobs() {
+			ids = append(ids, job.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(step.visible) {
+			t.Errorf("filter %q: visible = %v, want %v", step.filter.label(), ids, step.visible)
+		}
+		if m.selectedJobID != step.selectedID {
+			t.Errorf("filter %q: selected job %d, want %d", step.filter.label(), m.selectedJobID, step.selectedID)
+		}
+		title := strings.SplitN(stripANSI(m.renderQueueView()), "\n", 2)[0]
+		if step.header != "" && !strings.Contains(title, step.header) {
+			t.Errorf("header %q missing %q", title, step.header)
+		}
+		if step.header == "" && strings.Contains(title, "[v:") {
+			t.Errorf("header %q should not show a verdict filter", title)
+		}
+	}
+
+	// Esc clears an active verdict filter without a refetch
+	m.verdictFilter = verdictFilterFail
+	m, cmd := pressSpecial(m, tea.KeyEscape)
+	if m.verdictFilter != verdictFilterAll {
+		t.Errorf("expected esc to clear the verdict filter, got %v", m.verdictFilter)
+	}
+	if cmd != nil {
+		t.Error("expected no refetch when clearing the verdict filter")
+	}
+}
+
+func TestTUIVerdictFilterTasksView(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.width, m.height = 120, 30
+	m.currentView = tuiViewTasks
+	m.fixJobs = []storage.ReviewJob{
+		makeJob(10, withStatus(storage.JobStatusRunning)),
+		makeJob(11, withVerdict("P")),
+		makeJob(12),
+	}
+	m.fixSelectedIdx = 1
+
+	// Pending hides the selected job; selection snaps to the next visible one
+	m.verdictFilter = verdictFilterFail
+	m, _ = pressKey(m, 'v')
+	if m.verdictFilter != verdictFilterPending {
+		t.Fatalf("verdictFilter = %v, want pending", m.verdictFilter)
+	}
+	if m.fixSelectedIdx != 2 {
+		t.Errorf("fixSelectedIdx = %d, want 2", m.fixSelectedIdx)
+	}
+	m, _ = pressKey(m, 'k')
+	if m.fixSelectedIdx != 0 {
+		t.Errorf("up should skip the hidden job, got fixSelectedIdx %d", m.fixSelectedIdx)
+	}
+	out := stripANSI(m.renderTasksView())
+	if !strings.Contains(out, "[v: pending]") || strings.Contains(out, "#11") {
+		t.Errorf("tasks view should show the filter and hide #11:\n%s", out)
+	}
+
+	// Fail matches nothing: the view says so and row actions are ignored
+	m.verdictFilter = verdictFilterPass
+	m, _ = pressKey(m, 'v')
+	out = stripANSI(m.renderTasksView())
+	if !strings.Contains(out, "No tasks match the filters") {
+		t.Errorf("expected empty-filter message:\n%s", out)
+	}
+	if _, cmd := pressKey(m, 'x'); cmd != nil {
+		t.Error("expected no action on a hidden task")
+	}
+}
+
+func TestTUIAppliedFilterTasksView(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.width, m.height = 120, 30
+	m.currentView = tuiViewTasks
+	m.fixJobs = []storage.ReviewJob{
+		makeJob(20, withStatus(storage.JobStatusDone)),
+		makeJob(21, withStatus(storage.JobStatusApplied)),
+		makeJob(22, withStatus(storage.JobStatusRebased)),
+	}
+	m.fixJobs[1].AppliedCommitSHA = "abc1234def5678"
+	m.fixSelectedIdx = 0
+
+	m, _ = pressKey(m, 'a')
+	if !m.fixAppliedOnly {
+		t.Fatal("expected a to enable the applied filter")
+	}
+	if m.fixSelectedIdx != 1 {
+		t.Errorf("fixSelectedIdx = %d, want 1", m.fixSelectedIdx)
+	}
+	out := stripANSI(m.renderTasksView())
+	if !strings.Contains(out, "[applied]") || strings.Contains(out, "#20") {
+		t.Errorf("tasks view should show the filter and hide #20:\n%s", out)
+	}
+	if !strings.Contains(out, "applied → abc1234") {
+		t.Errorf("expected applied commit in the Parent column:\n%s", out)
+	}
+	if !strings.Contains(out, "#22") {
+		t.Errorf("rebased fixes should stay visible:\n%s", out)
+	}
+
+	m, _ = pressKey(m, 'a')
+	if m.fixAppliedOnly {
+		t.Error("expected a to toggle the applied filter off")
+	}
+	if out := stripANSI(m.renderTasksView()); !strings.Contains(out, "#20") {
+		t.Errorf("expected all tasks after toggling off:\n%s", out)
+	}
+}
+
+func TestTUIHideRemoteToggle(t *testing.T) {
+	remote := func(j *storage.ReviewJob) { j.SourceMachineID = "other-machine" }
+	m := newTuiModel("http://localhost")
+	m.width, m.height = 160, 30
+	m.currentView = tuiViewQueue
+	m.status.MachineID = "this-machine"
+	m.status.MachineIDs = []string{"other-machine", "this-machine"}
+	m.jobs = []storage.ReviewJob{
+		makeJob(1, remote),
+		makeJob(2, func(j *storage.ReviewJob) { j.SourceMachineID = "this-machine" }),
+		makeJob(3),
+	}
+	m.selectedIdx = 0
+	m.selectedJobID = 1
+
+	out := stripANSI(m.renderQueueView())
+	if !strings.Contains(out, "[R]") {
+		t.Errorf("expected remote marker in queue view:\n%s", out)
+	}
+	if !strings.Contains(out, "o: local") {
+		t.Errorf("expected o: local in help row when remote jobs exist:\n%s", out)
+	}
+
+	m, cmd := pressKey(m, 'o')
+	if !m.hideRemote {
+		t.Fatal("expected o to hide remote jobs")
+	}
+	if cmd == nil {
+		t.Error("expected a refetch with local_only")
+	}
+	var ids []int64
+	for _, job := range m.getVisibleJobs() {
+		ids = append(ids, job.ID)
+	}
+	if fmt.Sprint(ids) != "[2 3]" {
+		t.Errorf("visible = %v, want [2 3]", ids)
+	}
+	if m.selectedJobID != 2 {
+		t.Errorf("selected job %d, want 2 after hiding the remote job", m.selectedJobID)
+	}
+	title := strings.SplitN(stripANSI(m.renderQueueView()), "\n", 2)[0]
+	if !strings.Contains(title, "[local only]") {
+		t.Errorf("header %q missing [local only]", title)
+	}
+
+	m, _ = pressSpecial(m, tea.KeyEscape)
+	if m.hideRemote {
+		t.Error("expected esc to clear the remote filter")
+	}
+}
+
+func TestTUIHideRemoteHelpHiddenWithoutRemoteMachines(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.status.MachineID = "this-machine"
+	m.status.MachineIDs = []string{"this-machine"}
+	for _, row := range m.queueHelpRows() {
+		if slices.Contains(row, "o: local") {
+			t.Error("o: local should only be offered when another machine has jobs")
+		}
+	}
+}
+
+func TestTUIQueueSearch(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.width, m.height = 120, 30
+	m.currentView = tuiViewQueue
+	m.jobs = []storage.ReviewJob{
+		makeJob(1, withRepoName("api"), withBranch("main"), withAgent("codex")),
+		makeJob(2, withRepoName("web"), withBranch("feature/login"), withAgent("claude-code")),
+		makeJob(3, withRepoName("worker"), withBranch("main"), withAgent("codex")),
+	}
+	m.jobs[2].CommitSubject = "Fix Login retries"
+	m.selectedIdx = 0
+	m.selectedJobID = 1
+
+	visibleIDs := func(m tuiModel) string {
+		var ids []int64
+		for _, job := range m.getVisibleJobs() {
+			ids = append(ids, job.ID)
+		}
+		return fmt.Sprint(ids)
+	}
+
+	m, _ = pressKey(m, '/')
+	if !m.queueSearchTyping {
+		t.Fatal("expected / to focus the search input")
+	}
+	// While typing, letters are search text rather than queue shortcuts
+	for _, r := range "LOGIN" {
+		m, _ = pressKey(m, r)
+	}
+	if m.queueSearch != "LOGIN" {
+		t.Fatalf("queueSearch = %q, want LOGIN", m.queueSearch)
+	}
+	// Matches branch (job 2) and commit subject (job 3), case-insensitively
+	if got := visibleIDs(m); got != "[2 3]" {
+		t.Errorf("visible = %s, want [2 3]", got)
+	}
+	if m.selectedJobID != 2 {
+		t.Errorf("selection should move to the first match, got job %d", m.selectedJobID)
+	}
+	status := strings.Split(stripANSI(m.renderQueueView()), "\n")[1]
+	if !strings.Contains(status, "Search: /LOGIN_") {
+		t.Errorf("status line %q missing search input", status)
+	}
+
+	// Backspace widens the match; the agent field is searched too
+	for range "LOGIN" {
+		m, _ = pressSpecial(m, tea.KeyBackspace)
+	}
+	for _, r := range "codex" {
+		m, _ = pressKey(m, r)
+	}
+	if got := visibleIDs(m); got != "[1 3]" {
+		t.Errorf("visible = %s, want [1 3]", got)
+	}
+
+	// Enter keeps the search and returns keys to the queue
+	m, _ = pressSpecial(m, tea.KeyEnter)
+	if m.queueSearchTyping || m.queueSearch != "codex" {
+		t.Fatalf("after enter: typing=%v search=%q", m.queueSearchTyping, m.queueSearch)
+	}
+	m, _ = pressKey(m, 'j')
+	if m.selectedJobID != 3 {
+		t.Errorf("j should move to the next match, got job %d", m.selectedJobID)
+	}
+	status = strings.Split(stripANSI(m.renderQueueView()), "\n")[1]
+	if !strings.Contains(status, "Search: /codex (esc: clear)") {
+		t.Errorf("status line %q missing active search", status)
+	}
+
+	// Esc clears the search without a refetch
+	m, cmd := pressSpecial(m, tea.KeyEscape)
+	if m.queueSearch != "" || cmd != nil {
+		t.Errorf("esc: search=%q cmd=%v, want cleared with no refetch", m.queueSearch, cmd)
+	}
+	if got := visibleIDs(m); got != "[1 2 3]" {
+		t.Errorf("visible = %s, want all jobs", got)
+	}
+
+	// Esc while typing also clears and closes the input
+	m, _ = pressKey(m, '/')
+	m, _ = pressKey(m, 'x')
+	m, _ = pressSpecial(m, tea.KeyEscape)
+	if m.queueSearchTyping || m.queueSearch != "" {
+		t.Errorf("esc while typing: typing=%v search=%q", m.queueSearchTyping, m.queueSearch)
+	}
+}
+
+func TestTUITasksViewSort(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+	m := newTuiModel("http://localhost")
+	m.width, m.height = 120, 30
+	m.currentView = tuiViewTasks
+	// Server order: newest first
+	m.fixJobs = []storage.ReviewJob{
+		makeJob(4, withStatus(storage.JobStatusQueued), withRepoName("web"), withEnqueuedAt(now.Add(-1*time.Minute))),
+		makeJob(3, withStatus(storage.JobStatusRunning), withRepoName("Api"), withEnqueuedAt(now.Add(-5*time.Minute)), withStartedAt(now.Add(-2*time.Minute))),
+		makeJob(2, withRepoName("worker"), withEnqueuedAt(now.Add(-90*time.Minute)), withStartedAt(now.Add(-80*time.Minute)), withFinishedAt(ago(21*time.Minute))),
+		makeJob(1, withStatus(storage.JobStatusFailed), withRepoName("api"), withEnqueuedAt(now.Add(-2*time.Hour)), withStartedAt(now.Add(-2*time.Hour)), withFinishedAt(ago(110*time.Minute))),
+	}
+	m.fixSelectedIdx = 0 // job 4
+
+	order := func(m tuiModel) string {
+		var ids []int64
+		for _, job := range m.fixJobs {
+			ids = append(ids, job.ID)
+		}
+		return fmt.Sprint(ids)
+	}
+
+	steps := []struct {
+		key   rune
+		order string
+		title string
+	}{
+		{'s', "[3 4 2 1]", "[s: status]"},
+		{'s', "[1 2 3 4]", "[s: queued]"},
+		// 0 (never started), 2m, 10m, 59m: by duration, not by string
+		{'s', "[4 3 1 2]", "[s: elapsed]"},
+		{'S', "[2 1 3 4]", "[s: elapsed, reversed]"},
+		// Case-insensitive; ties stay newest first either way
+		{'s', "[2 4 3 1]", "[s: repo, reversed]"},
+		{'S', "[3 1 4 2]", "[s: repo]"},
+		{'s', "[4 3 2 1]", ""},
+	}
+	for _, step := range steps {
+		m, _ = pressKey(m, step.key)
+		if got := order(m); got != step.order {
+			t.Errorf("after %c to %q: order = %s, want %s", step.key, step.title, got, step.order)
+		}
+		if got := m.fixJobs[m.fixSelectedIdx].ID; got != 4 {
+			t.Errorf("after %c to %q: selected job %d, want 4", step.key, step.title, got)
+		}
+		title := strings.SplitN(stripANSI(m.renderTasksView()), "\n", 2)[0]
+		if step.title != "" && !strings.Contains(title, step.title) {
+			t.Errorf("title %q missing %q", title, step.title)
+		}
+		if step.title == "" && strings.Contains(title, "[s:") {
+			t.Errorf("title %q should not show a sort", title)
+		}
+	}
+
+	// A refresh keeps the sort and the selected job
+	m, _ = pressKey(m, 's')
+	refreshed := slices.Clone(m.fixJobs)
+	slices.SortFunc(refreshed, func(a, b storage.ReviewJob) int { return int(b.ID - a.ID) })
+	m.fixSelectedIdx = slices.IndexFunc(m.fixJobs, func(j storage.ReviewJob) bool { return j.ID == 1 })
+	m, _ = updateModel(t, m, tuiFixJobsMsg{jobs: refreshed})
+	if got := order(m); got != "[3 4 2 1]" {
+		t.Errorf("after refresh: order = %s, want status order", got)
+	}
+	if got := m.fixJobs[m.fixSelectedIdx].ID; got != 1 {
+		t.Errorf("after refresh: selected job %d, want 1", got)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+// tuiSeverityStyles color finding bullets in the review view by severity.
+var tuiSeverityStyles = map[string]lipgloss.Style{
+	"critical": lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "125", Dark: "201"}), // Magenta
+	"high":     lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "124", Dark: "196"}),            // Red
+	"medium":   lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "166", Dark: "208"}),            // Orange
+	"low":      lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "25", Dark: "33"}),              // Blue
+}
+
+// reviewFindings returns the findings parsed from the current review when
+// it is a failing review, or nil when the review has no verdict, passed,
+// or its output has no severity-labeled findings.
+func (m tuiModel) reviewFindings() []storage.Finding {
+	r := m.currentReview
+	if r == nil || r.Job == nil || r.Job.IsFixJob() || r.Job.Verdict == nil || *r.Job.Verdict != "F" {
+		return nil
+	}
+	return storage.ExtractFindings(r.Output)
+}
+
+// listedFindings returns the findings the review view renders as a list:
+// reviewFindings, unless the user switched to raw output.
+func (m tuiModel) listedFindings() []storage.Finding {
+	if m.findingsRaw {
+		return nil
+	}
+	return m.reviewFindings()
+}
+
+// resolvedFindingSet returns the indexes of the current review's
+// resolved findings as a set.
+func (m tuiModel) resolvedFindingSet() map[int]bool {
+	if m.currentReview == nil {
+		return nil
+	}
+	resolved := make(map[int]bool, len(m.currentReview.ResolvedFindings))
+	for _, idx := range m.currentReview.ResolvedFindings {
+		resolved[idx] = true
+	}
+	return resolved
+}
+
+// renderFindingLines renders findings as severity-colored bullets with a
+// resolved checkbox, showing the bodies of expanded findings indented
+// beneath their titles. It returns the lines and the index of each
+// finding's first line.
+func renderFindingLines(findings []storage.Finding, sel int, expanded, resolved map[int]bool, width int) ([]string, []int) {
+	numResolved := 0
+	for i := range findings {
+		if resolved[i] {
+			numResolved++
+		}
+	}
+	header := tuiStatusStyle.Render(fmt.Sprintf("%d findings, %d resolved (space: resolve, enter: expand/collapse, s: raw output)", len(findings), numResolved))
+	if numResolved == len(findings) {
+		header = tuiAddressedStyle.Render(fmt.Sprintf("All %d findings resolved", len(findings))) +
+			tuiStatusStyle.Render(" (space: unresolve, enter: expand/collapse, s: raw output)")
+	}
+	lines := []string{header, ""}
+	starts := make([]int, len(findings))
+	titleWidth := max(width-18, 10) // marker, checkbox, bullet, and severity column
+	for i, f := range findings {
+		starts[i] = len(lines)
+		body := f.Body()
+		marker := " "
+		if body != "" {
+			marker = "▸"
+			if expanded[i] {
+				marker = "▾"
+			}
+		}
+		check := "[ ]"
+		if resolved[i] {
+			check = "[x]"
+		}
+		sev := strings.ToUpper(f.Severity)
+		for j, title := range sanitizeLines(wrapText(f.Title(), titleWidth)) {
+			var line string
+			switch {
+			case j > 0:
+				line = fmt.Sprintf("%18s%s", "", title)
+			case i == sel:
+				line = tuiSelectedStyle.Render(fmt.Sprintf("%s %s ● %-8s  %s", marker, check, sev, title))
+			case resolved[i]:
+				line = tuiStatusStyle.Render(fmt.Sprintf("%s %s ● %-8s  %s", marker, check, sev, title))
+			default:
+				style := tuiSeverityStyles[f.Severity]
+				line = fmt.Sprintf("%s %s %s  %s", marker, check, style.Render(fmt.Sprintf("● %-8s", sev)), title)
+			}
+			lines = append(lines, line)
+		}
+		if expanded[i] && body != "" {
+			for _, bl := range sanitizeLines(wrapText(body, titleWidth)) {
+				lines = append(lines, fmt.Sprintf("%18s%s", "", tuiStatusStyle.Render(bl)))
+			}
+			lines = append(lines, "")
+		}
+	}
+	return lines, starts
+}
+
+// handleFindingMoveKey moves the finding selection by delta and scrolls
+// so the selected finding is in view.
+func (m tuiModel) handleFindingMoveKey(delta int) (tea.Model, tea.Cmd) {
+	findings := m.listedFindings()
+	m.findingSel = max(min(m.findingSel+delta, len(findings)-1), 0)
+	m.scrollToFinding(findings)
+	return m, nil
+}
+
+// handleFindingToggleKey expands or collapses the selected finding's body.
+func (m tuiModel) handleFindingToggleKey() (tea.Model, tea.Cmd) {
+	findings := m.listedFindings()
+	if m.findingSel >= len(findings) {
+		return m, nil
+	}
+	expanded := make(map[int]bool, len(m.findingExpanded)+1)
+	for i, v := range m.findingExpanded {
+		expanded[i] = v
+	}
+	expanded[m.findingSel] = !expanded[m.findingSel]
+	m.findingExpanded = expanded
+	m.scrollToFinding(findings)
+	return m, nil
+}
+
+// handleFindingResolveKey marks the selected finding resolved, or
+// unresolved if it already was. The checkbox flips immediately and rolls
+// back if the daemon rejects the change.
+func (m tuiModel) handleFindingResolveKey() (tea.Model, tea.Cmd) {
+	findings := m.listedFindings()
+	if m.findingSel >= len(findings) || m.currentReview == nil {
+		return m, nil
+	}
+	jobID := m.currentReview.JobID
+	idx := m.findingSel
+	oldResolved := m.currentReview.ResolvedFindings
+	resolve := !slices.Contains(oldResolved, idx)
+
+	// Copy the review so the optimistic update doesn't alias oldResolved
+	review := *m.currentReview
+	review.ResolvedFindings = slices.DeleteFunc(slices.Clone(oldResolved), func(i int) bool { return i == idx })
+	if resolve {
+		review.ResolvedFindings = append(review.ResolvedFindings, idx)
+		slices.Sort(review.ResolvedFindings)
+	}
+	m.currentReview = &review
+	return m, m.resolveFinding(jobID, idx, resolve, oldResolved)
+}
+
+// resolveFinding sets a finding's resolved state on the daemon.
+func (m tuiModel) resolveFinding(jobID int64, idx int, resolve bool, oldResolved []int) tea.Cmd {
+	return func() tea.Msg {
+		var resp struct {
+			ResolvedFindings []int `json:"resolved_findings"`
+		}
+		err := m.postJSON("/api/review/finding", map[string]any{
+			"job_id":      jobID,
+			"finding_idx": idx,
+			"resolved":    resolve,
+		}, &resp)
+		if err != nil {
+			return tuiFindingResolvedMsg{jobID: jobID, oldResolved: oldResolved, err: fmt.Errorf("resolve finding: %w", err)}
+		}
+		return tuiFindingResolvedMsg{jobID: jobID, resolved: resp.ResolvedFindings}
+	}
+}
+
+// handleFindingResolvedMsg applies the daemon's resolved findings for the
+// current review, or restores the previous ones on error.
+func (m tuiModel) handleFindingResolvedMsg(msg tuiFindingResolvedMsg) (tea.Model, tea.Cmd) {
+	if m.currentReview == nil || m.currentReview.JobID != msg.jobID {
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, nil
+	}
+	review := *m.currentReview
+	if msg.err != nil {
+		review.ResolvedFindings = msg.oldResolved
+		m.err = msg.err
+	} else {
+		review.ResolvedFindings = msg.resolved
+		if n := len(m.reviewFindings()); n > 0 && len(msg.resolved) == n {
+			m.flashMessage = fmt.Sprintf("All %d findings resolved", n)
+			m.flashExpiresAt = time.Now().Add(2 * time.Second)
+			m.flashView = tuiViewReview
+		}
+	}
+	m.currentReview = &review
+	return m, nil
+}
+
+// handleFindingsRawKey switches a failing review between the findings
+// list and the raw review output.
+func (m tuiModel) handleFindingsRawKey() (tea.Model, tea.Cmd) {
+	if len(m.reviewFindings()) == 0 {
+		return m, nil
+	}
+	m.findingsRaw = !m.findingsRaw
+	m.reviewScroll = 0
+	return m, nil
+}
+
+// scrollToFinding adjusts reviewScroll so the selected finding, including
+// its expanded body when it fits, is visible.
+func (m *tuiModel) scrollToFinding(findings []storage.Finding) {
+	wrapWidth := min(max(20, m.width-4), 100)
+	lines, starts := renderFindingLines(findings, m.findingSel, m.findingExpanded, m.resolvedFindingSet(), wrapWidth)
+	if m.findingSel >= len(starts) {
+		return
+	}
+	visible := m.height
+	if m.mdCache != nil && m.mdCache.lastReviewVisibleLines > 0 {
+		visible = m.mdCache.lastReviewVisibleLines
+	}
+	start := starts[m.findingSel]
+	end := len(lines)
+	if m.findingSel+1 < len(starts) {
+		end = starts[m.findingSel+1]
+	}
+	if end > m.reviewScroll+visible {
+		m.reviewScroll = end - visible
+	}
+	if start < m.reviewScroll {
+		m.reviewScroll = start
+	}
+	if m.findingSel == 0 {
+		m.reviewScroll = 0 // Keep the count header in view
+	}
+}
@@ -20,6 +20,11 @@ func (m tuiModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleReviewFixPanelKey(msg)
 	}
 
+	// Queue search input captures keys while it has focus
+	if m.currentView == tuiViewQueue && m.queueSearchTyping {
+		return m.handleQueueSearchKey(msg)
+	}
+
 	// Modal views that capture most keys for typing
 	switch m.currentView {
 	case tuiViewComment:
@@ -34,12 +39,56 @@ func (m tuiModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleTasksKey(msg)
 	case tuiViewPatch:
 		return m.handlePatchKey(msg)
+	case tuiViewCompare:
+		return m.handleCompareKey(msg)
 	}
 
 	// Global keys shared across queue/review/prompt/commitMsg/help views
 	return m.handleGlobalKey(msg)
 }
 
+// handleQueueSearchKey handles key input while the queue search box has
+// focus. The queue filters as the user types; enter keeps the search and
+// returns keys to the queue, esc clears it.
+func (m tuiModel) handleQueueSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.queueSearchTyping = false
+		m.setQueueSearch("")
+	case "enter":
+		m.queueSearchTyping = false
+	case "backspace":
+		if runes := []rune(m.queueSearch); len(runes) > 0 {
+			m.setQueueSearch(string(runes[:len(runes)-1]))
+		}
+	default:
+		search := m.queueSearch
+		for _, r := range msg.Runes {
+			if unicode.IsPrint(r) {
+				search += string(r)
+			}
+		}
+		m.setQueueSearch(search)
+	}
+	return m, nil
+}
+
+// setQueueSearch updates the queue search text, moving the selection off
+// rows the new search hides. Filtering is client-side over loaded jobs.
+func (m *tuiModel) setQueueSearch(search string) {
+	m.queueSearch = search
+	if len(m.jobs) == 0 {
+		return
+	}
+	m.normalizeSelectionIfHidden()
+	if m.getVisibleSelectedIdx() < 0 && m.findFirstVisibleJob() >= 0 {
+		m.selectedIdx = m.findFirstVisibleJob()
+		m.updateSelectedJobID()
+	}
+}
+
 // handleCommentKey handles key input in the comment modal.
 func (m tuiModel) handleCommentKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -49,15 +98,25 @@ func (m tuiModel) handleCommentKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.currentView = m.commentFromView
 		m.commentText = ""
 		m.commentJobID = 0
+		m.commentEditID = 0
 		return m, nil
 	case "enter":
 		if strings.TrimSpace(m.commentText) != "" {
 			text := m.commentText
 			jobID := m.commentJobID
 			m.currentView = m.commentFromView
+			if m.commentEditID != 0 {
+				return m, m.updateComment(jobID, m.commentEditID, text)
+			}
 			return m, m.submitComment(jobID, text)
 		}
 		return m, nil
+	case "ctrl+d":
+		if m.commentEditID != 0 {
+			m.currentView = m.commentFromView
+			return m, m.deleteComment(m.commentJobID, m.commentEditID)
+		}
+		return m, nil
 	case "backspace":
 		if len(m.commentText) > 0 {
 			runes := []rune(m.commentText)
@@ -277,6 +336,10 @@ func (m *tuiModel) fetchUnloadedBranches() tea.Cmd {
 
 // handleLogKey handles key input in the log view.
 func (m tuiModel) handleLogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.logCancelConfirm {
+		return m.handleLogCancelConfirmKey(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c":
 		return m, tea.Quit
@@ -288,15 +351,7 @@ func (m tuiModel) handleLogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.logJobID > 0 && m.logStreaming {
 			if job := m.logViewLookupJob(); job != nil &&
 				job.Status == storage.JobStatusRunning {
-				oldStatus := job.Status
-				oldFinishedAt := job.FinishedAt
-				job.Status = storage.JobStatusCanceled
-				now := time.Now()
-				job.FinishedAt = &now
-				m.logStreaming = false
-				return m, m.cancelJob(
-					job.ID, oldStatus, oldFinishedAt,
-				)
+				m.logCancelConfirm = true
 			}
 		}
 		return m, nil
@@ -351,6 +406,33 @@ func (m tuiModel) handleLogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleLogCancelConfirmKey handles the answer to the log view's cancel
+// prompt: y cancels the streaming job, any other key keeps it running.
+func (m tuiModel) handleLogCancelConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.logCancelConfirm = false
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "Y":
+		if !m.logStreaming {
+			return m, nil
+		}
+		job := m.logViewLookupJob()
+		if job == nil || job.Status != storage.JobStatusRunning {
+			return m, nil
+		}
+		oldStatus := job.Status
+		oldFinishedAt := job.FinishedAt
+		job.Status = storage.JobStatusCanceled
+		now := time.Now()
+		job.FinishedAt = &now
+		m.logStreaming = false
+		m.logFollow = false
+		return m, m.cancelJob(job.ID, oldStatus, oldFinishedAt)
+	}
+	return m, nil
+}
+
 // handleGlobalKey handles keys shared across queue, review, prompt, commit msg, and help views.
 func (m tuiModel) handleGlobalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -388,18 +470,39 @@ func (m tuiModel) handleGlobalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleBranchFilterOpenKey()
 	case "h":
 		return m.handleHideAddressedKey()
+	case "o":
+		return m.handleHideRemoteKey()
 	case "c":
 		return m.handleCommentOpenKey()
+	case "C":
+		return m.handleCommentEditKey()
 	case "y":
 		return m.handleCopyKey()
 	case "m":
 		return m.handleCommitMsgKey()
+	case "v":
+		if m.currentView == tuiViewReview {
+			return m.handleCompareOpenKey()
+		}
+		return m.handleVerdictFilterKey()
 	case "?":
 		return m.handleHelpKey()
+	case "/":
+		if m.currentView == tuiViewQueue {
+			m.queueSearchTyping = true
+		}
 	case "esc":
 		return m.handleEscKey()
 	case "F":
 		return m.handleFixKey()
+	case "s":
+		if m.currentView == tuiViewReview {
+			return m.handleFindingsRawKey()
+		}
+	case " ":
+		if m.currentView == tuiViewReview && len(m.listedFindings()) > 0 {
+			return m.handleFindingResolveKey()
+		}
 	case "T":
 		return m.handleToggleTasksKey()
 	case "tab":
@@ -482,6 +585,9 @@ func (m tuiModel) handleUpKey() (tea.Model, tea.Cmd) {
 			m.flashView = tuiViewQueue
 		}
 	case tuiViewReview:
+		if len(m.listedFindings()) > 0 {
+			return m.handleFindingMoveKey(-1)
+		}
 		if m.reviewScroll > 0 {
 			m.reviewScroll--
 		}
@@ -524,7 +630,7 @@ func (m tuiModel) handlePrevKey() (tea.Model, tea.Cmd) {
 				m.currentBranch = ""
 				m.currentReview = &storage.Review{
 					Agent:  job.Agent,
-					Output: "Job failed:\n\n" + job.Error,
+					Output: failedJobOutput(job),
 					Job:    &job,
 				}
 			}
@@ -600,6 +706,9 @@ func (m tuiModel) handleDownKey() (tea.Model, tea.Cmd) {
 			m.flashView = tuiViewQueue
 		}
 	case tuiViewReview:
+		if len(m.listedFindings()) > 0 {
+			return m.handleFindingMoveKey(1)
+		}
 		m.reviewScroll++
 		if m.mdCache != nil && m.reviewScroll > m.mdCache.lastReviewMaxScroll {
 			m.reviewScroll = m.mdCache.lastReviewMaxScroll
@@ -643,7 +752,7 @@ func (m tuiModel) handleNextKey() (tea.Model, tea.Cmd) {
 				m.currentBranch = ""
 				m.currentReview = &storage.Review{
 					Agent:  job.Agent,
-					Output: "Job failed:\n\n" + job.Error,
+					Output: failedJobOutput(job),
 					Job:    &job,
 				}
 			}
@@ -782,6 +891,9 @@ func (m tuiModel) handlePageDownKey() (tea.Model, tea.Cmd) {
 }
 
 func (m tuiModel) handleEnterKey() (tea.Model, tea.Cmd) {
+	if m.currentView == tuiViewReview {
+		return m.handleFindingToggleKey()
+	}
 	if m.currentView != tuiViewQueue || len(m.jobs) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.jobs) {
 		return m, nil
 	}
@@ -794,7 +906,7 @@ func (m tuiModel) handleEnterKey() (tea.Model, tea.Cmd) {
 		m.currentBranch = ""
 		m.currentReview = &storage.Review{
 			Agent:  job.Agent,
-			Output: "Job failed:\n\n" + job.Error,
+			Output: failedJobOutput(job),
 			Job:    &job,
 		}
 		m.reviewFromView = tuiViewQueue
@@ -987,6 +1099,7 @@ func (m tuiModel) openLogView(
 	)
 	m.logFetchSeq++
 	m.logLoading = true
+	m.logCancelConfirm = false
 
 	if status == storage.JobStatusRunning {
 		m.logStreaming = true
@@ -1050,7 +1163,51 @@ func (m tuiModel) handleHideAddressedKey() (tea.Model, tea.Cmd) {
 	return m, m.fetchJobs()
 }
 
+// handleHideRemoteKey toggles hiding jobs synced from other machines in
+// the queue view.
+func (m tuiModel) handleHideRemoteKey() (tea.Model, tea.Cmd) {
+	if m.currentView != tuiViewQueue {
+		return m, nil
+	}
+	m.hideRemote = !m.hideRemote
+	if len(m.jobs) > 0 {
+		m.normalizeSelectionIfHidden()
+		if m.getVisibleSelectedIdx() < 0 && m.findFirstVisibleJob() >= 0 {
+			m.selectedIdx = m.findFirstVisibleJob()
+			m.updateSelectedJobID()
+		}
+	}
+	m.fetchSeq++
+	m.loadingJobs = true
+	return m, m.fetchJobs()
+}
+
+// handleVerdictFilterKey cycles the verdict filter in the queue and tasks
+// views, moving the selection off rows the new filter hides.
+func (m tuiModel) handleVerdictFilterKey() (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case tuiViewQueue:
+		m.verdictFilter = m.verdictFilter.next()
+		if len(m.jobs) > 0 {
+			m.normalizeSelectionIfHidden()
+			if m.getVisibleSelectedIdx() < 0 && m.findFirstVisibleJob() >= 0 {
+				m.selectedIdx = m.findFirstVisibleJob()
+				m.updateSelectedJobID()
+			}
+		}
+	case tuiViewTasks:
+		m.verdictFilter = m.verdictFilter.next()
+		m.normalizeFixSelection()
+	}
+	return m, nil
+}
+
 func (m tuiModel) handleCommentOpenKey() (tea.Model, tea.Cmd) {
+	// Don't carry an unsubmitted edit over into a new comment
+	if m.commentEditID != 0 {
+		m.commentText = ""
+		m.commentEditID = 0
+	}
 	if m.currentView == tuiViewQueue && len(m.jobs) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.jobs) {
 		job := m.jobs[m.selectedIdx]
 		if job.Status == storage.JobStatusDone || job.Status == storage.JobStatusFailed {
@@ -1080,6 +1237,39 @@ func (m tuiModel) handleCommentOpenKey() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleCommentEditKey opens the comment modal on the user's most recent
+// comment on the current review, so it can be edited or deleted.
+func (m tuiModel) handleCommentEditKey() (tea.Model, tea.Cmd) {
+	if m.currentView != tuiViewReview || m.currentReview == nil {
+		return m, nil
+	}
+	// Comments are in thread order, so the newest is the highest ID
+	commenter := tuiCommenter()
+	var latest *storage.Response
+	for i := range m.currentResponses {
+		r := &m.currentResponses[i]
+		if r.Responder == commenter && (latest == nil || r.ID > latest.ID) {
+			latest = r
+		}
+	}
+	if r := latest; r != nil {
+		m.commentEditID = r.ID
+		m.commentText = r.Response
+		m.commentJobID = m.currentReview.JobID
+		m.commentCommit = ""
+		if m.currentReview.Job != nil {
+			m.commentCommit = git.ShortSHA(m.currentReview.Job.GitRef)
+		}
+		m.commentFromView = tuiViewReview
+		m.currentView = tuiViewComment
+		return m, nil
+	}
+	m.flashMessage = "No comment of yours to edit"
+	m.flashExpiresAt = time.Now().Add(2 * time.Second)
+	m.flashView = tuiViewReview
+	return m, nil
+}
+
 func (m tuiModel) handleCopyKey() (tea.Model, tea.Cmd) {
 	if m.currentView == tuiViewReview && m.currentReview != nil && m.currentReview.Output != "" {
 		return m, m.copyToClipboard(m.currentReview)
@@ -1141,7 +1331,11 @@ func (m tuiModel) handleHelpKey() (tea.Model, tea.Cmd) {
 }
 
 func (m tuiModel) handleEscKey() (tea.Model, tea.Cmd) {
-	if m.currentView == tuiViewQueue && len(m.filterStack) > 0 {
+	if m.currentView == tuiViewQueue && m.queueSearch != "" {
+		// Client-side only, like the verdict filter
+		m.setQueueSearch("")
+		return m, nil
+	} else if m.currentView == tuiViewQueue && len(m.filterStack) > 0 {
 		popped := m.popFilter()
 		if popped == filterTypeRepo || popped == filterTypeBranch {
 			m.hasMore = false
@@ -1160,6 +1354,18 @@ func (m tuiModel) handleEscKey() (tea.Model, tea.Cmd) {
 		m.fetchSeq++
 		m.loadingJobs = true
 		return m, m.fetchJobs()
+	} else if m.currentView == tuiViewQueue && m.hideRemote {
+		m.hideRemote = false
+		m.hasMore = false
+		m.selectedIdx = -1
+		m.selectedJobID = 0
+		m.fetchSeq++
+		m.loadingJobs = true
+		return m, m.fetchJobs()
+	} else if m.currentView == tuiViewQueue && m.verdictFilter != verdictFilterAll {
+		// Client-side only, so every loaded job becomes visible without a refetch
+		m.verdictFilter = verdictFilterAll
+		return m, nil
 	} else if m.currentView == tuiViewReview {
 		// If fix panel is open (unfocused), esc closes it rather than leaving the review
 		if m.reviewFixPanelOpen {
@@ -1364,7 +1570,7 @@ func (m tuiModel) handleJobsMsg(msg tuiJobsMsg) (tea.Model, tea.Cmd) {
 					m.currentBranch = ""
 					m.currentReview = &storage.Review{
 						Agent:  job.Agent,
-						Output: "Job failed:\n\n" + job.Error,
+						Output: failedJobOutput(job),
 						Job:    &job,
 					}
 				}
@@ -1618,6 +1824,13 @@ func (m tuiModel) handleWorktreeConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 
 // handleTasksKey handles key input in the tasks view.
 func (m tuiModel) handleTasksKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "l", "t", "A", "R", "x", "p":
+		// Row actions only apply to a job the filters show
+		if !m.fixSelectionVisible() {
+			return m, nil
+		}
+	}
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return m, tea.Quit
@@ -1625,15 +1838,36 @@ func (m tuiModel) handleTasksKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.currentView = tuiViewQueue
 		return m, nil
 	case "up", "k":
-		if m.fixSelectedIdx > 0 {
-			m.fixSelectedIdx--
+		for i := m.fixSelectedIdx - 1; i >= 0; i-- {
+			if m.fixJobVisible(m.fixJobs[i]) {
+				m.fixSelectedIdx = i
+				break
+			}
 		}
 		return m, nil
 	case "down", "j":
-		if m.fixSelectedIdx < len(m.fixJobs)-1 {
-			m.fixSelectedIdx++
+		for i := m.fixSelectedIdx + 1; i < len(m.fixJobs); i++ {
+			if m.fixJobVisible(m.fixJobs[i]) {
+				m.fixSelectedIdx = i
+				break
+			}
 		}
 		return m, nil
+	case "v":
+		return m.handleVerdictFilterKey()
+	case "a":
+		// Toggle showing only applied/rebased fixes
+		m.fixAppliedOnly = !m.fixAppliedOnly
+		m.normalizeFixSelection()
+		return m, nil
+	case "s":
+		m.fixSort = m.fixSort.next()
+		m.sortFixJobs(m.selectedFixJobID())
+		return m, nil
+	case "S":
+		m.fixSortReverse = !m.fixSortReverse
+		m.sortFixJobs(m.selectedFixJobID())
+		return m, nil
 	case "enter":
 		// View task: prompt for running, review for done/applied, log for failed
 		if len(m.fixJobs) > 0 && m.fixSelectedIdx < len(m.fixJobs) {
@@ -1765,6 +1999,11 @@ func (m tuiModel) handlePatchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		visibleRows := max(m.height-4, 1)
 		m.patchScroll = max(len(lines)-visibleRows, 0)
 		return m, nil
+	case "w":
+		if m.patchText != "" {
+			return m, m.savePatch()
+		}
+		return m, nil
 	}
 	return m, nil
 }
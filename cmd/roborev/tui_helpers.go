@@ -1,7 +1,10 @@
 package main
 
 import (
+	"cmp"
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,6 +26,207 @@ const (
 // branchNone is the sentinel value for jobs with no branch information.
 const branchNone = "(none)"
 
+// verdictFilter restricts the queue and tasks views to jobs with a given
+// verdict. It is applied client-side to already-loaded jobs.
+type verdictFilter int
+
+const (
+	verdictFilterAll verdictFilter = iota
+	verdictFilterPass
+	verdictFilterFail
+	verdictFilterPending // No verdict yet (queued, running, failed, or task jobs)
+)
+
+// next returns the filter the v key cycles to.
+func (f verdictFilter) next() verdictFilter {
+	return (f + 1) % (verdictFilterPending + 1)
+}
+
+// label returns the name shown in view headers; "" for no filter.
+func (f verdictFilter) label() string {
+	switch f {
+	case verdictFilterPass:
+		return "pass"
+	case verdictFilterFail:
+		return "fail"
+	case verdictFilterPending:
+		return "pending"
+	}
+	return ""
+}
+
+// matches reports whether a job's verdict passes the filter.
+func (f verdictFilter) matches(job storage.ReviewJob) bool {
+	switch f {
+	case verdictFilterPass:
+		return job.Verdict != nil && *job.Verdict == "P"
+	case verdictFilterFail:
+		return job.Verdict != nil && *job.Verdict == "F"
+	case verdictFilterPending:
+		return job.Verdict == nil
+	}
+	return true
+}
+
+// queueSearchMatches reports whether a job's repo name, branch, commit
+// subject, or agent contains the queue search text, ignoring case.
+func (m tuiModel) queueSearchMatches(job storage.ReviewJob) bool {
+	if m.queueSearch == "" {
+		return true
+	}
+	search := strings.ToLower(m.queueSearch)
+	fields := []string{m.getDisplayName(job.RepoPath, job.RepoName), m.getBranchForJob(job), job.CommitSubject, job.Agent}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixJobVisible reports whether a fix job passes the tasks view filters:
+// the verdict filter and, when enabled, the applied-only filter.
+func (m tuiModel) fixJobVisible(job storage.ReviewJob) bool {
+	if m.fixAppliedOnly && job.Status != storage.JobStatusApplied && job.Status != storage.JobStatusRebased {
+		return false
+	}
+	return m.verdictFilter.matches(job)
+}
+
+// visibleFixJobIndices returns the indices of fix jobs that pass the
+// tasks view filters, in display order.
+func (m tuiModel) visibleFixJobIndices() []int {
+	indices := make([]int, 0, len(m.fixJobs))
+	for i, job := range m.fixJobs {
+		if m.fixJobVisible(job) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// fixSelectionVisible reports whether the selected fix job passes the
+// tasks view filters.
+func (m tuiModel) fixSelectionVisible() bool {
+	return m.fixSelectedIdx >= 0 && m.fixSelectedIdx < len(m.fixJobs) &&
+		m.fixJobVisible(m.fixJobs[m.fixSelectedIdx])
+}
+
+// normalizeFixSelection moves the tasks view selection to the nearest
+// visible fix job when the filters hide the selected one,
+// preferring the next row.
+func (m *tuiModel) normalizeFixSelection() {
+	if len(m.fixJobs) == 0 || m.fixSelectionVisible() {
+		return
+	}
+	for i := m.fixSelectedIdx + 1; i < len(m.fixJobs); i++ {
+		if m.fixJobVisible(m.fixJobs[i]) {
+			m.fixSelectedIdx = i
+			return
+		}
+	}
+	for i := min(m.fixSelectedIdx, len(m.fixJobs)) - 1; i >= 0; i-- {
+		if m.fixJobVisible(m.fixJobs[i]) {
+			m.fixSelectedIdx = i
+			return
+		}
+	}
+}
+
+// fixSort is the tasks view sort key. fixSortNone keeps the server's
+// newest-first order.
+type fixSort int
+
+const (
+	fixSortNone fixSort = iota
+	fixSortStatus
+	fixSortQueued
+	fixSortElapsed
+	fixSortRepo
+)
+
+// next returns the sort key the s key cycles to.
+func (s fixSort) next() fixSort {
+	return (s + 1) % (fixSortRepo + 1)
+}
+
+// label returns the name shown in the tasks view title; "" for no sort.
+func (s fixSort) label() string {
+	switch s {
+	case fixSortStatus:
+		return "status"
+	case fixSortQueued:
+		return "queued"
+	case fixSortElapsed:
+		return "elapsed"
+	case fixSortRepo:
+		return "repo"
+	}
+	return ""
+}
+
+// fixStatusRank orders task statuses from most to least in need of
+// attention: active work first, then ready patches, then settled jobs.
+var fixStatusRank = map[storage.JobStatus]int{
+	storage.JobStatusRunning:  0,
+	storage.JobStatusQueued:   1,
+	storage.JobStatusDone:     2,
+	storage.JobStatusFailed:   3,
+	storage.JobStatusApplied:  4,
+	storage.JobStatusRebased:  5,
+	storage.JobStatusCanceled: 6,
+}
+
+// fixJobElapsed returns how long a fix job has run: until it finished,
+// or until now while it is running. Jobs that never started have zero.
+func fixJobElapsed(job storage.ReviewJob, now time.Time) time.Duration {
+	if job.StartedAt == nil {
+		return 0
+	}
+	if job.FinishedAt != nil {
+		return job.FinishedAt.Sub(*job.StartedAt)
+	}
+	return now.Sub(*job.StartedAt)
+}
+
+// sortFixJobs orders fix jobs by the tasks view sort key, ascending
+// unless reversed, breaking ties newest first. The job with selectedID
+// stays selected when it is still in the list.
+func (m *tuiModel) sortFixJobs(selectedID int64) {
+	now := time.Now()
+	slices.SortStableFunc(m.fixJobs, func(a, b storage.ReviewJob) int {
+		var c int
+		switch m.fixSort {
+		case fixSortStatus:
+			c = cmp.Compare(fixStatusRank[a.Status], fixStatusRank[b.Status])
+		case fixSortQueued:
+			c = a.EnqueuedAt.Compare(b.EnqueuedAt)
+		case fixSortElapsed:
+			c = cmp.Compare(fixJobElapsed(a, now), fixJobElapsed(b, now))
+		case fixSortRepo:
+			c = cmp.Compare(strings.ToLower(a.RepoName), strings.ToLower(b.RepoName))
+		}
+		if m.fixSortReverse {
+			c = -c
+		}
+		if c == 0 {
+			c = cmp.Compare(b.ID, a.ID)
+		}
+		return c
+	})
+	if idx := slices.IndexFunc(m.fixJobs, func(j storage.ReviewJob) bool { return j.ID == selectedID }); idx >= 0 {
+		m.fixSelectedIdx = idx
+	}
+}
+
+// selectedFixJobID returns the ID of the selected fix job, or 0.
+func (m tuiModel) selectedFixJobID() int64 {
+	if m.fixSelectedIdx >= 0 && m.fixSelectedIdx < len(m.fixJobs) {
+		return m.fixJobs[m.fixSelectedIdx].ID
+	}
+	return 0
+}
+
 // mutateJob finds a job by ID and applies the mutation function.
 // Returns true if the job was found and mutated.
 func (m *tuiModel) mutateJob(id int64, fn func(*storage.ReviewJob)) bool {
@@ -86,6 +290,21 @@ func (m *tuiModel) setJobError(jobID int64, errMsg string) {
 	})
 }
 
+// failedJobOutput returns the text shown in the review view for a failed
+// job: its final error, followed by the errors behind any automatic
+// retries, oldest first.
+func failedJobOutput(job storage.ReviewJob) string {
+	var sb strings.Builder
+	sb.WriteString("Job failed:\n\n" + job.Error)
+	if len(job.RetryErrors) > 0 {
+		sb.WriteString("\n\nRetry history:\n")
+		for i, e := range job.RetryErrors {
+			fmt.Fprintf(&sb, "\n%d. %s", i+1, e)
+		}
+	}
+	return sb.String()
+}
+
 // wrapText wraps text to the specified width, preserving existing line breaks
 // and breaking at word boundaries when possible. Uses runewidth for correct
 // display width calculation with Unicode and wide characters.
@@ -175,6 +394,10 @@ type markdownCache struct {
 	// scroll values even though View() uses a value receiver.
 	lastReviewMaxScroll int
 	lastPromptMaxScroll int
+
+	// Content lines visible in the last review render, used to keep the
+	// selected finding in view.
+	lastReviewVisibleLines int
 }
 
 // newMarkdownCache creates a markdownCache, detecting terminal background
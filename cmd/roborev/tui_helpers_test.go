@@ -565,3 +565,17 @@ func TestRenderHelpTableLinesWithinWidth(t *testing.T) {
 		}
 	}
 }
+
+func TestFailedJobOutput(t *testing.T) {
+	job := storage.ReviewJob{Error: "agent: exit status 1"}
+	if got, want := failedJobOutput(job), "Job failed:\n\nagent: exit status 1"; got != want {
+		t.Errorf("failedJobOutput() = %q, want %q", got, want)
+	}
+
+	job.RetryErrors = []string{"agent: timeout", "agent: exit status 2"}
+	got := failedJobOutput(job)
+	want := "Job failed:\n\nagent: exit status 1\n\nRetry history:\n\n1. agent: timeout\n2. agent: exit status 2"
+	if got != want {
+		t.Errorf("failedJobOutput() = %q, want %q", got, want)
+	}
+}
@@ -351,6 +351,27 @@ func TestTUICalculateColumnWidthsProportions(t *testing.T) {
 	}
 }
 
+func TestTUIRenderJobLineRangeLabel(t *testing.T) {
+	m := tuiModel{width: 200}
+	job := makeJob(1, withRef("abcdef1234567"), withEnqueuedAt(time.Now()))
+	job.RangeLabel = "main..HEAD"
+
+	line := m.renderJobLine(job, false, 3, columnWidths{ref: 30, branch: 10, repo: 10, agent: 10})
+	if !strings.Contains(line, "abcdef1234567 (main..HEAD)") {
+		t.Errorf("expected range label after ref, got: %s", line)
+	}
+
+	// A range job shows the range itself with its commit count
+	rangeJob := makeJob(2, withRef("abcdef1..1234567"), withEnqueuedAt(time.Now()))
+	rangeJob.JobType = storage.JobTypeRange
+	rangeJob.RangeLabel = "main..HEAD"
+	rangeJob.RangeCommits = 5
+	line = m.renderJobLine(rangeJob, false, 3, columnWidths{ref: 30, branch: 10, repo: 10, agent: 10})
+	if !strings.Contains(line, "main..HEAD (5 commits)") || strings.Contains(line, "abcdef1") {
+		t.Errorf("expected range label with commit count in place of the SHAs, got: %s", line)
+	}
+}
+
 func TestTUIRenderJobLineTruncation(t *testing.T) {
 	m := tuiModel{width: 80}
 	// Use a git range - shortRef truncates ranges to 17 chars max, then renderJobLine
@@ -964,6 +985,26 @@ func TestTUILoadingJobsShowsLoadingMessage(t *testing.T) {
 	}
 }
 
+func TestTUIStatusLineShowsQueueDepth(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.width = 160
+	m.height = 20
+	m.jobs = []storage.ReviewJob{makeJob(1)}
+	m.status = storage.DaemonStatus{QueuedJobs: 12, RunningJobs: 3, ActiveWorkers: 3, MaxWorkers: 4}
+
+	output := stripANSI(m.renderQueueView())
+	if !strings.Contains(output, "Workers: 3/4 | 12 queued, 3 running |") {
+		t.Errorf("Expected queue depth in status line, got: %s", output)
+	}
+
+	// Filtered views show filtered counts only, so the global depth is omitted
+	m.activeRepoFilter = []string{"/test/repo"}
+	output = stripANSI(m.renderQueueView())
+	if strings.Contains(output, "12 queued") {
+		t.Errorf("Expected no global queue depth in filtered status line, got: %s", output)
+	}
+}
+
 func TestTUILoadingShowsForLoadingMore(t *testing.T) {
 	// Test that "Loading..." shows when loadingMore is set on empty queue
 	m := newTuiModel("http://localhost")
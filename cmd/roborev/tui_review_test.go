@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -699,6 +700,33 @@ func TestTUIRenderReviewViewWithModel(t *testing.T) {
 	}
 }
 
+func TestTUIRenderReviewViewIndentsReplies(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.width = 100
+	m.height = 30
+	m.currentView = tuiViewReview
+	m.currentReview = &storage.Review{
+		ID:     10,
+		Agent:  "codex",
+		Output: "Some review output",
+		Job:    &storage.ReviewJob{ID: 1, GitRef: "abc1234", RepoName: "myrepo", Agent: "codex"},
+	}
+	parentID := int64(1)
+	m.currentResponses = []storage.Response{
+		{ID: 1, Responder: "alice", Response: "Why a mutex?"},
+		{ID: 2, Responder: "bob", Response: "Concurrent writers", ParentResponseID: &parentID, Depth: 1},
+	}
+
+	output := stripANSI(m.View())
+
+	if !strings.Contains(output, "\nWhy a mutex?") {
+		t.Errorf("Expected top-level comment without indent, got:\n%s", output)
+	}
+	if !strings.Contains(output, "│ Concurrent writers") {
+		t.Errorf("Expected reply indented under its parent, got:\n%s", output)
+	}
+}
+
 func TestTUIRenderReviewViewWithoutModel(t *testing.T) {
 	m := newTuiModel("http://localhost")
 	m.width = 100
@@ -1244,14 +1272,15 @@ func TestTUIVisibleLinesCalculationLongTitleWraps(t *testing.T) {
 	// New layout:
 	// - Title: "Review #1 very-long-repository-name-here (claude-code)" = ~54 chars, ceil(54/50) = 2 lines
 	// - Location line: "very-long-repository-name-here abc1234567890..de on feature/very-long-branch-name" = 81 chars, ceil(81/50) = 2 lines
+	// - Commit line: "Ref: abc1234567890..def5678901234" = 33 chars, 1 line (range job, no commit)
 	// - Addressed line: 1 line (since Addressed=true)
 	// - Status line: 1 line
 	// - Help: 4 lines (reflows at width=50)
-	// Non-content: 2 + 2 + 1 + 1 + 4 = 10
-	// visibleLines = 12 - 10 = 2
+	// Non-content: 2 + 2 + 1 + 1 + 1 + 4 = 11
+	// visibleLines = 13 - 11 = 2
 	m := newTuiModel("http://localhost")
 	m.width = 50
-	m.height = 12
+	m.height = 13
 	m.currentView = tuiViewReview
 	m.currentBranch = "feature/very-long-branch-name"
 
@@ -1271,7 +1300,7 @@ func TestTUIVisibleLinesCalculationLongTitleWraps(t *testing.T) {
 
 	output := m.View()
 
-	// visibleLines = 12 - 10 = 2
+	// visibleLines = 13 - 11 = 2
 	// Glamour produces 21 rendered lines.
 	visibleContentLines := 2
 	totalRenderedLines := 21
@@ -1950,14 +1979,16 @@ func TestTUIYankCopyShowsErrorOnFailure(t *testing.T) {
 	m.currentView = tuiViewQueue
 
 	// Simulate receiving a failed clipboard result
-	m, _ = updateModel(t, m, tuiClipboardResultMsg{err: fmt.Errorf("clipboard not available"), view: tuiViewQueue})
+	m, _ = updateModel(t, m, tuiClipboardResultMsg{err: errNoClipboard, view: tuiViewQueue})
 
-	if m.err == nil {
-		t.Error("Expected error to be set")
+	if m.err != nil {
+		t.Errorf("Expected no persistent error, got %v", m.err)
 	}
-
-	if !strings.Contains(m.err.Error(), "copy failed") {
-		t.Errorf("Expected error to contain 'copy failed', got %q", m.err.Error())
+	if !strings.Contains(m.flashMessage, "Copy failed") || !strings.Contains(m.flashMessage, "no clipboard tool") {
+		t.Errorf("Expected a copy failed flash naming the missing tool, got %q", m.flashMessage)
+	}
+	if m.flashView != tuiViewQueue || m.flashExpiresAt.IsZero() {
+		t.Errorf("Expected flash for the queue view, got view %v expires %v", m.flashView, m.flashExpiresAt)
 	}
 }
 
@@ -3021,7 +3052,7 @@ func TestTUILogViewLookupFixJob(t *testing.T) {
 }
 
 func TestTUILogCancelFixJob(t *testing.T) {
-	// Pressing 'x' in log view should cancel fix jobs.
+	// Confirming 'x' in log view should cancel fix jobs.
 	m := newTuiModel("http://localhost")
 	m.currentView = tuiViewLog
 	m.logJobID = 42
@@ -3037,7 +3068,8 @@ func TestTUILogCancelFixJob(t *testing.T) {
 		},
 	}
 
-	m2, cmd := pressKey(m, 'x')
+	m2, _ := pressKey(m, 'x')
+	m2, cmd := pressKey(m2, 'y')
 
 	if m2.logStreaming {
 		t.Error("streaming should stop after cancel")
@@ -3053,6 +3085,69 @@ func TestTUILogCancelFixJob(t *testing.T) {
 	}
 }
 
+func TestTUILogCancelRequiresConfirmation(t *testing.T) {
+	var canceled []float64
+	_, m := mockServerModel(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/job/cancel" {
+			var req map[string]any
+			json.NewDecoder(r.Body).Decode(&req)
+			canceled = append(canceled, req["job_id"].(float64))
+		}
+		json.NewEncoder(w).Encode(map[string]any{"success": true})
+	})
+	m.currentView = tuiViewLog
+	m.logJobID = 7
+	m.logFromView = tuiViewQueue
+	m.logStreaming = true
+	m.logFollow = true
+	m.width = 80
+	m.height = 30
+	m.jobs = []storage.ReviewJob{
+		{ID: 7, Status: storage.JobStatusRunning, Agent: "codex", GitRef: "abc1234"},
+	}
+
+	// x asks for confirmation without touching the job
+	m, cmd := pressKey(m, 'x')
+	if cmd != nil {
+		t.Fatal("x should only prompt, not cancel")
+	}
+	if !m.logCancelConfirm || !m.logStreaming || m.jobs[0].Status != storage.JobStatusRunning {
+		t.Fatalf("expected a pending confirmation with the job still running")
+	}
+	if out := stripANSI(m.renderLogView()); !strings.Contains(out, "Cancel job #7?") {
+		t.Errorf("expected confirmation prompt in log view:\n%s", out)
+	}
+
+	// Any other key dismisses the prompt and keeps streaming
+	m, cmd = pressKey(m, 'n')
+	if cmd != nil || m.logCancelConfirm || !m.logStreaming || m.jobs[0].Status != storage.JobStatusRunning {
+		t.Fatalf("expected n to dismiss the prompt and keep the job running")
+	}
+
+	// y cancels the job, stops the stream, and the view shows it canceled
+	m, _ = pressKey(m, 'x')
+	m, cmd = pressKey(m, 'y')
+	if m.logStreaming || m.logCancelConfirm {
+		t.Error("expected streaming to stop after confirming")
+	}
+	if m.jobs[0].Status != storage.JobStatusCanceled {
+		t.Errorf("expected job canceled, got %s", m.jobs[0].Status)
+	}
+	if m.currentView != tuiViewLog {
+		t.Errorf("expected to stay in log view, got %v", m.currentView)
+	}
+	if out := stripANSI(m.renderLogView()); !strings.Contains(out, "● canceled") {
+		t.Errorf("expected canceled marker in log title:\n%s", out)
+	}
+	if cmd == nil {
+		t.Fatal("expected cancel command")
+	}
+	cmd()
+	if len(canceled) != 1 || canceled[0] != 7 {
+		t.Errorf("expected one cancel request for job 7, got %v", canceled)
+	}
+}
+
 func TestTUILogVisibleLinesFixJob(t *testing.T) {
 	// logVisibleLines must account for the command-line header
 	// when viewing a fix job (from m.fixJobs, not m.jobs).
@@ -3566,3 +3661,222 @@ func TestFixPanelPendingClearedOnEscFromReview(t *testing.T) {
 		t.Errorf("Expected fixPromptJobID=0, got %d", got.fixPromptJobID)
 	}
 }
+
+func findingsReviewModel(verdict, output string) tuiModel {
+	m := newTuiModel("http://localhost")
+	m.width = 100
+	m.height = 30
+	m.currentView = tuiViewReview
+	m.currentReview = &storage.Review{
+		ID:     10,
+		Output: output,
+		Job:    &storage.ReviewJob{ID: 1, GitRef: "abc1234", Agent: "codex", Verdict: &verdict},
+	}
+	return m
+}
+
+const findingsReviewOutput = "## Review\n\n" +
+	"- **High**: SQL built with string concatenation\n" +
+	"  in internal/db/query.go\n" +
+	"\n" +
+	"- Low: typo in comment\n"
+
+func TestTUIRenderReviewViewFindingsList(t *testing.T) {
+	m := findingsReviewModel("F", findingsReviewOutput)
+
+	out := stripANSI(m.View())
+	for _, want := range []string{"2 findings", "▸ [ ] ● HIGH      SQL built with string concatenation", "● LOW       typo in comment", "enter: expand", "s: raw"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "internal/db/query.go") || strings.Contains(out, "## Review") {
+		t.Errorf("collapsed list should hide bodies and raw markdown:\n%s", out)
+	}
+
+	// Enter expands the selected finding's body
+	m, _ = pressSpecial(m, tea.KeyEnter)
+	out = stripANSI(m.View())
+	if !strings.Contains(out, "▾ [ ] ● HIGH") || !strings.Contains(out, "in internal/db/query.go") {
+		t.Errorf("expected expanded body after enter:\n%s", out)
+	}
+
+	// Down moves the selection; enter on a one-line finding leaves the first expanded
+	m, _ = pressSpecial(m, tea.KeyDown)
+	if m.findingSel != 1 {
+		t.Fatalf("findingSel = %d, want 1", m.findingSel)
+	}
+	m, _ = pressSpecial(m, tea.KeyDown)
+	if m.findingSel != 1 {
+		t.Errorf("findingSel = %d, want clamped to 1", m.findingSel)
+	}
+	m, _ = pressSpecial(m, tea.KeyUp)
+	m, _ = pressSpecial(m, tea.KeyEnter)
+	if strings.Contains(stripANSI(m.View()), "in internal/db/query.go") {
+		t.Error("second enter should collapse the body")
+	}
+
+	// s switches to the raw output and back
+	m, _ = pressKey(m, 's')
+	out = stripANSI(m.View())
+	if strings.Contains(out, "2 findings") || !strings.Contains(out, "s: findings") {
+		t.Errorf("expected raw output after s:\n%s", out)
+	}
+	m, _ = pressKey(m, 's')
+	if !strings.Contains(stripANSI(m.View()), "2 findings") {
+		t.Error("second s should return to the findings list")
+	}
+}
+
+func TestTUIRenderReviewViewFindingsFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		verdict string
+		output  string
+	}{
+		{"passing review", "P", findingsReviewOutput},
+		{"unstructured output", "F", "The change breaks the build.\nSee the CI log."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := findingsReviewModel(tt.verdict, tt.output)
+			out := stripANSI(m.View())
+			if strings.Contains(out, "resolved (") || strings.Contains(out, "s: raw") {
+				t.Errorf("expected raw rendering:\n%s", out)
+			}
+			m, _ = pressKey(m, 's')
+			if m.findingsRaw {
+				t.Error("s should do nothing without parsed findings")
+			}
+		})
+	}
+}
+
+func TestTUIResolveFinding(t *testing.T) {
+	var posted []map[string]any
+	_, base := mockServerModel(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		posted = append(posted, req)
+		if req["finding_idx"] == float64(0) {
+			json.NewEncoder(w).Encode(map[string]any{"resolved_findings": []int{0}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"resolved_findings": []int{0, 1}})
+	})
+	m := findingsReviewModel("F", findingsReviewOutput)
+	m.serverAddr = base.serverAddr
+	m.currentReview.JobID = 1
+
+	out := stripANSI(m.View())
+	if !strings.Contains(out, "2 findings, 0 resolved") || !strings.Contains(out, "[ ] ● LOW") {
+		t.Errorf("expected unresolved findings:\n%s", out)
+	}
+
+	// Space checks the box immediately, then applies the daemon's list
+	m, cmd := pressSpecial(m, tea.KeySpace)
+	if !strings.Contains(stripANSI(m.View()), "[x] ● HIGH") {
+		t.Errorf("expected optimistic checkbox after space:\n%s", stripANSI(m.View()))
+	}
+	m, _ = updateModel(t, m, cmd())
+	if len(posted) != 1 || posted[0]["job_id"] != float64(1) || posted[0]["resolved"] != true {
+		t.Errorf("posted = %v", posted)
+	}
+
+	m, _ = pressSpecial(m, tea.KeyDown)
+	m, cmd = pressSpecial(m, tea.KeySpace)
+	m, _ = updateModel(t, m, cmd())
+	out = stripANSI(m.View())
+	if !strings.Contains(out, "All 2 findings resolved") || !strings.Contains(out, "[x] ● LOW") {
+		t.Errorf("expected all findings resolved:\n%s", out)
+	}
+
+	// A failed update restores the previous checkboxes
+	m, _ = updateModel(t, m, tuiFindingResolvedMsg{jobID: 1, oldResolved: []int{0}, err: fmt.Errorf("daemon down")})
+	if !slices.Equal(m.currentReview.ResolvedFindings, []int{0}) || m.err == nil {
+		t.Errorf("expected rollback to [0] with error, got %v (err %v)", m.currentReview.ResolvedFindings, m.err)
+	}
+}
+
+func TestTUIReviewMsgResetsFindingSelection(t *testing.T) {
+	m := findingsReviewModel("F", findingsReviewOutput)
+	m.selectedJobID = 1
+	m.findingSel = 1
+	m.findingExpanded = map[int]bool{0: true}
+
+	m, _ = updateModel(t, m, tuiReviewMsg{review: m.currentReview, jobID: 1})
+	if m.findingSel != 0 || len(m.findingExpanded) != 0 {
+		t.Errorf("finding state not reset: sel=%d expanded=%v", m.findingSel, m.findingExpanded)
+	}
+}
+
+func TestTUIReviewViewRerunChain(t *testing.T) {
+	pass, fail := "P", "F"
+	first, second := int64(12), int64(15)
+	m := newTuiModel("http://localhost")
+	m.width, m.height = 120, 20
+	m.currentView = tuiViewReview
+	m.jobs = []storage.ReviewJob{
+		{ID: 18, GitRef: "abc1234", Status: storage.JobStatusRunning, SupersedesJobID: &second},
+		{ID: 16, GitRef: "def5678", Status: storage.JobStatusDone, Verdict: &pass},
+		{ID: 15, GitRef: "abc1234", Status: storage.JobStatusDone, Verdict: &pass, SupersedesJobID: &first},
+		{ID: 12, GitRef: "abc1234", Status: storage.JobStatusDone, Verdict: &fail},
+	}
+	m.currentReview = &storage.Review{ID: 1, JobID: 15, Output: "No issues found.",
+		Job: &storage.ReviewJob{ID: 15, GitRef: "abc1234", Agent: "codex", Verdict: &pass}}
+
+	if out := stripANSI(m.View()); !strings.Contains(out, "Reruns: #12 Fail → [#15 Pass] → #18 running") {
+		t.Errorf("expected the rerun chain:\n%s", out)
+	}
+
+	m.currentReview.JobID = 16
+	m.currentReview.Job = &storage.ReviewJob{ID: 16, GitRef: "def5678", Agent: "codex", Verdict: &pass}
+	if out := stripANSI(m.View()); strings.Contains(out, "Reruns:") {
+		t.Errorf("job without reruns should have no chain:\n%s", out)
+	}
+}
+
+func TestTUIReviewViewCommitLine(t *testing.T) {
+	authored := time.Date(2026, 3, 4, 5, 6, 0, 0, time.Local)
+	commitID := int64(9)
+
+	tests := []struct {
+		name string
+		job  storage.ReviewJob
+		want string
+	}{
+		{
+			name: "commit",
+			job: storage.ReviewJob{ID: 1, CommitID: &commitID, GitRef: "abc1234", CommitAuthor: "Ada Lovelace",
+				CommitDate: &authored, CommitSubject: "Fix the engine's timing"},
+			want: "Author: Ada Lovelace | 2026-03-04 05:06 | Fix the engine's timing",
+		},
+		{
+			name: "range falls back to ref",
+			job:  storage.ReviewJob{ID: 2, GitRef: "abc1234567890..def5678901234", JobType: storage.JobTypeRange},
+			want: "Ref: abc1234567890..def5678901234",
+		},
+		{
+			name: "dirty has no line",
+			job:  storage.ReviewJob{ID: 3, GitRef: "dirty", JobType: storage.JobTypeDirty},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reviewCommitLine(tt.job); got != tt.want {
+				t.Errorf("reviewCommitLine = %q, want %q", got, tt.want)
+			}
+			if tt.want == "" {
+				return
+			}
+			m := newTuiModel("http://localhost")
+			m.width, m.height = 120, 20
+			m.currentView = tuiViewReview
+			job := tt.job
+			m.currentReview = makeReview(10, &job, withReviewOutput("Looks fine"))
+			if out := stripANSI(m.View()); !strings.Contains(out, tt.want) {
+				t.Errorf("review view missing %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
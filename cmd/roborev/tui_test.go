@@ -15,6 +15,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/roborev-dev/roborev/internal/review"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/roborev-dev/roborev/internal/version"
 )
@@ -197,6 +198,27 @@ func TestTUIFetchJobsError(t *testing.T) {
 	}
 }
 
+func TestReadErrorBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"envelope", `{"error":{"code":"job_not_found","message":"job not found"}}`, "job not found"},
+		{"legacy string", `{"error":"job not found"}`, "job not found"},
+		{"plain text", "boom\n", "boom"},
+		{"empty", "", "500 Internal Server Error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readErrorBody(strings.NewReader(tt.body), "500 Internal Server Error")
+			if got != tt.want {
+				t.Errorf("readErrorBody(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTUIHTTPTimeout(t *testing.T) {
 	_, m := mockServerModel(t, func(w http.ResponseWriter, r *http.Request) {
 		// Delay much longer than client timeout to avoid flaky timing on fast machines
@@ -1001,6 +1023,46 @@ func TestTUIUpdateNotificationInQueueView(t *testing.T) {
 	}
 }
 
+func TestTUIUserPauseBanner(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.currentView = tuiViewQueue
+	m.width = 160
+	m.height = 24
+	m.updateAvailable = "1.2.3"
+	m.status.QueuePausedByUser = true
+
+	lines := strings.Split(stripANSI(m.renderQueueView()), "\n")
+	if len(lines) < 3 || !strings.Contains(lines[2], "PAUSED") || !strings.Contains(lines[2], "roborev resume") {
+		t.Errorf("Expected PAUSED banner on line 3 in place of the update notice, got %q", lines[2])
+	}
+}
+
+func TestTUIAuthFailureSurfacedInQueueView(t *testing.T) {
+	m := newTuiModel("http://localhost")
+	m.currentView = tuiViewQueue
+	m.width = 160
+	m.height = 24
+	m.updateAvailable = "1.2.3"
+	m.status.QueuePausedReason = "agent codex authentication failed (job 7)"
+	m.jobs = []storage.ReviewJob{
+		makeJob(7, withStatus(storage.JobStatusFailed), withError(review.AuthErrorPrefix+"401 Unauthorized")),
+		makeJob(8, withStatus(storage.JobStatusFailed), withError("connection reset")),
+	}
+	m.selectedIdx = 1
+
+	output := stripANSI(m.renderQueueView())
+	lines := strings.Split(output, "\n")
+	if len(lines) < 3 || !strings.Contains(lines[2], "Queue paused: agent codex authentication failed") {
+		t.Errorf("Expected paused-queue banner on line 3 in place of the update notice:\n%s", output)
+	}
+	if !strings.Contains(output, "authfail") {
+		t.Errorf("Expected auth failure status label:\n%s", output)
+	}
+	if strings.Count(output, "authfail") != 1 {
+		t.Errorf("Only the auth failure should be labeled authfail:\n%s", output)
+	}
+}
+
 func TestTUIUpdateNotificationDevBuild(t *testing.T) {
 	m := newTuiModel("http://localhost")
 	m.currentView = tuiViewQueue
@@ -1662,3 +1724,32 @@ func TestTUIFixTriggerResultMsg(t *testing.T) {
 		}
 	})
 }
+
+func TestTUIGetDisplayNameRepoNames(t *testing.T) {
+	configured := t.TempDir()
+	unconfigured := t.TempDir()
+	withRepoConfig := t.TempDir()
+	if err := os.WriteFile(filepath.Join(withRepoConfig, ".roborev.toml"), []byte(`display_name = "From Repo"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTuiModel("http://localhost")
+	m.repoNames = map[string]string{configured: "Configured"}
+
+	if got := m.getDisplayName(configured, "default"); got != "Configured" {
+		t.Errorf("configured repo: got %q, want %q", got, "Configured")
+	}
+	if got := m.getDisplayName(unconfigured, "default"); got != "default" {
+		t.Errorf("unconfigured repo: got %q, want fallback %q", got, "default")
+	}
+	if got := m.getDisplayName(withRepoConfig, "default"); got != "From Repo" {
+		t.Errorf("repo display_name: got %q, want %q", got, "From Repo")
+	}
+
+	// Global table wins over the repo's own display_name
+	m.repoNames[filepath.Base(withRepoConfig)] = "By Basename"
+	m.updateDisplayNameCache([]storage.ReviewJob{{RepoPath: withRepoConfig}})
+	if got := m.getDisplayName(withRepoConfig, "default"); got != "By Basename" {
+		t.Errorf("basename override: got %q, want %q", got, "By Basename")
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/roborev-dev/roborev/internal/storage"
 )
@@ -180,6 +183,44 @@ func TestWaitArgValidationWithoutDaemon(t *testing.T) {
 	}
 }
 
+func TestWaitSHAPrefixUnknownLocally(t *testing.T) {
+	setupFastPolling(t)
+
+	t.Run("daemon expands the prefix", func(t *testing.T) {
+		var gotRef string
+		newWaitEnv(t, newWaitMockHandler(mockConfig{
+			OnJobsQuery: func(r *http.Request) {
+				if ref := r.URL.Query().Get("git_ref"); ref != "" {
+					gotRef = ref
+				}
+			},
+			Jobs:   []storage.ReviewJob{{ID: 1, Agent: "test", Status: "done"}},
+			Review: &storage.Review{ID: 1, JobID: 1, Agent: "test", Output: "No issues found."},
+		}))
+
+		// Not a commit in this clone, so it can't be resolved by git
+		if _, err := runWait(t, "DEADBEEF", "--quiet"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotRef != "deadbeef" {
+			t.Errorf("git_ref sent to daemon = %q, want the prefix", gotRef)
+		}
+	})
+
+	t.Run("ambiguous prefix", func(t *testing.T) {
+		newWaitEnv(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			respondJSON(w, http.StatusConflict, map[string]any{
+				"error": map[string]any{"message": "ambiguous SHA: deadbeef matches deadbeef1, deadbeef2"},
+			})
+		}))
+
+		_, err := runWait(t, "deadbeef")
+		if err == nil || !strings.Contains(err.Error(), "deadbeef1, deadbeef2") {
+			t.Errorf("expected the ambiguity to list candidates, got: %v", err)
+		}
+	})
+}
+
 func TestWait_Scenarios(t *testing.T) {
 	setupFastPolling(t)
 
@@ -339,6 +380,39 @@ func TestWaitReviewFetchErrorIsPlainError(t *testing.T) {
 	}
 }
 
+func TestWaitTimeout(t *testing.T) {
+	setupFastPolling(t)
+
+	mock := mockConfig{
+		Jobs: []storage.ReviewJob{{ID: 42, Agent: "test", Status: "running"}},
+	}
+
+	t.Run("exits 2 and names the job", func(t *testing.T) {
+		newWaitEnv(t, newWaitMockHandler(mock))
+		stdout, err := runWait(t, "--job", "42", "--timeout", "50ms")
+		requireExitCode(t, err, 2)
+		if !strings.Contains(stdout, "Timed out") || !strings.Contains(stdout, "job 42") {
+			t.Errorf("expected timeout message naming job 42, got: %q", stdout)
+		}
+	})
+
+	t.Run("quiet suppresses message but keeps exit code", func(t *testing.T) {
+		newWaitEnv(t, newWaitMockHandler(mock))
+		stdout, err := runWait(t, "--job", "42", "--timeout", "50ms", "--quiet")
+		requireExitCode(t, err, 2)
+		if stdout != "" {
+			t.Errorf("expected no stdout in quiet mode, got: %q", stdout)
+		}
+	})
+
+	t.Run("negative timeout is rejected", func(t *testing.T) {
+		_, err := runWait(t, "--job", "42", "--timeout", "-1s")
+		if err == nil || !strings.Contains(err.Error(), "--timeout") {
+			t.Errorf("expected --timeout validation error, got: %v", err)
+		}
+	})
+}
+
 func TestWaitLookupNon200Response(t *testing.T) {
 	setupFastPolling(t)
 	newWaitEnv(t, newWaitMockHandler(mockConfig{
@@ -426,3 +500,125 @@ func TestWaitWorktreeResolvesRefFromWorktreeAndRepoFromMain(t *testing.T) {
 		t.Errorf("expected repo=%s (main repo) in query, got: %s", repoDir, lookupQuery)
 	}
 }
+
+func TestWaitMultiple(t *testing.T) {
+	setupFastPolling(t)
+
+	// Jobs 1 and 2 are done (PASS and FAIL); job 3 never finishes
+	statuses := map[string]storage.JobStatus{"1": "done", "2": "done", "3": "running", "4": "done"}
+	outputs := map[string]string{"1": "No issues found.", "2": "- High: unchecked error", "4": "No issues found."}
+	verdicts := map[string]string{"1": "P", "2": "F"}
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	finished := started.Add(1500 * time.Millisecond)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/jobs":
+			id := r.URL.Query().Get("id")
+			var jobs []storage.ReviewJob
+			if status, ok := statuses[id]; ok {
+				jobID, _ := strconv.ParseInt(id, 10, 64)
+				job := storage.ReviewJob{ID: jobID, Agent: "test", Status: status, StartedAt: &started}
+				if status == "done" {
+					job.FinishedAt = &finished
+				}
+				if v, ok := verdicts[id]; ok {
+					job.Verdict = &v
+				}
+				jobs = append(jobs, job)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"jobs": jobs, "has_more": false})
+		case "/api/review":
+			id := r.URL.Query().Get("job_id")
+			jobID, _ := strconv.ParseInt(id, 10, 64)
+			json.NewEncoder(w).Encode(storage.Review{JobID: jobID, Agent: "test", Output: outputs[id]})
+		}
+	})
+
+	t.Run("reports each job in order", func(t *testing.T) {
+		newWaitEnv(t, handler)
+		stdout, err := runWait(t, "--job", "1", "2")
+		requireExitCode(t, err, 1)
+		if !strings.Contains(stdout, "Job 1: PASS\nJob 2: FAIL") {
+			t.Errorf("expected per-job results in order, got: %q", stdout)
+		}
+	})
+
+	t.Run("all pass exits 0", func(t *testing.T) {
+		newWaitEnv(t, handler)
+		stdout, err := runWait(t, "1", "1")
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if strings.Count(stdout, "PASS") != 2 {
+			t.Errorf("expected two PASS lines, got: %q", stdout)
+		}
+	})
+
+	t.Run("timeout applies to the batch", func(t *testing.T) {
+		newWaitEnv(t, handler)
+		stdout, err := runWait(t, "--job", "1", "3", "--timeout", "50ms")
+		requireExitCode(t, err, 2)
+		if !strings.Contains(stdout, "Job 1: PASS") || !strings.Contains(stdout, "timed out waiting for job 3") {
+			t.Errorf("expected job 1 result and job 3 timeout, got: %q", stdout)
+		}
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		newWaitEnv(t, handler)
+		stdout, err := runWait(t, "--job", "1", "2", "3", "--timeout", "50ms", "--format", "json")
+		requireExitCode(t, err, 2)
+		var results []waitResult
+		if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+			t.Fatalf("expected a JSON array, got %q: %v", stdout, err)
+		}
+		want := []waitResult{
+			{JobID: 1, Verdict: "P", Status: "done", DurationMs: 1500},
+			{JobID: 2, Verdict: "F", Status: "done", DurationMs: 1500},
+			{JobID: 3, Status: "running", Error: "timed out"},
+		}
+		if !slices.Equal(results, want) {
+			t.Errorf("results = %+v, want %+v", results, want)
+		}
+	})
+
+	t.Run("json format for a single job", func(t *testing.T) {
+		newWaitEnv(t, handler)
+		stdout, err := runWait(t, "--job", "1", "--format", "json")
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if !strings.Contains(stdout, `"verdict": "P"`) || strings.Contains(stdout, "No issues found") {
+			t.Errorf("expected only the JSON result, got: %q", stdout)
+		}
+	})
+
+	t.Run("json verdict comes from the stored job verdict", func(t *testing.T) {
+		newWaitEnv(t, handler)
+		stdout, err := runWait(t, "--job", "4", "--format", "json")
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		var results []waitResult
+		if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+			t.Fatalf("expected a JSON array, got %q: %v", stdout, err)
+		}
+		want := []waitResult{{JobID: 4, Status: "done", DurationMs: 1500}}
+		if !slices.Equal(results, want) {
+			t.Errorf("results = %+v, want %+v", results, want)
+		}
+	})
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		_, err := runWait(t, "1", "--format", "yaml")
+		if err == nil || !strings.Contains(err.Error(), "invalid --format") {
+			t.Errorf("expected invalid --format error, got: %v", err)
+		}
+	})
+
+	t.Run("rejects non-numeric arguments", func(t *testing.T) {
+		_, err := runWait(t, "1", "HEAD")
+		if err == nil || !strings.Contains(err.Error(), "invalid job ID") {
+			t.Errorf("expected invalid job ID error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/daemon"
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func watchFilesCmd() *cobra.Command {
+	var (
+		agentName string
+		reasoning string
+		debounce  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch-files",
+		Short: "Review uncommitted changes whenever tracked files are saved",
+		Long: `Watch the working tree and run a review of uncommitted changes
+(like review --dirty) each time tracked files are saved. Bursts of saves
+are coalesced into one review once no file has changed for the debounce
+interval, and saves made while a review runs queue a single follow-up
+review. The latest verdict is shown in a one-line status.
+
+Limit which files trigger reviews in .roborev.toml:
+
+  [watch]
+  include = ["*.go", "web/src/"]
+  exclude = ["*_test.go", "vendor/"]
+
+Examples:
+  roborev watch-files
+  roborev watch-files --debounce 5s --reasoning fast
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if debounce <= 0 {
+				return fmt.Errorf("--debounce must be positive")
+			}
+			root, err := requireRepoRoot()
+			if err != nil {
+				return err
+			}
+			repoCfg, err := config.LoadRepoConfig(root)
+			if err != nil {
+				return fmt.Errorf("load repo config: %w", err)
+			}
+			var watchCfg config.WatchConfig
+			if repoCfg != nil {
+				watchCfg = repoCfg.Watch
+			}
+			if err := validateWatchGlobs(watchCfg); err != nil {
+				return err
+			}
+
+			if err := ensureDaemon(); err != nil {
+				return err
+			}
+
+			fsw, err := fsnotify.NewWatcher()
+			if err != nil {
+				return fmt.Errorf("create file watcher: %w", err)
+			}
+			defer fsw.Close()
+
+			debouncer := newSaveDebouncer(debounce)
+			defer debouncer.Stop()
+			w := &fileWatcher{root: root, filter: watchCfg, fsw: fsw, debouncer: debouncer}
+			if err := w.refresh(); err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sigCh, stopSignals := setupSignalHandler()
+			defer stopSignals()
+			go func() {
+				select {
+				case <-sigCh:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			status := newWatchStatus(cmd.OutOrStdout())
+			defer status.finish()
+			status.set("Watching %d tracked files in %s (Ctrl+C to stop)", w.trackedCount(), root)
+
+			// Reviews run one at a time off the debouncer so the event
+			// loop keeps draining fsnotify while an agent works.
+			go func() {
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-debouncer.C:
+					}
+					status.set("Reviewing uncommitted changes...")
+					status.set("%s", runWatchReview(ctx, root, agentName, reasoning))
+					if err := w.refresh(); err != nil {
+						status.set("Warning: %v", err)
+					}
+				}
+			}()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case event, ok := <-fsw.Events:
+					if !ok {
+						return nil
+					}
+					w.handleEvent(event)
+				case err, ok := <-fsw.Errors:
+					if !ok {
+						return nil
+					}
+					status.set("Watcher error: %v", err)
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&agentName, "agent", "", "agent to use (default: from config)")
+	cmd.Flags().StringVar(&reasoning, "reasoning", "", "reasoning level: thorough, standard, or fast")
+	cmd.Flags().DurationVar(&debounce, "debounce", 2*time.Second, "wait this long after the last save before reviewing")
+	registerAgentCompletion(cmd)
+	registerReasoningCompletion(cmd)
+
+	return cmd
+}
+
+// saveDebouncer coalesces bursts of Notify calls into one signal on C,
+// sent once delay has passed without another call. C holds at most one
+// pending signal, so any number of bursts during a running review
+// collapse into a single follow-up.
+type saveDebouncer struct {
+	C chan struct{}
+
+	delay time.Duration
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+func newSaveDebouncer(delay time.Duration) *saveDebouncer {
+	return &saveDebouncer{C: make(chan struct{}, 1), delay: delay}
+}
+
+// Notify records a save, restarting the quiet period.
+func (d *saveDebouncer) Notify() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(d.delay, func() {
+		select {
+		case d.C <- struct{}{}:
+		default: // A review is already pending
+		}
+	})
+}
+
+// Stop cancels any pending signal.
+func (d *saveDebouncer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
+
+// fileWatcher filters fsnotify events down to saves of tracked files that
+// pass the [watch] globs and feeds them to the debouncer.
+type fileWatcher struct {
+	root      string
+	filter    config.WatchConfig
+	fsw       *fsnotify.Watcher // nil in tests
+	debouncer *saveDebouncer
+
+	mu      sync.Mutex
+	tracked map[string]bool // repo-relative, slash-separated
+}
+
+// refresh reloads the tracked file list and watches any new directories
+// containing tracked files. fsnotify isn't recursive, so each directory is
+// added individually.
+func (w *fileWatcher) refresh() error {
+	files, err := git.GetTrackedFiles(w.root)
+	if err != nil {
+		return err
+	}
+	w.setTracked(files)
+	if w.fsw == nil {
+		return nil
+	}
+
+	dirs := map[string]bool{".": true}
+	for _, f := range files {
+		dirs[path.Dir(f)] = true
+	}
+	watched := make(map[string]bool)
+	for _, d := range w.fsw.WatchList() {
+		watched[d] = true
+	}
+	for d := range dirs {
+		abs := filepath.Join(w.root, filepath.FromSlash(d))
+		if watched[abs] {
+			continue
+		}
+		if err := w.fsw.Add(abs); err != nil {
+			return fmt.Errorf("watch %s: %w", abs, err)
+		}
+	}
+	return nil
+}
+
+func (w *fileWatcher) setTracked(files []string) {
+	tracked := make(map[string]bool, len(files))
+	for _, f := range files {
+		tracked[f] = true
+	}
+	w.mu.Lock()
+	w.tracked = tracked
+	w.mu.Unlock()
+}
+
+func (w *fileWatcher) trackedCount() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.tracked)
+}
+
+// handleEvent notifies the debouncer if event is a save of a watched file
+// and reports whether it did.
+func (w *fileWatcher) handleEvent(event fsnotify.Event) bool {
+	// Rename and Create cover editors that save by writing a temp file and
+	// renaming it over the original.
+	if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(w.root, event.Name)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	w.mu.Lock()
+	tracked := w.tracked[rel]
+	w.mu.Unlock()
+	if !tracked || !watchPathIncluded(w.filter, rel) {
+		return false
+	}
+	w.debouncer.Notify()
+	return true
+}
+
+// watchPathIncluded applies the [watch] include/exclude globs to a
+// repo-relative path.
+func watchPathIncluded(cfg config.WatchConfig, rel string) bool {
+	for _, p := range cfg.Exclude {
+		if matchWatchGlob(p, rel) {
+			return false
+		}
+	}
+	if len(cfg.Include) == 0 {
+		return true
+	}
+	for _, p := range cfg.Include {
+		if matchWatchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWatchGlob matches pattern against the full path or the basename;
+// a trailing "/" makes it a directory prefix.
+func matchWatchGlob(pattern, rel string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+		return rel == dir || strings.HasPrefix(rel, dir+"/")
+	}
+	if ok, _ := path.Match(pattern, rel); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, path.Base(rel))
+	return ok
+}
+
+func validateWatchGlobs(cfg config.WatchConfig) error {
+	for _, p := range append(cfg.Include, cfg.Exclude...) {
+		if _, err := path.Match(strings.TrimSuffix(p, "/"), ""); err != nil {
+			return fmt.Errorf("invalid [watch] glob %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// runWatchReview enqueues a dirty review, waits for it, and returns a
+// one-line summary of the outcome.
+func runWatchReview(ctx context.Context, root, agentName, reasoning string) string {
+	diff, err := git.GetDirtyDiff(root)
+	if err != nil {
+		return fmt.Sprintf("Error: get dirty diff: %v", err)
+	}
+	if diff == "" {
+		return "No uncommitted changes"
+	}
+	if len(diff) > MaxDirtyDiffSize {
+		return fmt.Sprintf("Skipped: diff too large (%d bytes, max %d bytes)", len(diff), MaxDirtyDiffSize)
+	}
+
+	job, err := enqueueDirtyReview(root, agentName, reasoning, diff)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	review, err := waitForJobCompletion(ctx, getDaemonAddr(), job.ID, nil)
+	if err != nil {
+		return fmt.Sprintf("Job %d: %v", job.ID, err)
+	}
+	verdict := "PASS"
+	if review.Verdict() != "P" {
+		verdict = "FAIL"
+	}
+	return fmt.Sprintf("%s (job %d, %s) - roborev show %d", verdict, job.ID, review.ProducedBy(), job.ID)
+}
+
+// enqueueDirtyReview posts a review of uncommitted changes to the daemon.
+func enqueueDirtyReview(root, agentName, reasoning, diff string) (*storage.ReviewJob, error) {
+	reqBody, _ := json.Marshal(map[string]any{
+		"repo_path":    root,
+		"git_ref":      "dirty",
+		"branch":       git.GetCurrentBranch(root),
+		"agent":        agentName,
+		"reasoning":    reasoning,
+		"diff_content": diff,
+	})
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := daemon.PostWithRetry(client, getDaemonAddr()+"/api/enqueue", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusOK {
+		var skipResp struct {
+			Skipped bool   `json:"skipped"`
+			Reason  string `json:"reason"`
+		}
+		if err := json.Unmarshal(body, &skipResp); err == nil && skipResp.Skipped {
+			return nil, fmt.Errorf("skipped: %s", skipResp.Reason)
+		}
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("enqueue failed: %s", readErrorBody(bytes.NewReader(body), resp.Status))
+	}
+
+	var job storage.ReviewJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &job, nil
+}
+
+// watchStatus prints timestamped status lines. On a terminal each line
+// overwrites the previous one; otherwise lines are appended.
+type watchStatus struct {
+	mu      sync.Mutex
+	w       io.Writer
+	inPlace bool
+	lastLen int
+}
+
+func newWatchStatus(w io.Writer) *watchStatus {
+	return &watchStatus{w: w, inPlace: writerIsTerminal(w)}
+}
+
+func (s *watchStatus) set(format string, args ...any) {
+	line := fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), fmt.Sprintf(format, args...))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.inPlace {
+		fmt.Fprintln(s.w, line)
+		return
+	}
+	pad := max(0, s.lastLen-len(line))
+	fmt.Fprintf(s.w, "\r%s%s", line, strings.Repeat(" ", pad))
+	s.lastLen = len(line)
+}
+
+// finish ends an in-place status line.
+func (s *watchStatus) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inPlace && s.lastLen > 0 {
+		fmt.Fprintln(s.w)
+	}
+}
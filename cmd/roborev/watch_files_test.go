@@ -0,0 +1,106 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/roborev-dev/roborev/internal/config"
+)
+
+// countSignals drains d.C until no signal arrives within quiet.
+func countSignals(d *saveDebouncer, quiet time.Duration) int {
+	n := 0
+	for {
+		select {
+		case <-d.C:
+			n++
+		case <-time.After(quiet):
+			return n
+		}
+	}
+}
+
+func TestFileWatcherCoalescesBurst(t *testing.T) {
+	root := t.TempDir()
+	d := newSaveDebouncer(50 * time.Millisecond)
+	defer d.Stop()
+	w := &fileWatcher{root: root, debouncer: d}
+	w.setTracked([]string{"main.go", "pkg/util.go", "README.md"})
+
+	event := func(rel string, op fsnotify.Op) fsnotify.Event {
+		return fsnotify.Event{Name: filepath.Join(root, filepath.FromSlash(rel)), Op: op}
+	}
+
+	// A burst of saves across files, including editor temp files and
+	// untracked paths that must not count.
+	burst := []fsnotify.Event{
+		event("main.go", fsnotify.Write),
+		event("main.go.swp", fsnotify.Create),
+		event("pkg/util.go", fsnotify.Write),
+		event("pkg/util.go", fsnotify.Chmod),
+		event(".git/index", fsnotify.Write),
+		event("main.go", fsnotify.Rename),
+		event("main.go", fsnotify.Create),
+		event("README.md", fsnotify.Write),
+	}
+	for _, ev := range burst {
+		w.handleEvent(ev)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := countSignals(d, 200*time.Millisecond); got != 1 {
+		t.Fatalf("expected 1 review trigger for burst, got %d", got)
+	}
+
+	// Untracked-only activity triggers nothing.
+	w.handleEvent(event("scratch.txt", fsnotify.Write))
+	if got := countSignals(d, 150*time.Millisecond); got != 0 {
+		t.Errorf("expected no trigger for untracked file, got %d", got)
+	}
+}
+
+func TestSaveDebouncerQueuesOneWhileBusy(t *testing.T) {
+	d := newSaveDebouncer(10 * time.Millisecond)
+	defer d.Stop()
+
+	// Two separate bursts while nobody reads C (a review is running)
+	// leave exactly one pending trigger.
+	d.Notify()
+	time.Sleep(50 * time.Millisecond)
+	d.Notify()
+	time.Sleep(50 * time.Millisecond)
+
+	if got := countSignals(d, 100*time.Millisecond); got != 1 {
+		t.Errorf("expected 1 pending trigger, got %d", got)
+	}
+}
+
+func TestWatchPathIncluded(t *testing.T) {
+	cfg := config.WatchConfig{
+		Include: []string{"*.go", "web/src/"},
+		Exclude: []string{"*_test.go", "vendor/"},
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"main.go", true},
+		{"internal/db/db.go", true},
+		{"internal/db/db_test.go", false},
+		{"vendor/lib/lib.go", false},
+		{"web/src/app.ts", true},
+		{"web/public/index.html", false},
+		{"README.md", false},
+	}
+	for _, tt := range tests {
+		if got := watchPathIncluded(cfg, tt.path); got != tt.want {
+			t.Errorf("watchPathIncluded(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if !watchPathIncluded(config.WatchConfig{}, "anything.txt") {
+		t.Error("empty config should include all files")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
@@ -77,8 +79,13 @@ type CommandAgent interface {
 	CommandName() string
 }
 
-// Registry holds available agents
-var registry = make(map[string]Agent)
+// Registry holds available agents. Built-in agents register at init;
+// custom agents from config are swapped in by SetCustomAgents at runtime.
+var (
+	registryMu   sync.RWMutex
+	registry     = make(map[string]Agent)
+	customAgents = make(map[string]bool)
+)
 var allowUnsafeAgents atomic.Bool
 var anthropicAPIKey atomic.Value
 
@@ -124,13 +131,17 @@ func CanonicalName(name string) string {
 
 // Register adds an agent to the registry
 func Register(a Agent) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	registry[a.Name()] = a
 }
 
 // Get returns an agent by name (supports aliases like "claude" for "claude-code")
 func Get(name string) (Agent, error) {
 	name = resolveAlias(name)
+	registryMu.RLock()
 	a, ok := registry[name]
+	registryMu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("unknown agent: %s", name)
 	}
@@ -139,6 +150,8 @@ func Get(name string) (Agent, error) {
 
 // Available returns the names of all registered agents
 func Available() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	names := make([]string, 0, len(registry))
 	for name := range registry {
 		names = append(names, name)
@@ -150,7 +163,9 @@ func Available() []string {
 // Supports aliases like "claude" for "claude-code"
 func IsAvailable(name string) bool {
 	name = resolveAlias(name)
+	registryMu.RLock()
 	a, ok := registry[name]
+	registryMu.RUnlock()
 	if !ok {
 		return false
 	}
@@ -165,6 +180,56 @@ func IsAvailable(name string) bool {
 	return true
 }
 
+// ValidateName returns an error listing the known agents if name is not
+// one of them. Supports aliases.
+func ValidateName(name string) error {
+	if _, err := Get(name); err != nil {
+		known := Available()
+		sort.Strings(known)
+		return fmt.Errorf("unknown agent %q (known agents: %s)", name, strings.Join(known, ", "))
+	}
+	return nil
+}
+
+// CheckAgentAvailable returns an actionable error if name is not a known
+// agent or its executable is not on PATH. Supports aliases.
+func CheckAgentAvailable(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	a, _ := Get(name)
+	ca, ok := a.(CommandAgent)
+	if !ok {
+		return nil
+	}
+	if _, err := exec.LookPath(ca.CommandName()); err != nil {
+		return fmt.Errorf("agent %s is not installed: %q was not found in PATH\nInstall it, or pick another agent with --agent or default_agent in ~/.roborev/config.toml", a.Name(), ca.CommandName())
+	}
+	return nil
+}
+
+// ProbeAgentVersion runs the agent's executable with --version and returns
+// the first line of its output. Agents without an executable report "".
+func ProbeAgentVersion(ctx context.Context, name string) (string, error) {
+	if err := CheckAgentAvailable(name); err != nil {
+		return "", err
+	}
+	a, _ := Get(name)
+	ca, ok := a.(CommandAgent)
+	if !ok {
+		return "", nil
+	}
+	out, err := exec.CommandContext(ctx, ca.CommandName(), "--version").CombinedOutput()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("%s --version did not respond: %w", ca.CommandName(), ctx.Err())
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s --version failed: %w", ca.CommandName(), err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(line), nil
+}
+
 // GetAvailable returns an available agent, trying the requested one first,
 // then falling back to alternatives. Returns error only if no agents available.
 // Supports aliases like "claude" for "claude-code"
@@ -187,7 +252,7 @@ func GetAvailable(preferred string) (Agent, error) {
 
 	// List what's actually available for error message (exclude test agent)
 	var available []string
-	for name := range registry {
+	for _, name := range Available() {
 		if name != "test" && IsAvailable(name) {
 			available = append(available, name)
 		}
@@ -44,6 +44,64 @@ func TestAvailableAgents(t *testing.T) {
 	}
 }
 
+// fakeCommandAgent is a test agent backed by an executable.
+type fakeCommandAgent struct {
+	*TestAgent
+	name string
+	cmd  string
+}
+
+func (a *fakeCommandAgent) Name() string        { return a.name }
+func (a *fakeCommandAgent) CommandName() string { return a.cmd }
+
+// registerFakeCommandAgent registers a command agent for the test's duration.
+func registerFakeCommandAgent(t *testing.T, name, cmd string) {
+	t.Helper()
+	Register(&fakeCommandAgent{TestAgent: NewTestAgent(), name: name, cmd: cmd})
+	t.Cleanup(func() { delete(registry, name) })
+}
+
+func TestCheckAgentAvailable(t *testing.T) {
+	registerFakeCommandAgent(t, "fake-missing", "roborev-no-such-binary")
+
+	if err := CheckAgentAvailable("test"); err != nil {
+		t.Errorf("test agent: unexpected error %v", err)
+	}
+
+	err := CheckAgentAvailable("fake-missing")
+	if err == nil {
+		t.Fatal("expected error for agent whose command is missing")
+	}
+	for _, want := range []string{"fake-missing", "roborev-no-such-binary", "not found in PATH", "--agent"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	err = CheckAgentAvailable("no-such-agent")
+	if err == nil || !strings.Contains(err.Error(), "unknown agent") || !strings.Contains(err.Error(), "codex") {
+		t.Errorf("unknown agent: got %v, want error listing known agents", err)
+	}
+}
+
+func TestProbeAgentVersion(t *testing.T) {
+	ok := writeTempCommand(t, "#!/bin/sh\necho 'fake 1.2.3'\necho 'extra detail'\n")
+	broken := writeTempCommand(t, "#!/bin/sh\nexit 3\n")
+	registerFakeCommandAgent(t, "fake-ok", ok)
+	registerFakeCommandAgent(t, "fake-broken", broken)
+
+	version, err := ProbeAgentVersion(context.Background(), "fake-ok")
+	if err != nil || version != "fake 1.2.3" {
+		t.Errorf("fake-ok: got (%q, %v), want first line of output", version, err)
+	}
+	if _, err := ProbeAgentVersion(context.Background(), "fake-broken"); err == nil {
+		t.Error("fake-broken: expected error for nonzero exit")
+	}
+	if version, err := ProbeAgentVersion(context.Background(), "test"); err != nil || version != "" {
+		t.Errorf("test agent: got (%q, %v), want empty version", version, err)
+	}
+}
+
 func TestSyncWriter(t *testing.T) {
 	t.Run("nil input returns nil", func(t *testing.T) {
 		sw := newSyncWriter(nil)
@@ -163,7 +163,8 @@ func (a *ClaudeAgent) Review(ctx context.Context, repoPath, commitSHA, prompt st
 	}
 
 	// Parse stream-json output
-	result, err := parseStreamJSON(stdoutPipe, output)
+	result, usage, err := parseStreamJSON(stdoutPipe, output)
+	recordUsage(ctx, usage)
 
 	if waitErr := cmd.Wait(); waitErr != nil {
 		// Build a detailed error including any partial output and stream errors
@@ -204,16 +205,26 @@ type claudeStreamMessage struct {
 	Error  struct {
 		Message string `json:"message,omitempty"`
 	} `json:"error,omitempty"`
+	// Reported on the final "result" event
+	TotalCostUSD float64 `json:"total_cost_usd,omitempty"`
+	Usage        struct {
+		InputTokens              int64 `json:"input_tokens"`
+		CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		OutputTokens             int64 `json:"output_tokens"`
+	} `json:"usage,omitempty"`
 }
 
-// parseStreamJSON parses Claude's stream-json output and extracts the final result.
+// parseStreamJSON parses Claude's stream-json output and extracts the final result
+// and the token usage and cost reported on the result event.
 // Uses bufio.Reader.ReadString to read lines without buffer size limits.
-// On success, returns (result, nil). On failure, returns (partialOutput, error)
+// On success, returns (result, usage, nil). On failure, returns (partialOutput, usage, error)
 // where partialOutput contains any assistant messages collected before the error.
-func parseStreamJSON(r io.Reader, output io.Writer) (string, error) {
+func parseStreamJSON(r io.Reader, output io.Writer) (string, Usage, error) {
 	br := bufio.NewReader(r)
 
 	var lastResult string
+	var usage Usage
 	var assistantMessages []string
 	var errorMessages []string
 	var validEventsParsed bool
@@ -221,7 +232,7 @@ func parseStreamJSON(r io.Reader, output io.Writer) (string, error) {
 	for {
 		line, err := br.ReadString('\n')
 		if err != nil && err != io.EOF {
-			return "", fmt.Errorf("read stream: %w", err)
+			return "", usage, fmt.Errorf("read stream: %w", err)
 		}
 
 		// Process line even if EOF (might have trailing content without newline)
@@ -245,6 +256,13 @@ func parseStreamJSON(r io.Reader, output io.Writer) (string, error) {
 				if msg.Type == "result" && msg.Result != "" {
 					lastResult = msg.Result
 				}
+				if msg.Type == "result" {
+					usage = Usage{
+						InputTokens:  msg.Usage.InputTokens + msg.Usage.CacheCreationInputTokens + msg.Usage.CacheReadInputTokens,
+						OutputTokens: msg.Usage.OutputTokens,
+						CostUSD:      msg.TotalCostUSD,
+					}
+				}
 
 				// Capture error events from Claude Code
 				if msg.Type == "error" && msg.Error.Message != "" {
@@ -261,7 +279,7 @@ func parseStreamJSON(r io.Reader, output io.Writer) (string, error) {
 
 	// Error if we didn't parse any valid events
 	if !validEventsParsed {
-		return "", fmt.Errorf("no valid stream-json events parsed from output")
+		return "", usage, fmt.Errorf("no valid stream-json events parsed from output")
 	}
 
 	// Build partial output for error context
@@ -269,20 +287,20 @@ func parseStreamJSON(r io.Reader, output io.Writer) (string, error) {
 
 	// If error events were received but we got no result, report them with any partial output
 	if len(errorMessages) > 0 && lastResult == "" {
-		return partial, fmt.Errorf("stream errors: %s", strings.Join(errorMessages, "; "))
+		return partial, usage, fmt.Errorf("stream errors: %s", strings.Join(errorMessages, "; "))
 	}
 
 	// Prefer the result field if present, otherwise join assistant messages
 	if lastResult != "" {
-		return lastResult, nil
+		return lastResult, usage, nil
 	}
 	if len(assistantMessages) > 0 {
-		return strings.Join(assistantMessages, "\n"), nil
+		return strings.Join(assistantMessages, "\n"), usage, nil
 	}
 
 	// Valid events were parsed but no result or assistant content found
 	// This is not an error - Claude might have used tools without text output
-	return "", nil
+	return "", usage, nil
 }
 
 // filterEnv returns a copy of env with the specified key removed
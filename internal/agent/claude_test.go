@@ -153,7 +153,7 @@ still not json
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.expectOutput {
 				var out bytes.Buffer
-				res, err := parseStreamJSON(strings.NewReader(tt.input), &out)
+				res, _, err := parseStreamJSON(strings.NewReader(tt.input), &out)
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
 				}
@@ -166,7 +166,7 @@ still not json
 				return
 			}
 
-			res, err := parseStreamJSON(strings.NewReader(tt.input), nil)
+			res, _, err := parseStreamJSON(strings.NewReader(tt.input), nil)
 
 			if tt.expectedErr != "" {
 				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
@@ -184,6 +184,22 @@ still not json
 	}
 }
 
+func TestParseStreamJSONUsage(t *testing.T) {
+	input := `{"type":"result","result":"done","total_cost_usd":0.0425,"usage":{"input_tokens":10,"cache_creation_input_tokens":500,"cache_read_input_tokens":1000,"output_tokens":200}}` + "\n"
+
+	res, usage, err := parseStreamJSON(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res != "done" {
+		t.Errorf("expected result %q, got %q", "done", res)
+	}
+	want := Usage{InputTokens: 1510, OutputTokens: 200, CostUSD: 0.0425}
+	if usage != want {
+		t.Errorf("usage = %+v, want %+v", usage, want)
+	}
+}
+
 func TestAnthropicAPIKey(t *testing.T) {
 	// Clear any existing key
 	SetAnthropicAPIKey("")
@@ -222,7 +222,8 @@ func (a *CodexAgent) Review(ctx context.Context, repoPath, commitSHA, prompt str
 	}
 
 	// Parse JSONL stream from stdout
-	result, parseErr := a.parseStreamJSON(stdoutPipe, sw)
+	result, usage, parseErr := a.parseStreamJSON(stdoutPipe, sw)
+	recordUsage(ctx, usage)
 
 	if waitErr := cmd.Wait(); waitErr != nil {
 		if parseErr != nil {
@@ -259,6 +260,11 @@ type codexEvent struct {
 		Command string `json:"command,omitempty"`
 		Status  string `json:"status,omitempty"`
 	} `json:"item,omitempty"`
+	// Reported on turn.completed
+	Usage struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"usage,omitempty"`
 }
 
 func isCodexEventType(eventType string) bool {
@@ -293,10 +299,12 @@ func codexFailureEventError(ev codexEvent) error {
 
 // parseStreamJSON parses codex's --json JSONL output and extracts review text.
 // Codex emits events like thread.started, turn.started, item.completed (with agent_message),
-// and turn.completed. The agent_message items contain the actual review text.
-func (a *CodexAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (string, error) {
+// and turn.completed. The agent_message items contain the actual review text;
+// token usage is summed across turn.completed events.
+func (a *CodexAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (string, Usage, error) {
 	br := bufio.NewReader(r)
 
+	var usage Usage
 	var validEventsParsed bool
 	var agentMessages []string
 	messageIndexByID := make(map[string]int)
@@ -305,7 +313,7 @@ func (a *CodexAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (string, error
 	for {
 		line, err := br.ReadString('\n')
 		if err != nil && err != io.EOF {
-			return "", fmt.Errorf("read stream: %w", err)
+			return "", usage, fmt.Errorf("read stream: %w", err)
 		}
 
 		trimmed := strings.TrimSpace(line)
@@ -324,6 +332,11 @@ func (a *CodexAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (string, error
 						streamFailure = codexFailureEventError(ev)
 					}
 
+					if ev.Type == "turn.completed" {
+						usage.InputTokens += ev.Usage.InputTokens
+						usage.OutputTokens += ev.Usage.OutputTokens
+					}
+
 					// Collect agent_message text from completed/updated items.
 					// For messages with IDs, keep only the latest text per ID to avoid duplicates
 					// from incremental updates while preserving first-seen order.
@@ -348,18 +361,18 @@ func (a *CodexAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (string, error
 	}
 
 	if !validEventsParsed {
-		return "", errNoCodexJSON
+		return "", usage, errNoCodexJSON
 	}
 
 	if streamFailure != nil {
-		return "", streamFailure
+		return "", usage, streamFailure
 	}
 
 	if len(agentMessages) > 0 {
-		return strings.Join(agentMessages, "\n"), nil
+		return strings.Join(agentMessages, "\n"), usage, nil
 	}
 
-	return "", nil
+	return "", usage, nil
 }
 
 func init() {
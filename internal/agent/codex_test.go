@@ -211,7 +211,7 @@ func TestCodexParseStreamJSON(t *testing.T) {
 				w = newSyncWriter(&buf)
 			}
 
-			result, err := a.parseStreamJSON(strings.NewReader(tt.input), w)
+			result, _, err := a.parseStreamJSON(strings.NewReader(tt.input), w)
 			if tt.wantErr != nil {
 				if !errors.Is(err, tt.wantErr) {
 					t.Errorf("parseStreamJSON() error = %v, wantErr %v", err, tt.wantErr)
@@ -267,6 +267,29 @@ func TestCodexReviewPipesPromptViaStdin(t *testing.T) {
 	}
 }
 
+func TestCodexReviewRecordsUsage(t *testing.T) {
+	withUnsafeAgents(t, false)
+
+	mock := mockAgentCLI(t, MockCLIOpts{
+		HelpOutput: "usage " + codexAutoApproveFlag,
+		StdoutLines: []string{
+			`{"type":"turn.completed","usage":{"input_tokens":1200,"cached_input_tokens":200,"output_tokens":300}}`,
+			`{"type":"item.completed","item":{"type":"agent_message","text":"ok"}}`,
+			`{"type":"turn.completed","usage":{"input_tokens":100,"output_tokens":50}}`,
+		},
+	})
+
+	a := NewCodexAgent(mock.CmdPath)
+	ctx, usage := WithUsageRecorder(context.Background())
+	if _, err := a.Review(ctx, t.TempDir(), "deadbeef", "prompt", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := Usage{InputTokens: 1300, OutputTokens: 350}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+}
+
 func TestCodexReviewNoValidJSONReturnsError(t *testing.T) {
 	withUnsafeAgents(t, false)
 
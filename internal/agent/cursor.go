@@ -127,7 +127,8 @@ func (a *CursorAgent) Review(ctx context.Context, repoPath, commitSHA, prompt st
 	}
 
 	// Reuse Claude's stream-json parser (same format)
-	result, err := a.parseStreamJSON(stdoutPipe, output)
+	result, usage, err := a.parseStreamJSON(stdoutPipe, output)
+	recordUsage(ctx, usage)
 
 	if waitErr := cmd.Wait(); waitErr != nil {
 		if err != nil {
@@ -143,7 +144,7 @@ func (a *CursorAgent) Review(ctx context.Context, repoPath, commitSHA, prompt st
 	return result, nil
 }
 
-func (a *CursorAgent) parseStreamJSON(r io.Reader, output io.Writer) (string, error) {
+func (a *CursorAgent) parseStreamJSON(r io.Reader, output io.Writer) (string, Usage, error) {
 	return parseStreamJSON(r, output)
 }
 
@@ -121,7 +121,7 @@ func TestCursorParseStreamJSON(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cursor := NewCursorAgent("dummy")
-			res, err := cursor.parseStreamJSON(strings.NewReader(tt.input), nil)
+			res, _, err := cursor.parseStreamJSON(strings.NewReader(tt.input), nil)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
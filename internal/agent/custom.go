@@ -0,0 +1,266 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/roborev-dev/roborev/internal/git"
+)
+
+// CustomAgentVars are the variables an [agents.<name>] command template can
+// reference, e.g. `mycli review --diff {{.DiffFile}} --out {{.OutputFile}}`.
+// String values are shell-quoted when non-empty, so they can be used as
+// arguments directly; use {{if .Model}} to omit empty ones. The prompt is
+// sent on stdin unless the command redirects it, as in
+// `mycli review < {{.DiffFile}}` to pipe in the diff instead.
+type CustomAgentVars struct {
+	RepoPath   string // Directory the review runs in (a worktree for fix jobs)
+	SHA        string // Commit SHA or range under review
+	PromptFile string // File holding the full review prompt, which is also sent on stdin
+	DiffFile   string // File holding the diff; empty when the ref is not a commit or range (e.g. dirty reviews)
+	OutputFile string // File the command writes its review to; stdout is used if the template doesn't reference it
+	Model      string
+	Reasoning  string // thorough, standard, or fast
+	Agentic    bool
+}
+
+// CustomAgent runs reviews with a command defined in config. The rendered
+// command runs under sh -c (PowerShell on Windows) in the repo directory,
+// and its review is read from OutputFile or stdout. The verdict is parsed
+// from that output like any other agent's.
+type CustomAgent struct {
+	name           string
+	command        string
+	tmpl           *template.Template
+	usesOutputFile bool
+	Reasoning      ReasoningLevel
+	Agentic        bool
+	Model          string
+}
+
+// NewCustomAgent parses a command template, checking that it starts with
+// an executable and only references fields of CustomAgentVars.
+func NewCustomAgent(name, command string) (*CustomAgent, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+	fields := strings.Fields(command)
+	if strings.Contains(fields[0], "{{") {
+		return nil, fmt.Errorf("command must start with the executable to run, not a template variable")
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(command)
+	if err != nil {
+		return nil, fmt.Errorf("parse command template: %w", err)
+	}
+
+	// Unknown fields only fail at execution, so render once with
+	// placeholders. A marker output file shows whether the template uses it.
+	const marker = "roborev-output-file-marker"
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, CustomAgentVars{
+		RepoPath: "repo", SHA: "sha", PromptFile: "prompt", DiffFile: "diff",
+		OutputFile: marker, Model: "model", Reasoning: "standard",
+	}); err != nil {
+		return nil, fmt.Errorf("command template: %w", err)
+	}
+
+	return &CustomAgent{
+		name:           name,
+		command:        command,
+		tmpl:           tmpl,
+		usesOutputFile: strings.Contains(rendered.String(), marker),
+		Reasoning:      ReasoningStandard,
+	}, nil
+}
+
+func (a *CustomAgent) clone() *CustomAgent {
+	c := *a
+	return &c
+}
+
+// WithReasoning returns a copy of the agent with the specified reasoning level
+func (a *CustomAgent) WithReasoning(level ReasoningLevel) Agent {
+	c := a.clone()
+	c.Reasoning = level
+	return c
+}
+
+// WithAgentic returns a copy of the agent configured for agentic mode.
+func (a *CustomAgent) WithAgentic(agentic bool) Agent {
+	c := a.clone()
+	c.Agentic = agentic
+	return c
+}
+
+// WithModel returns a copy of the agent configured to use the specified model.
+func (a *CustomAgent) WithModel(model string) Agent {
+	if model == "" {
+		return a
+	}
+	c := a.clone()
+	c.Model = model
+	return c
+}
+
+func (a *CustomAgent) Name() string {
+	return a.name
+}
+
+func (a *CustomAgent) CommandName() string {
+	return strings.Fields(a.command)[0]
+}
+
+func (a *CustomAgent) CommandLine() string {
+	return a.command
+}
+
+func (a *CustomAgent) Review(ctx context.Context, repoPath, commitSHA, prompt string, output io.Writer) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "roborev-agent-")
+	if err != nil {
+		return "", fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	promptFile := filepath.Join(tmpDir, "prompt.md")
+	diffFile := filepath.Join(tmpDir, "diff.patch")
+	outputFile := filepath.Join(tmpDir, "review.md")
+	if err := os.WriteFile(promptFile, []byte(prompt), 0o600); err != nil {
+		return "", fmt.Errorf("write prompt file: %w", err)
+	}
+	if err := os.WriteFile(diffFile, []byte(customAgentDiff(repoPath, commitSHA)), 0o600); err != nil {
+		return "", fmt.Errorf("write diff file: %w", err)
+	}
+
+	var rendered strings.Builder
+	if err := a.tmpl.Execute(&rendered, CustomAgentVars{
+		RepoPath:   shellQuote(repoPath),
+		SHA:        shellQuote(commitSHA),
+		PromptFile: shellQuote(promptFile),
+		DiffFile:   shellQuote(diffFile),
+		OutputFile: shellQuote(outputFile),
+		Model:      shellQuote(a.Model),
+		Reasoning:  shellQuote(string(a.Reasoning)),
+		Agentic:    a.Agentic || AllowUnsafeAgents(),
+	}); err != nil {
+		return "", fmt.Errorf("render command: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", rendered.String())
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", rendered.String())
+	}
+	cmd.Dir = repoPath
+	cmd.Stdin = strings.NewReader(prompt)
+
+	var stdout, stderr bytes.Buffer
+	if sw := newSyncWriter(output); sw != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, sw)
+		cmd.Stderr = io.MultiWriter(&stderr, sw)
+	} else {
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %w\nstderr: %s", a.name, err, stderr.String())
+	}
+
+	result := stdout.String()
+	if a.usesOutputFile {
+		data, err := os.ReadFile(outputFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("read %s output: %w", a.name, err)
+		}
+		result = string(data)
+	}
+	if strings.TrimSpace(result) == "" {
+		return "No review output generated", nil
+	}
+	return result, nil
+}
+
+// customAgentDiff returns the diff for a commit or range, or "" for refs
+// that aren't one (dirty reviews and task jobs carry the diff in the
+// prompt instead).
+func customAgentDiff(repoPath, ref string) string {
+	var diff string
+	var err error
+	if strings.Contains(ref, "..") {
+		diff, err = git.GetRangeDiff(repoPath, ref)
+	} else {
+		diff, err = git.GetDiff(repoPath, ref)
+	}
+	if err != nil {
+		return ""
+	}
+	return diff
+}
+
+// shellQuote single-quotes a non-empty value for the shell the command
+// runs under. Empty values stay empty so templates can test them with if.
+func shellQuote(s string) string {
+	if s == "" {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// IsCustomAgent reports whether name is an agent registered from an
+// [agents.<name>] section rather than a built-in one.
+func IsCustomAgent(name string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return customAgents[name]
+}
+
+// SetCustomAgents replaces the registered custom agents with the given
+// [agents.<name>] command templates. Invalid definitions, and names that
+// would shadow a built-in agent or alias, are skipped and reported in the
+// returned error; the valid ones are still registered.
+func SetCustomAgents(commands map[string]string) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for name := range customAgents {
+		delete(registry, name)
+	}
+	customAgents = make(map[string]bool, len(commands))
+
+	var errs []error
+	for _, name := range names {
+		if _, builtin := registry[name]; builtin || aliases[name] != "" {
+			errs = append(errs, fmt.Errorf("agents.%s: name is taken by a built-in agent", name))
+			continue
+		}
+		a, err := NewCustomAgent(name, commands[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("agents.%s: %w", name, err))
+			continue
+		}
+		registry[name] = a
+		customAgents[name] = true
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewCustomAgentValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		wantErr string
+	}{
+		{"valid", "mycli review --diff {{.DiffFile}} --out {{.OutputFile}}", ""},
+		{"conditional", "mycli {{if .Model}}--model {{.Model}}{{end}} {{.PromptFile}}", ""},
+		{"empty", "  ", "command is required"},
+		{"unknown variable", "mycli --diff {{.Diff}}", "can't evaluate field Diff"},
+		{"bad syntax", "mycli {{.DiffFile", "parse command template"},
+		{"template executable", "{{.RepoPath}}/bin/review", "must start with the executable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCustomAgent("mycli", tt.command)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSetCustomAgents(t *testing.T) {
+	t.Cleanup(func() { _ = SetCustomAgents(nil) })
+
+	err := SetCustomAgents(map[string]string{
+		"mycli":  "mycli review {{.PromptFile}}",
+		"codex":  "mycli",
+		"claude": "mycli",
+		"broken": "mycli {{.Nope}}",
+	})
+	if err == nil {
+		t.Fatal("expected errors for the invalid definitions")
+	}
+	for _, want := range []string{"agents.codex: name is taken", "agents.claude: name is taken", "agents.broken:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	a, err := Get("mycli")
+	if err != nil {
+		t.Fatalf("Get(mycli): %v", err)
+	}
+	if a.CommandLine() != "mycli review {{.PromptFile}}" {
+		t.Errorf("CommandLine = %q", a.CommandLine())
+	}
+	if _, ok := a.(CommandAgent); !ok {
+		t.Error("custom agent should be a CommandAgent")
+	}
+	if a, _ := Get("codex"); a == nil || a.Name() != "codex" {
+		t.Error("built-in codex agent was replaced")
+	}
+
+	// Replacing the set drops agents no longer defined
+	if err := SetCustomAgents(map[string]string{"other": "other-cli"}); err != nil {
+		t.Fatalf("SetCustomAgents: %v", err)
+	}
+	if _, err := Get("mycli"); err == nil {
+		t.Error("expected mycli to be unregistered")
+	}
+}
+
+func TestCustomAgentReview(t *testing.T) {
+	skipIfWindows(t)
+
+	// The script echoes its arguments and stdin so the test can check how
+	// the template was rendered.
+	script := writeTempCommand(t, `#!/bin/sh
+prompt=$(cat)
+if [ -n "$4" ]; then
+  printf 'model=%s reasoning=%s stdin=%s prompt=%s\n' "$2" "$3" "$prompt" "$(cat "$5")" > "$4"
+else
+  printf 'stdout review for %s\n' "$1"
+fi
+`)
+
+	t.Run("output file", func(t *testing.T) {
+		a, err := NewCustomAgent("mycli", script+" {{.SHA}} {{.Model}} {{.Reasoning}} {{.OutputFile}} {{.PromptFile}}")
+		if err != nil {
+			t.Fatalf("NewCustomAgent: %v", err)
+		}
+		agent := a.WithModel("big model").WithReasoning(ReasoningThorough)
+		result, err := agent.Review(context.Background(), t.TempDir(), "abc123", "review it", nil)
+		if err != nil {
+			t.Fatalf("Review: %v", err)
+		}
+		want := "model=big model reasoning=thorough stdin=review it prompt=review it\n"
+		if result != want {
+			t.Errorf("result = %q, want %q", result, want)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		a, err := NewCustomAgent("mycli", script+" {{.SHA}}")
+		if err != nil {
+			t.Fatalf("NewCustomAgent: %v", err)
+		}
+		result, err := a.Review(context.Background(), t.TempDir(), "it's-a-ref", "prompt", nil)
+		if err != nil {
+			t.Fatalf("Review: %v", err)
+		}
+		if result != "stdout review for it's-a-ref\n" {
+			t.Errorf("result = %q", result)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		a, err := NewCustomAgent("mycli", "false {{.SHA}}")
+		if err != nil {
+			t.Fatalf("NewCustomAgent: %v", err)
+		}
+		if _, err := a.Review(context.Background(), t.TempDir(), "abc", "prompt", nil); err == nil || !strings.Contains(err.Error(), "mycli failed") {
+			t.Errorf("expected failure error, got %v", err)
+		}
+	})
+}
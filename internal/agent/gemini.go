@@ -143,6 +143,7 @@ func (a *GeminiAgent) Review(ctx context.Context, repoPath, commitSHA, prompt st
 
 	// Parse stream-json output
 	parsed, parseErr := a.parseStreamJSON(stdoutPipe, sw)
+	recordUsage(ctx, parsed.usage)
 
 	if waitErr := cmd.Wait(); waitErr != nil {
 		if parseErr != nil {
@@ -179,11 +180,17 @@ type geminiStreamMessage struct {
 	} `json:"message,omitempty"`
 	// Result field for "result" type events
 	Result string `json:"result,omitempty"`
+	// Token counts for "result" type events
+	Stats struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"stats,omitempty"`
 }
 
 // parseResult contains the parsed result from stream-json output
 type parseResult struct {
 	result string // The extracted result text
+	usage  Usage  // Token usage from the result event, if reported
 }
 
 // parseStreamJSON parses Gemini's stream-json output and extracts the final result.
@@ -193,6 +200,7 @@ func (a *GeminiAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (parseResult,
 	br := bufio.NewReader(r)
 
 	var lastResult string
+	var usage Usage
 	var assistantMessages []string
 	var validEventsParsed bool
 
@@ -228,6 +236,9 @@ func (a *GeminiAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (parseResult,
 				if msg.Type == "result" && msg.Result != "" {
 					lastResult = msg.Result
 				}
+				if msg.Type == "result" {
+					usage = Usage{InputTokens: msg.Stats.InputTokens, OutputTokens: msg.Stats.OutputTokens}
+				}
 			}
 		}
 
@@ -243,13 +254,13 @@ func (a *GeminiAgent) parseStreamJSON(r io.Reader, sw *syncWriter) (parseResult,
 
 	// Prefer the result field if present, otherwise join assistant messages
 	if lastResult != "" {
-		return parseResult{result: lastResult}, nil
+		return parseResult{result: lastResult, usage: usage}, nil
 	}
 	if len(assistantMessages) > 0 {
-		return parseResult{result: strings.Join(assistantMessages, "\n")}, nil
+		return parseResult{result: strings.Join(assistantMessages, "\n"), usage: usage}, nil
 	}
 
-	return parseResult{}, nil
+	return parseResult{usage: usage}, nil
 }
 
 func init() {
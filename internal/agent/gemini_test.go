@@ -220,6 +220,22 @@ No issues found in the code.
 	}
 }
 
+func TestGeminiParseStreamJSONUsage(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"message","role":"assistant","content":"done"}`,
+		`{"type":"result","status":"success","stats":{"total_tokens":1500,"input_tokens":1200,"output_tokens":300}}`,
+	}, "\n") + "\n"
+
+	parsed, err := NewGeminiAgent("gemini").parseStreamJSON(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Usage{InputTokens: 1200, OutputTokens: 300}
+	if parsed.usage != want {
+		t.Errorf("usage = %+v, want %+v", parsed.usage, want)
+	}
+}
+
 func TestGemini_Review_Integration(t *testing.T) {
 	skipIfWindows(t)
 
@@ -0,0 +1,37 @@
+package agent
+
+import "context"
+
+// Usage is the token consumption and cost an agent reported for a run.
+// A zero field means the agent did not report it.
+type Usage struct {
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+}
+
+// IsZero reports whether no usage was recorded.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+type usageRecorderKey struct{}
+
+// WithUsageRecorder returns a context that collects the usage reported by
+// agents whose Review runs under it. The returned Usage accumulates across
+// reviews and stays zero for agents that don't report usage.
+func WithUsageRecorder(ctx context.Context) (context.Context, *Usage) {
+	u := &Usage{}
+	return context.WithValue(ctx, usageRecorderKey{}, u), u
+}
+
+// recordUsage adds u to the recorder installed in ctx, if any.
+func recordUsage(ctx context.Context, u Usage) {
+	rec, ok := ctx.Value(usageRecorderKey{}).(*Usage)
+	if !ok {
+		return
+	}
+	rec.InputTokens += u.InputTokens
+	rec.OutputTokens += u.OutputTokens
+	rec.CostUSD += u.CostUSD
+}
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/roborev-dev/roborev/internal/git"
@@ -42,16 +43,62 @@ type HookConfig struct {
 	Type    string `toml:"type"`    // "beads" for built-in, empty for command
 }
 
+// CustomAgentConfig is an [agents.<name>] section. Command defines a
+// custom agent run by a command template, whose variables are listed in
+// agent.CustomAgentVars. Timeout is a duration like "45m" that overrides
+// agent_timeout for this agent.
+type CustomAgentConfig struct {
+	Command string `toml:"command"`
+	Timeout string `toml:"timeout"`
+}
+
 // Config holds the daemon configuration
 type Config struct {
 	ServerAddr         string `toml:"server_addr"`
 	MaxWorkers         int    `toml:"max_workers"`
+	PerRepoMaxWorkers  int    `toml:"per_repo_max_workers"` // Max concurrent jobs per repo (0 = no limit)
+	MaxFixWorkers      int    `toml:"max_fix_workers"`      // Fix job pool within max_workers; the rest run reviews (0 = no separate pools)
 	ReviewContextCount int    `toml:"review_context_count"`
 	DefaultAgent       string `toml:"default_agent"`
 	DefaultModel       string `toml:"default_model"` // Default model for agents (format varies by agent)
 	DefaultBackupAgent string `toml:"default_backup_agent"`
 	JobTimeoutMinutes  int    `toml:"job_timeout_minutes"`
 
+	// FallbackAgents are tried in order, within the same attempt, when an
+	// agent fails before producing any output (binary missing, API outage).
+	FallbackAgents []string `toml:"fallback_agents"`
+
+	// MaxRetries is how many times a job that fails with a transient error
+	// (the agent exiting non-zero or timing out) is automatically retried,
+	// with exponential backoff between attempts. 0 disables retries.
+	MaxRetries int `toml:"max_retries"`
+
+	// MaxReviewsPerCommit caps how many reviews are kept per commit. When a
+	// review completes, older reviews of its commit beyond the newest N are
+	// pruned; addressed reviews are always kept. 0 keeps all.
+	MaxReviewsPerCommit int `toml:"max_reviews_per_commit"`
+
+	// PauseOnAuthFailure stops workers from claiming new jobs after a job
+	// fails because an agent rejected its credentials, so queued jobs don't
+	// all fail the same way. Resume with `roborev daemon resume`.
+	PauseOnAuthFailure bool `toml:"pause_on_auth_failure"`
+
+	// AgentTimeout overrides the global job_timeout_minutes for every
+	// agent, as a duration like "20m"; a repo's job_timeout_minutes still
+	// wins. A job that runs longer has its agent killed and fails (or is
+	// retried) with a "timed out" error.
+	AgentTimeout string `toml:"agent_timeout"`
+
+	// AgentTimeouts overrides job_timeout_minutes per agent. Keys are an
+	// agent name or "agent:reasoning"; values are durations like "45m".
+	AgentTimeouts map[string]string `toml:"agent_timeouts"`
+
+	// Agents holds per-agent settings by name. A section with a command
+	// defines a custom agent, usable anywhere a built-in agent name is,
+	// e.g. [agents.mycli] command = "mycli review ...". Any section may set
+	// a timeout, including ones for built-in agents.
+	Agents map[string]CustomAgentConfig `toml:"agents"`
+
 	// Workflow-specific agent/model configuration
 	ReviewAgent           string `toml:"review_agent"`
 	ReviewAgentFast       string `toml:"review_agent_fast"`
@@ -102,6 +149,7 @@ type Config struct {
 	DesignBackupAgent   string `toml:"design_backup_agent"`
 
 	AllowUnsafeAgents *bool `toml:"allow_unsafe_agents"` // nil = not set, allows commands to choose their own default
+	SkipDuringRebase  *bool `toml:"skip_during_rebase"`  // nil = not set, defaults to true
 
 	// Agent commands
 	CodexCmd      string `toml:"codex_cmd"`
@@ -111,22 +159,56 @@ type Config struct {
 	// API keys (optional - agents use subscription auth by default)
 	AnthropicAPIKey string `toml:"anthropic_api_key" sensitive:"true"`
 
+	// SensitiveKeys lists additional config keys whose values are masked
+	// when displayed, on top of the built-in secrets. Entries are exact
+	// keys or glob patterns (e.g. "post_review_hook", "sync.*").
+	SensitiveKeys []string `toml:"sensitive_keys"`
+
 	// Hooks configuration
 	Hooks []HookConfig `toml:"hooks"`
 
+	// PostReviewHook is an executable run after each completed review with a
+	// JSON payload (job, verdict, summary, findings) on stdin
+	PostReviewHook string `toml:"post_review_hook"`
+
+	// Notify configures notifications sent when a review fails
+	Notify NotifyConfig `toml:"notify"`
+
+	// GitHub configures posting reviews as GitHub pull request comments
+	GitHub GitHubConfig `toml:"github"`
+
+	// Review holds review settings (overridable per repo)
+	Review ReviewConfig `toml:"review"`
+
 	// Sync configuration for PostgreSQL
 	Sync SyncConfig `toml:"sync"`
 
 	// CI poller configuration
 	CI CIConfig `toml:"ci"`
 
+	// Daemon HTTP API settings
+	Daemon DaemonConfig `toml:"daemon"`
+
 	// Analysis settings
 	DefaultMaxPromptSize int `toml:"default_max_prompt_size"` // Max prompt size in bytes before falling back to paths (default: 200KB)
+	PromptTokenBudget    int `toml:"prompt_token_budget"`     // Estimated-token budget for review prompts; context is trimmed to fit (0 = unlimited)
+
+	// Verdict gating (overridable per repo)
+	FailThreshold    string       `toml:"fail_threshold"`    // Minimum finding severity that fails a review: critical, high, medium, low (default: low)
+	BlockingKeywords []string     `toml:"blocking_keywords"` // Output containing any of these (case-insensitive) always fails
+	FindingIgnore    []string     `toml:"finding_ignore"`    // Findings containing any of these (case-insensitive) don't affect the verdict
+	IgnoreRules      []IgnoreRule `toml:"ignore_rules"`      // Findings matching a rule by text and/or path are suppressed
+
+	LowConfidencePolicy string `toml:"low_confidence_policy"` // Passing reviews the agent marks "confidence: low": ignore (default), warn, fail, or needs_human
 
 	// UI preferences
 	HideAddressedByDefault bool `toml:"hide_addressed_by_default"`
 	AutoFilterRepo         bool `toml:"auto_filter_repo"`
 	TabWidth               int  `toml:"tab_width"` // Tab expansion width for TUI rendering (default: 2)
+
+	// RepoNames maps a repo path or directory basename to a TUI display name.
+	// Example: {"/home/me/src/api" = "backend", "web" = "frontend"}
+	RepoNames map[string]string `toml:"repo_names"`
 }
 
 // GitHubAppConfig holds GitHub App authentication settings.
@@ -208,6 +290,37 @@ func (c *GitHubAppConfig) GitHubAppPrivateKeyResolved() (string, error) {
 	return string(data), nil
 }
 
+// NotifyConfig holds settings for notifications about failing reviews
+type NotifyConfig struct {
+	// SlackWebhookURL is a Slack incoming webhook that receives a message
+	// for each review with verdict FAIL (empty = disabled)
+	SlackWebhookURL string `toml:"slack_webhook_url" sensitive:"true"`
+}
+
+// GitHubConfig holds settings for posting completed reviews as comments
+// on the GitHub pull request whose head is the reviewed commit
+type GitHubConfig struct {
+	// Token is a GitHub token allowed to comment on pull requests
+	// (empty = disabled). Each repo posts to its github_repo, or its
+	// GitHub remote.
+	Token string `toml:"token" sensitive:"true"`
+}
+
+// ReviewConfig holds the [review] table in config.toml
+type ReviewConfig struct {
+	// Dedup reuses a finished review of an identical diff (e.g. of a
+	// commit amended only to change its message) instead of running the
+	// agent again. nil = not set, defaults to true.
+	Dedup *bool `toml:"dedup"`
+}
+
+// DaemonConfig holds settings for the daemon's HTTP API
+type DaemonConfig struct {
+	// RateLimit caps enqueue and cancel requests per second, across all
+	// clients, so a runaway script can't flood the queue (0 = unlimited)
+	RateLimit float64 `toml:"rate_limit"`
+}
+
 // CIConfig holds configuration for the CI poller that watches GitHub PRs
 type CIConfig struct {
 	// Enabled enables the CI poller
@@ -376,11 +489,14 @@ type RepoConfig struct {
 	Agent              string   `toml:"agent"`
 	Model              string   `toml:"model"` // Model for agents (format varies by agent)
 	BackupAgent        string   `toml:"backup_agent"`
+	FallbackAgents     []string `toml:"fallback_agents"` // Overrides the global fallback_agents when set
 	ReviewContextCount int      `toml:"review_context_count"`
 	ReviewGuidelines   string   `toml:"review_guidelines"`
 	JobTimeoutMinutes  int      `toml:"job_timeout_minutes"`
 	ExcludedBranches   []string `toml:"excluded_branches"`
+	SkipDuringRebase   *bool    `toml:"skip_during_rebase"` // nil = use global setting
 	DisplayName        string   `toml:"display_name"`
+	GitHubRepo         string   `toml:"github_repo"`      // "owner/name" to post reviews to; empty uses the GitHub remote
 	ReviewReasoning    string   `toml:"review_reasoning"` // Reasoning level for reviews: thorough, standard, fast
 	RefineReasoning    string   `toml:"refine_reasoning"` // Reasoning level for refine: thorough, standard, fast
 	FixReasoning       string   `toml:"fix_reasoning"`    // Reasoning level for fix: thorough, standard, fast
@@ -441,7 +557,97 @@ type RepoConfig struct {
 	Hooks []HookConfig `toml:"hooks"`
 
 	// Analysis settings
-	MaxPromptSize int `toml:"max_prompt_size"` // Max prompt size in bytes before falling back to paths (overrides global default)
+	MaxPromptSize     int `toml:"max_prompt_size"`     // Max prompt size in bytes before falling back to paths (overrides global default)
+	PromptTokenBudget int `toml:"prompt_token_budget"` // Estimated-token budget for review prompts (overrides global)
+
+	// Verdict gating (overrides global settings)
+	FailThreshold    string   `toml:"fail_threshold"`
+	BlockingKeywords []string `toml:"blocking_keywords"`
+	FindingIgnore    []string `toml:"finding_ignore"`
+
+	LowConfidencePolicy string `toml:"low_confidence_policy"`
+
+	// Ignore rules for known-acceptable findings (added to global rules)
+	IgnoreRules []IgnoreRule `toml:"ignore_rules"`
+
+	// File filters for `roborev watch-files`
+	Watch WatchConfig `toml:"watch"`
+
+	// Named agent/model/reasoning sets selected with
+	// `roborev review --review-profile <name>`
+	Profiles map[string]ReviewProfile `toml:"profiles"`
+
+	// Review prompt settings
+	Review RepoReviewConfig `toml:"review"`
+	Prompt RepoPromptConfig `toml:"prompt"`
+}
+
+// RepoReviewConfig holds the [review] table in .roborev.toml.
+type RepoReviewConfig struct {
+	// PromptTemplate is a file, relative to the repo root, whose contents
+	// replace the built-in system prompt for default reviews. It is a
+	// text/template; see prompt.ReviewTemplateData for its fields.
+	PromptTemplate string `toml:"prompt_template"`
+
+	// Dedup overrides the global review.dedup setting (nil = use global)
+	Dedup *bool `toml:"dedup"`
+}
+
+// RepoPromptConfig holds the [prompt] table in .roborev.toml.
+type RepoPromptConfig struct {
+	// Template is used like review.prompt_template when that is unset. A
+	// value containing "{{" or a newline is the template itself; anything
+	// else is a file relative to the repo root.
+	Template string `toml:"template"`
+}
+
+// ReviewProfile is a named set of review settings from a [profiles.<name>]
+// table in .roborev.toml. Empty fields leave the usual resolution in place.
+type ReviewProfile struct {
+	Agent     string `toml:"agent"`
+	Model     string `toml:"model"`
+	Reasoning string `toml:"reasoning"`
+}
+
+// ReviewProfile returns the named profile. It is an error for the profile
+// not to exist or to name an invalid reasoning level.
+func (c *RepoConfig) ReviewProfile(name string) (ReviewProfile, error) {
+	var p ReviewProfile
+	var ok bool
+	if c != nil {
+		p, ok = c.Profiles[name]
+	}
+	if !ok {
+		return ReviewProfile{}, fmt.Errorf("no profile %q in .roborev.toml", name)
+	}
+	if strings.TrimSpace(p.Reasoning) != "" {
+		reasoning, err := NormalizeReasoning(p.Reasoning)
+		if err != nil {
+			return ReviewProfile{}, fmt.Errorf("profile %q: %w", name, err)
+		}
+		p.Reasoning = reasoning
+	}
+	return p, nil
+}
+
+// WatchConfig selects which tracked files trigger reviews in
+// `roborev watch-files`. Patterns are globs matched against the
+// repo-relative path or the basename; a pattern ending in "/" matches
+// everything under that directory. An empty Include matches all files;
+// Exclude wins over Include.
+type WatchConfig struct {
+	Include []string `toml:"include"`
+	Exclude []string `toml:"exclude"`
+}
+
+// IgnoreRule suppresses findings that match it: they don't count toward the
+// verdict and are marked suppressed in the stored review. Pattern is a
+// case-insensitive substring of the finding; Path is a glob (or directory
+// prefix) matched against file paths the finding mentions. When both are
+// set, both must match.
+type IgnoreRule struct {
+	Pattern string `toml:"pattern"`
+	Path    string `toml:"path"`
 }
 
 // DefaultConfig returns the default configuration
@@ -452,6 +658,7 @@ func DefaultConfig() *Config {
 		ReviewContextCount: 3,
 		DefaultAgent:       "codex",
 		JobTimeoutMinutes:  30,
+		MaxRetries:         3,
 		CodexCmd:           "codex",
 		ClaudeCodeCmd:      "claude",
 		CursorCmd:          "agent",
@@ -459,13 +666,56 @@ func DefaultConfig() *Config {
 }
 
 // DataDir returns the roborev data directory.
-// Uses ROBOREV_DATA_DIR env var if set, otherwise ~/.roborev
+// Uses ROBOREV_DATA_DIR env var if set, otherwise ~/.roborev. When a
+// profile is active, its config, database, and daemon runtime files live
+// in profiles/<name> under that directory instead.
 func DataDir() string {
-	if dir := os.Getenv("ROBOREV_DATA_DIR"); dir != "" {
+	return ProfileDataDir(ActiveProfile())
+}
+
+// ProfileEnvVar names the environment variable that selects the active
+// profile. The --profile flag sets it so spawned daemons inherit it.
+const ProfileEnvVar = "ROBOREV_PROFILE"
+
+// DefaultProfile is the profile whose data lives directly in the base data
+// directory. Selecting it is the same as selecting no profile.
+const DefaultProfile = "default"
+
+// ActiveProfile returns the profile named by ROBOREV_PROFILE, or "" when
+// none is set.
+func ActiveProfile() string {
+	return strings.TrimSpace(os.Getenv(ProfileEnvVar))
+}
+
+// ProfileDataDir returns the data directory for the named profile. An
+// empty name or DefaultProfile returns the base data directory.
+func ProfileDataDir(profile string) string {
+	dir := os.Getenv("ROBOREV_DATA_DIR")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".roborev")
+	}
+	if profile == "" || profile == DefaultProfile {
 		return dir
 	}
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".roborev")
+	return filepath.Join(dir, "profiles", profile)
+}
+
+// ValidateProfileName reports an error unless name is usable as a profile
+// directory: letters, digits, '-', '_' and '.', not starting with '.'.
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if name[0] == '.' {
+		return fmt.Errorf("invalid profile name %q: must not start with '.'", name)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return fmt.Errorf("invalid profile name %q: use letters, digits, '-', '_' or '.'", name)
+		}
+	}
+	return nil
 }
 
 // GlobalConfigPath returns the path to the global config file
@@ -591,6 +841,105 @@ func ResolveJobTimeout(repoPath string, globalCfg *Config) int {
 	return resolve(30, repoVal, globalVal)
 }
 
+// ResolveSkipDuringRebase reports whether post-commit reviews should be
+// skipped while a rebase is replaying commits. Per-repo config overrides
+// global config; the default is true.
+func ResolveSkipDuringRebase(repoPath string, globalCfg *Config) bool {
+	if repoCfg, err := LoadRepoConfig(repoPath); err == nil && repoCfg != nil && repoCfg.SkipDuringRebase != nil {
+		return *repoCfg.SkipDuringRebase
+	}
+	if globalCfg != nil && globalCfg.SkipDuringRebase != nil {
+		return *globalCfg.SkipDuringRebase
+	}
+	return true
+}
+
+// ResolveReviewDedup reports whether a review of a diff identical to one
+// already reviewed reuses that review instead of running the agent.
+// Per-repo config overrides global config; the default is true.
+func ResolveReviewDedup(repoPath string, globalCfg *Config) bool {
+	if repoCfg, err := LoadRepoConfig(repoPath); err == nil && repoCfg != nil && repoCfg.Review.Dedup != nil {
+		return *repoCfg.Review.Dedup
+	}
+	if globalCfg != nil && globalCfg.Review.Dedup != nil {
+		return *globalCfg.Review.Dedup
+	}
+	return true
+}
+
+// ResolveAgentTimeout returns the timeout configured for an agent, in
+// order of preference: the [agent_timeouts] entry for "agent:reasoning",
+// then for the bare agent name, then [agents.<name>].timeout, then
+// agent_timeout unless the repo at repoPath sets job_timeout_minutes.
+// Returns 0 when none applies, in which case the caller should fall back
+// to ResolveJobTimeout. A matching entry that isn't a positive duration
+// returns an error.
+func ResolveAgentTimeout(repoPath string, globalCfg *Config, agentName, reasoning string) (time.Duration, error) {
+	if globalCfg == nil || agentName == "" {
+		return 0, nil
+	}
+	keys := []string{agentName}
+	if reasoning != "" {
+		keys = []string{agentName + ":" + reasoning, agentName}
+	}
+	for _, key := range keys {
+		val, ok := globalCfg.AgentTimeouts[key]
+		if !ok {
+			continue
+		}
+		d, err := ParseAgentTimeout(val)
+		if err != nil {
+			return 0, fmt.Errorf("agent_timeouts.%s: %w", key, err)
+		}
+		return d, nil
+	}
+	if val := globalCfg.Agents[agentName].Timeout; val != "" {
+		d, err := ParseAgentTimeout(val)
+		if err != nil {
+			return 0, fmt.Errorf("agents.%s.timeout: %w", agentName, err)
+		}
+		return d, nil
+	}
+	if globalCfg.AgentTimeout != "" {
+		// A per-repo job timeout is more specific than the global default
+		if repoCfg, err := LoadRepoConfig(repoPath); err == nil && repoCfg != nil && clampPositive(repoCfg.JobTimeoutMinutes) > 0 {
+			return 0, nil
+		}
+		d, err := ParseAgentTimeout(globalCfg.AgentTimeout)
+		if err != nil {
+			return 0, fmt.Errorf("agent_timeout: %w", err)
+		}
+		return d, nil
+	}
+	return 0, nil
+}
+
+// CustomAgentCommands returns the command template of each [agents.<name>]
+// section that defines a custom agent, keyed by agent name. Sections that
+// only set a timeout are skipped.
+func (c *Config) CustomAgentCommands() map[string]string {
+	commands := make(map[string]string, len(c.Agents))
+	for name, a := range c.Agents {
+		if a.Command != "" {
+			commands[name] = a.Command
+		}
+	}
+	return commands
+}
+
+// ParseAgentTimeout parses an [agent_timeouts] value, which must be a
+// positive Go duration such as "45m" or "1h30m".
+func ParseAgentTimeout(val string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(val))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 45m or 1h30m)", val)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %q must be positive", val)
+	}
+	return d, nil
+}
+
 // IsBranchExcluded checks if a branch should be excluded from reviews
 func IsBranchExcluded(repoPath, branch string) bool {
 	repoCfg, err := LoadRepoConfig(repoPath)
@@ -601,6 +950,22 @@ func IsBranchExcluded(repoPath, branch string) bool {
 	return slices.Contains(repoCfg.ExcludedBranches, branch)
 }
 
+// LookupRepoName returns the display name configured in a [repo_names]
+// table for repoPath, matching the full path first and then the basename.
+// Returns empty if there is no match.
+func LookupRepoName(names map[string]string, repoPath string) string {
+	if len(names) == 0 || repoPath == "" {
+		return ""
+	}
+	if name := names[repoPath]; name != "" {
+		return name
+	}
+	if name := names[filepath.Clean(repoPath)]; name != "" {
+		return name
+	}
+	return names[filepath.Base(repoPath)]
+}
+
 // GetDisplayName returns the display name for a repo, or empty if not set
 func GetDisplayName(repoPath string) string {
 	repoCfg, err := LoadRepoConfig(repoPath)
@@ -695,6 +1060,18 @@ func NormalizeMinSeverity(value string) (string, error) {
 	}
 }
 
+// NormalizeLowConfidencePolicy validates and normalizes a
+// low_confidence_policy value. Returns "" for empty input.
+func NormalizeLowConfidencePolicy(value string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	switch normalized {
+	case "", "ignore", "warn", "fail", "needs_human":
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("invalid low_confidence_policy: %q (valid: ignore, warn, fail, needs_human)", value)
+	}
+}
+
 // ResolveReviewReasoning determines reasoning level for reviews.
 // Priority: explicit > per-repo config > default (thorough)
 func ResolveReviewReasoning(explicit string, repoPath string) (string, error) {
@@ -773,6 +1150,64 @@ func ResolveMaxPromptSize(repoPath string, globalCfg *Config) int {
 	return resolve(DefaultMaxPromptSize, repoVal, globalVal)
 }
 
+// ResolvePromptTokenBudget determines the review prompt token budget:
+// per-repo prompt_token_budget, then global prompt_token_budget. Zero
+// means no budget.
+func ResolvePromptTokenBudget(repoPath string, globalCfg *Config) int {
+	var repoVal int
+	if repoCfg, err := LoadRepoConfig(repoPath); err == nil && repoCfg != nil {
+		repoVal = clampPositive(repoCfg.PromptTokenBudget)
+	}
+	var globalVal int
+	if globalCfg != nil {
+		globalVal = clampPositive(globalCfg.PromptTokenBudget)
+	}
+	return resolve(0, repoVal, globalVal)
+}
+
+// VerdictGating holds the effective settings that decide whether review
+// output passes or fails.
+type VerdictGating struct {
+	FailThreshold    string   // critical, high, medium, low ("" means low)
+	BlockingKeywords []string // Fail if output contains any of these
+	FindingIgnore    []string // Ignore findings containing any of these
+	IgnoreRules      []IgnoreRule
+	LowConfidence    string // ignore, warn, fail, or needs_human ("" means ignore)
+}
+
+// ResolveVerdictGating determines verdict gating settings for a repo.
+// Each setting resolves independently: per-repo config > global config > default.
+// A list set in .roborev.toml replaces the global list, so an explicit empty
+// list clears it; ignore rules are the exception and accumulate, since each
+// rule targets a specific finding. Invalid fail_threshold values are treated
+// as unset.
+func ResolveVerdictGating(repoPath string, globalCfg *Config) VerdictGating {
+	var repoThreshold, globalThreshold string
+	var repoLowConf, globalLowConf string
+	var gating VerdictGating
+	if globalCfg != nil {
+		globalThreshold, _ = NormalizeMinSeverity(globalCfg.FailThreshold)
+		globalLowConf, _ = NormalizeLowConfidencePolicy(globalCfg.LowConfidencePolicy)
+		gating.BlockingKeywords = globalCfg.BlockingKeywords
+		gating.FindingIgnore = globalCfg.FindingIgnore
+		gating.IgnoreRules = append(gating.IgnoreRules, globalCfg.IgnoreRules...)
+	}
+	if repoCfg, err := LoadRepoConfig(repoPath); err == nil && repoCfg != nil {
+		repoThreshold, _ = NormalizeMinSeverity(repoCfg.FailThreshold)
+		repoLowConf, _ = NormalizeLowConfidencePolicy(repoCfg.LowConfidencePolicy)
+		if repoCfg.BlockingKeywords != nil {
+			gating.BlockingKeywords = repoCfg.BlockingKeywords
+		}
+		if repoCfg.FindingIgnore != nil {
+			gating.FindingIgnore = repoCfg.FindingIgnore
+		}
+		gating.IgnoreRules = append(gating.IgnoreRules, repoCfg.IgnoreRules...)
+	}
+	gating.FailThreshold = resolve("", repoThreshold, globalThreshold)
+	gating.LowConfidence = resolve("", repoLowConf, globalLowConf)
+	return gating
+}
+
 // ResolveAgentForWorkflow determines which agent to use based on workflow and level.
 // Priority (Option A - layer wins first, then specificity):
 // 1. CLI explicit
@@ -787,13 +1222,20 @@ func ResolveAgentForWorkflow(cli, repoPath string, globalCfg *Config, workflow,
 	if s := strings.TrimSpace(cli); s != "" {
 		return s
 	}
-	repoCfg, _ := LoadRepoConfig(repoPath)
-	if s := getWorkflowValue(repoCfg, globalCfg, workflow, level, true); s != "" {
+	if s := ConfiguredAgentForWorkflow(repoPath, globalCfg, workflow, level); s != "" {
 		return s
 	}
 	return "codex"
 }
 
+// ConfiguredAgentForWorkflow returns the agent the repo or global config
+// sets for a workflow and level (steps 2-7 of ResolveAgentForWorkflow), or
+// "" when neither config names one.
+func ConfiguredAgentForWorkflow(repoPath string, globalCfg *Config, workflow, level string) string {
+	repoCfg, _ := LoadRepoConfig(repoPath)
+	return getWorkflowValue(repoCfg, globalCfg, workflow, level, true)
+}
+
 // ResolveModelForWorkflow determines which model to use based on workflow and level.
 // Same priority as ResolveAgentForWorkflow, but returns empty string as default.
 func ResolveModelForWorkflow(cli, repoPath string, globalCfg *Config, workflow, level string) string {
@@ -804,6 +1246,68 @@ func ResolveModelForWorkflow(cli, repoPath string, globalCfg *Config, workflow,
 	return getWorkflowValue(repoCfg, globalCfg, workflow, level, false)
 }
 
+// ReviewParamsOptions holds the agent, model, and reasoning explicitly
+// requested for a review job. Empty fields fall back to configuration.
+type ReviewParamsOptions struct {
+	Agent     string
+	Model     string
+	Reasoning string
+	Workflow  string // Config workflow: "review" (default), "security", or "design"
+}
+
+// ResolveReviewParams determines the agent, model, and reasoning level for a
+// review job. Each resolves independently with priority:
+// explicit opts > repo config > global config > default.
+// Reasoning is resolved first since agent and model lookups can be
+// level-specific (e.g. review_agent_fast); it has no global setting and
+// defaults to thorough. The agent defaults to codex and the model to "".
+// An invalid reasoning level returns an error.
+func ResolveReviewParams(globalCfg *Config, repoCfg *RepoConfig, opts ReviewParamsOptions) (agent, model, reasoning string, err error) {
+	switch {
+	case strings.TrimSpace(opts.Reasoning) != "":
+		reasoning, err = NormalizeReasoning(opts.Reasoning)
+	case repoCfg != nil && strings.TrimSpace(repoCfg.ReviewReasoning) != "":
+		reasoning, err = NormalizeReasoning(repoCfg.ReviewReasoning)
+	default:
+		reasoning = "thorough"
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+
+	workflow := opts.Workflow
+	if workflow == "" {
+		workflow = "review"
+	}
+	agent = strings.TrimSpace(opts.Agent)
+	if agent == "" {
+		agent = resolve("codex", getWorkflowValue(repoCfg, globalCfg, workflow, reasoning, true))
+	}
+	model = strings.TrimSpace(opts.Model)
+	if model == "" {
+		model = getWorkflowValue(repoCfg, globalCfg, workflow, reasoning, false)
+	}
+	return agent, model, reasoning, nil
+}
+
+// ResolveFallbackAgents returns the ordered fallback agents for a repo:
+// the repo's fallback_agents when set, otherwise the global list.
+func ResolveFallbackAgents(repoPath string, globalCfg *Config) []string {
+	var agents []string
+	if repoCfg, _ := LoadRepoConfig(repoPath); repoCfg != nil && len(repoCfg.FallbackAgents) > 0 {
+		agents = repoCfg.FallbackAgents
+	} else if globalCfg != nil {
+		agents = globalCfg.FallbackAgents
+	}
+	var resolved []string
+	for _, a := range agents {
+		if a = strings.TrimSpace(a); a != "" {
+			resolved = append(resolved, a)
+		}
+	}
+	return resolved
+}
+
 // ResolveBackupAgentForWorkflow returns the backup agent for a workflow,
 // or empty string if none is configured.
 // Priority:
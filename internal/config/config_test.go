@@ -5,8 +5,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/roborev-dev/roborev/internal/testenv"
@@ -47,6 +49,21 @@ func TestDataDir(t *testing.T) {
 		}
 	})
 
+	t.Run("profile uses its own subdirectory", func(t *testing.T) {
+		t.Setenv("ROBOREV_DATA_DIR", "/custom/data/dir")
+
+		for profile, want := range map[string]string{
+			"":        "/custom/data/dir",
+			"default": "/custom/data/dir",
+			"work":    filepath.Join("/custom/data/dir", "profiles", "work"),
+		} {
+			t.Setenv(ProfileEnvVar, profile)
+			if dir := DataDir(); dir != want {
+				t.Errorf("profile %q: expected %s, got %s", profile, want, dir)
+			}
+		}
+	})
+
 	t.Run("GlobalConfigPath uses DataDir", func(t *testing.T) {
 		testDir := filepath.Join(os.TempDir(), "roborev-test")
 		t.Setenv("ROBOREV_DATA_DIR", testDir)
@@ -59,6 +76,19 @@ func TestDataDir(t *testing.T) {
 	})
 }
 
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"work", "personal-2", "team_a", "v1.2"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", ".hidden", "..", "a/b", `a\b`, "has space"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) = nil, want error", name)
+		}
+	}
+}
+
 func TestResolveAgent(t *testing.T) {
 	cfg := DefaultConfig()
 	tmpDir := t.TempDir()
@@ -164,6 +194,49 @@ func TestLoadRepoConfigNoGuidelines(t *testing.T) {
 	}
 }
 
+func TestLoadRepoConfigProfiles(t *testing.T) {
+	tmpDir := newTempRepo(t, `
+agent = "codex"
+
+[profiles.security]
+agent = "claude-code"
+model = "opus"
+reasoning = "Fast"
+
+[profiles.docs]
+agent = "gemini"
+
+[profiles.bad]
+reasoning = "extreme"
+`)
+
+	cfg, err := LoadRepoConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadRepoConfig failed: %v", err)
+	}
+
+	p, err := cfg.ReviewProfile("security")
+	if err != nil {
+		t.Fatalf("ReviewProfile(security): %v", err)
+	}
+	if p != (ReviewProfile{Agent: "claude-code", Model: "opus", Reasoning: "fast"}) {
+		t.Errorf("security profile = %+v", p)
+	}
+	if p, err := cfg.ReviewProfile("docs"); err != nil || p != (ReviewProfile{Agent: "gemini"}) {
+		t.Errorf("docs profile = %+v, %v", p, err)
+	}
+	if _, err := cfg.ReviewProfile("missing"); err == nil {
+		t.Error("expected an error for an undefined profile")
+	}
+	if _, err := cfg.ReviewProfile("bad"); err == nil {
+		t.Error("expected an error for an invalid reasoning level")
+	}
+	var noConfig *RepoConfig
+	if _, err := noConfig.ReviewProfile("security"); err == nil {
+		t.Error("expected an error without a repo config")
+	}
+}
+
 func TestLoadRepoConfigMissing(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -178,6 +251,42 @@ func TestLoadRepoConfigMissing(t *testing.T) {
 	}
 }
 
+func TestResolveAgentTimeout(t *testing.T) {
+	cfg := &Config{AgentTimeouts: map[string]string{
+		"codex":          "45m",
+		"codex:thorough": "90m",
+		"gemini":         "bogus",
+	}}
+
+	tests := []struct {
+		name      string
+		agent     string
+		reasoning string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{name: "agent and reasoning key", agent: "codex", reasoning: "thorough", want: 90 * time.Minute},
+		{name: "falls back to agent key", agent: "codex", reasoning: "fast", want: 45 * time.Minute},
+		{name: "no matching key", agent: "claude-code", reasoning: "thorough", want: 0},
+		{name: "invalid duration", agent: "gemini", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveAgentTimeout("", cfg, tt.agent, tt.reasoning)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveAgentTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveAgentTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := ResolveAgentTimeout("", nil, "codex", ""); got != 0 || err != nil {
+		t.Errorf("nil config: got %v, %v", got, err)
+	}
+}
+
 func TestResolveJobTimeout(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -250,6 +359,189 @@ func TestResolveJobTimeout(t *testing.T) {
 	}
 }
 
+func TestResolveReviewDedup(t *testing.T) {
+	off, on := false, true
+	tests := []struct {
+		name         string
+		repoConfig   string
+		globalConfig *Config
+		want         bool
+	}{
+		{name: "default when no config", want: true},
+		{name: "global disables", globalConfig: &Config{Review: ReviewConfig{Dedup: &off}}, want: false},
+		{
+			name:         "repo overrides global",
+			repoConfig:   "[review]\ndedup = true",
+			globalConfig: &Config{Review: ReviewConfig{Dedup: &off}},
+			want:         true,
+		},
+		{
+			name:         "repo disables",
+			repoConfig:   "[review]\ndedup = false",
+			globalConfig: &Config{Review: ReviewConfig{Dedup: &on}},
+			want:         false,
+		},
+		{
+			name:         "repo config without setting falls through to global",
+			repoConfig:   `agent = "codex"`,
+			globalConfig: &Config{Review: ReviewConfig{Dedup: &off}},
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if tt.repoConfig != "" {
+				writeRepoConfigStr(t, tmpDir, tt.repoConfig)
+			}
+			if got := ResolveReviewDedup(tmpDir, tt.globalConfig); got != tt.want {
+				t.Errorf("ResolveReviewDedup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSkipDuringRebase(t *testing.T) {
+	off, on := false, true
+	tests := []struct {
+		name         string
+		repoConfig   string
+		globalConfig *Config
+		want         bool
+	}{
+		{name: "default when no config", want: true},
+		{name: "global disables", globalConfig: &Config{SkipDuringRebase: &off}, want: false},
+		{
+			name:         "repo overrides global",
+			repoConfig:   `skip_during_rebase = true`,
+			globalConfig: &Config{SkipDuringRebase: &off},
+			want:         true,
+		},
+		{
+			name:         "repo disables",
+			repoConfig:   `skip_during_rebase = false`,
+			globalConfig: &Config{SkipDuringRebase: &on},
+			want:         false,
+		},
+		{
+			name:         "repo config without setting falls through to global",
+			repoConfig:   `agent = "codex"`,
+			globalConfig: &Config{SkipDuringRebase: &off},
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if tt.repoConfig != "" {
+				writeRepoConfigStr(t, tmpDir, tt.repoConfig)
+			}
+			if got := ResolveSkipDuringRebase(tmpDir, tt.globalConfig); got != tt.want {
+				t.Errorf("ResolveSkipDuringRebase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveVerdictGating(t *testing.T) {
+	global := &Config{
+		FailThreshold:    "medium",
+		BlockingKeywords: []string{"SECURITY"},
+		FindingIgnore:    []string{"vendor/"},
+	}
+
+	t.Run("global applies without repo config", func(t *testing.T) {
+		got := ResolveVerdictGating(t.TempDir(), global)
+		if got.FailThreshold != "medium" {
+			t.Errorf("FailThreshold = %q, want medium", got.FailThreshold)
+		}
+		if !slices.Equal(got.BlockingKeywords, []string{"SECURITY"}) {
+			t.Errorf("BlockingKeywords = %v", got.BlockingKeywords)
+		}
+		if !slices.Equal(got.FindingIgnore, []string{"vendor/"}) {
+			t.Errorf("FindingIgnore = %v", got.FindingIgnore)
+		}
+	})
+
+	t.Run("repo overrides each setting", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRepoConfigStr(t, dir, `fail_threshold = "High"
+blocking_keywords = ["DO NOT MERGE"]
+finding_ignore = ["generated"]`)
+		got := ResolveVerdictGating(dir, global)
+		if got.FailThreshold != "high" {
+			t.Errorf("FailThreshold = %q, want high", got.FailThreshold)
+		}
+		if !slices.Equal(got.BlockingKeywords, []string{"DO NOT MERGE"}) {
+			t.Errorf("BlockingKeywords = %v", got.BlockingKeywords)
+		}
+		if !slices.Equal(got.FindingIgnore, []string{"generated"}) {
+			t.Errorf("FindingIgnore = %v", got.FindingIgnore)
+		}
+	})
+
+	t.Run("repo empty list clears global list", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRepoConfigStr(t, dir, `blocking_keywords = []`)
+		got := ResolveVerdictGating(dir, global)
+		if len(got.BlockingKeywords) != 0 {
+			t.Errorf("BlockingKeywords = %v, want empty", got.BlockingKeywords)
+		}
+		if !slices.Equal(got.FindingIgnore, []string{"vendor/"}) {
+			t.Errorf("FindingIgnore = %v, want global", got.FindingIgnore)
+		}
+	})
+
+	t.Run("low confidence policy resolves repo over global", func(t *testing.T) {
+		if got := ResolveVerdictGating(t.TempDir(), &Config{LowConfidencePolicy: "Warn"}); got.LowConfidence != "warn" {
+			t.Errorf("LowConfidence = %q, want warn from global", got.LowConfidence)
+		}
+		dir := t.TempDir()
+		writeRepoConfigStr(t, dir, `low_confidence_policy = "fail"`)
+		if got := ResolveVerdictGating(dir, &Config{LowConfidencePolicy: "warn"}); got.LowConfidence != "fail" {
+			t.Errorf("LowConfidence = %q, want fail from repo", got.LowConfidence)
+		}
+		writeRepoConfigStr(t, dir, `low_confidence_policy = "needs_human"`)
+		if got := ResolveVerdictGating(dir, &Config{LowConfidencePolicy: "warn"}); got.LowConfidence != "needs_human" {
+			t.Errorf("LowConfidence = %q, want needs_human from repo", got.LowConfidence)
+		}
+		writeRepoConfigStr(t, dir, `low_confidence_policy = "downgrade"`)
+		if got := ResolveVerdictGating(dir, &Config{LowConfidencePolicy: "warn"}); got.LowConfidence != "warn" {
+			t.Errorf("LowConfidence = %q, want invalid repo value to fall through to warn", got.LowConfidence)
+		}
+	})
+
+	t.Run("invalid repo threshold falls through to global", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRepoConfigStr(t, dir, `fail_threshold = "severe"`)
+		if got := ResolveVerdictGating(dir, global); got.FailThreshold != "medium" {
+			t.Errorf("FailThreshold = %q, want medium", got.FailThreshold)
+		}
+	})
+
+	t.Run("ignore rules accumulate", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRepoConfigStr(t, dir, `[[ignore_rules]]
+pattern = "false positive"
+path = "testdata/"`)
+		g := &Config{IgnoreRules: []IgnoreRule{{Pattern: "generated code"}}}
+		got := ResolveVerdictGating(dir, g)
+		want := []IgnoreRule{{Pattern: "generated code"}, {Pattern: "false positive", Path: "testdata/"}}
+		if !slices.Equal(got.IgnoreRules, want) {
+			t.Errorf("IgnoreRules = %+v, want %+v", got.IgnoreRules, want)
+		}
+	})
+
+	t.Run("default when nothing set", func(t *testing.T) {
+		got := ResolveVerdictGating(t.TempDir(), nil)
+		if got.FailThreshold != "" || got.BlockingKeywords != nil || got.FindingIgnore != nil {
+			t.Errorf("expected zero gating, got %+v", got)
+		}
+	})
+}
+
 func TestResolveReasoning(t *testing.T) {
 	type resolverFunc func(explicit string, dir string) (string, error)
 
@@ -610,6 +902,48 @@ excluded_branches = ["wip"]
 	})
 }
 
+func TestLookupRepoName(t *testing.T) {
+	names := map[string]string{
+		"/src/api": "backend",
+		"web":      "frontend",
+	}
+	tests := []struct {
+		name     string
+		names    map[string]string
+		repoPath string
+		want     string
+	}{
+		{"full path", names, "/src/api", "backend"},
+		{"unclean path", names, "/src/api/", "backend"},
+		{"basename", names, "/home/me/web", "frontend"},
+		{"no match", names, "/src/other", ""},
+		{"nil table", nil, "/src/api", ""},
+		{"empty path", names, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LookupRepoName(tt.names, tt.repoPath); got != tt.want {
+				t.Errorf("LookupRepoName(%q) = %q, want %q", tt.repoPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadGlobalRepoNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[repo_names]\n\"/src/api\" = \"backend\"\nweb = \"frontend\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadGlobalFrom(path)
+	if err != nil {
+		t.Fatalf("LoadGlobalFrom: %v", err)
+	}
+	if cfg.RepoNames["/src/api"] != "backend" || cfg.RepoNames["web"] != "frontend" {
+		t.Errorf("unexpected repo_names: %v", cfg.RepoNames)
+	}
+}
+
 func TestValidateRoborevID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1186,6 +1520,21 @@ func TestResolveBackupAgentForWorkflow(t *testing.T) {
 	}
 }
 
+func TestResolveFallbackAgents(t *testing.T) {
+	global := &Config{FallbackAgents: []string{"claude-code", " ", "gemini"}}
+
+	if got := ResolveFallbackAgents(t.TempDir(), global); !slices.Equal(got, []string{"claude-code", "gemini"}) {
+		t.Errorf("global fallback_agents = %v, want [claude-code gemini]", got)
+	}
+	if got := ResolveFallbackAgents(t.TempDir(), nil); got != nil {
+		t.Errorf("no config = %v, want nil", got)
+	}
+	repoDir := newTempRepo(t, `fallback_agents = ["codex"]`)
+	if got := ResolveFallbackAgents(repoDir, global); !slices.Equal(got, []string{"codex"}) {
+		t.Errorf("repo fallback_agents = %v, want [codex]", got)
+	}
+}
+
 // M is a shorthand type for map[string]string to keep test tables compact
 type M = map[string]string
 
@@ -1902,3 +2251,186 @@ func TestValidateReviewTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveReviewParams(t *testing.T) {
+	global := &Config{
+		DefaultAgent:    "claude-code",
+		DefaultModel:    "global-model",
+		ReviewAgentFast: "gemini",
+		SecurityModel:   "global-security-model",
+	}
+	repo := &RepoConfig{
+		Agent:           "codex",
+		Model:           "repo-model",
+		ReviewReasoning: "standard",
+	}
+
+	tests := []struct {
+		name          string
+		global        *Config
+		repo          *RepoConfig
+		opts          ReviewParamsOptions
+		wantAgent     string
+		wantModel     string
+		wantReasoning string
+	}{
+		{
+			name:          "defaults with no config",
+			wantAgent:     "codex",
+			wantModel:     "",
+			wantReasoning: "thorough",
+		},
+		{
+			name:          "global config used when repo config is absent",
+			global:        global,
+			wantAgent:     "claude-code",
+			wantModel:     "global-model",
+			wantReasoning: "thorough",
+		},
+		{
+			name:          "repo config beats global config",
+			global:        global,
+			repo:          repo,
+			wantAgent:     "codex",
+			wantModel:     "repo-model",
+			wantReasoning: "standard",
+		},
+		{
+			name:          "explicit options beat repo and global config",
+			global:        global,
+			repo:          repo,
+			opts:          ReviewParamsOptions{Agent: "opencode", Model: "explicit-model", Reasoning: "thorough"},
+			wantAgent:     "opencode",
+			wantModel:     "explicit-model",
+			wantReasoning: "thorough",
+		},
+		{
+			name:          "empty repo fields fall through to global",
+			global:        global,
+			repo:          &RepoConfig{},
+			wantAgent:     "claude-code",
+			wantModel:     "global-model",
+			wantReasoning: "thorough",
+		},
+		{
+			name:          "whitespace options fall through to config",
+			global:        global,
+			repo:          repo,
+			opts:          ReviewParamsOptions{Agent: "  ", Model: " ", Reasoning: "\t"},
+			wantAgent:     "codex",
+			wantModel:     "repo-model",
+			wantReasoning: "standard",
+		},
+		{
+			name:          "explicit options are trimmed",
+			opts:          ReviewParamsOptions{Agent: " codex ", Model: " o3 ", Reasoning: " FAST "},
+			wantAgent:     "codex",
+			wantModel:     "o3",
+			wantReasoning: "fast",
+		},
+		{
+			name:          "each field resolves independently",
+			global:        global,
+			repo:          &RepoConfig{Model: "repo-model"},
+			opts:          ReviewParamsOptions{Reasoning: "standard"},
+			wantAgent:     "claude-code",
+			wantModel:     "repo-model",
+			wantReasoning: "standard",
+		},
+		{
+			name:          "reasoning selects level-specific global agent",
+			global:        global,
+			opts:          ReviewParamsOptions{Reasoning: "fast"},
+			wantAgent:     "gemini",
+			wantModel:     "global-model",
+			wantReasoning: "fast",
+		},
+		{
+			name:          "repo generic agent beats global level-specific agent",
+			global:        global,
+			repo:          &RepoConfig{Agent: "codex"},
+			opts:          ReviewParamsOptions{Reasoning: "fast"},
+			wantAgent:     "codex",
+			wantModel:     "global-model",
+			wantReasoning: "fast",
+		},
+		{
+			name:          "workflow selects workflow-specific settings",
+			global:        global,
+			opts:          ReviewParamsOptions{Workflow: "security"},
+			wantAgent:     "claude-code",
+			wantModel:     "global-security-model",
+			wantReasoning: "thorough",
+		},
+		{
+			name:          "review settings don't apply to other workflows",
+			global:        &Config{ReviewModel: "review-only-model"},
+			opts:          ReviewParamsOptions{Workflow: "design"},
+			wantAgent:     "codex",
+			wantModel:     "",
+			wantReasoning: "thorough",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, model, reasoning, err := ResolveReviewParams(tt.global, tt.repo, tt.opts)
+			if err != nil {
+				t.Fatalf("ResolveReviewParams failed: %v", err)
+			}
+			if agent != tt.wantAgent {
+				t.Errorf("agent = %q, want %q", agent, tt.wantAgent)
+			}
+			if model != tt.wantModel {
+				t.Errorf("model = %q, want %q", model, tt.wantModel)
+			}
+			if reasoning != tt.wantReasoning {
+				t.Errorf("reasoning = %q, want %q", reasoning, tt.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestResolveReviewParamsInvalidReasoning(t *testing.T) {
+	tests := []struct {
+		name string
+		repo *RepoConfig
+		opts ReviewParamsOptions
+	}{
+		{"explicit", nil, ReviewParamsOptions{Reasoning: "extreme"}},
+		{"repo config", &RepoConfig{ReviewReasoning: "extreme"}, ReviewParamsOptions{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := ResolveReviewParams(nil, tt.repo, tt.opts); err == nil {
+				t.Error("expected error for invalid reasoning")
+			}
+		})
+	}
+}
+
+func TestResolvePromptTokenBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoCfg string
+		global  *Config
+		want    int
+	}{
+		{"default unlimited", "", nil, 0},
+		{"global", "", &Config{PromptTokenBudget: 50000}, 50000},
+		{"repo overrides global", `prompt_token_budget = 20000`, &Config{PromptTokenBudget: 50000}, 20000},
+		{"repo zero falls through", `prompt_token_budget = 0`, &Config{PromptTokenBudget: 50000}, 50000},
+		{"negative ignored", `prompt_token_budget = -5`, &Config{PromptTokenBudget: -1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.repoCfg != "" {
+				dir = newTempRepo(t, tt.repoCfg)
+			}
+			if got := ResolvePromptTokenBudget(dir, tt.global); got != tt.want {
+				t.Errorf("ResolvePromptTokenBudget() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
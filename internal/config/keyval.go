@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -97,9 +98,22 @@ func IsGlobalKey(key string) bool {
 	return err == nil
 }
 
-// IsSensitiveKey returns true if the key holds a secret that should be masked.
-func IsSensitiveKey(key string) bool {
-	return sensitiveKeys[key]
+// IsSensitiveKey returns true if the key holds a secret that should be masked:
+// either a built-in sensitive field or a key matching one of extra, which are
+// exact keys or path.Match glob patterns (typically Config.SensitiveKeys).
+func IsSensitiveKey(key string, extra ...string) bool {
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, pattern := range extra {
+		if pattern == key {
+			return true
+		}
+		if ok, err := path.Match(pattern, key); err == nil && ok {
+			return true
+		}
+	}
+	return false
 }
 
 // MaskValue returns a masked version of a sensitive value, showing only the last 4 chars.
@@ -110,6 +124,46 @@ func MaskValue(val string) string {
 	return "****" + val[len(val)-4:]
 }
 
+// NativeValue converts a value as formatted by the listing functions back to
+// the type of key's field, so booleans and numbers can be serialized as
+// native JSON types. An unset pointer field yields nil; other values, and
+// values that fail to parse, are returned as strings.
+func NativeValue(key, value string) any {
+	field, err := FindFieldByTOMLKey(reflect.ValueOf(Config{}), key)
+	if err != nil {
+		field, err = FindFieldByTOMLKey(reflect.ValueOf(RepoConfig{}), key)
+		if err != nil {
+			return value
+		}
+	}
+	t := field.Type()
+	if t.Kind() == reflect.Ptr {
+		if value == "" {
+			return nil
+		}
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
 // KeyValueOrigin represents a config key, its value, and where it came from
 type KeyValueOrigin struct {
 	Key    string
@@ -204,6 +258,13 @@ func SetConfigValue(cfg any, key string, value string) error {
 		return fmt.Errorf("expected pointer to struct, got %s", v.Kind())
 	}
 
+	// "table.entry" keys address an entry in a map-typed table
+	if name, entry, ok := strings.Cut(key, "."); ok {
+		if field, err := FindFieldByTOMLKey(v, name); err == nil && field.Kind() == reflect.Map {
+			return setMapEntry(field, name, entry, value)
+		}
+	}
+
 	field, err := FindOrCreateFieldByTOMLKey(v, key)
 	if err != nil {
 		return err
@@ -216,6 +277,39 @@ func SetConfigValue(cfg any, key string, value string) error {
 	return setFieldValue(field, value)
 }
 
+// mapValueValidators checks entry values for map-typed tables, keyed by
+// table name.
+var mapValueValidators = map[string]func(string) error{
+	"agent_timeouts": func(v string) error {
+		_, err := ParseAgentTimeout(v)
+		return err
+	},
+}
+
+// setMapEntry sets entry in a map[string]string table field, creating the
+// map if needed.
+func setMapEntry(field reflect.Value, name, entry, value string) error {
+	if field.Type().Key().Kind() != reflect.String || field.Type().Elem().Kind() != reflect.String {
+		return fmt.Errorf("cannot set entries of %q", name)
+	}
+	if entry == "" {
+		return fmt.Errorf("missing entry name in key %q", name+".")
+	}
+	if validate := mapValueValidators[name]; validate != nil {
+		if err := validate(value); err != nil {
+			return fmt.Errorf("%s.%s: %w", name, entry, err)
+		}
+	}
+	if !field.CanSet() {
+		return fmt.Errorf("cannot set field for key %q", name)
+	}
+	if field.IsNil() {
+		field.Set(reflect.MakeMap(field.Type()))
+	}
+	field.SetMapIndex(reflect.ValueOf(entry).Convert(field.Type().Key()), reflect.ValueOf(value).Convert(field.Type().Elem()))
+	return nil
+}
+
 // ListConfigKeys returns all non-zero values from a config struct as key-value pairs.
 func ListConfigKeys(cfg any) []KeyValue {
 	v := reflect.ValueOf(cfg)
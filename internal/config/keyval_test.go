@@ -194,6 +194,12 @@ func TestSetConfigValue(t *testing.T) {
 			val:    "",
 			verify: func(c *Config) bool { return len(c.CI.Repos) == 0 },
 		},
+		{
+			name:   "set map entry",
+			key:    "agent_timeouts.codex:thorough",
+			val:    "90m",
+			verify: func(c *Config) bool { return c.AgentTimeouts["codex:thorough"] == "90m" },
+		},
 	}
 
 	for _, tt := range tests {
@@ -219,6 +225,19 @@ func TestSetConfigValue(t *testing.T) {
 	}
 }
 
+func TestSetConfigValueAgentTimeoutValidation(t *testing.T) {
+	for _, val := range []string{"soon", "0s", "-5m"} {
+		cfg := &Config{}
+		err := SetConfigValue(cfg, "agent_timeouts.gemini", val)
+		if err == nil {
+			t.Errorf("SetConfigValue(agent_timeouts.gemini, %q): expected error", val)
+		}
+		if len(cfg.AgentTimeouts) != 0 {
+			t.Errorf("invalid value %q should not be stored, got %v", val, cfg.AgentTimeouts)
+		}
+	}
+}
+
 func TestSetConfigValueMultipleKeys(t *testing.T) {
 	cfg := &Config{}
 	updates := []struct {
@@ -392,6 +411,32 @@ func TestMergedConfigWithOriginLocalOverridesGlobal(t *testing.T) {
 	})
 }
 
+func TestMergedConfigWithOriginVerdictGating(t *testing.T) {
+	global := DefaultConfig()
+	global.FailThreshold = "medium"
+	global.BlockingKeywords = []string{"SECURITY"}
+
+	repo := &RepoConfig{
+		FailThreshold: "high",
+		FindingIgnore: []string{"vendor/", "generated"},
+	}
+
+	rawGlobal := map[string]any{
+		"fail_threshold":    "medium",
+		"blocking_keywords": []any{"SECURITY"},
+	}
+	rawRepo := map[string]any{
+		"fail_threshold": "high",
+		"finding_ignore": []any{"vendor/", "generated"},
+	}
+
+	assertOrigins(t, MergedConfigWithOrigin(global, repo, rawGlobal, rawRepo), map[string]expectedOrigin{
+		"fail_threshold":    {Value: "high", Origin: "local"},
+		"blocking_keywords": {Value: "SECURITY", Origin: "global"},
+		"finding_ignore":    {Value: "vendor/,generated", Origin: "local"},
+	})
+}
+
 func TestMergedConfigWithOriginShowsAllOrigins(t *testing.T) {
 	global := DefaultConfig()
 	global.DefaultAgent = "gemini" // override from default
@@ -576,6 +621,57 @@ func TestIsSensitiveKey(t *testing.T) {
 	if IsSensitiveKey("ci.github_app_id") {
 		t.Error("expected ci.github_app_id to not be sensitive")
 	}
+	if !IsSensitiveKey("github.token") {
+		t.Error("expected github.token to be sensitive")
+	}
+
+	extra := []string{"post_review_hook", "sync.*"}
+	for _, key := range []string{"post_review_hook", "sync.postgres_url", "sync.machine_name", "anthropic_api_key"} {
+		if !IsSensitiveKey(key, extra...) {
+			t.Errorf("expected %s to be sensitive with extra keys %v", key, extra)
+		}
+	}
+	if IsSensitiveKey("default_agent", extra...) {
+		t.Error("expected default_agent to not be sensitive")
+	}
+}
+
+func TestNativeValue(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  any
+	}{
+		{"max_workers", "8", int64(8)},
+		{"sync.enabled", "true", true},
+		{"allow_unsafe_agents", "", nil},
+		{"allow_unsafe_agents", "false", false},
+		{"default_agent", "codex", "codex"},
+		{"agent", "claude-code", "claude-code"},
+		{"max_workers", "lots", "lots"},
+		{"nonexistent", "1", "1"},
+	}
+	for _, tt := range tests {
+		if got := NativeValue(tt.key, tt.value); got != tt.want {
+			t.Errorf("NativeValue(%q, %q) = %#v, want %#v", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		val  string
+		want string
+	}{
+		{"", "****"},
+		{"abcd", "****"},
+		{"https://hooks.example/secret-wxyz", "****wxyz"},
+	}
+	for _, tt := range tests {
+		if got := MaskValue(tt.val); got != tt.want {
+			t.Errorf("MaskValue(%q) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
 }
 
 func TestIsGlobalKey(t *testing.T) {
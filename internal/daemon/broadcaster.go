@@ -14,10 +14,12 @@ type Event struct {
 	Repo     string    `json:"repo"`
 	RepoName string    `json:"repo_name"`
 	SHA      string    `json:"sha"`
+	Subject  string    `json:"subject,omitempty"`
 	Agent    string    `json:"agent,omitempty"`
 	Verdict  string    `json:"verdict,omitempty"`
 	Findings string    `json:"findings,omitempty"`
 	Error    string    `json:"error,omitempty"`
+	JobType  string    `json:"job_type,omitempty"`
 }
 
 // Subscriber represents a client subscribed to events
@@ -424,11 +424,11 @@ func (p *CIPoller) processPR(ctx context.Context, ghRepo string, pr ghPR, cfg *c
 					return fmt.Errorf("no review agent available for type=%s: %w", rt, err)
 				}
 				resolvedAgent = name
-			} else if resolved, err := agent.GetAvailable(resolvedAgent); err != nil {
+			} else if name, err := resolveJobAgent(cfg, resolvedAgent, false); err != nil {
 				rollback()
 				return fmt.Errorf("no review agent available for type=%s: %w", rt, err)
 			} else {
-				resolvedAgent = resolved.Name()
+				resolvedAgent = name
 			}
 
 			// Resolve model through workflow config
@@ -1398,8 +1398,12 @@ func formatPRComment(review *storage.Review, verdict string) string {
 	}
 
 	if review.Job != nil {
+		reviewType := review.Job.ReviewType
+		if reviewType == "" {
+			reviewType = config.ReviewTypeDefault
+		}
 		fmt.Fprintf(&b, "\n---\n*Review type: %s | Agent: %s | Job: %d*\n",
-			review.Job.ReviewType, review.Job.Agent, review.Job.ID)
+			reviewType, review.Job.Agent, review.Job.ID)
 	}
 
 	return b.String()
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/roborev-dev/roborev/internal/git"
@@ -48,6 +50,68 @@ type Client interface {
 	Remap(req RemapRequest) (*RemapResult, error)
 }
 
+// maxRateLimitRetries and maxRetryAfter bound how PostWithRetry waits out
+// the daemon's rate limit (daemon.rate_limit) before giving up.
+const (
+	maxRateLimitRetries = 3
+	maxRetryAfter       = 10 * time.Second
+)
+
+// retrySleep waits between rate-limited attempts. Tests override it.
+var retrySleep = time.Sleep
+
+// PostWithRetry POSTs a JSON body, retrying when the daemon answers 429
+// Too Many Requests. It waits the response's Retry-After seconds (1 if
+// unset) between attempts, up to maxRateLimitRetries times; a longer
+// Retry-After than maxRetryAfter, or running out of retries, returns the
+// 429 response for the caller to report.
+func PostWithRetry(client *http.Client, url string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt == maxRateLimitRetries {
+			return resp, err
+		}
+		wait := time.Second
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			wait = time.Duration(secs) * time.Second
+		}
+		if wait > maxRetryAfter {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		retrySleep(wait)
+	}
+}
+
+// ReadErrorBody reads an error response body and returns the message from
+// its {"error": {...}} envelope, falling back to the raw body text or the
+// HTTP status.
+func ReadErrorBody(body io.Reader, status string) string {
+	data, err := io.ReadAll(io.LimitReader(body, 1024))
+	if err != nil || len(data) == 0 {
+		return status
+	}
+	var errResp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(data, &errResp) == nil && len(errResp.Error) > 0 {
+		var detail ErrorDetail
+		if json.Unmarshal(errResp.Error, &detail) == nil && detail.Message != "" {
+			return detail.Message
+		}
+		// Older daemons send the message as a bare string
+		var msg string
+		if json.Unmarshal(errResp.Error, &msg) == nil && msg != "" {
+			return msg
+		}
+	}
+	if s := strings.TrimSpace(string(data)); s != "" {
+		return s
+	}
+	return status
+}
+
 // DefaultPollInterval is the default polling interval for WaitForReview.
 // Tests can override this to speed up polling-based tests.
 var DefaultPollInterval = 2 * time.Second
@@ -146,8 +210,7 @@ func (c *HTTPClient) MarkReviewAddressed(jobID int64) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("mark addressed: %s: %s", resp.Status, body)
+		return fmt.Errorf("mark addressed: %s: %s", resp.Status, ReadErrorBody(resp.Body, resp.Status))
 	}
 
 	return nil
@@ -167,8 +230,7 @@ func (c *HTTPClient) AddComment(jobID int64, commenter, comment string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("add comment: %s: %s", resp.Status, body)
+		return fmt.Errorf("add comment: %s: %s", resp.Status, ReadErrorBody(resp.Body, resp.Status))
 	}
 
 	return nil
@@ -181,15 +243,14 @@ func (c *HTTPClient) EnqueueReview(repoPath, gitRef, agentName string) (int64, e
 		"agent":     agentName,
 	})
 
-	resp, err := c.httpClient.Post(c.addr+"/api/enqueue", "application/json", bytes.NewReader(reqBody))
+	resp, err := PostWithRetry(c.httpClient, c.addr+"/api/enqueue", reqBody)
 	if err != nil {
 		return 0, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("enqueue failed: %s", body)
+		return 0, fmt.Errorf("enqueue failed: %s", ReadErrorBody(resp.Body, resp.Status))
 	}
 
 	var job storage.ReviewJob
@@ -421,8 +482,7 @@ func (c *HTTPClient) Remap(req RemapRequest) (*RemapResult, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("remap: %s: %s", resp.Status, body)
+		return nil, fmt.Errorf("remap: %s: %s", resp.Status, ReadErrorBody(resp.Body, resp.Status))
 	}
 
 	var result RemapResult
@@ -396,3 +396,87 @@ func TestFindPendingJobForRef(t *testing.T) {
 		})
 	}
 }
+
+func TestPostWithRetry(t *testing.T) {
+	var slept []time.Duration
+	retrySleep = func(d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { retrySleep = time.Sleep })
+
+	tests := []struct {
+		name       string
+		limited    int    // requests answered with 429 before succeeding
+		retryAfter string // Retry-After header on each 429
+		wantCalls  int32
+		wantStatus int
+		wantSleeps []time.Duration
+	}{
+		{
+			name:       "waits out Retry-After",
+			limited:    2,
+			retryAfter: "2",
+			wantCalls:  3,
+			wantStatus: http.StatusCreated,
+			wantSleeps: []time.Duration{2 * time.Second, 2 * time.Second},
+		},
+		{
+			name:       "defaults to one second",
+			limited:    1,
+			wantCalls:  2,
+			wantStatus: http.StatusCreated,
+			wantSleeps: []time.Duration{time.Second},
+		},
+		{
+			name:       "gives up after the retry limit",
+			limited:    100,
+			retryAfter: "1",
+			wantCalls:  maxRateLimitRetries + 1,
+			wantStatus: http.StatusTooManyRequests,
+			wantSleeps: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:       "does not wait out a long Retry-After",
+			limited:    1,
+			retryAfter: "60",
+			wantCalls:  1,
+			wantStatus: http.StatusTooManyRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slept = nil
+			var calls atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(calls.Add(1)) <= tt.limited {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer ts.Close()
+
+			resp, err := PostWithRetry(ts.Client(), ts.URL+"/api/enqueue", []byte(`{}`))
+			if err != nil {
+				t.Fatalf("PostWithRetry: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if len(slept) != len(tt.wantSleeps) {
+				t.Fatalf("slept %v, want %v", slept, tt.wantSleeps)
+			}
+			for i := range slept {
+				if slept[i] != tt.wantSleeps[i] {
+					t.Errorf("slept %v, want %v", slept, tt.wantSleeps)
+					break
+				}
+			}
+		})
+	}
+}
@@ -39,6 +39,8 @@ func (sc *StaticConfig) Config() *config.Config {
 // allow_unsafe_agents, anthropic_api_key, review_context_count.
 //
 // Settings requiring restart: server_addr, max_workers, [sync] section.
+// A running daemon's worker count can still be changed through
+// /api/workers/resize (roborev config set max_workers N --global --apply).
 // These are read at startup and the running values are preserved even if the
 // config file changes. CLI flag overrides (--addr, --workers) only apply to
 // restart-required settings, so they remain in effect for the daemon's lifetime.
@@ -211,6 +213,9 @@ func (cw *ConfigWatcher) reloadConfig() {
 	// Update global agent settings
 	agent.SetAllowUnsafeAgents(newCfg.AllowUnsafeAgents != nil && *newCfg.AllowUnsafeAgents)
 	agent.SetAnthropicAPIKey(newCfg.AnthropicAPIKey)
+	if err := agent.SetCustomAgents(newCfg.CustomAgentCommands()); err != nil {
+		log.Printf("Warning: skipping invalid custom agents: %v", err)
+	}
 
 	// Log what changed (for debugging)
 	logConfigChanges(oldCfg, newCfg)
@@ -239,6 +244,21 @@ func logConfigChanges(old, new *config.Config) {
 	if old.ReviewContextCount != new.ReviewContextCount {
 		log.Printf("Config change: review_context_count %d -> %d", old.ReviewContextCount, new.ReviewContextCount)
 	}
+	if old.PerRepoMaxWorkers != new.PerRepoMaxWorkers {
+		log.Printf("Config change: per_repo_max_workers %d -> %d", old.PerRepoMaxWorkers, new.PerRepoMaxWorkers)
+	}
+	if old.MaxFixWorkers != new.MaxFixWorkers {
+		log.Printf("Config change: max_fix_workers %d -> %d", old.MaxFixWorkers, new.MaxFixWorkers)
+	}
+	if old.PauseOnAuthFailure != new.PauseOnAuthFailure {
+		log.Printf("Config change: pause_on_auth_failure %v -> %v", old.PauseOnAuthFailure, new.PauseOnAuthFailure)
+	}
+	if old.Daemon.RateLimit != new.Daemon.RateLimit {
+		log.Printf("Config change: daemon.rate_limit %g -> %g", old.Daemon.RateLimit, new.Daemon.RateLimit)
+	}
+	if old.AgentTimeout != new.AgentTimeout {
+		log.Printf("Config change: agent_timeout %q -> %q", old.AgentTimeout, new.AgentTimeout)
+	}
 	if old.JobTimeoutMinutes != new.JobTimeoutMinutes {
 		log.Printf("Config change: job_timeout_minutes %d -> %d", old.JobTimeoutMinutes, new.JobTimeoutMinutes)
 	}
@@ -248,7 +268,7 @@ func logConfigChanges(old, new *config.Config) {
 		log.Printf("Config change: allow_unsafe_agents %v -> %v", oldUnsafe, newUnsafe)
 	}
 	if old.MaxWorkers != new.MaxWorkers {
-		log.Printf("Config change: max_workers %d -> %d (requires daemon restart to take effect)", old.MaxWorkers, new.MaxWorkers)
+		log.Printf("Config change: max_workers %d -> %d (requires daemon restart or 'roborev config set max_workers --global --apply')", old.MaxWorkers, new.MaxWorkers)
 	}
 	if old.ServerAddr != new.ServerAddr {
 		log.Printf("Config change: server_addr %q -> %q (requires daemon restart to take effect)", old.ServerAddr, new.ServerAddr)
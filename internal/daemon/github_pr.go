@@ -0,0 +1,282 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/config"
+	gitpkg "github.com/roborev-dev/roborev/internal/git"
+	reviewpkg "github.com/roborev-dev/roborev/internal/review"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+// githubPRTimeout bounds posting a review to a GitHub pull request,
+// including the lookup of the pull request.
+const githubPRTimeout = 30 * time.Second
+
+// ErrNoPullRequest means no open pull request has the reviewed commit as
+// its head.
+var ErrNoPullRequest = errors.New("no open pull request has this commit as its head")
+
+// GitHubAPIError is a non-2xx response from the GitHub API.
+type GitHubAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *GitHubAPIError) Error() string {
+	var hint string
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		hint = " (check github.token)"
+	case http.StatusForbidden:
+		hint = " (the token may lack permission to comment on pull requests)"
+	case http.StatusNotFound:
+		hint = " (check github_repo and that the token can access it)"
+	}
+	return fmt.Sprintf("GitHub API returned %d: %s%s", e.StatusCode, e.Message, hint)
+}
+
+// GitHubPRCommenter posts reviews as comments on GitHub pull requests
+// using the [github] token from config.toml.
+type GitHubPRCommenter struct {
+	token  string
+	client httpDoer
+
+	// baseURL overrides the GitHub API base URL for testing.
+	// Empty string means https://api.github.com.
+	baseURL string
+}
+
+// NewGitHubPRCommenter creates a commenter that authenticates with token.
+func NewGitHubPRCommenter(token string) *GitHubPRCommenter {
+	return &GitHubPRCommenter{
+		token:  token,
+		client: &http.Client{Timeout: githubPRTimeout},
+	}
+}
+
+// PostReview posts body as a comment on the open pull request in repo
+// ("owner/name") whose head is sha, and returns the PR number. Returns
+// ErrNoPullRequest when there is no such pull request.
+func (c *GitHubPRCommenter) PostReview(ctx context.Context, repo, sha, body string) (int, error) {
+	number, err := c.findPR(ctx, repo, sha)
+	if err != nil {
+		return 0, err
+	}
+	return number, c.comment(ctx, repo, number, body)
+}
+
+// comment posts body as a comment on pull request number in repo.
+func (c *GitHubPRCommenter) comment(ctx context.Context, repo string, number int, body string) error {
+	if len(body) > reviewpkg.MaxCommentLen {
+		body = body[:reviewpkg.MaxCommentLen] + "\n\n...(truncated)"
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number)
+	if err := c.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("comment on PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// findPR returns the number of the open pull request in repo whose head
+// commit is sha.
+func (c *GitHubPRCommenter) findPR(ctx context.Context, repo, sha string) (int, error) {
+	var prs []struct {
+		Number int    `json:"number"`
+		State  string `json:"state"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/pulls", repo, sha), nil, &prs); err != nil {
+		return 0, fmt.Errorf("find pull request: %w", err)
+	}
+	for _, pr := range prs {
+		if pr.State == "open" && pr.Head.SHA == sha {
+			return pr.Number, nil
+		}
+	}
+	return 0, ErrNoPullRequest
+}
+
+// do sends an authenticated GitHub API request with an optional JSON body
+// and decodes the response into out when it is non-nil.
+func (c *GitHubPRCommenter) do(ctx context.Context, method, path string, body, out any) error {
+	baseURL := c.baseURL
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "roborev")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if json.Unmarshal(msg, &apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(msg))
+		}
+		return &GitHubAPIError{StatusCode: resp.StatusCode, Message: apiErr.Message}
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolveGitHubRepo returns the "owner/name" repository to post reviews
+// of repoPath to: the repo's github_repo when set, else its GitHub
+// remote. Returns "" when neither is available.
+func ResolveGitHubRepo(repoPath string) string {
+	if repoPath == "" {
+		return ""
+	}
+	if repoCfg, err := config.LoadRepoConfig(repoPath); err == nil && repoCfg != nil && repoCfg.GitHubRepo != "" {
+		return repoCfg.GitHubRepo
+	}
+	return ownerRepoFromURL(gitpkg.GetRemoteURL(repoPath, ""))
+}
+
+// HeadSHA returns the commit a review's git ref ends at: the ref itself,
+// or the end of a "base..head" range.
+func HeadSHA(gitRef string) string {
+	if _, head, ok := strings.Cut(gitRef, ".."); ok {
+		return head
+	}
+	return gitRef
+}
+
+// isCommitReview reports whether jobs of jobType review committed code,
+// and so may belong to a pull request.
+func isCommitReview(jobType string) bool {
+	return jobType == storage.JobTypeReview || jobType == storage.JobTypeRange
+}
+
+// GitHubReviewComment formats a review as a GitHub PR comment.
+func GitHubReviewComment(review *storage.Review) string {
+	verdict := storage.ParseVerdict(review.Output)
+	if review.Job != nil && review.Job.Verdict != nil {
+		verdict = *review.Job.Verdict
+	}
+	return formatPRComment(review, verdict)
+}
+
+// postGitHubPRComment posts a completed review to the pull request whose
+// head is the reviewed commit, once per pull request head. Jobs of a CI
+// poller batch are skipped, since the poller posts its own comment.
+// Errors, including a missing pull request, a 404, or a rejected token,
+// are logged and never fail the job.
+func (hr *HookRunner) postGitHubPRComment(cfg config.GitHubConfig, event Event) {
+	review := &storage.Review{
+		JobID:  event.JobID,
+		Agent:  event.Agent,
+		Output: event.Findings,
+		Job:    &storage.ReviewJob{ID: event.JobID, Agent: event.Agent},
+	}
+	if event.Verdict != "" {
+		review.Job.Verdict = &event.Verdict
+	}
+	if hr.db != nil {
+		if batch, err := hr.db.GetCIBatchByJobID(event.JobID); err != nil {
+			log.Printf("GitHub PR comment skipped (job %d): %v", event.JobID, err)
+			return
+		} else if batch != nil {
+			return
+		}
+		stored, err := hr.db.GetReviewByJobID(event.JobID)
+		if err != nil {
+			log.Printf("GitHub PR comment skipped (job %d): load review: %v", event.JobID, err)
+			return
+		}
+		review = stored
+	}
+
+	repo := ResolveGitHubRepo(event.Repo)
+	if repo == "" {
+		log.Printf("GitHub PR comment skipped (job %d): no github_repo and no GitHub remote", event.JobID)
+		return
+	}
+	commenter := NewGitHubPRCommenter(cfg.Token)
+	commenter.baseURL = hr.githubBaseURL
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubPRTimeout)
+	defer cancel()
+	sha := HeadSHA(event.SHA)
+	number, err := commenter.findPR(ctx, repo, sha)
+	switch {
+	case errors.Is(err, ErrNoPullRequest):
+		// Most commits aren't the head of a pull request
+		return
+	case err != nil:
+		log.Printf("GitHub PR comment failed (job %d, %s): %v", event.JobID, repo, err)
+		return
+	}
+
+	key := fmt.Sprintf("%s#%d@%s", repo, number, sha)
+	if !hr.claimGitHubPost(key) {
+		return
+	}
+	if err := commenter.comment(ctx, repo, number, GitHubReviewComment(review)); err != nil {
+		hr.releaseGitHubPost(key)
+		log.Printf("GitHub PR comment failed (job %d, %s): %v", event.JobID, repo, err)
+		return
+	}
+	log.Printf("Posted review of job %d to %s#%d", event.JobID, repo, number)
+}
+
+// claimGitHubPost records that a review is being posted to the pull
+// request head key, reporting false if one already was.
+func (hr *HookRunner) claimGitHubPost(key string) bool {
+	hr.githubPostedMu.Lock()
+	defer hr.githubPostedMu.Unlock()
+	if hr.githubPosted[key] {
+		return false
+	}
+	if hr.githubPosted == nil {
+		hr.githubPosted = make(map[string]bool)
+	}
+	hr.githubPosted[key] = true
+	return true
+}
+
+// releaseGitHubPost forgets a claim whose post failed, so a later review
+// of the same head can try again.
+func (hr *HookRunner) releaseGitHubPost(key string) {
+	hr.githubPostedMu.Lock()
+	defer hr.githubPostedMu.Unlock()
+	delete(hr.githubPosted, key)
+}
@@ -0,0 +1,258 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/storage"
+	"github.com/roborev-dev/roborev/internal/testutil"
+)
+
+// fakeGitHub serves the pull request lookup and comment endpoints for
+// owner/repo and records posted comments.
+type fakeGitHub struct {
+	*httptest.Server
+	comments chan string // "<path> <body>"
+	auth     chan string
+}
+
+func newFakeGitHub(t *testing.T, headSHA string, status int) *fakeGitHub {
+	t.Helper()
+	gh := &fakeGitHub{comments: make(chan string, 4), auth: make(chan string, 8)}
+	gh.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gh.auth <- r.Header.Get("Authorization")
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"message": "Not Found"})
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/commits/"):
+			json.NewEncoder(w).Encode([]map[string]any{
+				{"number": 3, "state": "closed", "head": map[string]string{"sha": headSHA}},
+				{"number": 7, "state": "open", "head": map[string]string{"sha": headSHA}},
+			})
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/"):
+			var body struct{ Body string }
+			json.NewDecoder(r.Body).Decode(&body)
+			gh.comments <- r.URL.Path + " " + body.Body
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(gh.Close)
+	return gh
+}
+
+func TestGitHubPRCommenterPostReview(t *testing.T) {
+	const sha = "abc1234def5678"
+
+	t.Run("posts to the open PR with the commit as head", func(t *testing.T) {
+		gh := newFakeGitHub(t, sha, http.StatusOK)
+		c := NewGitHubPRCommenter("secret-token")
+		c.baseURL = gh.URL
+
+		number, err := c.PostReview(context.Background(), "owner/repo", sha, "looks good")
+		if err != nil {
+			t.Fatalf("PostReview: %v", err)
+		}
+		if number != 7 {
+			t.Errorf("PR number = %d, want 7", number)
+		}
+		if got := <-gh.comments; got != "/repos/owner/repo/issues/7/comments looks good" {
+			t.Errorf("comment = %q", got)
+		}
+		if got := <-gh.auth; got != "Bearer secret-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+	})
+
+	t.Run("no PR for commit", func(t *testing.T) {
+		gh := newFakeGitHub(t, "other-sha", http.StatusOK)
+		c := NewGitHubPRCommenter("secret-token")
+		c.baseURL = gh.URL
+
+		if _, err := c.PostReview(context.Background(), "owner/repo", sha, "body"); !errors.Is(err, ErrNoPullRequest) {
+			t.Errorf("err = %v, want ErrNoPullRequest", err)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		gh := newFakeGitHub(t, sha, http.StatusNotFound)
+		c := NewGitHubPRCommenter("secret-token")
+		c.baseURL = gh.URL
+
+		_, err := c.PostReview(context.Background(), "owner/repo", sha, "body")
+		var apiErr *GitHubAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Fatalf("err = %v, want a 404 GitHubAPIError", err)
+		}
+		if !strings.Contains(err.Error(), "github_repo") {
+			t.Errorf("error %q should hint at github_repo", err)
+		}
+	})
+}
+
+// githubRepoDir returns a repo directory whose .roborev.toml posts
+// reviews to owner/repo.
+func githubRepoDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".roborev.toml"), []byte(`github_repo = "owner/repo"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// expectNoGitHubRequest fails if gh receives a request shortly.
+func expectNoGitHubRequest(t *testing.T, gh *fakeGitHub) {
+	t.Helper()
+	select {
+	case <-gh.auth:
+		t.Error("unexpected GitHub request")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestHandleEventGitHubPRComment(t *testing.T) {
+	event := failingReviewEvent()
+	event.JobType = storage.JobTypeReview
+	event.Repo = githubRepoDir(t)
+	cfg := &config.Config{GitHub: config.GitHubConfig{Token: "secret-token"}}
+
+	t.Run("posts completed commit review", func(t *testing.T) {
+		gh := newFakeGitHub(t, event.SHA, http.StatusOK)
+		hr := &HookRunner{cfgGetter: NewStaticConfig(cfg), githubBaseURL: gh.URL}
+
+		hr.handleEvent(event)
+
+		select {
+		case got := <-gh.comments:
+			if !strings.Contains(got, "/issues/7/comments") || !strings.Contains(got, "roborev: Fail") || !strings.Contains(got, "SQL built with string concatenation") {
+				t.Errorf("comment = %q", got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a PR comment")
+		}
+	})
+
+	t.Run("posts once per pull request head", func(t *testing.T) {
+		gh := newFakeGitHub(t, event.SHA, http.StatusOK)
+		hr := &HookRunner{cfgGetter: NewStaticConfig(cfg), githubBaseURL: gh.URL}
+
+		hr.postGitHubPRComment(cfg.GitHub, event)
+		second := event
+		second.JobID++
+		hr.postGitHubPRComment(cfg.GitHub, second)
+
+		if got := len(gh.comments); got != 1 {
+			t.Errorf("posted %d comments, want 1", got)
+		}
+	})
+
+	for _, tc := range []struct {
+		name  string
+		event func(Event) Event
+		cfg   *config.Config
+	}{
+		{"fix job", func(e Event) Event { e.JobType = storage.JobTypeFix; return e }, cfg},
+		{"failed job", func(e Event) Event { e.Type = "review.failed"; return e }, cfg},
+		{"no token configured", func(e Event) Event { return e }, &config.Config{}},
+	} {
+		t.Run("skips "+tc.name, func(t *testing.T) {
+			gh := newFakeGitHub(t, event.SHA, http.StatusOK)
+			hr := &HookRunner{cfgGetter: NewStaticConfig(tc.cfg), githubBaseURL: gh.URL}
+
+			hr.handleEvent(tc.event(event))
+
+			expectNoGitHubRequest(t, gh)
+		})
+	}
+}
+
+func TestPostGitHubPRCommentStoredJobs(t *testing.T) {
+	db := testutil.OpenTestDB(t)
+	repoDir := githubRepoDir(t)
+	repo, err := db.GetOrCreateRepo(repoDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+	cfg := config.GitHubConfig{Token: "secret-token"}
+
+	// complete stores a review of sha whose output looks failing
+	complete := func(sha string) Event {
+		t.Helper()
+		commit, err := db.GetOrCreateCommit(repo.ID, sha, "A", "S", time.Now())
+		if err != nil {
+			t.Fatalf("GetOrCreateCommit: %v", err)
+		}
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		if _, err := db.ClaimJob("w"); err != nil {
+			t.Fatalf("ClaimJob: %v", err)
+		}
+		if err := db.CompleteJobByAgent(job.ID, "test", "test", "prompt", "- Medium: unchecked error", storage.VerdictPolicy{FailThreshold: "high"}, nil); err != nil {
+			t.Fatalf("CompleteJobByAgent: %v", err)
+		}
+		return Event{Type: "review.completed", JobID: job.ID, Repo: repoDir, SHA: sha, Agent: "test", JobType: storage.JobTypeReview}
+	}
+
+	t.Run("uses the stored verdict", func(t *testing.T) {
+		event := complete("stored-verdict-sha")
+		event.Verdict = "F"
+		gh := newFakeGitHub(t, event.SHA, http.StatusOK)
+		hr := &HookRunner{db: db, githubBaseURL: gh.URL}
+
+		hr.postGitHubPRComment(cfg, event)
+
+		select {
+		case got := <-gh.comments:
+			if !strings.Contains(got, "roborev: Pass") {
+				t.Errorf("comment = %q, want the stored pass verdict", got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a PR comment")
+		}
+	})
+
+	t.Run("skips CI poller batch jobs", func(t *testing.T) {
+		event := complete("ci-batch-sha")
+		batch, _, err := db.CreateCIBatch("owner/repo", 7, event.SHA, 1)
+		if err != nil {
+			t.Fatalf("CreateCIBatch: %v", err)
+		}
+		if err := db.RecordBatchJob(batch.ID, event.JobID); err != nil {
+			t.Fatalf("RecordBatchJob: %v", err)
+		}
+		gh := newFakeGitHub(t, event.SHA, http.StatusOK)
+		hr := &HookRunner{db: db, githubBaseURL: gh.URL}
+
+		hr.postGitHubPRComment(cfg, event)
+
+		expectNoGitHubRequest(t, gh)
+	})
+}
+
+func TestHeadSHA(t *testing.T) {
+	for ref, want := range map[string]string{
+		"abc123":         "abc123",
+		"abc123..def456": "def456",
+	} {
+		if got := HeadSHA(ref); got != want {
+			t.Errorf("HeadSHA(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
@@ -1,34 +1,54 @@
 package daemon
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/roborev-dev/roborev/internal/config"
 	gitpkg "github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
 )
 
 // HookRunner listens for broadcaster events and runs configured hooks.
 type HookRunner struct {
 	cfgGetter   ConfigGetter
 	broadcaster Broadcaster
+	db          *storage.DB // nil disables lookups that need stored jobs
 	subID       int
 	stopCh      chan struct{}
+	slackClient httpDoer
+
+	// githubBaseURL overrides the GitHub API base URL for testing.
+	// Empty string means https://api.github.com.
+	githubBaseURL string
+
+	// githubPosted holds the "repo#pr@sha" keys of pull request heads a
+	// review has been posted to, so each head gets one comment.
+	githubPostedMu sync.Mutex
+	githubPosted   map[string]bool
 }
 
 // NewHookRunner creates a new HookRunner that subscribes to events from the broadcaster.
-func NewHookRunner(cfgGetter ConfigGetter, broadcaster Broadcaster) *HookRunner {
+func NewHookRunner(cfgGetter ConfigGetter, broadcaster Broadcaster, db *storage.DB) *HookRunner {
 	subID, eventCh := broadcaster.Subscribe("")
 
 	hr := &HookRunner{
 		cfgGetter:   cfgGetter,
 		broadcaster: broadcaster,
+		db:          db,
 		subID:       subID,
 		stopCh:      make(chan struct{}),
+		slackClient: &http.Client{Timeout: slackTimeout},
 	}
 
 	go hr.listen(eventCh)
@@ -97,6 +117,92 @@ func (hr *HookRunner) handleEvent(event Event) {
 	if fired > 0 {
 		log.Printf("Hooks: fired %d hook(s) for %s (job %d)", fired, event.Type, event.JobID)
 	}
+
+	if event.Type == "review.completed" && cfg.PostReviewHook != "" {
+		go runPostReviewHook(cfg.PostReviewHook, event)
+	}
+
+	if event.Type == "review.completed" && event.Verdict == "F" && cfg.Notify.SlackWebhookURL != "" {
+		go hr.notifySlack(cfg.Notify.SlackWebhookURL, event)
+	}
+
+	if event.Type == "review.completed" && cfg.GitHub.Token != "" && isCommitReview(event.JobType) {
+		go hr.postGitHubPRComment(cfg.GitHub, event)
+	}
+}
+
+// postReviewHookTimeout bounds how long a post_review_hook may run.
+const postReviewHookTimeout = 2 * time.Minute
+
+// PostReviewPayload is the JSON document written to post_review_hook's stdin.
+type PostReviewPayload struct {
+	Job      PostReviewJob     `json:"job"`
+	Verdict  string            `json:"verdict"` // "P" or "F"
+	Summary  string            `json:"summary"`
+	Findings []storage.Finding `json:"findings"`
+}
+
+// PostReviewJob identifies the reviewed job in a PostReviewPayload.
+type PostReviewJob struct {
+	ID       int64  `json:"id"`
+	Repo     string `json:"repo"`
+	RepoName string `json:"repo_name"`
+	SHA      string `json:"sha"`
+	Agent    string `json:"agent"`
+}
+
+// newPostReviewPayload builds the post_review_hook payload for a
+// review.completed event, whose Findings field holds the review output.
+func newPostReviewPayload(event Event) PostReviewPayload {
+	findings := storage.ExtractFindings(event.Findings)
+	if findings == nil {
+		findings = []storage.Finding{}
+	}
+	return PostReviewPayload{
+		Job: PostReviewJob{
+			ID:       event.JobID,
+			Repo:     event.Repo,
+			RepoName: event.RepoName,
+			SHA:      event.SHA,
+			Agent:    event.Agent,
+		},
+		Verdict:  event.Verdict,
+		Summary:  storage.ExtractSummary(event.Findings),
+		Findings: findings,
+	}
+}
+
+// runPostReviewHook runs the post_review_hook executable in the repo with
+// the review payload on stdin. Errors and timeouts are logged but never
+// propagated.
+func runPostReviewHook(path string, event Event) {
+	payload, err := json.Marshal(newPostReviewPayload(event))
+	if err != nil {
+		log.Printf("Post-review hook: encode payload for job %d: %v", event.JobID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postReviewHookTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path)
+	if event.Repo != "" {
+		cmd.Dir = event.Repo
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.WaitDelay = 5 * time.Second
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		log.Printf("Post-review hook timed out after %v (hook=%q job=%d)", postReviewHookTimeout, path, event.JobID)
+		return
+	}
+	if err != nil {
+		log.Printf("Post-review hook error (hook=%q job=%d): %v\n%s", path, event.JobID, err, output)
+		return
+	}
+	if len(output) > 0 {
+		log.Printf("Post-review hook output (hook=%q): %s", path, output)
+	}
 }
 
 // matchEvent checks if an event type matches a hook's event pattern.
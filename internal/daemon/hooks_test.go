@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"bytes"
+	"encoding/json"
 	"log"
 	"os"
 	"path/filepath"
@@ -22,7 +23,7 @@ func quote(s string) string {
 func setupRunner(t *testing.T, cfg *config.Config) (*HookRunner, Broadcaster) {
 	t.Helper()
 	b := NewBroadcaster()
-	hr := NewHookRunner(NewStaticConfig(cfg), b)
+	hr := NewHookRunner(NewStaticConfig(cfg), b, nil)
 	t.Cleanup(hr.Stop)
 	return hr, b
 }
@@ -635,7 +636,7 @@ func TestHookRunnerStopUnsubscribes(t *testing.T) {
 	cfg := &config.Config{}
 
 	before := broadcaster.SubscriberCount()
-	hr := NewHookRunner(NewStaticConfig(cfg), broadcaster)
+	hr := NewHookRunner(NewStaticConfig(cfg), broadcaster, nil)
 	afterSub := broadcaster.SubscriberCount()
 	if afterSub != before+1 {
 		t.Errorf("expected subscriber count %d after NewHookRunner, got %d", before+1, afterSub)
@@ -739,3 +740,75 @@ func TestHandleEventNoLogWhenNoHooksMatch(t *testing.T) {
 		t.Errorf("expected no log output when no hooks match, got %q", buf.String())
 	}
 }
+
+func TestPostReviewHookReceivesPayload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script uses sh")
+	}
+
+	tmpDir := t.TempDir()
+	payloadFile := filepath.Join(tmpDir, "payload.json")
+	script := filepath.Join(tmpDir, "post-review.sh")
+	// Write to a temp file and rename so the test never reads a partial payload.
+	body := "#!/bin/sh\ncat > " + quote(payloadFile+".tmp") + " && mv " + quote(payloadFile+".tmp") + " " + quote(payloadFile) + "\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, broadcaster := setupRunner(t, &config.Config{PostReviewHook: script})
+
+	broadcaster.Broadcast(Event{
+		Type:     "review.completed",
+		TS:       time.Now(),
+		JobID:    42,
+		Repo:     tmpDir,
+		RepoName: "test",
+		SHA:      "abc123",
+		Agent:    "codex",
+		Verdict:  "F",
+		Findings: "- High: SQL built with string concatenation\n- Low: typo in comment",
+	})
+
+	var got PostReviewPayload
+	if err := json.Unmarshal([]byte(waitForFileContent(t, payloadFile, 5*time.Second)), &got); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	want := PostReviewJob{ID: 42, Repo: tmpDir, RepoName: "test", SHA: "abc123", Agent: "codex"}
+	if got.Job != want {
+		t.Errorf("job = %+v, want %+v", got.Job, want)
+	}
+	if got.Verdict != "F" {
+		t.Errorf("verdict = %q, want F", got.Verdict)
+	}
+	if got.Summary == "" {
+		t.Error("expected a summary")
+	}
+	if len(got.Findings) != 2 || got.Findings[0].Severity != "high" || got.Findings[1].Severity != "low" {
+		t.Errorf("findings = %+v, want high then low", got.Findings)
+	}
+}
+
+func TestPostReviewHookIgnoresOtherEvents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook script uses sh")
+	}
+
+	tmpDir := t.TempDir()
+	markerFile := filepath.Join(tmpDir, "hook-fired")
+	script := filepath.Join(tmpDir, "post-review.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ntouch "+quote(markerFile)+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, broadcaster := setupRunner(t, &config.Config{PostReviewHook: script})
+
+	broadcaster.Broadcast(Event{
+		Type:  "review.failed",
+		TS:    time.Now(),
+		JobID: 1,
+		Repo:  tmpDir,
+		Error: "agent crashed",
+	})
+
+	assertFileNotCreated(t, markerFile, 500*time.Millisecond, "post_review_hook should only run for review.completed")
+}
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -105,6 +106,64 @@ func ParseJobIDFromLogName(name string) (int64, bool) {
 	return id, true
 }
 
+// resetJobLog empties a job log file so a new attempt starts a clean log.
+// A nil file is ignored.
+func resetJobLog(f *os.File) {
+	if f == nil {
+		return
+	}
+	if err := f.Truncate(0); err != nil {
+		log.Printf("Warning: cannot reset job log: %v", err)
+		return
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		log.Printf("Warning: cannot reset job log: %v", err)
+	}
+}
+
+// maxAttemptCapture caps how much of an attempt's output attemptWriter
+// keeps for producedOutput.
+const maxAttemptCapture = 64 * 1024
+
+// attemptWriter passes an agent attempt's output through to w, keeping
+// the start of it.
+type attemptWriter struct {
+	w         io.Writer
+	mu        sync.Mutex
+	captured  bytes.Buffer
+	truncated bool
+}
+
+func (a *attemptWriter) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	if room := maxAttemptCapture - a.captured.Len(); len(p) > room {
+		a.captured.Write(p[:room])
+		a.truncated = true
+	} else {
+		a.captured.Write(p)
+	}
+	a.mu.Unlock()
+	return a.w.Write(p)
+}
+
+// producedOutput reports whether the attempt wrote anything besides the
+// text of err. Agents stream their stderr, so an agent that only printed
+// the error it failed with produced no output.
+func (a *attemptWriter) producedOutput(err error) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.truncated {
+		return true
+	}
+	msg := err.Error()
+	for line := range strings.SplitSeq(a.captured.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.Contains(msg, line) {
+			return true
+		}
+	}
+	return false
+}
+
 // safeWriter wraps an io.Writer and silently drops writes after the
 // first error. This prevents a full-disk condition from killing the
 // agent subprocess via a broken-pipe through io.MultiWriter.
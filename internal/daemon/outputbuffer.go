@@ -196,6 +196,25 @@ func (ob *OutputBuffer) CloseJob(jobID int64) {
 	jo.subs = nil
 }
 
+// ResetJob discards a job's buffered lines, keeping its subscribers.
+func (ob *OutputBuffer) ResetJob(jobID int64) {
+	ob.mu.RLock()
+	jo, ok := ob.buffers[jobID]
+	ob.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	jo.mu.Lock()
+	defer jo.mu.Unlock()
+
+	ob.mu.Lock()
+	ob.totalBytes -= jo.totalBytes
+	ob.mu.Unlock()
+	jo.lines = jo.lines[:0]
+	jo.totalBytes = 0
+}
+
 // IsActive returns true if there's an active buffer for this job.
 func (ob *OutputBuffer) IsActive(jobID int64) bool {
 	ob.mu.RLock()
@@ -179,6 +179,25 @@ func TestOutputBuffer_CloseJob(t *testing.T) {
 	}
 }
 
+func TestOutputBuffer_ResetJob(t *testing.T) {
+	ob := NewOutputBuffer(1024, 4096)
+
+	ob.Append(1, OutputLine{Text: "first attempt", Type: "text"})
+	ob.ResetJob(1)
+	if !ob.IsActive(1) {
+		t.Error("expected job to stay active after reset")
+	}
+	if lines := ob.GetLines(1); len(lines) != 0 {
+		t.Errorf("expected no lines after reset, got %d", len(lines))
+	}
+	if ob.totalBytes != 0 {
+		t.Errorf("expected reset to release the job's bytes, total = %d", ob.totalBytes)
+	}
+
+	ob.Append(1, OutputLine{Text: "second attempt", Type: "text"})
+	assertLines(t, ob.GetLines(1), "second attempt")
+}
+
 func TestOutputBuffer_Subscribe(t *testing.T) {
 	ob := NewOutputBuffer(1024, 4096)
 
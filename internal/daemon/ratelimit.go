@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket shared by all callers of the endpoints it
+// guards. The daemon serves a single local user, so it has one global
+// bucket rather than one per client. The bucket holds up to one second of
+// requests (at least one), so short bursts at the configured rate pass.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second the bucket was sized for
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{now: time.Now}
+}
+
+// allow takes a token at the given rate (requests per second; <= 0 means
+// unlimited). When no token is available it returns false and how long
+// until one will be. A rate change, e.g. from a config reload, refills
+// the bucket at the new size.
+func (l *rateLimiter) allow(rate float64) (bool, time.Duration) {
+	if rate <= 0 {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	burst := math.Max(rate, 1)
+	if rate != l.rate {
+		l.rate, l.tokens, l.last = rate, burst, now
+	}
+	l.tokens = math.Min(burst, l.tokens+now.Sub(l.last).Seconds()*rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - l.tokens) / rate * float64(time.Second))
+}
+
+// rateLimited wraps a handler so requests beyond daemon.rate_limit get
+// 429 Too Many Requests with a Retry-After header. The limit is read per
+// request so config reloads apply immediately.
+func (s *Server) rateLimited(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := s.rateLimiter.allow(s.configWatcher.Config().Daemon.RateLimit)
+		if !ok {
+			retryAfter := max(int(math.Ceil(wait.Seconds())), 1)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writeErrorCode(w, http.StatusTooManyRequests, ErrCodeRateLimited,
+				"rate limit exceeded", map[string]any{"retry_after_seconds": retryAfter})
+			return
+		}
+		h(w, r)
+	}
+}
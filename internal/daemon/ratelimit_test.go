@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/testutil"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter()
+	l.now = func() time.Time { return clock }
+
+	// A burst of up to one second of requests passes, then the bucket is empty
+	for i := range 2 {
+		if ok, _ := l.allow(2); !ok {
+			t.Fatalf("request %d within the burst was limited", i+1)
+		}
+	}
+	ok, wait := l.allow(2)
+	if ok {
+		t.Fatal("expected the request after the burst to be limited")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("wait = %v, want 500ms", wait)
+	}
+
+	// Tokens refill at the configured rate
+	clock = clock.Add(500 * time.Millisecond)
+	if ok, _ := l.allow(2); !ok {
+		t.Error("expected a token after 500ms at 2/s")
+	}
+
+	// A rate change resizes and refills the bucket
+	if ok, _ := l.allow(0.5); !ok {
+		t.Error("expected a fresh bucket after a rate change")
+	}
+	if ok, wait := l.allow(0.5); ok || wait != 2*time.Second {
+		t.Errorf("allow = %v, %v; want limited for 2s", ok, wait)
+	}
+
+	// Zero is unlimited
+	for range 100 {
+		if ok, _ := l.allow(0); !ok {
+			t.Fatal("rate 0 should never limit")
+		}
+	}
+}
+
+func TestRateLimitedEndpoints(t *testing.T) {
+	db, _ := testutil.OpenTestDBWithDir(t)
+	cfg := config.DefaultConfig()
+	cfg.Daemon.RateLimit = 1
+	server := NewServer(db, cfg, "")
+	clock := time.Now()
+	server.rateLimiter.now = func() time.Time { return clock }
+	handler := server.httpServer.Handler
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader("{}")))
+		return w
+	}
+
+	// The first request reaches the handler, which rejects the empty body
+	if w := do(http.MethodPost, "/api/job/cancel"); w.Code != http.StatusBadRequest {
+		t.Fatalf("first cancel: status %d: %s", w.Code, w.Body.String())
+	}
+
+	// The bucket is global, so enqueue and cancel share it
+	for _, path := range []string{"/api/enqueue", "/api/job/cancel", "/api/job/cancel-bulk"} {
+		w := do(http.MethodPost, path)
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("%s: status %d, want 429", path, w.Code)
+			continue
+		}
+		if got := w.Header().Get("Retry-After"); got != "1" {
+			t.Errorf("%s: Retry-After = %q, want 1", path, got)
+		}
+		var errResp ErrorResponse
+		testutil.DecodeJSON(t, w, &errResp)
+		if errResp.Error.Code != ErrCodeRateLimited {
+			t.Errorf("%s: code = %q, want %q", path, errResp.Error.Code, ErrCodeRateLimited)
+		}
+	}
+
+	// Status and read endpoints are never limited
+	for _, path := range []string{"/api/status", "/api/jobs"} {
+		if w := do(http.MethodGet, path); w.Code != http.StatusOK {
+			t.Errorf("%s: status %d, want 200", path, w.Code)
+		}
+	}
+
+	// After a second the bucket has a token again
+	clock = clock.Add(time.Second)
+	if w := do(http.MethodPost, "/api/job/cancel"); w.Code == http.StatusTooManyRequests {
+		t.Error("expected the limit to lift after one second")
+	}
+}
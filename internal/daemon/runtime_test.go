@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/roborev-dev/roborev/internal/config"
 	"github.com/roborev-dev/roborev/internal/testenv"
 )
 
@@ -120,6 +121,38 @@ func TestRuntimeInfoReadWrite(t *testing.T) {
 	}
 }
 
+func TestRuntimeProfilesAreIsolated(t *testing.T) {
+	testenv.SetDataDir(t)
+
+	t.Setenv(config.ProfileEnvVar, "work")
+	if err := WriteRuntime("127.0.0.1:7400", 7400, "test-version"); err != nil {
+		t.Fatalf("WriteRuntime failed: %v", err)
+	}
+
+	t.Setenv(config.ProfileEnvVar, "personal")
+	runtimes, err := ListAllRuntimes()
+	if err != nil {
+		t.Fatalf("ListAllRuntimes failed: %v", err)
+	}
+	if len(runtimes) != 0 {
+		t.Fatalf("Expected no runtimes in personal profile, got %+v", runtimes)
+	}
+	if err := WriteRuntime("127.0.0.1:7401", 7401, "test-version"); err != nil {
+		t.Fatalf("WriteRuntime failed: %v", err)
+	}
+
+	for profile, wantAddr := range map[string]string{"work": "127.0.0.1:7400", "personal": "127.0.0.1:7401"} {
+		t.Setenv(config.ProfileEnvVar, profile)
+		info, err := ReadRuntime()
+		if err != nil {
+			t.Fatalf("ReadRuntime(%s) failed: %v", profile, err)
+		}
+		if info.Addr != wantAddr {
+			t.Errorf("profile %s: expected addr %s, got %s", profile, wantAddr, info.Addr)
+		}
+	}
+}
+
 func TestKillDaemonSkipsHTTPForNonLoopback(t *testing.T) {
 	// Verify that isLoopbackAddr correctly rejects non-loopback addresses,
 	// which prevents KillDaemon from making HTTP requests to them.
@@ -19,6 +19,8 @@ import (
 	"github.com/roborev-dev/roborev/internal/config"
 	"github.com/roborev-dev/roborev/internal/git"
 	"github.com/roborev-dev/roborev/internal/githook"
+	"github.com/roborev-dev/roborev/internal/prompt"
+	"github.com/roborev-dev/roborev/internal/review"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/roborev-dev/roborev/internal/version"
 )
@@ -35,6 +37,7 @@ type Server struct {
 	hookRunner    *HookRunner
 	errorLog      *ErrorLog
 	activityLog   *ActivityLog
+	rateLimiter   *rateLimiter
 	startTime     time.Time
 
 	// Cached machine ID to avoid INSERT on every status request
@@ -47,6 +50,9 @@ func NewServer(db *storage.DB, cfg *config.Config, configPath string) *Server {
 	// Always set for deterministic state - default to false (conservative)
 	agent.SetAllowUnsafeAgents(cfg.AllowUnsafeAgents != nil && *cfg.AllowUnsafeAgents)
 	agent.SetAnthropicAPIKey(cfg.AnthropicAPIKey)
+	if err := agent.SetCustomAgents(cfg.CustomAgentCommands()); err != nil {
+		log.Printf("Warning: skipping invalid custom agents: %v", err)
+	}
 	broadcaster := NewBroadcaster()
 
 	// Initialize error log
@@ -65,7 +71,7 @@ func NewServer(db *storage.DB, cfg *config.Config, configPath string) *Server {
 	configWatcher := NewConfigWatcher(configPath, cfg, broadcaster, activityLog)
 
 	// Create hook runner to fire hooks on review events
-	hookRunner := NewHookRunner(configWatcher, broadcaster)
+	hookRunner := NewHookRunner(configWatcher, broadcaster, db)
 
 	s := &Server{
 		db:            db,
@@ -75,28 +81,49 @@ func NewServer(db *storage.DB, cfg *config.Config, configPath string) *Server {
 		hookRunner:    hookRunner,
 		errorLog:      errorLog,
 		activityLog:   activityLog,
+		rateLimiter:   newRateLimiter(),
 		startTime:     time.Now(),
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/api/enqueue", s.handleEnqueue)
+	mux.HandleFunc("/api/enqueue", s.rateLimited(s.handleEnqueue))
 	mux.HandleFunc("/api/health", s.handleHealth)
 	mux.HandleFunc("/api/jobs", s.handleListJobs)
-	mux.HandleFunc("/api/job/cancel", s.handleCancelJob)
+	mux.HandleFunc("/api/job/cancel", s.rateLimited(s.handleCancelJob))
+	mux.HandleFunc("/api/job/cancel-bulk", s.rateLimited(s.handleCancelJobsBulk))
 	mux.HandleFunc("/api/job/output", s.handleJobOutput)
+	mux.HandleFunc("/api/job/{id}/stream", s.handleJobStream)
 	mux.HandleFunc("/api/job/log", s.handleJobLog)
 	mux.HandleFunc("/api/job/rerun", s.handleRerunJob)
+	mux.HandleFunc("/api/job/retry", s.handleRetryJob)
+	mux.HandleFunc("/api/review/rerun", s.handleRerunReview)
+	mux.HandleFunc("/api/job/requeue", s.handleRequeueJob)
 	mux.HandleFunc("/api/job/update-branch", s.handleUpdateJobBranch)
 	mux.HandleFunc("/api/repos", s.handleListRepos)
 	mux.HandleFunc("/api/repos/register", s.handleRegisterRepo)
 	mux.HandleFunc("/api/branches", s.handleListBranches)
 	mux.HandleFunc("/api/review", s.handleGetReview)
 	mux.HandleFunc("/api/review/address", s.handleAddressReview)
+	mux.HandleFunc("/api/review/seen", s.handleMarkReviewSeen)
+	mux.HandleFunc("/api/review/finding", s.handleResolveFinding)
+	mux.HandleFunc("/api/review/comparison", s.handleReviewComparison)
+	mux.HandleFunc("/api/review/{id}/export", s.handleExportReview)
 	mux.HandleFunc("/api/comment", s.handleAddComment)
+	mux.HandleFunc("/api/comment/update", s.handleUpdateComment)
+	mux.HandleFunc("/api/comment/delete", s.handleDeleteComment)
 	mux.HandleFunc("/api/comments", s.handleListComments)
 	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/stats", s.handleStats)
 	mux.HandleFunc("/api/stream/events", s.handleStreamEvents)
+	mux.HandleFunc("/api/events", s.handleEvents)
 	mux.HandleFunc("/api/jobs/batch", s.handleBatchJobs)
+	mux.HandleFunc("/api/jobs/retry-failed", s.handleRetryFailedJobs)
+	mux.HandleFunc("/api/jobs/recover", s.handleRecoverJobs)
+	mux.HandleFunc("/api/pause", s.handlePauseQueue)
+	mux.HandleFunc("/api/resume", s.handleResumeQueue)
+	mux.HandleFunc("/api/queue/resume", s.handleResumeQueue)
+	mux.HandleFunc("/api/workers", s.handleListWorkers)
+	mux.HandleFunc("/api/workers/resize", s.handleResizeWorkers)
 	mux.HandleFunc("/api/remap", s.handleRemap)
 	mux.HandleFunc("/api/sync/now", s.handleSyncNow)
 	mux.HandleFunc("/api/sync/status", s.handleSyncStatus)
@@ -105,6 +132,7 @@ func NewServer(db *storage.DB, cfg *config.Config, configPath string) *Server {
 	mux.HandleFunc("/api/job/applied", s.handleMarkJobApplied)
 	mux.HandleFunc("/api/job/rebased", s.handleMarkJobRebased)
 	mux.HandleFunc("/api/activity", s.handleActivity)
+	mux.HandleFunc("/api/db/vacuum", s.handleVacuum)
 
 	s.httpServer = &http.Server{
 		Addr:    cfg.ServerAddr,
@@ -318,12 +346,12 @@ func (s *Server) handleBatchJobs(w http.ResponseWriter, r *http.Request) {
 // handleSyncNow triggers an immediate sync cycle
 func (s *Server) handleSyncNow(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
 	if s.syncWorker == nil {
-		http.Error(w, "Sync not enabled", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, "sync not enabled")
 		return
 	}
 
@@ -336,7 +364,7 @@ func (s *Server) handleSyncNow(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-Content-Type-Options", "nosniff")
 		flusher, ok := w.(http.Flusher)
 		if !ok {
-			http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, "streaming not supported")
 			return
 		}
 
@@ -391,7 +419,7 @@ func (s *Server) handleSyncNow(w http.ResponseWriter, r *http.Request) {
 	// Non-streaming: wait for completion
 	stats, err := s.syncWorker.SyncNow()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -414,7 +442,7 @@ func (s *Server) handleSyncNow(w http.ResponseWriter, r *http.Request) {
 // handleSyncStatus returns the current sync worker health status
 func (s *Server) handleSyncStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -437,26 +465,110 @@ func (s *Server) handleSyncStatus(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleVacuum compacts the database and refreshes planner statistics.
+// Job claims are paused for the duration so workers don't start new jobs
+// while the database file is being rewritten.
+func (s *Server) handleVacuum(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	resume := s.workerPool.PauseClaims()
+	result, err := s.db.Vacuum()
+	resume()
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("vacuum: %v", err))
+		return
+	}
+
+	if s.activityLog != nil {
+		s.activityLog.Log(
+			"db.vacuumed", "server",
+			fmt.Sprintf("database vacuumed (%d -> %d bytes)", result.SizeBefore, result.SizeAfter),
+			map[string]string{
+				"size_before": strconv.FormatInt(result.SizeBefore, 10),
+				"size_after":  strconv.FormatInt(result.SizeAfter, 10),
+			},
+		)
+	}
+
+	writeJSON(w, result)
+}
+
 // API request/response types
 
 type EnqueueRequest struct {
 	RepoPath     string `json:"repo_path"`
 	CommitSHA    string `json:"commit_sha,omitempty"` // Single commit (for backwards compat)
-	GitRef       string `json:"git_ref,omitempty"`    // Single commit, range like "abc..def", or "dirty"
+	GitRef       string `json:"git_ref,omitempty"`    // Single commit, range like "abc..def", "dirty", or "stash@{N}"
 	Branch       string `json:"branch,omitempty"`     // Branch name at time of job creation
 	Agent        string `json:"agent,omitempty"`
 	Model        string `json:"model,omitempty"`         // Model to use (for opencode: provider/model format)
-	DiffContent  string `json:"diff_content,omitempty"`  // Pre-captured diff for dirty reviews
+	DiffContent  string `json:"diff_content,omitempty"`  // Pre-captured diff for dirty reviews, a filtered range, or an edited commit
 	Reasoning    string `json:"reasoning,omitempty"`     // Reasoning level: thorough, standard, fast
 	ReviewType   string `json:"review_type,omitempty"`   // Review type (e.g., "security") — changes system prompt
 	CustomPrompt string `json:"custom_prompt,omitempty"` // Custom prompt for ad-hoc agent work
 	Agentic      bool   `json:"agentic,omitempty"`       // Enable agentic mode (allow file edits)
 	OutputPrefix string `json:"output_prefix,omitempty"` // Prefix to prepend to review output
 	JobType      string `json:"job_type,omitempty"`      // Explicit job type (review/range/dirty/task/compact)
+	RangeLabel   string `json:"range_label,omitempty"`   // Range as the user wrote it (e.g. "main..HEAD"), for a range or one of its commits
 }
 
+// ErrorCode is a machine-readable error category. Clients should branch on
+// the code rather than on the message text, which may change.
+type ErrorCode string
+
+const (
+	ErrCodeInvalidArgument  ErrorCode = "invalid_argument"
+	ErrCodeNotFound         ErrorCode = "not_found"
+	ErrCodeJobNotFound      ErrorCode = "job_not_found"
+	ErrCodeConflict         ErrorCode = "conflict"
+	ErrCodeForbidden        ErrorCode = "forbidden"
+	ErrCodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	ErrCodeTooLarge         ErrorCode = "too_large"
+	ErrCodeRateLimited      ErrorCode = "rate_limited"
+	ErrCodeUnavailable      ErrorCode = "unavailable"
+	ErrCodeInternal         ErrorCode = "internal"
+)
+
+// ErrorResponse is the body of every non-2xx JSON response:
+//
+//	{"error": {"code": "job_not_found", "message": "job not found", "details": {...}}}
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failed request. Details carries optional
+// structured context such as the offending job ID.
+type ErrorDetail struct {
+	Code    ErrorCode      `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// errorCodeForStatus returns the default code for an HTTP error status.
+func errorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrCodeInvalidArgument
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusForbidden:
+		return ErrCodeForbidden
+	case http.StatusMethodNotAllowed:
+		return ErrCodeMethodNotAllowed
+	case http.StatusRequestEntityTooLarge:
+		return ErrCodeTooLarge
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrCodeUnavailable
+	default:
+		return ErrCodeInternal
+	}
 }
 
 func writeJSON(w http.ResponseWriter, v any) {
@@ -475,8 +587,19 @@ func writeJSONWithStatus(w http.ResponseWriter, status int, v any) {
 	}
 }
 
+// writeError writes an error response with the default code for status.
 func writeError(w http.ResponseWriter, status int, msg string) {
-	writeJSONWithStatus(w, status, ErrorResponse{Error: msg})
+	writeErrorCode(w, status, errorCodeForStatus(status), msg, nil)
+}
+
+// writeErrorCode writes an error response with an explicit code and
+// optional details.
+func writeErrorCode(w http.ResponseWriter, status int, code ErrorCode, msg string, details map[string]any) {
+	writeJSONWithStatus(w, status, ErrorResponse{Error: ErrorDetail{
+		Code:    code,
+		Message: msg,
+		Details: details,
+	}})
 }
 
 // writeInternalError writes an internal error response and logs it
@@ -579,13 +702,6 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Resolve reasoning level first (needed for agent/model resolution)
-	reasoning, err := config.ResolveReviewReasoning(req.Reasoning, repoRoot)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
 	// Map review_type to config workflow for agent/model resolution.
 	// "default" uses the standard "review" workflow; others use their own name.
 	workflow := "review"
@@ -593,27 +709,48 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 		workflow = req.ReviewType
 	}
 
-	// Resolve agent for workflow at this reasoning level
-	agentName := config.ResolveAgentForWorkflow(req.Agent, repoRoot, s.configWatcher.Config(), workflow, reasoning)
+	// Resolve agent, model, and reasoning: request > repo config > global config
+	repoCfg, _ := config.LoadRepoConfig(repoRoot)
+	agentName, model, reasoning, err := config.ResolveReviewParams(s.configWatcher.Config(), repoCfg, config.ReviewParamsOptions{
+		Agent:     req.Agent,
+		Model:     req.Model,
+		Reasoning: req.Reasoning,
+		Workflow:  workflow,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Surface a broken review.prompt_template or [prompt] template now
+	// rather than when the worker builds the prompt
+	if config.IsDefaultReviewType(req.ReviewType) && req.CustomPrompt == "" {
+		if _, err := prompt.LoadReviewTemplate(repoRoot); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	// Resolve to an installed agent: if the configured agent isn't available,
 	// fall back through the chain (codex -> claude-code -> gemini -> ...).
 	// Fail fast with 503 if nothing is installed at all.
-	if resolved, err := agent.GetAvailable(agentName); err != nil {
+	requested := agentName
+	agentName, err = resolveJobAgent(s.configWatcher.Config(), agentName, req.Agent != "")
+	if err != nil {
+		var invalid *invalidAgentError
+		if errors.As(err, &invalid) {
+			writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, err.Error(), map[string]any{"agent": requested})
+			return
+		}
 		writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("no review agent available: %v", err))
 		return
-	} else {
-		agentName = resolved.Name()
 	}
 
-	// Resolve model for workflow at this reasoning level
-	model := config.ResolveModelForWorkflow(req.Model, repoRoot, s.configWatcher.Config(), workflow, reasoning)
-
 	// Check if this is a custom prompt, dirty review, range, or single commit
 	// Note: isPrompt is determined by whether custom_prompt is provided, not git_ref value
 	// This allows reviewing a branch literally named "prompt" without collision
 	isPrompt := req.CustomPrompt != ""
-	isDirty := !isPrompt && gitRef == "dirty"
+	isDirty := !isPrompt && (gitRef == "dirty" || git.IsStashRef(gitRef))
 	isRange := !isPrompt && !isDirty && strings.Contains(gitRef, "..")
 
 	// Validate dirty review has diff content
@@ -622,9 +759,9 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Server-side size validation for dirty diffs (200KB max)
+	// Server-side size validation for pre-captured diffs (200KB max)
 	const maxDiffSize = 200 * 1024
-	if isDirty && len(req.DiffContent) > maxDiffSize {
+	if len(req.DiffContent) > maxDiffSize {
 		writeError(w, http.StatusBadRequest, fmt.Sprintf("diff_content too large (%d bytes, max %d)", len(req.DiffContent), maxDiffSize))
 		return
 	}
@@ -660,6 +797,7 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 			Reasoning:   reasoning,
 			ReviewType:  req.ReviewType,
 			DiffContent: req.DiffContent,
+			JobType:     storage.JobTypeDirty,
 		})
 		if err != nil {
 			writeError(w, http.StatusInternalServerError, fmt.Sprintf("enqueue dirty job: %v", err))
@@ -669,7 +807,15 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 		// For ranges, resolve both endpoints and create range job
 		// Use gitCwd to resolve refs correctly in worktree context
 		parts := strings.SplitN(gitRef, "..", 2)
-		startSHA, err := git.ResolveSHA(gitCwd, parts[0])
+		var startSHA string
+		var err error
+		if end, ok := strings.CutPrefix(parts[1], "."); ok {
+			// Symmetric "a...b" range: review b's changes since the merge-base
+			parts[1] = end
+			startSHA, _, err = git.ResolveRange(gitCwd, gitRef)
+		} else {
+			startSHA, err = git.ResolveSHA(gitCwd, parts[0])
+		}
 		if err != nil {
 			// If the start ref is <sha>^ and resolution failed, the commit
 			// may be the root commit (no parent). Use the empty tree SHA so
@@ -692,16 +838,41 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// Store as full SHA range
-		fullRef := startSHA + ".." + endSHA
+		// Count the range's commits for display; the root-commit
+		// fallback has no start commit to exclude
+		countRef := startSHA + ".." + endSHA
+		if startSHA == git.EmptyTreeSHA {
+			countRef = endSHA
+		}
+		commits, _ := git.GetRangeCommits(gitCwd, countRef)
+
+		// Hash the diff under review so a finished review of an identical
+		// diff can be reused by the worker
+		var diffHash string
+		if s.reviewDedup(repoRoot) {
+			diff := req.DiffContent
+			if diff == "" {
+				diff, _ = git.GetRangeDiff(gitCwd, startSHA+".."+endSHA)
+			}
+			diffHash = storage.HashDiff(diff, prompt.CacheSettings(repoRoot))
+		}
+
+		// Store as full SHA range. A diff sent with a range holds the
+		// patches of a subset of its commits (e.g. one author's).
 		job, err = s.db.EnqueueJob(storage.EnqueueOpts{
-			RepoID:     repo.ID,
-			GitRef:     fullRef,
-			Branch:     req.Branch,
-			Agent:      agentName,
-			Model:      model,
-			Reasoning:  reasoning,
-			ReviewType: req.ReviewType,
+			RepoID:       repo.ID,
+			GitRef:       endSHA,
+			RangeBase:    startSHA,
+			Branch:       req.Branch,
+			Agent:        agentName,
+			Model:        model,
+			Reasoning:    reasoning,
+			ReviewType:   req.ReviewType,
+			DiffContent:  req.DiffContent,
+			JobType:      storage.JobTypeRange,
+			RangeLabel:   req.RangeLabel,
+			RangeCommits: len(commits),
+			DiffHash:     diffHash,
 		})
 		if err != nil {
 			writeError(w, http.StatusInternalServerError, fmt.Sprintf("enqueue job: %v", err))
@@ -729,24 +900,65 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		patchID := git.GetPatchID(gitCwd, sha)
+		if req.DiffContent != "" {
+			// A diff sent with a commit (e.g. a hand-edited version of it)
+			// is reviewed in place of the commit's own changes. Store it as
+			// a dirty job linked to the commit, so it isn't taken for a
+			// review of the commit itself.
+			job, err = s.db.EnqueueJob(storage.EnqueueOpts{
+				RepoID:      repo.ID,
+				CommitID:    commit.ID,
+				GitRef:      "dirty",
+				Branch:      req.Branch,
+				Agent:       agentName,
+				Model:       model,
+				Reasoning:   reasoning,
+				ReviewType:  req.ReviewType,
+				DiffContent: req.DiffContent,
+				JobType:     storage.JobTypeDirty,
+			})
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, fmt.Sprintf("enqueue dirty job: %v", err))
+				return
+			}
+			job.CommitSubject = commit.Subject
+		} else {
+			patchID := git.GetPatchID(gitCwd, sha)
+			var diffHash string
+			if s.reviewDedup(repoRoot) {
+				diff, _ := git.GetDiff(gitCwd, sha)
+				diffHash = storage.HashDiff(diff, prompt.CacheSettings(repoRoot))
+			}
+
+			job, err = s.db.EnqueueJob(storage.EnqueueOpts{
+				RepoID:     repo.ID,
+				CommitID:   commit.ID,
+				GitRef:     sha,
+				Branch:     req.Branch,
+				Agent:      agentName,
+				Model:      model,
+				Reasoning:  reasoning,
+				ReviewType: req.ReviewType,
+				PatchID:    patchID,
+				DiffHash:   diffHash,
+				RangeLabel: req.RangeLabel,
+				JobType:    storage.JobTypeReview,
+			})
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, fmt.Sprintf("enqueue job: %v", err))
+				return
+			}
+			job.CommitSubject = commit.Subject
+		}
 
-		job, err = s.db.EnqueueJob(storage.EnqueueOpts{
-			RepoID:     repo.ID,
-			CommitID:   commit.ID,
-			GitRef:     sha,
-			Branch:     req.Branch,
-			Agent:      agentName,
-			Model:      model,
-			Reasoning:  reasoning,
-			ReviewType: req.ReviewType,
-			PatchID:    patchID,
-		})
-		if err != nil {
-			writeError(w, http.StatusInternalServerError, fmt.Sprintf("enqueue job: %v", err))
-			return
+		// A revert makes the reverted commit's reviews moot
+		if reverted := git.RevertedCommit(info.Body); reverted != "" {
+			if n, err := s.db.MarkReviewsReverted(repo.ID, reverted, sha); err != nil {
+				log.Printf("Mark reviews of %s reverted by %s: %v", git.ShortSHA(reverted), git.ShortSHA(sha), err)
+			} else if n > 0 {
+				log.Printf("Resolved %d review(s) of %s, reverted by %s", n, git.ShortSHA(reverted), git.ShortSHA(sha))
+			}
 		}
-		job.CommitSubject = commit.Subject
 	}
 
 	// Fill in joined fields
@@ -771,6 +983,62 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 	writeCreatedJSON(w, job)
 }
 
+// invalidAgentError reports an agent that is not defined, or a custom
+// agent whose command is not installed.
+type invalidAgentError struct {
+	err error
+}
+
+func (e *invalidAgentError) Error() string { return e.err.Error() }
+func (e *invalidAgentError) Unwrap() error { return e.err }
+
+// resolveJobAgent returns the installed agent a new job should run with.
+// A named agent must be defined, and a custom agent must also be installed.
+// When the client asked for name explicitly, either problem is an
+// *invalidAgentError: silently swapping in a built-in agent would hide a
+// typo in its [agents.<name>] command. A name that came from config instead
+// falls back to default_agent with a logged warning, so a bad .roborev.toml
+// doesn't fail post-commit hooks, fix jobs or CI reviews. Agents that are
+// merely not installed fall back through agent.GetAvailable as before.
+func resolveJobAgent(cfg *config.Config, name string, explicit bool) (string, error) {
+	if err := checkJobAgent(name); err != nil {
+		if explicit {
+			return "", &invalidAgentError{err: err}
+		}
+		fallback := ""
+		if cfg != nil && cfg.DefaultAgent != name && checkJobAgent(cfg.DefaultAgent) == nil {
+			fallback = cfg.DefaultAgent
+		}
+		log.Printf("Warning: configured agent %q is unusable (%v); using the default agent instead", name, err)
+		name = fallback
+	}
+	a, err := agent.GetAvailable(name)
+	if err != nil {
+		return "", err
+	}
+	return a.Name(), nil
+}
+
+// checkJobAgent is the validation resolveJobAgent applies to a named agent.
+func checkJobAgent(name string) error {
+	if name == "" {
+		return nil
+	}
+	if err := agent.ValidateName(name); err != nil {
+		return fmt.Errorf("%w; custom agents are defined in an [agents.%s] section of config.toml", err, name)
+	}
+	if agent.IsCustomAgent(name) {
+		return agent.CheckAgentAvailable(name)
+	}
+	return nil
+}
+
+// reviewDedup reports whether jobs enqueued for repoRoot store a diff hash,
+// letting the worker reuse a finished review of an identical diff.
+func (s *Server) reviewDedup(repoRoot string) bool {
+	return config.ResolveReviewDedup(repoRoot, s.configWatcher.Config())
+}
+
 func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -806,6 +1074,10 @@ func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	status := r.URL.Query().Get("status")
+	if status != "" && !storage.JobStatus(status).IsValid() {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, fmt.Sprintf("unknown status %q", status), map[string]any{"status": status})
+		return
+	}
 	repo := r.URL.Query().Get("repo")
 	gitRef := r.URL.Query().Get("git_ref")
 
@@ -845,6 +1117,18 @@ func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
 
 	var listOpts []storage.ListJobsOption
 	if gitRef != "" {
+		// Expand an abbreviated SHA to the commit it names
+		resolved, err := s.db.ResolveJobSHA(gitRef)
+		if errors.Is(err, storage.ErrAmbiguousSHA) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err == nil {
+			gitRef = resolved
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			s.writeInternalError(w, fmt.Sprintf("resolve git_ref: %v", err))
+			return
+		}
 		listOpts = append(listOpts, storage.WithGitRef(gitRef))
 	}
 	if branch := r.URL.Query().Get("branch"); branch != "" {
@@ -857,12 +1141,39 @@ func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
 	if addrStr := r.URL.Query().Get("addressed"); addrStr == "true" || addrStr == "false" {
 		listOpts = append(listOpts, storage.WithAddressed(addrStr == "true"))
 	}
+	if seenStr := r.URL.Query().Get("seen"); seenStr == "true" || seenStr == "false" {
+		listOpts = append(listOpts, storage.WithSeen(seenStr == "true"))
+	}
+	if verdict := r.URL.Query().Get("verdict"); verdict != "" {
+		if !storage.IsVerdictFilter(verdict) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid verdict %q (want pass, fail, or pending)", verdict))
+			return
+		}
+		listOpts = append(listOpts, storage.WithVerdict(verdict))
+	}
 	if jobType := r.URL.Query().Get("job_type"); jobType != "" {
 		listOpts = append(listOpts, storage.WithJobType(jobType))
 	}
 	if exJobType := r.URL.Query().Get("exclude_job_type"); exJobType != "" {
 		listOpts = append(listOpts, storage.WithExcludeJobType(exJobType))
 	}
+	if agent := r.URL.Query().Get("agent"); agent != "" {
+		listOpts = append(listOpts, storage.WithAgent(agent))
+	}
+	machine := r.URL.Query().Get("machine")
+	localOnly := r.URL.Query().Get("local_only") == "true"
+	if machine != "" && localOnly {
+		writeError(w, http.StatusBadRequest, "machine and local_only cannot be combined")
+		return
+	}
+	if machine != "" {
+		listOpts = append(listOpts, storage.WithMachineID(machine))
+	}
+	if localOnly {
+		if id := s.getMachineID(); id != "" {
+			listOpts = append(listOpts, storage.WithMachineIDOrNone(id))
+		}
+	}
 
 	jobs, err := s.db.ListJobs(status, repo, fetchLimit, offset, listOpts...)
 	if err != nil {
@@ -1015,10 +1326,14 @@ func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Look the job up first so a queued cancel can be announced; running
+	// jobs are announced by their worker once the agent stops.
+	prior, _ := s.db.GetJobByID(req.JobID)
+
 	// Cancel in DB first (marks as canceled)
 	if err := s.db.CancelJob(req.JobID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			writeError(w, http.StatusNotFound, "job not found or not cancellable")
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found or not cancellable", map[string]any{"job_id": req.JobID})
 			return
 		}
 		writeError(w, http.StatusInternalServerError, fmt.Sprintf("cancel job: %v", err))
@@ -1028,9 +1343,87 @@ func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
 	// Also cancel the running worker if job was running (kills subprocess)
 	s.workerPool.CancelJob(req.JobID)
 
+	if prior != nil && prior.Status == storage.JobStatusQueued {
+		s.broadcaster.Broadcast(Event{
+			Type:     "review.canceled",
+			TS:       time.Now(),
+			JobID:    prior.ID,
+			Repo:     prior.RepoPath,
+			RepoName: prior.RepoName,
+			SHA:      prior.GitRef,
+			Agent:    prior.Agent,
+		})
+	}
+
 	writeJSON(w, map[string]any{"success": true})
 }
 
+type CancelJobsBulkRequest struct {
+	RepoID int64             `json:"repo_id,omitempty"` // Limit to this repo (0 = all repos)
+	Status storage.JobStatus `json:"status,omitempty"`  // "queued" or "running" (empty = both)
+}
+
+// handleCancelJobsBulk cancels every queued and/or running job in a repo,
+// with the same guards as handleCancelJob: only queued and running jobs
+// are canceled, running workers are stopped, and queued cancels are
+// announced.
+func (s *Server) handleCancelJobsBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req CancelJobsBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Status != "" && !req.Status.Cancellable() {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "status must be queued or running", map[string]any{"status": req.Status})
+		return
+	}
+
+	canceled, err := s.db.CancelJobsWhere(req.RepoID, req.Status)
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("cancel jobs: %v", err))
+		return
+	}
+	n := len(canceled)
+
+	for _, c := range canceled {
+		// Stop the worker running each claimed job; the worker announces
+		// its own cancel. Queued jobs have no worker to announce them.
+		if c.Claimed {
+			s.workerPool.CancelJob(c.ID)
+			continue
+		}
+		job, err := s.db.GetJobByID(c.ID)
+		if err != nil {
+			log.Printf("Bulk cancel: look up job %d: %v", c.ID, err)
+			continue
+		}
+		s.broadcaster.Broadcast(Event{
+			Type:     "review.canceled",
+			TS:       time.Now(),
+			JobID:    job.ID,
+			Repo:     job.RepoPath,
+			RepoName: job.RepoName,
+			SHA:      job.GitRef,
+			Agent:    job.Agent,
+		})
+	}
+
+	if n > 0 && s.activityLog != nil {
+		s.activityLog.Log(
+			"jobs.canceled", "server",
+			fmt.Sprintf("canceled %d job(s)", n),
+			map[string]string{"count": strconv.Itoa(n), "repo_id": strconv.FormatInt(req.RepoID, 10), "status": string(req.Status)},
+		)
+	}
+
+	writeJSON(w, map[string]int{"canceled": n})
+}
+
 // JobOutputResponse is the response for /api/job/output
 type JobOutputResponse struct {
 	JobID   int64        `json:"job_id"`
@@ -1060,7 +1453,7 @@ func (s *Server) handleJobOutput(w http.ResponseWriter, r *http.Request) {
 	// Check job exists
 	job, err := s.db.GetJobByID(jobID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "job not found")
+		writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": jobID})
 		return
 	}
 
@@ -1159,6 +1552,100 @@ func (s *Server) handleJobOutput(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleJobStream streams a running job's output as Server-Sent Events:
+// an "output" event carrying each new line, flushed as it is appended,
+// then a "complete" event with the job's final status once it finishes.
+// A job that isn't running gets only the "complete" event.
+func (s *Server) handleJobStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	idStr := r.PathValue("id")
+	jobID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || jobID <= 0 {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "invalid job id", map[string]any{"job_id": idStr})
+		return
+	}
+
+	job, err := s.db.GetJobByID(jobID)
+	if err != nil {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": jobID})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	writeEvent := func(name string, v any) bool {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return true
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	complete := func(status storage.JobStatus) {
+		writeEvent("complete", map[string]string{"status": string(status)})
+	}
+
+	if job.Status != storage.JobStatusRunning {
+		complete(job.Status)
+		return
+	}
+
+	// Unsubscribing on return, including when the client disconnects,
+	// keeps the worker from sending to a dead connection. Appends never
+	// block on a slow subscriber either way.
+	initial, ch, cancel := s.workerPool.SubscribeJobOutput(jobID)
+	defer cancel()
+
+	// The job may have finished between the status check and subscribing,
+	// in which case its buffer is already closed and ch never will be.
+	if job, err := s.db.GetJobByID(jobID); err == nil && job.Status != storage.JobStatusRunning {
+		complete(job.Status)
+		return
+	}
+
+	for _, line := range initial {
+		if !writeEvent("output", line) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line, ok := <-ch:
+			if !ok {
+				// Job finished - channel closed, fetch actual status
+				finalStatus := storage.JobStatusDone
+				if finalJob, err := s.db.GetJobByID(jobID); err == nil {
+					finalStatus = finalJob.Status
+				}
+				complete(finalStatus)
+				return
+			}
+			if !writeEvent("output", line) {
+				return
+			}
+		}
+	}
+}
+
 // handleJobLog serves the raw JSONL log file for a job.
 // The TUI and CLI use this to render formatted agent output.
 //
@@ -1200,7 +1687,7 @@ func (s *Server) handleJobLog(
 
 	job, err := s.db.GetJobByID(jobID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "job not found")
+		writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": jobID})
 		return
 	}
 
@@ -1350,7 +1837,7 @@ func (s *Server) handleRerunJob(w http.ResponseWriter, r *http.Request) {
 
 	if err := s.db.ReenqueueJob(req.JobID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			writeError(w, http.StatusNotFound, "job not found or not rerunnable")
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found or not rerunnable", map[string]any{"job_id": req.JobID})
 			return
 		}
 		writeError(w, http.StatusInternalServerError, fmt.Sprintf("rerun job: %v", err))
@@ -1360,16 +1847,19 @@ func (s *Server) handleRerunJob(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]any{"success": true})
 }
 
-func (s *Server) handleUpdateJobBranch(w http.ResponseWriter, r *http.Request) {
+type RetryJobRequest struct {
+	JobID int64 `json:"job_id"`
+}
+
+// handleRetryJob enqueues a new job copying a finished one, leaving the
+// original and its review in place.
+func (s *Server) handleRetryJob(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	var req struct {
-		JobID  int64  `json:"job_id"`
-		Branch string `json:"branch"`
-	}
+	var req RetryJobRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
@@ -1379,37 +1869,359 @@ func (s *Server) handleUpdateJobBranch(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "job_id is required")
 		return
 	}
-	if req.Branch == "" {
-		writeError(w, http.StatusBadRequest, "branch is required")
-		return
-	}
 
-	rowsAffected, err := s.db.UpdateJobBranch(req.JobID, req.Branch)
+	job, err := s.db.EnqueueRetry(req.JobID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("update branch: %v", err))
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": req.JobID})
+		case errors.Is(err, storage.ErrJobNotRetryable):
+			writeErrorCode(w, http.StatusConflict, ErrCodeConflict, err.Error(), map[string]any{"job_id": req.JobID})
+		default:
+			s.writeInternalError(w, fmt.Sprintf("retry job: %v", err))
+		}
 		return
 	}
 
-	writeJSON(w, map[string]any{
-		"success": true,
-		"updated": rowsAffected > 0,
-	})
+	writeCreatedJSON(w, job)
 }
 
-func (s *Server) handleGetReview(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+// RerunReviewRequest is the request body for POST /api/review/rerun.
+type RerunReviewRequest struct {
+	JobID int64 `json:"job_id"`
+}
+
+// handleRerunReview enqueues a new review of a finished review job's ref,
+// linked to it so the two verdicts can be compared.
+func (s *Server) handleRerunReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	var review *storage.Review
-	var err error
+	var req RerunReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.JobID == 0 {
+		writeError(w, http.StatusBadRequest, "job_id is required")
+		return
+	}
 
-	// Support lookup by job_id (preferred) or sha
-	if jobIDStr := r.URL.Query().Get("job_id"); jobIDStr != "" {
-		jobID, parseErr := strconv.ParseInt(jobIDStr, 10, 64)
-		if parseErr != nil {
-			writeError(w, http.StatusBadRequest, "invalid job_id")
+	job, err := s.db.RerunReview(req.JobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": req.JobID})
+		case errors.Is(err, storage.ErrJobNotRetryable):
+			writeErrorCode(w, http.StatusConflict, ErrCodeConflict, err.Error(), map[string]any{"job_id": req.JobID})
+		case errors.Is(err, storage.ErrNotReviewJob):
+			writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, err.Error(), map[string]any{"job_id": req.JobID})
+		default:
+			s.writeInternalError(w, fmt.Sprintf("rerun review: %v", err))
+		}
+		return
+	}
+
+	writeCreatedJSON(w, job)
+}
+
+type RequeueJobRequest struct {
+	JobID int64 `json:"job_id"`
+}
+
+// handleRequeueJob resets a failed or canceled job back to queued in place.
+func (s *Server) handleRequeueJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req RequeueJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.JobID == 0 {
+		writeError(w, http.StatusBadRequest, "job_id is required")
+		return
+	}
+
+	job, err := s.db.RequeueJob(req.JobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": req.JobID})
+		case errors.Is(err, storage.ErrJobNotRequeueable):
+			writeErrorCode(w, http.StatusConflict, ErrCodeConflict, err.Error(), map[string]any{"job_id": req.JobID})
+		default:
+			s.writeInternalError(w, fmt.Sprintf("requeue job: %v", err))
+		}
+		return
+	}
+
+	writeJSON(w, job)
+}
+
+type RetryFailedRequest struct {
+	RepoPath string     `json:"repo_path,omitempty"` // Limit to this repo root (empty = all repos)
+	Since    *time.Time `json:"since,omitempty"`     // Limit to jobs that failed at or after this time
+}
+
+// handleRetryFailedJobs requeues every failed job matching the request that
+// has not exhausted its retries.
+func (s *Server) handleRetryFailedJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req RetryFailedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	opts := storage.RequeueFailedOptions{RepoPath: req.RepoPath, MaxRetries: max(s.configWatcher.Config().MaxRetries, 0)}
+	if req.Since != nil {
+		opts.Since = *req.Since
+	}
+	n, err := s.db.RequeueAllFailed(opts)
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("retry failed jobs: %v", err))
+		return
+	}
+
+	if n > 0 && s.activityLog != nil {
+		s.activityLog.Log(
+			"jobs.retried", "server",
+			fmt.Sprintf("requeued %d failed job(s)", n),
+			map[string]string{"count": strconv.Itoa(n), "repo": req.RepoPath},
+		)
+	}
+
+	writeJSON(w, map[string]int{"requeued": n})
+}
+
+type RecoverJobsRequest struct {
+	Fail bool `json:"fail,omitempty"` // Mark stuck jobs failed instead of requeuing them
+}
+
+type RecoverJobsResponse struct {
+	Recovered int    `json:"recovered"` // Stuck jobs requeued or failed
+	Active    int    `json:"active"`    // Running jobs owned by a live worker (left alone)
+	Action    string `json:"action"`    // "requeued" or "failed"
+}
+
+// handleRecoverJobs resets jobs stuck in running with no worker processing
+// them. The startup sweep covers daemon restarts; this is the manual escape
+// hatch for jobs orphaned while the daemon stayed up.
+func (s *Server) handleRecoverJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req RecoverJobsRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	recovered, active, err := s.workerPool.RecoverStuckJobs(req.Fail)
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("recover jobs: %v", err))
+		return
+	}
+
+	resp := RecoverJobsResponse{Recovered: recovered, Active: active, Action: "requeued"}
+	if req.Fail {
+		resp.Action = "failed"
+	}
+
+	if recovered > 0 && s.activityLog != nil {
+		s.activityLog.Log(
+			"jobs.recovered", "server",
+			fmt.Sprintf("%s %d stuck job(s)", resp.Action, recovered),
+			map[string]string{"count": strconv.Itoa(recovered), "action": resp.Action},
+		)
+	}
+
+	writeJSON(w, resp)
+}
+
+type PauseQueueResponse struct {
+	Paused bool `json:"paused"` // False if the queue was already paused
+}
+
+// handlePauseQueue stops workers from starting new jobs until the queue
+// is resumed. Running jobs finish; queued jobs wait.
+func (s *Server) handlePauseQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	paused, err := s.workerPool.Pause()
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("pause queue: %v", err))
+		return
+	}
+	if paused && s.activityLog != nil {
+		s.activityLog.Log("queue.paused", "server", "queue paused", nil)
+	}
+	writeJSON(w, PauseQueueResponse{Paused: paused})
+}
+
+type ResumeQueueResponse struct {
+	Resumed bool `json:"resumed"` // False if the queue wasn't paused
+}
+
+// handleResumeQueue lets workers claim jobs again after the user paused
+// the queue or an agent authentication failure did (pause_on_auth_failure).
+func (s *Server) handleResumeQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	resumed, err := s.workerPool.Resume()
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("resume queue: %v", err))
+		return
+	}
+	if resumed && s.activityLog != nil {
+		s.activityLog.Log("queue.resumed", "server", "queue resumed", nil)
+	}
+	writeJSON(w, ResumeQueueResponse{Resumed: resumed})
+}
+
+type WorkersResponse struct {
+	MaxWorkers    int            `json:"max_workers"`
+	ActiveWorkers int            `json:"active_workers"`
+	Queued        int            `json:"queued"`
+	Running       int            `json:"running"`
+	Workers       []WorkerStatus `json:"workers"`
+}
+
+// handleListWorkers reports what each worker is doing, from the worker
+// pool's own bookkeeping, plus the queue depth.
+func (s *Server) handleListWorkers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	queued, running, err := s.db.GetQueueDepth()
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("get queue depth: %v", err))
+		return
+	}
+	writeJSON(w, WorkersResponse{
+		MaxWorkers:    s.workerPool.MaxWorkers(),
+		ActiveWorkers: s.workerPool.ActiveWorkers(),
+		Queued:        queued,
+		Running:       running,
+		Workers:       s.workerPool.WorkerStatuses(),
+	})
+}
+
+type ResizeWorkersRequest struct {
+	Workers int `json:"workers"`
+}
+
+type ResizeWorkersResponse struct {
+	Workers  int `json:"workers"`
+	Previous int `json:"previous"`
+}
+
+// handleResizeWorkers changes the worker count without a restart. Retired
+// workers finish their current job first.
+func (s *Server) handleResizeWorkers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req ResizeWorkersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Workers < MinWorkers || req.Workers > MaxWorkers {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("workers must be between %d and %d", MinWorkers, MaxWorkers))
+		return
+	}
+
+	prev, err := s.workerPool.Resize(req.Workers)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if prev != req.Workers && s.activityLog != nil {
+		s.activityLog.Log(
+			"workers.resized", "server",
+			fmt.Sprintf("worker pool resized from %d to %d", prev, req.Workers),
+			map[string]string{"previous": strconv.Itoa(prev), "workers": strconv.Itoa(req.Workers)},
+		)
+	}
+	writeJSON(w, ResizeWorkersResponse{Workers: req.Workers, Previous: prev})
+}
+
+func (s *Server) handleUpdateJobBranch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req struct {
+		JobID  int64  `json:"job_id"`
+		Branch string `json:"branch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.JobID == 0 {
+		writeError(w, http.StatusBadRequest, "job_id is required")
+		return
+	}
+	if req.Branch == "" {
+		writeError(w, http.StatusBadRequest, "branch is required")
+		return
+	}
+
+	rowsAffected, err := s.db.UpdateJobBranch(req.JobID, req.Branch)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("update branch: %v", err))
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"success": true,
+		"updated": rowsAffected > 0,
+	})
+}
+
+func (s *Server) handleGetReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var review *storage.Review
+	var err error
+
+	// Support lookup by job_id (preferred) or sha
+	if jobIDStr := r.URL.Query().Get("job_id"); jobIDStr != "" {
+		jobID, parseErr := strconv.ParseInt(jobIDStr, 10, 64)
+		if parseErr != nil {
+			writeError(w, http.StatusBadRequest, "invalid job_id")
 			return
 		}
 		review, err = s.db.GetReviewByJobID(jobID)
@@ -1420,17 +2232,102 @@ func (s *Server) handleGetReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errors.Is(err, storage.ErrAmbiguousSHA) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusNotFound, "review not found")
 		return
 	}
+	if review.ResolvedFindings, err = s.db.GetResolvedFindings(review.JobID); err != nil {
+		s.writeInternalError(w, fmt.Sprintf("get resolved findings: %v", err))
+		return
+	}
 
 	writeJSON(w, review)
 }
 
+// handleExportReview serves the review for a job rendered by
+// review.ExportReview, with its comments. ?format=json returns the full
+// review plus comments as JSON instead of Markdown.
+func (s *Server) handleExportReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	idStr := r.PathValue("id")
+	jobID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || jobID <= 0 {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "invalid job id", map[string]any{"job_id": idStr})
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "md"
+	}
+	if format != "md" && format != "json" {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "format must be md or json", map[string]any{"format": format})
+		return
+	}
+
+	rv, err := s.db.GetReviewByJobID(jobID)
+	if err != nil {
+		writeErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "review not found", map[string]any{"job_id": jobID})
+		return
+	}
+	comments, err := s.db.GetCommentsForJob(jobID)
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("load comments: %v", err))
+		return
+	}
+
+	if format == "json" {
+		data, err := review.ExportReviewJSON(*rv, comments)
+		if err != nil {
+			s.writeInternalError(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	io.WriteString(w, review.ExportReview(*rv, comments))
+}
+
+// handleReviewComparison returns the reviews in a job's comparison group:
+// sibling reviews of the same ref, usually by different agents.
+func (s *Server) handleReviewComparison(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	jobID, err := strconv.ParseInt(r.URL.Query().Get("job_id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job_id")
+		return
+	}
+
+	reviews, err := s.db.GetComparisonGroup(jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "review not found")
+		return
+	}
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("comparison group: %v", err))
+		return
+	}
+
+	writeJSON(w, map[string]any{"reviews": reviews})
+}
+
 type AddCommentRequest struct {
-	SHA       string `json:"sha,omitempty"`    // Legacy: link to commit by SHA
-	JobID     int64  `json:"job_id,omitempty"` // Preferred: link to job
+	SHA       string `json:"sha,omitempty"`       // Legacy: link to commit by SHA
+	JobID     int64  `json:"job_id,omitempty"`    // Preferred: link to job
+	ParentID  int64  `json:"parent_id,omitempty"` // Reply to this comment (job_id optional, but must match)
 	Commenter string `json:"commenter"`
 	Comment   string `json:"comment"`
 }
@@ -1452,8 +2349,8 @@ func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Must provide either job_id or sha
-	if req.JobID == 0 && req.SHA == "" {
+	// Must provide either job_id or sha, unless replying to a comment
+	if req.JobID == 0 && req.SHA == "" && req.ParentID == 0 {
 		writeError(w, http.StatusBadRequest, "job_id or sha is required")
 		return
 	}
@@ -1461,12 +2358,26 @@ func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
 	var resp *storage.Response
 	var err error
 
-	if req.JobID != 0 {
+	if req.ParentID != 0 {
+		resp, err = s.db.AddReplyToJob(req.JobID, req.ParentID, req.Commenter, req.Comment)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				writeError(w, http.StatusNotFound, "parent comment not found")
+				return
+			}
+			if errors.Is(err, storage.ErrReplyParentMismatch) {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("add reply: %v", err))
+			return
+		}
+	} else if req.JobID != 0 {
 		// Link to job (preferred method)
 		resp, err = s.db.AddCommentToJob(req.JobID, req.Commenter, req.Comment)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				writeError(w, http.StatusNotFound, "job not found")
+				writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": req.JobID})
 				return
 			}
 			writeError(w, http.StatusInternalServerError, fmt.Sprintf("add comment: %v", err))
@@ -1490,6 +2401,77 @@ func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
 	writeCreatedJSON(w, resp)
 }
 
+type UpdateCommentRequest struct {
+	ID        int64  `json:"id"`
+	Commenter string `json:"commenter"` // Must match the comment's original commenter
+	Comment   string `json:"comment"`
+}
+
+func (s *Server) handleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.ID == 0 || req.Commenter == "" || req.Comment == "" {
+		writeError(w, http.StatusBadRequest, "id, commenter and comment are required")
+		return
+	}
+
+	if err := s.db.UpdateComment(req.ID, req.Commenter, req.Comment); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			writeErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "comment not found", map[string]any{"comment_id": req.ID})
+		case errors.Is(err, storage.ErrNotCommentAuthor):
+			writeErrorCode(w, http.StatusForbidden, ErrCodeForbidden, err.Error(), map[string]any{"comment_id": req.ID})
+		default:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("update comment: %v", err))
+		}
+		return
+	}
+
+	writeJSON(w, map[string]any{"success": true})
+}
+
+type DeleteCommentRequest struct {
+	ID int64 `json:"id"`
+}
+
+func (s *Server) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req DeleteCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.ID == 0 {
+		writeError(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	if err := s.db.DeleteComment(req.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			writeErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "comment not found", map[string]any{"comment_id": req.ID})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("delete comment: %v", err))
+		return
+	}
+
+	writeJSON(w, map[string]any{"success": true})
+}
+
 func (s *Server) handleListComments(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -1561,6 +2543,12 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	configReloadCounter := s.configWatcher.ReloadCounter()
 
+	machineIDs, err := s.db.GetMachineIDs()
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("get machine IDs: %v", err))
+		return
+	}
+
 	status := storage.DaemonStatus{
 		Version:             version.Version,
 		QueuedJobs:          queued,
@@ -1573,13 +2561,57 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		ActiveWorkers:       s.workerPool.ActiveWorkers(),
 		MaxWorkers:          s.workerPool.MaxWorkers(),
 		MachineID:           s.getMachineID(),
+		MachineIDs:          machineIDs,
 		ConfigReloadedAt:    configReloadedAt,
 		ConfigReloadCounter: configReloadCounter,
+		QueuePausedReason:   s.workerPool.QueuePauseReason(),
+		QueuePausedByUser:   s.workerPool.PausedByUser(),
+	}
+
+	// Per-repo queue depth is opt-in since it costs an extra join
+	if r.URL.Query().Get("by_repo") == "true" {
+		byRepo, err := s.db.GetQueueDepthByRepo()
+		if err != nil {
+			s.writeInternalError(w, fmt.Sprintf("get queue depth by repo: %v", err))
+			return
+		}
+		status.QueueByRepo = byRepo
 	}
 
 	writeJSON(w, status)
 }
 
+// defaultStatsWindowHours is the /api/stats window when none is given.
+const defaultStatsWindowHours = 24
+
+// handleStats returns queue counts plus average duration and pass rate over
+// the last `window` hours (0 = all time), without loading job rows.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	hours := defaultStatsWindowHours
+	if v := r.URL.Query().Get("window"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument,
+				fmt.Sprintf("window must be a non-negative number of hours, got %q", v),
+				map[string]any{"window": v})
+			return
+		}
+		hours = n
+	}
+
+	counts, err := s.db.JobCounts(time.Duration(hours) * time.Hour)
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("job counts: %v", err))
+		return
+	}
+	writeJSON(w, counts)
+}
+
 type AddressReviewRequest struct {
 	JobID     int64 `json:"job_id"`
 	Addressed bool  `json:"addressed"`
@@ -1614,6 +2646,103 @@ func (s *Server) handleAddressReview(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]any{"success": true})
 }
 
+// ResolveFindingRequest is the request body for POST /api/review/finding.
+type ResolveFindingRequest struct {
+	JobID      int64 `json:"job_id"`
+	FindingIdx int   `json:"finding_idx"`
+	Resolved   bool  `json:"resolved"`
+}
+
+// ResolveFindingResponse lists the job's resolved findings after a change.
+type ResolveFindingResponse struct {
+	ResolvedFindings []int `json:"resolved_findings"`
+}
+
+// handleResolveFinding marks one finding of a job's review as resolved or
+// unresolved.
+func (s *Server) handleResolveFinding(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req ResolveFindingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.JobID <= 0 {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "job_id is required", nil)
+		return
+	}
+
+	if err := s.db.SetFindingResolved(req.JobID, req.FindingIdx, req.Resolved); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			writeError(w, http.StatusNotFound, "review not found for job")
+		case errors.Is(err, storage.ErrNoSuchFinding):
+			writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			s.writeInternalError(w, fmt.Sprintf("set finding resolved: %v", err))
+		}
+		return
+	}
+
+	resolved, err := s.db.GetResolvedFindings(req.JobID)
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("get resolved findings: %v", err))
+		return
+	}
+	if resolved == nil {
+		resolved = []int{}
+	}
+	writeJSON(w, ResolveFindingResponse{ResolvedFindings: resolved})
+}
+
+// MarkReviewSeenRequest is the request body for POST /api/review/seen.
+type MarkReviewSeenRequest struct {
+	JobID  int64  `json:"job_id"`
+	SeenBy string `json:"seen_by"`
+}
+
+// handleMarkReviewSeen records that a human has read a job's review.
+func (s *Server) handleMarkReviewSeen(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req MarkReviewSeenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.JobID <= 0 {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "job_id is required", nil)
+		return
+	}
+	if strings.TrimSpace(req.SeenBy) == "" {
+		writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "seen_by is required", nil)
+		return
+	}
+
+	if err := s.db.MarkReviewSeen(req.JobID, strings.TrimSpace(req.SeenBy)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			writeErrorCode(w, http.StatusNotFound, ErrCodeNotFound, "review not found for job", map[string]any{"job_id": req.JobID})
+			return
+		}
+		s.writeInternalError(w, fmt.Sprintf("mark seen: %v", err))
+		return
+	}
+
+	review, err := s.db.GetReviewByJobID(req.JobID)
+	if err != nil {
+		s.writeInternalError(w, fmt.Sprintf("get review: %v", err))
+		return
+	}
+	writeJSON(w, review)
+}
+
 // RemapRequest is the request body for POST /api/remap.
 type RemapRequest struct {
 	RepoPath string         `json:"repo_path"`
@@ -1770,6 +2899,66 @@ func (s *Server) handleStreamEvents(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleEvents streams job state changes (review.started, review.completed,
+// review.failed, review.canceled, ...) as Server-Sent Events. Optional
+// query parameters: repo limits events to one repo root, job_id to one job.
+// A comment line is sent on connect so clients can tell the stream is live
+// before the first event.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var jobID int64
+	if v := r.URL.Query().Get("job_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			writeErrorCode(w, http.StatusBadRequest, ErrCodeInvalidArgument, "invalid job_id", map[string]any{"job_id": v})
+			return
+		}
+		jobID = id
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	subID, eventCh := s.broadcaster.Subscribe(r.URL.Query().Get("repo"))
+	defer s.broadcaster.Unsubscribe(subID)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-eventCh:
+			if !ok {
+				return
+			}
+			if jobID != 0 && event.JobID != jobID {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -1886,7 +3075,7 @@ func (s *Server) handleFixJob(w http.ResponseWriter, r *http.Request) {
 	// Fetch the parent job — must be a review (not a fix job)
 	parentJob, err := s.db.GetJobByID(req.ParentJobID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "parent job not found")
+		writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "parent job not found", map[string]any{"job_id": req.ParentJobID})
 		return
 	}
 	if parentJob.IsFixJob() {
@@ -1900,7 +3089,7 @@ func (s *Server) handleFixJob(w http.ResponseWriter, r *http.Request) {
 		// Server-side rebase: look up stale patch from DB and build rebase prompt
 		staleJob, err := s.db.GetJobByID(req.StaleJobID)
 		if err != nil {
-			writeError(w, http.StatusNotFound, "stale job not found")
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "stale job not found", map[string]any{"job_id": req.StaleJobID})
 			return
 		}
 		if staleJob.JobType != storage.JobTypeFix {
@@ -1943,12 +3132,10 @@ func (s *Server) handleFixJob(w http.ResponseWriter, r *http.Request) {
 	// Resolve agent for fix workflow
 	cfg := s.configWatcher.Config()
 	reasoning := "standard"
-	agentName := config.ResolveAgentForWorkflow("", parentJob.RepoPath, cfg, "fix", reasoning)
-	if resolved, err := agent.GetAvailable(agentName); err != nil {
+	agentName, err := resolveJobAgent(cfg, config.ResolveAgentForWorkflow("", parentJob.RepoPath, cfg, "fix", reasoning), false)
+	if err != nil {
 		writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("no agent available: %v", err))
 		return
-	} else {
-		agentName = resolved.Name()
 	}
 	model := config.ResolveModelForWorkflow("", parentJob.RepoPath, cfg, "fix", reasoning)
 
@@ -2013,7 +3200,8 @@ func isValidGitRef(ref string) bool {
 	return true
 }
 
-// handleGetPatch returns the stored patch for a completed fix job.
+// handleGetPatch returns the stored patch for a completed fix job, or the
+// patch a review agent suggested inline.
 func (s *Server) handleGetPatch(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -2034,7 +3222,7 @@ func (s *Server) handleGetPatch(w http.ResponseWriter, r *http.Request) {
 
 	job, err := s.db.GetJobByID(jobID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "job not found")
+		writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found", map[string]any{"job_id": jobID})
 		return
 	}
 
@@ -2055,7 +3243,8 @@ func (s *Server) handleMarkJobApplied(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		JobID int64 `json:"job_id"`
+		JobID     int64  `json:"job_id"`
+		CommitSHA string `json:"commit_sha,omitempty"` // Commit the patch was committed as
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body")
@@ -2066,9 +3255,9 @@ func (s *Server) handleMarkJobApplied(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.MarkJobApplied(req.JobID); err != nil {
+	if err := s.db.MarkJobApplied(req.JobID, req.CommitSHA); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			writeError(w, http.StatusNotFound, "job not found or not in done state")
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found or not in done state", map[string]any{"job_id": req.JobID})
 			return
 		}
 		writeError(w, http.StatusInternalServerError, fmt.Sprintf("mark applied: %v", err))
@@ -2098,7 +3287,7 @@ func (s *Server) handleMarkJobRebased(w http.ResponseWriter, r *http.Request) {
 
 	if err := s.db.MarkJobRebased(req.JobID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			writeError(w, http.StatusNotFound, "job not found or not in done state")
+			writeErrorCode(w, http.StatusNotFound, ErrCodeJobNotFound, "job not found or not in done state", map[string]any{"job_id": req.JobID})
 			return
 		}
 		writeError(w, http.StatusInternalServerError, fmt.Sprintf("mark rebased: %v", err))
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,6 +24,7 @@ import (
 	"github.com/roborev-dev/roborev/internal/agent"
 	"github.com/roborev-dev/roborev/internal/config"
 	gitpkg "github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/review"
 	"github.com/roborev-dev/roborev/internal/storage"
 	"github.com/roborev-dev/roborev/internal/testutil"
 )
@@ -720,6 +723,157 @@ func TestHandleStatus(t *testing.T) {
 			t.Errorf("Expected ConfigReloadedAt to be empty initially, got %q", status.ConfigReloadedAt)
 		}
 	})
+
+	t.Run("queue by repo only when requested", func(t *testing.T) {
+		server, db, tmpDir := newTestServer(t)
+		repo, err := db.GetOrCreateRepo(tmpDir)
+		if err != nil {
+			t.Fatalf("GetOrCreateRepo failed: %v", err)
+		}
+		for _, sha := range []string{"abc123", "def456"} {
+			commit, err := db.GetOrCreateCommit(repo.ID, sha, "Author", "Subject", time.Now())
+			if err != nil {
+				t.Fatalf("GetOrCreateCommit failed: %v", err)
+			}
+			if _, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test"}); err != nil {
+				t.Fatalf("EnqueueJob failed: %v", err)
+			}
+		}
+
+		w := httptest.NewRecorder()
+		server.handleStatus(w, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+		var status storage.DaemonStatus
+		testutil.DecodeJSON(t, w, &status)
+		if status.QueuedJobs != 2 {
+			t.Errorf("Expected 2 queued jobs, got %d", status.QueuedJobs)
+		}
+		if status.QueueByRepo != nil {
+			t.Errorf("Expected no queue_by_repo without by_repo, got %+v", status.QueueByRepo)
+		}
+
+		w = httptest.NewRecorder()
+		server.handleStatus(w, httptest.NewRequest(http.MethodGet, "/api/status?by_repo=true", nil))
+		status = storage.DaemonStatus{}
+		testutil.DecodeJSON(t, w, &status)
+		if len(status.QueueByRepo) != 1 {
+			t.Fatalf("Expected 1 repo in queue_by_repo, got %+v", status.QueueByRepo)
+		}
+		if d := status.QueueByRepo[0]; d.RepoID != repo.ID || d.Queued != 2 || d.Running != 0 {
+			t.Errorf("Unexpected queue_by_repo entry: %+v", d)
+		}
+	})
+}
+
+func TestHandleStats(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	enqueue := func(sha string) *storage.ReviewJob {
+		t.Helper()
+		commit, err := db.GetOrCreateCommit(repo.ID, sha, "Author", "Subject", time.Now())
+		if err != nil {
+			t.Fatalf("GetOrCreateCommit failed: %v", err)
+		}
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		return job
+	}
+	claim := func() {
+		t.Helper()
+		if _, err := db.ClaimJob("worker-1"); err != nil {
+			t.Fatalf("ClaimJob failed: %v", err)
+		}
+	}
+	// complete finishes the oldest queued job with output and a duration,
+	// ending finishedAgo before now.
+	complete := func(sha, output string, d, finishedAgo time.Duration) {
+		t.Helper()
+		job := enqueue(sha)
+		claim()
+		if err := db.CompleteJob(job.ID, "test", "prompt", output); err != nil {
+			t.Fatalf("CompleteJob failed: %v", err)
+		}
+		finished := time.Now().UTC().Add(-finishedAgo)
+		if _, err := db.Exec(`UPDATE review_jobs SET started_at = ?, finished_at = ? WHERE id = ?`,
+			finished.Add(-d).Format(time.RFC3339), finished.Format(time.RFC3339), job.ID); err != nil {
+			t.Fatalf("set timestamps: %v", err)
+		}
+	}
+
+	complete("pass", "No issues found.", 60*time.Second, time.Minute)
+	complete("fail", "## Issues\n- High: bug in main.go", 120*time.Second, time.Minute)
+	complete("old", "No issues found.", time.Hour, 48*time.Hour)
+
+	failed := enqueue("failed")
+	claim()
+	if _, err := db.FailJob(failed.ID, "", "boom"); err != nil {
+		t.Fatalf("FailJob failed: %v", err)
+	}
+	canceled := enqueue("canceled")
+	if err := db.CancelJob(canceled.ID); err != nil {
+		t.Fatalf("CancelJob failed: %v", err)
+	}
+	enqueue("running")
+	claim()
+	enqueue("queued")
+
+	get := func(query string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/stats"+query, nil)
+		w := httptest.NewRecorder()
+		server.handleStats(w, req)
+		var body map[string]any
+		if w.Code == http.StatusOK {
+			testutil.DecodeJSON(t, w, &body)
+		}
+		return w.Code, body
+	}
+
+	t.Run("default window", func(t *testing.T) {
+		code, body := get("")
+		if code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		want := map[string]float64{
+			"queued": 1, "running": 1, "done": 2, "failed": 1, "canceled": 1,
+			"avg_duration_seconds": 90, "passed": 1, "rejected": 1, "pass_rate": 50,
+		}
+		for k, v := range want {
+			if body[k] != v {
+				t.Errorf("%s = %v, want %v", k, body[k], v)
+			}
+		}
+	})
+
+	t.Run("all time", func(t *testing.T) {
+		code, body := get("?window=0")
+		if code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if body["done"] != float64(3) || body["passed"] != float64(2) {
+			t.Errorf("expected old job included, got done=%v passed=%v", body["done"], body["passed"])
+		}
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		if code, _ := get("?window=abc"); code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", code)
+		}
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/stats", nil)
+		w := httptest.NewRecorder()
+		server.handleStats(w, req)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405, got %d", w.Code)
+		}
+	})
 }
 
 func TestHandleCancelJob(t *testing.T) {
@@ -779,6 +933,20 @@ func TestHandleCancelJob(t *testing.T) {
 		if w.Code != http.StatusNotFound {
 			t.Errorf("Expected status 404 for nonexistent job, got %d", w.Code)
 		}
+		var resp struct {
+			Error map[string]any `json:"error"`
+		}
+		testutil.DecodeJSON(t, w, &resp)
+		if resp.Error["code"] != string(ErrCodeJobNotFound) {
+			t.Errorf("Expected code %q, got %v", ErrCodeJobNotFound, resp.Error["code"])
+		}
+		if msg, _ := resp.Error["message"].(string); msg == "" {
+			t.Errorf("Expected non-empty message, got %v", resp.Error)
+		}
+		details, _ := resp.Error["details"].(map[string]any)
+		if details["job_id"] != float64(99999) {
+			t.Errorf("Expected details.job_id 99999, got %v", resp.Error["details"])
+		}
 	})
 
 	t.Run("cancel with missing job_id fails", func(t *testing.T) {
@@ -790,6 +958,19 @@ func TestHandleCancelJob(t *testing.T) {
 		if w.Code != http.StatusBadRequest {
 			t.Errorf("Expected status 400 for missing job_id, got %d", w.Code)
 		}
+		var resp struct {
+			Error map[string]any `json:"error"`
+		}
+		testutil.DecodeJSON(t, w, &resp)
+		if resp.Error["code"] != string(ErrCodeInvalidArgument) {
+			t.Errorf("Expected code %q, got %v", ErrCodeInvalidArgument, resp.Error["code"])
+		}
+		if resp.Error["message"] != "job_id is required" {
+			t.Errorf("Expected message 'job_id is required', got %v", resp.Error["message"])
+		}
+		if _, ok := resp.Error["details"]; ok {
+			t.Errorf("Expected no details, got %v", resp.Error["details"])
+		}
 	})
 
 	t.Run("cancel with wrong method fails", func(t *testing.T) {
@@ -836,6 +1017,118 @@ func TestHandleCancelJob(t *testing.T) {
 	})
 }
 
+func TestHandleCancelJobsBulk(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repoA, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "bulk-a"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	repoB, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "bulk-b"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	enqueue := func(repo *storage.Repo, sha string) *storage.ReviewJob {
+		t.Helper()
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		return job
+	}
+	status := func(job *storage.ReviewJob) storage.JobStatus {
+		t.Helper()
+		got, err := db.GetJobByID(job.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID failed: %v", err)
+		}
+		return got.Status
+	}
+
+	running := enqueue(repoA, "bulk-running")
+	if _, err := db.ClaimJob("worker-1"); err != nil {
+		t.Fatalf("ClaimJob failed: %v", err)
+	}
+	queuedA := []*storage.ReviewJob{enqueue(repoA, "bulk-q1"), enqueue(repoA, "bulk-q2")}
+	queuedB := enqueue(repoB, "bulk-other")
+
+	subID, events := server.broadcaster.Subscribe("")
+	defer server.broadcaster.Unsubscribe(subID)
+
+	t.Run("cancels queued jobs in one repo", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/cancel-bulk", CancelJobsBulkRequest{RepoID: repoA.ID, Status: storage.JobStatusQueued})
+		w := httptest.NewRecorder()
+
+		server.handleCancelJobsBulk(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp map[string]int
+		testutil.DecodeJSON(t, w, &resp)
+		if resp["canceled"] != 2 {
+			t.Errorf("canceled = %d, want 2", resp["canceled"])
+		}
+		for _, job := range queuedA {
+			if got := status(job); got != storage.JobStatusCanceled {
+				t.Errorf("job %d status = %s, want canceled", job.ID, got)
+			}
+		}
+		if got := status(running); got != storage.JobStatusRunning {
+			t.Errorf("running job status = %s, want running", got)
+		}
+		if got := status(queuedB); got != storage.JobStatusQueued {
+			t.Errorf("other repo's job status = %s, want queued", got)
+		}
+		for range queuedA {
+			select {
+			case e := <-events:
+				if e.Type != "review.canceled" {
+					t.Errorf("event type = %q, want review.canceled", e.Type)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected a review.canceled event per queued job")
+			}
+		}
+	})
+
+	t.Run("cancels running jobs", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/cancel-bulk", CancelJobsBulkRequest{RepoID: repoA.ID, Status: storage.JobStatusRunning})
+		w := httptest.NewRecorder()
+
+		server.handleCancelJobsBulk(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if got := status(running); got != storage.JobStatusCanceled {
+			t.Errorf("running job status = %s, want canceled", got)
+		}
+	})
+
+	t.Run("rejects a non-cancellable status", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/cancel-bulk", CancelJobsBulkRequest{RepoID: repoB.ID, Status: storage.JobStatusDone})
+		w := httptest.NewRecorder()
+
+		server.handleCancelJobsBulk(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+		if got := status(queuedB); got != storage.JobStatusQueued {
+			t.Errorf("other repo's job status = %s, want queued", got)
+		}
+	})
+
+	t.Run("wrong method fails", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.handleCancelJobsBulk(w, httptest.NewRequest(http.MethodGet, "/api/job/cancel-bulk", nil))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405, got %d", w.Code)
+		}
+	})
+}
+
 func TestListJobsPagination(t *testing.T) {
 	server, db, _ := newTestServer(t)
 
@@ -1057,6 +1350,13 @@ func TestHandleListJobsAddressedFilter(t *testing.T) {
 // startStreamHandler starts the stream handler in a goroutine and waits for subscription.
 // Returns a cancel function, the recorder, and a done channel.
 func startStreamHandler(t *testing.T, server *Server, url string) (context.CancelFunc, *safeRecorder, chan struct{}) {
+	t.Helper()
+	return startSubscribedHandler(t, server, server.handleStreamEvents, url)
+}
+
+// startSubscribedHandler runs a broadcaster-backed handler in a goroutine
+// and waits for it to subscribe.
+func startSubscribedHandler(t *testing.T, server *Server, handler http.HandlerFunc, url string) (context.CancelFunc, *safeRecorder, chan struct{}) {
 	t.Helper()
 	ctx, cancel := context.WithCancel(context.Background())
 	req := httptest.NewRequest(http.MethodGet, url, nil).WithContext(ctx)
@@ -1067,7 +1367,7 @@ func startStreamHandler(t *testing.T, server *Server, url string) (context.Cance
 	// Run handler in goroutine
 	done := make(chan struct{})
 	go func() {
-		server.handleStreamEvents(w, req)
+		handler(w, req)
 		close(done)
 	}()
 
@@ -1080,6 +1380,96 @@ func startStreamHandler(t *testing.T, server *Server, url string) (context.Cance
 	return cancel, w, done
 }
 
+func TestHandleEvents(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	t.Run("sends SSE headers and connect comment", func(t *testing.T) {
+		cancel, w, done := startSubscribedHandler(t, server, server.handleEvents, "/api/events")
+		cancel()
+		<-done
+
+		if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("Expected Content-Type 'text/event-stream', got '%s'", ct)
+		}
+		if body := w.bodyString(); !strings.HasPrefix(body, ": connected\n\n") {
+			t.Errorf("Expected connect comment, got %q", body)
+		}
+	})
+
+	t.Run("wrong method fails", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.handleEvents(w, httptest.NewRequest(http.MethodPost, "/api/events", nil))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405 for POST, got %d", w.Code)
+		}
+	})
+
+	t.Run("invalid job_id fails", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.handleEvents(w, httptest.NewRequest(http.MethodGet, "/api/events?job_id=abc", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("filters by job_id", func(t *testing.T) {
+		cancel, w, done := startSubscribedHandler(t, server, server.handleEvents, "/api/events?job_id=2")
+
+		server.broadcaster.Broadcast(Event{Type: "review.started", TS: time.Now(), JobID: 1})
+		server.broadcaster.Broadcast(Event{Type: "review.completed", TS: time.Now(), JobID: 2, Verdict: "pass"})
+
+		if !waitForEvents(w, 5, time.Second) {
+			cancel()
+			t.Fatal("Timed out waiting for events")
+		}
+		cancel()
+		<-done
+
+		body := w.bodyString()
+		if strings.Contains(body, "review.started") {
+			t.Errorf("Expected job 1 event to be filtered out, got %q", body)
+		}
+		if !strings.Contains(body, "event: review.completed\ndata: {") {
+			t.Errorf("Expected review.completed SSE frame, got %q", body)
+		}
+		if !strings.Contains(body, `"job_id":2`) {
+			t.Errorf("Expected job_id 2 in event data, got %q", body)
+		}
+	})
+
+	t.Run("canceling a queued job is announced", func(t *testing.T) {
+		repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "events-repo"))
+		if err != nil {
+			t.Fatalf("GetOrCreateRepo failed: %v", err)
+		}
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, GitRef: "abc123", Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+
+		cancel, w, done := startSubscribedHandler(t, server, server.handleEvents, fmt.Sprintf("/api/events?job_id=%d", job.ID))
+
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/cancel", CancelJobRequest{JobID: job.ID})
+		rec := httptest.NewRecorder()
+		server.handleCancelJob(rec, req)
+		if rec.Code != http.StatusOK {
+			cancel()
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if !waitForEvents(w, 5, time.Second) {
+			cancel()
+			t.Fatal("Timed out waiting for cancel event")
+		}
+		cancel()
+		<-done
+
+		if body := w.bodyString(); !strings.Contains(body, "event: review.canceled\n") {
+			t.Errorf("Expected review.canceled event, got %q", body)
+		}
+	})
+}
+
 func TestHandleStreamEvents(t *testing.T) {
 	server, _, _ := newTestServer(t)
 
@@ -1588,41 +1978,335 @@ func TestHandleRerunJob(t *testing.T) {
 	})
 }
 
-func TestHandleRegisterRepo(t *testing.T) {
-	t.Run("GET returns 405", func(t *testing.T) {
-		server, _, _ := newTestServer(t)
-		req := httptest.NewRequest(http.MethodGet, "/api/repos/register", nil)
+func TestHandleRetryJob(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+
+	t.Run("retry failed job", func(t *testing.T) {
+		commit, _ := db.GetOrCreateCommit(repo.ID, "retry-failed", "Author", "Subject", time.Now())
+		job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "retry-failed", Agent: "test"})
+		db.ClaimJob("worker-1")
+		db.FailJob(job.ID, "", "some error")
+
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/retry", RetryJobRequest{JobID: job.ID})
 		w := httptest.NewRecorder()
-		server.handleRegisterRepo(w, req)
-		if w.Code != http.StatusMethodNotAllowed {
-			t.Errorf("Expected 405, got %d", w.Code)
+
+		server.handleRetryJob(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var retry storage.ReviewJob
+		testutil.DecodeJSON(t, w, &retry)
+		if retry.ID == job.ID || retry.Status != storage.JobStatusQueued {
+			t.Errorf("Expected a new queued job, got %+v", retry)
+		}
+		if retry.RetryOfJobID == nil || *retry.RetryOfJobID != job.ID {
+			t.Errorf("Expected retry_of_job_id %d, got %v", job.ID, retry.RetryOfJobID)
 		}
-	})
 
-	t.Run("empty body returns 400", func(t *testing.T) {
-		server, _, _ := newTestServer(t)
-		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/repos/register", map[string]string{})
-		w := httptest.NewRecorder()
-		server.handleRegisterRepo(w, req)
-		if w.Code != http.StatusBadRequest {
-			t.Errorf("Expected 400, got %d: %s", w.Code, w.Body.String())
+		original, err := db.GetJobByID(job.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID failed: %v", err)
+		}
+		if original.Status != storage.JobStatusFailed {
+			t.Errorf("Expected original to stay failed, got '%s'", original.Status)
 		}
 	})
 
-	t.Run("non-git path returns 400", func(t *testing.T) {
-		server, _, _ := newTestServer(t)
-		plainDir := t.TempDir()
-		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/repos/register", map[string]string{
-			"repo_path": plainDir,
-		})
+	t.Run("retry queued job fails", func(t *testing.T) {
+		commit, _ := db.GetOrCreateCommit(repo.ID, "retry-queued", "Author", "Subject", time.Now())
+		job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "retry-queued", Agent: "test"})
+
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/retry", RetryJobRequest{JobID: job.ID})
 		w := httptest.NewRecorder()
-		server.handleRegisterRepo(w, req)
-		if w.Code != http.StatusBadRequest {
-			t.Errorf("Expected 400, got %d: %s", w.Code, w.Body.String())
+
+		server.handleRetryJob(w, req)
+
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for queued job, got %d", w.Code)
 		}
 	})
 
-	t.Run("valid git repo returns 200 and persists", func(t *testing.T) {
+	t.Run("retry nonexistent job fails", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/retry", RetryJobRequest{JobID: 99999})
+		w := httptest.NewRecorder()
+
+		server.handleRetryJob(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestHandleRerunReview(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	commit, _ := db.GetOrCreateCommit(repo.ID, "rerun-sha", "Author", "Subject", time.Now())
+	job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "rerun-sha", Agent: "test"})
+
+	rerun := func(jobID int64) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.handleRerunReview(w, testutil.MakeJSONRequest(t, http.MethodPost, "/api/review/rerun", RerunReviewRequest{JobID: jobID}))
+		return w
+	}
+
+	if w := rerun(job.ID); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a queued job, got %d: %s", w.Code, w.Body.String())
+	}
+
+	db.ClaimJob("worker-1")
+	if err := db.CompleteJob(job.ID, "test", "prompt", "output"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+	w := rerun(job.ID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created storage.ReviewJob
+	testutil.DecodeJSON(t, w, &created)
+	if created.SupersedesJobID == nil || *created.SupersedesJobID != job.ID {
+		t.Errorf("Expected supersedes_job_id %d, got %v", job.ID, created.SupersedesJobID)
+	}
+
+	if w := rerun(99999); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleMarkReviewSeen(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	commit, _ := db.GetOrCreateCommit(repo.ID, "seen-sha", "Author", "Subject", time.Now())
+	job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "seen-sha", Agent: "test"})
+	db.ClaimJob("worker-1")
+	if err := db.CompleteJob(job.ID, "test", "prompt", "output"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	t.Run("marks review seen", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/review/seen", MarkReviewSeenRequest{JobID: job.ID, SeenBy: "alice"})
+		w := httptest.NewRecorder()
+		server.handleMarkReviewSeen(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var review storage.Review
+		testutil.DecodeJSON(t, w, &review)
+		if review.SeenBy != "alice" || review.SeenAt == nil {
+			t.Errorf("Expected review seen by alice, got %+v", review)
+		}
+	})
+
+	t.Run("lists unseen reviews", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs?seen=false", nil)
+		w := httptest.NewRecorder()
+		server.handleListJobs(w, req)
+
+		var resp struct {
+			Jobs []storage.ReviewJob `json:"jobs"`
+		}
+		testutil.DecodeJSON(t, w, &resp)
+		if len(resp.Jobs) != 0 {
+			t.Errorf("Expected no unseen reviews, got %d", len(resp.Jobs))
+		}
+	})
+
+	t.Run("missing seen_by fails", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/review/seen", MarkReviewSeenRequest{JobID: job.ID})
+		w := httptest.NewRecorder()
+		server.handleMarkReviewSeen(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("job without review fails", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/review/seen", MarkReviewSeenRequest{JobID: 99999, SeenBy: "alice"})
+		w := httptest.NewRecorder()
+		server.handleMarkReviewSeen(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestHandleResolveFinding(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	commit, _ := db.GetOrCreateCommit(repo.ID, "finding-sha", "Author", "Subject", time.Now())
+	job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "finding-sha", Agent: "test"})
+	db.ClaimJob("worker-1")
+	if err := db.CompleteJob(job.ID, "test", "prompt", "- High: race in cache\n- Low: typo\n"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	resolve := func(req ResolveFindingRequest) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.handleResolveFinding(w, testutil.MakeJSONRequest(t, http.MethodPost, "/api/review/finding", req))
+		return w
+	}
+
+	t.Run("resolves and unresolves", func(t *testing.T) {
+		w := resolve(ResolveFindingRequest{JobID: job.ID, FindingIdx: 1, Resolved: true})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp ResolveFindingResponse
+		testutil.DecodeJSON(t, w, &resp)
+		if !slices.Equal(resp.ResolvedFindings, []int{1}) {
+			t.Errorf("resolved_findings = %v, want [1]", resp.ResolvedFindings)
+		}
+
+		w = httptest.NewRecorder()
+		server.handleGetReview(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/review?job_id=%d", job.ID), nil))
+		var review storage.Review
+		testutil.DecodeJSON(t, w, &review)
+		if !slices.Equal(review.ResolvedFindings, []int{1}) {
+			t.Errorf("review resolved_findings = %v, want [1]", review.ResolvedFindings)
+		}
+
+		w = resolve(ResolveFindingRequest{JobID: job.ID, FindingIdx: 1, Resolved: false})
+		testutil.DecodeJSON(t, w, &resp)
+		if len(resp.ResolvedFindings) != 0 {
+			t.Errorf("resolved_findings = %v, want none", resp.ResolvedFindings)
+		}
+	})
+
+	t.Run("out of range finding fails", func(t *testing.T) {
+		if w := resolve(ResolveFindingRequest{JobID: job.ID, FindingIdx: 5, Resolved: true}); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("job without review fails", func(t *testing.T) {
+		if w := resolve(ResolveFindingRequest{JobID: 99999, FindingIdx: 0, Resolved: true}); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestHandleRequeueJob(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+
+	t.Run("requeue failed job", func(t *testing.T) {
+		commit, _ := db.GetOrCreateCommit(repo.ID, "requeue-failed", "Author", "Subject", time.Now())
+		job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "requeue-failed", Agent: "test"})
+		db.ClaimJob("worker-1")
+		db.FailJob(job.ID, "", "some error")
+
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/requeue", RequeueJobRequest{JobID: job.ID})
+		w := httptest.NewRecorder()
+
+		server.handleRequeueJob(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var requeued storage.ReviewJob
+		testutil.DecodeJSON(t, w, &requeued)
+		if requeued.ID != job.ID || requeued.Status != storage.JobStatusQueued || requeued.Attempts != 1 {
+			t.Errorf("Expected job %d queued with 1 attempt, got %+v", job.ID, requeued)
+		}
+	})
+
+	t.Run("requeue done job fails", func(t *testing.T) {
+		commit, _ := db.GetOrCreateCommit(repo.ID, "requeue-done", "Author", "Subject", time.Now())
+		job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "requeue-done", Agent: "test"})
+		for {
+			claimed, err := db.ClaimJob("worker-1")
+			if err != nil || claimed == nil {
+				t.Fatalf("ClaimJob: %v", err)
+			}
+			db.CompleteJob(claimed.ID, "test", "prompt", "output")
+			if claimed.ID == job.ID {
+				break
+			}
+		}
+
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/requeue", RequeueJobRequest{JobID: job.ID})
+		w := httptest.NewRecorder()
+
+		server.handleRequeueJob(w, req)
+
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for done job, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "is done") {
+			t.Errorf("Expected error to name the job status, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("requeue nonexistent job fails", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/job/requeue", RequeueJobRequest{JobID: 99999})
+		w := httptest.NewRecorder()
+
+		server.handleRequeueJob(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestHandleRegisterRepo(t *testing.T) {
+	t.Run("GET returns 405", func(t *testing.T) {
+		server, _, _ := newTestServer(t)
+		req := httptest.NewRequest(http.MethodGet, "/api/repos/register", nil)
+		w := httptest.NewRecorder()
+		server.handleRegisterRepo(w, req)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected 405, got %d", w.Code)
+		}
+	})
+
+	t.Run("empty body returns 400", func(t *testing.T) {
+		server, _, _ := newTestServer(t)
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/repos/register", map[string]string{})
+		w := httptest.NewRecorder()
+		server.handleRegisterRepo(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("non-git path returns 400", func(t *testing.T) {
+		server, _, _ := newTestServer(t)
+		plainDir := t.TempDir()
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/repos/register", map[string]string{
+			"repo_path": plainDir,
+		})
+		w := httptest.NewRecorder()
+		server.handleRegisterRepo(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("valid git repo returns 200 and persists", func(t *testing.T) {
 		server, db, tmpDir := newTestServer(t)
 		repoDir := filepath.Join(tmpDir, "testrepo")
 		testutil.InitTestGitRepo(t, repoDir)
@@ -1770,6 +2454,52 @@ func TestHandleEnqueueExcludedBranch(t *testing.T) {
 	})
 }
 
+func TestHandleEnqueueReviewTemplateValidation(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repoDir := filepath.Join(tmpDir, "testrepo")
+	testutil.InitTestGitRepo(t, repoDir)
+	if err := os.WriteFile(filepath.Join(repoDir, ".roborev.toml"), []byte("[review]\nprompt_template = \"review.tmpl\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write repo config: %v", err)
+	}
+	enqueue := func(reviewType string) *httptest.ResponseRecorder {
+		reqData := map[string]string{"repo_path": repoDir, "git_ref": "HEAD", "agent": "test", "review_type": reviewType}
+		w := httptest.NewRecorder()
+		server.handleEnqueue(w, testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", reqData))
+		return w
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "review.tmpl"), []byte("Review {{.Subject"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w := enqueue("")
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "review.prompt_template") {
+		t.Fatalf("broken template: got %d: %s", w.Code, w.Body.String())
+	}
+	// Security reviews don't use the template
+	if w := enqueue("security"); w.Code != http.StatusCreated {
+		t.Errorf("security review: got %d: %s", w.Code, w.Body.String())
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "review.tmpl"), []byte("Review {{.Subject}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if w := enqueue(""); w.Code != http.StatusCreated {
+		t.Errorf("valid template: got %d: %s", w.Code, w.Body.String())
+	}
+	if queued, _, _, _, _, _, _, _ := db.GetJobCounts(); queued != 2 {
+		t.Errorf("Expected 2 queued jobs, got %d", queued)
+	}
+
+	// An inline [prompt] template is checked the same way
+	if err := os.WriteFile(filepath.Join(repoDir, ".roborev.toml"), []byte("[prompt]\ntemplate = \"Review {{.Title}}\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if w := enqueue(""); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "prompt.template") {
+		t.Errorf("broken [prompt] template: got %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestHandleEnqueueBranchFallback(t *testing.T) {
 	server, db, tmpDir := newTestServer(t)
 
@@ -1804,8 +2534,136 @@ func TestHandleEnqueueBranchFallback(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetJob: %v", err)
 	}
-	if job.Branch != "my-feature" {
-		t.Errorf("expected branch %q, got %q", "my-feature", job.Branch)
+	if job.Branch != "my-feature" {
+		t.Errorf("expected branch %q, got %q", "my-feature", job.Branch)
+	}
+}
+
+func TestHandleEnqueueRevertResolvesReview(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repoDir := filepath.Join(tmpDir, "testrepo")
+	testutil.InitTestGitRepo(t, repoDir)
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	enqueueHead := func() storage.ReviewJob {
+		t.Helper()
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", map[string]string{
+			"repo_path": repoDir,
+			"git_ref":   "HEAD",
+			"agent":     "test",
+		})
+		w := httptest.NewRecorder()
+		server.handleEnqueue(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var job storage.ReviewJob
+		testutil.DecodeJSON(t, w, &job)
+		return job
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "test.txt"), []byte("broken"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	git("commit", "-am", "break things")
+	original := enqueueHead()
+	if _, err := db.ClaimJob("worker-1"); err != nil {
+		t.Fatalf("ClaimJob: %v", err)
+	}
+	if err := db.CompleteJob(original.ID, "test", "prompt", "**Verdict: FAIL**\n- Broken"); err != nil {
+		t.Fatalf("CompleteJob: %v", err)
+	}
+
+	git("revert", "--no-edit", "HEAD")
+	revertSHA := git("rev-parse", "HEAD")
+	enqueueHead()
+
+	review, err := db.GetReviewByJobID(original.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID: %v", err)
+	}
+	if !review.Addressed {
+		t.Error("expected the reverted commit's review to be addressed")
+	}
+	if review.RevertedBy != revertSHA {
+		t.Errorf("expected RevertedBy %s, got %q", revertSHA, review.RevertedBy)
+	}
+}
+
+func TestHandleEnqueueDiffHash(t *testing.T) {
+	server, _, tmpDir := newTestServer(t)
+
+	repoDir := filepath.Join(tmpDir, "testrepo")
+	testutil.InitTestGitRepo(t, repoDir)
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	enqueueHead := func() storage.ReviewJob {
+		t.Helper()
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", map[string]string{
+			"repo_path": repoDir,
+			"git_ref":   "HEAD",
+			"agent":     "test",
+		})
+		w := httptest.NewRecorder()
+		server.handleEnqueue(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var job storage.ReviewJob
+		testutil.DecodeJSON(t, w, &job)
+		return job
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "test.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	git("commit", "-am", "change things")
+	original := enqueueHead()
+	git("commit", "--amend", "-m", "reword the message only")
+	amended := enqueueHead()
+
+	if original.DiffHash == "" {
+		t.Fatal("expected a diff hash for a commit review")
+	}
+	if amended.GitRef == original.GitRef || amended.DiffHash != original.DiffHash {
+		t.Errorf("amending only the message should keep the diff hash: %s %q vs %s %q",
+			original.GitRef, original.DiffHash, amended.GitRef, amended.DiffHash)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "test.txt"), []byte("changed again"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	git("commit", "--amend", "-am", "reword the message only")
+	changed := enqueueHead()
+	if changed.DiffHash == original.DiffHash {
+		t.Error("a different diff should have a different diff hash")
+	}
+
+	// The review guidelines shape the prompt, so they are part of the hash
+	if err := os.WriteFile(filepath.Join(repoDir, ".roborev.toml"), []byte(`review_guidelines = "Check error handling."`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if guided := enqueueHead(); guided.DiffHash == "" || guided.DiffHash == changed.DiffHash {
+		t.Errorf("new guidelines should change the diff hash: %q vs %q", guided.DiffHash, changed.DiffHash)
+	}
+
+	// With review.dedup off, no diff is hashed
+	if err := os.WriteFile(filepath.Join(repoDir, ".roborev.toml"), []byte("[review]\ndedup = false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if off := enqueueHead(); off.DiffHash != "" {
+		t.Errorf("diff hash = %q with review.dedup off, want none", off.DiffHash)
 	}
 }
 
@@ -1833,10 +2691,10 @@ func TestHandleEnqueueBodySizeLimit(t *testing.T) {
 			t.Errorf("Expected status 413, got %d: %s", w.Code, w.Body.String())
 		}
 
-		var response map[string]any
+		var response ErrorResponse
 		testutil.DecodeJSON(t, w, &response)
 
-		if errMsg, ok := response["error"].(string); !ok || !strings.Contains(errMsg, "too large") {
+		if errMsg := response.Error.Message; !strings.Contains(errMsg, "too large") {
 			t.Errorf("Expected error about body size, got %v", response)
 		}
 	})
@@ -1858,10 +2716,10 @@ func TestHandleEnqueueBodySizeLimit(t *testing.T) {
 			t.Errorf("Expected status 400, got %d: %s", w.Code, w.Body.String())
 		}
 
-		var response map[string]any
+		var response ErrorResponse
 		testutil.DecodeJSON(t, w, &response)
 
-		if errMsg, ok := response["error"].(string); !ok || !strings.Contains(errMsg, "diff_content required") {
+		if errMsg := response.Error.Message; !strings.Contains(errMsg, "diff_content required") {
 			t.Errorf("Expected error about diff_content required, got %v", response)
 		}
 	})
@@ -1886,6 +2744,122 @@ func TestHandleEnqueueBodySizeLimit(t *testing.T) {
 	})
 }
 
+func TestHandleEnqueueDirtyJobType(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repoDir := filepath.Join(tmpDir, "testrepo")
+	testutil.InitTestGitRepo(t, repoDir)
+
+	req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", map[string]string{
+		"repo_path":    repoDir,
+		"git_ref":      "dirty",
+		"agent":        "test",
+		"diff_content": "diff --git a/f.txt b/f.txt\n+uncommitted\n",
+	})
+	w := httptest.NewRecorder()
+	server.handleEnqueue(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var job storage.ReviewJob
+	testutil.DecodeJSON(t, w, &job)
+
+	stored, err := db.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	if stored.JobType != storage.JobTypeDirty {
+		t.Errorf("Expected job_type %q for a dirty review, got %q", storage.JobTypeDirty, stored.JobType)
+	}
+}
+
+func TestHandleEnqueueStash(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repoDir := filepath.Join(tmpDir, "testrepo")
+	testutil.InitTestGitRepo(t, repoDir)
+
+	reqData := map[string]string{
+		"repo_path":    repoDir,
+		"git_ref":      "stash@{1}",
+		"agent":        "test",
+		"diff_content": "diff --git a/f.txt b/f.txt\n+stashed\n",
+	}
+	req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", reqData)
+	w := httptest.NewRecorder()
+	server.handleEnqueue(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var job storage.ReviewJob
+	testutil.DecodeJSON(t, w, &job)
+
+	stored, err := db.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	if stored.GitRef != "stash@{1}" {
+		t.Errorf("Expected git_ref stash@{1}, got %q", stored.GitRef)
+	}
+	if stored.JobType != storage.JobTypeDirty || stored.CommitID != nil {
+		t.Errorf("Expected commit-less dirty job, got type %q commit %v", stored.JobType, stored.CommitID)
+	}
+}
+
+func TestHandleEnqueueCommitWithDiff(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repoDir := filepath.Join(tmpDir, "testrepo")
+	testutil.InitTestGitRepo(t, repoDir)
+	baseSHA, err := gitpkg.ResolveSHA(repoDir, "HEAD")
+	if err != nil {
+		t.Fatalf("ResolveSHA: %v", err)
+	}
+
+	edited := "diff --git a/test.txt b/test.txt\n+edited by hand\n"
+	req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", map[string]string{
+		"repo_path":    repoDir,
+		"git_ref":      baseSHA,
+		"agent":        "test",
+		"diff_content": edited,
+	})
+	w := httptest.NewRecorder()
+	server.handleEnqueue(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var job storage.ReviewJob
+	testutil.DecodeJSON(t, w, &job)
+
+	stored, err := db.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	if stored.JobType != storage.JobTypeDirty || stored.GitRef != "dirty" || stored.CommitID == nil {
+		t.Errorf("Expected a dirty job linked to %s, got type %q ref %q commit %v", baseSHA, stored.JobType, stored.GitRef, stored.CommitID)
+	}
+	commit, err := db.GetCommitBySHA(baseSHA)
+	if err != nil {
+		t.Fatalf("GetCommitBySHA: %v", err)
+	}
+	if stored.CommitID != nil && *stored.CommitID != commit.ID {
+		t.Errorf("Expected commit %d, got %d", commit.ID, *stored.CommitID)
+	}
+	// Lookups of the commit's own reviews (show, wait) don't see it
+	if jobs, err := db.ListJobs("", "", 0, 0, storage.WithGitRef(baseSHA)); err != nil || len(jobs) != 0 {
+		t.Errorf("Expected no jobs for %s, got %d (err %v)", baseSHA, len(jobs), err)
+	}
+	claimed, err := db.ClaimJob("w")
+	if err != nil || claimed == nil {
+		t.Fatalf("ClaimJob: %v", err)
+	}
+	if claimed.DiffContent == nil || *claimed.DiffContent != edited {
+		t.Errorf("Expected the stored diff to be the supplied diff, got %v", claimed.DiffContent)
+	}
+}
+
 func TestHandleListJobsByID(t *testing.T) {
 	server, db, tmpDir := newTestServer(t)
 
@@ -2369,6 +3343,251 @@ func TestHandleAddCommentWithoutReview(t *testing.T) {
 	}
 }
 
+func TestHandleListWorkers(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "test-repo"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	commit, err := db.GetOrCreateCommit(repo.ID, "abc123", "Author", "Test commit", time.Now())
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit failed: %v", err)
+	}
+	if _, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "abc123", Agent: "test"}); err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+
+	// Paused workers stay idle and leave the job queued
+	pool := server.workerPool
+	if _, err := pool.Pause(); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	pool.Start()
+	t.Cleanup(pool.Stop)
+	pool.workersMu.Lock()
+	busy := pool.liveWorkers[0]
+	pool.workersMu.Unlock()
+	pool.setWorkerJob(busy, 42)
+
+	w := httptest.NewRecorder()
+	server.handleListWorkers(w, httptest.NewRequest(http.MethodGet, "/api/workers", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp WorkersResponse
+	testutil.DecodeJSON(t, w, &resp)
+
+	if resp.MaxWorkers != pool.MaxWorkers() || len(resp.Workers) != resp.MaxWorkers {
+		t.Errorf("max_workers=%d with %d workers listed, want %d", resp.MaxWorkers, len(resp.Workers), pool.MaxWorkers())
+	}
+	if resp.Queued != 1 || resp.Running != 0 {
+		t.Errorf("queued=%d running=%d, want 1 and 0", resp.Queued, resp.Running)
+	}
+	for _, ws := range resp.Workers {
+		if ws.ID == busy.id {
+			if ws.Status != "busy" || ws.JobID != 42 || ws.StartedAt == nil {
+				t.Errorf("busy worker = %+v, want busy on job 42 with a start time", ws)
+			}
+		} else if ws.Status != "idle" || ws.JobID != 0 {
+			t.Errorf("worker %+v, want idle", ws)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	server.handleListWorkers(w, httptest.NewRequest(http.MethodPost, "/api/workers", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleResizeWorkers(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	resize := func(method string, body any) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.handleResizeWorkers(w, testutil.MakeJSONRequest(t, method, "/api/workers/resize", body))
+		return w
+	}
+
+	prev := server.workerPool.MaxWorkers()
+	w := resize(http.MethodPost, ResizeWorkersRequest{Workers: prev + 2})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ResizeWorkersResponse
+	testutil.DecodeJSON(t, w, &resp)
+	if resp.Previous != prev || resp.Workers != prev+2 {
+		t.Errorf("response = %+v, want %d -> %d", resp, prev, prev+2)
+	}
+	if got := server.workerPool.MaxWorkers(); got != prev+2 {
+		t.Errorf("MaxWorkers() = %d, want %d", got, prev+2)
+	}
+
+	for _, n := range []int{0, MaxWorkers + 1} {
+		if w := resize(http.MethodPost, ResizeWorkersRequest{Workers: n}); w.Code != http.StatusBadRequest {
+			t.Errorf("workers=%d: expected 400, got %d", n, w.Code)
+		}
+	}
+	if w := resize(http.MethodGet, nil); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandleAddCommentReply(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "test-repo"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	var jobs []*storage.ReviewJob
+	for _, sha := range []string{"abc123", "def456"} {
+		commit, err := db.GetOrCreateCommit(repo.ID, sha, "Author", "Test commit", time.Now())
+		if err != nil {
+			t.Fatalf("GetOrCreateCommit failed: %v", err)
+		}
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test-agent"})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+	parent, err := db.AddCommentToJob(jobs[0].ID, "alice", "Why a mutex?")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+
+	add := func(reqData map[string]any) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.handleAddComment(w, testutil.MakeJSONRequest(t, http.MethodPost, "/api/comment", reqData))
+		return w
+	}
+
+	w := add(map[string]any{"parent_id": parent.ID, "commenter": "bob", "comment": "Concurrent writers"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var reply storage.Response
+	testutil.DecodeJSON(t, w, &reply)
+	if reply.ParentResponseID == nil || *reply.ParentResponseID != parent.ID || reply.JobID == nil || *reply.JobID != jobs[0].ID {
+		t.Errorf("reply = %+v, want parent %d on job %d", reply, parent.ID, jobs[0].ID)
+	}
+
+	if w := add(map[string]any{"job_id": jobs[1].ID, "parent_id": parent.ID, "commenter": "bob", "comment": "Wrong job"}); w.Code != http.StatusBadRequest {
+		t.Errorf("reply on another job: expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := add(map[string]any{"parent_id": 99999, "commenter": "bob", "comment": "Missing"}); w.Code != http.StatusNotFound {
+		t.Errorf("missing parent: expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+
+	comments, err := db.GetCommentsForJob(jobs[0].ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	if len(comments) != 2 || comments[1].Depth != 1 {
+		t.Errorf("Expected the comment and one reply, got %+v", comments)
+	}
+}
+
+func TestHandleUpdateAndDeleteComment(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "test-repo"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	commit, err := db.GetOrCreateCommit(repo.ID, "abc123", "Author", "Test commit", time.Now())
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit failed: %v", err)
+	}
+	job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "abc123", Agent: "test-agent"})
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	comment, err := db.AddCommentToJob(job.ID, "alice", "Wrnog")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+
+	update := func(reqData map[string]any) *httptest.ResponseRecorder {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/comment/update", reqData)
+		w := httptest.NewRecorder()
+		server.handleUpdateComment(w, req)
+		return w
+	}
+	remove := func(id int64) *httptest.ResponseRecorder {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/comment/delete", map[string]any{"id": id})
+		w := httptest.NewRecorder()
+		server.handleDeleteComment(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) ErrorCode {
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode error response: %v", err)
+		}
+		return resp.Error.Code
+	}
+
+	t.Run("update by author", func(t *testing.T) {
+		w := update(map[string]any{"id": comment.ID, "commenter": "alice", "comment": "Wrong"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		comments, err := db.GetCommentsForJob(job.ID)
+		if err != nil {
+			t.Fatalf("GetCommentsForJob failed: %v", err)
+		}
+		if len(comments) != 1 || comments[0].Response != "Wrong" {
+			t.Errorf("Expected updated comment, got %+v", comments)
+		}
+	})
+
+	t.Run("update by another commenter", func(t *testing.T) {
+		w := update(map[string]any{"id": comment.ID, "commenter": "bob", "comment": "Mine now"})
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+		}
+		if code := errorCode(w); code != ErrCodeForbidden {
+			t.Errorf("Expected code %q, got %q", ErrCodeForbidden, code)
+		}
+	})
+
+	t.Run("update missing fields", func(t *testing.T) {
+		w := update(map[string]any{"id": comment.ID, "commenter": "alice"})
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("update missing comment", func(t *testing.T) {
+		w := update(map[string]any{"id": 99999, "commenter": "alice", "comment": "Gone"})
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+		}
+		if code := errorCode(w); code != ErrCodeNotFound {
+			t.Errorf("Expected code %q, got %q", ErrCodeNotFound, code)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if w := remove(comment.ID); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		comments, err := db.GetCommentsForJob(job.ID)
+		if err != nil {
+			t.Fatalf("GetCommentsForJob failed: %v", err)
+		}
+		if len(comments) != 0 {
+			t.Errorf("Expected no comments after delete, got %+v", comments)
+		}
+		if w := remove(comment.ID); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 deleting twice, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
 // TestHandleJobOutput_InvalidJobID tests that invalid job_id returns 400.
 func TestHandleJobOutput_InvalidJobID(t *testing.T) {
 	server, _, _ := newTestServer(t)
@@ -2529,28 +3748,166 @@ func TestHandleJobOutput_StreamingCompletedJob(t *testing.T) {
 		t.Fatalf("Expected 1 row affected, got %d", n)
 	}
 
-	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/job/output?job_id=%d&stream=1", job.ID), nil)
-	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/job/output?job_id=%d&stream=1", job.ID), nil)
+	w := httptest.NewRecorder()
+
+	server.handleJobOutput(w, req)
+
+	// Should return immediately with complete message, not hang
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Type   string `json:"type"`
+		Status string `json:"status"`
+	}
+	testutil.DecodeJSON(t, w, &resp)
+
+	if resp.Type != "complete" {
+		t.Errorf("Expected type 'complete', got %q", resp.Type)
+	}
+	if resp.Status != "done" {
+		t.Errorf("Expected status 'done', got %q", resp.Status)
+	}
+}
+
+func TestHandleJobStream(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+	handler := server.httpServer.Handler
+
+	repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "test-repo"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	enqueue := func(t *testing.T, sha string) *storage.ReviewJob {
+		t.Helper()
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		return job
+	}
+
+	t.Run("invalid id fails", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/job/abc/stream", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("missing job fails", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/job/99999/stream", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("job not running completes immediately", func(t *testing.T) {
+		job := enqueue(t, "queued1")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/job/%d/stream", job.ID), nil))
+		if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("Expected Content-Type 'text/event-stream', got '%s'", ct)
+		}
+		if body := w.Body.String(); body != "event: complete\ndata: {\"status\":\"queued\"}\n\n" {
+			t.Errorf("Expected only a complete event, got %q", body)
+		}
+		if err := db.CancelJob(job.ID); err != nil {
+			t.Fatalf("CancelJob failed: %v", err)
+		}
+	})
+
+	t.Run("streams output until the job finishes", func(t *testing.T) {
+		job := enqueue(t, "running1")
+		if claimed, err := db.ClaimJob("worker-1"); err != nil || claimed == nil || claimed.ID != job.ID {
+			t.Fatalf("ClaimJob: claimed=%v err=%v", claimed, err)
+		}
+		buffers := server.workerPool.outputBuffers
+		buffers.Append(job.ID, OutputLine{Timestamp: time.Now(), Text: "first line", Type: "text"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/job/%d/stream", job.ID), nil).WithContext(ctx)
+		w := newSafeRecorder()
+		done := make(chan struct{})
+		go func() {
+			handler.ServeHTTP(w, req)
+			close(done)
+		}()
+
+		waitForBody := func(substr string) {
+			t.Helper()
+			deadline := time.Now().Add(time.Second)
+			for !strings.Contains(w.bodyString(), substr) {
+				if time.Now().After(deadline) {
+					t.Fatalf("Timed out waiting for %q, got %q", substr, w.bodyString())
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+		waitForBody("first line")
 
-	server.handleJobOutput(w, req)
+		buffers.Append(job.ID, OutputLine{Timestamp: time.Now(), Text: "second line", Type: "tool"})
+		waitForBody("second line")
 
-	// Should return immediately with complete message, not hang
-	if w.Code != http.StatusOK {
-		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
-	}
+		if err := db.CompleteJob(job.ID, "test", "prompt", "output"); err != nil {
+			t.Fatalf("CompleteJob failed: %v", err)
+		}
+		buffers.CloseJob(job.ID)
 
-	var resp struct {
-		Type   string `json:"type"`
-		Status string `json:"status"`
-	}
-	testutil.DecodeJSON(t, w, &resp)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected stream to close when the job finished")
+		}
 
-	if resp.Type != "complete" {
-		t.Errorf("Expected type 'complete', got %q", resp.Type)
-	}
-	if resp.Status != "done" {
-		t.Errorf("Expected status 'done', got %q", resp.Status)
-	}
+		body := w.bodyString()
+		if !strings.Contains(body, "event: output\ndata: {") || !strings.Contains(body, `"line_type":"tool"`) {
+			t.Errorf("Expected output events, got %q", body)
+		}
+		if !strings.HasSuffix(body, "event: complete\ndata: {\"status\":\"done\"}\n\n") {
+			t.Errorf("Expected a final complete event, got %q", body)
+		}
+	})
+
+	t.Run("client disconnect unsubscribes", func(t *testing.T) {
+		job := enqueue(t, "running2")
+		if claimed, err := db.ClaimJob("worker-1"); err != nil || claimed == nil || claimed.ID != job.ID {
+			t.Fatalf("ClaimJob: claimed=%v err=%v", claimed, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/job/%d/stream", job.ID), nil).WithContext(ctx)
+		done := make(chan struct{})
+		go func() {
+			handler.ServeHTTP(newSafeRecorder(), req)
+			close(done)
+		}()
+
+		jo := server.workerPool.outputBuffers.getOrCreate(job.ID)
+		subscribers := func() int {
+			jo.mu.RLock()
+			defer jo.mu.RUnlock()
+			return len(jo.subs)
+		}
+		deadline := time.Now().Add(time.Second)
+		for subscribers() == 0 {
+			if time.Now().After(deadline) {
+				cancel()
+				t.Fatal("Timed out waiting for subscriber")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		cancel()
+		<-done
+		if n := subscribers(); n != 0 {
+			t.Errorf("Expected subscriber removed after disconnect, have %d", n)
+		}
+	})
 }
 
 func TestHandleEnqueueAgentAvailability(t *testing.T) {
@@ -2588,6 +3945,7 @@ func TestHandleEnqueueAgentAvailability(t *testing.T) {
 		mockBinaries  []string // binary names to place in PATH
 		expectedAgent string   // expected agent stored in job
 		expectedCode  int      // expected HTTP status code
+		expectedError string   // substring of the error for non-201 codes
 	}{
 		{
 			name:          "explicit test agent preserved",
@@ -2623,12 +3981,38 @@ func TestHandleEnqueueAgentAvailability(t *testing.T) {
 			mockBinaries: nil,
 			expectedCode: http.StatusServiceUnavailable,
 		},
+		{
+			name:          "custom agent kept when installed",
+			requestAgent:  "myreviewer",
+			mockBinaries:  []string{"myreviewer", "codex"},
+			expectedAgent: "myreviewer",
+			expectedCode:  http.StatusCreated,
+		},
+		{
+			name:          "missing custom agent command returns 400",
+			requestAgent:  "myreviewer",
+			mockBinaries:  []string{"codex"},
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "agent myreviewer is not installed",
+		},
+		{
+			name:          "undefined agent returns 400",
+			requestAgent:  "inhouse",
+			mockBinaries:  []string{"codex"},
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "[agents.inhouse]",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Each subtest gets its own server/DB to avoid SHA dedup conflicts
 			server, _, _ := newTestServer(t)
+			// NewServer registers the (empty) configured custom agents
+			if err := agent.SetCustomAgents(map[string]string{"myreviewer": "myreviewer review < {{.DiffFile}}"}); err != nil {
+				t.Fatalf("SetCustomAgents: %v", err)
+			}
+			t.Cleanup(func() { _ = agent.SetCustomAgents(nil) })
 
 			// Isolate PATH: only mock binaries + git (no real agent CLIs)
 			origPath := os.Getenv("PATH")
@@ -2664,6 +4048,9 @@ func TestHandleEnqueueAgentAvailability(t *testing.T) {
 			}
 
 			if tt.expectedCode != http.StatusCreated {
+				if !strings.Contains(w.Body.String(), tt.expectedError) {
+					t.Errorf("Expected error containing %q, got: %s", tt.expectedError, w.Body.String())
+				}
 				return
 			}
 
@@ -2677,6 +4064,76 @@ func TestHandleEnqueueAgentAvailability(t *testing.T) {
 	}
 }
 
+func TestResolveJobAgent(t *testing.T) {
+	if err := agent.SetCustomAgents(map[string]string{"myreviewer": "roborev-missing-reviewer review < {{.DiffFile}}"}); err != nil {
+		t.Fatalf("SetCustomAgents: %v", err)
+	}
+	t.Cleanup(func() { _ = agent.SetCustomAgents(nil) })
+	cfg := &config.Config{DefaultAgent: "test"}
+
+	tests := []struct {
+		name        string
+		agent       string
+		explicit    bool
+		wantAgent   string
+		wantInvalid bool
+	}{
+		{name: "defined agent kept", agent: "test", wantAgent: "test"},
+		{name: "requested undefined agent rejected", agent: "inhouse", explicit: true, wantInvalid: true},
+		{name: "requested uninstalled custom agent rejected", agent: "myreviewer", explicit: true, wantInvalid: true},
+		{name: "configured undefined agent falls back", agent: "inhouse", wantAgent: "test"},
+		{name: "configured uninstalled custom agent falls back", agent: "myreviewer", wantAgent: "test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveJobAgent(cfg, tt.agent, tt.explicit)
+			var invalid *invalidAgentError
+			if tt.wantInvalid {
+				if !errors.As(err, &invalid) {
+					t.Fatalf("expected an invalid agent error, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveJobAgent: %v", err)
+			}
+			if got != tt.wantAgent {
+				t.Errorf("agent = %q, want %q", got, tt.wantAgent)
+			}
+		})
+	}
+}
+
+func TestHandleEnqueueConfiguredUnknownAgentFallsBack(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "repo")
+	testutil.InitTestGitRepo(t, repoDir)
+	if err := os.WriteFile(filepath.Join(repoDir, ".roborev.toml"), []byte(`agent = "inhouse"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, _ := testutil.OpenTestDBWithDir(t)
+	cfg := config.DefaultConfig()
+	cfg.DefaultAgent = "test"
+	server := NewServer(db, cfg, "")
+
+	// A post-commit hook names no agent, so the repo's config supplies it
+	req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", map[string]string{
+		"repo_path":  repoDir,
+		"commit_sha": testutil.GetHeadSHA(t, repoDir),
+	})
+	w := httptest.NewRecorder()
+	server.handleEnqueue(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var job storage.ReviewJob
+	testutil.DecodeJSON(t, w, &job)
+	if job.Agent != "test" {
+		t.Errorf("Expected the default agent, got %q", job.Agent)
+	}
+}
+
 func TestHandleJobOutput_MissingJobID(t *testing.T) {
 	server, _, _ := newTestServer(t)
 
@@ -2877,6 +4334,68 @@ func TestHandleEnqueueRangeFromRootCommit(t *testing.T) {
 	}
 }
 
+func TestHandleEnqueueRangeLabelAndCommits(t *testing.T) {
+	repoDir := t.TempDir()
+	testutil.InitTestGitRepo(t, repoDir)
+	baseSHA, err := gitpkg.ResolveSHA(repoDir, "HEAD")
+	if err != nil {
+		t.Fatalf("resolve base SHA: %v", err)
+	}
+
+	// Two commits on a feature branch, one on the original branch
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repoDir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	run("tag", "start")
+	run("checkout", "-b", "feature")
+	run("commit", "--allow-empty", "-m", "feature one")
+	run("commit", "--allow-empty", "-m", "feature two")
+	featureSHA, err := gitpkg.ResolveSHA(repoDir, "feature")
+	if err != nil {
+		t.Fatalf("resolve feature SHA: %v", err)
+	}
+	run("checkout", "-")
+	run("commit", "--allow-empty", "-m", "upstream")
+
+	server, db, _ := newTestServer(t)
+
+	for _, rangeRef := range []string{"start..feature", "HEAD...feature"} {
+		t.Run(rangeRef, func(t *testing.T) {
+			req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/enqueue", map[string]string{
+				"repo_path":   repoDir,
+				"git_ref":     rangeRef,
+				"range_label": rangeRef,
+				"agent":       "test",
+			})
+			w := httptest.NewRecorder()
+			server.handleEnqueue(w, req)
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var job storage.ReviewJob
+			testutil.DecodeJSON(t, w, &job)
+			stored, err := db.GetJobByID(job.ID)
+			if err != nil {
+				t.Fatalf("GetJobByID: %v", err)
+			}
+			if want := baseSHA + ".." + featureSHA; stored.GitRef != want {
+				t.Errorf("git_ref = %q, want %q", stored.GitRef, want)
+			}
+			if stored.JobType != storage.JobTypeRange {
+				t.Errorf("job_type = %q, want range", stored.JobType)
+			}
+			if stored.RangeLabel != rangeRef || stored.RangeCommits != 2 {
+				t.Errorf("range_label = %q, range_commits = %d, want %q and 2", stored.RangeLabel, stored.RangeCommits, rangeRef)
+			}
+		})
+	}
+}
+
 // TestHandleEnqueueRangeNonCommitObjectRejects verifies that the root-commit
 // fallback does not trigger for non-commit objects (e.g. blobs).
 func TestHandleEnqueueRangeNonCommitObjectRejects(t *testing.T) {
@@ -3265,69 +4784,264 @@ func TestHandleGetReviewJobIDParsing(t *testing.T) {
 	if err != nil {
 		t.Fatalf("EnqueueJob: %v", err)
 	}
-	claimed, err := db.ClaimJob("test-worker")
-	if err != nil {
-		t.Fatalf("ClaimJob: %v", err)
+	claimed, err := db.ClaimJob("test-worker")
+	if err != nil {
+		t.Fatalf("ClaimJob: %v", err)
+	}
+	if claimed == nil || claimed.ID != job.ID {
+		t.Fatalf("ClaimJob: expected job %d", job.ID)
+	}
+	if err := db.CompleteJob(
+		job.ID, "test", "prompt", "review output",
+	); err != nil {
+		t.Fatalf("CompleteJob: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{
+			"valid job_id",
+			fmt.Sprintf("job_id=%d", job.ID),
+			http.StatusOK,
+		},
+		{
+			"missing params",
+			"",
+			http.StatusBadRequest,
+		},
+		{
+			"non-numeric job_id",
+			"job_id=abc",
+			http.StatusBadRequest,
+		},
+		{
+			"partial numeric job_id",
+			"job_id=10abc",
+			http.StatusBadRequest,
+		},
+		{
+			"not found job_id",
+			"job_id=999999",
+			http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/api/review"
+			if tt.query != "" {
+				url += "?" + tt.query
+			}
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			w := httptest.NewRecorder()
+
+			server.handleGetReview(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf(
+					"expected status %d, got %d: %s",
+					tt.wantStatus, w.Code, w.Body.String(),
+				)
+			}
+		})
+	}
+}
+
+func TestHandleExportReview(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+	handler := server.httpServer.Handler
+
+	repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "repo-export"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+	commit, err := db.GetOrCreateCommit(repo.ID, "abc123", "Author", "Add search", time.Now())
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit: %v", err)
+	}
+	job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "abc123", Agent: "test"})
+	if err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	if _, err := db.ClaimJob("test-worker"); err != nil {
+		t.Fatalf("ClaimJob: %v", err)
+	}
+	if err := db.CompleteJob(job.ID, "test", "prompt", "- High: query uses user input unescaped"); err != nil {
+		t.Fatalf("CompleteJob: %v", err)
+	}
+	if _, err := db.AddCommentToJob(job.ID, "alice", "Fixed in the next commit."); err != nil {
+		t.Fatalf("AddCommentToJob: %v", err)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("markdown by default", func(t *testing.T) {
+		w := get(fmt.Sprintf("/api/review/%d/export", job.ID))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		for _, want := range []string{fmt.Sprintf("# Review #%d: abc123", job.ID), "(Add search)", "query uses user input unescaped", "## Comments", "**alice**", "Fixed in the next commit."} {
+			if !strings.Contains(w.Body.String(), want) {
+				t.Errorf("body missing %q:\n%s", want, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		w := get(fmt.Sprintf("/api/review/%d/export?format=json", job.ID))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+		var got review.ExportedReview
+		testutil.DecodeJSON(t, w, &got)
+		if got.JobID != job.ID || got.Job == nil || got.Job.CommitSubject != "Add search" {
+			t.Errorf("unexpected review: %+v", got.Review)
+		}
+		if len(got.Comments) != 1 || got.Comments[0].Responder != "alice" {
+			t.Errorf("comments = %+v", got.Comments)
+		}
+	})
+
+	for _, tc := range []struct {
+		name, path string
+		want       int
+	}{
+		{"invalid id", "/api/review/abc/export", http.StatusBadRequest},
+		{"invalid format", fmt.Sprintf("/api/review/%d/export?format=pdf", job.ID), http.StatusBadRequest},
+		{"missing review", "/api/review/99999/export", http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := get(tc.path); w.Code != tc.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleGetReviewAmbiguousSHA(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "repo-ambiguous"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+	for _, sha := range []string{"beef1111", "beef2222"} {
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		if _, err := db.ClaimJob("test-worker"); err != nil {
+			t.Fatalf("ClaimJob: %v", err)
+		}
+		if err := db.CompleteJob(job.ID, "test", "prompt", "output"); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	server.handleGetReview(w, httptest.NewRequest(http.MethodGet, "/api/review?sha=beef", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusConflict, w.Body.String())
+	}
+	var errResp ErrorResponse
+	testutil.DecodeJSON(t, w, &errResp)
+	if !strings.Contains(errResp.Error.Message, "beef1111, beef2222") {
+		t.Errorf("message = %q, want both candidates", errResp.Error.Message)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleGetReview(w, httptest.NewRequest(http.MethodGet, "/api/review?sha=beef2", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("unique prefix: status = %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandlePauseResumeQueue(t *testing.T) {
+	server, _, _ := newTestServer(t)
+	handler := server.httpServer.Handler
+
+	post := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", path, w.Code, w.Body.String())
+		}
+		return w
+	}
+	status := func() storage.DaemonStatus {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+		var st storage.DaemonStatus
+		testutil.DecodeJSON(t, w, &st)
+		return st
+	}
+
+	var paused PauseQueueResponse
+	testutil.DecodeJSON(t, post("/api/pause"), &paused)
+	if !paused.Paused {
+		t.Error("expected the first pause to report paused")
+	}
+	if !status().QueuePausedByUser {
+		t.Error("status should report the user pause")
 	}
-	if claimed == nil || claimed.ID != job.ID {
-		t.Fatalf("ClaimJob: expected job %d", job.ID)
+	testutil.DecodeJSON(t, post("/api/pause"), &paused)
+	if paused.Paused {
+		t.Error("expected a second pause to report already paused")
 	}
-	if err := db.CompleteJob(
-		job.ID, "test", "prompt", "review output",
-	); err != nil {
-		t.Fatalf("CompleteJob: %v", err)
+
+	var resumed ResumeQueueResponse
+	testutil.DecodeJSON(t, post("/api/resume"), &resumed)
+	if !resumed.Resumed || status().QueuePausedByUser {
+		t.Errorf("resume = %+v, status still paused = %v", resumed, status().QueuePausedByUser)
 	}
 
-	tests := []struct {
-		name       string
-		query      string
-		wantStatus int
-	}{
-		{
-			"valid job_id",
-			fmt.Sprintf("job_id=%d", job.ID),
-			http.StatusOK,
-		},
-		{
-			"missing params",
-			"",
-			http.StatusBadRequest,
-		},
-		{
-			"non-numeric job_id",
-			"job_id=abc",
-			http.StatusBadRequest,
-		},
-		{
-			"partial numeric job_id",
-			"job_id=10abc",
-			http.StatusBadRequest,
-		},
-		{
-			"not found job_id",
-			"job_id=999999",
-			http.StatusNotFound,
-		},
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/pause", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /api/pause: status %d, want 405", w.Code)
 	}
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			url := "/api/review"
-			if tt.query != "" {
-				url += "?" + tt.query
-			}
-			req := httptest.NewRequest(http.MethodGet, url, nil)
-			w := httptest.NewRecorder()
+func TestHandleListJobsSHAPrefix(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
 
-			server.handleGetReview(w, req)
+	repo, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "repo-prefix"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+	for _, sha := range []string{"cafe1111", "cafe2222"} {
+		if _, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, GitRef: sha, Agent: "test"}); err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+	}
 
-			if w.Code != tt.wantStatus {
-				t.Errorf(
-					"expected status %d, got %d: %s",
-					tt.wantStatus, w.Code, w.Body.String(),
-				)
-			}
-		})
+	w := httptest.NewRecorder()
+	server.handleListJobs(w, httptest.NewRequest(http.MethodGet, "/api/jobs?git_ref=cafe2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Jobs []storage.ReviewJob `json:"jobs"`
+	}
+	testutil.DecodeJSON(t, w, &resp)
+	if len(resp.Jobs) != 1 || resp.Jobs[0].GitRef != "cafe2222" {
+		t.Errorf("jobs = %+v, want the queued job for cafe2222", resp.Jobs)
+	}
+
+	w = httptest.NewRecorder()
+	server.handleListJobs(w, httptest.NewRequest(http.MethodGet, "/api/jobs?git_ref=cafe", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("ambiguous prefix: status = %d, want %d", w.Code, http.StatusConflict)
 	}
 }
 
@@ -4380,3 +6094,341 @@ func writeTempFile(t *testing.T, data []byte) *os.File {
 	}
 	return f
 }
+
+func TestHandleVacuum(t *testing.T) {
+	server, db, _ := newTestServer(t)
+	repo := testutil.CreateTestRepo(t, db)
+
+	// Populate and then delete enough data to leave free pages behind
+	bulk := strings.Repeat("finding ", 4096)
+	var kept *storage.ReviewJob
+	for i := range 20 {
+		job := testutil.CreateCompletedReview(t, db, repo.ID, fmt.Sprintf("vacuum-sha-%d", i), "test", bulk)
+		if i == 0 {
+			kept = job
+			continue
+		}
+		if _, err := db.Exec(`DELETE FROM reviews WHERE job_id = ?`, job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("rejects GET", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/db/vacuum", nil)
+		w := httptest.NewRecorder()
+		server.handleVacuum(w, req)
+		testutil.AssertStatusCode(t, w, http.StatusMethodNotAllowed)
+	})
+
+	t.Run("vacuums and keeps data intact", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/db/vacuum", nil)
+		w := httptest.NewRecorder()
+		server.handleVacuum(w, req)
+		testutil.AssertStatusCode(t, w, http.StatusOK)
+
+		var result storage.VacuumResult
+		testutil.DecodeJSON(t, w, &result)
+		if result.SizeBefore <= 0 || result.SizeAfter <= 0 {
+			t.Fatalf("expected sizes to be reported, got %+v", result)
+		}
+		if result.SizeAfter >= result.SizeBefore {
+			t.Errorf("expected vacuum to shrink database, got %+v", result)
+		}
+
+		review, err := db.GetReviewByJobID(kept.ID)
+		if err != nil {
+			t.Fatalf("review not fetchable after vacuum: %v", err)
+		}
+		if review.Output != bulk {
+			t.Error("review output changed after vacuum")
+		}
+	})
+}
+
+func TestHandleReviewComparison(t *testing.T) {
+	server, db, _ := newTestServer(t)
+	repo := testutil.CreateTestRepo(t, db)
+
+	first := testutil.CreateCompletedReview(t, db, repo.ID, "cmp-sha", "codex", "No issues found.")
+	second := testutil.CreateCompletedReview(t, db, repo.ID, "cmp-sha", "claude-code", "- High: bug")
+	lone := testutil.CreateCompletedReview(t, db, repo.ID, "other-sha", "codex", "No issues found.")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/review/comparison?"+query, nil)
+		w := httptest.NewRecorder()
+		server.handleReviewComparison(w, req)
+		return w
+	}
+
+	t.Run("returns siblings", func(t *testing.T) {
+		w := get(fmt.Sprintf("job_id=%d", second.ID))
+		testutil.AssertStatusCode(t, w, http.StatusOK)
+		var result struct {
+			Reviews []storage.Review `json:"reviews"`
+		}
+		testutil.DecodeJSON(t, w, &result)
+		if len(result.Reviews) != 2 || result.Reviews[0].JobID != first.ID || result.Reviews[1].JobID != second.ID {
+			t.Fatalf("unexpected reviews: %+v", result.Reviews)
+		}
+	})
+
+	t.Run("single review", func(t *testing.T) {
+		w := get(fmt.Sprintf("job_id=%d", lone.ID))
+		testutil.AssertStatusCode(t, w, http.StatusOK)
+		var result struct {
+			Reviews []storage.Review `json:"reviews"`
+		}
+		testutil.DecodeJSON(t, w, &result)
+		if len(result.Reviews) != 1 {
+			t.Errorf("expected only the job's own review, got %d", len(result.Reviews))
+		}
+	})
+
+	t.Run("invalid job_id", func(t *testing.T) {
+		testutil.AssertStatusCode(t, get("job_id=abc"), http.StatusBadRequest)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		testutil.AssertStatusCode(t, get("job_id=99999"), http.StatusNotFound)
+	})
+}
+
+func TestHandleRetryFailedJobs(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	other, err := db.GetOrCreateRepo(filepath.Join(tmpDir, "other"))
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	for _, tc := range []struct {
+		repo *storage.Repo
+		sha  string
+	}{{repo, "retry-a"}, {repo, "retry-b"}, {other, "retry-other"}} {
+		commit, _ := db.GetOrCreateCommit(tc.repo.ID, tc.sha, "Author", "Subject", time.Now())
+		job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: tc.repo.ID, CommitID: commit.ID, GitRef: tc.sha, Agent: "test"})
+		db.ClaimJob("worker-1")
+		db.FailJob(job.ID, "", "some error")
+	}
+
+	t.Run("rejects GET", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/retry-failed", nil)
+		w := httptest.NewRecorder()
+		server.handleRetryFailedJobs(w, req)
+		testutil.AssertStatusCode(t, w, http.StatusMethodNotAllowed)
+	})
+
+	t.Run("requeues failed jobs for repo", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/jobs/retry-failed", RetryFailedRequest{RepoPath: repo.RootPath})
+		w := httptest.NewRecorder()
+		server.handleRetryFailedJobs(w, req)
+		testutil.AssertStatusCode(t, w, http.StatusOK)
+
+		var resp map[string]int
+		testutil.DecodeJSON(t, w, &resp)
+		if resp["requeued"] != 2 {
+			t.Errorf("expected 2 requeued, got %d", resp["requeued"])
+		}
+	})
+}
+
+func TestHandleRecoverJobs(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+	repo, err := db.GetOrCreateRepo(tmpDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	commit, _ := db.GetOrCreateCommit(repo.ID, "recover-sha", "Author", "Subject", time.Now())
+	job, _ := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "recover-sha", Agent: "test"})
+	db.ClaimJob("worker-crashed")
+
+	t.Run("rejects GET", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/recover", nil)
+		w := httptest.NewRecorder()
+		server.handleRecoverJobs(w, req)
+		testutil.AssertStatusCode(t, w, http.StatusMethodNotAllowed)
+	})
+
+	t.Run("fails stuck job", func(t *testing.T) {
+		req := testutil.MakeJSONRequest(t, http.MethodPost, "/api/jobs/recover", RecoverJobsRequest{Fail: true})
+		w := httptest.NewRecorder()
+		server.handleRecoverJobs(w, req)
+		testutil.AssertStatusCode(t, w, http.StatusOK)
+
+		var resp RecoverJobsResponse
+		testutil.DecodeJSON(t, w, &resp)
+		if resp.Recovered != 1 || resp.Action != "failed" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+		got, _ := db.GetJobByID(job.ID)
+		if got.Status != storage.JobStatusFailed {
+			t.Errorf("expected failed, got %s", got.Status)
+		}
+	})
+}
+
+func TestHandleListJobsAgentAndStatusFilter(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, _ := db.GetOrCreateRepo(filepath.Join(tmpDir, "repo-agent"))
+	for i, agentName := range []string{"codex", "claude-code", "codex"} {
+		sha := fmt.Sprintf("agent-%d", i)
+		commit, _ := db.GetOrCreateCommit(repo.ID, sha, "Author", "Subject", time.Now())
+		if _, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: agentName}); err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+	}
+	// Complete the first codex job with a failing review
+	claimed, err := db.ClaimJob("w")
+	if err != nil {
+		t.Fatalf("ClaimJob failed: %v", err)
+	}
+	if err := db.CompleteJob(claimed.ID, "codex", "", "- High: unchecked error"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, []storage.ReviewJob) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs?"+query, nil)
+		w := httptest.NewRecorder()
+		server.handleListJobs(w, req)
+		var resp struct {
+			Jobs []storage.ReviewJob `json:"jobs"`
+		}
+		if w.Code == http.StatusOK {
+			testutil.DecodeJSON(t, w, &resp)
+		}
+		return w, resp.Jobs
+	}
+
+	t.Run("agent", func(t *testing.T) {
+		w, jobs := list("agent=codex")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(jobs) != 2 {
+			t.Fatalf("Expected 2 codex jobs, got %d", len(jobs))
+		}
+		for _, j := range jobs {
+			if j.Agent != "codex" {
+				t.Errorf("Expected agent codex, got %q", j.Agent)
+			}
+		}
+	})
+
+	t.Run("agent and status with verdict", func(t *testing.T) {
+		w, jobs := list("agent=codex&status=done")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(jobs) != 1 || jobs[0].ID != claimed.ID {
+			t.Fatalf("Expected only job %d, got %+v", claimed.ID, jobs)
+		}
+		if jobs[0].Verdict == nil || *jobs[0].Verdict != "F" {
+			t.Errorf("Expected verdict F, got %v", jobs[0].Verdict)
+		}
+	})
+
+	t.Run("verdict", func(t *testing.T) {
+		w, jobs := list("verdict=fail")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(jobs) != 1 || jobs[0].ID != claimed.ID {
+			t.Fatalf("Expected only failing job %d, got %+v", claimed.ID, jobs)
+		}
+		if _, jobs := list("verdict=pass"); len(jobs) != 0 {
+			t.Errorf("Expected no passing jobs, got %d", len(jobs))
+		}
+		if _, jobs := list("verdict=pending"); len(jobs) != 2 {
+			t.Errorf("Expected 2 jobs without a verdict, got %d", len(jobs))
+		}
+		if w, _ := list("verdict=maybe"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for an invalid verdict, got %d", w.Code)
+		}
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		w, _ := list("status=finished")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp ErrorResponse
+		testutil.DecodeJSON(t, w, &resp)
+		if resp.Error.Code != ErrCodeInvalidArgument {
+			t.Errorf("Expected code %q, got %q", ErrCodeInvalidArgument, resp.Error.Code)
+		}
+	})
+}
+
+func TestHandleListJobsMachineFilter(t *testing.T) {
+	server, db, tmpDir := newTestServer(t)
+
+	repo, _ := db.GetOrCreateRepo(filepath.Join(tmpDir, "repo-machine"))
+	var jobIDs []int64
+	for i := range 2 {
+		sha := fmt.Sprintf("machine-%d", i)
+		commit, _ := db.GetOrCreateCommit(repo.ID, sha, "Author", "Subject", time.Now())
+		job, err := db.EnqueueJob(storage.EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test"})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		jobIDs = append(jobIDs, job.ID)
+	}
+	localID, remoteID := jobIDs[0], jobIDs[1]
+	if _, err := db.Exec(`UPDATE review_jobs SET source_machine_id = 'remote-1' WHERE id = ?`, remoteID); err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, []storage.ReviewJob) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs?"+query, nil)
+		w := httptest.NewRecorder()
+		server.handleListJobs(w, req)
+		var resp struct {
+			Jobs []storage.ReviewJob `json:"jobs"`
+		}
+		if w.Code == http.StatusOK {
+			testutil.DecodeJSON(t, w, &resp)
+		}
+		return w, resp.Jobs
+	}
+
+	t.Run("machine", func(t *testing.T) {
+		w, jobs := list("machine=remote-1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(jobs) != 1 || jobs[0].ID != remoteID {
+			t.Fatalf("Expected only job %d, got %+v", remoteID, jobs)
+		}
+	})
+
+	t.Run("local only", func(t *testing.T) {
+		w, jobs := list("local_only=true")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if len(jobs) != 1 || jobs[0].ID != localID {
+			t.Fatalf("Expected only job %d, got %+v", localID, jobs)
+		}
+	})
+
+	t.Run("machine with local only is rejected", func(t *testing.T) {
+		if w, _ := list("machine=remote-1&local_only=true"); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("status lists machine IDs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+		w := httptest.NewRecorder()
+		server.handleStatus(w, req)
+		var status storage.DaemonStatus
+		testutil.DecodeJSON(t, w, &status)
+		if !slices.Contains(status.MachineIDs, "remote-1") || !slices.Contains(status.MachineIDs, status.MachineID) {
+			t.Errorf("MachineIDs = %v, want remote-1 and local %q", status.MachineIDs, status.MachineID)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gitpkg "github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+// slackTimeout bounds each Slack webhook request.
+const slackTimeout = 10 * time.Second
+
+// maxSlackFindings caps how many findings a Slack message lists.
+const maxSlackFindings = 5
+
+// httpDoer sends HTTP requests. *http.Client satisfies it; tests substitute
+// a fake.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// slackMessage is the JSON body of a Slack incoming webhook request.
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// slackEscaper escapes the characters Slack mrkdwn treats as control
+// sequences, so text like "<!channel>" or "<url|label>" is shown literally.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// formatSlackMessage renders a failing review.completed event as Slack
+// mrkdwn: the repo, short SHA, commit subject, verdict, and the findings
+// at the highest severity present in the review output. All text taken
+// from the event is escaped.
+func formatSlackMessage(event Event) string {
+	repoName := event.RepoName
+	if repoName == "" {
+		repoName = filepath.Base(event.Repo)
+	}
+	esc := slackEscaper.Replace
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*roborev: review FAILED* for %s `%s`", esc(repoName), esc(gitpkg.ShortSHA(event.SHA)))
+	if event.Subject != "" {
+		fmt.Fprintf(&sb, " %s", esc(event.Subject))
+	}
+	fmt.Fprintf(&sb, "\nJob %d", event.JobID)
+	if event.Agent != "" {
+		fmt.Fprintf(&sb, " reviewed by %s", esc(event.Agent))
+	}
+	fmt.Fprintf(&sb, ". Run `roborev show %d` for details.", event.JobID)
+
+	top := storage.HighestSeverityFindings(storage.ExtractFindings(event.Findings))
+	if len(top) == 0 {
+		if summary := storage.ExtractSummary(event.Findings); summary != "" {
+			fmt.Fprintf(&sb, "\n%s", esc(summary))
+		}
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "\n*Top findings (%s):*", esc(top[0].Severity))
+	for i, f := range top {
+		if i == maxSlackFindings {
+			fmt.Fprintf(&sb, "\n...and %d more", len(top)-maxSlackFindings)
+			break
+		}
+		line, _, _ := strings.Cut(f.Text, "\n")
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*"))
+		fmt.Fprintf(&sb, "\n- %s", esc(line))
+	}
+	return sb.String()
+}
+
+// postSlackNotification posts the message for event to a Slack incoming
+// webhook. Requests that take longer than slackTimeout are abandoned.
+func postSlackNotification(client httpDoer, webhookURL string, event Event) error {
+	body, err := json.Marshal(slackMessage{Text: formatSlackMessage(event)})
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", stripURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", stripURL(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// stripURL drops the URL from a *url.Error, since the path of a Slack
+// webhook URL is its secret token and must not end up in logs.
+func stripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// notifySlack posts a failing review to Slack. Errors are logged and
+// never propagated, so a Slack outage can't affect the review job.
+func (hr *HookRunner) notifySlack(webhookURL string, event Event) {
+	if err := postSlackNotification(hr.slackClient, webhookURL, event); err != nil {
+		log.Printf("Slack notification failed (job %d): %v", event.JobID, err)
+	}
+}
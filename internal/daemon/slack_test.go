@@ -0,0 +1,215 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/config"
+)
+
+// fakeSlackClient records webhook requests and answers with a fixed
+// status or error.
+type fakeSlackClient struct {
+	status int
+	err    error
+	reqs   chan *http.Request
+	bodies chan slackMessage
+}
+
+func newFakeSlackClient(status int, err error) *fakeSlackClient {
+	return &fakeSlackClient{
+		status: status,
+		err:    err,
+		reqs:   make(chan *http.Request, 4),
+		bodies: make(chan slackMessage, 4),
+	}
+}
+
+func (c *fakeSlackClient) Do(req *http.Request) (*http.Response, error) {
+	var msg slackMessage
+	if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+		return nil, err
+	}
+	c.reqs <- req
+	c.bodies <- msg
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &http.Response{
+		StatusCode: c.status,
+		Status:     http.StatusText(c.status),
+		Body:       io.NopCloser(strings.NewReader("ok")),
+	}, nil
+}
+
+func failingReviewEvent() Event {
+	return Event{
+		Type:     "review.completed",
+		TS:       time.Now(),
+		JobID:    42,
+		Repo:     "/tmp/myrepo",
+		RepoName: "myrepo",
+		SHA:      "abc1234def5678",
+		Subject:  "Add login handler",
+		Agent:    "codex",
+		Verdict:  "F",
+		Findings: "Summary: needs work\n\n- High: SQL built with string concatenation\n- Low: typo in comment\n- High: password logged in plain text",
+	}
+}
+
+func TestFormatSlackMessage(t *testing.T) {
+	text := formatSlackMessage(failingReviewEvent())
+
+	for _, want := range []string{"FAILED", "myrepo", "abc1234", "Add login handler", "Job 42", "codex", "roborev show 42", "(high)", "SQL built with string concatenation", "password logged in plain text"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "typo in comment") {
+		t.Errorf("message should list only the highest-severity findings:\n%s", text)
+	}
+}
+
+func TestFormatSlackMessageCapsFindings(t *testing.T) {
+	event := failingReviewEvent()
+	var lines []string
+	for range maxSlackFindings + 2 {
+		lines = append(lines, "- Critical: data loss")
+	}
+	event.Findings = strings.Join(lines, "\n")
+
+	text := formatSlackMessage(event)
+	if got := strings.Count(text, "data loss"); got != maxSlackFindings {
+		t.Errorf("listed %d findings, want %d:\n%s", got, maxSlackFindings, text)
+	}
+	if !strings.Contains(text, "and 2 more") {
+		t.Errorf("message should note the omitted findings:\n%s", text)
+	}
+}
+
+func TestFormatSlackMessageEscapesText(t *testing.T) {
+	event := failingReviewEvent()
+	event.Subject = "Ping <!channel> & friends"
+	event.Findings = "- High: see <https://evil.example|docs> for details"
+
+	text := formatSlackMessage(event)
+	for _, want := range []string{"Ping &lt;!channel&gt; &amp; friends", "see &lt;https://evil.example|docs&gt; for details"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message missing escaped %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "<!channel>") || strings.Contains(text, "<https://") {
+		t.Errorf("message contains unescaped mrkdwn:\n%s", text)
+	}
+}
+
+func TestPostSlackNotification(t *testing.T) {
+	t.Run("posts JSON to the webhook", func(t *testing.T) {
+		client := newFakeSlackClient(http.StatusOK, nil)
+		if err := postSlackNotification(client, "https://hooks.slack.test/abc", failingReviewEvent()); err != nil {
+			t.Fatalf("postSlackNotification: %v", err)
+		}
+		req := <-client.reqs
+		if req.Method != http.MethodPost || req.URL.String() != "https://hooks.slack.test/abc" {
+			t.Errorf("request = %s %s", req.Method, req.URL)
+		}
+		if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if _, ok := req.Context().Deadline(); !ok {
+			t.Error("request should have a deadline")
+		}
+		if msg := <-client.bodies; !strings.Contains(msg.Text, "abc1234") {
+			t.Errorf("text = %q", msg.Text)
+		}
+	})
+
+	t.Run("non-2xx status is an error", func(t *testing.T) {
+		client := newFakeSlackClient(http.StatusForbidden, nil)
+		if err := postSlackNotification(client, "https://hooks.slack.test/abc", failingReviewEvent()); err == nil {
+			t.Error("expected error for 403 response")
+		}
+	})
+
+	t.Run("transport error is returned", func(t *testing.T) {
+		client := newFakeSlackClient(0, errors.New("connection refused"))
+		if err := postSlackNotification(client, "https://hooks.slack.test/abc", failingReviewEvent()); err == nil {
+			t.Error("expected transport error")
+		}
+	})
+}
+
+func TestNotifySlackDoesNotLogWebhookToken(t *testing.T) {
+	// A closed server gives a connection error for the webhook URL
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+	const tokenPath = "/services/T000/B000/s3cr3tT0ken"
+
+	for _, webhookURL := range []string{ts.URL + tokenPath, "http://[::1" + tokenPath} {
+		var buf bytes.Buffer
+		prevOut := log.Writer()
+		log.SetOutput(&buf)
+		hr := &HookRunner{slackClient: &http.Client{}}
+		hr.notifySlack(webhookURL, failingReviewEvent())
+		log.SetOutput(prevOut)
+
+		logOutput := buf.String()
+		if !strings.Contains(logOutput, "Slack notification failed") {
+			t.Errorf("expected a failure to be logged, got %q", logOutput)
+		}
+		if strings.Contains(logOutput, "s3cr3tT0ken") {
+			t.Errorf("log leaks the webhook token: %q", logOutput)
+		}
+	}
+}
+
+func TestHandleEventSlackNotification(t *testing.T) {
+	cfg := &config.Config{Notify: config.NotifyConfig{SlackWebhookURL: "https://hooks.slack.test/abc"}}
+
+	t.Run("notifies on failing review", func(t *testing.T) {
+		client := newFakeSlackClient(http.StatusInternalServerError, nil)
+		hr := &HookRunner{cfgGetter: NewStaticConfig(cfg), slackClient: client}
+
+		hr.handleEvent(failingReviewEvent())
+
+		select {
+		case msg := <-client.bodies:
+			if !strings.Contains(msg.Text, "Add login handler") {
+				t.Errorf("text = %q", msg.Text)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a Slack notification")
+		}
+	})
+
+	for _, tc := range []struct {
+		name  string
+		event func(Event) Event
+		cfg   *config.Config
+	}{
+		{"passing review", func(e Event) Event { e.Verdict = "P"; return e }, cfg},
+		{"failed job", func(e Event) Event { e.Type = "review.failed"; return e }, cfg},
+		{"no webhook configured", func(e Event) Event { return e }, &config.Config{}},
+	} {
+		t.Run("skips "+tc.name, func(t *testing.T) {
+			client := newFakeSlackClient(http.StatusOK, nil)
+			hr := &HookRunner{cfgGetter: NewStaticConfig(tc.cfg), slackClient: client}
+
+			hr.handleEvent(tc.event(failingReviewEvent()))
+
+			select {
+			case <-client.reqs:
+				t.Error("unexpected Slack notification")
+			case <-time.After(200 * time.Millisecond):
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,24 +33,50 @@ type WorkerPool struct {
 
 	numWorkers    int
 	activeWorkers atomic.Int32
-	stopCh        chan struct{}
-	readyCh       chan struct{} // closed after wg.Add in Start
-	startOnce     sync.Once
-	stopOnce      sync.Once
-	wg            sync.WaitGroup
+
+	// Workers that haven't been retired, oldest first. Resize retires
+	// workers from the end. workers also holds retired workers until
+	// they finish their current job and exit.
+	liveWorkers  []*workerHandle
+	workers      map[string]*workerHandle
+	nextWorkerID int
+	workersMu    sync.Mutex
+
+	stopCh    chan struct{}
+	readyCh   chan struct{} // closed after wg.Add in Start
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
 
 	// Track running jobs for cancellation
 	runningJobs    map[int64]context.CancelFunc
-	pendingCancels map[int64]bool // Jobs canceled before registered
+	pendingCancels map[int64]bool     // Jobs canceled before registered
+	claimedJobs    map[int64]struct{} // Jobs claimed by a worker and not yet finished
 	runningJobsMu  sync.Mutex
 
+	// Held for reading while claiming a job; held for writing while
+	// claims are paused (e.g. during database maintenance)
+	claimMu sync.RWMutex
+
 	// Agent cooldowns for quota exhaustion
 	agentCooldowns   map[string]time.Time // agent name -> expiry
 	agentCooldownsMu sync.RWMutex
 
+	// Why claims are paused after an agent authentication failure
+	// ("" = not paused). Only set when pause_on_auth_failure is enabled.
+	authPauseReason   string
+	authPauseReasonMu sync.RWMutex
+
+	// Set while the user has paused the queue with `roborev pause`.
+	// Persisted so the pause survives a daemon restart.
+	userPaused atomic.Bool
+
 	// Output capture for tail command
 	outputBuffers *OutputBuffer
 
+	// Backoff before a job's first automatic retry
+	retryBaseDelay time.Duration
+
 	// Test hooks for deterministic synchronization (nil in production)
 	testHookAfterSecondCheck    func() // Called after second runningJobs check, before second DB lookup
 	testHookCooldownLockUpgrade func() // Called between RUnlock and Lock in isAgentCoolingDown
@@ -55,7 +84,7 @@ type WorkerPool struct {
 
 // NewWorkerPool creates a new worker pool
 func NewWorkerPool(db *storage.DB, cfgGetter ConfigGetter, numWorkers int, broadcaster Broadcaster, errorLog *ErrorLog, activityLog *ActivityLog) *WorkerPool {
-	return &WorkerPool{
+	wp := &WorkerPool{
 		db:             db,
 		cfgGetter:      cfgGetter,
 		promptBuilder:  prompt.NewBuilder(db),
@@ -67,33 +96,169 @@ func NewWorkerPool(db *storage.DB, cfgGetter ConfigGetter, numWorkers int, broad
 		readyCh:        make(chan struct{}),
 		runningJobs:    make(map[int64]context.CancelFunc),
 		pendingCancels: make(map[int64]bool),
+		claimedJobs:    make(map[int64]struct{}),
+		workers:        make(map[string]*workerHandle),
 		agentCooldowns: make(map[string]time.Time),
 		outputBuffers:  NewOutputBuffer(512*1024, 4*1024*1024), // 512KB/job, 4MB total
+		retryBaseDelay: defaultRetryBaseDelay,
+	}
+	if db != nil {
+		if paused, err := db.GetSyncState(storage.SyncStateQueuePaused); err != nil {
+			log.Printf("Warning: failed to read queue pause state: %v", err)
+		} else if paused == "1" {
+			log.Println("Queue is paused; run 'roborev resume' to start new jobs")
+			wp.userPaused.Store(true)
+		}
 	}
+	return wp
 }
 
 // Start begins the worker pool. Safe to call multiple times;
 // only the first call spawns workers.
 func (wp *WorkerPool) Start() {
 	wp.startOnce.Do(func() {
+		wp.workersMu.Lock()
+		defer wp.workersMu.Unlock()
 		log.Printf(
 			"Starting worker pool with %d workers",
 			wp.numWorkers,
 		)
-		wp.wg.Add(wp.numWorkers)
-		close(wp.readyCh)
-		for i := 0; i < wp.numWorkers; i++ {
-			go wp.worker(i)
+		for range wp.numWorkers {
+			wp.spawnWorkerLocked()
 		}
+		close(wp.readyCh)
 	})
 }
 
+// workerHandle tracks one worker goroutine
+type workerHandle struct {
+	seq    int
+	id     string
+	retire chan struct{}
+
+	// Guarded by WorkerPool.workersMu
+	retiring bool
+	jobID    int64
+	jobStart time.Time
+}
+
+// WorkerStatus describes what a worker is doing
+type WorkerStatus struct {
+	ID             string     `json:"id"`
+	Status         string     `json:"status"` // "idle" or "busy"
+	JobID          int64      `json:"job_id,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	RunningSeconds int64      `json:"running_seconds,omitempty"`
+	Retiring       bool       `json:"retiring,omitempty"` // Exits once its job finishes
+}
+
+// spawnWorkerLocked starts one worker. Caller must hold workersMu.
+func (wp *WorkerPool) spawnWorkerLocked() {
+	h := &workerHandle{
+		seq:    wp.nextWorkerID,
+		id:     fmt.Sprintf("worker-%d", wp.nextWorkerID),
+		retire: make(chan struct{}),
+	}
+	wp.nextWorkerID++
+	wp.liveWorkers = append(wp.liveWorkers, h)
+	wp.workers[h.id] = h
+	wp.wg.Add(1)
+	go wp.worker(h)
+}
+
+// setWorkerJob records the job a worker is running (0 when idle)
+func (wp *WorkerPool) setWorkerJob(h *workerHandle, jobID int64) {
+	wp.workersMu.Lock()
+	defer wp.workersMu.Unlock()
+	h.jobID = jobID
+	h.jobStart = time.Now()
+}
+
+// WorkerStatuses reports each worker's state, including retired workers
+// still finishing a job, in the order they were started.
+func (wp *WorkerPool) WorkerStatuses() []WorkerStatus {
+	wp.workersMu.Lock()
+	handles := make([]*workerHandle, 0, len(wp.workers))
+	for _, h := range wp.workers {
+		handles = append(handles, h)
+	}
+	slices.SortFunc(handles, func(a, b *workerHandle) int { return a.seq - b.seq })
+
+	now := time.Now()
+	statuses := make([]WorkerStatus, 0, len(handles))
+	for _, h := range handles {
+		st := WorkerStatus{ID: h.id, Status: "idle", Retiring: h.retiring}
+		if h.jobID != 0 {
+			started := h.jobStart
+			st.Status = "busy"
+			st.JobID = h.jobID
+			st.StartedAt = &started
+			st.RunningSeconds = int64(now.Sub(started).Seconds())
+		}
+		statuses = append(statuses, st)
+	}
+	wp.workersMu.Unlock()
+	return statuses
+}
+
+// Bounds accepted by Resize
+const (
+	MinWorkers = 1
+	MaxWorkers = 64
+)
+
+// Resize changes the number of workers while the daemon runs. New
+// workers start claiming jobs immediately; retired workers finish their
+// current job before exiting, so until they do more than n jobs may be
+// running. Returns the previous worker count.
+func (wp *WorkerPool) Resize(n int) (int, error) {
+	if n < MinWorkers || n > MaxWorkers {
+		return 0, fmt.Errorf("worker count must be between %d and %d, got %d", MinWorkers, MaxWorkers, n)
+	}
+
+	wp.workersMu.Lock()
+	defer wp.workersMu.Unlock()
+	select {
+	case <-wp.stopCh:
+		return 0, fmt.Errorf("worker pool is stopped")
+	default:
+	}
+
+	prev := wp.numWorkers
+	wp.numWorkers = n
+	select {
+	case <-wp.readyCh:
+	default:
+		// Not started yet; Start spawns the new count
+		return prev, nil
+	}
+
+	for len(wp.liveWorkers) < n {
+		wp.spawnWorkerLocked()
+	}
+	for len(wp.liveWorkers) > n {
+		last := len(wp.liveWorkers) - 1
+		h := wp.liveWorkers[last]
+		h.retiring = true
+		close(h.retire)
+		wp.liveWorkers = wp.liveWorkers[:last]
+	}
+	if prev != n {
+		log.Printf("Resized worker pool from %d to %d workers", prev, n)
+	}
+	return prev, nil
+}
+
 // Stop gracefully shuts down the worker pool. Safe to call
 // multiple times; only the first call performs shutdown.
 func (wp *WorkerPool) Stop() {
 	wp.stopOnce.Do(func() {
 		log.Println("Stopping worker pool...")
+		// Close under workersMu so a concurrent Resize can't add
+		// workers once Wait may have started
+		wp.workersMu.Lock()
 		close(wp.stopCh)
+		wp.workersMu.Unlock()
 		// Wait for Start to finish wg.Add before calling Wait.
 		// If Start was never called, readyCh stays open but
 		// stopCh is closed, so any late workers exit immediately.
@@ -113,9 +278,116 @@ func (wp *WorkerPool) ActiveWorkers() int {
 
 // MaxWorkers returns the total number of workers in the pool
 func (wp *WorkerPool) MaxWorkers() int {
+	wp.workersMu.Lock()
+	defer wp.workersMu.Unlock()
 	return wp.numWorkers
 }
 
+// claimLimits splits a pool of workers into a fix pool of max_fix_workers
+// and a review pool of the rest, so neither kind of job can take every
+// worker. At least one worker is left for reviews. With no fix limit, or
+// a single worker, any job may use any worker.
+func claimLimits(cfg *config.Config, workers int) storage.ClaimLimits {
+	limits := storage.ClaimLimits{PerRepo: cfg.PerRepoMaxWorkers}
+	if cfg.MaxFixWorkers <= 0 || workers <= 1 {
+		return limits
+	}
+	limits.Fix = min(cfg.MaxFixWorkers, workers-1)
+	limits.Review = workers - limits.Fix
+	return limits
+}
+
+// PauseClaims stops workers from claiming new jobs until the returned
+// resume function is called. Jobs already running are not affected.
+func (wp *WorkerPool) PauseClaims() (resume func()) {
+	wp.claimMu.Lock()
+	return wp.claimMu.Unlock
+}
+
+// QueuePauseReason returns why the queue is paused after an agent
+// authentication failure, or "" if it isn't.
+func (wp *WorkerPool) QueuePauseReason() string {
+	wp.authPauseReasonMu.RLock()
+	defer wp.authPauseReasonMu.RUnlock()
+	return wp.authPauseReason
+}
+
+// ResumeQueue lets workers claim jobs again after an authentication
+// failure paused the queue. Returns false if the queue wasn't paused.
+func (wp *WorkerPool) ResumeQueue() bool {
+	wp.authPauseReasonMu.Lock()
+	defer wp.authPauseReasonMu.Unlock()
+	if wp.authPauseReason == "" {
+		return false
+	}
+	wp.authPauseReason = ""
+	return true
+}
+
+// PausedByUser reports whether the user has paused the queue.
+func (wp *WorkerPool) PausedByUser() bool {
+	return wp.userPaused.Load()
+}
+
+// Pause stops workers from claiming new jobs until Resume is called,
+// remembering the pause across daemon restarts. Running jobs finish
+// normally. Returns false if the queue was already paused by the user.
+func (wp *WorkerPool) Pause() (bool, error) {
+	if wp.userPaused.Load() {
+		return false, nil
+	}
+	if err := wp.db.SetSyncState(storage.SyncStateQueuePaused, "1"); err != nil {
+		return false, err
+	}
+	return !wp.userPaused.Swap(true), nil
+}
+
+// Resume lifts both a user pause and a pause after an authentication
+// failure. Returns false if the queue wasn't paused.
+func (wp *WorkerPool) Resume() (bool, error) {
+	wasPaused := wp.userPaused.Load()
+	if wasPaused {
+		if err := wp.db.SetSyncState(storage.SyncStateQueuePaused, ""); err != nil {
+			return false, err
+		}
+		wp.userPaused.Store(false)
+	}
+	return wp.ResumeQueue() || wasPaused, nil
+}
+
+// pauseQueue stops workers from claiming new jobs until ResumeQueue is
+// called. An existing pause keeps its original reason.
+func (wp *WorkerPool) pauseQueue(reason string) {
+	wp.authPauseReasonMu.Lock()
+	defer wp.authPauseReasonMu.Unlock()
+	if wp.authPauseReason == "" {
+		wp.authPauseReason = reason
+	}
+}
+
+// RecoverStuckJobs requeues (or, if fail is set, fails) jobs that are marked
+// running in the database but are not being processed by any worker in this
+// pool, e.g. after a daemon crash. Returns the number of jobs recovered and
+// the number of running jobs left alone because a worker owns them.
+func (wp *WorkerPool) RecoverStuckJobs(fail bool) (recovered, active int, err error) {
+	// With claims paused, claimedJobs covers every job a worker holds
+	resume := wp.PauseClaims()
+	defer resume()
+
+	wp.runningJobsMu.Lock()
+	activeIDs := make([]int64, 0, len(wp.claimedJobs))
+	for id := range wp.claimedJobs {
+		activeIDs = append(activeIDs, id)
+	}
+	wp.runningJobsMu.Unlock()
+
+	recovered, err = wp.db.RecoverStuckJobs(activeIDs, fail)
+	if err != nil {
+		return 0, 0, err
+	}
+	return recovered, len(activeIDs), nil
+}
+
 // GetJobOutput returns the current output lines for a job.
 func (wp *WorkerPool) GetJobOutput(jobID int64) []OutputLine {
 	return wp.outputBuffers.GetLines(jobID)
@@ -254,9 +526,14 @@ func (wp *WorkerPool) unregisterRunningJob(jobID int64) {
 	wp.runningJobsMu.Unlock()
 }
 
-func (wp *WorkerPool) worker(id int) {
+func (wp *WorkerPool) worker(h *workerHandle) {
 	defer wp.wg.Done()
-	workerID := fmt.Sprintf("worker-%d", id)
+	defer func() {
+		wp.workersMu.Lock()
+		delete(wp.workers, h.id)
+		wp.workersMu.Unlock()
+	}()
+	workerID := h.id
 
 	log.Printf("[%s] Started", workerID)
 
@@ -265,11 +542,29 @@ func (wp *WorkerPool) worker(id int) {
 		case <-wp.stopCh:
 			log.Printf("[%s] Shutting down", workerID)
 			return
+		case <-h.retire:
+			log.Printf("[%s] Retired", workerID)
+			return
 		default:
 		}
 
+		// Leave jobs queued while paused by the user or after an
+		// authentication failure
+		if wp.PausedByUser() || wp.QueuePauseReason() != "" {
+			time.Sleep(2 * time.Second)
+			continue
+		}
+
 		// Try to claim a job
-		job, err := wp.db.ClaimJob(workerID)
+		wp.claimMu.RLock()
+		cfg := wp.cfgGetter.Config()
+		job, err := wp.db.ClaimJobWithLimits(workerID, claimLimits(cfg, wp.MaxWorkers()))
+		if job != nil {
+			wp.runningJobsMu.Lock()
+			wp.claimedJobs[job.ID] = struct{}{}
+			wp.runningJobsMu.Unlock()
+		}
+		wp.claimMu.RUnlock()
 		if err != nil {
 			log.Printf("[%s] Error claiming job: %v", workerID, err)
 			if wp.errorLog != nil {
@@ -287,14 +582,44 @@ func (wp *WorkerPool) worker(id int) {
 
 		// Process the job
 		wp.activeWorkers.Add(1)
+		wp.setWorkerJob(h, job.ID)
 		wp.processJob(workerID, job)
+		wp.setWorkerJob(h, 0)
 		wp.activeWorkers.Add(-1)
+
+		wp.runningJobsMu.Lock()
+		delete(wp.claimedJobs, job.ID)
+		wp.runningJobsMu.Unlock()
 	}
 }
 
-// maxRetries is the number of retry attempts allowed after initial failure.
-// With maxRetries=3, a job can run up to 4 times total (1 initial + 3 retries).
-const maxRetries = 3
+// Automatic retries back off exponentially: the first waits
+// defaultRetryBaseDelay and each later one twice as long as the last, up
+// to maxRetryDelay.
+const (
+	defaultRetryBaseDelay = 30 * time.Second
+	maxRetryDelay         = 10 * time.Minute
+)
+
+// maxRetries returns the number of retry attempts allowed after initial
+// failure. With max_retries=3, a job can run up to 4 times total (1
+// initial + 3 retries).
+func (wp *WorkerPool) maxRetries() int {
+	return max(wp.cfgGetter.Config().MaxRetries, 0)
+}
+
+// retryDelay returns the backoff before retrying a job that has already
+// been retried retryCount times.
+func (wp *WorkerPool) retryDelay(retryCount int) time.Duration {
+	delay := wp.retryBaseDelay
+	for range retryCount {
+		if delay >= maxRetryDelay {
+			break
+		}
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
 
 // reviewTypeTag returns a display prefix for non-default review types
 // (e.g. "security "). Returns "" for the default review type to avoid
@@ -306,6 +631,19 @@ func reviewTypeTag(rt string) string {
 	return rt + " "
 }
 
+// tokenUsage converts the usage an agent reported into its stored form,
+// or nil when the agent reported none.
+func tokenUsage(u agent.Usage) *storage.TokenUsage {
+	if u.IsZero() {
+		return nil
+	}
+	return &storage.TokenUsage{
+		InputTokens:  u.InputTokens,
+		OutputTokens: u.OutputTokens,
+		CostUSD:      u.CostUSD,
+	}
+}
+
 func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 	rtTag := reviewTypeTag(job.ReviewType)
 
@@ -331,15 +669,20 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 	// This prevents mixed settings if config reloads mid-job.
 	cfg := wp.cfgGetter.Config()
 
-	// Get timeout from config (per-repo or global, default 30 minutes)
-	timeoutMinutes := config.ResolveJobTimeout(job.RepoPath, cfg)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutMinutes)*time.Minute)
+	timeout := jobTimeout(cfg, job)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// Register for cancellation tracking
 	wp.registerRunningJob(job.ID, cancel)
 	defer wp.unregisterRunningJob(job.ID)
 
+	// A finished review of an identical diff (e.g. of a commit amended
+	// only to change its message) is copied instead of running the agent
+	if job.DiffHash != "" && config.ResolveReviewDedup(job.RepoPath, cfg) && wp.completeFromCache(workerID, job) {
+		return
+	}
+
 	// Skip immediately if the agent is in quota cooldown.
 	// Resolve alias so "claude" checks cooldown for "claude-code".
 	canonicalAgent := agent.CanonicalName(job.Agent)
@@ -354,6 +697,7 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 	// Build the prompt (or use pre-stored prompt for task/compact jobs)
 	var reviewPrompt string
 	var err error
+	builder := wp.promptBuilder.WithTokenBudget(config.ResolvePromptTokenBudget(job.RepoPath, cfg))
 	if job.UsesStoredPrompt() && job.Prompt != "" {
 		// Prompt-native job (task, compact) — prepend agent-specific preamble
 		preamble := prompt.GetSystemPrompt(job.Agent, "run")
@@ -367,12 +711,21 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 		// daemon version mismatch or storage issue. Fail clearly instead
 		// of trying to build a prompt from a non-git label.
 		err = fmt.Errorf("%s job %d has no stored prompt (git_ref=%q); restart the daemon with 'roborev daemon restart'", job.JobType, job.ID, job.GitRef)
+	} else if job.DiffContent != nil && gitpkg.IsRange(job.GitRef) {
+		// Filtered range - use pre-captured per-commit diffs
+		reviewPrompt, err = builder.BuildRangeWithDiff(job.RepoPath, job.GitRef, *job.DiffContent, job.RepoID, cfg.ReviewContextCount, job.Agent, job.ReviewType)
+	} else if job.DiffContent != nil && job.CommitID != nil {
+		// Supplied (e.g. hand-edited) diff, with its commit for context
+		var commit *storage.Commit
+		if commit, err = wp.db.GetCommitByID(*job.CommitID); err == nil {
+			reviewPrompt, err = builder.BuildWithDiff(job.RepoPath, commit.SHA, *job.DiffContent, job.RepoID, cfg.ReviewContextCount, job.Agent, job.ReviewType)
+		}
 	} else if job.DiffContent != nil {
 		// Dirty job - use pre-captured diff
-		reviewPrompt, err = wp.promptBuilder.BuildDirty(job.RepoPath, *job.DiffContent, job.RepoID, cfg.ReviewContextCount, job.Agent, job.ReviewType)
+		reviewPrompt, err = builder.BuildDirty(job.RepoPath, *job.DiffContent, job.RepoID, cfg.ReviewContextCount, job.Agent, job.ReviewType)
 	} else {
 		// Normal job - build prompt from git ref
-		reviewPrompt, err = wp.promptBuilder.Build(job.RepoPath, job.GitRef, job.RepoID, cfg.ReviewContextCount, job.Agent, job.ReviewType)
+		reviewPrompt, err = builder.Build(job.RepoPath, job.GitRef, job.RepoID, cfg.ReviewContextCount, job.Agent, job.ReviewType)
 	}
 	if err != nil {
 		log.Printf("[%s] Error building prompt: %v", workerID, err)
@@ -385,8 +738,15 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 		log.Printf("[%s] Error saving prompt: %v", workerID, err)
 	}
 
-	// Get the agent (falls back to available agent if preferred not installed)
-	baseAgent, err := agent.GetAvailable(job.Agent)
+	// Get the agent (falls back to available agent if preferred not installed).
+	// A missing agent is replaced by the first installed fallback_agents
+	// entry before any built-in substitute.
+	fallbacks := wp.fallbackAgents(job, cfg)
+	preferred := job.Agent
+	if !agent.IsAvailable(preferred) && len(fallbacks) > 0 {
+		preferred, fallbacks = fallbacks[0], fallbacks[1:]
+	}
+	baseAgent, err := agent.GetAvailable(preferred)
 	if err != nil {
 		log.Printf("[%s] Error getting agent: %v", workerID, err)
 		wp.failOrRetryAgent(workerID, job, job.Agent, fmt.Sprintf("get agent: %v", err))
@@ -407,6 +767,12 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 	if agentName != job.Agent {
 		log.Printf("[%s] Agent %s not available, using %s", workerID, job.Agent, agentName)
 	}
+	// primaryAgent is stored as the review's agent; agentName becomes the
+	// agent that actually produced it if the run falls back below.
+	primaryAgent := agentName
+	if preferred != job.Agent {
+		primaryAgent = agent.CanonicalName(job.Agent)
+	}
 
 	// Broadcast started event
 	wp.broadcaster.Broadcast(Event{
@@ -432,12 +798,18 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 	if logFile != nil {
 		defer logFile.Close()
 	}
-	var agentOutput io.Writer = outputWriter
+	var logWriter *safeWriter
 	if logFile != nil {
-		agentOutput = io.MultiWriter(
-			outputWriter, &safeWriter{w: logFile},
-		)
+		logWriter = &safeWriter{w: logFile}
+	}
+	// streamOutput tees an attempt's output to w and the log
+	streamOutput := func(w io.Writer) *attemptWriter {
+		if logWriter != nil {
+			w = io.MultiWriter(w, logWriter)
+		}
+		return &attemptWriter{w: w}
 	}
+	agentOutput := streamOutput(outputWriter)
 
 	// For fix jobs, create an isolated worktree to run the agent in.
 	// The agent modifies files in the worktree; afterwards we capture the diff as a patch.
@@ -459,7 +831,35 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 	// Run the review
 	log.Printf("[%s] Running %s %sreview (job %d)...",
 		workerID, agentName, rtTag, job.ID)
-	output, err := a.Review(ctx, reviewRepoPath, job.GitRef, reviewPrompt, agentOutput)
+	reviewCtx, usage := agent.WithUsageRecorder(ctx)
+	output, err := a.Review(reviewCtx, reviewRepoPath, job.GitRef, reviewPrompt, agentOutput)
+	// An agent that couldn't run at all hands the job to the next fallback
+	// agent within this attempt. Agents that produced any output before
+	// failing, or returned a FAIL verdict, go through the usual retry path
+	// instead.
+	for err != nil && ctx.Err() == nil && isAgentUnavailable(err) && output == "" && !agentOutput.producedOutput(err) && len(fallbacks) > 0 {
+		next, getErr := agent.Get(fallbacks[0])
+		fallbacks = fallbacks[1:]
+		if getErr != nil {
+			continue
+		}
+		log.Printf("[%s] Agent %s unavailable on job %d, falling back to %s: %v",
+			workerID, agentName, job.ID, next.Name(), err)
+		// The job's model is specific to its agent, so fallbacks use their default
+		a = next.WithReasoning(reasoningLevel).WithAgentic(job.Agentic)
+		agentName = a.Name()
+		// Start the fallback with empty output, normalized for its agent
+		wp.outputBuffers.ResetJob(job.ID)
+		outputWriter = wp.outputBuffers.Writer(job.ID, GetNormalizer(agentName))
+		resetJobLog(logFile)
+		agentOutput = streamOutput(outputWriter)
+		output, err = a.Review(reviewCtx, reviewRepoPath, job.GitRef, reviewPrompt, agentOutput)
+	}
+	if err != nil && ctx.Err() == nil && logWriter != nil {
+		// Agent errors carry the tail of its stderr, which isn't part of
+		// the streamed output; keep it with the log for `roborev log`
+		fmt.Fprintf(logWriter, "\nagent error: %v\n", err)
+	}
 	if err != nil {
 		// Check if this was a cancellation
 		if ctx.Err() == context.Canceled {
@@ -476,6 +876,13 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 			})
 			return // Job already marked as canceled in DB, nothing more to do
 		}
+		// The deadline kills the agent process; report the timeout rather
+		// than the signal it died from
+		if ctx.Err() == context.DeadlineExceeded {
+			log.Printf("[%s] Job %d timed out after %v", workerID, job.ID, timeout)
+			wp.failOrRetryAgent(workerID, job, agentName, fmt.Sprintf("agent %s timed out after %gs", agentName, timeout.Seconds()))
+			return
+		}
 		log.Printf("[%s] Agent error on job %d: %v",
 			workerID, job.ID, err)
 		wp.failOrRetryAgent(workerID, job, agentName, fmt.Sprintf("agent: %v", err))
@@ -510,15 +917,19 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 		return
 	}
 
+	// Verdicts honor the repo's fail threshold, blocking keywords, ignore
+	// patterns, and ignore rules layered over the global config.
+	policy := storage.PolicyFromGating(config.ResolveVerdictGating(job.RepoPath, cfg))
+
 	// Store the result (use actual agent name, not requested).
 	// CompleteJob/CompleteFixJob is a no-op (returns nil) if the job was
 	// canceled between agent finish and now.
 	if job.IsFixJob() {
-		if err := wp.db.CompleteFixJob(job.ID, agentName, reviewPrompt, output, fixPatch); err != nil {
+		if err := wp.db.CompleteFixJobByAgent(job.ID, primaryAgent, agentName, reviewPrompt, output, fixPatch); err != nil {
 			log.Printf("[%s] Error storing fix review: %v", workerID, err)
 			return
 		}
-	} else if err := wp.db.CompleteJob(job.ID, agentName, reviewPrompt, output); err != nil {
+	} else if err := wp.db.CompleteJobByAgent(job.ID, primaryAgent, agentName, reviewPrompt, output, policy, tokenUsage(*usage)); err != nil {
 		log.Printf("[%s] Error storing review: %v", workerID, err)
 		return
 	}
@@ -545,6 +956,17 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 	log.Printf("[%s] Completed job %d %s %sreview/%s",
 		workerID, job.ID, job.RepoName, rtTag, agentName)
 
+	// Cap stored reviews per commit now that this one is in. Pruning
+	// failures are logged but don't affect the completed job.
+	if job.CommitID != nil && cfg.MaxReviewsPerCommit > 0 {
+		pruned, err := wp.db.PruneCommitReviews(*job.CommitID, cfg.MaxReviewsPerCommit)
+		if err != nil {
+			log.Printf("[%s] Warning: failed to prune old reviews for job %d: %v", workerID, job.ID, err)
+		} else if pruned.Reviews > 0 {
+			log.Printf("[%s] Pruned %d old review(s) of %s", workerID, pruned.Reviews, job.GitRef)
+		}
+	}
+
 	if wp.activityLog != nil {
 		wp.activityLog.Log(
 			"job.completed", "worker",
@@ -558,8 +980,9 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 		)
 	}
 
-	// Broadcast completion event
-	verdict := storage.ParseVerdict(output)
+	// Broadcast completion event with the verdict that was stored, which
+	// includes low-confidence handling and the output prefix
+	verdict := wp.storedVerdict(workerID, job.ID, output, policy)
 	wp.broadcaster.Broadcast(Event{
 		Type:     "review.completed",
 		TS:       time.Now(),
@@ -567,14 +990,93 @@ func (wp *WorkerPool) processJob(workerID string, job *storage.ReviewJob) {
 		Repo:     job.RepoPath,
 		RepoName: job.RepoName,
 		SHA:      job.GitRef,
+		Subject:  job.CommitSubject,
 		Agent:    agentName,
 		Verdict:  verdict,
 		Findings: output,
+		JobType:  job.JobType,
+	})
+}
+
+// storedVerdict returns the verdict stored for a completed job, falling back
+// to applying policy to output when it can't be read.
+func (wp *WorkerPool) storedVerdict(workerID string, jobID int64, output string, policy storage.VerdictPolicy) string {
+	review, err := wp.db.GetReviewByJobID(jobID)
+	if err != nil {
+		log.Printf("[%s] Error loading stored verdict for job %d: %v", workerID, jobID, err)
+		return policy.Verdict(output)
+	}
+	if review.Job == nil || review.Job.Verdict == nil {
+		return policy.Verdict(output)
+	}
+	return *review.Job.Verdict
+}
+
+// completeFromCache completes job with a copy of the newest review of an
+// identical diff and broadcasts its completion, reporting whether it did.
+// Errors are logged and leave the job to run the agent as usual.
+func (wp *WorkerPool) completeFromCache(workerID string, job *storage.ReviewJob) bool {
+	sourceID, err := wp.db.FindCachedReview(job)
+	if err != nil {
+		log.Printf("[%s] Error looking up cached review for job %d: %v", workerID, job.ID, err)
+		return false
+	}
+	if sourceID == 0 {
+		return false
+	}
+	if ok, err := wp.db.CompleteJobFromCache(job.ID, sourceID); err != nil {
+		log.Printf("[%s] Error copying review of job %d into job %d: %v", workerID, sourceID, job.ID, err)
+		return false
+	} else if !ok {
+		return false
+	}
+	log.Printf("[%s] Completed job %d %s from the review of job %d (identical diff)",
+		workerID, job.ID, job.RepoName, sourceID)
+
+	review, err := wp.db.GetReviewByJobID(job.ID)
+	if err != nil {
+		log.Printf("[%s] Error loading cached review for job %d: %v", workerID, job.ID, err)
+		return true
+	}
+	var verdict string
+	if review.Job != nil && review.Job.Verdict != nil {
+		verdict = *review.Job.Verdict
+	}
+
+	if wp.activityLog != nil {
+		wp.activityLog.Log(
+			"job.completed", "worker",
+			fmt.Sprintf("job %d completed by %s from cache", job.ID, workerID),
+			map[string]string{
+				"job_id":      fmt.Sprintf("%d", job.ID),
+				"worker":      workerID,
+				"agent":       review.Agent,
+				"cached_from": fmt.Sprintf("%d", sourceID),
+			},
+		)
+	}
+
+	wp.broadcaster.Broadcast(Event{
+		Type:     "review.completed",
+		TS:       time.Now(),
+		JobID:    job.ID,
+		Repo:     job.RepoPath,
+		RepoName: job.RepoName,
+		SHA:      job.GitRef,
+		Subject:  job.CommitSubject,
+		Agent:    review.Agent,
+		Verdict:  verdict,
+		Findings: review.Output,
+		JobType:  job.JobType,
 	})
+	return true
 }
 
 // failOrRetry attempts to retry the job, or marks it as failed if max retries reached.
 // This is used for non-agent errors (e.g., prompt build failures) where switching agents won't help.
+// Each retry is delayed with exponential backoff and errorMsg is kept in the
+// job's retry history. Reviews whose verdict is FAIL complete normally and
+// never come through here, so they are not retried.
 func (wp *WorkerPool) failOrRetry(workerID string, job *storage.ReviewJob, agentName string, errorMsg string) {
 	wp.failOrRetryInner(workerID, job, agentName, errorMsg, false)
 }
@@ -598,7 +1100,17 @@ func (wp *WorkerPool) failOrRetryInner(workerID string, job *storage.ReviewJob,
 		return
 	}
 
-	retried, err := wp.db.RetryJob(job.ID, workerID, maxRetries)
+	// Authentication errors fail identically on every attempt, so they
+	// skip retries and go straight to failover or failure.
+	if agentError && isAuthError(agentName, errorMsg) {
+		wp.failAuth(workerID, job, agentName, errorMsg)
+		return
+	}
+
+	maxRetries := wp.maxRetries()
+	retryCount, _ := wp.db.GetJobRetryCount(job.ID)
+	delay := wp.retryDelay(retryCount)
+	retried, err := wp.db.RetryJobWithBackoff(job.ID, workerID, maxRetries, errorMsg, delay)
 	if err != nil {
 		log.Printf("[%s] Error retrying job: %v", workerID, err)
 		if updated, fErr := wp.db.FailJob(job.ID, workerID, errorMsg); fErr != nil {
@@ -614,9 +1126,8 @@ func (wp *WorkerPool) failOrRetryInner(workerID string, job *storage.ReviewJob,
 	}
 
 	if retried {
-		retryCount, _ := wp.db.GetJobRetryCount(job.ID)
-		log.Printf("[%s] Job %d %s queued for retry (%d/%d)",
-			workerID, job.ID, job.RepoName, retryCount, maxRetries)
+		log.Printf("[%s] Job %d %s queued for retry (%d/%d) in %v",
+			workerID, job.ID, job.RepoName, retryCount+1, maxRetries, delay)
 	} else {
 		// Retries exhausted -- attempt failover to backup agent if this is an agent error
 		if agentError {
@@ -677,6 +1188,52 @@ func (wp *WorkerPool) resolveBackupAgent(job *storage.ReviewJob) string {
 	return resolved.Name()
 }
 
+// fallbackAgents returns the canonical names of the job's fallback_agents
+// that are installed, not cooling down, and not the job's own agent, in
+// configured order.
+func (wp *WorkerPool) fallbackAgents(job *storage.ReviewJob, cfg *config.Config) []string {
+	primary := agent.CanonicalName(job.Agent)
+	seen := map[string]bool{primary: true}
+	var names []string
+	for _, name := range config.ResolveFallbackAgents(job.RepoPath, cfg) {
+		name = agent.CanonicalName(name)
+		if seen[name] || !agent.IsAvailable(name) || wp.isAgentCoolingDown(name) {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// unavailablePatterns are agent error substrings meaning the agent's API
+// was down or unreachable, so the review never started.
+var unavailablePatterns = []string{
+	"executable file not found",
+	"internal server error",
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+	"overloaded",
+	"connection refused",
+	"no such host",
+}
+
+// isAgentUnavailable reports whether an agent error means the agent never
+// got to review: its binary couldn't be started or its API was down.
+func isAgentUnavailable(err error) bool {
+	if errors.Is(err, exec.ErrNotFound) {
+		return true
+	}
+	lower := strings.ToLower(err.Error())
+	for _, p := range unavailablePatterns {
+		if strings.Contains(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // broadcastFailed sends a review.failed event for a job
 func (wp *WorkerPool) broadcastFailed(job *storage.ReviewJob, agentName, errorMsg string) {
 	wp.broadcaster.Broadcast(Event{
@@ -719,6 +1276,110 @@ func isQuotaError(errMsg string) bool {
 	return false
 }
 
+// authErrorPatterns are lowercase substrings that agent CLIs print when
+// their credentials are missing, invalid, or expired, keyed by canonical
+// agent name. Generic HTTP text such as "401" or "unauthorized" is left
+// out: a proxy or a tool the agent runs can print it too, and those
+// errors should go through normal retries.
+var authErrorPatterns = map[string][]string{
+	"claude-code": {
+		"invalid api key",
+		"please run /login",
+		"oauth token has expired",
+		"oauth token revoked",
+		"authentication_error",
+	},
+	"codex": {
+		"incorrect api key provided",
+		"invalid_api_key",
+		"not logged in",
+		"token_expired",
+		"refresh token has expired",
+	},
+	"gemini": {
+		"api key not valid",
+		"api_key_invalid",
+		"unauthenticated",
+	},
+	"copilot": {
+		"no authentication information found",
+		"not authenticated",
+	},
+	"cursor": {
+		"not logged in",
+		"cursor-agent login",
+	},
+	"opencode": {
+		"no credentials",
+		"opencode auth login",
+		"authentication_error",
+	},
+	"droid": {
+		"not logged in",
+		"factory_api_key",
+	},
+}
+
+// isAuthError returns true if the error message shows the agent rejected
+// its credentials (case-insensitive), using the agent's own patterns.
+func isAuthError(agentName, errMsg string) bool {
+	lower := strings.ToLower(errMsg)
+	for _, p := range authErrorPatterns[agent.CanonicalName(agentName)] {
+		if strings.Contains(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// failAuth handles a job whose agent rejected its credentials. The job
+// fails over to the backup agent when one is usable; otherwise it fails
+// with an auth-prefixed error and, if pause_on_auth_failure is set, the
+// queue pauses so queued jobs aren't spent failing the same way.
+func (wp *WorkerPool) failAuth(
+	workerID string, job *storage.ReviewJob,
+	agentName, errorMsg string,
+) {
+	backupAgent := wp.resolveBackupAgent(job)
+	if backupAgent != "" && !wp.isAgentCoolingDown(backupAgent) {
+		failedOver, err := wp.db.FailoverJob(job.ID, workerID, backupAgent)
+		if err != nil {
+			log.Printf("[%s] Error attempting failover for job %d: %v",
+				workerID, job.ID, err)
+		}
+		if failedOver {
+			log.Printf("[%s] Job %d failing over from %s to %s (authentication): %s",
+				workerID, job.ID, agentName, backupAgent, errorMsg)
+			return
+		}
+	}
+
+	authMsg := review.AuthErrorPrefix + errorMsg
+	updated, err := wp.db.FailJob(job.ID, workerID, authMsg)
+	if err != nil {
+		log.Printf("[%s] Error failing job %d: %v", workerID, job.ID, err)
+		return
+	}
+	if !updated {
+		return
+	}
+	log.Printf("[%s] Job %d failed (agent %s authentication failed)",
+		workerID, job.ID, agentName)
+	wp.broadcastFailed(job, agentName, authMsg)
+	if wp.errorLog != nil {
+		wp.errorLog.LogError("worker",
+			fmt.Sprintf("job %d failed (authentication): %s", job.ID, errorMsg),
+			job.ID)
+	}
+	wp.logJobFailed(job.ID, workerID, agentName, authMsg)
+
+	if wp.cfgGetter.Config().PauseOnAuthFailure {
+		wp.pauseQueue(fmt.Sprintf("agent %s authentication failed (job %d)", agentName, job.ID))
+		log.Printf("[%s] Queue paused after authentication failure; run 'roborev daemon resume' once credentials are fixed",
+			workerID)
+	}
+}
+
 // parseQuotaCooldown extracts a Go-format duration from a "reset after
 // <dur>" substring. Returns fallback if not found or unparseable.
 func parseQuotaCooldown(errMsg string, fallback time.Duration) time.Duration {
@@ -904,3 +1565,18 @@ func (wp *WorkerPool) markCompactSourceJobs(workerID string, jobID int64) error
 
 	return nil
 }
+
+// jobTimeout returns how long a job may run: the agent's timeout from
+// config.ResolveAgentTimeout if set, else the per-repo or global
+// job_timeout_minutes (default 30 minutes). A per-repo job_timeout_minutes
+// overrides the global agent_timeout, but not agent-specific timeouts.
+func jobTimeout(cfg *config.Config, job *storage.ReviewJob) time.Duration {
+	d, err := config.ResolveAgentTimeout(job.RepoPath, cfg, job.Agent, job.Reasoning)
+	if err != nil {
+		log.Printf("Job %d: %v; using job_timeout_minutes", job.ID, err)
+	}
+	if d > 0 {
+		return d
+	}
+	return time.Duration(config.ResolveJobTimeout(job.RepoPath, cfg)) * time.Minute
+}
@@ -1,11 +1,15 @@
 package daemon
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/roborev-dev/roborev/internal/agent"
 	"github.com/roborev-dev/roborev/internal/config"
 	"github.com/roborev-dev/roborev/internal/review"
 	"github.com/roborev-dev/roborev/internal/storage"
@@ -513,6 +517,361 @@ func TestProcessJob_CooldownResolvesAlias(t *testing.T) {
 	}
 }
 
+func TestProcessJob_BroadcastsStoredVerdict(t *testing.T) {
+	lowConfidence := agent.NewTestAgent()
+	lowConfidence.Delay = 0
+	lowConfidence.Output = "No issues found.\nConfidence: low"
+	agent.Register(lowConfidence)
+	t.Cleanup(func() { agent.Register(agent.NewTestAgent()) })
+
+	tc := newWorkerTestContext(t, 1)
+	if err := os.WriteFile(filepath.Join(tc.TmpDir, ".roborev.toml"), []byte(`low_confidence_policy = "needs_human"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, events := tc.Broadcaster.Subscribe("")
+	job := tc.createAndClaimJob(t, testutil.GetHeadSHA(t, tc.TmpDir), "test-worker")
+	job, err := tc.DB.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	tc.Pool.processJob("test-worker", job)
+
+	review, err := tc.DB.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID: %v", err)
+	}
+	if review.Job == nil || review.Job.Verdict == nil || *review.Job.Verdict != "F" {
+		t.Fatalf("stored verdict = %+v, want F", review.Job)
+	}
+	for {
+		select {
+		case e := <-events:
+			if e.Type != "review.completed" {
+				continue
+			}
+			if e.Verdict != "F" {
+				t.Errorf("broadcast verdict = %q, want the stored F", e.Verdict)
+			}
+			return
+		default:
+			t.Fatal("expected a review.completed event")
+		}
+	}
+}
+
+func TestProcessJob_RepoFailThresholdOverridesVerdict(t *testing.T) {
+	findingAgent := agent.NewTestAgent()
+	findingAgent.Delay = 0
+	findingAgent.Output = "- Medium: unchecked error from Close"
+	agent.Register(findingAgent)
+	t.Cleanup(func() { agent.Register(agent.NewTestAgent()) })
+
+	tc := newWorkerTestContext(t, 1)
+
+	// Repo with a stricter-than-default threshold: medium findings pass
+	if err := os.WriteFile(filepath.Join(tc.TmpDir, ".roborev.toml"), []byte(`fail_threshold = "high"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	overridden := tc.createAndClaimJob(t, testutil.GetHeadSHA(t, tc.TmpDir), "test-worker")
+	overridden, err := tc.DB.GetJobByID(overridden.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	tc.Pool.processJob("test-worker", overridden)
+
+	// Repo without config uses the global default: any finding fails
+	otherDir := t.TempDir()
+	testutil.InitTestGitRepo(t, otherDir)
+	otherRepo, err := tc.DB.GetOrCreateRepo(otherDir)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo: %v", err)
+	}
+	otherSHA := testutil.GetHeadSHA(t, otherDir)
+	commit, err := tc.DB.GetOrCreateCommit(otherRepo.ID, otherSHA, "A", "S", time.Now())
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit: %v", err)
+	}
+	if _, err := tc.DB.EnqueueJob(storage.EnqueueOpts{RepoID: otherRepo.ID, CommitID: commit.ID, GitRef: otherSHA, Agent: "test"}); err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	other, err := tc.DB.ClaimJob("test-worker")
+	if err != nil || other == nil {
+		t.Fatalf("ClaimJob: err=%v, job=%v", err, other)
+	}
+	tc.Pool.processJob("test-worker", other)
+
+	jobs, err := tc.DB.ListJobs("", "", 10, 0)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	for _, c := range []struct {
+		jobID int64
+		want  string
+	}{
+		{overridden.ID, "P"},
+		{other.ID, "F"},
+	} {
+		var got *storage.ReviewJob
+		for i := range jobs {
+			if jobs[i].ID == c.jobID {
+				got = &jobs[i]
+			}
+		}
+		if got == nil || got.Status != storage.JobStatusDone {
+			t.Fatalf("job %d not completed: %+v", c.jobID, got)
+		}
+		if got.Verdict == nil || *got.Verdict != c.want {
+			t.Errorf("job %d verdict = %v, want %s", c.jobID, got.Verdict, c.want)
+		}
+	}
+}
+
+func TestProcessJob_DedupIdenticalDiff(t *testing.T) {
+	reviewer := agent.NewTestAgent()
+	reviewer.Delay = 0
+	reviewer.Output = "- Medium: unchecked error from Close"
+	agent.Register(reviewer)
+	t.Cleanup(func() { agent.Register(agent.NewTestAgent()) })
+
+	tc := newWorkerTestContext(t, 1)
+	sha := testutil.GetHeadSHA(t, tc.TmpDir)
+	commit, err := tc.DB.GetOrCreateCommit(tc.Repo.ID, sha, "A", "S", time.Now())
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit: %v", err)
+	}
+	process := func() *storage.ReviewJob {
+		t.Helper()
+		if _, err := tc.DB.EnqueueJob(storage.EnqueueOpts{
+			RepoID: tc.Repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "test",
+			DiffHash: storage.HashDiff("diff --git a/main.go b/main.go", ""),
+		}); err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		claimed, err := tc.DB.ClaimJob("test-worker")
+		if err != nil || claimed == nil {
+			t.Fatalf("ClaimJob: err=%v, job=%v", err, claimed)
+		}
+		tc.Pool.processJob("test-worker", claimed)
+		job, err := tc.DB.GetJobByID(claimed.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID: %v", err)
+		}
+		if job.Status != storage.JobStatusDone {
+			t.Fatalf("job %d status = %s, want done", job.ID, job.Status)
+		}
+		return job
+	}
+
+	first := process()
+	firstReview, err := tc.DB.GetReviewByJobID(first.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID: %v", err)
+	}
+
+	// A changed agent output makes a copied review recognizable
+	reviewer.Output = "No issues found."
+	_, events := tc.Broadcaster.Subscribe("")
+	cached := process()
+	if cached.CachedFromJobID == nil || *cached.CachedFromJobID != first.ID {
+		t.Fatalf("CachedFromJobID = %v, want %d", cached.CachedFromJobID, first.ID)
+	}
+	if review, err := tc.DB.GetReviewByJobID(cached.ID); err != nil || review.Output != firstReview.Output {
+		t.Errorf("expected the first review to be copied, got %v, %v", review, err)
+	}
+	select {
+	case e := <-events:
+		if e.Type != "review.completed" || e.JobID != cached.ID || e.Verdict != "F" {
+			t.Errorf("unexpected event %+v", e)
+		}
+	default:
+		t.Error("expected a review.completed event for the cache hit")
+	}
+
+	// review.dedup = false in the repo config runs the agent again
+	if err := os.WriteFile(filepath.Join(tc.TmpDir, ".roborev.toml"), []byte("[review]\ndedup = false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fresh := process()
+	if fresh.CachedFromJobID != nil {
+		t.Errorf("CachedFromJobID = %d, want a fresh review with dedup disabled", *fresh.CachedFromJobID)
+	}
+	if review, err := tc.DB.GetReviewByJobID(fresh.ID); err != nil || review.Output == firstReview.Output {
+		t.Errorf("expected a fresh review, got %v, %v", review, err)
+	}
+}
+
+func TestProcessJob_CustomCommandAgent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test that requires Unix shell scripts")
+	}
+
+	// A fake in-house reviewer: flags a finding when it receives a diff
+	script := filepath.Join(t.TempDir(), "mycli")
+	body := "#!/bin/sh\n" +
+		"# usage: mycli review --diff FILE --out FILE\n" +
+		"if grep -q '^diff --git' \"$3\"; then\n" +
+		"  echo '- High: leaked file handle in main.go' > \"$5\"\n" +
+		"fi\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Agents = map[string]config.CustomAgentConfig{
+		"mycli": {Command: script + " review --diff {{.DiffFile}} --out {{.OutputFile}}"},
+	}
+	if err := agent.SetCustomAgents(cfg.CustomAgentCommands()); err != nil {
+		t.Fatalf("SetCustomAgents: %v", err)
+	}
+	t.Cleanup(func() { _ = agent.SetCustomAgents(nil) })
+
+	tc := newWorkerTestContext(t, 1)
+	sha := testutil.GetHeadSHA(t, tc.TmpDir)
+	commit, err := tc.DB.GetOrCreateCommit(tc.Repo.ID, sha, "Author", "Subject", time.Now())
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit: %v", err)
+	}
+	if _, err := tc.DB.EnqueueJob(storage.EnqueueOpts{RepoID: tc.Repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "mycli"}); err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	job, err := tc.DB.ClaimJob("test-worker")
+	if err != nil || job == nil {
+		t.Fatalf("ClaimJob: err=%v, job=%v", err, job)
+	}
+	tc.Pool.processJob("test-worker", job)
+
+	review, err := tc.DB.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID: %v", err)
+	}
+	if review.Agent != "mycli" || review.Job.Status != storage.JobStatusDone {
+		t.Fatalf("review by %q with status %s, want mycli done", review.Agent, review.Job.Status)
+	}
+	if !strings.Contains(review.Output, "leaked file handle") {
+		t.Errorf("output = %q, want the script's review", review.Output)
+	}
+	if review.Job.Verdict == nil || *review.Job.Verdict != "F" {
+		t.Errorf("verdict = %v, want F", review.Job.Verdict)
+	}
+}
+
+func TestProcessJob_FallbackAgents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test that requires Unix shell scripts")
+	}
+
+	script := func(name, body string) string {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	cfg := config.DefaultConfig()
+	cfg.Agents = map[string]config.CustomAgentConfig{
+		"down":    {Command: script("down", "echo '503 Service Unavailable' >&2\nexit 1\n")},
+		"flaky":   {Command: script("flaky", "echo 'Reviewing main.go'\necho '503 Service Unavailable' >&2\nexit 1\n")},
+		"crashes": {Command: script("crashes", "echo 'panic: nil map' >&2\nexit 2\n")},
+		"strict":  {Command: script("strict", "echo '- High: unchecked error in main.go'\n")},
+		"backup":  {Command: script("backup", "echo 'No issues found.'\n")},
+	}
+	cfg.FallbackAgents = []string{"down", "backup"}
+	if err := agent.SetCustomAgents(cfg.CustomAgentCommands()); err != nil {
+		t.Fatalf("SetCustomAgents: %v", err)
+	}
+	t.Cleanup(func() { _ = agent.SetCustomAgents(nil) })
+
+	// run processes a job for agentName and returns it with its review,
+	// which is nil when the job didn't complete.
+	run := func(t *testing.T, agentName string) (*storage.ReviewJob, *storage.Review) {
+		t.Helper()
+		tc := newWorkerTestContext(t, 1)
+		tc.Pool.cfgGetter = NewStaticConfig(cfg)
+		sha := testutil.GetHeadSHA(t, tc.TmpDir)
+		commit, err := tc.DB.GetOrCreateCommit(tc.Repo.ID, sha, "Author", "Subject", time.Now())
+		if err != nil {
+			t.Fatalf("GetOrCreateCommit: %v", err)
+		}
+		if _, err := tc.DB.EnqueueJob(storage.EnqueueOpts{RepoID: tc.Repo.ID, CommitID: commit.ID, GitRef: sha, Agent: agentName}); err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		job, err := tc.DB.ClaimJob("test-worker")
+		if err != nil || job == nil {
+			t.Fatalf("ClaimJob: err=%v, job=%v", err, job)
+		}
+		tc.Pool.processJob("test-worker", job)
+
+		review, err := tc.DB.GetReviewByJobID(job.ID)
+		if err == nil {
+			return review.Job, review
+		}
+		updated, err := tc.DB.GetJobByID(job.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID: %v", err)
+		}
+		return updated, nil
+	}
+
+	t.Run("unavailable agent falls back", func(t *testing.T) {
+		job, review := run(t, "down")
+		if review == nil {
+			t.Fatalf("job %s without a review: %s", job.Status, job.Error)
+		}
+		if review.Agent != "down" || review.EffectiveAgent != "backup" {
+			t.Errorf("review agent=%q effective=%q, want down and backup", review.Agent, review.EffectiveAgent)
+		}
+		if review.ProducedBy() != "backup" {
+			t.Errorf("ProducedBy() = %q, want backup", review.ProducedBy())
+		}
+		// The fallback starts a clean log
+		data, err := ReadJobLog(job.ID)
+		if err != nil {
+			t.Fatalf("ReadJobLog: %v", err)
+		}
+		if strings.Contains(string(data), "503") || !strings.Contains(string(data), "No issues found.") {
+			t.Errorf("job log = %q, want only the backup agent's output", data)
+		}
+	})
+
+	t.Run("agent that produced output does not fall back", func(t *testing.T) {
+		job, review := run(t, "flaky")
+		if review != nil {
+			t.Fatalf("unexpected review by %s", review.ProducedBy())
+		}
+		if job.Status != storage.JobStatusQueued {
+			t.Errorf("status = %s, want queued for retry", job.Status)
+		}
+	})
+
+	t.Run("FAIL verdict does not fall back", func(t *testing.T) {
+		job, review := run(t, "strict")
+		if review == nil || review.EffectiveAgent != "strict" {
+			t.Fatalf("review = %+v, want one by strict", review)
+		}
+		if job.Verdict == nil || *job.Verdict != "F" {
+			t.Errorf("verdict = %v, want F", job.Verdict)
+		}
+	})
+
+	t.Run("agent that ran and failed is retried, not replaced", func(t *testing.T) {
+		job, review := run(t, "crashes")
+		if review != nil {
+			t.Fatalf("unexpected review by %s", review.ProducedBy())
+		}
+		if job.Status != storage.JobStatusQueued {
+			t.Errorf("status = %s, want queued for retry", job.Status)
+		}
+		// The failure is kept in the job log for `roborev log`
+		data, err := ReadJobLog(job.ID)
+		if err != nil {
+			t.Fatalf("ReadJobLog: %v", err)
+		}
+		if !strings.Contains(string(data), "agent error:") || !strings.Contains(string(data), "panic: nil map") {
+			t.Errorf("job log = %q, want the agent error and its stderr", data)
+		}
+	})
+}
+
 func TestResolveBackupAgent_AliasMatchesPrimary(t *testing.T) {
 	// "claude" is an alias for "claude-code". If job.Agent is "claude"
 	// and backup resolves to "claude-code", they are the same agent.
@@ -643,6 +1002,291 @@ func TestFailOrRetryInner_NonQuotaStillRetries(t *testing.T) {
 	}
 }
 
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		agent  string
+		errMsg string
+		want   bool
+	}{
+		{"claude-code", "Invalid API key · Please run /login", true},
+		{"claude", "OAuth token has expired. Please obtain a new token", true},
+		{"codex", "stream error: 401 Unauthorized: Incorrect API key provided", true},
+		{"codex", "You are not logged in. Run codex login", true},
+		{"gemini", "API key not valid. Please pass a valid API key.", true},
+		{"copilot", "No authentication information found", true},
+		{"opencode", `{"type":"error","error":{"type":"authentication_error"}}`, true},
+		// Agent-specific patterns don't apply to other agents
+		{"gemini", "Please run /login", false},
+		// Bare HTTP status text could come from anything the agent ran
+		{"codex", "proxy returned 401 Unauthorized", false},
+		{"claude-code", "git fetch: authentication failed for remote", false},
+		// Other errors go through normal retries
+		{"claude-code", "connection reset by peer", false},
+		{"codex", "quota exceeded for model", false},
+		{"gemini", "HTTP 429: slow down", false},
+		{"claude-code", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.agent+"/"+tt.errMsg, func(t *testing.T) {
+			if got := isAuthError(tt.agent, tt.errMsg); got != tt.want {
+				t.Errorf("isAuthError(%q, %q) = %v, want %v",
+					tt.agent, tt.errMsg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailOrRetryInner_AuthFailsWithoutRetry(t *testing.T) {
+	tc := newWorkerTestContext(t, 1)
+	sha := testutil.GetHeadSHA(t, tc.TmpDir)
+	job := tc.createAndClaimJob(t, sha, "test-worker")
+
+	tc.Pool.failOrRetryInner("test-worker", job, "claude-code", "Invalid API key · Please run /login", true)
+
+	updated, err := tc.DB.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	if updated.Status != storage.JobStatusFailed {
+		t.Errorf("status=%q, want failed", updated.Status)
+	}
+	if !review.IsAuthFailure(updated.Error) {
+		t.Errorf("error=%q, want prefix %q", updated.Error, review.AuthErrorPrefix)
+	}
+	retryCount, _ := tc.DB.GetJobRetryCount(job.ID)
+	if retryCount != 0 {
+		t.Errorf("retry_count=%d, want 0 (auth errors should skip retries)", retryCount)
+	}
+	// pause_on_auth_failure is off by default
+	if reason := tc.Pool.QueuePauseReason(); reason != "" {
+		t.Errorf("queue paused (%q) without pause_on_auth_failure", reason)
+	}
+}
+
+func TestFailOrRetryInner_AuthPausesQueue(t *testing.T) {
+	tc := newWorkerTestContext(t, 1)
+	cfg := config.DefaultConfig()
+	cfg.PauseOnAuthFailure = true
+	tc.Pool = NewWorkerPool(tc.DB, NewStaticConfig(cfg), 1, tc.Broadcaster, nil, nil)
+
+	sha := testutil.GetHeadSHA(t, tc.TmpDir)
+	job := tc.createAndClaimJob(t, sha, "test-worker")
+	queued := tc.createJob(t, "queued-sha")
+
+	tc.Pool.failOrRetryInner("test-worker", job, "codex", "Not logged in. Run codex login", true)
+
+	reason := tc.Pool.QueuePauseReason()
+	if !strings.Contains(reason, "codex") {
+		t.Fatalf("QueuePauseReason() = %q, want a reason naming codex", reason)
+	}
+
+	// Paused workers leave queued jobs alone
+	tc.Pool.Start()
+	time.Sleep(500 * time.Millisecond)
+	tc.Pool.Stop()
+	if got, _ := tc.DB.GetJobByID(queued.ID); got.Status != storage.JobStatusQueued {
+		t.Errorf("queued job status=%q, want queued while paused", got.Status)
+	}
+
+	if !tc.Pool.ResumeQueue() {
+		t.Error("ResumeQueue() = false, want true")
+	}
+	if tc.Pool.QueuePauseReason() != "" {
+		t.Error("queue still paused after ResumeQueue")
+	}
+	if tc.Pool.ResumeQueue() {
+		t.Error("ResumeQueue() on an unpaused queue = true, want false")
+	}
+}
+
+func TestFailOrRetryInner_AuthFailsOverToBackup(t *testing.T) {
+	tc := newWorkerTestContext(t, 1)
+	cfg := config.DefaultConfig()
+	cfg.DefaultBackupAgent = "test"
+	cfg.PauseOnAuthFailure = true
+	tc.Pool = NewWorkerPool(tc.DB, NewStaticConfig(cfg), 1, tc.Broadcaster, nil, nil)
+
+	sha := testutil.GetHeadSHA(t, tc.TmpDir)
+	commit, err := tc.DB.GetOrCreateCommit(tc.Repo.ID, sha, "A", "S", time.Now())
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit: %v", err)
+	}
+	job, err := tc.DB.EnqueueJob(storage.EnqueueOpts{RepoID: tc.Repo.ID, CommitID: commit.ID, GitRef: sha, Agent: "codex"})
+	if err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	if claimed, err := tc.DB.ClaimJob("test-worker"); err != nil || claimed.ID != job.ID {
+		t.Fatalf("ClaimJob: err=%v, claimed=%v", err, claimed)
+	}
+	job.RepoPath = tc.TmpDir
+
+	tc.Pool.failOrRetryInner("test-worker", job, "codex", "Not logged in. Run codex login", true)
+
+	updated, err := tc.DB.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	if updated.Status != storage.JobStatusQueued || updated.Agent != "test" {
+		t.Errorf("status=%q agent=%q, want queued on the backup agent", updated.Status, updated.Agent)
+	}
+	if reason := tc.Pool.QueuePauseReason(); reason != "" {
+		t.Errorf("queue paused (%q) although the backup agent was usable", reason)
+	}
+}
+
+func TestWorkerPoolPauseResume(t *testing.T) {
+	tc := newWorkerTestContext(t, 1)
+	queued := tc.createJob(t, "queued-sha")
+
+	if paused, err := tc.Pool.Pause(); err != nil || !paused {
+		t.Fatalf("Pause() = %v, %v; want true", paused, err)
+	}
+	if paused, _ := tc.Pool.Pause(); paused {
+		t.Error("Pause() on a paused queue = true, want false")
+	}
+
+	// Paused workers leave queued jobs alone
+	tc.Pool.Start()
+	time.Sleep(500 * time.Millisecond)
+	tc.Pool.Stop()
+	if got, _ := tc.DB.GetJobByID(queued.ID); got.Status != storage.JobStatusQueued {
+		t.Errorf("queued job status=%q, want queued while paused", got.Status)
+	}
+
+	// A new pool, as after a daemon restart, remembers the pause
+	restarted := NewWorkerPool(tc.DB, NewStaticConfig(config.DefaultConfig()), 1, tc.Broadcaster, nil, nil)
+	if !restarted.PausedByUser() {
+		t.Fatal("pause not restored after restart")
+	}
+
+	if resumed, err := restarted.Resume(); err != nil || !resumed {
+		t.Fatalf("Resume() = %v, %v; want true", resumed, err)
+	}
+	if resumed, _ := restarted.Resume(); resumed {
+		t.Error("Resume() on an unpaused queue = true, want false")
+	}
+	if NewWorkerPool(tc.DB, NewStaticConfig(config.DefaultConfig()), 1, tc.Broadcaster, nil, nil).PausedByUser() {
+		t.Error("resume not persisted")
+	}
+
+	// Resume also lifts a pause after an authentication failure
+	restarted.pauseQueue("agent codex authentication failed")
+	if resumed, _ := restarted.Resume(); !resumed || restarted.QueuePauseReason() != "" {
+		t.Errorf("Resume() = %v with reason %q, want auth pause lifted", resumed, restarted.QueuePauseReason())
+	}
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	tc := newWorkerTestContext(t, 2)
+	// Keep workers idle so they only loop on the pause check
+	if _, err := tc.Pool.Pause(); err != nil {
+		t.Fatalf("Pause() failed: %v", err)
+	}
+
+	liveWorkers := func() int {
+		tc.Pool.workersMu.Lock()
+		defer tc.Pool.workersMu.Unlock()
+		return len(tc.Pool.liveWorkers)
+	}
+
+	// Before Start, Resize only changes how many workers Start spawns
+	if prev, err := tc.Pool.Resize(3); err != nil || prev != 2 {
+		t.Fatalf("Resize(3) before start = %d, %v; want 2", prev, err)
+	}
+	tc.Pool.Start()
+	if got := liveWorkers(); got != 3 {
+		t.Fatalf("started %d workers, want 3", got)
+	}
+
+	if prev, err := tc.Pool.Resize(5); err != nil || prev != 3 {
+		t.Fatalf("Resize(5) = %d, %v; want 3", prev, err)
+	}
+	if got, max := liveWorkers(), tc.Pool.MaxWorkers(); got != 5 || max != 5 {
+		t.Errorf("after growing: %d workers, MaxWorkers() = %d; want 5", got, max)
+	}
+
+	if prev, err := tc.Pool.Resize(1); err != nil || prev != 5 {
+		t.Fatalf("Resize(1) = %d, %v; want 5", prev, err)
+	}
+	if got, max := liveWorkers(), tc.Pool.MaxWorkers(); got != 1 || max != 1 {
+		t.Errorf("after shrinking: %d workers, MaxWorkers() = %d; want 1", got, max)
+	}
+
+	for _, n := range []int{0, -1, MaxWorkers + 1} {
+		if _, err := tc.Pool.Resize(n); err == nil {
+			t.Errorf("Resize(%d) succeeded, want error", n)
+		}
+	}
+
+	// Stop waits for retired workers as well as the remaining one
+	tc.Pool.Stop()
+	if _, err := tc.Pool.Resize(2); err == nil {
+		t.Error("Resize after Stop succeeded, want error")
+	}
+}
+
+func TestFailOrRetryInner_BacksOffAndRecordsErrors(t *testing.T) {
+	tc := newWorkerTestContext(t, 1)
+	sha := testutil.GetHeadSHA(t, tc.TmpDir)
+	job := tc.createAndClaimJob(t, sha, "test-worker")
+
+	tc.Pool.failOrRetryInner("test-worker", job, "codex", "agent: exit status 1", true)
+
+	updated, err := tc.DB.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	if updated.Status != storage.JobStatusQueued {
+		t.Errorf("status=%q, want queued (retry)", updated.Status)
+	}
+	if len(updated.RetryErrors) != 1 || updated.RetryErrors[0] != "agent: exit status 1" {
+		t.Errorf("RetryErrors=%q, want the agent error", updated.RetryErrors)
+	}
+
+	// The retry waits out its backoff before it can be claimed again
+	if claimed, err := tc.DB.ClaimJob("test-worker"); err != nil || claimed != nil {
+		t.Errorf("expected job to be backing off, claimed=%v err=%v", claimed, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	pool := NewWorkerPool(nil, NewStaticConfig(config.DefaultConfig()), 1, NewBroadcaster(), nil, nil)
+	tests := []struct {
+		retryCount int
+		want       time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{5, maxRetryDelay},
+		{100, maxRetryDelay},
+	}
+	for _, tt := range tests {
+		if got := pool.retryDelay(tt.retryCount); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.retryCount, got, tt.want)
+		}
+	}
+}
+
+func TestFailOrRetryInner_MaxRetriesZeroFails(t *testing.T) {
+	tc := newWorkerTestContext(t, 1)
+	cfg := config.DefaultConfig()
+	cfg.MaxRetries = 0
+	tc.Pool = NewWorkerPool(tc.DB, NewStaticConfig(cfg), 1, tc.Broadcaster, nil, nil)
+	sha := testutil.GetHeadSHA(t, tc.TmpDir)
+	job := tc.createAndClaimJob(t, sha, "test-worker")
+
+	tc.Pool.failOrRetryInner("test-worker", job, "codex", "agent: exit status 1", true)
+
+	updated, err := tc.DB.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	if updated.Status != storage.JobStatusFailed {
+		t.Errorf("status=%q, want failed with max_retries = 0", updated.Status)
+	}
+}
+
 func TestFailoverOrFail_FailsOverToBackup(t *testing.T) {
 	tc := newWorkerTestContext(t, 1)
 	sha := testutil.GetHeadSHA(t, tc.TmpDir)
@@ -718,6 +1362,7 @@ func TestFailOrRetryInner_RetryExhaustedBackupInCooldown(t *testing.T) {
 	tc.Pool = NewWorkerPool(
 		tc.DB, NewStaticConfig(cfg), 1, tc.Broadcaster, nil, nil,
 	)
+	tc.Pool.retryBaseDelay = 0
 
 	// Enqueue with agent "codex"
 	commit, err := tc.DB.GetOrCreateCommit(
@@ -742,7 +1387,7 @@ func TestFailOrRetryInner_RetryExhaustedBackupInCooldown(t *testing.T) {
 	job.RepoPath = tc.TmpDir
 
 	// Exhaust retries
-	for i := range maxRetries {
+	for i := range cfg.MaxRetries {
 		tc.Pool.failOrRetryInner(
 			"test-worker", job, "codex",
 			"connection reset", true,
@@ -789,6 +1434,7 @@ func TestFailOrRetryInner_RetryExhaustedFailsOverToBackup(t *testing.T) {
 	tc.Pool = NewWorkerPool(
 		tc.DB, NewStaticConfig(cfg), 1, tc.Broadcaster, nil, nil,
 	)
+	tc.Pool.retryBaseDelay = 0
 
 	// Enqueue with agent "codex"
 	commit, err := tc.DB.GetOrCreateCommit(
@@ -813,7 +1459,7 @@ func TestFailOrRetryInner_RetryExhaustedFailsOverToBackup(t *testing.T) {
 	job.RepoPath = tc.TmpDir
 
 	// Exhaust retries
-	for i := range maxRetries {
+	for i := range cfg.MaxRetries {
 		tc.Pool.failOrRetryInner(
 			"test-worker", job, "codex",
 			"connection reset", true,
@@ -943,3 +1589,174 @@ func TestResolveBackupAgent(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkerPoolRecoverStuckJobs(t *testing.T) {
+	tc := newWorkerTestContext(t, 1)
+
+	// Jobs left running by a crashed worker
+	stuck1 := tc.createAndClaimJob(t, "stuck-sha-1", "worker-0")
+	stuck2 := tc.createAndClaimJob(t, "stuck-sha-2", "worker-1")
+	// A job a live worker in this pool is processing
+	active := tc.createAndClaimJob(t, "active-sha", "worker-2")
+	tc.Pool.runningJobsMu.Lock()
+	tc.Pool.claimedJobs[active.ID] = struct{}{}
+	tc.Pool.runningJobsMu.Unlock()
+
+	recovered, running, err := tc.Pool.RecoverStuckJobs(false)
+	if err != nil {
+		t.Fatalf("RecoverStuckJobs: %v", err)
+	}
+	if recovered != 2 || running != 1 {
+		t.Errorf("expected 2 recovered and 1 active, got %d and %d", recovered, running)
+	}
+
+	for _, job := range []*storage.ReviewJob{stuck1, stuck2} {
+		got, err := tc.DB.GetJobByID(job.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID: %v", err)
+		}
+		if got.Status != storage.JobStatusQueued || got.WorkerID != "" {
+			t.Errorf("job %d: expected queued with no worker, got %s/%q", job.ID, got.Status, got.WorkerID)
+		}
+	}
+	got, _ := tc.DB.GetJobByID(active.ID)
+	if got.Status != storage.JobStatusRunning {
+		t.Errorf("active job should stay running, got %s", got.Status)
+	}
+}
+
+func TestJobTimeoutPerAgent(t *testing.T) {
+	repoPath := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.JobTimeoutMinutes = 20
+	cfg.AgentTimeouts = map[string]string{
+		"codex":          "45m",
+		"codex:thorough": "2h",
+		"gemini":         "not-a-duration",
+	}
+
+	tests := []struct {
+		name      string
+		agent     string
+		reasoning string
+		want      time.Duration
+	}{
+		{"agent and reasoning entry", "codex", "thorough", 2 * time.Hour},
+		{"agent entry", "codex", "fast", 45 * time.Minute},
+		{"no entry uses global", "claude-code", "thorough", 20 * time.Minute},
+		{"invalid entry uses global", "gemini", "fast", 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &storage.ReviewJob{ID: 1, Agent: tt.agent, Reasoning: tt.reasoning, RepoPath: repoPath}
+			if got := jobTimeout(cfg, job); got != tt.want {
+				t.Errorf("jobTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("agents table and agent_timeout", func(t *testing.T) {
+		cfg.Agents = map[string]config.CustomAgentConfig{"copilot": {Timeout: "10m"}}
+		cfg.AgentTimeout = "15m"
+		for agentName, want := range map[string]time.Duration{
+			"codex":       45 * time.Minute, // agent_timeouts wins
+			"copilot":     10 * time.Minute,
+			"claude-code": 15 * time.Minute,
+		} {
+			job := &storage.ReviewJob{ID: 1, Agent: agentName, Reasoning: "fast", RepoPath: repoPath}
+			if got := jobTimeout(cfg, job); got != want {
+				t.Errorf("%s: jobTimeout() = %v, want %v", agentName, got, want)
+			}
+		}
+	})
+
+	t.Run("repo job_timeout_minutes beats agent_timeout", func(t *testing.T) {
+		repoPath := t.TempDir()
+		if err := os.WriteFile(filepath.Join(repoPath, ".roborev.toml"), []byte("job_timeout_minutes = 60\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg.AgentTimeout = "15m"
+		for agentName, want := range map[string]time.Duration{
+			"codex":       45 * time.Minute, // agent-specific entries still win
+			"claude-code": 60 * time.Minute,
+		} {
+			job := &storage.ReviewJob{ID: 1, Agent: agentName, Reasoning: "fast", RepoPath: repoPath}
+			if got := jobTimeout(cfg, job); got != want {
+				t.Errorf("%s: jobTimeout() = %v, want %v", agentName, got, want)
+			}
+		}
+	})
+}
+
+func TestProcessJob_AgentTimeout(t *testing.T) {
+	slowAgent := agent.NewTestAgent()
+	slowAgent.Delay = 10 * time.Second
+	agent.Register(slowAgent)
+	t.Cleanup(func() { agent.Register(agent.NewTestAgent()) })
+
+	for _, tc := range []struct {
+		name       string
+		maxRetries int
+		want       storage.JobStatus
+	}{
+		{"fails without retries", 0, storage.JobStatusFailed},
+		{"requeues with retries", 1, storage.JobStatusQueued},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			wtc := newWorkerTestContext(t, 1)
+			cfg := config.DefaultConfig()
+			cfg.MaxRetries = tc.maxRetries
+			cfg.Agents = map[string]config.CustomAgentConfig{"test": {Timeout: "50ms"}}
+			wtc.Pool.cfgGetter = NewStaticConfig(cfg)
+
+			job := wtc.createAndClaimJob(t, testutil.GetHeadSHA(t, wtc.TmpDir), "test-worker")
+			job, err := wtc.DB.GetJobByID(job.ID)
+			if err != nil {
+				t.Fatalf("GetJobByID: %v", err)
+			}
+			start := time.Now()
+			wtc.Pool.processJob("test-worker", job)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("processJob took %v; the timeout was not enforced", elapsed)
+			}
+
+			got, err := wtc.DB.GetJobByID(job.ID)
+			if err != nil {
+				t.Fatalf("GetJobByID: %v", err)
+			}
+			if got.Status != tc.want {
+				t.Fatalf("status = %s, want %s", got.Status, tc.want)
+			}
+			msg := got.Error
+			if tc.want == storage.JobStatusQueued && len(got.RetryErrors) > 0 {
+				msg = got.RetryErrors[0]
+			}
+			if !strings.Contains(msg, "timed out after 0.05s") {
+				t.Errorf("error = %q, want a timeout message", msg)
+			}
+		})
+	}
+}
+
+func TestClaimLimitsSplitsPools(t *testing.T) {
+	tests := []struct {
+		name    string
+		fix     int
+		workers int
+		want    storage.ClaimLimits
+	}{
+		{"no fix limit", 0, 4, storage.ClaimLimits{}},
+		{"fix pool within workers", 1, 4, storage.ClaimLimits{Fix: 1, Review: 3}},
+		{"fix pool leaves a review worker", 8, 4, storage.ClaimLimits{Fix: 3, Review: 1}},
+		{"single worker is shared", 1, 1, storage.ClaimLimits{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.MaxFixWorkers = tt.fix
+			if got := claimLimits(cfg, tt.workers); got != tt.want {
+				t.Errorf("claimLimits(fix=%d, workers=%d) = %+v, want %+v", tt.fix, tt.workers, got, tt.want)
+			}
+		})
+	}
+}
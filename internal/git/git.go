@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"time"
@@ -73,6 +74,19 @@ func GetCommitInfo(repoPath, sha string) (*CommitInfo, error) {
 	}, nil
 }
 
+// revertTrailer matches the line git revert adds to a revert commit's message.
+var revertTrailer = regexp.MustCompile(`(?m)^This reverts commit ([0-9a-f]{40,64})\b`)
+
+// RevertedCommit returns the full SHA named by the "This reverts commit
+// <sha>" line git revert writes into body, or "" if body has none.
+func RevertedCommit(body string) string {
+	m := revertTrailer.FindStringSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
 // GetCurrentBranch returns the current branch name, or empty string if detached HEAD
 func GetCurrentBranch(repoPath string) string {
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
@@ -91,6 +105,34 @@ func GetCurrentBranch(repoPath string) string {
 	return branch
 }
 
+// BranchTip is a local branch and the commit it points to.
+type BranchTip struct {
+	Name string
+	SHA  string
+}
+
+// ListLocalBranches returns every local branch with its tip commit, sorted
+// by branch name.
+func ListLocalBranches(repoPath string) ([]BranchTip, error) {
+	cmd := exec.Command("git", "for-each-ref", "--sort=refname", "--format=%(objectname) %(refname:short)", "refs/heads")
+	cmd.Dir = repoPath
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref: %w", err)
+	}
+
+	var branches []BranchTip
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		sha, name, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		branches = append(branches, BranchTip{Name: name, SHA: sha})
+	}
+	return branches, nil
+}
+
 // LocalBranchName strips the "origin/" prefix from a branch name if present.
 // This normalizes branch names for comparison since GetDefaultBranch may return
 // "origin/main" while GetCurrentBranch returns "main".
@@ -335,6 +377,39 @@ func ParseRange(ref string) (start, end string, ok bool) {
 	return parts[0], parts[1], true
 }
 
+// ResolveRange resolves both endpoints of a "base..tip" or "base...tip"
+// range to full SHAs. For "base...tip" the base is the merge-base of the
+// two, matching what git diff compares. An empty endpoint means HEAD.
+func ResolveRange(repoPath, rangeRef string) (base, tip string, err error) {
+	start, end, symmetric := strings.Cut(rangeRef, "...")
+	if !symmetric {
+		var ok bool
+		start, end, ok = ParseRange(rangeRef)
+		if !ok {
+			return "", "", fmt.Errorf("not a range: %s", rangeRef)
+		}
+	}
+	if start == "" {
+		start = "HEAD"
+	}
+	if end == "" {
+		end = "HEAD"
+	}
+	tip, err = ResolveSHA(repoPath, end+"^{commit}")
+	if err != nil {
+		return "", "", fmt.Errorf("invalid end commit %q: %w", end, err)
+	}
+	if symmetric {
+		base, err = GetMergeBase(repoPath, start, tip)
+	} else {
+		base, err = ResolveSHA(repoPath, start+"^{commit}")
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("invalid start commit %q: %w", start, err)
+	}
+	return base, tip, nil
+}
+
 // GetRangeCommits returns all commits in a range (oldest first)
 func GetRangeCommits(repoPath, rangeRef string) ([]string, error) {
 	cmd := exec.Command("git", "log", "--format=%H", "--reverse", rangeRef)
@@ -356,6 +431,52 @@ func GetRangeCommits(repoPath, rangeRef string) ([]string, error) {
 	return commits, nil
 }
 
+// GetRangeCommitsByAuthor returns the non-merge commits in a range (oldest
+// first) whose author name or email contains author, ignoring case.
+func GetRangeCommitsByAuthor(repoPath, rangeRef, author string) ([]string, error) {
+	cmd := exec.Command("git", "log", "--format=%H", "--reverse", "--no-merges",
+		"--fixed-strings", "--regexp-ignore-case", "--author="+author, rangeRef)
+	cmd.Dir = repoPath
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log range: %w", err)
+	}
+
+	var commits []string
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
+
+// GetCommitsDiff returns the patches of the given commits in order, each
+// headed by a "commit <sha> <subject>" line. Excludes generated files like
+// lock files.
+func GetCommitsDiff(repoPath string, shas []string) (string, error) {
+	var sb strings.Builder
+	for _, sha := range shas {
+		args := []string{"show", "--format=commit %H %s", sha, "--"}
+		args = append(args, ".")
+		args = append(args, excludedPathPatterns...)
+
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("git show %s: %w", ShortSHA(sha), err)
+		}
+		sb.Write(out)
+		if len(out) > 0 && out[len(out)-1] != '\n' {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String(), nil
+}
+
 // GetRangeDiff returns the combined diff for a range, excluding generated files like lock files
 func GetRangeDiff(repoPath, rangeRef string) (string, error) {
 	args := []string{"diff", rangeRef, "--"}
@@ -509,6 +630,85 @@ func GetDirtyDiff(repoPath string) (string, error) {
 	return result.String(), nil
 }
 
+// IsStashRef reports whether ref has the canonical stash entry form
+// "stash@{N}" returned by ResolveStashRef.
+func IsStashRef(ref string) bool {
+	n, ok := strings.CutPrefix(ref, "stash@{")
+	if !ok {
+		return false
+	}
+	n, ok = strings.CutSuffix(n, "}")
+	return ok && n != "" && strings.Trim(n, "0123456789") == ""
+}
+
+// ResolveStashRef checks that ref names an entry in the repo's stash and
+// returns it in canonical "stash@{N}" form. It accepts "stash@{N}", a bare
+// index "N", or "stash" for the newest entry.
+func ResolveStashRef(repoPath, ref string) (string, error) {
+	switch {
+	case ref == "stash":
+		ref = "stash@{0}"
+	case ref != "" && strings.Trim(ref, "0123456789") == "":
+		ref = "stash@{" + ref + "}"
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no stash entry %q", ref)
+	}
+	sha := strings.TrimSpace(string(out))
+
+	cmd = exec.Command("git", "stash", "list", "--format=%gd %H")
+	cmd.Dir = repoPath
+	out, err = cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git stash list: %w", err)
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		if selector, entrySHA, ok := strings.Cut(line, " "); ok && entrySHA == sha {
+			return selector, nil
+		}
+	}
+	return "", fmt.Errorf("%q is not a stash entry", ref)
+}
+
+// GetStashDiff returns the changes saved in a stash entry as a diff
+// against the commit it was stashed on, including any untracked files
+// stashed with --include-untracked.
+func GetStashDiff(repoPath, stashRef string) (string, error) {
+	diffArgs := func(from, to string) []string {
+		args := []string{"diff", from, to, "--", "."}
+		return append(args, excludedPathPatterns...)
+	}
+
+	cmd := exec.Command("git", diffArgs(stashRef+"^1", stashRef)...)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff %s: %w", stashRef, err)
+	}
+	var result strings.Builder
+	result.Write(out)
+
+	// Untracked files are stored as a parentless third parent
+	untracked := stashRef + "^3"
+	cmd = exec.Command("git", "rev-parse", "--verify", "--quiet", untracked)
+	cmd.Dir = repoPath
+	if cmd.Run() == nil {
+		cmd = exec.Command("git", diffArgs(EmptyTreeSHA, untracked)...)
+		cmd.Dir = repoPath
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("git diff %s: %w", untracked, err)
+		}
+		result.Write(out)
+	}
+
+	return result.String(), nil
+}
+
 // excludedPathPatterns contains pathspec patterns for files that should be excluded from diffs.
 // These are typically generated files that add noise to code reviews.
 // Uses :(exclude) long form since :! shorthand doesn't work reliably with git show/diff.
@@ -570,6 +770,25 @@ func isBinaryContent(content []byte) bool {
 	return false
 }
 
+// GetTrackedFiles returns the repo-relative paths of all files in the index.
+func GetTrackedFiles(repoPath string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "-z")
+	cmd.Dir = repoPath
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+
+	var files []string
+	for f := range strings.SplitSeq(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
 // GetRangeFilesChanged returns the list of files changed in a range (e.g. "mergeBase..HEAD")
 func GetRangeFilesChanged(repoPath, rangeRef string) ([]string, error) {
 	cmd := exec.Command("git", "diff", "--name-only", rangeRef)
@@ -955,6 +1174,51 @@ func ResetWorkingTree(repoPath string) error {
 	return nil
 }
 
+// GetCommitMessage returns the full message (subject and body) of a commit.
+func GetCommitMessage(repoPath, sha string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%B", sha)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git log: %w", err)
+	}
+	return string(out), nil
+}
+
+// IsCommitPushed returns true if the commit is reachable from any
+// remote-tracking branch.
+func IsCommitPushed(repoPath, sha string) (bool, error) {
+	cmd := exec.Command("git", "branch", "-r", "--contains", sha)
+	cmd.Dir = repoPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("git branch --contains: %w: %s", err, stderr.String())
+	}
+	return len(strings.TrimSpace(string(out))) > 0, nil
+}
+
+// AmendCommitMessage replaces the HEAD commit's message without including
+// any staged changes. Hooks are not run, so an installed post-commit hook
+// does not queue a new review of the amended commit. Returns the SHA of the
+// amended commit.
+func AmendCommitMessage(repoPath, message string) (string, error) {
+	// --only with no paths amends just the message, leaving the index alone.
+	// --no-verify only skips pre-commit and commit-msg, so point hooksPath
+	// somewhere without hooks to skip post-commit and post-rewrite too.
+	cmd := exec.Command("git", "-c", "core.hooksPath="+os.DevNull,
+		"commit", "--amend", "--only", "--no-verify", "--cleanup=verbatim", "-F", "-")
+	cmd.Dir = repoPath
+	cmd.Stdin = strings.NewReader(message)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git commit --amend: %w: %s", err, stderr.String())
+	}
+	return ResolveSHA(repoPath, "HEAD")
+}
+
 // GetRemoteURL returns the URL for a git remote.
 // If remoteName is empty, tries "origin" first, then any other remote.
 // Returns empty string if no remotes exist.
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -521,6 +522,69 @@ func TestGetCurrentBranch(t *testing.T) {
 	})
 }
 
+func TestListLocalBranches(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.Run("symbolic-ref", "HEAD", "refs/heads/main")
+	repo.CommitFile("file.txt", "content", "initial")
+	mainSHA := repo.HeadSHA()
+	repo.Run("checkout", "-b", "feature/a")
+	repo.CommitFile("a.txt", "a", "feature a")
+	featureSHA := repo.HeadSHA()
+	repo.Run("branch", "alias", "main")
+
+	branches, err := ListLocalBranches(repo.Dir)
+	if err != nil {
+		t.Fatalf("ListLocalBranches: %v", err)
+	}
+	want := []BranchTip{
+		{Name: "alias", SHA: mainSHA},
+		{Name: "feature/a", SHA: featureSHA},
+		{Name: "main", SHA: mainSHA},
+	}
+	if !slices.Equal(branches, want) {
+		t.Errorf("branches = %+v, want %+v", branches, want)
+	}
+
+	if _, err := ListLocalBranches(t.TempDir()); err == nil {
+		t.Error("expected error for non-repo")
+	}
+}
+
+func TestRevertedCommit(t *testing.T) {
+	sha := strings.Repeat("ab", 20)
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"git revert message", "This reverts commit " + sha + ".", sha},
+		{"trailer after explanation", "Broke the build.\n\nThis reverts commit " + sha + ".\n", sha},
+		{"no trailer", "Fix the parser", ""},
+		{"abbreviated sha", "This reverts commit abcdef1.", ""},
+		{"mentioned mid-line", "Note: This reverts commit " + sha, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RevertedCommit(tt.body); got != tt.want {
+				t.Errorf("RevertedCommit(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+
+	repo := NewTestRepo(t)
+	repo.CommitFile("file.txt", "one", "initial")
+	repo.CommitFile("file.txt", "two", "change")
+	changed := repo.HeadSHA()
+	repo.Run("revert", "--no-edit", "HEAD")
+	info, err := GetCommitInfo(repo.Dir, repo.HeadSHA())
+	if err != nil {
+		t.Fatalf("GetCommitInfo: %v", err)
+	}
+	if got := RevertedCommit(info.Body); got != changed {
+		t.Errorf("RevertedCommit of git revert body = %q, want %q", got, changed)
+	}
+}
+
 func TestHasUncommittedChanges(t *testing.T) {
 	repo := NewTestRepo(t)
 	repo.CommitFile("file.txt", "initial", "initial")
@@ -660,6 +724,67 @@ func TestGetDirtyDiff(t *testing.T) {
 	})
 }
 
+func TestResolveStashRef(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("file.txt", "initial\n", "initial")
+	repo.WriteFile("file.txt", "first stash\n")
+	repo.Run("stash")
+	repo.WriteFile("file.txt", "second stash\n")
+	repo.Run("stash")
+
+	for ref, want := range map[string]string{
+		"stash":     "stash@{0}",
+		"stash@{0}": "stash@{0}",
+		"1":         "stash@{1}",
+		"stash@{1}": "stash@{1}",
+	} {
+		got, err := ResolveStashRef(repo.Dir, ref)
+		if err != nil {
+			t.Errorf("ResolveStashRef(%q) failed: %v", ref, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ResolveStashRef(%q) = %q, want %q", ref, got, want)
+		}
+		if !IsStashRef(got) {
+			t.Errorf("IsStashRef(%q) = false", got)
+		}
+	}
+
+	for _, ref := range []string{"stash@{5}", "HEAD", "nonexistent", ""} {
+		if _, err := ResolveStashRef(repo.Dir, ref); err == nil {
+			t.Errorf("ResolveStashRef(%q) succeeded, want error", ref)
+		}
+	}
+}
+
+func TestGetStashDiff(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("file.txt", "initial\n", "initial")
+
+	repo.WriteFile("file.txt", "stashed change\n")
+	repo.WriteFile("untracked.txt", "untracked content\n")
+	repo.Run("stash", "--include-untracked")
+	// Later work must not leak into the stash's diff
+	repo.CommitFile("later.txt", "later\n", "later")
+	repo.WriteFile("file.txt", "working tree change\n")
+
+	diff, err := GetStashDiff(repo.Dir, "stash@{0}")
+	if err != nil {
+		t.Fatalf("GetStashDiff failed: %v", err)
+	}
+	for _, want := range []string{"+stashed change", "-initial", "untracked.txt", "+untracked content"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+	for _, unwanted := range []string{"later.txt", "working tree change"} {
+		if strings.Contains(diff, unwanted) {
+			t.Errorf("diff should not contain %q, got:\n%s", unwanted, diff)
+		}
+	}
+}
+
 func TestGetDirtyDiffNoCommits(t *testing.T) {
 	repo := NewTestRepo(t)
 
@@ -983,6 +1108,113 @@ func TestGetRangeFilesChanged(t *testing.T) {
 	})
 }
 
+func TestResolveRange(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.Run("symbolic-ref", "HEAD", "refs/heads/main")
+	repo.CommitFile("base.txt", "base", "base commit")
+	baseSHA := repo.HeadSHA()
+	repo.Run("checkout", "-b", "feature")
+	repo.CommitFile("feature.txt", "feature", "feature commit")
+	featureSHA := repo.HeadSHA()
+	repo.Run("checkout", "main")
+	repo.CommitFile("main.txt", "main", "main commit")
+	mainSHA := repo.HeadSHA()
+
+	tests := []struct {
+		rangeRef string
+		base     string
+		tip      string
+	}{
+		{"main..feature", mainSHA, featureSHA},
+		{"main...feature", baseSHA, featureSHA},
+		{"feature..", featureSHA, mainSHA},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rangeRef, func(t *testing.T) {
+			base, tip, err := ResolveRange(repo.Dir, tt.rangeRef)
+			if err != nil {
+				t.Fatalf("ResolveRange failed: %v", err)
+			}
+			if base != tt.base || tip != tt.tip {
+				t.Errorf("ResolveRange(%q) = (%s, %s), want (%s, %s)", tt.rangeRef, base, tip, tt.base, tt.tip)
+			}
+		})
+	}
+
+	t.Run("invalid endpoint", func(t *testing.T) {
+		if _, _, err := ResolveRange(repo.Dir, "main..nope"); err == nil {
+			t.Error("expected error for unknown end commit")
+		}
+	})
+}
+
+func TestGetRangeCommitsByAuthor(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("base.txt", "base", "base commit")
+	baseSHA := repo.HeadSHA()
+
+	commitAs := func(author, file, msg string) string {
+		repo.WriteFile(file, msg)
+		repo.Run("add", file)
+		repo.Run("commit", "--author", author, "-m", msg)
+		return repo.HeadSHA()
+	}
+	alice1 := commitAs("Alice <alice@example.com>", "a1.txt", "alice first")
+	commitAs("Bob <bob@example.com>", "b1.txt", "bob change")
+	alice2 := commitAs("Alice <alice@example.com>", "a2.txt", "alice second")
+
+	t.Run("filters to author in order", func(t *testing.T) {
+		commits, err := GetRangeCommitsByAuthor(repo.Dir, baseSHA+"..HEAD", "ALICE@example.com")
+		if err != nil {
+			t.Fatalf("GetRangeCommitsByAuthor failed: %v", err)
+		}
+		if len(commits) != 2 || commits[0] != alice1 || commits[1] != alice2 {
+			t.Errorf("expected [%s %s], got %v", alice1, alice2, commits)
+		}
+	})
+
+	t.Run("no matching author", func(t *testing.T) {
+		commits, err := GetRangeCommitsByAuthor(repo.Dir, baseSHA+"..HEAD", "carol@example.com")
+		if err != nil {
+			t.Fatalf("GetRangeCommitsByAuthor failed: %v", err)
+		}
+		if len(commits) != 0 {
+			t.Errorf("expected no commits, got %v", commits)
+		}
+	})
+
+	t.Run("diff contains only selected commits", func(t *testing.T) {
+		diff, err := GetCommitsDiff(repo.Dir, []string{alice1, alice2})
+		if err != nil {
+			t.Fatalf("GetCommitsDiff failed: %v", err)
+		}
+		for _, want := range []string{"commit " + alice1 + " alice first", "a1.txt", "a2.txt"} {
+			if !strings.Contains(diff, want) {
+				t.Errorf("expected diff to contain %q, got:\n%s", want, diff)
+			}
+		}
+		if strings.Contains(diff, "b1.txt") {
+			t.Errorf("expected diff to exclude bob's commit, got:\n%s", diff)
+		}
+	})
+}
+
+func TestGetTrackedFiles(t *testing.T) {
+	repo := NewTestRepo(t)
+	repo.CommitFile("main.go", "package main", "add main")
+	repo.CommitFile("pkg/name with space.go", "package pkg", "add pkg")
+	repo.WriteFile("untracked.txt", "scratch")
+
+	files, err := GetTrackedFiles(repo.Dir)
+	if err != nil {
+		t.Fatalf("GetTrackedFiles failed: %v", err)
+	}
+	want := []string{"main.go", "pkg/name with space.go"}
+	if !slices.Equal(files, want) {
+		t.Errorf("GetTrackedFiles() = %q, want %q", files, want)
+	}
+}
+
 func TestCreateCommitPreCommitHookOutput(t *testing.T) {
 	repo := NewTestRepo(t)
 	repo.CommitFile("initial.txt", "initial", "initial commit")
@@ -0,0 +1,88 @@
+package prompt
+
+import (
+	"log"
+	"strings"
+)
+
+// EstimateTokens approximates how many tokens s uses, at roughly four
+// bytes per token. It errs high for code-heavy text, which is the safe
+// direction for budgeting.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// promptSection is one contiguous part of a prompt. Sections with a
+// nonzero trimRank are optional context that can be dropped to fit the
+// token budget, lowest rank first.
+type promptSection struct {
+	name     string // Reported in the log when the section is trimmed
+	text     string
+	trimRank int // 0 = always kept
+}
+
+// Trim ranks for optional prompt sections, least important first.
+const (
+	trimPreviousReviews  = 1
+	trimPreviousAttempts = 2
+	trimCommitBody       = 3
+)
+
+// maxPromptBytes returns the largest prompt, in bytes, that fits both
+// MaxPromptSize and the token budget.
+func (b *Builder) maxPromptBytes() int {
+	if b.tokenBudget > 0 {
+		return min(MaxPromptSize, b.tokenBudget*4)
+	}
+	return MaxPromptSize
+}
+
+// finish joins sections followed by diffSection into the prompt. While
+// the prompt exceeds the builder's token budget, optional sections are
+// dropped in trimRank order. If the diff still doesn't fit within the
+// budget or MaxPromptSize, the text returned by diffFallback for the
+// preceding prompt is used in its place.
+func (b *Builder) finish(sections []promptSection, diffSection string, diffFallback func(prefix string) string) string {
+	keep := make([]bool, len(sections))
+	for i := range keep {
+		keep[i] = true
+	}
+	join := func() string {
+		var sb strings.Builder
+		for i, s := range sections {
+			if keep[i] {
+				sb.WriteString(s.text)
+			}
+		}
+		return sb.String()
+	}
+
+	prefix := join()
+	if b.tokenBudget > 0 && EstimateTokens(prefix+diffSection) > b.tokenBudget {
+		var trimmed []string
+		for _, rank := range []int{trimPreviousReviews, trimPreviousAttempts, trimCommitBody} {
+			if EstimateTokens(prefix+diffSection) <= b.tokenBudget {
+				break
+			}
+			for i, s := range sections {
+				if s.trimRank == rank && keep[i] && s.text != "" {
+					keep[i] = false
+					trimmed = append(trimmed, s.name)
+				}
+			}
+			prefix = join()
+		}
+		if len(trimmed) > 0 {
+			log.Printf("prompt: over token budget of %d, trimmed %s", b.tokenBudget, strings.Join(trimmed, ", "))
+		}
+	}
+
+	overBudget := b.tokenBudget > 0 && EstimateTokens(prefix+diffSection) > b.tokenBudget
+	if overBudget {
+		log.Printf("prompt: diff alone exceeds token budget of %d (~%d tokens), omitting it", b.tokenBudget, EstimateTokens(diffSection))
+	}
+	if overBudget || len(prefix)+len(diffSection) > MaxPromptSize {
+		return prefix + diffFallback(prefix)
+	}
+	return prefix + diffSection
+}
@@ -123,7 +123,8 @@ type ReviewContext struct {
 
 // Builder constructs review prompts
 type Builder struct {
-	db *storage.DB
+	db          *storage.DB
+	tokenBudget int // Estimated-token limit for review prompts; 0 = none
 }
 
 // NewBuilder creates a new prompt builder
@@ -131,13 +132,30 @@ func NewBuilder(db *storage.DB) *Builder {
 	return &Builder{db: db}
 }
 
+// WithTokenBudget returns a copy of the builder whose review prompts are
+// kept within budget estimated tokens (see EstimateTokens) by trimming
+// previous reviews, previous attempts, and the commit message body, in
+// that order, and finally omitting the diff. Zero means no budget.
+func (b *Builder) WithTokenBudget(budget int) *Builder {
+	c := *b
+	c.tokenBudget = budget
+	return &c
+}
+
 // Build constructs a review prompt for a commit or range with context from previous reviews.
 // reviewType selects the system prompt variant (e.g., "security"); any default alias (see config.IsDefaultReviewType) uses the standard prompt.
 func (b *Builder) Build(repoPath, gitRef string, repoID int64, contextCount int, agentName, reviewType string) (string, error) {
 	if git.IsRange(gitRef) {
 		return b.buildRangePrompt(repoPath, gitRef, repoID, contextCount, agentName, reviewType)
 	}
-	return b.buildSinglePrompt(repoPath, gitRef, repoID, contextCount, agentName, reviewType)
+	return b.buildSinglePrompt(repoPath, gitRef, "", repoID, contextCount, agentName, reviewType)
+}
+
+// BuildWithDiff constructs a review prompt for a single commit using a diff
+// supplied at enqueue time, such as a hand-edited version of the commit's
+// changes, instead of the commit's own diff.
+func (b *Builder) BuildWithDiff(repoPath, sha, diff string, repoID int64, contextCount int, agentName, reviewType string) (string, error) {
+	return b.buildSinglePrompt(repoPath, sha, diff, repoID, contextCount, agentName, reviewType)
 }
 
 // BuildDirty constructs a review prompt for uncommitted (dirty) changes.
@@ -154,28 +172,39 @@ func (b *Builder) BuildDirty(repoPath, diff string, repoID int64, contextCount i
 	if promptType == config.ReviewTypeDesign {
 		promptType = "design-review"
 	}
-	sb.WriteString(GetSystemPrompt(agentName, promptType))
+	var guidelines string
+	if repoCfg, err := config.LoadRepoConfig(repoPath); err == nil && repoCfg != nil {
+		guidelines = repoCfg.ReviewGuidelines
+	}
+	data := ReviewTemplateData{
+		Repo: repoPath, Ref: "dirty", Diff: diff, Guidelines: guidelines, Agent: agentName,
+	}
+	system, err := reviewSystemPrompt(repoPath, agentName, promptType, reviewType, data)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(system)
 	sb.WriteString("\n")
 
 	// Add project-specific guidelines if configured
-	if repoCfg, err := config.LoadRepoConfig(repoPath); err == nil && repoCfg != nil {
-		b.writeProjectGuidelines(&sb, repoCfg.ReviewGuidelines)
-	}
+	b.writeProjectGuidelines(&sb, guidelines)
 
 	// Get previous reviews for context (use HEAD as reference point)
+	var previous strings.Builder
 	if contextCount > 0 && b.db != nil {
 		headSHA, err := git.ResolveSHA(repoPath, "HEAD")
 		if err == nil {
 			contexts, err := b.getPreviousReviewContexts(repoPath, headSHA, contextCount)
 			if err == nil && len(contexts) > 0 {
-				b.writePreviousReviews(&sb, contexts)
+				b.writePreviousReviews(&previous, contexts)
 			}
 		}
 	}
 
 	// Uncommitted changes section
-	sb.WriteString("## Uncommitted Changes\n\n")
-	sb.WriteString("The following changes have not yet been committed.\n\n")
+	var changes strings.Builder
+	changes.WriteString("## Uncommitted Changes\n\n")
+	changes.WriteString("The following changes have not yet been committed.\n\n")
 
 	// Build diff section
 	var diffSection strings.Builder
@@ -187,29 +216,32 @@ func (b *Builder) BuildDirty(repoPath, diff string, repoID int64, contextCount i
 	}
 	diffSection.WriteString("```\n")
 
-	// Check if adding the diff would exceed max prompt size
-	if sb.Len()+diffSection.Len() > MaxPromptSize {
-		// For dirty changes, we can't tell them to "use git diff" because
-		// the working tree may have changed. Just truncate with a note.
+	sections := []promptSection{
+		{text: sb.String()},
+		{name: "previous reviews", text: previous.String(), trimRank: trimPreviousReviews},
+		{text: changes.String()},
+	}
+	// For dirty changes, we can't tell them to "use git diff" because
+	// the working tree may have changed. Just truncate with a note.
+	return b.finish(sections, diffSection.String(), func(prefix string) string {
+		var sb strings.Builder
 		sb.WriteString("### Diff\n\n")
 		sb.WriteString("(Diff too large to include in full)\n")
 		// Include truncated diff
-		maxDiffLen := MaxPromptSize - sb.Len() - 100 // Leave room for closing markers
+		maxDiffLen := b.maxPromptBytes() - len(prefix) - sb.Len() - 100 // Leave room for closing markers
 		if maxDiffLen > 1000 {
 			sb.WriteString("```diff\n")
 			sb.WriteString(diff[:maxDiffLen])
 			sb.WriteString("\n... (truncated)\n")
 			sb.WriteString("```\n")
 		}
-	} else {
-		sb.WriteString(diffSection.String())
-	}
-
-	return sb.String(), nil
+		return sb.String()
+	}), nil
 }
 
-// buildSinglePrompt constructs a prompt for a single commit
-func (b *Builder) buildSinglePrompt(repoPath, sha string, repoID int64, contextCount int, agentName, reviewType string) (string, error) {
+// buildSinglePrompt constructs a prompt for a single commit. An empty
+// suppliedDiff means the commit's diff is read from git.
+func (b *Builder) buildSinglePrompt(repoPath, sha, suppliedDiff string, repoID int64, contextCount int, agentName, reviewType string) (string, error) {
 	var sb strings.Builder
 
 	// Start with system prompt
@@ -220,48 +252,64 @@ func (b *Builder) buildSinglePrompt(repoPath, sha string, repoID int64, contextC
 	if promptType == config.ReviewTypeDesign {
 		promptType = "design-review"
 	}
-	sb.WriteString(GetSystemPrompt(agentName, promptType))
+
+	// Get commit info
+	info, err := git.GetCommitInfo(repoPath, sha)
+	if err != nil {
+		return "", fmt.Errorf("get commit info: %w", err)
+	}
+
+	// Get the diff
+	diff := suppliedDiff
+	if diff == "" {
+		diff, err = git.GetDiff(repoPath, sha)
+		if err != nil {
+			return "", fmt.Errorf("get diff: %w", err)
+		}
+	}
+
+	guidelines := loadGuidelines(repoPath)
+	data := ReviewTemplateData{
+		Repo: repoPath, Ref: sha, Subject: info.Subject, Author: info.Author,
+		Diff: diff, Guidelines: guidelines, Agent: agentName,
+	}
+	system, err := reviewSystemPrompt(repoPath, agentName, promptType, reviewType, data)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(system)
 	sb.WriteString("\n")
 
 	// Add project-specific guidelines from default branch
-	b.writeProjectGuidelines(&sb, loadGuidelines(repoPath))
+	b.writeProjectGuidelines(&sb, guidelines)
 
 	// Get previous reviews if requested
+	var previous strings.Builder
 	if contextCount > 0 && b.db != nil {
 		contexts, err := b.getPreviousReviewContexts(repoPath, sha, contextCount)
 		if err != nil {
 			// Log but don't fail - previous reviews are nice-to-have context
 			// Just continue without them
 		} else if len(contexts) > 0 {
-			b.writePreviousReviews(&sb, contexts)
+			b.writePreviousReviews(&previous, contexts)
 		}
 	}
 
 	// Include previous review attempts for this same commit (for re-reviews)
-	b.writePreviousAttemptsForGitRef(&sb, sha)
+	var attempts strings.Builder
+	b.writePreviousAttemptsForGitRef(&attempts, sha)
 
 	// Current commit section
 	shortSHA := git.ShortSHA(sha)
 
-	// Get commit info
-	info, err := git.GetCommitInfo(repoPath, sha)
-	if err != nil {
-		return "", fmt.Errorf("get commit info: %w", err)
-	}
-
-	sb.WriteString("## Current Commit\n\n")
-	fmt.Fprintf(&sb, "**Commit:** %s\n", shortSHA)
-	fmt.Fprintf(&sb, "**Author:** %s\n", info.Author)
-	fmt.Fprintf(&sb, "**Subject:** %s\n", info.Subject)
+	var commit strings.Builder
+	commit.WriteString("## Current Commit\n\n")
+	fmt.Fprintf(&commit, "**Commit:** %s\n", shortSHA)
+	fmt.Fprintf(&commit, "**Author:** %s\n", info.Author)
+	fmt.Fprintf(&commit, "**Subject:** %s\n", info.Subject)
+	var body string
 	if info.Body != "" {
-		fmt.Fprintf(&sb, "\n**Message:**\n%s\n", info.Body)
-	}
-	sb.WriteString("\n")
-
-	// Get and include the diff
-	diff, err := git.GetDiff(repoPath, sha)
-	if err != nil {
-		return "", fmt.Errorf("get diff: %w", err)
+		body = fmt.Sprintf("\n**Message:**\n%s\n", info.Body)
 	}
 
 	// Build diff section
@@ -274,21 +322,94 @@ func (b *Builder) buildSinglePrompt(repoPath, sha string, repoID int64, contextC
 	}
 	diffSection.WriteString("```\n")
 
-	// Check if adding the diff would exceed max prompt size
-	if sb.Len()+diffSection.Len() > MaxPromptSize {
+	sections := []promptSection{
+		{text: sb.String()},
+		{name: "previous reviews", text: previous.String(), trimRank: trimPreviousReviews},
+		{name: "previous attempts", text: attempts.String(), trimRank: trimPreviousAttempts},
+		{text: commit.String()},
+		{name: "commit message body", text: body, trimRank: trimCommitBody},
+		{text: "\n"},
+	}
+	if suppliedDiff != "" {
+		sections = append(sections, promptSection{text: "The diff below was supplied for this review and may differ from the commit's\n" +
+			"own changes. Review the diff as shown; use the commit only for context.\n\n"})
+	}
+	return b.finish(sections, diffSection.String(), func(string) string {
+		if suppliedDiff != "" {
+			// The supplied diff isn't in git, so there is nothing to point at
+			return "### Diff\n\n(Diff too large to include)\n"
+		}
 		// Fall back to just commit info without diff
-		sb.WriteString("### Diff\n\n")
-		sb.WriteString("(Diff too large to include - please review the commit directly)\n")
-		fmt.Fprintf(&sb, "View with: git show %s\n", sha)
-	} else {
-		sb.WriteString(diffSection.String())
-	}
-
-	return sb.String(), nil
+		return "### Diff\n\n" +
+			"(Diff too large to include - please review the commit directly)\n" +
+			fmt.Sprintf("View with: git show %s\n", sha)
+	}), nil
 }
 
 // buildRangePrompt constructs a prompt for a commit range
 func (b *Builder) buildRangePrompt(repoPath, rangeRef string, repoID int64, contextCount int, agentName, reviewType string) (string, error) {
+	// Get commits in range
+	commits, err := git.GetRangeCommits(repoPath, rangeRef)
+	if err != nil {
+		return "", fmt.Errorf("get range commits: %w", err)
+	}
+
+	// Get the combined diff for the range
+	diff, err := git.GetRangeDiff(repoPath, rangeRef)
+	if err != nil {
+		return "", fmt.Errorf("get range diff: %w", err)
+	}
+
+	sections, err := b.rangePreamble(repoPath, rangeRef, diff, contextCount, agentName, reviewType)
+	if err != nil {
+		return "", err
+	}
+
+	// Commit range section
+	var sb strings.Builder
+	sb.WriteString("## Commit Range\n\n")
+	fmt.Fprintf(&sb, "Reviewing %d commits:\n\n", len(commits))
+
+	for _, sha := range commits {
+		info, err := git.GetCommitInfo(repoPath, sha)
+		shortSHA := git.ShortSHA(sha)
+		if err == nil {
+			fmt.Fprintf(&sb, "- %s %s\n", shortSHA, info.Subject)
+		} else {
+			fmt.Fprintf(&sb, "- %s\n", shortSHA)
+		}
+	}
+	sb.WriteString("\n")
+	sections = append(sections, promptSection{text: sb.String()})
+
+	diffSection, fallback := rangeDiff("Combined Diff", diff, "git diff "+rangeRef)
+	return b.finish(sections, diffSection, fallback), nil
+}
+
+// BuildRangeWithDiff constructs a review prompt for a subset of the commits
+// in a range (e.g. one author's commits). The diff was captured at enqueue
+// time as each selected commit's patch, in order, headed by its SHA and
+// subject.
+func (b *Builder) BuildRangeWithDiff(repoPath, rangeRef, diff string, repoID int64, contextCount int, agentName, reviewType string) (string, error) {
+	sections, err := b.rangePreamble(repoPath, rangeRef, diff, contextCount, agentName, reviewType)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Commit Range\n\n")
+	sb.WriteString("Reviewing only selected commits from this range. Other commits in the\n")
+	sb.WriteString("range are not part of this review. Each commit's changes are shown below\n")
+	sb.WriteString("in order.\n\n")
+	sections = append(sections, promptSection{text: sb.String()})
+
+	diffSection, fallback := rangeDiff("Commit Diffs", diff, "git log -p "+rangeRef)
+	return b.finish(sections, diffSection, fallback), nil
+}
+
+// rangePreamble returns the system prompt, project guidelines, and
+// previous review context shared by range prompts.
+func (b *Builder) rangePreamble(repoPath, rangeRef, diff string, contextCount int, agentName, reviewType string) ([]promptSection, error) {
 	var sb strings.Builder
 
 	// Start with system prompt for ranges
@@ -299,56 +420,50 @@ func (b *Builder) buildRangePrompt(repoPath, rangeRef string, repoID int64, cont
 	if promptType == config.ReviewTypeDesign {
 		promptType = "design-review"
 	}
-	sb.WriteString(GetSystemPrompt(agentName, promptType))
+	guidelines := loadGuidelines(repoPath)
+	system, err := reviewSystemPrompt(repoPath, agentName, promptType, reviewType, rangeTemplateData(repoPath, rangeRef, diff, guidelines, agentName))
+	if err != nil {
+		return nil, err
+	}
+	sb.WriteString(system)
 	sb.WriteString("\n")
 
 	// Add project-specific guidelines from default branch
-	b.writeProjectGuidelines(&sb, loadGuidelines(repoPath))
+	b.writeProjectGuidelines(&sb, guidelines)
 
 	// Get previous reviews from before the range start
+	var previous strings.Builder
 	if contextCount > 0 && b.db != nil {
 		startSHA, err := git.GetRangeStart(repoPath, rangeRef)
 		if err == nil {
 			contexts, err := b.getPreviousReviewContexts(repoPath, startSHA, contextCount)
 			if err == nil && len(contexts) > 0 {
-				b.writePreviousReviews(&sb, contexts)
+				b.writePreviousReviews(&previous, contexts)
 			}
 		}
 	}
 
 	// Include previous review attempts for this same range (for re-reviews)
-	b.writePreviousAttemptsForGitRef(&sb, rangeRef)
-
-	// Get commits in range
-	commits, err := git.GetRangeCommits(repoPath, rangeRef)
-	if err != nil {
-		return "", fmt.Errorf("get range commits: %w", err)
-	}
-
-	// Commit range section
-	sb.WriteString("## Commit Range\n\n")
-	fmt.Fprintf(&sb, "Reviewing %d commits:\n\n", len(commits))
-
-	for _, sha := range commits {
-		info, err := git.GetCommitInfo(repoPath, sha)
-		shortSHA := git.ShortSHA(sha)
-		if err == nil {
-			fmt.Fprintf(&sb, "- %s %s\n", shortSHA, info.Subject)
-		} else {
-			fmt.Fprintf(&sb, "- %s\n", shortSHA)
-		}
-	}
-	sb.WriteString("\n")
+	var attempts strings.Builder
+	b.writePreviousAttemptsForGitRef(&attempts, rangeRef)
+
+	return []promptSection{
+		{text: sb.String()},
+		{name: "previous reviews", text: previous.String(), trimRank: trimPreviousReviews},
+		{name: "previous attempts", text: attempts.String(), trimRank: trimPreviousAttempts},
+	}, nil
+}
 
-	// Get and include the combined diff for the range
-	diff, err := git.GetRangeDiff(repoPath, rangeRef)
-	if err != nil {
-		return "", fmt.Errorf("get range diff: %w", err)
-	}
+// rangeTemplateData returns the template data for a range review.
+func rangeTemplateData(repoPath, rangeRef, diff, guidelines, agentName string) ReviewTemplateData {
+	return ReviewTemplateData{Repo: repoPath, Ref: rangeRef, Diff: diff, Guidelines: guidelines, Agent: agentName}
+}
 
-	// Build diff section
+// rangeDiff returns a range diff section along with its fallback, a
+// pointer at the git command to run, for when the diff doesn't fit.
+func rangeDiff(title, diff, viewCmd string) (string, func(string) string) {
 	var diffSection strings.Builder
-	diffSection.WriteString("### Combined Diff\n\n")
+	fmt.Fprintf(&diffSection, "### %s\n\n", title)
 	diffSection.WriteString("```diff\n")
 	diffSection.WriteString(diff)
 	if !strings.HasSuffix(diff, "\n") {
@@ -356,17 +471,12 @@ func (b *Builder) buildRangePrompt(repoPath, rangeRef string, repoID int64, cont
 	}
 	diffSection.WriteString("```\n")
 
-	// Check if adding the diff would exceed max prompt size
-	if sb.Len()+diffSection.Len() > MaxPromptSize {
+	return diffSection.String(), func(string) string {
 		// Fall back to just commit info without diff
-		sb.WriteString("### Combined Diff\n\n")
-		sb.WriteString("(Diff too large to include - please review the commits directly)\n")
-		fmt.Fprintf(&sb, "View with: git diff %s\n", rangeRef)
-	} else {
-		sb.WriteString(diffSection.String())
+		return fmt.Sprintf("### %s\n\n", title) +
+			"(Diff too large to include - please review the commits directly)\n" +
+			fmt.Sprintf("View with: %s\n", viewCmd)
 	}
-
-	return sb.String(), nil
 }
 
 // writePreviousReviews writes the previous reviews section to the builder
@@ -416,30 +526,40 @@ func (b *Builder) writeProjectGuidelines(sb *strings.Builder, guidelines string)
 // Falls back to filesystem LoadRepoConfig only when no .roborev.toml
 // exists on the default branch (not when it exists with empty guidelines).
 func loadGuidelines(repoPath string) string {
-	// Load review guidelines from the default branch (origin/main,
-	// origin/master, etc.). Branch-specific guidelines are intentionally
-	// ignored to prevent prompt injection from untrusted PR authors.
+	if cfg, _ := trustedRepoConfig(repoPath); cfg != nil {
+		return cfg.ReviewGuidelines
+	}
+	return ""
+}
+
+// trustedRepoConfig loads the repo config that shapes review prompts,
+// returning it with the ref it was read from. It is read from the default
+// branch (origin/main, origin/master, etc.); branch-specific settings are
+// intentionally ignored to prevent prompt injection from untrusted PR
+// authors. Files the config names should be read at the same ref.
+func trustedRepoConfig(repoPath string) (*config.RepoConfig, string) {
 	if defaultBranch, err := git.GetDefaultBranch(repoPath); err == nil {
 		cfg, err := config.LoadRepoConfigFromRef(repoPath, defaultBranch)
 		if err != nil {
 			if config.IsConfigParseError(err) {
 				log.Printf("prompt: invalid .roborev.toml on %s: %v",
 					defaultBranch, err)
-				return ""
+				return nil, ""
 			}
 			log.Printf("prompt: failed to read .roborev.toml from %s: %v"+
 				" (will try filesystem)", defaultBranch, err)
 		} else if cfg != nil {
-			return cfg.ReviewGuidelines
+			return cfg, defaultBranch
 		}
 	}
 
 	// Fall back to filesystem config when default branch has no config
-	// (e.g., no remote, or .roborev.toml not yet committed).
+	// (e.g., no remote, or .roborev.toml not yet committed). The empty
+	// ref means files are read from the working tree too.
 	if fsCfg, err := config.LoadRepoConfig(repoPath); err == nil && fsCfg != nil {
-		return fsCfg.ReviewGuidelines
+		return fsCfg, ""
 	}
-	return ""
+	return nil, ""
 }
 
 // writePreviousAttemptsForGitRef writes previous review attempts for the same git ref (commit or range)
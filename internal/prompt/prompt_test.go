@@ -653,6 +653,37 @@ func TestBuildSinglePrompt_WithGuidelines(t *testing.T) {
 	assertNotContains(t, section, "Branch-only rule.", "branch guidelines should not appear in guidelines section")
 }
 
+func TestBuildRangeWithDiff(t *testing.T) {
+	repoPath, commits := setupTestRepo(t)
+	rangeRef := commits[3] + ".." + commits[5]
+	diff := "commit abc123 selected change\n+added line\n"
+
+	b := NewBuilder(nil)
+	prompt, err := b.BuildRangeWithDiff(repoPath, rangeRef, diff, 0, 0, "test", "")
+	if err != nil {
+		t.Fatalf("BuildRangeWithDiff failed: %v", err)
+	}
+	assertContains(t, prompt, "commit range", "Expected range system prompt")
+	assertContains(t, prompt, "+added line", "Expected supplied diff in prompt")
+}
+
+func TestBuildWithDiff(t *testing.T) {
+	repoPath, commits := setupTestRepo(t)
+	sha := commits[5]
+	diff := "diff --git a/file.txt b/file.txt\n+edited by hand\n"
+
+	b := NewBuilder(nil)
+	prompt, err := b.BuildWithDiff(repoPath, sha, diff, 0, 0, "test", "")
+	if err != nil {
+		t.Fatalf("BuildWithDiff failed: %v", err)
+	}
+	assertContains(t, prompt, "## Current Commit", "Expected commit context")
+	assertContains(t, prompt, "commit 6", "Expected the commit subject")
+	assertContains(t, prompt, "+edited by hand", "Expected supplied diff in prompt")
+	assertContains(t, prompt, "may differ from the commit", "Expected a note about the supplied diff")
+	assertNotContains(t, prompt, "+xxxxxx", "The commit's own diff should not be included")
+}
+
 func TestBuildRangePrompt_WithGuidelines(t *testing.T) {
 	dir, baseSHA, featureSHA := setupGuidelinesRepo(t, "main",
 		"Base guideline.", "Branch-only rule.")
@@ -668,3 +699,106 @@ func TestBuildRangePrompt_WithGuidelines(t *testing.T) {
 	assertContains(t, section, "Base guideline.", "expected default branch guidelines in range prompt")
 	assertNotContains(t, section, "Branch-only rule.", "branch guidelines should not appear in guidelines section")
 }
+
+// setupBudgetRepo creates a commit with a message body, a large review of
+// its parent, and a review of the commit itself, so that a built prompt
+// has every trimmable section.
+func setupBudgetRepo(t *testing.T) (*Builder, string, string, int64) {
+	t.Helper()
+	repoPath, commits := setupTestRepo(t)
+	r := &testRepo{t: t, dir: repoPath}
+	if err := os.WriteFile(filepath.Join(repoPath, "file.txt"), []byte("budgeted change"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r.git("commit", "-am", "budgeted subject\n\nBudgeted commit body.")
+	sha := r.git("rev-parse", "HEAD")
+
+	db := testutil.OpenTestDB(t)
+	repo, err := db.GetOrCreateRepo(repoPath)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+	parent := commits[5]
+	if _, err := db.GetOrCreateCommit(repo.ID, parent, "Test", "commit 6", time.Now()); err != nil {
+		t.Fatalf("GetOrCreateCommit failed: %v", err)
+	}
+	testutil.CreateCompletedReview(t, db, repo.ID, parent, "test", "Parent review. "+strings.Repeat("p", 8000))
+	testutil.CreateCompletedReview(t, db, repo.ID, sha, "test", "Earlier attempt at this commit.")
+	return NewBuilder(db), repoPath, sha, repo.ID
+}
+
+func TestBuildPromptTokenBudget(t *testing.T) {
+	b, repoPath, sha, repoID := setupBudgetRepo(t)
+
+	full, err := b.Build(repoPath, sha, repoID, 1, "", "")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	assertContains(t, full, "Parent review.", "unbudgeted prompt should contain previous reviews")
+	assertContains(t, full, "Earlier attempt at this commit.", "unbudgeted prompt should contain previous attempts")
+	assertContains(t, full, "Budgeted commit body.", "unbudgeted prompt should contain the commit body")
+
+	unlimited, err := b.WithTokenBudget(0).Build(repoPath, sha, repoID, 1, "", "")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if unlimited != full {
+		t.Error("zero token budget should not change the prompt")
+	}
+
+	t.Run("previous reviews trimmed first", func(t *testing.T) {
+		budget := EstimateTokens(full) - 1000
+		prompt, err := b.WithTokenBudget(budget).Build(repoPath, sha, repoID, 1, "", "")
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		if EstimateTokens(prompt) > budget {
+			t.Errorf("prompt is ~%d tokens, want at most %d", EstimateTokens(prompt), budget)
+		}
+		assertNotContains(t, prompt, "Parent review.", "previous reviews should be trimmed")
+		assertContains(t, prompt, "Earlier attempt at this commit.", "previous attempts should be kept")
+		assertContains(t, prompt, "Budgeted commit body.", "commit body should be kept")
+		assertContains(t, prompt, "+budgeted change", "diff should be kept")
+	})
+
+	t.Run("commit body trimmed last", func(t *testing.T) {
+		minimal := strings.Replace(full, "\n**Message:**\nBudgeted commit body.\n", "", 1)
+		minimal = minimal[:strings.Index(minimal, PreviousReviewsHeader)] + minimal[strings.Index(minimal, "## Current Commit"):]
+		budget := EstimateTokens(minimal) + 1
+		prompt, err := b.WithTokenBudget(budget).Build(repoPath, sha, repoID, 1, "", "")
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		assertNotContains(t, prompt, "Parent review.", "previous reviews should be trimmed")
+		assertNotContains(t, prompt, "Earlier attempt at this commit.", "previous attempts should be trimmed")
+		assertNotContains(t, prompt, "Budgeted commit body.", "commit body should be trimmed")
+		assertContains(t, prompt, "**Subject:** budgeted subject", "commit header should be kept")
+		assertContains(t, prompt, "+budgeted change", "diff should be kept")
+	})
+
+	t.Run("diff alone over budget falls back", func(t *testing.T) {
+		prompt, err := b.WithTokenBudget(10).Build(repoPath, sha, repoID, 1, "", "")
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		assertNotContains(t, prompt, "+budgeted change", "diff should be omitted")
+		assertContains(t, prompt, "(Diff too large to include", "prompt should use the diff fallback")
+		assertContains(t, prompt, "View with: git show "+sha, "prompt should point at the commit")
+	})
+}
+
+func TestBuildDirtyTokenBudget(t *testing.T) {
+	repoPath, _ := setupTestRepo(t)
+	diff := "diff --git a/big.txt b/big.txt\n" + strings.Repeat("+dirty line\n", 1000)
+
+	b := NewBuilder(nil).WithTokenBudget(2000)
+	prompt, err := b.BuildDirty(repoPath, diff, 0, 0, "", "")
+	if err != nil {
+		t.Fatalf("BuildDirty failed: %v", err)
+	}
+	assertContains(t, prompt, "(Diff too large to include in full)", "dirty prompt should truncate the diff")
+	assertContains(t, prompt, "... (truncated)", "dirty prompt should include a truncated diff")
+	if EstimateTokens(prompt) > 2000 {
+		t.Errorf("prompt is ~%d tokens, want at most 2000", EstimateTokens(prompt))
+	}
+}
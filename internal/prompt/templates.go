@@ -2,9 +2,17 @@ package prompt
 
 import (
 	"embed"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
+
+	"github.com/roborev-dev/roborev/internal/config"
+	"github.com/roborev-dev/roborev/internal/git"
 )
 
 //go:embed templates/*.tmpl
@@ -72,3 +80,128 @@ func getSystemPrompt(agentName string, promptType string, now func() time.Time)
 func appendDateLine(prompt string, now func() time.Time) string {
 	return prompt + "\n\nCurrent date: " + now().UTC().Format("2006-01-02") + " (UTC)"
 }
+
+// ReviewTemplateData holds the values a repo's review template can
+// reference, e.g. {{.Subject}}. The template replaces only the system
+// prompt, so the commit details and diff still follow it.
+type ReviewTemplateData struct {
+	Repo       string // Repo root path
+	Ref        string // Commit SHA, range, or "dirty"
+	Subject    string // Commit subject; empty for ranges and dirty reviews
+	Author     string // Commit author; empty for ranges and dirty reviews
+	Diff       string // The diff under review
+	Guidelines string // The repo's review_guidelines
+	Agent      string // Agent running the review
+}
+
+// LoadReviewTemplate parses the repo's review template: the file named by
+// review.prompt_template, or else [prompt].template, which is a file or
+// the template itself. Like review_guidelines, the setting and the file
+// are read from the default branch, so a branch under review can't
+// rewrite its own prompt; the working tree is used only when the default
+// branch has no config. It returns nil when neither key is set or the
+// file doesn't exist, so callers fall back to the built-in prompt. A path
+// outside the repo, or a template that doesn't parse or references an
+// unknown field, is an error.
+func LoadReviewTemplate(repoPath string) (*template.Template, error) {
+	repoCfg, ref := trustedRepoConfig(repoPath)
+	key, name, content, err := reviewTemplateSource(repoPath, repoCfg, ref)
+	if err != nil || content == "" {
+		return nil, err
+	}
+	return parseReviewTemplate(key, name, content)
+}
+
+// reviewTemplateSource returns the config key the repo's review template
+// comes from, its name, and its unparsed text, read at ref. content is ""
+// when no template is configured or its file doesn't exist.
+func reviewTemplateSource(repoPath string, repoCfg *config.RepoConfig, ref string) (key, name, content string, err error) {
+	if repoCfg == nil {
+		return "", "", "", nil
+	}
+	key, name = "review.prompt_template", repoCfg.Review.PromptTemplate
+	if name == "" {
+		key, name = "prompt.template", repoCfg.Prompt.Template
+		if strings.Contains(name, "{{") || strings.Contains(name, "\n") {
+			return key, key, name, nil
+		}
+		name = strings.TrimSpace(name)
+	}
+	if name == "" {
+		return "", "", "", nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", "", "", fmt.Errorf("%s %q must be a path inside the repo", key, name)
+	}
+	data, err := readRepoFile(repoPath, ref, name)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", "", nil
+	}
+	if err != nil {
+		return "", "", "", fmt.Errorf("read %s: %w", key, err)
+	}
+	return key, name, string(data), nil
+}
+
+// CacheSettings returns the repo settings, besides the diff, that shape a
+// review prompt: the trusted review_guidelines and review template text.
+// Enqueue folds them into a job's diff hash, so a finished review is only
+// reused while they are unchanged.
+func CacheSettings(repoPath string) string {
+	repoCfg, ref := trustedRepoConfig(repoPath)
+	if repoCfg == nil {
+		return ""
+	}
+	_, _, tmpl, _ := reviewTemplateSource(repoPath, repoCfg, ref)
+	return repoCfg.ReviewGuidelines + "\x00" + tmpl
+}
+
+// readRepoFile reads a file named by the repo config, relative to the
+// repo root, at ref, or from the working tree when ref is empty. A
+// missing file is reported as os.ErrNotExist.
+func readRepoFile(repoPath, ref, name string) ([]byte, error) {
+	if ref == "" {
+		return os.ReadFile(filepath.Join(repoPath, filepath.FromSlash(name)))
+	}
+	content, err := git.ReadFile(repoPath, ref, filepath.ToSlash(filepath.Clean(name)))
+	if err != nil && (strings.Contains(err.Error(), "does not exist in") ||
+		strings.Contains(err.Error(), "exists on disk, but not in")) {
+		return nil, fmt.Errorf("%s at %s: %w", name, ref, os.ErrNotExist)
+	}
+	return content, err
+}
+
+// parseReviewTemplate parses a review template, reporting errors under
+// the config key it came from.
+func parseReviewTemplate(key, name, content string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	// Unknown fields only fail at execution, so render once with empty data
+	if err := tmpl.Execute(io.Discard, ReviewTemplateData{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return tmpl, nil
+}
+
+// reviewSystemPrompt returns the system prompt that opens a review: the
+// repo's review template rendered with data for default reviews when one
+// is configured, otherwise the built-in prompt for promptType.
+func reviewSystemPrompt(repoPath, agentName, promptType, reviewType string, data ReviewTemplateData) (string, error) {
+	if !config.IsDefaultReviewType(reviewType) {
+		return GetSystemPrompt(agentName, promptType), nil
+	}
+	tmpl, err := LoadReviewTemplate(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if tmpl == nil {
+		return GetSystemPrompt(agentName, promptType), nil
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render review template: %w", err)
+	}
+	return appendDateLine(sb.String()+noSkillsInstruction, time.Now), nil
+}
@@ -0,0 +1,84 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/git"
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+// ExportedReview is the JSON form of an exported review: the full
+// review (with its job, when loaded), the parsed findings, and the
+// comments left on it.
+type ExportedReview struct {
+	storage.Review
+	Findings []storage.Finding  `json:"findings,omitempty"`
+	Comments []storage.Response `json:"comments,omitempty"`
+}
+
+// ExportReviewJSON renders a review and its comments as indented JSON.
+func ExportReviewJSON(r storage.Review, comments []storage.Response) ([]byte, error) {
+	data, err := json.MarshalIndent(ExportedReview{
+		Review:   r,
+		Findings: storage.ExtractFindings(r.Output),
+		Comments: comments,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode review: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ExportReview renders a review as a Markdown document: a header listing
+// the commit, agent, model, verdict, and timing, then the review output,
+// then any comments in the order they were left.
+func ExportReview(r storage.Review, comments []storage.Response) string {
+	title := fmt.Sprintf("Review #%d", r.JobID)
+	var meta []string
+	add := func(label, value string) {
+		if value != "" {
+			meta = append(meta, fmt.Sprintf("- **%s:** %s", label, value))
+		}
+	}
+	if j := r.Job; j != nil {
+		title += ": " + git.ShortRef(j.GitRef)
+		add("Repo", j.RepoName)
+		commit := j.GitRef
+		if j.CommitSubject != "" {
+			commit += " (" + j.CommitSubject + ")"
+		}
+		add("Commit", commit)
+		add("Agent", r.ProducedBy())
+		if r.ProducedBy() == r.Agent {
+			add("Model", j.Model)
+		}
+		if j.Verdict != nil {
+			verdict := "Fail"
+			if *j.Verdict == "P" {
+				verdict = "Pass"
+			}
+			add("Verdict", verdict)
+		}
+		if j.FinishedAt != nil {
+			add("Finished", j.FinishedAt.Local().Format("2006-01-02 15:04 MST"))
+			if j.StartedAt != nil {
+				add("Duration", j.FinishedAt.Sub(*j.StartedAt).Round(time.Second).String())
+			}
+		}
+	} else {
+		add("Agent", r.ProducedBy())
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n%s\n\n%s\n", title, strings.Join(meta, "\n"), strings.TrimRight(r.Output, "\n"))
+	if len(comments) > 0 {
+		sb.WriteString("\n## Comments\n")
+		for _, c := range comments {
+			fmt.Fprintf(&sb, "\n**%s** (%s):\n\n%s\n", c.Responder, c.CreatedAt.Local().Format("2006-01-02 15:04"), strings.TrimRight(c.Response, "\n"))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package review
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roborev-dev/roborev/internal/storage"
+)
+
+func exportTestReview() storage.Review {
+	started := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	finished := started.Add(95 * time.Second)
+	verdict := "F"
+	return storage.Review{
+		JobID:   42,
+		Agent:   "codex",
+		Summary: "Unsafe query",
+		Output:  "## Review\n\n- High: query uses user input unescaped\n",
+		Job: &storage.ReviewJob{
+			RepoName: "api", GitRef: "abcdef1234567", CommitSubject: "Add search",
+			Model: "gpt-5", StartedAt: &started, FinishedAt: &finished, Verdict: &verdict,
+		},
+	}
+}
+
+func TestExportReview(t *testing.T) {
+	comments := []storage.Response{
+		{Responder: "alice", Response: "Fixed in the next commit.\n", CreatedAt: time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)},
+		{Responder: "bob", Response: "Confirmed.", CreatedAt: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)},
+	}
+
+	md := ExportReview(exportTestReview(), comments)
+	for _, want := range []string{
+		"# Review #42: abcdef1\n",
+		"- **Commit:** abcdef1234567 (Add search)\n",
+		"- **Agent:** codex\n",
+		"- **Model:** gpt-5\n",
+		"- **Verdict:** Fail\n",
+		"- **Duration:** 1m35s\n",
+		"\n## Review\n\n- High: query uses user input unescaped\n",
+		"\n## Comments\n",
+		"**alice** (",
+		"Fixed in the next commit.\n",
+		"**bob** (",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Index(md, "alice") > strings.Index(md, "bob") {
+		t.Errorf("comments out of order:\n%s", md)
+	}
+
+	if md := ExportReview(exportTestReview(), nil); strings.Contains(md, "## Comments") {
+		t.Errorf("markdown without comments should have no Comments section:\n%s", md)
+	}
+}
+
+func TestExportReviewJSON(t *testing.T) {
+	r := exportTestReview()
+	data, err := ExportReviewJSON(r, []storage.Response{{Responder: "alice", Response: "Fixed."}})
+	if err != nil {
+		t.Fatalf("ExportReviewJSON: %v", err)
+	}
+	var got ExportedReview
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.JobID != 42 || got.Output != r.Output || got.Job == nil || got.Job.Model != "gpt-5" {
+		t.Errorf("unexpected JSON export: %+v", got)
+	}
+	if len(got.Findings) != 1 || got.Findings[0].Severity != "high" {
+		t.Errorf("findings = %+v, want one high finding", got.Findings)
+	}
+	if len(got.Comments) != 1 || got.Comments[0].Responder != "alice" {
+		t.Errorf("comments = %+v, want alice's comment", got.Comments)
+	}
+}
@@ -26,3 +26,8 @@ const MaxCommentLen = 60000
 // fails due to agent quota exhaustion rather than a real error.
 // Matches the prefix set by internal/daemon/worker.go.
 const QuotaErrorPrefix = "quota: "
+
+// AuthErrorPrefix is prepended to error messages when a review fails
+// because the agent rejected its credentials (expired or invalid API key,
+// logged out CLI). Matches the prefix set by internal/daemon/worker.go.
+const AuthErrorPrefix = "authentication failed: "
@@ -222,6 +222,12 @@ func IsQuotaFailure(r ReviewResult) bool {
 		strings.HasPrefix(r.Error, QuotaErrorPrefix)
 }
 
+// IsAuthFailure returns true if a job error indicates the agent
+// rejected its credentials.
+func IsAuthFailure(errMsg string) bool {
+	return strings.HasPrefix(errMsg, AuthErrorPrefix)
+}
+
 // CountQuotaFailures returns the number of reviews that failed
 // due to agent quota exhaustion rather than a real error.
 func CountQuotaFailures(reviews []ReviewResult) int {
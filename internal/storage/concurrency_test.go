@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentWritersAcrossHandles(t *testing.T) {
+	// Two handles on one file stand in for the daemon and a CLI process.
+	dbPath := filepath.Join(t.TempDir(), "shared.db")
+	handles := make([]*DB, 2)
+	for i := range handles {
+		db, err := Open(dbPath)
+		if err != nil {
+			t.Fatalf("Open handle %d: %v", i, err)
+		}
+		t.Cleanup(func() { db.Close() })
+		handles[i] = db
+	}
+
+	repo := createRepo(t, handles[0], "/tmp/concurrent-repo")
+
+	const workers = 8
+	const jobsPerWorker = 10
+	errs := make(chan error, workers*jobsPerWorker)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db := handles[w%len(handles)]
+			workerID := fmt.Sprintf("worker-%d", w)
+			for i := range jobsPerWorker {
+				_, err := db.EnqueueJob(EnqueueOpts{
+					RepoID: repo.ID,
+					GitRef: fmt.Sprintf("base-%d-%d..head", w, i),
+					Agent:  "test",
+				})
+				if err != nil {
+					errs <- fmt.Errorf("enqueue: %w", err)
+					continue
+				}
+				job, err := db.ClaimJob(workerID)
+				if err != nil {
+					errs <- fmt.Errorf("claim: %w", err)
+					continue
+				}
+				if job == nil {
+					continue // Another worker claimed it first
+				}
+				if err := db.CompleteJob(job.ID, "test", "prompt", "No issues found."); err != nil {
+					errs <- fmt.Errorf("complete job %d: %w", job.ID, err)
+					continue
+				}
+				if _, err := db.AddCommentToJob(job.ID, workerID, "ack"); err != nil {
+					errs <- fmt.Errorf("comment on job %d: %w", job.ID, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	var total int
+	if err := handles[1].QueryRow(`SELECT COUNT(*) FROM review_jobs`).Scan(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != workers*jobsPerWorker {
+		t.Errorf("expected %d jobs, got %d", workers*jobsPerWorker, total)
+	}
+}
@@ -0,0 +1,78 @@
+package storage
+
+import "strings"
+
+// Confidence levels an agent may state in its output.
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// Low-confidence policies, applied when an agent passes a review but says
+// its confidence is low.
+//
+// Verdicts are stored as pass/fail (reviews.verdict_bool) and synced,
+// filtered and gated on as such, so LowConfidenceNeedsHuman is a failing
+// verdict whose output is flagged for a human rather than a third verdict:
+// it blocks wait, hooks and CI like any fail, and the flag says why.
+const (
+	LowConfidenceIgnore     = "ignore"      // Keep the pass (default)
+	LowConfidenceWarn       = "warn"        // Keep the pass but prepend lowConfidenceWarning
+	LowConfidenceFail       = "fail"        // Downgrade the pass to a fail
+	LowConfidenceNeedsHuman = "needs_human" // Downgrade to a fail and prepend lowConfidenceNeedsHuman
+)
+
+// lowConfidenceWarning is prepended to the output of a low-confidence pass
+// under LowConfidenceWarn.
+const lowConfidenceWarning = "> **Warning:** the agent reported low confidence in this review; verify it before relying on the pass.\n\n"
+
+// lowConfidenceNeedsHuman is prepended to the output of a low-confidence
+// pass under LowConfidenceNeedsHuman.
+const lowConfidenceNeedsHuman = "> **NEEDS_HUMAN:** the agent passed this review with low confidence; a human must review it before it can pass.\n\n"
+
+// ParseConfidence returns the confidence the agent stated in a line such as
+// "Confidence: low" or "**Confidence**: High", lowercased. The last such
+// line wins, since agents usually state confidence at the end. Returns ""
+// when the output states no recognized confidence.
+func ParseConfidence(output string) string {
+	confidence := ""
+	for line := range strings.SplitSeq(output, "\n") {
+		trimmed := stripListMarker(stripMarkdown(strings.ToLower(strings.TrimSpace(line))))
+		rest, ok := strings.CutPrefix(trimmed, "confidence")
+		if !ok {
+			continue
+		}
+		rest, ok = strings.CutPrefix(strings.TrimSpace(rest), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		switch level := strings.Trim(fields[0], ".,;*_()"); level {
+		case ConfidenceLow, ConfidenceMedium, ConfidenceHigh:
+			confidence = level
+		}
+	}
+	return confidence
+}
+
+// applyLowConfidence returns the verdict after the policy's low-confidence
+// handling, and the note, if any, to prepend to the stored output. Only a
+// pass with low confidence is affected.
+func (p VerdictPolicy) applyLowConfidence(verdict, confidence string) (string, string) {
+	if verdict != "P" || confidence != ConfidenceLow {
+		return verdict, ""
+	}
+	switch p.LowConfidence {
+	case LowConfidenceFail:
+		return "F", ""
+	case LowConfidenceNeedsHuman:
+		return "F", lowConfidenceNeedsHuman
+	case LowConfidenceWarn:
+		return verdict, lowConfidenceWarning
+	}
+	return verdict, ""
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfidence(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"plain", "No issues found.\n\nConfidence: low", "low"},
+		{"markdown bold", "No issues found.\n**Confidence:** High", "high"},
+		{"list item", "- confidence: medium (limited context)", "medium"},
+		{"trailing punctuation", "Confidence: low.", "low"},
+		{"last wins", "Confidence: high\n...\nConfidence: low", "low"},
+		{"unrecognized level", "Confidence: 70%", ""},
+		{"no label", "I have low confidence in the cache change", ""},
+		{"absent", "No issues found.", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseConfidence(tt.output); got != tt.want {
+				t.Errorf("ParseConfidence(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLowConfidencePolicy(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	lowPass := "No issues found.\n\nConfidence: low"
+	complete := func(sha, output string, policy VerdictPolicy) *Review {
+		t.Helper()
+		_, _, job := createJobChain(t, db, "/tmp/confidence-repo", sha)
+		claimJob(t, db, "worker-1")
+		if err := db.CompleteJobWithPolicy(job.ID, "codex", "prompt", output, policy); err != nil {
+			t.Fatalf("CompleteJobWithPolicy: %v", err)
+		}
+		review, err := db.GetReviewByJobID(job.ID)
+		if err != nil {
+			t.Fatalf("GetReviewByJobID: %v", err)
+		}
+		return review
+	}
+
+	t.Run("default keeps low-confidence pass", func(t *testing.T) {
+		r := complete("default", lowPass, VerdictPolicy{})
+		if r.VerdictBool == nil || *r.VerdictBool != 1 {
+			t.Errorf("verdict = %v, want pass", r.VerdictBool)
+		}
+		if r.Confidence != ConfidenceLow {
+			t.Errorf("Confidence = %q, want low", r.Confidence)
+		}
+		if r.Output != lowPass {
+			t.Errorf("output changed: %q", r.Output)
+		}
+	})
+
+	t.Run("fail downgrades low-confidence pass", func(t *testing.T) {
+		r := complete("fail", lowPass, VerdictPolicy{LowConfidence: LowConfidenceFail})
+		if r.VerdictBool == nil || *r.VerdictBool != 0 {
+			t.Errorf("verdict = %v, want fail", r.VerdictBool)
+		}
+	})
+
+	t.Run("needs_human fails and flags low-confidence pass", func(t *testing.T) {
+		r := complete("needs-human", lowPass, VerdictPolicy{LowConfidence: LowConfidenceNeedsHuman})
+		if r.VerdictBool == nil || *r.VerdictBool != 0 {
+			t.Errorf("verdict = %v, want fail", r.VerdictBool)
+		}
+		if !strings.HasPrefix(r.Output, lowConfidenceNeedsHuman) || !strings.HasSuffix(r.Output, lowPass) {
+			t.Errorf("expected NEEDS_HUMAN note before output, got %q", r.Output)
+		}
+	})
+
+	t.Run("warn keeps pass and adds warning", func(t *testing.T) {
+		r := complete("warn", lowPass, VerdictPolicy{LowConfidence: LowConfidenceWarn})
+		if r.VerdictBool == nil || *r.VerdictBool != 1 {
+			t.Errorf("verdict = %v, want pass", r.VerdictBool)
+		}
+		if !strings.HasPrefix(r.Output, lowConfidenceWarning) || !strings.HasSuffix(r.Output, lowPass) {
+			t.Errorf("expected warning before output, got %q", r.Output)
+		}
+	})
+
+	t.Run("high-confidence pass is untouched", func(t *testing.T) {
+		r := complete("high", "No issues found.\nConfidence: high", VerdictPolicy{LowConfidence: LowConfidenceFail})
+		if r.VerdictBool == nil || *r.VerdictBool != 1 {
+			t.Errorf("verdict = %v, want pass", r.VerdictBool)
+		}
+	})
+}
+
+func TestVerdictPolicyVerdict(t *testing.T) {
+	lowPass := "No issues found.\nConfidence: low"
+	if got := (VerdictPolicy{LowConfidence: LowConfidenceFail}).Verdict(lowPass); got != "F" {
+		t.Errorf("low-confidence pass under fail policy = %q, want F", got)
+	}
+	if got := (VerdictPolicy{FailThreshold: "high"}).Verdict("- Medium: unchecked error from Close"); got != "P" {
+		t.Errorf("medium finding under high threshold = %q, want P", got)
+	}
+	if got := (VerdictPolicy{}).Verdict("- Medium: unchecked error from Close"); got != "F" {
+		t.Errorf("medium finding under default policy = %q, want F", got)
+	}
+}
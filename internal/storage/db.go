@@ -3,15 +3,18 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/roborev-dev/roborev/internal/config"
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 const schema = `
@@ -105,6 +108,13 @@ CREATE TABLE IF NOT EXISTS ci_pr_batch_jobs (
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS finding_resolutions (
+  job_id INTEGER NOT NULL REFERENCES review_jobs(id),
+  finding_idx INTEGER NOT NULL,
+  resolved_at TEXT NOT NULL DEFAULT (datetime('now')),
+  PRIMARY KEY (job_id, finding_idx)
+);
+
 CREATE INDEX IF NOT EXISTS idx_review_jobs_status ON review_jobs(status);
 CREATE INDEX IF NOT EXISTS idx_review_jobs_repo ON review_jobs(repo_id);
 CREATE INDEX IF NOT EXISTS idx_review_jobs_git_ref ON review_jobs(git_ref);
@@ -132,8 +142,10 @@ func Open(dbPath string) (*DB, error) {
 
 	// Open with WAL mode and busy timeout.
 	// 30s busy_timeout gives enough headroom for concurrent writers
-	// (worker pool + sync worker) to wait for locks rather than failing.
-	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(30000)")
+	// (worker pool + sync worker, and CLI processes sharing the file) to
+	// wait for locks rather than failing. busy_timeout is set first so the
+	// journal_mode switch itself waits on a locked database.
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(30000)&_pragma=journal_mode(WAL)")
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -155,6 +167,55 @@ func Open(dbPath string) (*DB, error) {
 	return wrapped, nil
 }
 
+// Lock retry parameters for retryOnBusy. busy_timeout already waits on
+// most contention; these cover the cases SQLite reports immediately.
+const (
+	busyRetryAttempts = 5
+	busyRetryDelay    = 50 * time.Millisecond
+)
+
+// isBusyError reports whether err is a transient SQLITE_BUSY or
+// SQLITE_LOCKED error (including extended codes).
+func isBusyError(err error) bool {
+	var serr *sqlite.Error
+	if !errors.As(err, &serr) {
+		return false
+	}
+	code := serr.Code() & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+// retryOnBusy runs fn, retrying with linear backoff while it fails with a
+// transient lock error. fn must be safe to re-run, i.e. roll back any
+// partial work before returning an error.
+func retryOnBusy(fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !isBusyError(err) || attempt == busyRetryAttempts {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * busyRetryDelay)
+	}
+}
+
+// beginImmediate starts a BEGIN IMMEDIATE transaction on a dedicated
+// connection. Taking the write lock up front lets a transaction that reads
+// before it writes wait on busy_timeout; a deferred one fails with
+// "database is locked" if another connection wrote in between. The caller
+// must COMMIT or ROLLBACK and then close the connection.
+func (db *DB) beginImmediate(ctx context.Context) (*sql.Conn, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
 // migrate runs any needed migrations for existing databases
 func (db *DB) migrate() error {
 	// Migration: add prompt column to review_jobs if missing
@@ -655,11 +716,263 @@ func (db *DB) migrate() error {
 		}
 	}
 
+	// Migration: add summary column to reviews if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('reviews') WHERE name = 'summary'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check summary column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE reviews ADD COLUMN summary TEXT`)
+		if err != nil {
+			return fmt.Errorf("add summary column: %w", err)
+		}
+	}
+
+	// Migration: add token usage and cost columns to reviews if missing.
+	// Legacy reviews keep NULLs, meaning usage was not recorded.
+	for _, col := range []struct{ name, typ string }{
+		{"input_tokens", "INTEGER"},
+		{"output_tokens", "INTEGER"},
+		{"cost_usd", "REAL"},
+	} {
+		err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('reviews') WHERE name = ?`, col.name).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("check %s column: %w", col.name, err)
+		}
+		if count == 0 {
+			_, err = db.Exec(`ALTER TABLE reviews ADD COLUMN ` + col.name + ` ` + col.typ)
+			if err != nil {
+				return fmt.Errorf("add %s column: %w", col.name, err)
+			}
+		}
+	}
+
+	// Migration: add parent_response_id column to responses if missing.
+	// NULL marks a top-level comment; replies point at the comment they answer.
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('responses') WHERE name = 'parent_response_id'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check parent_response_id column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE responses ADD COLUMN parent_response_id INTEGER`)
+		if err != nil {
+			return fmt.Errorf("add parent_response_id column: %w", err)
+		}
+	}
+
+	// Migration: add updated_at and deleted_at columns to responses if
+	// missing. Edits bump updated_at; deletes set deleted_at instead of
+	// removing the row, so the tombstone can be synced.
+	for _, col := range []string{"updated_at", "deleted_at"} {
+		err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('responses') WHERE name = ?`, col).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("check responses %s column: %w", col, err)
+		}
+		if count == 0 {
+			_, err = db.Exec(`ALTER TABLE responses ADD COLUMN ` + col + ` TEXT`)
+			if err != nil {
+				return fmt.Errorf("add responses %s column: %w", col, err)
+			}
+		}
+	}
+
+	// Migration: add effective_agent column to reviews if missing. NULL
+	// means the review predates fallback agents and reviews.agent wrote it.
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('reviews') WHERE name = 'effective_agent'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check effective_agent column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE reviews ADD COLUMN effective_agent TEXT`)
+		if err != nil {
+			return fmt.Errorf("add effective_agent column: %w", err)
+		}
+	}
+
+	// Migration: add confidence column to reviews if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('reviews') WHERE name = 'confidence'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check confidence column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE reviews ADD COLUMN confidence TEXT`)
+		if err != nil {
+			return fmt.Errorf("add confidence column: %w", err)
+		}
+	}
+
+	// Migration: add retry_of_job_id column to review_jobs if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('review_jobs') WHERE name = 'retry_of_job_id'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check retry_of_job_id column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE review_jobs ADD COLUMN retry_of_job_id INTEGER`)
+		if err != nil {
+			return fmt.Errorf("add retry_of_job_id column: %w", err)
+		}
+	}
+
+	// Migration: add supersedes_job_id column to review_jobs if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('review_jobs') WHERE name = 'supersedes_job_id'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check supersedes_job_id column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE review_jobs ADD COLUMN supersedes_job_id INTEGER`)
+		if err != nil {
+			return fmt.Errorf("add supersedes_job_id column: %w", err)
+		}
+	}
+
+	// Migration: add diff_hash and cached_from_job_id columns to review_jobs if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('review_jobs') WHERE name = 'diff_hash'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check diff_hash column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE review_jobs ADD COLUMN diff_hash TEXT`)
+		if err != nil {
+			return fmt.Errorf("add diff_hash column: %w", err)
+		}
+		_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_review_jobs_diff_hash ON review_jobs(repo_id, diff_hash)`)
+		if err != nil {
+			return fmt.Errorf("create idx_review_jobs_diff_hash: %w", err)
+		}
+	}
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('review_jobs') WHERE name = 'cached_from_job_id'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check cached_from_job_id column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE review_jobs ADD COLUMN cached_from_job_id INTEGER`)
+		if err != nil {
+			return fmt.Errorf("add cached_from_job_id column: %w", err)
+		}
+	}
+
+	// Migration: add attempts column to review_jobs if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('review_jobs') WHERE name = 'attempts'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check attempts column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE review_jobs ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0`)
+		if err != nil {
+			return fmt.Errorf("add attempts column: %w", err)
+		}
+	}
+
+	// Migration: add range_commits column to review_jobs if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('review_jobs') WHERE name = 'range_commits'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check range_commits column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE review_jobs ADD COLUMN range_commits INTEGER NOT NULL DEFAULT 0`)
+		if err != nil {
+			return fmt.Errorf("add range_commits column: %w", err)
+		}
+	}
+
+	// Migration: add retry_errors, retry_after, range_label, and applied_commit_sha columns to review_jobs if missing
+	for _, col := range []string{"retry_errors", "retry_after", "range_label", "applied_commit_sha"} {
+		err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('review_jobs') WHERE name = ?`, col).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("check %s column: %w", col, err)
+		}
+		if count == 0 {
+			_, err = db.Exec(`ALTER TABLE review_jobs ADD COLUMN ` + col + ` TEXT`)
+			if err != nil {
+				return fmt.Errorf("add %s column: %w", col, err)
+			}
+		}
+	}
+
+	// Migration: add severity_counts column to reviews if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('reviews') WHERE name = 'severity_counts'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check severity_counts column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE reviews ADD COLUMN severity_counts TEXT`)
+		if err != nil {
+			return fmt.Errorf("add severity_counts column: %w", err)
+		}
+	}
+
+	// Migration: add seen_by and seen_at columns to reviews if missing
+	for _, col := range []string{"seen_by", "seen_at"} {
+		err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('reviews') WHERE name = ?`, col).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("check %s column: %w", col, err)
+		}
+		if count == 0 {
+			_, err = db.Exec(`ALTER TABLE reviews ADD COLUMN ` + col + ` TEXT`)
+			if err != nil {
+				return fmt.Errorf("add %s column: %w", col, err)
+			}
+		}
+	}
+
+	// Migration: add reverted_by column to reviews if missing
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('reviews') WHERE name = 'reverted_by'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check reverted_by column: %w", err)
+	}
+	if count == 0 {
+		_, err = db.Exec(`ALTER TABLE reviews ADD COLUMN reverted_by TEXT`)
+		if err != nil {
+			return fmt.Errorf("add reverted_by column: %w", err)
+		}
+	}
+
 	// Run sync-related migrations
 	if err := db.migrateSyncColumns(); err != nil {
 		return err
 	}
 
+	if err := db.migrateReviewSearch(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// migrateReviewSearch creates the reviews_fts full-text index over review
+// output and the triggers that keep it in sync with the reviews table. The
+// index is rebuilt from existing rows when it is first created.
+func (db *DB) migrateReviewSearch() error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'reviews_fts'`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("check reviews_fts table: %w", err)
+	}
+	if count == 0 {
+		if _, err := db.Exec(`CREATE VIRTUAL TABLE reviews_fts USING fts5(output, content='reviews', content_rowid='id')`); err != nil {
+			return fmt.Errorf("create reviews_fts: %w", err)
+		}
+		if _, err := db.Exec(`INSERT INTO reviews_fts(reviews_fts) VALUES('rebuild')`); err != nil {
+			return fmt.Errorf("rebuild reviews_fts: %w", err)
+		}
+	}
+
+	for _, trigger := range []string{
+		`CREATE TRIGGER IF NOT EXISTS reviews_fts_insert AFTER INSERT ON reviews BEGIN
+			INSERT INTO reviews_fts(rowid, output) VALUES (new.id, new.output);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS reviews_fts_delete AFTER DELETE ON reviews BEGIN
+			INSERT INTO reviews_fts(reviews_fts, rowid, output) VALUES ('delete', old.id, old.output);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS reviews_fts_update AFTER UPDATE OF output ON reviews BEGIN
+			INSERT INTO reviews_fts(reviews_fts, rowid, output) VALUES ('delete', old.id, old.output);
+			INSERT INTO reviews_fts(rowid, output) VALUES (new.id, new.output);
+		END`,
+	} {
+		if _, err := db.Exec(trigger); err != nil {
+			return fmt.Errorf("create reviews_fts trigger: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -1161,6 +1474,42 @@ func (db *DB) ResetStaleJobs() error {
 	return err
 }
 
+// RecoverStuckJobs resets running jobs that no worker owns, skipping the
+// job IDs in activeIDs. Stuck jobs are requeued with their retry count kept,
+// or marked failed when fail is set. Returns the number of jobs recovered.
+func (db *DB) RecoverStuckJobs(activeIDs []int64, fail bool) (int, error) {
+	now := time.Now().Format(time.RFC3339)
+	query := `
+		UPDATE review_jobs
+		SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL
+		WHERE status = 'running'`
+	args := []any{}
+	if fail {
+		query = `
+		UPDATE review_jobs
+		SET status = 'failed', finished_at = ?, error = ?, updated_at = ?
+		WHERE status = 'running'`
+		args = append(args, now, "recovered: job was stuck in running with no active worker", now)
+	}
+	if len(activeIDs) > 0 {
+		placeholders := strings.Repeat("?,", len(activeIDs))
+		query += ` AND id NOT IN (` + placeholders[:len(placeholders)-1] + `)`
+		for _, id := range activeIDs {
+			args = append(args, id)
+		}
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
 // CountStalledJobs returns the number of jobs that have been running longer than the threshold
 func (db *DB) CountStalledJobs(threshold time.Duration) (int, error) {
 	// Use threshold in seconds for SQLite datetime arithmetic
@@ -1179,3 +1528,277 @@ func (db *DB) CountStalledJobs(threshold time.Duration) (int, error) {
 	}
 	return count, nil
 }
+
+// VacuumResult reports the database size before and after a vacuum.
+type VacuumResult struct {
+	SizeBefore int64 `json:"size_before"`
+	SizeAfter  int64 `json:"size_after"`
+}
+
+// Vacuum merges the review search index into a single segment, rebuilds
+// the database file to reclaim space left behind by deleted rows,
+// checkpoints the WAL so it does not retain the rewritten pages, and
+// refreshes query planner statistics. Callers should pause job claims first;
+// concurrent writers block on busy_timeout until the vacuum finishes.
+func (db *DB) Vacuum() (*VacuumResult, error) {
+	before, err := db.sizeBytes()
+	if err != nil {
+		return nil, fmt.Errorf("measure size: %w", err)
+	}
+
+	// Optimize before VACUUM so the pages of the merged segments are reclaimed
+	if _, err := db.Exec(`INSERT INTO reviews_fts(reviews_fts) VALUES('optimize')`); err != nil {
+		return nil, fmt.Errorf("optimize reviews_fts: %w", err)
+	}
+	if _, err := db.Exec(`VACUUM`); err != nil {
+		return nil, fmt.Errorf("vacuum: %w", err)
+	}
+	if _, err := db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return nil, fmt.Errorf("checkpoint wal: %w", err)
+	}
+	if _, err := db.Exec(`PRAGMA optimize`); err != nil {
+		return nil, fmt.Errorf("optimize: %w", err)
+	}
+
+	after, err := db.sizeBytes()
+	if err != nil {
+		return nil, fmt.Errorf("measure size: %w", err)
+	}
+	return &VacuumResult{SizeBefore: before, SizeAfter: after}, nil
+}
+
+// sizeBytes returns the size of the main database in bytes (excluding WAL).
+func (db *DB) sizeBytes() (int64, error) {
+	var pageCount, pageSize int64
+	if err := db.QueryRow(`PRAGMA page_count`).Scan(&pageCount); err != nil {
+		return 0, err
+	}
+	if err := db.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return pageCount * pageSize, nil
+}
+
+// PruneResult reports the rows removed by PruneJobs.
+type PruneResult struct {
+	Jobs     int64 `json:"jobs"`
+	Reviews  int64 `json:"reviews"`
+	Comments int64 `json:"comments"`
+}
+
+// prunableStatuses are the terminal statuses PruneJobs may delete. Queued and
+// running jobs are never pruned.
+var prunableStatuses = []JobStatus{
+	JobStatusDone, JobStatusFailed, JobStatusCanceled, JobStatusApplied, JobStatusRebased,
+}
+
+// PruneJobs deletes finished jobs whose finished_at is older than olderThan,
+// along with their reviews, comments, and CI batch links. Jobs with a status
+// in keepStatuses are kept. Deletion runs in one write transaction, so it is
+// safe while the daemon is running.
+func (db *DB) PruneJobs(olderThan time.Duration, keepStatuses []JobStatus) (*PruneResult, error) {
+	return db.PruneReviews(PruneOpts{OlderThan: olderThan, KeepStatuses: keepStatuses})
+}
+
+// PruneOpts selects the finished jobs PruneReviews deletes. A job is pruned
+// when it matches either limit; with neither set nothing is pruned.
+type PruneOpts struct {
+	OlderThan    time.Duration // Prune jobs finished longer ago than this (0 = no age limit)
+	KeepLast     int           // Prune jobs beyond the newest KeepLast finished jobs per repo (0 = no count limit)
+	KeepStatuses []JobStatus   // Never prune jobs with these statuses
+	DryRun       bool          // Report what would be pruned without deleting anything
+}
+
+// PruneReviews deletes finished jobs selected by opts, along with their
+// reviews, comments, and CI batch links. Queued and running jobs are never
+// pruned, nor are jobs a CI PR review or a fix job refers to. Jobs with a kept status still count toward KeepLast. Deletion
+// runs in one write transaction, so it is safe while the daemon is
+// running; a dry run rolls the transaction back and reports the counts.
+func (db *DB) PruneReviews(opts PruneOpts) (*PruneResult, error) {
+	var args []any
+	for _, s := range prunableStatuses {
+		args = append(args, string(s))
+	}
+	statusIn := "?" + strings.Repeat(", ?", len(args)-1)
+	var deletable []any
+	for _, s := range prunableStatuses {
+		if !slices.Contains(opts.KeepStatuses, s) {
+			deletable = append(deletable, string(s))
+		}
+	}
+	if len(deletable) == 0 || (opts.OlderThan <= 0 && opts.KeepLast <= 0) {
+		return &PruneResult{}, nil
+	}
+	args = append(args, deletable...)
+
+	var limits []string
+	if opts.OlderThan > 0 {
+		limits = append(limits, `datetime(finished_at) < datetime(?)`)
+		args = append(args, time.Now().Add(-opts.OlderThan).UTC().Format(time.RFC3339))
+	}
+	if opts.KeepLast > 0 {
+		limits = append(limits, `rank > ?`)
+		args = append(args, opts.KeepLast)
+	}
+	jobIDs := `SELECT id FROM (
+			SELECT id, status, finished_at,
+			       ROW_NUMBER() OVER (PARTITION BY repo_id ORDER BY datetime(finished_at) DESC, id DESC) AS rank
+			FROM review_jobs
+			WHERE status IN (` + statusIn + `) AND finished_at IS NOT NULL
+		) WHERE status IN (?` + strings.Repeat(", ?", len(deletable)-1) + `)
+		AND (` + strings.Join(limits, " OR ") + `)`
+
+	var result PruneResult
+	err := retryOnBusy(func() error {
+		result = PruneResult{}
+		ctx := context.Background()
+		conn, err := db.beginImmediate(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		committed := false
+		defer func() {
+			if !committed {
+				_, _ = conn.ExecContext(ctx, "ROLLBACK")
+			}
+		}()
+
+		if err := deleteJobs(ctx, conn, jobIDs, args, &result); err != nil {
+			return err
+		}
+		if opts.DryRun {
+			return nil
+		}
+		if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return err
+		}
+		committed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// deleteJobs deletes the jobs selected by jobIDs (a subquery or placeholder
+// list bound to args) along with their reviews, comments, and CI batch
+// links, adding the counts to result. Jobs a CI PR review or a fix job
+// still points at are skipped; retry, supersede, and cache links to the
+// deleted jobs are cleared.
+func deleteJobs(ctx context.Context, conn *sql.Conn, jobIDs string, args []any, result *PruneResult) error {
+	// ci_pr_reviews rows are kept so the CI poller doesn't re-review old
+	// PR heads, so their jobs must stay too
+	jobIDs = `SELECT id FROM review_jobs d WHERE id IN (` + jobIDs + `)
+		AND NOT EXISTS (SELECT 1 FROM ci_pr_reviews c WHERE c.job_id = d.id)
+		AND NOT EXISTS (SELECT 1 FROM review_jobs f WHERE f.parent_job_id = d.id)`
+	exec := func(query string) (int64, error) {
+		res, err := conn.ExecContext(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+
+	// Delete dependents before the jobs they reference
+	n, err := exec(`DELETE FROM responses WHERE job_id IN (` + jobIDs + `)`)
+	if err != nil {
+		return fmt.Errorf("delete comments: %w", err)
+	}
+	result.Comments += n
+	if n, err = exec(`DELETE FROM reviews WHERE job_id IN (` + jobIDs + `)`); err != nil {
+		return fmt.Errorf("delete reviews: %w", err)
+	}
+	result.Reviews += n
+	if _, err = exec(`DELETE FROM ci_pr_batch_jobs WHERE job_id IN (` + jobIDs + `)`); err != nil {
+		return fmt.Errorf("delete batch links: %w", err)
+	}
+	if _, err = exec(`DELETE FROM finding_resolutions WHERE job_id IN (` + jobIDs + `)`); err != nil {
+		return fmt.Errorf("delete finding resolutions: %w", err)
+	}
+	for _, col := range []string{"retry_of_job_id", "supersedes_job_id", "cached_from_job_id"} {
+		if _, err = exec(`UPDATE review_jobs SET ` + col + ` = NULL WHERE ` + col + ` IN (` + jobIDs + `)`); err != nil {
+			return fmt.Errorf("clear %s: %w", col, err)
+		}
+	}
+	if n, err = exec(`DELETE FROM review_jobs WHERE id IN (` + jobIDs + `)`); err != nil {
+		return fmt.Errorf("delete jobs: %w", err)
+	}
+	result.Jobs += n
+	return nil
+}
+
+// PruneCommitReviews deletes the oldest reviews of a commit beyond the newest
+// keep, along with their jobs, comments, and CI batch links. Addressed
+// reviews, reviews a fix job was created from, and reviews a CI PR review
+// refers to are protected: they are never pruned and don't count toward
+// keep. A keep of zero or less prunes
+// nothing.
+func (db *DB) PruneCommitReviews(commitID int64, keep int) (*PruneResult, error) {
+	if keep <= 0 {
+		return &PruneResult{}, nil
+	}
+
+	var result PruneResult
+	err := retryOnBusy(func() error {
+		result = PruneResult{}
+		ctx := context.Background()
+		conn, err := db.beginImmediate(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		committed := false
+		defer func() {
+			if !committed {
+				_, _ = conn.ExecContext(ctx, "ROLLBACK")
+			}
+		}()
+
+		// Collect the IDs up front: the ranking joins reviews, which
+		// deleteJobs removes before the jobs themselves.
+		rows, err := conn.QueryContext(ctx, `
+			SELECT id FROM (
+				SELECT j.id, ROW_NUMBER() OVER (ORDER BY rv.id DESC) AS rank
+				FROM review_jobs j
+				JOIN reviews rv ON rv.job_id = j.id
+				WHERE j.commit_id = ? AND rv.addressed = 0
+				AND NOT EXISTS (SELECT 1 FROM review_jobs f WHERE f.parent_job_id = j.id)
+				AND NOT EXISTS (SELECT 1 FROM ci_pr_reviews c WHERE c.job_id = j.id)
+			) WHERE rank > ?`, commitID, keep)
+		if err != nil {
+			return fmt.Errorf("select reviews to prune: %w", err)
+		}
+		var args []any
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			args = append(args, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return nil
+		}
+
+		jobIDs := "?" + strings.Repeat(", ?", len(args)-1)
+		if err := deleteJobs(ctx, conn, jobIDs, args, &result); err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return err
+		}
+		committed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -586,6 +588,45 @@ func TestMarkReviewAddressed(t *testing.T) {
 	}
 }
 
+func TestMarkReviewsReverted(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo, _, job := createJobChain(t, db, "/tmp/test-repo", "reverted123")
+	db.ClaimJob("worker-1")
+	if err := db.CompleteJob(job.ID, "codex", "prompt", "**Verdict: FAIL**\n- Off-by-one"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+	other := createRepo(t, db, "/tmp/other-repo")
+
+	n, err := db.MarkReviewsReverted(other.ID, "reverted123", "revert456")
+	if err != nil {
+		t.Fatalf("MarkReviewsReverted failed: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected no reviews updated in another repo, got %d", n)
+	}
+
+	n, err = db.MarkReviewsReverted(repo.ID, "reverted123", "revert456")
+	if err != nil {
+		t.Fatalf("MarkReviewsReverted failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 review updated, got %d", n)
+	}
+
+	review, err := db.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID failed: %v", err)
+	}
+	if !review.Addressed {
+		t.Error("expected reverted review to be addressed")
+	}
+	if review.RevertedBy != "revert456" {
+		t.Errorf("expected RevertedBy revert456, got %q", review.RevertedBy)
+	}
+}
+
 func TestMarkReviewAddressedNotFound(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -716,6 +757,121 @@ func TestJobCounts(t *testing.T) {
 	}
 }
 
+func TestGetQueueDepth(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	queued, running, err := db.GetQueueDepth()
+	if err != nil {
+		t.Fatalf("GetQueueDepth failed: %v", err)
+	}
+	if queued != 0 || running != 0 {
+		t.Errorf("Expected empty queue, got %d queued, %d running", queued, running)
+	}
+
+	alpha := createRepo(t, db, filepath.Join(t.TempDir(), "alpha"))
+	beta := createRepo(t, db, filepath.Join(t.TempDir(), "beta"))
+	idle := createRepo(t, db, filepath.Join(t.TempDir(), "idle"))
+	// A finished job doesn't count toward any repo's depth
+	enqueueJob(t, db, idle.ID, createCommit(t, db, idle.ID, "i1").ID, "i1")
+	done := claimJob(t, db, "w0")
+	if err := db.CompleteJob(done.ID, "codex", "p", "o"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	for _, sha := range []string{"a1", "a2", "a3"} {
+		enqueueJob(t, db, alpha.ID, createCommit(t, db, alpha.ID, sha).ID, sha)
+	}
+	enqueueJob(t, db, beta.ID, createCommit(t, db, beta.ID, "b1").ID, "b1")
+	claimJob(t, db, "w1") // a1
+	claimJob(t, db, "w2") // a2
+
+	queued, running, err = db.GetQueueDepth()
+	if err != nil {
+		t.Fatalf("GetQueueDepth failed: %v", err)
+	}
+	if queued != 2 || running != 2 {
+		t.Errorf("Expected 2 queued, 2 running, got %d queued, %d running", queued, running)
+	}
+
+	byRepo, err := db.GetQueueDepthByRepo()
+	if err != nil {
+		t.Fatalf("GetQueueDepthByRepo failed: %v", err)
+	}
+	want := []RepoQueueDepth{
+		{RepoID: alpha.ID, RepoName: "alpha", RepoPath: alpha.RootPath, Queued: 1, Running: 2},
+		{RepoID: beta.ID, RepoName: "beta", RepoPath: beta.RootPath, Queued: 1, Running: 0},
+	}
+	if len(byRepo) != len(want) {
+		t.Fatalf("Expected %d repos, got %+v", len(want), byRepo)
+	}
+	for i := range want {
+		if byRepo[i] != want[i] {
+			t.Errorf("byRepo[%d] = %+v, want %+v", i, byRepo[i], want[i])
+		}
+	}
+}
+
+func TestRecoverStuckJobs(t *testing.T) {
+	setup := func(t *testing.T) (*DB, []*ReviewJob) {
+		db := openTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		repo := createRepo(t, db, "/tmp/recover-repo")
+		var jobs []*ReviewJob
+		for _, sha := range []string{"stuck-1", "stuck-2", "active"} {
+			job := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, sha).ID, sha)
+			claimJob(t, db, "worker-1")
+			jobs = append(jobs, job)
+		}
+		// A queued job must not be touched
+		jobs = append(jobs, enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "queued").ID, "queued"))
+		return db, jobs
+	}
+
+	t.Run("requeues stuck jobs", func(t *testing.T) {
+		db, jobs := setup(t)
+		if _, err := db.Exec(`UPDATE review_jobs SET retry_count = 2 WHERE id = ?`, jobs[0].ID); err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := db.RecoverStuckJobs([]int64{jobs[2].ID}, false)
+		if err != nil {
+			t.Fatalf("RecoverStuckJobs: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("expected 2 recovered, got %d", n)
+		}
+		for i, want := range []JobStatus{JobStatusQueued, JobStatusQueued, JobStatusRunning, JobStatusQueued} {
+			got, _ := db.GetJobByID(jobs[i].ID)
+			if got.Status != want {
+				t.Errorf("job %s: status = %s, want %s", got.GitRef, got.Status, want)
+			}
+		}
+		if count, _ := db.GetJobRetryCount(jobs[0].ID); count != 2 {
+			t.Errorf("expected retry_count preserved, got %d", count)
+		}
+	})
+
+	t.Run("fails stuck jobs", func(t *testing.T) {
+		db, jobs := setup(t)
+
+		n, err := db.RecoverStuckJobs(nil, true)
+		if err != nil {
+			t.Fatalf("RecoverStuckJobs: %v", err)
+		}
+		if n != 3 {
+			t.Errorf("expected 3 recovered, got %d", n)
+		}
+		got, _ := db.GetJobByID(jobs[0].ID)
+		if got.Status != JobStatusFailed || !strings.Contains(got.Error, "stuck") {
+			t.Errorf("expected failed with stuck error, got %s %q", got.Status, got.Error)
+		}
+		if got, _ := db.GetJobByID(jobs[3].ID); got.Status != JobStatusQueued {
+			t.Errorf("queued job changed to %s", got.Status)
+		}
+	})
+}
+
 func TestCountStalledJobs(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -834,6 +990,66 @@ func TestRetryJob(t *testing.T) {
 	}
 }
 
+func TestRetryJobWithBackoff(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, _, job := createJobChain(t, db, "/tmp/test-repo", "retry-backoff")
+	claimJob(t, db, "worker-1")
+
+	retried, err := db.RetryJobWithBackoff(job.ID, "worker-1", 3, "agent: exit status 1", time.Hour)
+	if err != nil {
+		t.Fatalf("RetryJobWithBackoff failed: %v", err)
+	}
+	if !retried {
+		t.Fatal("expected retry to succeed")
+	}
+
+	// The job is queued but can't be claimed until the backoff passes
+	if claimed, err := db.ClaimJob("worker-1"); err != nil || claimed != nil {
+		t.Fatalf("expected no claimable job during backoff, got %v (err %v)", claimed, err)
+	}
+	if _, err := db.Exec(`UPDATE review_jobs SET retry_after = ? WHERE id = ?`,
+		time.Now().Add(-time.Second).UTC().Format(time.RFC3339), job.ID); err != nil {
+		t.Fatal(err)
+	}
+	claimJob(t, db, "worker-1")
+
+	if _, err := db.RetryJobWithBackoff(job.ID, "worker-1", 3, "agent: context deadline exceeded", 0); err != nil {
+		t.Fatalf("second RetryJobWithBackoff failed: %v", err)
+	}
+	updated, err := db.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	want := []string{"agent: exit status 1", "agent: context deadline exceeded"}
+	if !slices.Equal(updated.RetryErrors, want) {
+		t.Errorf("RetryErrors = %q, want %q", updated.RetryErrors, want)
+	}
+	if updated.RetryCount != 2 {
+		t.Errorf("RetryCount = %d, want 2", updated.RetryCount)
+	}
+
+	jobs, err := db.ListJobs("", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListJobs failed: %v", err)
+	}
+	if len(jobs) != 1 || !slices.Equal(jobs[0].RetryErrors, want) {
+		t.Errorf("ListJobs RetryErrors = %+v, want %q", jobs, want)
+	}
+
+	// A manual requeue starts over with a clean history
+	claimJob(t, db, "worker-1")
+	db.FailJob(job.ID, "", "final error")
+	requeued, err := db.RequeueJob(job.ID)
+	if err != nil {
+		t.Fatalf("RequeueJob failed: %v", err)
+	}
+	if len(requeued.RetryErrors) != 0 {
+		t.Errorf("expected retry history cleared, got %q", requeued.RetryErrors)
+	}
+}
+
 func TestRetryJobOnlyWorksForRunning(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -888,6 +1104,90 @@ func TestRetryJobAtomic(t *testing.T) {
 	}
 }
 
+func TestRequeueAllFailed(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repoA := createRepo(t, db, "/tmp/requeue-a")
+	repoB := createRepo(t, db, "/tmp/requeue-b")
+
+	// failJob enqueues, claims, and fails a job, then overrides its retry
+	// count and failure time.
+	failJob := func(repo *Repo, sha string, retries int, finishedAt time.Time) *ReviewJob {
+		t.Helper()
+		job := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, sha).ID, sha)
+		claimJob(t, db, "worker-1")
+		if _, err := db.FailJob(job.ID, "", "agent error"); err != nil {
+			t.Fatalf("FailJob: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE review_jobs SET retry_count = ?, finished_at = ? WHERE id = ?`,
+			retries, finishedAt.Format(time.RFC3339), job.ID); err != nil {
+			t.Fatalf("update job: %v", err)
+		}
+		return job
+	}
+
+	now := time.Now()
+	recentA := failJob(repoA, "rq-recent-a", 0, now)
+	oldA := failJob(repoA, "rq-old-a", 1, now.Add(-48*time.Hour))
+	permanentA := failJob(repoA, "rq-permanent-a", 3, now)
+	recentB := failJob(repoB, "rq-recent-b", 2, now)
+
+	done := enqueueJob(t, db, repoA.ID, createCommit(t, db, repoA.ID, "rq-done").ID, "rq-done")
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(done.ID, "codex", "p", "No issues found."); err != nil {
+		t.Fatalf("CompleteJob: %v", err)
+	}
+
+	// Restrict to one repo and a time window first
+	n, err := db.RequeueAllFailed(RequeueFailedOptions{
+		RepoPath:   repoA.RootPath,
+		Since:      now.Add(-time.Hour),
+		MaxRetries: 3,
+	})
+	if err != nil {
+		t.Fatalf("RequeueAllFailed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 job requeued for repo A since 1h ago, got %d", n)
+	}
+
+	// Then everything else that is eligible
+	n, err = db.RequeueAllFailed(RequeueFailedOptions{MaxRetries: 3})
+	if err != nil {
+		t.Fatalf("RequeueAllFailed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 more jobs requeued, got %d", n)
+	}
+
+	for _, tc := range []struct {
+		job     *ReviewJob
+		status  JobStatus
+		retries int
+	}{
+		{recentA, JobStatusQueued, 1},
+		{oldA, JobStatusQueued, 2},
+		{permanentA, JobStatusFailed, 3},
+		{recentB, JobStatusQueued, 3},
+		{done, JobStatusDone, 0},
+	} {
+		got, err := db.GetJobByID(tc.job.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID(%d): %v", tc.job.ID, err)
+		}
+		if got.Status != tc.status {
+			t.Errorf("job %s: status = %s, want %s", got.GitRef, got.Status, tc.status)
+		}
+		if count, _ := db.GetJobRetryCount(tc.job.ID); count != tc.retries {
+			t.Errorf("job %s: retry_count = %d, want %d", got.GitRef, count, tc.retries)
+		}
+		if tc.status == JobStatusQueued && (got.Error != "" || got.FinishedAt != nil) {
+			t.Errorf("job %s: expected error and finished_at cleared", got.GitRef)
+		}
+	}
+}
+
 func TestFailoverJob(t *testing.T) {
 	t.Run("succeeds with backup agent", func(t *testing.T) {
 		db := openTestDB(t)
@@ -1223,6 +1523,76 @@ func TestCancelJob(t *testing.T) {
 	})
 }
 
+func TestCancelJobsWhere(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repoA := createRepo(t, db, "/tmp/bulk-cancel-a")
+	repoB := createRepo(t, db, "/tmp/bulk-cancel-b")
+	enqueue := func(repo *Repo, sha string) *ReviewJob {
+		return enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, sha).ID, sha)
+	}
+
+	running := enqueue(repoA, "a-running")
+	if claimed, err := db.ClaimJob("worker-1"); err != nil || claimed.ID != running.ID {
+		t.Fatalf("ClaimJob: claimed=%v err=%v", claimed, err)
+	}
+	queuedA1 := enqueue(repoA, "a-queued-1")
+	queuedA2 := enqueue(repoA, "a-queued-2")
+	queuedB := enqueue(repoB, "b-queued")
+
+	status := func(job *ReviewJob) JobStatus {
+		t.Helper()
+		got, err := db.GetJobByID(job.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID(%d): %v", job.ID, err)
+		}
+		return got.Status
+	}
+
+	canceled, err := db.CancelJobsWhere(repoA.ID, JobStatusQueued)
+	if err != nil {
+		t.Fatalf("CancelJobsWhere: %v", err)
+	}
+	if len(canceled) != 2 {
+		t.Errorf("canceled %d jobs, want 2", len(canceled))
+	}
+	for _, c := range canceled {
+		if c.Claimed || (c.ID != queuedA1.ID && c.ID != queuedA2.ID) {
+			t.Errorf("canceled %+v, want an unclaimed queued job in repo A", c)
+		}
+	}
+	for _, job := range []*ReviewJob{queuedA1, queuedA2} {
+		if got := status(job); got != JobStatusCanceled {
+			t.Errorf("job %d status = %s, want canceled", job.ID, got)
+		}
+	}
+	if got := status(running); got != JobStatusRunning {
+		t.Errorf("running job status = %s, want running", got)
+	}
+	if got := status(queuedB); got != JobStatusQueued {
+		t.Errorf("other repo's job status = %s, want queued", got)
+	}
+
+	// Empty status cancels queued and running jobs in every repo
+	canceled, err = db.CancelJobsWhere(0, "")
+	if err != nil {
+		t.Fatalf("CancelJobsWhere: %v", err)
+	}
+	if len(canceled) != 2 || status(running) != JobStatusCanceled || status(queuedB) != JobStatusCanceled {
+		t.Errorf("canceled %d jobs, want the running job and the other repo's queued job", len(canceled))
+	}
+	for _, c := range canceled {
+		if c.Claimed != (c.ID == running.ID) {
+			t.Errorf("canceled %+v: Claimed should be set only for the running job", c)
+		}
+	}
+
+	if _, err := db.CancelJobsWhere(repoA.ID, JobStatusDone); err == nil {
+		t.Error("expected error for a non-cancellable status")
+	}
+}
+
 func TestMarkJobApplied(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -1235,7 +1605,7 @@ func TestMarkJobApplied(t *testing.T) {
 		db.ClaimJob("worker-1")
 		db.CompleteJob(job.ID, "codex", "prompt", "output")
 
-		err := db.MarkJobApplied(job.ID)
+		err := db.MarkJobApplied(job.ID, "")
 		if err != nil {
 			t.Fatalf("MarkJobApplied failed: %v", err)
 		}
@@ -1244,12 +1614,30 @@ func TestMarkJobApplied(t *testing.T) {
 		if updated.Status != JobStatusApplied {
 			t.Errorf("Expected status 'applied', got '%s'", updated.Status)
 		}
+		if updated.AppliedCommitSHA != "" {
+			t.Errorf("Expected no applied commit, got %q", updated.AppliedCommitSHA)
+		}
+	})
+
+	t.Run("records applied commit", func(t *testing.T) {
+		job, _ := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "applied-test-sha", Agent: "codex", JobType: JobTypeFix, ParentJobID: 1})
+		db.ClaimJob("worker-1")
+		db.CompleteJob(job.ID, "codex", "prompt", "output")
+
+		if err := db.MarkJobApplied(job.ID, "deadbeef"); err != nil {
+			t.Fatalf("MarkJobApplied failed: %v", err)
+		}
+
+		updated, _ := db.GetJobByID(job.ID)
+		if updated.AppliedCommitSHA != "deadbeef" {
+			t.Errorf("Expected applied commit 'deadbeef', got %q", updated.AppliedCommitSHA)
+		}
 	})
 
 	t.Run("mark non-done job fails", func(t *testing.T) {
 		job, _ := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "applied-test-q", Agent: "codex", JobType: JobTypeFix, ParentJobID: 1})
 
-		err := db.MarkJobApplied(job.ID)
+		err := db.MarkJobApplied(job.ID, "")
 		if err == nil {
 			t.Error("MarkJobApplied should fail for queued jobs")
 		}
@@ -1259,9 +1647,9 @@ func TestMarkJobApplied(t *testing.T) {
 		job, _ := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "applied-test-2", Agent: "codex", JobType: JobTypeFix, ParentJobID: 1})
 		db.ClaimJob("worker-1")
 		db.CompleteJob(job.ID, "codex", "prompt", "output")
-		db.MarkJobApplied(job.ID)
+		db.MarkJobApplied(job.ID, "")
 
-		err := db.MarkJobApplied(job.ID)
+		err := db.MarkJobApplied(job.ID, "")
 		if err == nil {
 			t.Error("MarkJobApplied should fail for already-applied jobs")
 		}
@@ -1272,13 +1660,66 @@ func TestMarkJobApplied(t *testing.T) {
 		db.ClaimJob("worker-1")
 		db.CompleteJob(job.ID, "codex", "prompt", "output")
 
-		err := db.MarkJobApplied(job.ID)
+		err := db.MarkJobApplied(job.ID, "")
 		if err == nil {
 			t.Error("MarkJobApplied should fail for non-fix jobs")
 		}
 	})
 }
 
+func TestGetAppliedFixes(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo, _ := db.GetOrCreateRepo("/tmp/test-repo")
+	other, _ := db.GetOrCreateRepo("/tmp/other-repo")
+	commit, _ := db.GetOrCreateCommit(repo.ID, "fixes-test", "A", "S", time.Now())
+	otherCommit, _ := db.GetOrCreateCommit(other.ID, "fixes-other", "A", "S", time.Now())
+
+	finishFix := func(repoID, commitID int64, ref string) int64 {
+		t.Helper()
+		job, err := db.EnqueueJob(EnqueueOpts{RepoID: repoID, CommitID: commitID, GitRef: ref, Agent: "codex", JobType: JobTypeFix, ParentJobID: 1})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		db.ClaimJob("worker-1")
+		db.CompleteJob(job.ID, "codex", "prompt", "output")
+		return job.ID
+	}
+
+	applied := finishFix(repo.ID, commit.ID, "fix-applied")
+	rebased := finishFix(repo.ID, commit.ID, "fix-rebased")
+	finishFix(repo.ID, commit.ID, "fix-ready")
+	otherApplied := finishFix(other.ID, otherCommit.ID, "fix-other")
+
+	if err := db.MarkJobApplied(applied, "abc123"); err != nil {
+		t.Fatalf("MarkJobApplied failed: %v", err)
+	}
+	if err := db.MarkJobRebased(rebased); err != nil {
+		t.Fatalf("MarkJobRebased failed: %v", err)
+	}
+	if err := db.MarkJobApplied(otherApplied, "def456"); err != nil {
+		t.Fatalf("MarkJobApplied failed: %v", err)
+	}
+
+	fixes, err := db.GetAppliedFixes(repo.ID)
+	if err != nil {
+		t.Fatalf("GetAppliedFixes failed: %v", err)
+	}
+	var ids []int64
+	for _, f := range fixes {
+		ids = append(ids, f.ID)
+	}
+	if len(ids) != 2 || !slices.Contains(ids, applied) || !slices.Contains(ids, rebased) {
+		t.Fatalf("Expected jobs %d and %d, got %v", applied, rebased, ids)
+	}
+	for _, f := range fixes {
+		if f.ID == applied && f.AppliedCommitSHA != "abc123" {
+			t.Errorf("Expected applied commit 'abc123', got %q", f.AppliedCommitSHA)
+		}
+	}
+}
+
 func TestMarkJobRebased(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -2831,67 +3272,423 @@ func TestReenqueueJob(t *testing.T) {
 	})
 }
 
-func TestListJobsAndGetJobByIDReturnAgentic(t *testing.T) {
-	// Test that agentic field is properly returned by ListJobs and GetJobByID
+func TestEnqueueRetry(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
 
-	repoPath := filepath.Join(t.TempDir(), "agentic-test-repo")
-	repo, err := db.GetOrCreateRepo(repoPath)
-	if err != nil {
-		t.Fatalf("GetOrCreateRepo failed: %v", err)
-	}
-
-	// Enqueue a prompt job with agentic=true
-	job, err := db.EnqueueJob(EnqueueOpts{
-		RepoID:  repo.ID,
-		Agent:   "test-agent",
-		Prompt:  "Review this code",
-		Agentic: true,
-	})
-	if err != nil {
-		t.Fatalf("EnqueuePromptJob failed: %v", err)
-	}
-
-	// Verify the returned job has Agentic set
-	if !job.Agentic {
-		t.Error("EnqueuePromptJob should return job with Agentic=true")
-	}
-
-	t.Run("ListJobs returns agentic field", func(t *testing.T) {
-		jobs, err := db.ListJobs("", "", 50, 0)
+	t.Run("retry failed job", func(t *testing.T) {
+		repo, commit, _ := createJobChain(t, db, "/tmp/test-repo", "retry-failed")
+		job, err := db.EnqueueJob(EnqueueOpts{
+			RepoID: repo.ID, CommitID: commit.ID, GitRef: "retry-failed",
+			Agent: "codex", Model: "o3", Reasoning: "fast", Branch: "main",
+		})
 		if err != nil {
-			t.Fatalf("ListJobs failed: %v", err)
-		}
-		if len(jobs) == 0 {
-			t.Fatal("Expected at least one job")
+			t.Fatalf("EnqueueJob failed: %v", err)
 		}
-
-		// Find our job
-		var found bool
-		for _, j := range jobs {
-			if j.ID == job.ID {
-				found = true
-				if !j.Agentic {
-					t.Errorf("ListJobs should return Agentic=true for job %d", j.ID)
-				}
+		// Drain the chain's job so the claim below gets ours.
+		for {
+			claimed := claimJob(t, db, "worker-1")
+			if claimed.ID == job.ID {
 				break
 			}
+			db.CompleteJob(claimed.ID, "codex", "prompt", "output")
 		}
-		if !found {
-			t.Errorf("Job %d not found in ListJobs result", job.ID)
+		db.FailJob(job.ID, "", "agent crashed")
+
+		retry, err := db.EnqueueRetry(job.ID)
+		if err != nil {
+			t.Fatalf("EnqueueRetry failed: %v", err)
+		}
+		if retry.ID == job.ID || retry.Status != JobStatusQueued {
+			t.Fatalf("expected a new queued job, got %+v", retry)
 		}
-	})
 
-	t.Run("GetJobByID returns agentic field", func(t *testing.T) {
-		fetchedJob, err := db.GetJobByID(job.ID)
+		got, err := db.GetJobByID(retry.ID)
 		if err != nil {
 			t.Fatalf("GetJobByID failed: %v", err)
 		}
-		if !fetchedJob.Agentic {
-			t.Errorf("GetJobByID should return Agentic=true for job %d", job.ID)
+		if got.RetryOfJobID == nil || *got.RetryOfJobID != job.ID {
+			t.Errorf("RetryOfJobID = %v, want %d", got.RetryOfJobID, job.ID)
 		}
-	})
+		if got.CommitID == nil || *got.CommitID != commit.ID || got.GitRef != "retry-failed" ||
+			got.Agent != "codex" || got.Model != "o3" || got.Reasoning != "fast" || got.Branch != "main" {
+			t.Errorf("retry did not copy the original job: %+v", got)
+		}
+
+		original, _ := db.GetJobByID(job.ID)
+		if original.Status != JobStatusFailed {
+			t.Errorf("original status = %s, want failed", original.Status)
+		}
+	})
+
+	t.Run("retry queued job fails", func(t *testing.T) {
+		_, _, job := createJobChain(t, db, "/tmp/test-repo", "retry-queued")
+
+		if _, err := db.EnqueueRetry(job.ID); !errors.Is(err, ErrJobNotRetryable) {
+			t.Errorf("expected ErrJobNotRetryable, got %v", err)
+		}
+	})
+
+	t.Run("retry missing job fails", func(t *testing.T) {
+		if _, err := db.EnqueueRetry(99999); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+}
+
+func TestRerunReview(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo, commit, job := createJobChain(t, db, "/tmp/test-repo", "rerun-review")
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(job.ID, "test", "prompt", "- High: unchecked error"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	rerun, err := db.RerunReview(job.ID)
+	if err != nil {
+		t.Fatalf("RerunReview failed: %v", err)
+	}
+	if rerun.ID == job.ID || rerun.Status != JobStatusQueued {
+		t.Fatalf("expected a new queued job, got %+v", rerun)
+	}
+	got, err := db.GetJobByID(rerun.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	if got.SupersedesJobID == nil || *got.SupersedesJobID != job.ID {
+		t.Errorf("SupersedesJobID = %v, want %d", got.SupersedesJobID, job.ID)
+	}
+	if got.RetryOfJobID != nil {
+		t.Errorf("RetryOfJobID = %d, want unset for a rerun", *got.RetryOfJobID)
+	}
+	if got.CommitID == nil || *got.CommitID != commit.ID || got.GitRef != "rerun-review" {
+		t.Errorf("rerun did not review the same commit: %+v", got)
+	}
+
+	jobs, err := db.ListJobs("", "", 10, 0)
+	if err != nil {
+		t.Fatalf("ListJobs failed: %v", err)
+	}
+	for _, j := range jobs {
+		if j.ID == rerun.ID && (j.SupersedesJobID == nil || *j.SupersedesJobID != job.ID) {
+			t.Errorf("ListJobs SupersedesJobID = %v, want %d", j.SupersedesJobID, job.ID)
+		}
+	}
+	if review, err := db.GetReviewByJobID(job.ID); err != nil || review.Output != "- High: unchecked error" {
+		t.Errorf("original review should be kept, got %v, %v", review, err)
+	}
+
+	if _, err := db.RerunReview(rerun.ID); !errors.Is(err, ErrJobNotRetryable) {
+		t.Errorf("expected ErrJobNotRetryable for a queued job, got %v", err)
+	}
+
+	task, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, GitRef: "prompt", Agent: "test", Prompt: "Explain main.go"})
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	if err := db.CancelJob(task.ID); err != nil {
+		t.Fatalf("CancelJob failed: %v", err)
+	}
+	if _, err := db.RerunReview(task.ID); !errors.Is(err, ErrNotReviewJob) {
+		t.Errorf("expected ErrNotReviewJob for a task job, got %v", err)
+	}
+}
+
+func TestCompleteJobFromCache(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/test-repo")
+	diffHash := HashDiff("diff --git a/main.go b/main.go", "")
+	enqueue := func(sha, agent, hash string) *ReviewJob {
+		t.Helper()
+		commit := createCommit(t, db, repo.ID, sha)
+		job, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: agent, DiffHash: hash})
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		return job
+	}
+
+	original := enqueue("before-amend", "codex", diffHash)
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(original.ID, "codex", "prompt", "- High: unchecked error"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	amended := enqueue("after-amend", "codex", diffHash)
+	claimed := claimJob(t, db, "worker-1")
+	if claimed.ID != amended.ID || claimed.DiffHash != diffHash {
+		t.Fatalf("claimed job %d with diff hash %q, want job %d with %q", claimed.ID, claimed.DiffHash, amended.ID, diffHash)
+	}
+	if got, err := db.FindCachedReview(claimed); err != nil || got != original.ID {
+		t.Fatalf("FindCachedReview = %d, %v; want %d", got, err, original.ID)
+	}
+	enqueueOpts := func(sha string, opts EnqueueOpts) *ReviewJob {
+		t.Helper()
+		commit := createCommit(t, db, repo.ID, sha)
+		opts.RepoID, opts.CommitID, opts.GitRef, opts.Agent, opts.DiffHash = repo.ID, commit.ID, sha, "codex", diffHash
+		job, err := db.EnqueueJob(opts)
+		if err != nil {
+			t.Fatalf("EnqueueJob failed: %v", err)
+		}
+		return job
+	}
+	for _, other := range []*ReviewJob{
+		enqueue("other-agent", "claude-code", diffHash),
+		enqueue("other-diff", "codex", HashDiff("diff --git a/util.go b/util.go", "")),
+		enqueue("other-settings", "codex", HashDiff("diff --git a/main.go b/main.go", "review_guidelines")),
+		enqueue("no-hash", "codex", ""),
+		enqueueOpts("other-reasoning", EnqueueOpts{Reasoning: "fast"}),
+		enqueueOpts("agentic", EnqueueOpts{Agentic: true}),
+	} {
+		if got, err := db.FindCachedReview(other); err != nil || got != 0 {
+			t.Errorf("FindCachedReview(%s) = %d, %v; want no cached review", other.GitRef, got, err)
+		}
+	}
+
+	ok, err := db.CompleteJobFromCache(claimed.ID, original.ID)
+	if err != nil || !ok {
+		t.Fatalf("CompleteJobFromCache = %v, %v", ok, err)
+	}
+	job, err := db.GetJobByID(claimed.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	if job.Status != JobStatusDone || job.CachedFromJobID == nil || *job.CachedFromJobID != original.ID {
+		t.Errorf("job status %s, CachedFromJobID %v; want done from job %d", job.Status, job.CachedFromJobID, original.ID)
+	}
+	review, err := db.GetReviewByJobID(claimed.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID failed: %v", err)
+	}
+	if review.Output != "- High: unchecked error" || review.Agent != "codex" || review.Job.Verdict == nil || *review.Job.Verdict != "F" {
+		t.Errorf("unexpected copied review: %+v", review)
+	}
+	if review.Job.CachedFromJobID == nil || *review.Job.CachedFromJobID != original.ID {
+		t.Errorf("review job CachedFromJobID = %v, want %d", review.Job.CachedFromJobID, original.ID)
+	}
+
+	if ok, err := db.CompleteJobFromCache(claimed.ID, original.ID); err != nil || ok {
+		t.Errorf("completing a finished job from cache = %v, %v; want false", ok, err)
+	}
+}
+
+func TestRequeueJob(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	t.Run("requeue failed job", func(t *testing.T) {
+		_, _, job := createJobChain(t, db, "/tmp/test-repo", "requeue-failed")
+		claimJob(t, db, "worker-1")
+		db.FailJob(job.ID, "", "network blip")
+
+		requeued, err := db.RequeueJob(job.ID)
+		if err != nil {
+			t.Fatalf("RequeueJob failed: %v", err)
+		}
+		if requeued.ID != job.ID || requeued.Status != JobStatusQueued {
+			t.Fatalf("expected job %d queued in place, got %+v", job.ID, requeued)
+		}
+		if requeued.StartedAt != nil || requeued.FinishedAt != nil || requeued.Error != "" || requeued.WorkerID != "" {
+			t.Errorf("expected run state cleared, got %+v", requeued)
+		}
+		if requeued.Attempts != 1 {
+			t.Errorf("Attempts = %d, want 1", requeued.Attempts)
+		}
+
+		claimJob(t, db, "worker-1")
+		db.FailJob(job.ID, "", "network blip again")
+		requeued, err = db.RequeueJob(job.ID)
+		if err != nil {
+			t.Fatalf("second RequeueJob failed: %v", err)
+		}
+		if requeued.Attempts != 2 {
+			t.Errorf("Attempts after second requeue = %d, want 2", requeued.Attempts)
+		}
+	})
+
+	t.Run("requeue canceled job", func(t *testing.T) {
+		_, _, job := createJobChain(t, db, "/tmp/test-repo", "requeue-canceled")
+		if err := db.CancelJob(job.ID); err != nil {
+			t.Fatalf("CancelJob failed: %v", err)
+		}
+		requeued, err := db.RequeueJob(job.ID)
+		if err != nil {
+			t.Fatalf("RequeueJob failed: %v", err)
+		}
+		if requeued.Status != JobStatusQueued {
+			t.Errorf("status = %s, want queued", requeued.Status)
+		}
+	})
+
+	t.Run("requeue done job fails", func(t *testing.T) {
+		_, _, job := createJobChain(t, db, "/tmp/test-repo", "requeue-done")
+		for {
+			claimed := claimJob(t, db, "worker-1")
+			db.CompleteJob(claimed.ID, "codex", "prompt", "output")
+			if claimed.ID == job.ID {
+				break
+			}
+		}
+
+		if _, err := db.RequeueJob(job.ID); !errors.Is(err, ErrJobNotRequeueable) {
+			t.Errorf("expected ErrJobNotRequeueable, got %v", err)
+		}
+		if _, err := db.GetReviewByJobID(job.ID); err != nil {
+			t.Errorf("expected the done job's review to be kept: %v", err)
+		}
+	})
+
+	t.Run("requeue missing job fails", func(t *testing.T) {
+		if _, err := db.RequeueJob(99999); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+}
+
+func TestMarkReviewSeen(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, _, seenJob := createJobChain(t, db, "/tmp/test-repo", "seen-sha")
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(seenJob.ID, "codex", "prompt", "output"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+	_, _, unseenJob := createJobChain(t, db, "/tmp/test-repo", "unseen-sha")
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(unseenJob.ID, "codex", "prompt", "output"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+	_, _, queuedJob := createJobChain(t, db, "/tmp/test-repo", "queued-sha")
+
+	if err := db.MarkReviewSeen(seenJob.ID, "alice"); err != nil {
+		t.Fatalf("MarkReviewSeen failed: %v", err)
+	}
+	review, err := db.GetReviewByJobID(seenJob.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID failed: %v", err)
+	}
+	if review.SeenBy != "alice" || review.SeenAt == nil {
+		t.Fatalf("expected review seen by alice, got SeenBy=%q SeenAt=%v", review.SeenBy, review.SeenAt)
+	}
+	firstSeenAt := *review.SeenAt
+
+	t.Run("first reader is kept", func(t *testing.T) {
+		if err := db.MarkReviewSeen(seenJob.ID, "bob"); err != nil {
+			t.Fatalf("MarkReviewSeen failed: %v", err)
+		}
+		review, err := db.GetReviewByJobID(seenJob.ID)
+		if err != nil {
+			t.Fatalf("GetReviewByJobID failed: %v", err)
+		}
+		if review.SeenBy != "alice" || !review.SeenAt.Equal(firstSeenAt) {
+			t.Errorf("expected first reader alice kept, got %q at %v", review.SeenBy, review.SeenAt)
+		}
+	})
+
+	t.Run("job without review fails", func(t *testing.T) {
+		if err := db.MarkReviewSeen(queuedJob.ID, "alice"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("list unseen reviews", func(t *testing.T) {
+		jobs, err := db.ListJobs("", "", 0, 0, WithSeen(false))
+		if err != nil {
+			t.Fatalf("ListJobs failed: %v", err)
+		}
+		if len(jobs) != 1 || jobs[0].ID != unseenJob.ID {
+			t.Fatalf("expected only job %d unseen, got %+v", unseenJob.ID, jobs)
+		}
+		if jobs[0].Seen == nil || *jobs[0].Seen {
+			t.Errorf("expected Seen=false, got %v", jobs[0].Seen)
+		}
+
+		jobs, err = db.ListJobs("", "", 0, 0, WithSeen(true))
+		if err != nil {
+			t.Fatalf("ListJobs failed: %v", err)
+		}
+		if len(jobs) != 1 || jobs[0].ID != seenJob.ID || jobs[0].Seen == nil || !*jobs[0].Seen {
+			t.Fatalf("expected only job %d seen, got %+v", seenJob.ID, jobs)
+		}
+	})
+
+	t.Run("jobs without review have no seen state", func(t *testing.T) {
+		jobs, err := db.ListJobs("queued", "", 0, 0)
+		if err != nil {
+			t.Fatalf("ListJobs failed: %v", err)
+		}
+		if len(jobs) != 1 || jobs[0].Seen != nil {
+			t.Errorf("expected queued job with nil Seen, got %+v", jobs)
+		}
+	})
+}
+
+func TestListJobsAndGetJobByIDReturnAgentic(t *testing.T) {
+	// Test that agentic field is properly returned by ListJobs and GetJobByID
+	db := openTestDB(t)
+	defer db.Close()
+
+	repoPath := filepath.Join(t.TempDir(), "agentic-test-repo")
+	repo, err := db.GetOrCreateRepo(repoPath)
+	if err != nil {
+		t.Fatalf("GetOrCreateRepo failed: %v", err)
+	}
+
+	// Enqueue a prompt job with agentic=true
+	job, err := db.EnqueueJob(EnqueueOpts{
+		RepoID:  repo.ID,
+		Agent:   "test-agent",
+		Prompt:  "Review this code",
+		Agentic: true,
+	})
+	if err != nil {
+		t.Fatalf("EnqueuePromptJob failed: %v", err)
+	}
+
+	// Verify the returned job has Agentic set
+	if !job.Agentic {
+		t.Error("EnqueuePromptJob should return job with Agentic=true")
+	}
+
+	t.Run("ListJobs returns agentic field", func(t *testing.T) {
+		jobs, err := db.ListJobs("", "", 50, 0)
+		if err != nil {
+			t.Fatalf("ListJobs failed: %v", err)
+		}
+		if len(jobs) == 0 {
+			t.Fatal("Expected at least one job")
+		}
+
+		// Find our job
+		var found bool
+		for _, j := range jobs {
+			if j.ID == job.ID {
+				found = true
+				if !j.Agentic {
+					t.Errorf("ListJobs should return Agentic=true for job %d", j.ID)
+				}
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Job %d not found in ListJobs result", job.ID)
+		}
+	})
+
+	t.Run("GetJobByID returns agentic field", func(t *testing.T) {
+		fetchedJob, err := db.GetJobByID(job.ID)
+		if err != nil {
+			t.Fatalf("GetJobByID failed: %v", err)
+		}
+		if !fetchedJob.Agentic {
+			t.Errorf("GetJobByID should return Agentic=true for job %d", job.ID)
+		}
+	})
 
 	// Also test with agentic=false to ensure we're not just always returning true
 	t.Run("non-agentic job returns Agentic=false", func(t *testing.T) {
@@ -3342,30 +4139,106 @@ func TestEnqueueJobWithPatchID(t *testing.T) {
 	}
 }
 
-func TestRemapJobGitRef(t *testing.T) {
+func TestEnqueueJobWithRangeLabel(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
 
-	repo := createRepo(t, db, "/tmp/test-remap")
-	commit := createCommit(t, db, repo.ID, "oldsha")
+	repo := createRepo(t, db, "/tmp/test-range-label")
+	commit := createCommit(t, db, repo.ID, "abc123")
 
-	t.Run("remap updates matching jobs", func(t *testing.T) {
-		job, err := db.EnqueueJob(EnqueueOpts{
-			RepoID:   repo.ID,
-			CommitID: commit.ID,
-			GitRef:   "oldsha",
-			Agent:    "test",
-			PatchID:  "patchabc",
-		})
-		if err != nil {
-			t.Fatalf("EnqueueJob: %v", err)
-		}
+	job, err := db.EnqueueJob(EnqueueOpts{
+		RepoID:     repo.ID,
+		CommitID:   commit.ID,
+		GitRef:     "abc123",
+		Agent:      "test",
+		RangeLabel: "main..HEAD",
+	})
+	if err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	if job.RangeLabel != "main..HEAD" {
+		t.Errorf("expected RangeLabel=main..HEAD, got %q", job.RangeLabel)
+	}
 
-		newCommit := createCommit(t, db, repo.ID, "newsha")
-		n, err := db.RemapJobGitRef(repo.ID, "oldsha", "newsha", "patchabc", newCommit.ID)
-		if err != nil {
-			t.Fatalf("RemapJobGitRef: %v", err)
-		}
+	got, err := db.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	if got.RangeLabel != "main..HEAD" {
+		t.Errorf("GetJobByID: expected RangeLabel=main..HEAD, got %q", got.RangeLabel)
+	}
+
+	jobs, err := db.ListJobs("", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].RangeLabel != "main..HEAD" {
+		t.Errorf("ListJobs: expected RangeLabel=main..HEAD, got %+v", jobs)
+	}
+}
+
+func TestEnqueueRangeJobWithBase(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/test-range-base")
+
+	job, err := db.EnqueueJob(EnqueueOpts{
+		RepoID:       repo.ID,
+		GitRef:       "def456",
+		RangeBase:    "abc123",
+		Agent:        "test",
+		RangeLabel:   "main..HEAD",
+		RangeCommits: 5,
+	})
+	if err != nil {
+		t.Fatalf("EnqueueJob: %v", err)
+	}
+	if job.GitRef != "abc123..def456" || job.JobType != JobTypeRange {
+		t.Errorf("expected range job abc123..def456, got %q (%s)", job.GitRef, job.JobType)
+	}
+
+	got, err := db.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID: %v", err)
+	}
+	if got.GitRef != "abc123..def456" || got.RangeLabel != "main..HEAD" || got.RangeCommits != 5 {
+		t.Errorf("GetJobByID: got git_ref=%q range_label=%q range_commits=%d", got.GitRef, got.RangeLabel, got.RangeCommits)
+	}
+
+	jobs, err := db.ListJobs("", "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].RangeCommits != 5 {
+		t.Errorf("ListJobs: expected RangeCommits=5, got %+v", jobs)
+	}
+}
+
+func TestRemapJobGitRef(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/test-remap")
+	commit := createCommit(t, db, repo.ID, "oldsha")
+
+	t.Run("remap updates matching jobs", func(t *testing.T) {
+		job, err := db.EnqueueJob(EnqueueOpts{
+			RepoID:   repo.ID,
+			CommitID: commit.ID,
+			GitRef:   "oldsha",
+			Agent:    "test",
+			PatchID:  "patchabc",
+		})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+
+		newCommit := createCommit(t, db, repo.ID, "newsha")
+		n, err := db.RemapJobGitRef(repo.ID, "oldsha", "newsha", "patchabc", newCommit.ID)
+		if err != nil {
+			t.Fatalf("RemapJobGitRef: %v", err)
+		}
 		if n != 1 {
 			t.Errorf("expected 1 row updated, got %d", n)
 		}
@@ -3780,3 +4653,547 @@ func TestListJobsWithJobTypeFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestVacuum(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	job := createCompletedJob(t, db, repo.ID, "vacuum-sha", "No issues found.")
+	createCompletedJob(t, db, repo.ID, "vacuum-sha2", "- High: unchecked error")
+	createCompletedJob(t, db, repo.ID, "vacuum-sha3", "- Low: typo in comment")
+
+	// Each review insert writes its own search index segment
+	segments := func() int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(DISTINCT segid) FROM reviews_fts_idx`).Scan(&n); err != nil {
+			t.Fatalf("count reviews_fts segments: %v", err)
+		}
+		return n
+	}
+	if n := segments(); n < 2 {
+		t.Fatalf("expected several search index segments before vacuum, got %d", n)
+	}
+
+	result, err := db.Vacuum()
+	if err != nil {
+		t.Fatalf("Vacuum failed: %v", err)
+	}
+	if result.SizeBefore <= 0 || result.SizeAfter <= 0 {
+		t.Errorf("expected positive sizes, got %+v", result)
+	}
+	if n := segments(); n != 1 {
+		t.Errorf("expected vacuum to merge the search index into 1 segment, got %d", n)
+	}
+
+	review, err := db.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID after vacuum: %v", err)
+	}
+	if review.Output != "No issues found." {
+		t.Errorf("unexpected output after vacuum: %q", review.Output)
+	}
+}
+
+func TestPruneJobs(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	old := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	// age backdates a job's finished_at past the prune cutoff.
+	age := func(jobID int64) {
+		t.Helper()
+		if _, err := db.Exec(`UPDATE review_jobs SET finished_at = ? WHERE id = ?`, old, jobID); err != nil {
+			t.Fatalf("backdate job %d: %v", jobID, err)
+		}
+	}
+
+	oldDone := createCompletedJob(t, db, repo.ID, "old-done", "No issues found.")
+	age(oldDone.ID)
+	if _, err := db.AddCommentToJob(oldDone.ID, "dev", "looked at it"); err != nil {
+		t.Fatalf("AddCommentToJob: %v", err)
+	}
+	oldApplied := createCompletedJob(t, db, repo.ID, "old-applied", "No issues found.")
+	age(oldApplied.ID)
+	if _, err := db.Exec(`UPDATE review_jobs SET status = 'applied', finished_at = ? WHERE id = ?`, old, oldApplied.ID); err != nil {
+		t.Fatalf("mark applied: %v", err)
+	}
+	oldFailed := enqueueJob(t, db, repo.ID, 0, "old-failed")
+	setJobStatus(t, db, oldFailed.ID, JobStatusFailed)
+	age(oldFailed.ID)
+	recent := createCompletedJob(t, db, repo.ID, "recent", "No issues found.")
+
+	// Queued and running jobs are never pruned, even with a stale finished_at.
+	queued := enqueueJob(t, db, repo.ID, 0, "queued")
+	age(queued.ID)
+	running := enqueueJob(t, db, repo.ID, 0, "running")
+	setJobStatus(t, db, running.ID, JobStatusRunning)
+	age(running.ID)
+
+	result, err := db.PruneJobs(30*24*time.Hour, []JobStatus{JobStatusApplied})
+	if err != nil {
+		t.Fatalf("PruneJobs: %v", err)
+	}
+	if *result != (PruneResult{Jobs: 2, Reviews: 1, Comments: 1}) {
+		t.Errorf("PruneJobs() = %+v, want 2 jobs, 1 review, 1 comment", *result)
+	}
+
+	for _, id := range []int64{oldDone.ID, oldFailed.ID} {
+		if _, err := db.GetJobByID(id); err == nil {
+			t.Errorf("job %d should have been pruned", id)
+		}
+	}
+	for _, id := range []int64{oldApplied.ID, recent.ID, queued.ID, running.ID} {
+		if _, err := db.GetJobByID(id); err != nil {
+			t.Errorf("job %d should have been kept: %v", id, err)
+		}
+	}
+	if _, err := db.GetReviewByJobID(oldDone.ID); err == nil {
+		t.Error("review of pruned job should be deleted")
+	}
+
+	// Without keep statuses the applied job goes too.
+	result, err = db.PruneJobs(30*24*time.Hour, nil)
+	if err != nil {
+		t.Fatalf("PruneJobs: %v", err)
+	}
+	if result.Jobs != 1 || result.Reviews != 1 {
+		t.Errorf("second PruneJobs() = %+v, want 1 job and 1 review", *result)
+	}
+}
+
+func TestPruneReviews(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repoA := createRepo(t, db, t.TempDir())
+	repoB := createRepo(t, db, t.TempDir())
+
+	// finish completes a job and sets its finished_at to daysAgo days back.
+	finish := func(repoID int64, ref string, daysAgo int) *ReviewJob {
+		t.Helper()
+		job := createCompletedJob(t, db, repoID, ref, "No issues found.")
+		finishedAt := time.Now().Add(-time.Duration(daysAgo) * 24 * time.Hour).UTC().Format(time.RFC3339)
+		if _, err := db.Exec(`UPDATE review_jobs SET finished_at = ? WHERE id = ?`, finishedAt, job.ID); err != nil {
+			t.Fatalf("backdate job %d: %v", job.ID, err)
+		}
+		return job
+	}
+
+	a1 := finish(repoA.ID, "a1", 1)
+	a2 := finish(repoA.ID, "a2", 2)
+	a3 := finish(repoA.ID, "a3", 3)
+	b1 := finish(repoB.ID, "b1", 1)
+	b100 := finish(repoB.ID, "b100", 100)
+	queued := enqueueJob(t, db, repoA.ID, 0, "queued")
+
+	t.Run("dry run deletes nothing", func(t *testing.T) {
+		result, err := db.PruneReviews(PruneOpts{KeepLast: 1, DryRun: true})
+		if err != nil {
+			t.Fatalf("PruneReviews: %v", err)
+		}
+		if *result != (PruneResult{Jobs: 3, Reviews: 3}) {
+			t.Errorf("dry run = %+v, want 3 jobs and 3 reviews", *result)
+		}
+		for _, id := range []int64{a2.ID, a3.ID, b100.ID} {
+			if _, err := db.GetReviewByJobID(id); err != nil {
+				t.Errorf("dry run deleted review of job %d: %v", id, err)
+			}
+		}
+	})
+
+	t.Run("no limits prunes nothing", func(t *testing.T) {
+		result, err := db.PruneReviews(PruneOpts{})
+		if err != nil {
+			t.Fatalf("PruneReviews: %v", err)
+		}
+		if *result != (PruneResult{}) {
+			t.Errorf("PruneReviews() = %+v, want nothing pruned", *result)
+		}
+	})
+
+	t.Run("age or count", func(t *testing.T) {
+		result, err := db.PruneReviews(PruneOpts{OlderThan: 30 * 24 * time.Hour, KeepLast: 2})
+		if err != nil {
+			t.Fatalf("PruneReviews: %v", err)
+		}
+		if result.Jobs != 2 || result.Reviews != 2 {
+			t.Errorf("PruneReviews() = %+v, want 2 jobs and 2 reviews", *result)
+		}
+		for _, id := range []int64{a3.ID, b100.ID} {
+			if _, err := db.GetJobByID(id); err == nil {
+				t.Errorf("job %d should have been pruned", id)
+			}
+		}
+		for _, id := range []int64{a1.ID, a2.ID, b1.ID, queued.ID} {
+			if _, err := db.GetJobByID(id); err != nil {
+				t.Errorf("job %d should have been kept: %v", id, err)
+			}
+		}
+	})
+}
+
+func TestPruneKeepsReferencedJobs(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// One connection, so foreign key enforcement covers the prune
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+
+	repo := createRepo(t, db, t.TempDir())
+	old := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	finishOld := func(ref string) *ReviewJob {
+		t.Helper()
+		job := createCompletedJob(t, db, repo.ID, ref, "No issues found.")
+		if _, err := db.Exec(`UPDATE review_jobs SET finished_at = ? WHERE id = ?`, old, job.ID); err != nil {
+			t.Fatalf("backdate job %d: %v", job.ID, err)
+		}
+		return job
+	}
+
+	ciJob := finishOld("ci")
+	parent := finishOld("parent")
+	retried := finishOld("retried")
+
+	if err := db.RecordCIReview("acme/api", 7, "ci", ciJob.ID); err != nil {
+		t.Fatalf("RecordCIReview: %v", err)
+	}
+	fix, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, GitRef: "parent", Agent: "codex", JobType: JobTypeFix, ParentJobID: parent.ID, Prompt: "fix"})
+	if err != nil {
+		t.Fatalf("EnqueueJob fix: %v", err)
+	}
+	retry := enqueueJob(t, db, repo.ID, 0, "retry")
+	if _, err := db.Exec(`UPDATE review_jobs SET retry_of_job_id = ? WHERE id = ?`, retried.ID, retry.ID); err != nil {
+		t.Fatalf("link retry: %v", err)
+	}
+
+	result, err := db.PruneReviews(PruneOpts{OlderThan: 30 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("PruneReviews: %v", err)
+	}
+	if result.Jobs != 1 {
+		t.Errorf("PruneReviews() = %+v, want only the retried job pruned", *result)
+	}
+	if _, err := db.GetJobByID(retried.ID); err == nil {
+		t.Errorf("job %d should have been pruned", retried.ID)
+	}
+	for _, id := range []int64{ciJob.ID, parent.ID, fix.ID, retry.ID} {
+		if _, err := db.GetJobByID(id); err != nil {
+			t.Errorf("job %d should have been kept: %v", id, err)
+		}
+	}
+	var retryOf sql.NullInt64
+	if err := db.QueryRow(`SELECT retry_of_job_id FROM review_jobs WHERE id = ?`, retry.ID).Scan(&retryOf); err != nil {
+		t.Fatal(err)
+	}
+	if retryOf.Valid {
+		t.Errorf("retry link to pruned job should be cleared, got %d", retryOf.Int64)
+	}
+}
+
+func TestPruneCommitReviews(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	commit := createCommit(t, db, repo.ID, "abc123")
+	other := createCommit(t, db, repo.ID, "def456")
+
+	review := func(commitID int64, sha string) *ReviewJob {
+		t.Helper()
+		job := enqueueJob(t, db, repo.ID, commitID, sha)
+		claimJob(t, db, "worker-1")
+		if err := db.CompleteJob(job.ID, "codex", "prompt", "No issues found."); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+		return job
+	}
+
+	oldest := review(commit.ID, "abc123")
+	if _, err := db.AddCommentToJob(oldest.ID, "dev", "noted"); err != nil {
+		t.Fatalf("AddCommentToJob: %v", err)
+	}
+	addressed := review(commit.ID, "abc123")
+	if err := db.MarkReviewAddressedByJobID(addressed.ID, true); err != nil {
+		t.Fatalf("MarkReviewAddressedByJobID: %v", err)
+	}
+	fixed := review(commit.ID, "abc123")
+	middle := review(commit.ID, "abc123")
+	newer := review(commit.ID, "abc123")
+	newest := review(commit.ID, "abc123")
+	otherReview := review(other.ID, "def456")
+	if _, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, GitRef: "abc123", Agent: "codex", JobType: JobTypeFix, ParentJobID: fixed.ID, Prompt: "fix"}); err != nil {
+		t.Fatalf("EnqueueJob fix: %v", err)
+	}
+
+	result, err := db.PruneCommitReviews(commit.ID, 2)
+	if err != nil {
+		t.Fatalf("PruneCommitReviews: %v", err)
+	}
+	if *result != (PruneResult{Jobs: 2, Reviews: 2, Comments: 1}) {
+		t.Errorf("PruneCommitReviews() = %+v, want 2 jobs, 2 reviews, 1 comment", *result)
+	}
+
+	for _, id := range []int64{oldest.ID, middle.ID} {
+		if _, err := db.GetJobByID(id); err == nil {
+			t.Errorf("job %d should have been pruned", id)
+		}
+	}
+	// The newest two survive, as do protected reviews and other commits.
+	for _, id := range []int64{newer.ID, newest.ID, addressed.ID, fixed.ID, otherReview.ID} {
+		if _, err := db.GetReviewByJobID(id); err != nil {
+			t.Errorf("review of job %d should have been kept: %v", id, err)
+		}
+	}
+	comments, err := db.GetCommentsForJob(oldest.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob: %v", err)
+	}
+	if len(comments) != 0 {
+		t.Errorf("comments of pruned review should be deleted, got %d", len(comments))
+	}
+
+	// Nothing is left to prune, and keep <= 0 is a no-op.
+	for _, keep := range []int{2, 0} {
+		result, err = db.PruneCommitReviews(commit.ID, keep)
+		if err != nil {
+			t.Fatalf("PruneCommitReviews(%d): %v", keep, err)
+		}
+		if *result != (PruneResult{}) {
+			t.Errorf("PruneCommitReviews(%d) = %+v, want nothing pruned", keep, *result)
+		}
+	}
+}
+
+func TestClaimJobWithRepoLimit(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	mono := createRepo(t, db, "/tmp/monorepo")
+	small := createRepo(t, db, "/tmp/small")
+	// Three monorepo jobs are queued ahead of the small repo's job
+	for i := range 3 {
+		sha := fmt.Sprintf("mono%d", i)
+		enqueueJob(t, db, mono.ID, createCommit(t, db, mono.ID, sha).ID, sha)
+	}
+	smallJob := enqueueJob(t, db, small.ID, createCommit(t, db, small.ID, "small0").ID, "small0")
+
+	claim := func(workerID string) *ReviewJob {
+		t.Helper()
+		job, err := db.ClaimJobWithRepoLimit(workerID, 2)
+		if err != nil {
+			t.Fatalf("ClaimJobWithRepoLimit failed: %v", err)
+		}
+		return job
+	}
+
+	for i := range 2 {
+		job := claim(fmt.Sprintf("w%d", i))
+		if job == nil || job.RepoID != mono.ID {
+			t.Fatalf("claim %d: expected a monorepo job, got %+v", i, job)
+		}
+	}
+
+	// The monorepo is at its cap, so the small repo's job is next
+	if job := claim("w2"); job == nil || job.ID != smallJob.ID {
+		t.Fatalf("expected small repo job %d, got %+v", smallJob.ID, job)
+	}
+
+	// Only a capped monorepo job remains queued
+	if job := claim("w3"); job != nil {
+		t.Fatalf("expected nil when every queued job is capped, got job %d", job.ID)
+	}
+
+	// Without a limit the remaining job is claimable
+	if job := claimJob(t, db, "w4"); job == nil || job.RepoID != mono.ID {
+		t.Fatalf("expected unlimited claim to take the monorepo job, got %+v", job)
+	}
+}
+
+func TestClaimJobWithFixLimit(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/fix-limit")
+	commit := createCommit(t, db, repo.ID, "fixbase")
+	// Three fix jobs are queued ahead of two reviews
+	var fixIDs []int64
+	for i := range 3 {
+		job, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: fmt.Sprintf("fix%d", i), Agent: "codex", JobType: JobTypeFix, ParentJobID: 1})
+		if err != nil {
+			t.Fatalf("EnqueueJob fix: %v", err)
+		}
+		fixIDs = append(fixIDs, job.ID)
+	}
+	for i := range 2 {
+		sha := fmt.Sprintf("review%d", i)
+		enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, sha).ID, sha)
+	}
+
+	limits := ClaimLimits{Fix: 1}
+	claim := func(workerID string) *ReviewJob {
+		t.Helper()
+		job, err := db.ClaimJobWithLimits(workerID, limits)
+		if err != nil {
+			t.Fatalf("ClaimJobWithLimits failed: %v", err)
+		}
+		return job
+	}
+
+	if job := claim("w0"); job == nil || job.ID != fixIDs[0] {
+		t.Fatalf("expected first fix job %d, got %+v", fixIDs[0], job)
+	}
+	// With one fix job running, the reviews queued behind the fixes run
+	for i := 1; i <= 2; i++ {
+		if job := claim(fmt.Sprintf("w%d", i)); job == nil || job.JobType != JobTypeReview {
+			t.Fatalf("claim %d: expected a review job, got %+v", i, job)
+		}
+	}
+	if job := claim("w3"); job != nil {
+		t.Fatalf("expected nil while the fix cap is reached, got job %d", job.ID)
+	}
+
+	// Finishing the running fix frees its slot for the next one
+	if err := db.CompleteJob(fixIDs[0], "codex", "prompt", "done"); err != nil {
+		t.Fatalf("CompleteJob: %v", err)
+	}
+	if job := claim("w4"); job == nil || job.ID != fixIDs[1] {
+		t.Fatalf("expected second fix job %d, got %+v", fixIDs[1], job)
+	}
+}
+
+func TestClaimJobWithReviewLimit(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/review-limit")
+	commit := createCommit(t, db, repo.ID, "reviewbase")
+	// Three reviews are queued ahead of a fix job
+	for i := range 3 {
+		sha := fmt.Sprintf("review%d", i)
+		enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, sha).ID, sha)
+	}
+	fix, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "fix0", Agent: "codex", JobType: JobTypeFix, ParentJobID: 1})
+	if err != nil {
+		t.Fatalf("EnqueueJob fix: %v", err)
+	}
+
+	limits := ClaimLimits{Fix: 1, Review: 2}
+	claim := func(workerID string) *ReviewJob {
+		t.Helper()
+		job, err := db.ClaimJobWithLimits(workerID, limits)
+		if err != nil {
+			t.Fatalf("ClaimJobWithLimits failed: %v", err)
+		}
+		return job
+	}
+
+	for i := range 2 {
+		if job := claim(fmt.Sprintf("w%d", i)); job == nil || job.JobType != JobTypeReview {
+			t.Fatalf("claim %d: expected a review job, got %+v", i, job)
+		}
+	}
+	// The review pool is full, so the fix job behind the third review runs
+	if job := claim("w2"); job == nil || job.ID != fix.ID {
+		t.Fatalf("expected fix job %d, got %+v", fix.ID, job)
+	}
+	if job := claim("w3"); job != nil {
+		t.Fatalf("expected nil while both pools are full, got job %d", job.ID)
+	}
+}
+
+func TestClaimJobWhileQueuePaused(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/queue-paused")
+	job := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "paused0").ID, "paused0")
+
+	if err := db.SetSyncState(SyncStateQueuePaused, "1"); err != nil {
+		t.Fatalf("SetSyncState failed: %v", err)
+	}
+	claimed, err := db.ClaimJob("w0")
+	if err != nil {
+		t.Fatalf("ClaimJob failed: %v", err)
+	}
+	if claimed != nil {
+		t.Fatalf("expected nil while the queue is paused, got job %d", claimed.ID)
+	}
+
+	if err := db.SetSyncState(SyncStateQueuePaused, ""); err != nil {
+		t.Fatalf("SetSyncState failed: %v", err)
+	}
+	claimed, err = db.ClaimJob("w0")
+	if err != nil {
+		t.Fatalf("ClaimJob failed: %v", err)
+	}
+	if claimed == nil || claimed.ID != job.ID {
+		t.Fatalf("expected job %d after resuming, got %+v", job.ID, claimed)
+	}
+}
+
+func TestListJobsByMachine(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	localID, err := db.GetMachineID()
+	if err != nil {
+		t.Fatalf("GetMachineID failed: %v", err)
+	}
+	repo := createRepo(t, db, "/tmp/repo-machine")
+	local := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "m-local").ID, "m-local")
+	remote := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "m-remote").ID, "m-remote")
+	legacy := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "m-legacy").ID, "m-legacy")
+	if _, err := db.Exec(`UPDATE review_jobs SET source_machine_id = 'remote-1' WHERE id = ?`, remote.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE review_jobs SET source_machine_id = NULL WHERE id = ?`, legacy.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	jobIDs := func(jobs []ReviewJob) []int64 {
+		var ids []int64
+		for _, j := range jobs {
+			ids = append(ids, j.ID)
+		}
+		return ids
+	}
+
+	t.Run("by machine", func(t *testing.T) {
+		jobs, err := db.ListJobsByMachine("remote-1")
+		if err != nil {
+			t.Fatalf("ListJobsByMachine failed: %v", err)
+		}
+		if got := jobIDs(jobs); len(got) != 1 || got[0] != remote.ID {
+			t.Errorf("remote jobs = %v, want [%d]", got, remote.ID)
+		}
+	})
+
+	t.Run("local only includes jobs with no machine", func(t *testing.T) {
+		jobs, err := db.ListJobs("", "", 50, 0, WithMachineIDOrNone(localID))
+		if err != nil {
+			t.Fatalf("ListJobs failed: %v", err)
+		}
+		if got := jobIDs(jobs); len(got) != 2 || got[0] != legacy.ID || got[1] != local.ID {
+			t.Errorf("local jobs = %v, want [%d %d]", got, legacy.ID, local.ID)
+		}
+	})
+
+	t.Run("machine IDs", func(t *testing.T) {
+		ids, err := db.GetMachineIDs()
+		if err != nil {
+			t.Fatalf("GetMachineIDs failed: %v", err)
+		}
+		want := []string{localID, "remote-1"}
+		sort.Strings(want)
+		if !slices.Equal(ids, want) {
+			t.Errorf("machine IDs = %v, want %v", ids, want)
+		}
+	})
+}
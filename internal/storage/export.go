@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ExportOptions filters ExportReviews.
+type ExportOptions struct {
+	RepoPath string    // Only jobs in this repo root (empty = all repos)
+	Branch   string    // Only jobs on this branch (empty = any branch)
+	Since    time.Time // Only reviews finished at or after this time (zero = any time)
+}
+
+// ExportedReview is a completed review with the job metadata needed to
+// render it outside roborev.
+type ExportedReview struct {
+	JobID       int64     `json:"job_id"`
+	RepoName    string    `json:"repo_name"`
+	RepoPath    string    `json:"repo_path"`
+	GitRef      string    `json:"git_ref"`
+	Branch      string    `json:"branch,omitempty"`
+	Subject     string    `json:"subject,omitempty"` // Commit subject for single-commit reviews
+	Agent       string    `json:"agent"`
+	Model       string    `json:"model,omitempty"`
+	JobType     string    `json:"job_type"`
+	ReviewType  string    `json:"review_type,omitempty"`
+	FinishedAt  time.Time `json:"finished_at"`
+	Verdict     string    `json:"verdict"` // "P" or "F"
+	Summary     string    `json:"summary,omitempty"`
+	Output      string    `json:"output"`
+	Addressed   bool      `json:"addressed"`
+	DiffContent string    `json:"diff_content,omitempty"` // Stored diff (dirty reviews only)
+}
+
+// ExportReviews returns completed reviews matching opts, oldest first.
+func (db *DB) ExportReviews(opts ExportOptions) ([]ExportedReview, error) {
+	query := `
+		SELECT j.id, r.name, r.root_path, j.git_ref, j.branch, c.subject, j.agent, j.model,
+		       j.job_type, j.review_type, j.finished_at, rv.verdict_bool, rv.summary, rv.output,
+		       rv.addressed, j.diff_content
+		FROM review_jobs j
+		JOIN repos r ON r.id = j.repo_id
+		JOIN reviews rv ON rv.job_id = j.id
+		LEFT JOIN commits c ON c.id = j.commit_id
+		WHERE j.status = 'done'`
+	var args []any
+	if opts.RepoPath != "" {
+		query += ` AND r.root_path = ?`
+		args = append(args, opts.RepoPath)
+	}
+	if opts.Branch != "" {
+		query += ` AND j.branch = ?`
+		args = append(args, opts.Branch)
+	}
+	if !opts.Since.IsZero() {
+		query += ` AND datetime(j.finished_at) >= datetime(?)`
+		args = append(args, opts.Since.UTC().Format(time.RFC3339))
+	}
+	query += ` ORDER BY j.finished_at, j.id`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []ExportedReview
+	for rows.Next() {
+		var r ExportedReview
+		var branch, subject, model, reviewType, finishedAt, summary, diff sql.NullString
+		var verdictBool sql.NullInt64
+		var addressed int
+		if err := rows.Scan(&r.JobID, &r.RepoName, &r.RepoPath, &r.GitRef, &branch, &subject,
+			&r.Agent, &model, &r.JobType, &reviewType, &finishedAt, &verdictBool, &summary,
+			&r.Output, &addressed, &diff); err != nil {
+			return nil, err
+		}
+		r.Branch = branch.String
+		r.Subject = subject.String
+		r.Model = model.String
+		r.ReviewType = reviewType.String
+		r.Summary = summary.String
+		r.DiffContent = diff.String
+		r.Addressed = addressed != 0
+		r.Verdict = verdictFromBoolOrParse(verdictBool, r.Output)
+		if finishedAt.Valid {
+			r.FinishedAt, _ = time.Parse(time.RFC3339, finishedAt.String)
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews, rows.Err()
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExportReviews(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/export-repo")
+	other := createRepo(t, db, "/tmp/export-other")
+
+	finish := func(repo *Repo, sha, branch, output string, finished time.Time) int64 {
+		t.Helper()
+		commit := createCommit(t, db, repo.ID, sha)
+		job, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Branch: branch, Agent: "codex"})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		claimJob(t, db, "worker-1")
+		if err := db.CompleteJob(job.ID, "codex", "p", output); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE review_jobs SET finished_at = ? WHERE id = ?`,
+			finished.UTC().Format(time.RFC3339), job.ID); err != nil {
+			t.Fatalf("set finished_at: %v", err)
+		}
+		return job.ID
+	}
+
+	now := time.Now()
+	old := finish(repo, "aaa111", "main", "No issues found.", now.Add(-48*time.Hour))
+	recent := finish(repo, "bbb222", "feature", "- High: nil dereference in Load", now.Add(-time.Hour))
+	elsewhere := finish(other, "ccc333", "main", "No issues found.", now.Add(-2*time.Hour))
+
+	// A queued job without a review is never exported.
+	enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "ddd444").ID, "ddd444")
+
+	ids := func(reviews []ExportedReview) []int64 {
+		var out []int64
+		for _, r := range reviews {
+			out = append(out, r.JobID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		opts ExportOptions
+		want []int64
+	}{
+		{"all", ExportOptions{}, []int64{old, elsewhere, recent}},
+		{"repo", ExportOptions{RepoPath: repo.RootPath}, []int64{old, recent}},
+		{"branch", ExportOptions{Branch: "main"}, []int64{old, elsewhere}},
+		{"since", ExportOptions{Since: now.Add(-3 * time.Hour)}, []int64{elsewhere, recent}},
+		{"combined", ExportOptions{RepoPath: repo.RootPath, Since: now.Add(-3 * time.Hour)}, []int64{recent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.ExportReviews(tt.opts)
+			if err != nil {
+				t.Fatalf("ExportReviews: %v", err)
+			}
+			if g := ids(got); !slices.Equal(g, tt.want) {
+				t.Errorf("ExportReviews() = %v, want %v", g, tt.want)
+			}
+		})
+	}
+
+	got, err := db.ExportReviews(ExportOptions{RepoPath: repo.RootPath, Branch: "feature"})
+	if err != nil {
+		t.Fatalf("ExportReviews: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 review, got %d", len(got))
+	}
+	r := got[0]
+	if r.Verdict != "F" || r.Subject != "Subject" || r.RepoName != "export-repo" || r.Branch != "feature" {
+		t.Errorf("unexpected review metadata: %+v", r)
+	}
+}
+
+func TestExtractFindings(t *testing.T) {
+	output := "## Review\n\n" +
+		"- **High**: SQL built with string concatenation\n" +
+		"  in internal/db/query.go\n" +
+		"\n" +
+		"- Low: typo in comment\n" +
+		"\n" +
+		"Overall the change is fine."
+
+	got := ExtractFindings(output)
+	want := []Finding{
+		{Severity: "high", Text: "- **High**: SQL built with string concatenation\n  in internal/db/query.go"},
+		{Severity: "low", Text: "- Low: typo in comment"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ExtractFindings() returned %d findings, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := ExtractFindings("No issues found."); got != nil {
+		t.Errorf("expected no findings, got %+v", got)
+	}
+}
+
+func TestFindingTitleAndBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		f         Finding
+		wantTitle string
+		wantBody  string
+	}{
+		{"bold label", Finding{"high", "- **High**: SQL built with string concatenation\n  in internal/db/query.go"}, "SQL built with string concatenation", "in internal/db/query.go"},
+		{"em dash", Finding{"medium", "2. Medium — missing timeout"}, "missing timeout", ""},
+		{"severity field", Finding{"critical", "**Severity**: Critical - data loss on retry"}, "data loss on retry", ""},
+		{"label only", Finding{"low", "- **Low:**\n  Typo in comment\n  at line 3"}, "Typo in comment", "at line 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Title(); got != tt.wantTitle {
+				t.Errorf("Title() = %q, want %q", got, tt.wantTitle)
+			}
+			if got := tt.f.Body(); got != tt.wantBody {
+				t.Errorf("Body() = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
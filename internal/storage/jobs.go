@@ -2,12 +2,19 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"path"
 	"slices"
 	"strings"
 	"time"
+
+	"github.com/roborev-dev/roborev/internal/config"
 )
 
 // verdictToBool converts a ParseVerdict result ("P"/"F") to an integer
@@ -72,6 +79,349 @@ func ParseVerdict(output string) string {
 	return "F"
 }
 
+// VerdictPolicy adjusts how review output is turned into a pass/fail verdict.
+// The zero value matches ParseVerdict: any severity-labeled finding fails.
+type VerdictPolicy struct {
+	// FailThreshold is the minimum severity (critical, high, medium, low)
+	// that fails a review. Empty means low.
+	FailThreshold string
+	// BlockingKeywords fail the review when any appears in the output
+	// (case-insensitive), regardless of findings.
+	BlockingKeywords []string
+	// FindingIgnore lists substrings (case-insensitive); findings whose text
+	// contains any of them do not count toward the verdict.
+	FindingIgnore []string
+	// IgnoreRules suppress known-acceptable findings by text and/or path.
+	IgnoreRules []IgnoreRule
+	// LowConfidence is the LowConfidence* policy for a pass the agent
+	// marked low confidence. Empty means LowConfidenceIgnore.
+	LowConfidence string
+}
+
+// PolicyFromGating returns the verdict policy for a repo's resolved
+// verdict gating settings (see config.ResolveVerdictGating).
+func PolicyFromGating(gating config.VerdictGating) VerdictPolicy {
+	policy := VerdictPolicy{
+		FailThreshold:    gating.FailThreshold,
+		BlockingKeywords: gating.BlockingKeywords,
+		FindingIgnore:    gating.FindingIgnore,
+		LowConfidence:    gating.LowConfidence,
+	}
+	for _, r := range gating.IgnoreRules {
+		policy.IgnoreRules = append(policy.IgnoreRules, IgnoreRule{Pattern: r.Pattern, Path: r.Path})
+	}
+	return policy
+}
+
+// Verdict returns the verdict stored for output under the policy,
+// including its low-confidence handling: the same verdict CompleteJob
+// records, for reviews that are not stored.
+func (p VerdictPolicy) Verdict(output string) string {
+	verdict, _ := p.applyLowConfidence(ParseVerdictWithPolicy(MarkSuppressedFindings(output, p), p), ParseConfidence(output))
+	return verdict
+}
+
+// IgnoreRule matches findings to suppress. Pattern is a case-insensitive
+// substring of the finding text; Path is a glob matched against file paths
+// mentioned in the finding (full path or basename), or a directory prefix.
+// When both are set, both must match. A rule with neither never matches.
+type IgnoreRule struct {
+	Pattern string
+	Path    string
+}
+
+// matches reports whether the rule applies to a finding (lowercased text).
+func (r IgnoreRule) matches(text string) bool {
+	pattern := strings.ToLower(strings.TrimSpace(r.Pattern))
+	glob := strings.ToLower(strings.TrimSpace(r.Path))
+	if pattern == "" && glob == "" {
+		return false
+	}
+	if pattern != "" && !strings.Contains(text, pattern) {
+		return false
+	}
+	return glob == "" || mentionsPath(text, glob)
+}
+
+// mentionsPath reports whether any path-like token in text matches glob.
+// Trailing ":line" suffixes and surrounding markdown punctuation are
+// ignored, so "`internal/db.go:42`" matches "internal/*.go" and "db.go".
+func mentionsPath(text, glob string) bool {
+	dirPrefix := strings.TrimSuffix(glob, "/") + "/"
+	for _, tok := range strings.Fields(text) {
+		tok = strings.Trim(tok, "`*_()[]{}<>\"',;")
+		for {
+			i := strings.LastIndexByte(tok, ':')
+			if i < 0 || strings.Trim(tok[i+1:], "0123456789-") != "" {
+				break
+			}
+			tok = tok[:i]
+		}
+		tok = strings.TrimSuffix(tok, ".")
+		if tok == "" {
+			continue
+		}
+		if ok, _ := path.Match(glob, tok); ok {
+			return true
+		}
+		if ok, _ := path.Match(glob, path.Base(tok)); ok {
+			return true
+		}
+		if strings.HasPrefix(tok, dirPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignored reports whether a finding (lowercased text) is suppressed by the
+// policy's ignore patterns or rules.
+func (p VerdictPolicy) ignored(text string) bool {
+	if matchesAny(text, p.FindingIgnore) {
+		return true
+	}
+	for _, r := range p.IgnoreRules {
+		if r.matches(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressedMarker is appended to findings suppressed by ignore rules.
+const suppressedMarker = " [suppressed]"
+
+// MarkSuppressedFindings appends a "[suppressed]" marker to the first line of
+// each finding the policy ignores, so readers can see why it did not count.
+// Output without ignored findings is returned unchanged.
+func MarkSuppressedFindings(output string, policy VerdictPolicy) string {
+	if len(policy.FindingIgnore) == 0 && len(policy.IgnoreRules) == 0 {
+		return output
+	}
+	lines := strings.Split(output, "\n")
+	lc := strings.Split(strings.ToLower(output), "\n")
+	if len(lc) != len(lines) {
+		return output
+	}
+	changed := false
+	for i := range lc {
+		if lineSeverity(lc, i) == "" || !policy.ignored(findingText(lc, i)) {
+			continue
+		}
+		if !strings.HasSuffix(strings.TrimRight(lines[i], " \t\r"), strings.TrimSpace(suppressedMarker)) {
+			lines[i] = strings.TrimRight(lines[i], " \t\r") + suppressedMarker
+			changed = true
+		}
+	}
+	if !changed {
+		return output
+	}
+	return strings.Join(lines, "\n")
+}
+
+// severityRank orders severity levels; unknown levels rank as low.
+func severityRank(sev string) int {
+	switch sev {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// ParseVerdictWithPolicy is ParseVerdict with per-repo gating applied.
+// Blocking keywords always fail. Findings below the fail threshold or
+// matching an ignore pattern or rule are dropped; if findings were present but all
+// were dropped, the review passes.
+func ParseVerdictWithPolicy(output string, policy VerdictPolicy) string {
+	lc := strings.ToLower(output)
+	for _, kw := range policy.BlockingKeywords {
+		if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" && strings.Contains(lc, kw) {
+			return "F"
+		}
+	}
+
+	threshold := severityRank(strings.ToLower(strings.TrimSpace(policy.FailThreshold)))
+	lines := strings.Split(lc, "\n")
+	sawFinding := false
+	for i := range lines {
+		sev := lineSeverity(lines, i)
+		if sev == "" {
+			continue
+		}
+		sawFinding = true
+		if severityRank(sev) < threshold {
+			continue
+		}
+		if policy.ignored(findingText(lines, i)) {
+			continue
+		}
+		return "F"
+	}
+	if sawFinding {
+		return "P"
+	}
+	return ParseVerdict(output)
+}
+
+// findingText returns the finding starting at lines[i]: that line plus any
+// continuation lines up to the next blank line or severity-labeled line.
+func findingText(lines []string, i int) string {
+	end := i + 1
+	for end < len(lines) && strings.TrimSpace(lines[end]) != "" && lineSeverity(lines, end) == "" {
+		end++
+	}
+	return strings.Join(lines[i:end], "\n")
+}
+
+// Finding is a severity-labeled item parsed from review output.
+type Finding struct {
+	Severity string `json:"severity"` // critical, high, medium, or low
+	Text     string `json:"text"`     // The finding line plus its continuation lines
+}
+
+// Title returns the finding's headline: its first line without the list
+// marker, markdown emphasis, or severity label. A label-only first line
+// (e.g. "**High:**") falls back to the first continuation line.
+func (f Finding) Title() string {
+	title, _ := f.split()
+	return title
+}
+
+// Body returns the finding's continuation lines after the title, each
+// trimmed of indentation, or "" for a one-line finding.
+func (f Finding) Body() string {
+	_, body := f.split()
+	return body
+}
+
+func (f Finding) split() (title, body string) {
+	var lines []string
+	for line := range strings.SplitSeq(f.Text, "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	s := lines[0]
+	if s != "" && (s[0] == '-' || s[0] == '*' || (s[0] >= '0' && s[0] <= '9') || strings.HasPrefix(s, "•")) {
+		s = strings.TrimLeft(s, "-*•0123456789.) ")
+	}
+	s = stripMarkdown(s)
+	for _, label := range []string{"severity", f.Severity} {
+		if label != "" && strings.HasPrefix(strings.ToLower(s), label) {
+			s = strings.TrimSpace(strings.TrimLeft(s[len(label):], ":-–—| "))
+		}
+	}
+	rest := lines[1:]
+	if s == "" && len(rest) > 0 {
+		s, rest = rest[0], rest[1:]
+	}
+	return s, strings.TrimSpace(strings.Join(rest, "\n"))
+}
+
+// ExtractFindings returns the severity-labeled findings in review output,
+// in order. Output without severity labels yields nil.
+func ExtractFindings(output string) []Finding {
+	lines := strings.Split(output, "\n")
+	lc := strings.Split(strings.ToLower(output), "\n")
+	if len(lc) != len(lines) {
+		return nil
+	}
+	var findings []Finding
+	for i := range lc {
+		sev := lineSeverity(lc, i)
+		if sev == "" {
+			continue
+		}
+		end := i + strings.Count(findingText(lc, i), "\n") + 1
+		findings = append(findings, Finding{
+			Severity: sev,
+			Text:     strings.TrimSpace(strings.Join(lines[i:end], "\n")),
+		})
+	}
+	return findings
+}
+
+// HighestSeverityFindings returns the findings at the most severe level
+// present, in order.
+func HighestSeverityFindings(findings []Finding) []Finding {
+	top := 0
+	for _, f := range findings {
+		top = max(top, severityRank(f.Severity))
+	}
+	var out []Finding
+	for _, f := range findings {
+		if severityRank(f.Severity) == top {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// SeverityCounts is the number of findings at each severity in a review.
+type SeverityCounts struct {
+	Critical int `json:"critical,omitempty"`
+	High     int `json:"high,omitempty"`
+	Medium   int `json:"medium,omitempty"`
+	Low      int `json:"low,omitempty"`
+}
+
+// Total returns the number of findings across all severities.
+func (c SeverityCounts) Total() int {
+	return c.Critical + c.High + c.Medium + c.Low
+}
+
+// CountSeverities counts the severity-labeled findings in review output,
+// skipping findings the policy ignores. Findings below the policy's fail
+// threshold are still counted.
+func CountSeverities(output string, policy VerdictPolicy) SeverityCounts {
+	var c SeverityCounts
+	lines := strings.Split(strings.ToLower(output), "\n")
+	for i := range lines {
+		sev := lineSeverity(lines, i)
+		if sev == "" || policy.ignored(findingText(lines, i)) {
+			continue
+		}
+		switch severityRank(sev) {
+		case 4:
+			c.Critical++
+		case 3:
+			c.High++
+		case 2:
+			c.Medium++
+		default:
+			c.Low++
+		}
+	}
+	return c
+}
+
+// parseSeverityCounts decodes a review's severity_counts column. NULL
+// (reviews stored before counts were recorded) yields nil.
+func parseSeverityCounts(s sql.NullString) *SeverityCounts {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	var c SeverityCounts
+	if err := json.Unmarshal([]byte(s.String), &c); err != nil {
+		return nil
+	}
+	return &c
+}
+
+// matchesAny reports whether text (already lowercased) contains any of the
+// patterns, compared case-insensitively. Empty patterns never match.
+func matchesAny(text string, patterns []string) bool {
+	for _, p := range patterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" && strings.Contains(text, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // stripMarkdown removes common markdown formatting from a line
 func stripMarkdown(s string) string {
 	// Strip leading markdown headers (##, ###, etc.)
@@ -141,97 +491,105 @@ func stripFieldLabel(s string) string {
 // Requires separators to be followed by space to avoid "High-level overview".
 // Skips lines that appear to be part of a severity legend/rubric.
 func hasSeverityLabel(output string) bool {
-	lc := strings.ToLower(output)
-	severities := []string{"critical", "high", "medium", "low"}
-	lines := strings.Split(lc, "\n")
-
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if len(trimmed) == 0 {
-			continue
+	lines := strings.Split(strings.ToLower(output), "\n")
+	for i := range lines {
+		if lineSeverity(lines, i) != "" {
+			return true
 		}
+	}
+	return false
+}
 
-		// Check if line starts with bullet/number - if so, strip it
-		first := trimmed[0]
-		hasBullet := first == '-' || first == '*' || (first >= '0' && first <= '9') ||
-			strings.HasPrefix(trimmed, "•")
+// lineSeverity returns the severity ("critical", "high", "medium", "low")
+// labeled on lines[i], or "" if the line is not a severity-labeled finding.
+// lines must already be lowercased.
+func lineSeverity(lines []string, i int) string {
+	severities := []string{"critical", "high", "medium", "low"}
+	trimmed := strings.TrimSpace(lines[i])
+	if len(trimmed) == 0 {
+		return ""
+	}
 
-		checkText := trimmed
-		if hasBullet {
-			// Strip leading bullets/asterisks/numbers
-			checkText = strings.TrimLeft(trimmed, "-*•0123456789.) ")
-			checkText = strings.TrimSpace(checkText)
-		}
+	// Check if line starts with bullet/number - if so, strip it
+	first := trimmed[0]
+	hasBullet := first == '-' || first == '*' || (first >= '0' && first <= '9') ||
+		strings.HasPrefix(trimmed, "•")
 
-		// Strip markdown formatting (bold, headers) before checking
-		checkText = stripMarkdown(checkText)
+	checkText := trimmed
+	if hasBullet {
+		// Strip leading bullets/asterisks/numbers
+		checkText = strings.TrimLeft(trimmed, "-*•0123456789.) ")
+		checkText = strings.TrimSpace(checkText)
+	}
 
-		// Check if text starts with a severity word
-		for _, sev := range severities {
-			if !strings.HasPrefix(checkText, sev) {
-				continue
-			}
+	// Strip markdown formatting (bold, headers) before checking
+	checkText = stripMarkdown(checkText)
 
-			// Check if followed by separator (dash, em-dash, colon, pipe)
-			rest := checkText[len(sev):]
-			rest = strings.TrimSpace(rest)
-			if len(rest) == 0 {
-				continue
-			}
+	// Check if text starts with a severity word
+	for _, sev := range severities {
+		if !strings.HasPrefix(checkText, sev) {
+			continue
+		}
 
-			// Check for valid separator
-			hasValidSep := false
-			// Check for em-dash or en-dash (these are unambiguous)
-			if strings.HasPrefix(rest, "—") || strings.HasPrefix(rest, "–") {
-				hasValidSep = true
-			}
-			// Check for colon or pipe (unambiguous separators)
-			if rest[0] == ':' || rest[0] == '|' {
-				hasValidSep = true
-			}
-			// For hyphen, require space after to avoid "High-level"
-			if rest[0] == '-' && len(rest) > 1 && rest[1] == ' ' {
-				hasValidSep = true
-			}
+		// Check if followed by separator (dash, em-dash, colon, pipe)
+		rest := checkText[len(sev):]
+		rest = strings.TrimSpace(rest)
+		if len(rest) == 0 {
+			continue
+		}
 
-			if !hasValidSep {
-				continue
-			}
+		// Check for valid separator
+		hasValidSep := false
+		// Check for em-dash or en-dash (these are unambiguous)
+		if strings.HasPrefix(rest, "—") || strings.HasPrefix(rest, "–") {
+			hasValidSep = true
+		}
+		// Check for colon or pipe (unambiguous separators)
+		if rest[0] == ':' || rest[0] == '|' {
+			hasValidSep = true
+		}
+		// For hyphen, require space after to avoid "High-level"
+		if rest[0] == '-' && len(rest) > 1 && rest[1] == ' ' {
+			hasValidSep = true
+		}
 
-			// Skip if this looks like a legend/rubric entry
-			// Check if previous non-empty line is a legend header
-			if isLegendEntry(lines, i) {
-				continue
-			}
+		if !hasValidSep {
+			continue
+		}
 
-			return true
+		// Skip if this looks like a legend/rubric entry
+		// Check if previous non-empty line is a legend header
+		if isLegendEntry(lines, i) {
+			continue
 		}
 
-		// Check for "severity: <level>" pattern (e.g., "**Severity**: High")
-		if strings.HasPrefix(checkText, "severity") {
-			rest := checkText[len("severity"):]
+		return sev
+	}
+
+	// Check for "severity: <level>" pattern (e.g., "**Severity**: High")
+	if strings.HasPrefix(checkText, "severity") {
+		rest := checkText[len("severity"):]
+		rest = strings.TrimSpace(rest)
+		hasSep := len(rest) > 0 && (rest[0] == ':' || rest[0] == '|' ||
+			strings.HasPrefix(rest, "—") || strings.HasPrefix(rest, "–"))
+		// Accept hyphen-minus when followed by space (mirrors the severity-word branch)
+		if !hasSep && len(rest) > 1 && rest[0] == '-' && rest[1] == ' ' {
+			hasSep = true
+		}
+		if hasSep {
+			// Skip separator and whitespace
+			rest = strings.TrimLeft(rest, ":-–—| ")
 			rest = strings.TrimSpace(rest)
-			hasSep := len(rest) > 0 && (rest[0] == ':' || rest[0] == '|' ||
-				strings.HasPrefix(rest, "—") || strings.HasPrefix(rest, "–"))
-			// Accept hyphen-minus when followed by space (mirrors the severity-word branch)
-			if !hasSep && len(rest) > 1 && rest[0] == '-' && rest[1] == ' ' {
-				hasSep = true
-			}
-			if hasSep {
-				// Skip separator and whitespace
-				rest = strings.TrimLeft(rest, ":-–—| ")
-				rest = strings.TrimSpace(rest)
-				for _, sev := range severities {
-					if strings.HasPrefix(rest, sev) {
-						if !isLegendEntry(lines, i) {
-							return true
-						}
+			for _, sev := range severities {
+				if strings.HasPrefix(rest, sev) {
+					if !isLegendEntry(lines, i) {
+						return sev
 					}
 				}
 			}
 		}
 	}
-	return false
+	return ""
 }
 
 // isLegendEntry checks if a line at index i appears to be part of a severity legend/rubric
@@ -733,26 +1091,53 @@ func parseSQLiteTime(s string) time.Time {
 //   - CommitID > 0 → "review" (single commit)
 //   - otherwise → "range" (commit range)
 type EnqueueOpts struct {
-	RepoID       int64
-	CommitID     int64  // >0 for single-commit reviews
-	GitRef       string // SHA, "start..end" range, or "dirty"
-	Branch       string
-	Agent        string
-	Model        string
-	Reasoning    string
-	ReviewType   string // e.g. "security" — changes which system prompt is used
-	PatchID      string // Stable patch-id for rebase tracking
-	DiffContent  string // For dirty reviews (captured at enqueue time)
-	Prompt       string // For task jobs (pre-stored prompt)
-	OutputPrefix string // Prefix to prepend to review output
-	Agentic      bool   // Allow file edits and command execution
-	Label        string // Display label in TUI for task jobs (default: "prompt")
-	JobType      string // Explicit job type (review/range/dirty/task/compact/fix); inferred if empty
-	ParentJobID  int64  // Parent job being fixed (for fix jobs)
+	RepoID          int64
+	CommitID        int64  // >0 for single-commit reviews
+	GitRef          string // SHA, "start..end" range, "dirty", or "stash@{N}"
+	Branch          string
+	Agent           string
+	Model           string
+	Reasoning       string
+	ReviewType      string // e.g. "security" — changes which system prompt is used
+	PatchID         string // Stable patch-id for rebase tracking
+	DiffHash        string // Hash of the diff to review (see HashDiff), for reusing a review of an identical diff
+	DiffContent     string // For dirty reviews, filtered ranges, or edited commits (captured at enqueue time)
+	Prompt          string // For task jobs (pre-stored prompt)
+	OutputPrefix    string // Prefix to prepend to review output
+	Agentic         bool   // Allow file edits and command execution
+	Label           string // Display label in TUI for task jobs (default: "prompt")
+	JobType         string // Explicit job type (review/range/dirty/task/compact/fix); inferred if empty
+	ParentJobID     int64  // Parent job being fixed (for fix jobs)
+	RetryOfJobID    int64  // Job being retried (set by EnqueueRetry)
+	SupersedesJobID int64  // Review being re-run (set by RerunReview)
+	RangeLabel      string // Range the commit was enqueued from, shared by its sibling jobs, or the range a range job reviews
+	RangeBase       string // Base commit of a range review; GitRef is its tip and is stored as "base..tip"
+	RangeCommits    int    // Commits in a range review, for display
 }
 
 // EnqueueJob creates a new review job. The job type is inferred from opts.
 func (db *DB) EnqueueJob(opts EnqueueOpts) (*ReviewJob, error) {
+	return db.insertJob(opts, JobStatusQueued)
+}
+
+// RecordLocalReview stores a review that was run outside the daemon (e.g.
+// by "roborev review --local") as a finished job, so it shows up alongside
+// daemon reviews. The job is inserted as running rather than queued so no
+// worker can claim it before it is completed.
+func (db *DB) RecordLocalReview(opts EnqueueOpts, prompt, output string, policy VerdictPolicy) (*ReviewJob, error) {
+	job, err := db.insertJob(opts, JobStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.CompleteJobWithPolicy(job.ID, job.Agent, prompt, output, policy); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// insertJob creates a job in the given initial status (queued, or running
+// for a review that is already under way).
+func (db *DB) insertJob(opts EnqueueOpts, status JobStatus) (*ReviewJob, error) {
 	reasoning := opts.Reasoning
 	if reasoning == "" {
 		reasoning = "thorough"
@@ -777,6 +1162,9 @@ func (db *DB) EnqueueJob(opts EnqueueOpts) (*ReviewJob, error) {
 
 	// For task jobs, use Label as git_ref display value
 	gitRef := opts.GitRef
+	if opts.RangeBase != "" {
+		gitRef = opts.RangeBase + ".." + opts.GitRef
+	}
 	if jobType == JobTypeTask {
 		if opts.Label != "" {
 			gitRef = opts.Label
@@ -807,17 +1195,37 @@ func (db *DB) EnqueueJob(opts EnqueueOpts) (*ReviewJob, error) {
 		parentJobIDParam = opts.ParentJobID
 	}
 
-	result, err := db.Exec(`
+	var retryOfParam any
+	if opts.RetryOfJobID > 0 {
+		retryOfParam = opts.RetryOfJobID
+	}
+
+	var supersedesParam any
+	if opts.SupersedesJobID > 0 {
+		supersedesParam = opts.SupersedesJobID
+	}
+
+	var startedAt any
+	if status == JobStatusRunning {
+		startedAt = nowStr
+	}
+
+	var result sql.Result
+	err := retryOnBusy(func() error {
+		var err error
+		result, err = db.Exec(`
 		INSERT INTO review_jobs (repo_id, commit_id, git_ref, branch, agent, model, reasoning,
-			status, job_type, review_type, patch_id, diff_content, prompt, agentic, output_prefix,
-			parent_job_id, uuid, source_machine_id, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, 'queued', ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		opts.RepoID, commitIDParam, gitRef, nullString(opts.Branch),
-		opts.Agent, nullString(opts.Model), reasoning,
-		jobType, opts.ReviewType, nullString(opts.PatchID),
-		nullString(opts.DiffContent), nullString(opts.Prompt), agenticInt,
-		nullString(opts.OutputPrefix), parentJobIDParam,
-		uid, machineID, nowStr)
+			status, started_at, job_type, review_type, patch_id, diff_content, prompt, agentic, output_prefix,
+			parent_job_id, retry_of_job_id, supersedes_job_id, range_label, range_commits, diff_hash, uuid, source_machine_id, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			opts.RepoID, commitIDParam, gitRef, nullString(opts.Branch),
+			opts.Agent, nullString(opts.Model), reasoning,
+			status, startedAt, jobType, opts.ReviewType, nullString(opts.PatchID),
+			nullString(opts.DiffContent), nullString(opts.Prompt), agenticInt,
+			nullString(opts.OutputPrefix), parentJobIDParam, retryOfParam, supersedesParam,
+			nullString(opts.RangeLabel), opts.RangeCommits, nullString(opts.DiffHash), uid, machineID, nowStr)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -834,11 +1242,14 @@ func (db *DB) EnqueueJob(opts EnqueueOpts) (*ReviewJob, error) {
 		JobType:         jobType,
 		ReviewType:      opts.ReviewType,
 		PatchID:         opts.PatchID,
-		Status:          JobStatusQueued,
+		DiffHash:        opts.DiffHash,
+		Status:          status,
 		EnqueuedAt:      now,
 		Prompt:          opts.Prompt,
 		Agentic:         opts.Agentic,
 		OutputPrefix:    opts.OutputPrefix,
+		RangeLabel:      opts.RangeLabel,
+		RangeCommits:    opts.RangeCommits,
 		UUID:            uid,
 		SourceMachineID: machineID,
 		UpdatedAt:       &now,
@@ -846,6 +1257,12 @@ func (db *DB) EnqueueJob(opts EnqueueOpts) (*ReviewJob, error) {
 	if opts.ParentJobID > 0 {
 		job.ParentJobID = &opts.ParentJobID
 	}
+	if opts.RetryOfJobID > 0 {
+		job.RetryOfJobID = &opts.RetryOfJobID
+	}
+	if opts.SupersedesJobID > 0 {
+		job.SupersedesJobID = &opts.SupersedesJobID
+	}
 	if opts.CommitID > 0 {
 		job.CommitID = &opts.CommitID
 	}
@@ -857,21 +1274,65 @@ func (db *DB) EnqueueJob(opts EnqueueOpts) (*ReviewJob, error) {
 
 // ClaimJob atomically claims the next queued job for a worker
 func (db *DB) ClaimJob(workerID string) (*ReviewJob, error) {
+	return db.ClaimJobWithLimits(workerID, ClaimLimits{})
+}
+
+// ClaimJobWithRepoLimit atomically claims the next queued job for a worker,
+// skipping jobs still backing off after a retry and jobs whose repo
+// already has perRepoMax running jobs. A perRepoMax of zero or less means
+// no per-repo limit. Returns nil when the queue is empty or every queued
+// job is blocked.
+func (db *DB) ClaimJobWithRepoLimit(workerID string, perRepoMax int) (*ReviewJob, error) {
+	return db.ClaimJobWithLimits(workerID, ClaimLimits{PerRepo: perRepoMax})
+}
+
+// ClaimLimits caps how many running jobs ClaimJobWithLimits allows. Zero
+// or less means no limit.
+type ClaimLimits struct {
+	PerRepo int // Running jobs per repo
+	Fix     int // Running fix jobs across all repos
+	Review  int // Running non-fix jobs across all repos
+}
+
+// ClaimJobWithLimits atomically claims the next queued job for a worker,
+// skipping jobs still backing off after a retry and jobs that would
+// exceed limits. Jobs blocked by a limit don't hold up the jobs queued
+// behind them. Returns nil when the queue is empty, every queued job is
+// blocked, or the user has paused the queue (SyncStateQueuePaused).
+func (db *DB) ClaimJobWithLimits(workerID string, limits ClaimLimits) (*ReviewJob, error) {
 	now := time.Now()
 	nowStr := now.Format(time.RFC3339)
 
 	// Atomically claim a job by updating it in a single statement
-	// This prevents race conditions where two workers select the same job
+	// This prevents race conditions where two workers select the same job,
+	// and keeps the running-per-repo count consistent with the claim
 	result, err := db.Exec(`
 		UPDATE review_jobs
 		SET status = 'running', worker_id = ?, started_at = ?, updated_at = ?
 		WHERE id = (
-			SELECT id FROM review_jobs
-			WHERE status = 'queued'
-			ORDER BY enqueued_at, id
+			SELECT q.id FROM review_jobs q
+			LEFT JOIN (
+				SELECT repo_id, COUNT(*) AS running
+				FROM review_jobs
+				WHERE status = 'running'
+				GROUP BY repo_id
+			) rc ON rc.repo_id = q.repo_id
+			WHERE q.status = 'queued'
+			AND NOT EXISTS (SELECT 1 FROM sync_state WHERE key = ? AND value = '1')
+			AND (q.retry_after IS NULL OR datetime(q.retry_after) <= datetime(?))
+			AND (? <= 0 OR COALESCE(rc.running, 0) < ?)
+			AND (? <= 0 OR COALESCE(q.job_type, '') != 'fix' OR (
+				SELECT COUNT(*) FROM review_jobs
+				WHERE status = 'running' AND job_type = 'fix'
+			) < ?)
+			AND (? <= 0 OR COALESCE(q.job_type, '') = 'fix' OR (
+				SELECT COUNT(*) FROM review_jobs
+				WHERE status = 'running' AND COALESCE(job_type, '') != 'fix'
+			) < ?)
+			ORDER BY q.enqueued_at, q.id
 			LIMIT 1
 		)
-	`, workerID, nowStr, nowStr)
+	`, workerID, nowStr, nowStr, SyncStateQueuePaused, nowStr, limits.PerRepo, limits.PerRepo, limits.Fix, limits.Fix, limits.Review, limits.Review)
 	if err != nil {
 		return nil, err
 	}
@@ -897,12 +1358,12 @@ func (db *DB) ClaimJob(workerID string) (*ReviewJob, error) {
 	var jobType sql.NullString
 	var reviewType sql.NullString
 	var outputPrefix sql.NullString
-	var patchID sql.NullString
+	var patchID, diffHash sql.NullString
 	var parentJobID sql.NullInt64
 	err = db.QueryRow(`
 		SELECT j.id, j.repo_id, j.commit_id, j.git_ref, j.branch, j.agent, j.model, j.reasoning, j.status, j.enqueued_at,
 		       r.root_path, r.name, c.subject, j.diff_content, j.prompt, COALESCE(j.agentic, 0), j.job_type, j.review_type,
-		       j.output_prefix, j.patch_id, j.parent_job_id
+		       j.output_prefix, j.patch_id, j.parent_job_id, j.diff_hash
 		FROM review_jobs j
 		JOIN repos r ON r.id = j.repo_id
 		LEFT JOIN commits c ON c.id = j.commit_id
@@ -911,7 +1372,7 @@ func (db *DB) ClaimJob(workerID string) (*ReviewJob, error) {
 		LIMIT 1
 	`, workerID).Scan(&job.ID, &job.RepoID, &commitID, &job.GitRef, &branch, &job.Agent, &model, &job.Reasoning, &job.Status, &enqueuedAt,
 		&job.RepoPath, &job.RepoName, &commitSubject, &diffContent, &prompt, &agenticInt, &jobType, &reviewType,
-		&outputPrefix, &patchID, &parentJobID)
+		&outputPrefix, &patchID, &parentJobID, &diffHash)
 	if err != nil {
 		return nil, err
 	}
@@ -950,6 +1411,7 @@ func (db *DB) ClaimJob(workerID string) (*ReviewJob, error) {
 	if parentJobID.Valid {
 		job.ParentJobID = &parentJobID.Int64
 	}
+	job.DiffHash = diffHash.String
 	job.EnqueuedAt = parseSQLiteTime(enqueuedAt)
 	job.Status = JobStatusRunning
 	job.WorkerID = workerID
@@ -973,6 +1435,12 @@ func (db *DB) SaveJobPatch(jobID int64, patch string) error {
 // and persists the patch in a single transaction. This prevents invalid
 // states where a patch is written but the job isn't done, or vice versa.
 func (db *DB) CompleteFixJob(jobID int64, agent, prompt, output, patch string) error {
+	return db.CompleteFixJobByAgent(jobID, agent, agent, prompt, output, patch)
+}
+
+// CompleteFixJobByAgent is CompleteFixJob for a review that effectiveAgent
+// produced in place of agent (see fallback_agents).
+func (db *DB) CompleteFixJobByAgent(jobID int64, agent, effectiveAgent, prompt, output, patch string) error {
 	now := time.Now().Format(time.RFC3339)
 	machineID, _ := db.GetMachineID()
 	reviewUUID := GenerateUUID()
@@ -1026,8 +1494,8 @@ func (db *DB) CompleteFixJob(jobID int64, agent, prompt, output, patch string) e
 
 	verdictBool := verdictToBool(ParseVerdict(finalOutput))
 	_, err = conn.ExecContext(ctx,
-		`INSERT INTO reviews (job_id, agent, prompt, output, verdict_bool, uuid, updated_by_machine_id, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		jobID, agent, prompt, finalOutput, verdictBool, reviewUUID, machineID, now)
+		`INSERT INTO reviews (job_id, agent, effective_agent, prompt, output, summary, verdict_bool, uuid, updated_by_machine_id, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		jobID, agent, effectiveAgent, prompt, finalOutput, nullString(ExtractSummary(output)), verdictBool, reviewUUID, machineID, now)
 	if err != nil {
 		return err
 	}
@@ -1044,76 +1512,231 @@ func (db *DB) CompleteFixJob(jobID int64, agent, prompt, output, patch string) e
 // Only updates if job is still in 'running' state (respects cancellation).
 // If the job has an output_prefix, it will be prepended to the output.
 func (db *DB) CompleteJob(jobID int64, agent, prompt, output string) error {
+	return db.CompleteJobWithPolicy(jobID, agent, prompt, output, VerdictPolicy{})
+}
+
+// CompleteJobWithPolicy is CompleteJob with the stored verdict computed
+// under the given policy (the effective gating settings for the job's repo).
+func (db *DB) CompleteJobWithPolicy(jobID int64, agent, prompt, output string, policy VerdictPolicy) error {
+	return db.CompleteJobWithUsage(jobID, agent, prompt, output, policy, nil)
+}
+
+// TokenUsage is the token consumption and cost an agent reported for a
+// review. A zero CostUSD means the agent did not report cost.
+type TokenUsage struct {
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+}
+
+// CompleteJobWithUsage is CompleteJobWithPolicy that also stores the agent's
+// token usage on the review. A nil usage leaves the usage columns NULL.
+func (db *DB) CompleteJobWithUsage(jobID int64, agent, prompt, output string, policy VerdictPolicy, usage *TokenUsage) error {
+	return db.CompleteJobByAgent(jobID, agent, agent, prompt, output, policy, usage)
+}
+
+// CompleteJobByAgent is CompleteJobWithUsage for a review that
+// effectiveAgent produced in place of agent (see fallback_agents).
+func (db *DB) CompleteJobByAgent(jobID int64, agent, effectiveAgent, prompt, output string, policy VerdictPolicy, usage *TokenUsage) error {
 	// Get machine ID and generate UUIDs before starting transaction
 	// to avoid potential lock conflicts with GetMachineID's writes
 	now := time.Now().Format(time.RFC3339)
 	machineID, _ := db.GetMachineID()
 	reviewUUID := GenerateUUID()
 
-	// Use BEGIN IMMEDIATE to acquire write lock upfront, avoiding deadlocks
-	// when concurrent goroutines (workers, sync) try to upgrade from read to write.
-	ctx := context.Background()
-	conn, err := db.Conn(ctx)
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
+	// Retry the whole transaction on transient lock errors that
+	// busy_timeout doesn't absorb (e.g. another process sharing the DB).
+	return retryOnBusy(func() error {
+		// Use BEGIN IMMEDIATE to acquire write lock upfront, avoiding deadlocks
+		// when concurrent goroutines (workers, sync) try to upgrade from read to write.
+		ctx := context.Background()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
 
-	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
-		return err
-	}
-	committed := false
-	defer func() {
-		if !committed {
-			if _, err := conn.ExecContext(ctx, "ROLLBACK"); err != nil {
-				log.Printf("jobs CompleteJob: rollback failed: %v", err)
+		if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+			return err
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				if _, err := conn.ExecContext(ctx, "ROLLBACK"); err != nil {
+					log.Printf("jobs CompleteJob: rollback failed: %v", err)
+				}
 			}
+		}()
+
+		// Fetch output_prefix from job (if any)
+		var outputPrefix sql.NullString
+		var jobType string
+		err = conn.QueryRowContext(ctx, `SELECT output_prefix, job_type FROM review_jobs WHERE id = ?`, jobID).Scan(&outputPrefix, &jobType)
+		if err != nil && err != sql.ErrNoRows {
+			return err
 		}
-	}()
 
-	// Fetch output_prefix from job (if any)
-	var outputPrefix sql.NullString
-	err = conn.QueryRowContext(ctx, `SELECT output_prefix FROM review_jobs WHERE id = ?`, jobID).Scan(&outputPrefix)
-	if err != nil && err != sql.ErrNoRows {
-		return err
-	}
+		// Capture a patch the agent suggested inline so it can be applied
+		// without a separate fix job. Fix jobs store their own patch.
+		var suggestedPatch string
+		if jobType != JobTypeFix {
+			suggestedPatch = ExtractSuggestedPatch(output)
+		}
 
-	// Prepend output_prefix if present
-	finalOutput := output
-	if outputPrefix.Valid && outputPrefix.String != "" {
-		finalOutput = outputPrefix.String + output
-	}
+		// Prepend output_prefix if present, and flag suppressed findings
+		finalOutput := MarkSuppressedFindings(output, policy)
+		if outputPrefix.Valid && outputPrefix.String != "" {
+			finalOutput = outputPrefix.String + finalOutput
+		}
 
-	// Update job status only if still running (not canceled)
-	result, err := conn.ExecContext(ctx, `UPDATE review_jobs SET status = 'done', finished_at = ?, updated_at = ? WHERE id = ? AND status = 'running'`, now, now, jobID)
-	if err != nil {
-		return err
-	}
+		// Update job status only if still running (not canceled)
+		result, err := conn.ExecContext(ctx, `UPDATE review_jobs SET status = 'done', finished_at = ?, updated_at = ?, patch = COALESCE(?, patch) WHERE id = ? AND status = 'running'`, now, now, nullString(suggestedPatch), jobID)
+		if err != nil {
+			return err
+		}
 
-	// Check if we actually updated (job wasn't canceled)
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows == 0 {
-		// Job was canceled or in unexpected state, don't store review
+		// Check if we actually updated (job wasn't canceled)
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			// Job was canceled or in unexpected state, don't store review
+			return nil
+		}
+
+		// Insert review with sync columns. The summary is extracted from the
+		// agent output only, so an output_prefix never becomes the summary.
+		confidence := ParseConfidence(output)
+		verdict, note := policy.applyLowConfidence(ParseVerdictWithPolicy(finalOutput, policy), confidence)
+		finalOutput = note + finalOutput
+		verdictBool := verdictToBool(verdict)
+		severityCounts, err := json.Marshal(CountSeverities(finalOutput, policy))
+		if err != nil {
+			return err
+		}
+		var inputTokens, outputTokens, costUSD any
+		if usage != nil {
+			inputTokens, outputTokens = usage.InputTokens, usage.OutputTokens
+			if usage.CostUSD > 0 {
+				costUSD = usage.CostUSD
+			}
+		}
+		_, err = conn.ExecContext(ctx, `INSERT INTO reviews (job_id, agent, effective_agent, prompt, output, summary, verdict_bool, severity_counts, confidence, input_tokens, output_tokens, cost_usd, uuid, updated_by_machine_id, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			jobID, agent, effectiveAgent, prompt, finalOutput, nullString(ExtractSummary(output)), verdictBool, string(severityCounts), nullString(confidence), inputTokens, outputTokens, costUSD, reviewUUID, machineID, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, "COMMIT")
+		if err != nil {
+			return err
+		}
+		committed = true
 		return nil
-	}
+	})
+}
 
-	// Insert review with sync columns
-	verdictBool := verdictToBool(ParseVerdict(finalOutput))
-	_, err = conn.ExecContext(ctx, `INSERT INTO reviews (job_id, agent, prompt, output, verdict_bool, uuid, updated_by_machine_id, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		jobID, agent, prompt, finalOutput, verdictBool, reviewUUID, machineID, now)
-	if err != nil {
-		return err
+// HashDiff returns the hash stored as a job's diff_hash for a diff
+// reviewed under settings, the repo's prompt settings (guidelines and
+// review template), or "" for an empty diff.
+func HashDiff(diff, settings string) string {
+	if diff == "" {
+		return ""
 	}
+	h := sha256.New()
+	h.Write([]byte(diff))
+	if settings != "" {
+		h.Write([]byte{0})
+		h.Write([]byte(settings))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
 
-	_, err = conn.ExecContext(ctx, "COMMIT")
-	if err != nil {
-		return err
+// FindCachedReview returns the newest finished job with a review of the
+// same diff as job: same repo, diff hash, review type, agent, model,
+// reasoning, and agentic mode.
+// Returns 0 when there is none or job has no diff hash.
+func (db *DB) FindCachedReview(job *ReviewJob) (int64, error) {
+	if job.DiffHash == "" {
+		return 0, nil
 	}
-	committed = true
-	return nil
+	var id int64
+	err := db.QueryRow(`
+		SELECT j.id FROM review_jobs j
+		JOIN reviews rv ON rv.job_id = j.id
+		WHERE j.repo_id = ? AND j.diff_hash = ? AND j.id != ? AND j.status = 'done'
+		  AND COALESCE(j.review_type, '') = ? AND j.agent = ? AND COALESCE(j.model, '') = ?
+		  AND j.reasoning = ? AND j.agentic = ?
+		ORDER BY j.id DESC
+		LIMIT 1
+	`, job.RepoID, job.DiffHash, job.ID, job.ReviewType, job.Agent, job.Model, job.Reasoning, job.Agentic).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+// CompleteJobFromCache completes a running job with a copy of the review
+// of sourceJobID instead of running an agent, recording the source in
+// cached_from_job_id. Token usage is left NULL since no agent ran. Returns
+// false without storing anything if the job is no longer running (e.g.
+// canceled) or the source review is gone.
+func (db *DB) CompleteJobFromCache(jobID, sourceJobID int64) (bool, error) {
+	now := time.Now().Format(time.RFC3339)
+	machineID, _ := db.GetMachineID()
+	reviewUUID := GenerateUUID()
+
+	var completed bool
+	err := retryOnBusy(func() error {
+		completed = false
+		ctx := context.Background()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+			return err
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				if _, err := conn.ExecContext(ctx, "ROLLBACK"); err != nil {
+					log.Printf("jobs CompleteJobFromCache: rollback failed: %v", err)
+				}
+			}
+		}()
+
+		result, err := conn.ExecContext(ctx, `
+			INSERT INTO reviews (job_id, agent, effective_agent, prompt, output, summary, verdict_bool, severity_counts, confidence, uuid, updated_by_machine_id, updated_at)
+			SELECT ?, rv.agent, rv.effective_agent, rv.prompt, rv.output, rv.summary, rv.verdict_bool, rv.severity_counts, rv.confidence, ?, ?, ?
+			FROM reviews rv
+			WHERE rv.job_id = ? AND EXISTS (SELECT 1 FROM review_jobs WHERE id = ? AND status = 'running')`,
+			jobID, reviewUUID, machineID, now, sourceJobID, jobID)
+		if err != nil {
+			return err
+		}
+		if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+			return err
+		}
+		_, err = conn.ExecContext(ctx, `
+			UPDATE review_jobs SET status = 'done', finished_at = ?, updated_at = ?, cached_from_job_id = ?,
+			       patch = (SELECT patch FROM review_jobs WHERE id = ?)
+			WHERE id = ?`, now, now, sourceJobID, sourceJobID, jobID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return err
+		}
+		committed = true
+		completed = true
+		return nil
+	})
+	return completed, err
 }
 
 // FailJob marks a job as failed with an error message.
@@ -1149,7 +1772,7 @@ func (db *DB) CancelJob(jobID int64) error {
 	result, err := db.Exec(`
 		UPDATE review_jobs
 		SET status = 'canceled', finished_at = ?, updated_at = ?
-		WHERE id = ? AND status IN ('queued', 'running')
+		WHERE id = ? AND status IN `+cancellableStatuses+`
 	`, now, now, jobID)
 	if err != nil {
 		return err
@@ -1164,14 +1787,67 @@ func (db *DB) CancelJob(jobID int64) error {
 	return nil
 }
 
-// MarkJobApplied transitions a fix job from done to applied.
-func (db *DB) MarkJobApplied(jobID int64) error {
+// cancellableStatuses is the SQL list of statuses CancelJob and
+// CancelJobsWhere may cancel; keep in sync with JobStatus.Cancellable.
+const cancellableStatuses = `('queued', 'running')`
+
+// CanceledJob identifies a job canceled by CancelJobsWhere.
+type CanceledJob struct {
+	ID int64
+	// Claimed is true when a worker had claimed the job, i.e. it was
+	// running rather than queued when it was canceled.
+	Claimed bool
+}
+
+// CancelJobsWhere atomically cancels every job in repoID (0 = all repos)
+// with the given status and returns the jobs canceled. status must be
+// queued or running; empty cancels both, like CancelJob.
+func (db *DB) CancelJobsWhere(repoID int64, status JobStatus) ([]CanceledJob, error) {
+	if status != "" && !status.Cancellable() {
+		return nil, fmt.Errorf("cannot cancel %s jobs", status)
+	}
+	now := time.Now().Format(time.RFC3339)
+	query := `
+		UPDATE review_jobs
+		SET status = 'canceled', finished_at = ?, updated_at = ?
+		WHERE status IN ` + cancellableStatuses
+	args := []any{now, now}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	if repoID != 0 {
+		query += ` AND repo_id = ?`
+		args = append(args, repoID)
+	}
+	query += ` RETURNING id, worker_id IS NOT NULL`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var canceled []CanceledJob
+	for rows.Next() {
+		var job CanceledJob
+		if err := rows.Scan(&job.ID, &job.Claimed); err != nil {
+			return nil, err
+		}
+		canceled = append(canceled, job)
+	}
+	return canceled, rows.Err()
+}
+
+// MarkJobApplied transitions a fix job from done to applied, recording
+// the commit its patch was committed as (empty if unknown).
+func (db *DB) MarkJobApplied(jobID int64, commitSHA string) error {
 	now := time.Now().Format(time.RFC3339)
 	result, err := db.Exec(`
 		UPDATE review_jobs
-		SET status = 'applied', updated_at = ?
+		SET status = 'applied', applied_commit_sha = ?, updated_at = ?
 		WHERE id = ? AND status = 'done' AND job_type = 'fix'
-	`, now, jobID)
+	`, nullString(commitSHA), now, jobID)
 	if err != nil {
 		return err
 	}
@@ -1207,6 +1883,142 @@ func (db *DB) MarkJobRebased(jobID int64) error {
 	return nil
 }
 
+// GetAppliedFixes returns a repo's applied and rebased fix jobs, newest
+// first.
+func (db *DB) GetAppliedFixes(repoID int64) ([]ReviewJob, error) {
+	jobs, err := db.ListJobs("", "", 0, 0, WithRepoID(repoID), WithJobType(JobTypeFix))
+	if err != nil {
+		return nil, err
+	}
+	applied := jobs[:0]
+	for _, j := range jobs {
+		if j.Status == JobStatusApplied || j.Status == JobStatusRebased {
+			applied = append(applied, j)
+		}
+	}
+	return applied, nil
+}
+
+// ErrJobNotRetryable is returned by EnqueueRetry for a job that is still
+// queued or running, or that is an applied or rebased fix.
+var ErrJobNotRetryable = errors.New("job has not finished")
+
+// EnqueueRetry enqueues a fresh copy of a finished job, linked to it
+// through RetryOfJobID. Unlike ReenqueueJob and RetryJob, the original job
+// and its review are left untouched. Returns sql.ErrNoRows if the job does not exist and
+// ErrJobNotRetryable if it has not finished.
+func (db *DB) EnqueueRetry(jobID int64) (*ReviewJob, error) {
+	opts, err := db.finishedJobOpts(jobID)
+	if err != nil {
+		return nil, err
+	}
+	opts.RetryOfJobID = jobID
+	return db.EnqueueJob(opts)
+}
+
+// ErrNotReviewJob is returned by RerunReview for a job that did not review
+// code, such as a fix or task job.
+var ErrNotReviewJob = errors.New("job is not a code review")
+
+// RerunReview enqueues a fresh review of a finished review job's repo and
+// ref with the same agent, model, and review type, linked to it through
+// SupersedesJobID so the old and new verdicts can be compared. The earlier
+// job and its review are kept. Returns sql.ErrNoRows if the job does not
+// exist, ErrJobNotRetryable if it has not finished, and ErrNotReviewJob if
+// it is not a review.
+func (db *DB) RerunReview(jobID int64) (*ReviewJob, error) {
+	opts, err := db.finishedJobOpts(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job := (ReviewJob{JobType: opts.JobType}); job.IsFixJob() || job.UsesStoredPrompt() {
+		return nil, fmt.Errorf("%w: job %d is a %s job", ErrNotReviewJob, jobID, opts.JobType)
+	}
+	opts.SupersedesJobID = jobID
+	return db.EnqueueJob(opts)
+}
+
+// finishedJobOpts returns the options that enqueue a copy of a finished
+// job. Returns sql.ErrNoRows if the job does not exist and
+// ErrJobNotRetryable if it has not finished.
+func (db *DB) finishedJobOpts(jobID int64) (EnqueueOpts, error) {
+	var (
+		opts                               EnqueueOpts
+		status                             JobStatus
+		commitID, parentJobID              sql.NullInt64
+		branch, model, reviewType, patchID sql.NullString
+		diffContent, prompt, outputPrefix  sql.NullString
+		jobType                            sql.NullString
+		agentic                            int
+	)
+	err := db.QueryRow(`
+		SELECT repo_id, commit_id, git_ref, branch, agent, model, reasoning, status,
+		       job_type, review_type, patch_id, diff_content, prompt, COALESCE(agentic, 0),
+		       output_prefix, parent_job_id
+		FROM review_jobs WHERE id = ?`, jobID).Scan(
+		&opts.RepoID, &commitID, &opts.GitRef, &branch, &opts.Agent, &model, &opts.Reasoning, &status,
+		&jobType, &reviewType, &patchID, &diffContent, &prompt, &agentic,
+		&outputPrefix, &parentJobID)
+	if err != nil {
+		return EnqueueOpts{}, err
+	}
+	if !status.Retryable() {
+		return EnqueueOpts{}, fmt.Errorf("%w: job %d is %s", ErrJobNotRetryable, jobID, status)
+	}
+
+	opts.CommitID = commitID.Int64
+	opts.Branch = branch.String
+	opts.Model = model.String
+	opts.JobType = jobType.String
+	opts.ReviewType = reviewType.String
+	opts.PatchID = patchID.String
+	opts.DiffContent = diffContent.String
+	opts.OutputPrefix = outputPrefix.String
+	opts.ParentJobID = parentJobID.Int64
+	opts.Agentic = agentic != 0
+	// Only stored-prompt jobs carry their prompt over; review jobs rebuild it.
+	if (ReviewJob{JobType: opts.JobType}).UsesStoredPrompt() {
+		opts.Prompt = prompt.String
+	}
+	return opts, nil
+}
+
+// ErrJobNotRequeueable is returned by RequeueJob for a job that is not
+// failed or canceled.
+var ErrJobNotRequeueable = errors.New("job is not failed or canceled")
+
+// RequeueJob resets a failed or canceled job back to queued in place,
+// clearing its worker, start and finish times, and error, and incrementing
+// its attempts. Unlike ReenqueueJob it never touches a done job's review.
+// Returns sql.ErrNoRows if the job does not exist and ErrJobNotRequeueable
+// if it is not failed or canceled.
+func (db *DB) RequeueJob(jobID int64) (*ReviewJob, error) {
+	var rows int64
+	err := retryOnBusy(func() error {
+		result, err := db.Exec(`
+			UPDATE review_jobs
+			SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL,
+			    retry_count = 0, retry_errors = NULL, retry_after = NULL, attempts = attempts + 1
+			WHERE id = ? AND status IN ('failed', 'canceled')`, jobID)
+		if err != nil {
+			return err
+		}
+		rows, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		var status JobStatus
+		if err := db.QueryRow(`SELECT status FROM review_jobs WHERE id = ?`, jobID).Scan(&status); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: job %d is %s", ErrJobNotRequeueable, jobID, status)
+	}
+	return db.GetJobByID(jobID)
+}
+
 // ReenqueueJob resets a completed, failed, or canceled job back to queued status.
 // This allows manual re-running of jobs to get a fresh review.
 // For done jobs, the existing review is deleted to avoid unique constraint violations.
@@ -1230,16 +2042,21 @@ func (db *DB) ReenqueueJob(jobID int64) error {
 		}
 	}()
 
-	// Delete any existing review for this job (for done jobs being rerun)
+	// Delete any existing review for this job (for done jobs being rerun),
+	// and the resolutions of its findings, which the new review renumbers
 	_, err = conn.ExecContext(ctx, `DELETE FROM reviews WHERE job_id = ?`, jobID)
 	if err != nil {
 		return err
 	}
+	_, err = conn.ExecContext(ctx, `DELETE FROM finding_resolutions WHERE job_id = ?`, jobID)
+	if err != nil {
+		return err
+	}
 
 	// Reset job status
 	result, err := conn.ExecContext(ctx, `
 		UPDATE review_jobs
-		SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL, retry_count = 0, patch = NULL
+		SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL, retry_count = 0, retry_errors = NULL, retry_after = NULL, patch = NULL
 		WHERE id = ? AND status IN ('done', 'failed', 'canceled')
 	`, jobID)
 	if err != nil {
@@ -1266,21 +2083,30 @@ func (db *DB) ReenqueueJob(jobID int64) error {
 // preventing a stale/zombie worker from requeuing a reclaimed job.
 // Pass empty workerID to skip the ownership check (for admin/test callers).
 func (db *DB) RetryJob(jobID int64, workerID string, maxRetries int) (bool, error) {
-	var result sql.Result
-	var err error
+	return db.RetryJobWithBackoff(jobID, workerID, maxRetries, "", 0)
+}
+
+// RetryJobWithBackoff is like RetryJob, but also appends errMsg (when
+// non-empty) to the job's retry history and keeps the requeued job from
+// being claimed until delay has passed.
+func (db *DB) RetryJobWithBackoff(jobID int64, workerID string, maxRetries int, errMsg string, delay time.Duration) (bool, error) {
+	var retryAfter any
+	if delay > 0 {
+		retryAfter = time.Now().Add(delay).UTC().Format(time.RFC3339)
+	}
+	query := `
+		UPDATE review_jobs
+		SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL, retry_count = retry_count + 1,
+		    retry_after = ?,
+		    retry_errors = CASE WHEN ? = '' THEN retry_errors ELSE json_insert(COALESCE(retry_errors, '[]'), '$[#]', ?) END
+		WHERE id = ? AND retry_count < ? AND status = 'running'`
+	args := []any{retryAfter, errMsg, errMsg, jobID, maxRetries}
 	if workerID != "" {
-		result, err = db.Exec(`
-			UPDATE review_jobs
-			SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL, retry_count = retry_count + 1
-			WHERE id = ? AND retry_count < ? AND status = 'running' AND worker_id = ?
-		`, jobID, maxRetries, workerID)
-	} else {
-		result, err = db.Exec(`
-			UPDATE review_jobs
-			SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL, retry_count = retry_count + 1
-			WHERE id = ? AND retry_count < ? AND status = 'running'
-		`, jobID, maxRetries)
+		query += ` AND worker_id = ?`
+		args = append(args, workerID)
 	}
+
+	result, err := db.Exec(query, args...)
 	if err != nil {
 		return false, err
 	}
@@ -1293,6 +2119,55 @@ func (db *DB) RetryJob(jobID int64, workerID string, maxRetries int) (bool, erro
 	return rows > 0, nil
 }
 
+// parseRetryErrors decodes a job's retry_errors column, a JSON array of
+// the errors that caused each automatic retry.
+func parseRetryErrors(s sql.NullString) []string {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	var errs []string
+	if err := json.Unmarshal([]byte(s.String), &errs); err != nil {
+		return nil
+	}
+	return errs
+}
+
+// RequeueFailedOptions filters the jobs requeued by RequeueAllFailed.
+type RequeueFailedOptions struct {
+	RepoPath   string    // Only jobs in this repo root (empty = all repos)
+	Since      time.Time // Only jobs that failed at or after this time (zero = any time)
+	MaxRetries int       // Jobs with retry_count >= MaxRetries are permanently failed and skipped
+}
+
+// RequeueAllFailed resets matching failed jobs to queued, incrementing their
+// retry counts. Returns the number of jobs requeued.
+func (db *DB) RequeueAllFailed(opts RequeueFailedOptions) (int, error) {
+	query := `
+		UPDATE review_jobs
+		SET status = 'queued', worker_id = NULL, started_at = NULL, finished_at = NULL, error = NULL, retry_count = retry_count + 1,
+		    retry_after = NULL
+		WHERE status = 'failed' AND retry_count < ?`
+	args := []any{opts.MaxRetries}
+	if opts.RepoPath != "" {
+		query += ` AND repo_id IN (SELECT id FROM repos WHERE root_path = ?)`
+		args = append(args, opts.RepoPath)
+	}
+	if !opts.Since.IsZero() {
+		query += ` AND datetime(finished_at) >= datetime(?)`
+		args = append(args, opts.Since.UTC().Format(time.RFC3339))
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
 // FailoverJob atomically switches a running job to the given backup agent
 // and requeues it. Returns false if the job is not in running state, the
 // worker doesn't own the job, or the backup agent is the same as the
@@ -1338,8 +2213,14 @@ type listJobsOptions struct {
 	branch             string
 	branchIncludeEmpty bool
 	addressed          *bool
+	seen               *bool
 	jobType            string
 	excludeJobType     string
+	agent              string
+	repoID             int64
+	verdict            string
+	machineID          string
+	machineIncludeNone bool
 }
 
 // WithGitRef filters jobs by git ref.
@@ -1366,6 +2247,12 @@ func WithAddressed(addressed bool) ListJobsOption {
 	return func(o *listJobsOptions) { o.addressed = &addressed }
 }
 
+// WithSeen filters jobs to those whose review has (true) or has not
+// (false) been marked seen by a human. Jobs without a review never match.
+func WithSeen(seen bool) ListJobsOption {
+	return func(o *listJobsOptions) { o.seen = &seen }
+}
+
 // WithJobType filters jobs by job_type (e.g. "fix", "review").
 func WithJobType(jobType string) ListJobsOption {
 	return func(o *listJobsOptions) { o.jobType = jobType }
@@ -1376,15 +2263,100 @@ func WithExcludeJobType(jobType string) ListJobsOption {
 	return func(o *listJobsOptions) { o.excludeJobType = jobType }
 }
 
+// WithAgent filters jobs by the agent that ran them.
+func WithAgent(agent string) ListJobsOption {
+	return func(o *listJobsOptions) { o.agent = agent }
+}
+
+// WithRepoID filters jobs by repo ID.
+func WithRepoID(repoID int64) ListJobsOption {
+	return func(o *listJobsOptions) { o.repoID = repoID }
+}
+
+// WithMachineID filters jobs by the machine that created them.
+func WithMachineID(machineID string) ListJobsOption {
+	return func(o *listJobsOptions) { o.machineID = machineID }
+}
+
+// WithMachineIDOrNone filters jobs by the machine that created them,
+// also including jobs with no recorded machine. Pass the local machine ID
+// to list local jobs, since jobs from before sync have none.
+func WithMachineIDOrNone(machineID string) ListJobsOption {
+	return func(o *listJobsOptions) {
+		o.machineID = machineID
+		o.machineIncludeNone = true
+	}
+}
+
+// ListJobsByMachine returns all jobs created by the given machine, newest
+// first.
+func (db *DB) ListJobsByMachine(machineID string) ([]ReviewJob, error) {
+	return db.ListJobs("", "", 0, 0, WithMachineID(machineID))
+}
+
+// GetMachineIDs returns the distinct IDs of the machines that created
+// jobs in the database, sorted.
+func (db *DB) GetMachineIDs() ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT source_machine_id FROM review_jobs WHERE source_machine_id IS NOT NULL AND source_machine_id != '' ORDER BY source_machine_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Verdict filters accepted by WithVerdict and GetJobsByVerdict.
+const (
+	VerdictFilterPass    = "pass"
+	VerdictFilterFail    = "fail"
+	VerdictFilterPending = "pending" // No verdict yet: queued, running, or done without one (e.g. a task job)
+)
+
+// IsVerdictFilter reports whether v is a verdict filter WithVerdict accepts.
+func IsVerdictFilter(v string) bool {
+	return v == VerdictFilterPass || v == VerdictFilterFail || v == VerdictFilterPending
+}
+
+// WithVerdict filters jobs by verdict (VerdictFilterPass, VerdictFilterFail,
+// or VerdictFilterPending), matching ReviewJob.Verdict.
+func WithVerdict(verdict string) ListJobsOption {
+	return func(o *listJobsOptions) { o.verdict = verdict }
+}
+
+// GetJobsByVerdict returns up to limit jobs in a repo with the given
+// verdict filter, newest first. A repoID of 0 means all repos and a limit
+// of 0 means no limit.
+func (db *DB) GetJobsByVerdict(repoID int64, verdict string, limit int) ([]ReviewJob, error) {
+	if !IsVerdictFilter(verdict) {
+		return nil, fmt.Errorf("invalid verdict filter %q (want pass, fail, or pending)", verdict)
+	}
+	opts := []ListJobsOption{WithVerdict(verdict)}
+	if repoID != 0 {
+		opts = append(opts, WithRepoID(repoID))
+	}
+	return db.ListJobs("", "", limit, 0, opts...)
+}
+
 // ListJobs returns jobs with optional status, repo, branch, and addressed filters.
 // addressedFilter: nil = no filter, non-nil bool = filter by addressed state.
 func (db *DB) ListJobs(statusFilter string, repoFilter string, limit, offset int, opts ...ListJobsOption) ([]ReviewJob, error) {
 	query := `
 		SELECT j.id, j.repo_id, j.commit_id, j.git_ref, j.branch, j.agent, j.reasoning, j.status, j.enqueued_at,
 		       j.started_at, j.finished_at, j.worker_id, j.error, j.prompt, j.retry_count,
-		       COALESCE(j.agentic, 0), r.root_path, r.name, c.subject, rv.addressed, rv.output,
-		       j.source_machine_id, j.uuid, j.model, j.job_type, j.review_type, j.patch_id,
-		       j.parent_job_id
+		       COALESCE(j.agentic, 0), r.root_path, r.name, c.subject, rv.id, rv.addressed,
+		       CASE WHEN rv.verdict_bool IS NULL OR COALESCE(rv.summary, '') = '' THEN rv.output END,
+		       rv.summary, rv.verdict_bool, j.source_machine_id, j.uuid, j.model, j.job_type, j.review_type, j.patch_id,
+		       j.parent_job_id, j.retry_of_job_id, j.attempts, j.retry_errors, rv.seen_at, j.range_label,
+		       j.applied_commit_sha, j.range_commits, j.supersedes_job_id, j.diff_hash, j.cached_from_job_id
 		FROM review_jobs j
 		JOIN repos r ON r.id = j.repo_id
 		LEFT JOIN commits c ON c.id = j.commit_id
@@ -1424,6 +2396,13 @@ func (db *DB) ListJobs(statusFilter string, repoFilter string, limit, offset int
 			conditions = append(conditions, "(rv.addressed IS NULL OR rv.addressed = 0)")
 		}
 	}
+	if o.seen != nil {
+		if *o.seen {
+			conditions = append(conditions, "rv.seen_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "(rv.id IS NOT NULL AND rv.seen_at IS NULL)")
+		}
+	}
 	if o.jobType != "" {
 		conditions = append(conditions, "j.job_type = ?")
 		args = append(args, o.jobType)
@@ -1432,6 +2411,36 @@ func (db *DB) ListJobs(statusFilter string, repoFilter string, limit, offset int
 		conditions = append(conditions, "j.job_type != ?")
 		args = append(args, o.excludeJobType)
 	}
+	if o.agent != "" {
+		conditions = append(conditions, "j.agent = ?")
+		args = append(args, o.agent)
+	}
+	if o.repoID != 0 {
+		conditions = append(conditions, "j.repo_id = ?")
+		args = append(args, o.repoID)
+	}
+	if o.machineID != "" {
+		if o.machineIncludeNone {
+			conditions = append(conditions, "(j.source_machine_id = ? OR j.source_machine_id = '' OR j.source_machine_id IS NULL)")
+		} else {
+			conditions = append(conditions, "j.source_machine_id = ?")
+		}
+		args = append(args, o.machineID)
+	}
+	switch o.verdict {
+	case VerdictFilterPass, VerdictFilterFail:
+		if err := db.backfillVerdicts(); err != nil {
+			return nil, fmt.Errorf("backfill legacy verdicts: %w", err)
+		}
+		verdictBool := 0
+		if o.verdict == VerdictFilterPass {
+			verdictBool = 1
+		}
+		conditions = append(conditions, "rv.verdict_bool = ? AND COALESCE(j.job_type, '') != 'task'")
+		args = append(args, verdictBool)
+	case VerdictFilterPending:
+		conditions = append(conditions, "(j.status IN ('queued', 'running') OR (j.status = 'done' AND (rv.id IS NULL OR COALESCE(j.job_type, '') = 'task')))")
+	}
 
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
@@ -1459,21 +2468,26 @@ func (db *DB) ListJobs(statusFilter string, repoFilter string, limit, offset int
 	for rows.Next() {
 		var j ReviewJob
 		var enqueuedAt string
-		var startedAt, finishedAt, workerID, errMsg, prompt, output, sourceMachineID, jobUUID, model, branch, jobTypeStr, reviewTypeStr, patchIDStr sql.NullString
-		var commitID sql.NullInt64
+		var startedAt, finishedAt, workerID, errMsg, prompt, output, summary, sourceMachineID, jobUUID, model, branch, jobTypeStr, reviewTypeStr, patchIDStr sql.NullString
+		var commitID, reviewID sql.NullInt64
 		var commitSubject sql.NullString
-		var addressed sql.NullInt64
+		var addressed, verdictBool sql.NullInt64
 		var agentic int
-		var parentJobID sql.NullInt64
+		var parentJobID, retryOfJobID, supersedesJobID, cachedFromJobID sql.NullInt64
+		var retryErrors, seenAt, rangeLabel, appliedSHA, diffHash sql.NullString
 
 		err := rows.Scan(&j.ID, &j.RepoID, &commitID, &j.GitRef, &branch, &j.Agent, &j.Reasoning, &j.Status, &enqueuedAt,
 			&startedAt, &finishedAt, &workerID, &errMsg, &prompt, &j.RetryCount,
-			&agentic, &j.RepoPath, &j.RepoName, &commitSubject, &addressed, &output,
-			&sourceMachineID, &jobUUID, &model, &jobTypeStr, &reviewTypeStr, &patchIDStr,
-			&parentJobID)
+			&agentic, &j.RepoPath, &j.RepoName, &commitSubject, &reviewID, &addressed, &output, &summary,
+			&verdictBool, &sourceMachineID, &jobUUID, &model, &jobTypeStr, &reviewTypeStr, &patchIDStr,
+			&parentJobID, &retryOfJobID, &j.Attempts, &retryErrors, &seenAt, &rangeLabel,
+			&appliedSHA, &j.RangeCommits, &supersedesJobID, &diffHash, &cachedFromJobID)
 		if err != nil {
 			return nil, err
 		}
+		j.RetryErrors = parseRetryErrors(retryErrors)
+		j.RangeLabel = rangeLabel.String
+		j.AppliedCommitSHA = appliedSHA.String
 
 		if jobUUID.Valid {
 			j.UUID = jobUUID.String
@@ -1524,23 +2538,73 @@ func (db *DB) ListJobs(statusFilter string, repoFilter string, limit, offset int
 		if addressed.Valid {
 			val := addressed.Int64 != 0
 			j.Addressed = &val
+			seen := seenAt.Valid
+			j.Seen = &seen
 		}
 		if parentJobID.Valid {
 			j.ParentJobID = &parentJobID.Int64
 		}
+		if retryOfJobID.Valid {
+			j.RetryOfJobID = &retryOfJobID.Int64
+		}
+		if supersedesJobID.Valid {
+			j.SupersedesJobID = &supersedesJobID.Int64
+		}
+		j.DiffHash = diffHash.String
+		if cachedFromJobID.Valid {
+			j.CachedFromJobID = &cachedFromJobID.Int64
+		}
 		// Compute verdict only for non-task jobs (task jobs don't have PASS/FAIL verdicts)
-		// Task jobs (run, analyze, custom) are identified by having no commit_id and not being dirty
-		if output.Valid && !j.IsTaskJob() {
-			verdict := ParseVerdict(output.String)
+		// Task jobs (run, analyze, custom) are identified by having no commit_id and not being dirty.
+		// output is only selected when the stored verdict or summary is missing.
+		if reviewID.Valid && !j.IsTaskJob() {
+			verdict := verdictFromBoolOrParse(verdictBool, output.String)
 			j.Verdict = &verdict
 		}
+		if reviewID.Valid {
+			j.Summary = summaryOrDerive(summary.String, output.String)
+		}
 
 		jobs = append(jobs, j)
 	}
-
 	return jobs, rows.Err()
 }
 
+// backfillVerdicts stores the parsed verdict of legacy reviews whose
+// verdict_bool is NULL, so verdict filters can match them in SQL.
+func (db *DB) backfillVerdicts() error {
+	rows, err := db.Query(`SELECT id, output FROM reviews WHERE verdict_bool IS NULL`)
+	if err != nil {
+		return err
+	}
+	verdicts := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var output string
+		if err := rows.Scan(&id, &output); err != nil {
+			rows.Close()
+			return err
+		}
+		verdicts[id] = verdictToBool(ParseVerdict(output))
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for id, verdictBool := range verdicts {
+		err := retryOnBusy(func() error {
+			_, err := db.Exec(`UPDATE reviews SET verdict_bool = ? WHERE id = ? AND verdict_bool IS NULL`, verdictBool, id)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetJobByID returns a job by ID with joined fields
 // JobStats holds aggregate counts for the queue status line.
 type JobStats struct {
@@ -1597,15 +2661,16 @@ func (db *DB) GetJobByID(id int64) (*ReviewJob, error) {
 	var commitID sql.NullInt64
 	var commitSubject sql.NullString
 	var agentic int
-	var parentJobID sql.NullInt64
-	var patch sql.NullString
+	var parentJobID, retryOfJobID, supersedesJobID, cachedFromJobID sql.NullInt64
+	var patch, retryErrors, rangeLabel, appliedSHA, diffHash sql.NullString
 
 	var model, branch, jobTypeStr, reviewTypeStr, patchIDStr sql.NullString
 	err := db.QueryRow(`
 		SELECT j.id, j.repo_id, j.commit_id, j.git_ref, j.branch, j.agent, j.reasoning, j.status, j.enqueued_at,
 		       j.started_at, j.finished_at, j.worker_id, j.error, j.prompt, COALESCE(j.agentic, 0),
 		       r.root_path, r.name, c.subject, j.model, j.job_type, j.review_type, j.patch_id,
-		       j.parent_job_id, j.patch
+		       j.parent_job_id, j.retry_of_job_id, j.patch, j.attempts, j.retry_count, j.retry_errors, j.range_label,
+		       j.applied_commit_sha, j.range_commits, j.supersedes_job_id, j.diff_hash, j.cached_from_job_id
 		FROM review_jobs j
 		JOIN repos r ON r.id = j.repo_id
 		LEFT JOIN commits c ON c.id = j.commit_id
@@ -1613,10 +2678,14 @@ func (db *DB) GetJobByID(id int64) (*ReviewJob, error) {
 	`, id).Scan(&j.ID, &j.RepoID, &commitID, &j.GitRef, &branch, &j.Agent, &j.Reasoning, &j.Status, &enqueuedAt,
 		&startedAt, &finishedAt, &workerID, &errMsg, &prompt, &agentic,
 		&j.RepoPath, &j.RepoName, &commitSubject, &model, &jobTypeStr, &reviewTypeStr, &patchIDStr,
-		&parentJobID, &patch)
+		&parentJobID, &retryOfJobID, &patch, &j.Attempts, &j.RetryCount, &retryErrors, &rangeLabel,
+		&appliedSHA, &j.RangeCommits, &supersedesJobID, &diffHash, &cachedFromJobID)
 	if err != nil {
 		return nil, err
 	}
+	j.RetryErrors = parseRetryErrors(retryErrors)
+	j.RangeLabel = rangeLabel.String
+	j.AppliedCommitSHA = appliedSHA.String
 
 	if commitID.Valid {
 		j.CommitID = &commitID.Int64
@@ -1661,6 +2730,16 @@ func (db *DB) GetJobByID(id int64) (*ReviewJob, error) {
 	if parentJobID.Valid {
 		j.ParentJobID = &parentJobID.Int64
 	}
+	if retryOfJobID.Valid {
+		j.RetryOfJobID = &retryOfJobID.Int64
+	}
+	if supersedesJobID.Valid {
+		j.SupersedesJobID = &supersedesJobID.Int64
+	}
+	j.DiffHash = diffHash.String
+	if cachedFromJobID.Valid {
+		j.CachedFromJobID = &cachedFromJobID.Int64
+	}
 	if patch.Valid {
 		j.Patch = &patch.String
 	}
@@ -1703,6 +2782,47 @@ func (db *DB) GetJobCounts() (queued, running, done, failed, canceled, applied,
 	return
 }
 
+// GetQueueDepth returns the number of queued and running jobs.
+func (db *DB) GetQueueDepth() (queued, running int, err error) {
+	err = db.QueryRow(`
+		SELECT
+			COALESCE(SUM(CASE WHEN status = 'queued' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = 'running' THEN 1 ELSE 0 END), 0)
+		FROM review_jobs
+		WHERE status IN ('queued', 'running')
+	`).Scan(&queued, &running)
+	return
+}
+
+// GetQueueDepthByRepo returns queued and running job counts for each repo
+// that has any, ordered by repo name.
+func (db *DB) GetQueueDepthByRepo() ([]RepoQueueDepth, error) {
+	rows, err := db.Query(`
+		SELECT r.id, r.name, r.root_path,
+			SUM(CASE WHEN j.status = 'queued' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN j.status = 'running' THEN 1 ELSE 0 END)
+		FROM review_jobs j
+		JOIN repos r ON r.id = j.repo_id
+		WHERE j.status IN ('queued', 'running')
+		GROUP BY r.id, r.name, r.root_path
+		ORDER BY r.name, r.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var depths []RepoQueueDepth
+	for rows.Next() {
+		var d RepoQueueDepth
+		if err := rows.Scan(&d.RepoID, &d.RepoName, &d.RepoPath, &d.Queued, &d.Running); err != nil {
+			return nil, err
+		}
+		depths = append(depths, d)
+	}
+	return depths, rows.Err()
+}
+
 // UpdateJobBranch sets the branch field for a job that doesn't have one.
 // This is used to backfill the branch when it's derived from git.
 // Only updates if the current branch is NULL or empty.
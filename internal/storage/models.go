@@ -35,6 +35,35 @@ const (
 	JobStatusRebased  JobStatus = "rebased"
 )
 
+// IsValid reports whether s is one of the known job statuses.
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusQueued, JobStatusRunning, JobStatusDone, JobStatusFailed,
+		JobStatusCanceled, JobStatusApplied, JobStatusRebased:
+		return true
+	}
+	return false
+}
+
+// Retryable reports whether a job in status s has finished and may be
+// rerun or retried. Applied and rebased fix jobs are excluded.
+func (s JobStatus) Retryable() bool {
+	return s == JobStatusDone || s == JobStatusFailed || s == JobStatusCanceled
+}
+
+// Requeueable reports whether a job in status s can be reset to queued in
+// place by RequeueJob: only failed and canceled jobs, which have no review
+// to lose.
+func (s JobStatus) Requeueable() bool {
+	return s == JobStatusFailed || s == JobStatusCanceled
+}
+
+// Cancellable reports whether a job in status s can be canceled by
+// CancelJob or CancelJobsWhere: only queued and running jobs.
+func (s JobStatus) Cancellable() bool {
+	return s == JobStatusQueued || s == JobStatusRunning
+}
+
 // JobType classifies what kind of work a review job represents.
 const (
 	JobTypeReview  = "review"  // Single commit review
@@ -63,13 +92,31 @@ type ReviewJob struct {
 	Error        string     `json:"error,omitempty"`
 	Prompt       string     `json:"prompt,omitempty"`
 	RetryCount   int        `json:"retry_count"`
-	DiffContent  *string    `json:"diff_content,omitempty"`  // For dirty reviews (uncommitted changes)
-	Agentic      bool       `json:"agentic"`                 // Enable agentic mode (allow file edits)
-	ReviewType   string     `json:"review_type,omitempty"`   // Review type (e.g., "security") - changes system prompt
-	PatchID      string     `json:"patch_id,omitempty"`      // Stable patch-id for rebase tracking
-	OutputPrefix string     `json:"output_prefix,omitempty"` // Prefix to prepend to review output
-	ParentJobID  *int64     `json:"parent_job_id,omitempty"` // Job being fixed (for fix jobs)
-	Patch        *string    `json:"patch,omitempty"`         // Generated diff patch (for completed fix jobs)
+	RetryErrors  []string   `json:"retry_errors,omitempty"`    // Error behind each automatic retry, oldest first
+	Attempts     int        `json:"attempts,omitempty"`        // Manual requeues via RequeueJob
+	DiffContent  *string    `json:"diff_content,omitempty"`    // For dirty reviews (uncommitted changes)
+	Agentic      bool       `json:"agentic"`                   // Enable agentic mode (allow file edits)
+	ReviewType   string     `json:"review_type,omitempty"`     // Review type (e.g., "security") - changes system prompt
+	PatchID      string     `json:"patch_id,omitempty"`        // Stable patch-id for rebase tracking
+	OutputPrefix string     `json:"output_prefix,omitempty"`   // Prefix to prepend to review output
+	RangeLabel   string     `json:"range_label,omitempty"`     // Range (e.g. "main..HEAD") this commit or range job was enqueued as
+	RangeCommits int        `json:"range_commits,omitempty"`   // Commits in a range job (0 if not recorded)
+	ParentJobID  *int64     `json:"parent_job_id,omitempty"`   // Job being fixed (for fix jobs)
+	RetryOfJobID *int64     `json:"retry_of_job_id,omitempty"` // Job this one retries (set by EnqueueRetry)
+	Patch        *string    `json:"patch,omitempty"`           // Generated diff patch (fix jobs) or patch suggested in review output
+
+	// Review this one re-runs (set by RerunReview), for comparing verdicts
+	SupersedesJobID *int64 `json:"supersedes_job_id,omitempty"`
+
+	// Hash of the reviewed diff (see HashDiff), and the job whose review
+	// of an identical diff was copied into this one instead of running
+	// the agent (a cache hit)
+	DiffHash        string `json:"diff_hash,omitempty"`
+	CachedFromJobID *int64 `json:"cached_from_job_id,omitempty"`
+
+	// Commit an applied fix job's patch was committed as; empty if unknown
+	AppliedCommitSHA string `json:"applied_commit_sha,omitempty"`
+
 	// Sync fields
 	UUID            string     `json:"uuid,omitempty"`              // Globally unique identifier for sync
 	SourceMachineID string     `json:"source_machine_id,omitempty"` // Machine that created this job
@@ -77,11 +124,15 @@ type ReviewJob struct {
 	SyncedAt        *time.Time `json:"synced_at,omitempty"`         // Last sync time
 
 	// Joined fields for convenience
-	RepoPath      string  `json:"repo_path,omitempty"`
-	RepoName      string  `json:"repo_name,omitempty"`
-	CommitSubject string  `json:"commit_subject,omitempty"` // empty for ranges
-	Addressed     *bool   `json:"addressed,omitempty"`      // nil if no review yet
-	Verdict       *string `json:"verdict,omitempty"`        // P/F parsed from review output
+	RepoPath      string     `json:"repo_path,omitempty"`
+	RepoName      string     `json:"repo_name,omitempty"`
+	CommitSubject string     `json:"commit_subject,omitempty"` // empty for ranges
+	CommitAuthor  string     `json:"commit_author,omitempty"`  // set by GetReviewByJobID; empty for ranges
+	CommitDate    *time.Time `json:"commit_date,omitempty"`    // author date, set by GetReviewByJobID
+	Addressed     *bool      `json:"addressed,omitempty"`      // nil if no review yet
+	Seen          *bool      `json:"seen,omitempty"`           // Whether a human has read the review; nil if no review yet
+	Verdict       *string    `json:"verdict,omitempty"`        // P/F parsed from review output
+	Summary       string     `json:"summary,omitempty"`        // Short TL;DR of the review (empty if no review yet)
 }
 
 // IsDirtyJob returns true if this is a dirty review (uncommitted changes).
@@ -144,11 +195,18 @@ type JobWithReview struct {
 }
 
 type Review struct {
-	ID        int64     `json:"id"`
-	JobID     int64     `json:"job_id"`
-	Agent     string    `json:"agent"`
-	Prompt    string    `json:"prompt"`
-	Output    string    `json:"output"`
+	ID      int64  `json:"id"`
+	JobID   int64  `json:"job_id"`
+	Agent   string `json:"agent"`
+	Prompt  string `json:"prompt"`
+	Output  string `json:"output"`
+	Summary string `json:"summary,omitempty"` // Short TL;DR for listings, kept separate from Output
+
+	// EffectiveAgent is the agent that produced the review. It differs from
+	// Agent when the worker fell back to one of fallback_agents, and is
+	// empty for reviews stored before it was recorded (see ProducedBy).
+	EffectiveAgent string `json:"effective_agent,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	Addressed bool      `json:"addressed"`
 
@@ -161,8 +219,82 @@ type Review struct {
 	// Stored verdict: 1=pass, 0=fail, NULL=legacy (not yet backfilled)
 	VerdictBool *int `json:"verdict_bool,omitempty"`
 
+	// Findings per severity when the review was stored; nil for older reviews
+	SeverityCounts *SeverityCounts `json:"severity_counts,omitempty"`
+
+	// Confidence the agent stated (low, medium, high); empty when not stated
+	Confidence string `json:"confidence,omitempty"`
+
+	// RevertedBy is the SHA of a later commit that reverted the reviewed
+	// commit; such reviews are marked addressed automatically
+	RevertedBy string `json:"reverted_by,omitempty"`
+
+	// SeenBy and SeenAt record the first human to read the review (see
+	// MarkReviewSeen); empty and nil while unseen
+	SeenBy string     `json:"seen_by,omitempty"`
+	SeenAt *time.Time `json:"seen_at,omitempty"`
+
+	// ResolvedFindings holds the indexes, into ExtractFindings(Output), of
+	// findings marked resolved (see SetFindingResolved). Filled in by the
+	// review API only.
+	ResolvedFindings []int `json:"resolved_findings,omitempty"`
+
+	// Token usage and cost reported by the agent; nil when not reported
+	InputTokens  *int64   `json:"input_tokens,omitempty"`
+	OutputTokens *int64   `json:"output_tokens,omitempty"`
+	CostUSD      *float64 `json:"cost_usd,omitempty"`
+
 	// Joined fields
 	Job *ReviewJob `json:"job,omitempty"`
+
+	// Matching excerpt, set only by SearchReviews
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// ProducedBy returns the agent that wrote the review: EffectiveAgent, or
+// Agent for reviews stored before it was recorded.
+func (r *Review) ProducedBy() string {
+	if r.EffectiveAgent != "" {
+		return r.EffectiveAgent
+	}
+	return r.Agent
+}
+
+// Verdict returns the review's verdict, "P" or "F": the stored verdict,
+// which reflects the verdict policy and low-confidence handling the review
+// was completed under, or the parsed output for legacy reviews without one.
+// A failing review passes once every finding in it has been resolved.
+func (r *Review) Verdict() string {
+	verdict := ParseVerdict(r.Output)
+	switch {
+	case r.Job != nil && r.Job.Verdict != nil:
+		verdict = *r.Job.Verdict
+	case r.VerdictBool != nil:
+		verdict = "F"
+		if *r.VerdictBool == 1 {
+			verdict = "P"
+		}
+	}
+	if verdict == "F" && len(r.ResolvedFindings) > 0 && r.allFindingsResolved() {
+		return "P"
+	}
+	return verdict
+}
+
+// allFindingsResolved reports whether ResolvedFindings covers every finding
+// in the review output.
+func (r *Review) allFindingsResolved() bool {
+	resolved := make(map[int]bool, len(r.ResolvedFindings))
+	for _, idx := range r.ResolvedFindings {
+		resolved[idx] = true
+	}
+	findings := ExtractFindings(r.Output)
+	for i := range findings {
+		if !resolved[i] {
+			return false
+		}
+	}
+	return len(findings) > 0
 }
 
 type Response struct {
@@ -173,6 +305,12 @@ type Response struct {
 	Response  string    `json:"response"`
 	CreatedAt time.Time `json:"created_at"`
 
+	// ParentResponseID is the comment this one replies to; nil for
+	// top-level comments. Depth is how deeply the reply is nested, set by
+	// GetCommentsForJob (0 for top-level comments).
+	ParentResponseID *int64 `json:"parent_response_id,omitempty"`
+	Depth            int    `json:"depth,omitempty"`
+
 	// Sync fields
 	UUID            string     `json:"uuid,omitempty"`              // Globally unique identifier for sync
 	SourceMachineID string     `json:"source_machine_id,omitempty"` // Machine that created this response
@@ -180,19 +318,32 @@ type Response struct {
 }
 
 type DaemonStatus struct {
-	Version             string `json:"version"`
-	QueuedJobs          int    `json:"queued_jobs"`
-	RunningJobs         int    `json:"running_jobs"`
-	CompletedJobs       int    `json:"completed_jobs"`
-	FailedJobs          int    `json:"failed_jobs"`
-	CanceledJobs        int    `json:"canceled_jobs"`
-	AppliedJobs         int    `json:"applied_jobs"`
-	RebasedJobs         int    `json:"rebased_jobs"`
-	ActiveWorkers       int    `json:"active_workers"`
-	MaxWorkers          int    `json:"max_workers"`
-	MachineID           string `json:"machine_id,omitempty"`            // Local machine ID for remote job detection
-	ConfigReloadedAt    string `json:"config_reloaded_at,omitempty"`    // Last config reload timestamp (RFC3339Nano)
-	ConfigReloadCounter uint64 `json:"config_reload_counter,omitempty"` // Monotonic reload counter (for sub-second detection)
+	Version             string           `json:"version"`
+	QueuedJobs          int              `json:"queued_jobs"`
+	RunningJobs         int              `json:"running_jobs"`
+	CompletedJobs       int              `json:"completed_jobs"`
+	FailedJobs          int              `json:"failed_jobs"`
+	CanceledJobs        int              `json:"canceled_jobs"`
+	AppliedJobs         int              `json:"applied_jobs"`
+	RebasedJobs         int              `json:"rebased_jobs"`
+	ActiveWorkers       int              `json:"active_workers"`
+	MaxWorkers          int              `json:"max_workers"`
+	MachineID           string           `json:"machine_id,omitempty"`            // Local machine ID for remote job detection
+	MachineIDs          []string         `json:"machine_ids,omitempty"`           // Every machine that created jobs, local included (filter choices)
+	ConfigReloadedAt    string           `json:"config_reloaded_at,omitempty"`    // Last config reload timestamp (RFC3339Nano)
+	ConfigReloadCounter uint64           `json:"config_reload_counter,omitempty"` // Monotonic reload counter (for sub-second detection)
+	QueuePausedReason   string           `json:"queue_paused_reason,omitempty"`   // Why claims are paused after an auth failure ("" = not paused)
+	QueuePausedByUser   bool             `json:"queue_paused_by_user,omitempty"`  // The user paused the queue (roborev pause)
+	QueueByRepo         []RepoQueueDepth `json:"queue_by_repo,omitempty"`         // Per-repo queue depth, only with ?by_repo=1
+}
+
+// RepoQueueDepth is the number of queued and running jobs for one repo.
+type RepoQueueDepth struct {
+	RepoID   int64  `json:"repo_id"`
+	RepoName string `json:"repo_name"`
+	RepoPath string `json:"repo_path"`
+	Queued   int    `json:"queued"`
+	Running  int    `json:"running"`
 }
 
 // HealthStatus represents the overall daemon health
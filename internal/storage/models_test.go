@@ -216,3 +216,26 @@ func TestUsesStoredPrompt(t *testing.T) {
 		})
 	}
 }
+
+func TestReviewVerdict(t *testing.T) {
+	failing := "- High: unchecked error"
+	tests := []struct {
+		name   string
+		review storage.Review
+		want   string
+	}{
+		{"stored pass overrides output", storage.Review{Output: failing, Job: &storage.ReviewJob{Verdict: testutil.Ptr("P")}}, "P"},
+		{"verdict_bool without job", storage.Review{Output: failing, VerdictBool: testutil.Ptr(1)}, "P"},
+		{"stored fail", storage.Review{Output: "No issues found.", VerdictBool: testutil.Ptr(0)}, "F"},
+		{"legacy review parses output", storage.Review{Output: failing}, "F"},
+		{"all findings resolved", storage.Review{Output: failing, VerdictBool: testutil.Ptr(0), ResolvedFindings: []int{0}}, "P"},
+		{"some findings unresolved", storage.Review{Output: failing + "\n- Low: typo in comment", VerdictBool: testutil.Ptr(0), ResolvedFindings: []int{1}}, "F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.review.Verdict(); got != tt.want {
+				t.Errorf("Verdict() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
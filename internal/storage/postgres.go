@@ -14,12 +14,12 @@ import (
 )
 
 // PostgreSQL schema version - increment when schema changes
-const pgSchemaVersion = 5
+const pgSchemaVersion = 7
 
 // pgSchemaName is the PostgreSQL schema used to isolate roborev tables
 const pgSchemaName = "roborev"
 
-//go:embed schemas/postgres_v5.sql
+//go:embed schemas/postgres_v7.sql
 var pgSchemaSQL string
 
 // pgSchemaStatements returns the individual DDL statements for schema creation.
@@ -186,6 +186,10 @@ func (p *PgPool) EnsureSchema(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("create patch_id index: %w", err)
 		}
+		_, err = p.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_responses_updated ON responses(updated_at)`)
+		if err != nil {
+			return fmt.Errorf("create responses updated_at index: %w", err)
+		}
 	} else if currentVersion > pgSchemaVersion {
 		return fmt.Errorf("database schema version %d is newer than supported version %d", currentVersion, pgSchemaVersion)
 	} else if currentVersion < pgSchemaVersion {
@@ -250,6 +254,32 @@ func (p *PgPool) EnsureSchema(ctx context.Context) error {
 				return fmt.Errorf("migrate to v5 (add patch_id index): %w", err)
 			}
 		}
+		if currentVersion < 6 {
+			// Migration 5->6: Track edits and deletes on responses
+			_, err = p.pool.Exec(ctx, `ALTER TABLE responses ADD COLUMN IF NOT EXISTS updated_by_machine_id UUID`)
+			if err != nil {
+				return fmt.Errorf("migrate to v6 (add updated_by_machine_id column): %w", err)
+			}
+			_, err = p.pool.Exec(ctx, `ALTER TABLE responses ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()`)
+			if err != nil {
+				return fmt.Errorf("migrate to v6 (add updated_at column): %w", err)
+			}
+			_, err = p.pool.Exec(ctx, `ALTER TABLE responses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE`)
+			if err != nil {
+				return fmt.Errorf("migrate to v6 (add deleted_at column): %w", err)
+			}
+			_, err = p.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_responses_updated ON responses(updated_at)`)
+			if err != nil {
+				return fmt.Errorf("migrate to v6 (add responses updated_at index): %w", err)
+			}
+		}
+		if currentVersion < 7 {
+			// Migration 6->7: Link replies to their parent response
+			_, err = p.pool.Exec(ctx, `ALTER TABLE responses ADD COLUMN IF NOT EXISTS parent_response_uuid UUID`)
+			if err != nil {
+				return fmt.Errorf("migrate to v7 (add parent_response_uuid column): %w", err)
+			}
+		}
 		// Update version
 		_, err = p.pool.Exec(ctx, `INSERT INTO schema_version (version) VALUES ($1) ON CONFLICT (version) DO NOTHING`, pgSchemaVersion)
 		if err != nil {
@@ -529,14 +559,21 @@ func (p *PgPool) UpsertReview(ctx context.Context, r SyncableReview) error {
 	return err
 }
 
-// InsertResponse inserts a response in PostgreSQL (append-only, no updates)
-func (p *PgPool) InsertResponse(ctx context.Context, r SyncableResponse) error {
+// UpsertResponse inserts a response in PostgreSQL, or applies an edit or
+// tombstone to an existing one
+func (p *PgPool) UpsertResponse(ctx context.Context, r SyncableResponse) error {
 	_, err := p.pool.Exec(ctx, `
 		INSERT INTO responses (
-			uuid, job_uuid, responder, response, source_machine_id, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (uuid) DO NOTHING
-	`, r.UUID, r.JobUUID, r.Responder, r.Response, r.SourceMachineID, r.CreatedAt)
+			uuid, job_uuid, parent_response_uuid, responder, response, source_machine_id,
+			updated_by_machine_id, created_at, updated_at, deleted_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), $9)
+		ON CONFLICT (uuid) DO UPDATE SET
+			response = EXCLUDED.response,
+			updated_by_machine_id = EXCLUDED.updated_by_machine_id,
+			updated_at = NOW(),
+			deleted_at = EXCLUDED.deleted_at
+	`, r.UUID, r.JobUUID, nullString(r.ParentResponseUUID), r.Responder, r.Response, r.SourceMachineID,
+		nullString(r.UpdatedByMachineID), r.CreatedAt, r.DeletedAt)
 	return err
 }
 
@@ -720,51 +757,75 @@ func (p *PgPool) PullReviews(ctx context.Context, excludeMachineID string, known
 
 // PulledResponse represents a response pulled from PostgreSQL
 type PulledResponse struct {
-	UUID            string
-	JobUUID         string
-	Responder       string
-	Response        string
-	SourceMachineID string
-	CreatedAt       time.Time
+	UUID               string
+	JobUUID            string
+	Responder          string
+	Response           string
+	SourceMachineID    string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          *time.Time
+	ParentResponseUUID string // Comment this one replies to; empty for top-level comments
 }
 
-// PullResponses fetches responses from PostgreSQL created after the given ID cursor.
-func (p *PgPool) PullResponses(ctx context.Context, excludeMachineID string, afterID int64, limit int) ([]PulledResponse, int64, error) {
+// PullResponses fetches responses from PostgreSQL updated after the given cursor.
+// Edits and deletes bump updated_at, so they are pulled again.
+func (p *PgPool) PullResponses(ctx context.Context, excludeMachineID string, cursor string, limit int) ([]PulledResponse, string, error) {
+	var cursorTime time.Time
+	var cursorID int64
+
+	if cursor != "" {
+		var ts string
+		_, err := fmt.Sscanf(cursor, "%s %d", &ts, &cursorID)
+		if err == nil {
+			cursorTime, _ = time.Parse(time.RFC3339Nano, ts)
+		}
+	}
+
 	rows, err := p.pool.Query(ctx, `
 		SELECT
-			r.uuid, r.job_uuid, r.responder, r.response, r.source_machine_id, r.created_at, r.id
+			r.uuid, r.job_uuid, r.responder, r.response, r.source_machine_id,
+			r.created_at, r.updated_at, r.deleted_at, COALESCE(r.parent_response_uuid::text, ''), r.id
 		FROM responses r
-		WHERE (r.source_machine_id IS NULL OR r.source_machine_id != $1)
-		AND r.id > $2
-		ORDER BY r.id
-		LIMIT $3
-	`, excludeMachineID, afterID, limit)
+		WHERE COALESCE(r.updated_by_machine_id, r.source_machine_id) IS DISTINCT FROM $1
+		AND (r.updated_at > $2 OR (r.updated_at = $2 AND r.id > $3))
+		ORDER BY r.updated_at, r.id
+		LIMIT $4
+	`, excludeMachineID, cursorTime, cursorID, limit)
 	if err != nil {
-		return nil, afterID, fmt.Errorf("query responses: %w", err)
+		return nil, cursor, fmt.Errorf("query responses: %w", err)
 	}
 	defer rows.Close()
 
 	var responses []PulledResponse
-	var lastID = afterID
+	var lastUpdatedAt time.Time
+	var lastID int64
 
 	for rows.Next() {
 		var r PulledResponse
 
 		err := rows.Scan(
-			&r.UUID, &r.JobUUID, &r.Responder, &r.Response, &r.SourceMachineID, &r.CreatedAt, &lastID,
+			&r.UUID, &r.JobUUID, &r.Responder, &r.Response, &r.SourceMachineID,
+			&r.CreatedAt, &r.UpdatedAt, &r.DeletedAt, &r.ParentResponseUUID, &lastID,
 		)
 		if err != nil {
-			return nil, afterID, fmt.Errorf("scan response: %w", err)
+			return nil, cursor, fmt.Errorf("scan response: %w", err)
 		}
 
+		lastUpdatedAt = r.UpdatedAt
 		responses = append(responses, r)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, afterID, fmt.Errorf("rows error: %w", err)
+		return nil, cursor, fmt.Errorf("rows error: %w", err)
 	}
 
-	return responses, lastID, nil
+	newCursor := cursor
+	if len(responses) > 0 {
+		newCursor = fmt.Sprintf("%s %d", lastUpdatedAt.Format(time.RFC3339Nano), lastID)
+	}
+
+	return responses, newCursor, nil
 }
 
 // nullString returns nil if s is empty, otherwise returns s
@@ -825,7 +886,8 @@ func (p *PgPool) BatchUpsertReviews(ctx context.Context, reviews []SyncableRevie
 	return success, firstErr
 }
 
-// BatchInsertResponses inserts multiple responses in a single batch operation.
+// BatchInsertResponses inserts multiple responses in a single batch operation,
+// applying edits and tombstones to responses that already exist.
 // Returns a boolean slice indicating success/failure for each item at the corresponding index.
 func (p *PgPool) BatchInsertResponses(ctx context.Context, responses []SyncableResponse) ([]bool, error) {
 	if len(responses) == 0 {
@@ -836,10 +898,16 @@ func (p *PgPool) BatchInsertResponses(ctx context.Context, responses []SyncableR
 	for _, r := range responses {
 		batch.Queue(`
 			INSERT INTO responses (
-				uuid, job_uuid, responder, response, source_machine_id, created_at
-			) VALUES ($1, $2, $3, $4, $5, $6)
-			ON CONFLICT (uuid) DO NOTHING
-		`, r.UUID, r.JobUUID, r.Responder, r.Response, r.SourceMachineID, r.CreatedAt)
+				uuid, job_uuid, parent_response_uuid, responder, response, source_machine_id,
+				updated_by_machine_id, created_at, updated_at, deleted_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), $9)
+			ON CONFLICT (uuid) DO UPDATE SET
+				response = EXCLUDED.response,
+				updated_by_machine_id = EXCLUDED.updated_by_machine_id,
+				updated_at = NOW(),
+				deleted_at = EXCLUDED.deleted_at
+		`, r.UUID, r.JobUUID, nullString(r.ParentResponseUUID), r.Responder, r.Response, r.SourceMachineID,
+			nullString(r.UpdatedByMachineID), r.CreatedAt, r.DeletedAt)
 	}
 
 	br := p.pool.SendBatch(ctx, batch)
@@ -500,6 +500,16 @@ func (db *DB) DeleteRepo(repoID int64, cascade bool) error {
 			return err
 		}
 
+		// 2b. Delete finding resolutions for jobs in this repo
+		_, err = conn.ExecContext(ctx, `
+			DELETE FROM finding_resolutions WHERE job_id IN (
+				SELECT id FROM review_jobs WHERE repo_id = ?
+			)
+		`, repoID)
+		if err != nil {
+			return err
+		}
+
 		// 3. Delete jobs for this repo
 		_, err = conn.ExecContext(ctx, `DELETE FROM review_jobs WHERE repo_id = ?`, repoID)
 		if err != nil {
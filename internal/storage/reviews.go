@@ -2,7 +2,9 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
@@ -16,27 +18,37 @@ func (db *DB) GetReviewByJobID(jobID int64) (*Review, error) {
 	var enqueuedAt string
 	var startedAt, finishedAt, workerID, errMsg, reviewUUID, model, jobTypeStr, reviewTypeStr, patchIDStr sql.NullString
 	var commitID sql.NullInt64
-	var commitSubject sql.NullString
+	var commitSubject, commitAuthor, commitDate, summary, confidence, revertedBy, seenBy, seenAt, severityCounts, effectiveAgent sql.NullString
 
-	var verdictBool sql.NullInt64
+	var verdictBool, inputTokens, outputTokens, cachedFromJobID sql.NullInt64
+	var costUSD sql.NullFloat64
 	err := db.QueryRow(`
-		SELECT rv.id, rv.job_id, rv.agent, rv.prompt, rv.output, rv.created_at, rv.addressed, rv.uuid, rv.verdict_bool,
+		SELECT rv.id, rv.job_id, rv.agent, rv.prompt, rv.output, rv.summary, rv.created_at, rv.addressed, rv.uuid, rv.verdict_bool, rv.confidence, rv.reverted_by, rv.input_tokens, rv.output_tokens, rv.cost_usd, rv.seen_by, rv.seen_at, rv.severity_counts, rv.effective_agent,
 		       j.id, j.repo_id, j.commit_id, j.git_ref, j.agent, j.reasoning, j.status, j.enqueued_at,
 		       j.started_at, j.finished_at, j.worker_id, j.error, j.model, j.job_type, j.review_type, j.patch_id,
-		       rp.root_path, rp.name, c.subject
+		       j.cached_from_job_id, rp.root_path, rp.name, c.subject, c.author, c.timestamp
 		FROM reviews rv
 		JOIN review_jobs j ON j.id = rv.job_id
 		JOIN repos rp ON rp.id = j.repo_id
 		LEFT JOIN commits c ON c.id = j.commit_id
 		WHERE rv.job_id = ?
-	`, jobID).Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &createdAt, &addressed, &reviewUUID, &verdictBool,
+	`, jobID).Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &summary, &createdAt, &addressed, &reviewUUID, &verdictBool, &confidence, &revertedBy, &inputTokens, &outputTokens, &costUSD, &seenBy, &seenAt, &severityCounts, &effectiveAgent,
 		&job.ID, &job.RepoID, &commitID, &job.GitRef, &job.Agent, &job.Reasoning, &job.Status, &enqueuedAt,
 		&startedAt, &finishedAt, &workerID, &errMsg, &model, &jobTypeStr, &reviewTypeStr, &patchIDStr,
-		&job.RepoPath, &job.RepoName, &commitSubject)
+		&cachedFromJobID, &job.RepoPath, &job.RepoName, &commitSubject, &commitAuthor, &commitDate)
 	if err != nil {
 		return nil, err
 	}
 	r.Addressed = addressed != 0
+	r.Summary = summaryOrDerive(summary.String, r.Output)
+	r.EffectiveAgent = effectiveAgent.String
+	r.Confidence = confidence.String
+	r.RevertedBy = revertedBy.String
+	r.SeenBy = seenBy.String
+	if seenAt.Valid {
+		t := parseSQLiteTime(seenAt.String)
+		r.SeenAt = &t
+	}
 	if reviewUUID.Valid {
 		r.UUID = reviewUUID.String
 	}
@@ -48,6 +60,11 @@ func (db *DB) GetReviewByJobID(jobID int64) (*Review, error) {
 	if commitSubject.Valid {
 		job.CommitSubject = commitSubject.String
 	}
+	job.CommitAuthor = commitAuthor.String
+	if commitDate.Valid {
+		t := parseSQLiteTime(commitDate.String)
+		job.CommitDate = &t
+	}
 	if model.Valid {
 		job.Model = model.String
 	}
@@ -60,6 +77,9 @@ func (db *DB) GetReviewByJobID(jobID int64) (*Review, error) {
 	if patchIDStr.Valid {
 		job.PatchID = patchIDStr.String
 	}
+	if cachedFromJobID.Valid {
+		job.CachedFromJobID = &cachedFromJobID.Int64
+	}
 	job.EnqueuedAt = parseSQLiteTime(enqueuedAt)
 	if startedAt.Valid {
 		t := parseSQLiteTime(startedAt.String)
@@ -85,14 +105,130 @@ func (db *DB) GetReviewByJobID(jobID int64) (*Review, error) {
 		v := int(verdictBool.Int64)
 		r.VerdictBool = &v
 	}
+	r.SeverityCounts = parseSeverityCounts(severityCounts)
+	if inputTokens.Valid {
+		r.InputTokens = &inputTokens.Int64
+	}
+	if outputTokens.Valid {
+		r.OutputTokens = &outputTokens.Int64
+	}
+	if costUSD.Valid {
+		r.CostUSD = &costUSD.Float64
+	}
 
 	r.Job = &job
 
 	return &r, nil
 }
 
-// GetReviewByCommitSHA finds the most recent review by commit SHA (searches git_ref field)
+// ErrAmbiguousSHA is returned by GetReviewByCommitSHA and ResolveJobSHA
+// when a SHA prefix matches more than one commit. The error message lists
+// the candidates.
+var ErrAmbiguousSHA = errors.New("ambiguous SHA")
+
+// minSHAPrefixLen is the shortest SHA prefix GetReviewByCommitSHA expands,
+// matching git's minimum abbreviation.
+const minSHAPrefixLen = 4
+
+// maxSHACandidates caps how many candidates an ErrAmbiguousSHA lists.
+const maxSHACandidates = 10
+
+// GetReviewByCommitSHA finds the most recent review by commit SHA (searches
+// git_ref field). A full SHA is matched exactly; if nothing matches and sha
+// is a hex prefix of at least minSHAPrefixLen characters, it is expanded
+// to the single reviewed commit it prefixes. A prefix of several commits
+// returns an error wrapping ErrAmbiguousSHA.
 func (db *DB) GetReviewByCommitSHA(sha string) (*Review, error) {
+	r, err := db.getReviewByExactSHA(sha)
+	if !errors.Is(err, sql.ErrNoRows) || !IsSHAPrefix(sha) {
+		return r, err
+	}
+
+	full, err := db.expandSHAPrefix(sha, `
+		SELECT DISTINCT j.git_ref
+		FROM reviews rv
+		JOIN review_jobs j ON j.id = rv.job_id
+		WHERE j.git_ref LIKE ? AND j.git_ref NOT LIKE '%..%'
+		ORDER BY j.git_ref
+		LIMIT ?`)
+	if err != nil {
+		return nil, err
+	}
+	return db.getReviewByExactSHA(full)
+}
+
+// ResolveJobSHA expands a SHA prefix to the full SHA of the single commit
+// with a job, reviewed or not, so a job can be found before it finishes.
+// A ref that isn't a SHA prefix, or that some job matches exactly, is
+// returned as is. Returns sql.ErrNoRows if no job's commit matches, and an
+// error wrapping ErrAmbiguousSHA for a prefix of several commits.
+func (db *DB) ResolveJobSHA(sha string) (string, error) {
+	if !IsSHAPrefix(sha) {
+		return sha, nil
+	}
+	var exists int
+	err := db.QueryRow(`SELECT 1 FROM review_jobs WHERE git_ref = ? LIMIT 1`, sha).Scan(&exists)
+	if err == nil {
+		return sha, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return db.expandSHAPrefix(sha, `
+		SELECT DISTINCT git_ref
+		FROM review_jobs
+		WHERE git_ref LIKE ? AND git_ref NOT LIKE '%..%'
+		ORDER BY git_ref
+		LIMIT ?`)
+}
+
+// expandSHAPrefix runs query, which selects the distinct git_refs matching
+// a LIKE pattern up to a limit, and returns the single match for prefix.
+func (db *DB) expandSHAPrefix(prefix, query string) (string, error) {
+	rows, err := db.Query(query, strings.ToLower(prefix)+"%", maxSHACandidates+1)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var candidates []string
+	for rows.Next() {
+		var ref string
+		if err := rows.Scan(&ref); err != nil {
+			return "", err
+		}
+		candidates = append(candidates, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", sql.ErrNoRows
+	case 1:
+		return candidates[0], nil
+	}
+	list := strings.Join(candidates[:min(len(candidates), maxSHACandidates)], ", ")
+	if len(candidates) > maxSHACandidates {
+		list += ", ..."
+	}
+	return "", fmt.Errorf("%w: %s matches %s", ErrAmbiguousSHA, prefix, list)
+}
+
+// IsSHAPrefix reports whether s could be an abbreviated commit SHA.
+func IsSHAPrefix(s string) bool {
+	if len(s) < minSHAPrefixLen || len(s) >= 40 {
+		return false
+	}
+	for _, c := range strings.ToLower(s) {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (db *DB) getReviewByExactSHA(sha string) (*Review, error) {
 	var r Review
 	var createdAt string
 	var addressed int
@@ -100,12 +236,13 @@ func (db *DB) GetReviewByCommitSHA(sha string) (*Review, error) {
 	var enqueuedAt string
 	var startedAt, finishedAt, workerID, errMsg, reviewUUID, model, jobTypeStr, reviewTypeStr, patchIDStr sql.NullString
 	var commitID sql.NullInt64
-	var commitSubject sql.NullString
+	var commitSubject, summary, confidence, revertedBy, seenBy, seenAt, severityCounts, effectiveAgent sql.NullString
 
 	// Search by git_ref which contains the SHA for single commits
-	var verdictBool sql.NullInt64
+	var verdictBool, inputTokens, outputTokens sql.NullInt64
+	var costUSD sql.NullFloat64
 	err := db.QueryRow(`
-		SELECT rv.id, rv.job_id, rv.agent, rv.prompt, rv.output, rv.created_at, rv.addressed, rv.uuid, rv.verdict_bool,
+		SELECT rv.id, rv.job_id, rv.agent, rv.prompt, rv.output, rv.summary, rv.created_at, rv.addressed, rv.uuid, rv.verdict_bool, rv.confidence, rv.reverted_by, rv.input_tokens, rv.output_tokens, rv.cost_usd, rv.seen_by, rv.seen_at, rv.severity_counts, rv.effective_agent,
 		       j.id, j.repo_id, j.commit_id, j.git_ref, j.agent, j.reasoning, j.status, j.enqueued_at,
 		       j.started_at, j.finished_at, j.worker_id, j.error, j.model, j.job_type, j.review_type, j.patch_id,
 		       rp.root_path, rp.name, c.subject
@@ -116,7 +253,7 @@ func (db *DB) GetReviewByCommitSHA(sha string) (*Review, error) {
 		WHERE j.git_ref = ?
 		ORDER BY rv.created_at DESC
 		LIMIT 1
-	`, sha).Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &createdAt, &addressed, &reviewUUID, &verdictBool,
+	`, sha).Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &summary, &createdAt, &addressed, &reviewUUID, &verdictBool, &confidence, &revertedBy, &inputTokens, &outputTokens, &costUSD, &seenBy, &seenAt, &severityCounts, &effectiveAgent,
 		&job.ID, &job.RepoID, &commitID, &job.GitRef, &job.Agent, &job.Reasoning, &job.Status, &enqueuedAt,
 		&startedAt, &finishedAt, &workerID, &errMsg, &model, &jobTypeStr, &reviewTypeStr, &patchIDStr,
 		&job.RepoPath, &job.RepoName, &commitSubject)
@@ -124,6 +261,15 @@ func (db *DB) GetReviewByCommitSHA(sha string) (*Review, error) {
 		return nil, err
 	}
 	r.Addressed = addressed != 0
+	r.Summary = summaryOrDerive(summary.String, r.Output)
+	r.EffectiveAgent = effectiveAgent.String
+	r.Confidence = confidence.String
+	r.RevertedBy = revertedBy.String
+	r.SeenBy = seenBy.String
+	if seenAt.Valid {
+		t := parseSQLiteTime(seenAt.String)
+		r.SeenAt = &t
+	}
 	if reviewUUID.Valid {
 		r.UUID = reviewUUID.String
 	}
@@ -173,6 +319,16 @@ func (db *DB) GetReviewByCommitSHA(sha string) (*Review, error) {
 		v := int(verdictBool.Int64)
 		r.VerdictBool = &v
 	}
+	r.SeverityCounts = parseSeverityCounts(severityCounts)
+	if inputTokens.Valid {
+		r.InputTokens = &inputTokens.Int64
+	}
+	if outputTokens.Valid {
+		r.OutputTokens = &outputTokens.Int64
+	}
+	if costUSD.Valid {
+		r.CostUSD = &costUSD.Float64
+	}
 
 	r.Job = &job
 
@@ -182,7 +338,7 @@ func (db *DB) GetReviewByCommitSHA(sha string) (*Review, error) {
 // GetAllReviewsForGitRef returns all reviews for a git ref (commit SHA or range) for re-review context
 func (db *DB) GetAllReviewsForGitRef(gitRef string) ([]Review, error) {
 	rows, err := db.Query(`
-		SELECT rv.id, rv.job_id, rv.agent, rv.prompt, rv.output, rv.created_at, rv.addressed
+		SELECT rv.id, rv.job_id, rv.agent, rv.effective_agent, rv.prompt, rv.output, rv.created_at, rv.addressed
 		FROM reviews rv
 		JOIN review_jobs j ON j.id = rv.job_id
 		WHERE j.git_ref = ?
@@ -198,9 +354,11 @@ func (db *DB) GetAllReviewsForGitRef(gitRef string) ([]Review, error) {
 		var r Review
 		var createdAt string
 		var addressed int
-		if err := rows.Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &createdAt, &addressed); err != nil {
+		var effectiveAgent sql.NullString
+		if err := rows.Scan(&r.ID, &r.JobID, &r.Agent, &effectiveAgent, &r.Prompt, &r.Output, &createdAt, &addressed); err != nil {
 			return nil, err
 		}
+		r.EffectiveAgent = effectiveAgent.String
 		r.CreatedAt = parseSQLiteTime(createdAt)
 		r.Addressed = addressed != 0
 		reviews = append(reviews, r)
@@ -212,7 +370,7 @@ func (db *DB) GetAllReviewsForGitRef(gitRef string) ([]Review, error) {
 // GetRecentReviewsForRepo returns the N most recent reviews for a repo
 func (db *DB) GetRecentReviewsForRepo(repoID int64, limit int) ([]Review, error) {
 	rows, err := db.Query(`
-		SELECT rv.id, rv.job_id, rv.agent, rv.prompt, rv.output, rv.created_at, rv.addressed
+		SELECT rv.id, rv.job_id, rv.agent, rv.effective_agent, rv.prompt, rv.output, rv.created_at, rv.addressed
 		FROM reviews rv
 		JOIN review_jobs j ON j.id = rv.job_id
 		WHERE j.repo_id = ?
@@ -229,9 +387,11 @@ func (db *DB) GetRecentReviewsForRepo(repoID int64, limit int) ([]Review, error)
 		var r Review
 		var createdAt string
 		var addressed int
-		if err := rows.Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &createdAt, &addressed); err != nil {
+		var effectiveAgent sql.NullString
+		if err := rows.Scan(&r.ID, &r.JobID, &r.Agent, &effectiveAgent, &r.Prompt, &r.Output, &createdAt, &addressed); err != nil {
 			return nil, err
 		}
+		r.EffectiveAgent = effectiveAgent.String
 		r.CreatedAt = parseSQLiteTime(createdAt)
 		r.Addressed = addressed != 0
 		reviews = append(reviews, r)
@@ -286,6 +446,104 @@ func (db *DB) MarkReviewAddressedByJobID(jobID int64, addressed bool) error {
 	return nil
 }
 
+// MarkReviewSeen records that who, a human, has read the review for jobID.
+// The first reader is kept: marking an already seen review again leaves
+// its seen_by and seen_at unchanged. Returns sql.ErrNoRows if the job has
+// no review.
+func (db *DB) MarkReviewSeen(jobID int64, who string) error {
+	now := time.Now().Format(time.RFC3339)
+	machineID, _ := db.GetMachineID()
+
+	result, err := db.Exec(`
+		UPDATE reviews SET seen_by = ?, seen_at = ?, updated_by_machine_id = ?, updated_at = ?
+		WHERE job_id = ? AND seen_at IS NULL`, who, now, machineID, now, jobID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		var exists int
+		if err := db.QueryRow(`SELECT 1 FROM reviews WHERE job_id = ?`, jobID).Scan(&exists); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarkReviewsReverted records that revertingSHA reverted revertedSHA in
+// repoID: reviews of revertedSHA are marked addressed and linked to the
+// reverting commit through reverted_by. Returns the number of reviews
+// updated, which is zero when revertedSHA was never reviewed.
+func (db *DB) MarkReviewsReverted(repoID int64, revertedSHA, revertingSHA string) (int, error) {
+	now := time.Now().Format(time.RFC3339)
+	machineID, _ := db.GetMachineID()
+
+	var updated int64
+	err := retryOnBusy(func() error {
+		result, err := db.Exec(`
+			UPDATE reviews SET addressed = 1, reverted_by = ?, updated_by_machine_id = ?, updated_at = ?
+			WHERE job_id IN (SELECT id FROM review_jobs WHERE repo_id = ? AND git_ref = ?)`,
+			revertingSHA, machineID, now, repoID, revertedSHA)
+		if err != nil {
+			return err
+		}
+		updated, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("mark reviews reverted: %w", err)
+	}
+	return int(updated), nil
+}
+
+// GetComparisonGroup returns the completed reviews in jobID's comparison
+// group: review or range jobs in the same repo with the same git ref, job
+// type, and review type, typically run by different agents. The group
+// includes jobID's own review and is ordered by job ID. Returns
+// sql.ErrNoRows if jobID has no review or is not a commit or range review.
+func (db *DB) GetComparisonGroup(jobID int64) ([]Review, error) {
+	rows, err := db.Query(`
+		SELECT s.id
+		FROM review_jobs j
+		JOIN review_jobs s ON s.repo_id = j.repo_id AND s.git_ref = j.git_ref
+		                  AND s.job_type = j.job_type AND s.review_type = j.review_type
+		JOIN reviews rv ON rv.job_id = s.id
+		WHERE j.id = ? AND j.job_type IN ('review', 'range') AND s.status = 'done'
+		ORDER BY s.id`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(ids, jobID) {
+		return nil, sql.ErrNoRows
+	}
+
+	group := make([]Review, 0, len(ids))
+	for _, id := range ids {
+		review, err := db.GetReviewByJobID(id)
+		if err != nil {
+			return nil, fmt.Errorf("load review for job %d: %w", id, err)
+		}
+		group = append(group, *review)
+	}
+	return group, nil
+}
+
 // GetJobsWithReviewsByIDs fetches jobs and their reviews in batch for the given job IDs.
 // Returns a map of job ID to JobWithReview. Jobs without reviews are included with a nil Review.
 func (db *DB) GetJobsWithReviewsByIDs(jobIDs []int64) (map[int64]JobWithReview, error) {
@@ -382,7 +640,7 @@ func (db *DB) GetJobsWithReviewsByIDs(jobIDs []int64) (map[int64]JobWithReview,
 
 	// Fetch reviews for these jobs
 	reviewQuery := fmt.Sprintf(`
-		SELECT rv.id, rv.job_id, rv.agent, rv.prompt, rv.output, rv.created_at, rv.addressed, rv.verdict_bool
+		SELECT rv.id, rv.job_id, rv.agent, rv.effective_agent, rv.prompt, rv.output, rv.summary, rv.created_at, rv.addressed, rv.verdict_bool
 		FROM reviews rv
 		WHERE rv.job_id IN (%s)
 	`, inClause)
@@ -398,11 +656,14 @@ func (db *DB) GetJobsWithReviewsByIDs(jobIDs []int64) (map[int64]JobWithReview,
 		var createdAt string
 		var addressed int
 		var verdictBool sql.NullInt64
-		if err := reviewRows.Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &createdAt, &addressed, &verdictBool); err != nil {
+		var summary, effectiveAgent sql.NullString
+		if err := reviewRows.Scan(&r.ID, &r.JobID, &r.Agent, &effectiveAgent, &r.Prompt, &r.Output, &summary, &createdAt, &addressed, &verdictBool); err != nil {
 			return nil, fmt.Errorf("scan review: %w", err)
 		}
+		r.EffectiveAgent = effectiveAgent.String
 		r.CreatedAt = parseSQLiteTime(createdAt)
 		r.Addressed = addressed != 0
+		r.Summary = summaryOrDerive(summary.String, r.Output)
 		if verdictBool.Valid {
 			v := int(verdictBool.Int64)
 			r.VerdictBool = &v
@@ -415,6 +676,7 @@ func (db *DB) GetJobsWithReviewsByIDs(jobIDs []int64) (map[int64]JobWithReview,
 				verdict := verdictFromBoolOrParse(verdictBool, r.Output)
 				entry.Job.Verdict = &verdict
 			}
+			entry.Job.Summary = r.Summary
 			result[r.JobID] = entry
 		}
 	}
@@ -425,21 +687,40 @@ func (db *DB) GetJobsWithReviewsByIDs(jobIDs []int64) (map[int64]JobWithReview,
 	return result, nil
 }
 
+// GetJobsWithReviewsOrdered is like GetJobsWithReviewsByIDs but returns the
+// results in the order of jobIDs. IDs with no matching job are skipped.
+func (db *DB) GetJobsWithReviewsOrdered(jobIDs []int64) ([]JobWithReview, error) {
+	byID, err := db.GetJobsWithReviewsByIDs(jobIDs)
+	if err != nil || len(byID) == 0 {
+		return nil, err
+	}
+
+	ordered := make([]JobWithReview, 0, len(byID))
+	for _, id := range jobIDs {
+		if jr, ok := byID[id]; ok {
+			ordered = append(ordered, jr)
+		}
+	}
+	return ordered, nil
+}
+
 // GetReviewByID finds a review by its ID
 func (db *DB) GetReviewByID(reviewID int64) (*Review, error) {
 	var r Review
 	var createdAt string
 	var addressed int
+	var summary sql.NullString
 
 	err := db.QueryRow(`
-		SELECT id, job_id, agent, prompt, output, created_at, addressed
+		SELECT id, job_id, agent, prompt, output, summary, created_at, addressed
 		FROM reviews WHERE id = ?
-	`, reviewID).Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &createdAt, &addressed)
+	`, reviewID).Scan(&r.ID, &r.JobID, &r.Agent, &r.Prompt, &r.Output, &summary, &createdAt, &addressed)
 	if err != nil {
 		return nil, err
 	}
 	r.CreatedAt = parseSQLiteTime(createdAt)
 	r.Addressed = addressed != 0
+	r.Summary = summaryOrDerive(summary.String, r.Output)
 
 	return &r, nil
 }
@@ -486,8 +767,12 @@ func (db *DB) AddCommentToJob(jobID int64, responder, response string) (*Respons
 	now := time.Now()
 	nowStr := now.Format(time.RFC3339)
 
-	result, err := db.Exec(`INSERT INTO responses (job_id, responder, response, uuid, source_machine_id, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
-		jobID, responder, response, uuid, machineID, nowStr)
+	var result sql.Result
+	err = retryOnBusy(func() error {
+		result, err = db.Exec(`INSERT INTO responses (job_id, responder, response, uuid, source_machine_id, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			jobID, responder, response, uuid, machineID, nowStr)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -504,12 +789,180 @@ func (db *DB) AddCommentToJob(jobID int64, responder, response string) (*Respons
 	}, nil
 }
 
+// ErrReplyParentMismatch is returned by AddReplyToJob when the parent
+// comment belongs to another job, or to a commit rather than a job.
+var ErrReplyParentMismatch = errors.New("parent comment does not belong to this job")
+
+// ReplyToComment adds a reply to a comment, linked to the parent's job.
+// Returns sql.ErrNoRows if the parent comment does not exist.
+func (db *DB) ReplyToComment(parentResponseID int64, responder, text string) (*Response, error) {
+	return db.AddReplyToJob(0, parentResponseID, responder, text)
+}
+
+// AddReply is ReplyToComment under the name callers used first.
+func (db *DB) AddReply(parentResponseID int64, responder, text string) (*Response, error) {
+	return db.ReplyToComment(parentResponseID, responder, text)
+}
+
+// AddReplyToJob is ReplyToComment that also checks the parent belongs to
+// jobID. A zero jobID accepts the parent's job, which must be set: legacy
+// commit-linked comments can't be replied to.
+func (db *DB) AddReplyToJob(jobID, parentResponseID int64, responder, text string) (*Response, error) {
+	var parentJobID sql.NullInt64
+	if err := db.QueryRow(`SELECT job_id FROM responses WHERE id = ? AND deleted_at IS NULL`, parentResponseID).Scan(&parentJobID); err != nil {
+		return nil, err
+	}
+	if !parentJobID.Valid || (jobID != 0 && parentJobID.Int64 != jobID) {
+		return nil, ErrReplyParentMismatch
+	}
+	jobID = parentJobID.Int64
+
+	uuid := GenerateUUID()
+	machineID, _ := db.GetMachineID()
+	now := time.Now()
+	nowStr := now.Format(time.RFC3339)
+
+	var result sql.Result
+	err := retryOnBusy(func() error {
+		var err error
+		result, err = db.Exec(`INSERT INTO responses (job_id, parent_response_id, responder, response, uuid, source_machine_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			jobID, parentResponseID, responder, text, uuid, machineID, nowStr)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := result.LastInsertId()
+	return &Response{
+		ID:               id,
+		JobID:            &jobID,
+		Responder:        responder,
+		Response:         text,
+		CreatedAt:        now,
+		ParentResponseID: &parentResponseID,
+		UUID:             uuid,
+		SourceMachineID:  machineID,
+	}, nil
+}
+
+// ErrNotCommentAuthor is returned by UpdateComment when the responder is not
+// the one who wrote the comment.
+var ErrNotCommentAuthor = errors.New("only the original responder can edit a comment")
+
+// UpdateComment replaces the text of a comment. Only the comment's original
+// responder may edit it. Returns sql.ErrNoRows if the comment does not exist.
+// Clearing synced_at queues the edit for the next sync push.
+func (db *DB) UpdateComment(id int64, responder, newText string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	var result sql.Result
+	err := retryOnBusy(func() error {
+		var err error
+		result, err = db.Exec(`
+			UPDATE responses SET response = ?, updated_at = ?, synced_at = NULL
+			WHERE id = ? AND responder = ? AND deleted_at IS NULL`, newText, now, id, responder)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	// Nothing updated: distinguish a missing comment from someone else's
+	var author string
+	if err := db.QueryRow(`SELECT responder FROM responses WHERE id = ? AND deleted_at IS NULL`, id).Scan(&author); err != nil {
+		return err
+	}
+	return ErrNotCommentAuthor
+}
+
+// ErrNoSuchFinding is returned by SetFindingResolved when the review has
+// no finding at the given index.
+var ErrNoSuchFinding = errors.New("review has no finding at that index")
+
+// SetFindingResolved marks a finding of a job's review as resolved or
+// unresolved. findingIdx is the finding's position in ExtractFindings of
+// the review output. Returns sql.ErrNoRows if the job has no review.
+func (db *DB) SetFindingResolved(jobID int64, findingIdx int, resolved bool) error {
+	if !resolved {
+		_, err := db.Exec(`DELETE FROM finding_resolutions WHERE job_id = ? AND finding_idx = ?`, jobID, findingIdx)
+		return err
+	}
+
+	var output string
+	if err := db.QueryRow(`SELECT output FROM reviews WHERE job_id = ?`, jobID).Scan(&output); err != nil {
+		return err
+	}
+	if findingIdx < 0 || findingIdx >= len(ExtractFindings(output)) {
+		return ErrNoSuchFinding
+	}
+	_, err := db.Exec(`INSERT OR IGNORE INTO finding_resolutions (job_id, finding_idx) VALUES (?, ?)`, jobID, findingIdx)
+	return err
+}
+
+// GetResolvedFindings returns the indexes of a job's resolved findings in
+// ascending order.
+func (db *DB) GetResolvedFindings(jobID int64) ([]int, error) {
+	rows, err := db.Query(`SELECT finding_idx FROM finding_resolutions WHERE job_id = ? ORDER BY finding_idx`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resolved []int
+	for rows.Next() {
+		var idx int
+		if err := rows.Scan(&idx); err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, idx)
+	}
+	return resolved, rows.Err()
+}
+
+// DeleteComment removes a comment along with its replies. Returns
+// sql.ErrNoRows if the comment does not exist. Rows are tombstoned with
+// deleted_at rather than removed, because sync only pushes rows it can see.
+func (db *DB) DeleteComment(id int64) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	var result sql.Result
+	err := retryOnBusy(func() error {
+		var err error
+		result, err = db.Exec(`
+			WITH RECURSIVE thread(id) AS (
+				SELECT id FROM responses WHERE id = ? AND deleted_at IS NULL
+				UNION ALL
+				SELECT r.id FROM responses r JOIN thread t ON r.parent_response_id = t.id
+			)
+			UPDATE responses SET deleted_at = ?, updated_at = ?, synced_at = NULL
+			WHERE id IN (SELECT id FROM thread) AND deleted_at IS NULL`, id, now, now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 // GetCommentsForCommit returns all comments for a commit
 func (db *DB) GetCommentsForCommit(commitID int64) ([]Response, error) {
 	rows, err := db.Query(`
 		SELECT id, commit_id, job_id, responder, response, created_at
 		FROM responses
-		WHERE commit_id = ?
+		WHERE commit_id = ? AND deleted_at IS NULL
 		ORDER BY created_at ASC
 	`, commitID)
 	if err != nil {
@@ -538,13 +991,15 @@ func (db *DB) GetCommentsForCommit(commitID int64) ([]Response, error) {
 	return responses, rows.Err()
 }
 
-// GetCommentsForJob returns all comments linked to a job
+// GetCommentsForJob returns all comments linked to a job in thread order:
+// top-level comments oldest first, each followed by its replies (oldest
+// first, nested depth-first) with Depth set.
 func (db *DB) GetCommentsForJob(jobID int64) ([]Response, error) {
 	rows, err := db.Query(`
-		SELECT id, commit_id, job_id, responder, response, created_at
+		SELECT id, commit_id, job_id, parent_response_id, responder, response, created_at
 		FROM responses
-		WHERE job_id = ?
-		ORDER BY created_at ASC
+		WHERE job_id = ? AND deleted_at IS NULL
+		ORDER BY created_at ASC, id ASC
 	`, jobID)
 	if err != nil {
 		return nil, err
@@ -555,8 +1010,8 @@ func (db *DB) GetCommentsForJob(jobID int64) ([]Response, error) {
 	for rows.Next() {
 		var r Response
 		var createdAt string
-		var commitIDNull, jobIDNull sql.NullInt64
-		if err := rows.Scan(&r.ID, &commitIDNull, &jobIDNull, &r.Responder, &r.Response, &createdAt); err != nil {
+		var commitIDNull, jobIDNull, parentIDNull sql.NullInt64
+		if err := rows.Scan(&r.ID, &commitIDNull, &jobIDNull, &parentIDNull, &r.Responder, &r.Response, &createdAt); err != nil {
 			return nil, err
 		}
 		if commitIDNull.Valid {
@@ -565,11 +1020,49 @@ func (db *DB) GetCommentsForJob(jobID int64) ([]Response, error) {
 		if jobIDNull.Valid {
 			r.JobID = &jobIDNull.Int64
 		}
+		if parentIDNull.Valid {
+			r.ParentResponseID = &parentIDNull.Int64
+		}
 		r.CreatedAt = parseSQLiteTime(createdAt)
 		responses = append(responses, r)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return responses, rows.Err()
+	return threadComments(responses), nil
+}
+
+// threadComments orders comments so each reply follows its parent, setting
+// Depth. Replies whose parent isn't in the list are treated as top-level.
+func threadComments(comments []Response) []Response {
+	present := make(map[int64]bool, len(comments))
+	for _, c := range comments {
+		present[c.ID] = true
+	}
+	var roots []Response
+	replies := make(map[int64][]Response)
+	for _, c := range comments {
+		if c.ParentResponseID != nil && present[*c.ParentResponseID] {
+			replies[*c.ParentResponseID] = append(replies[*c.ParentResponseID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	threaded := make([]Response, 0, len(comments))
+	var walk func(c Response, depth int)
+	walk = func(c Response, depth int) {
+		c.Depth = depth
+		threaded = append(threaded, c)
+		for _, reply := range replies[c.ID] {
+			walk(reply, depth+1)
+		}
+	}
+	for _, c := range roots {
+		walk(c, 0)
+	}
+	return threaded
 }
 
 // GetCommentsForCommitSHA returns all comments for a commit by SHA
@@ -2,7 +2,12 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestAddCommentToJobAllStates verifies that comments can be added to jobs
@@ -152,6 +157,193 @@ func TestAddCommentToJobWithNoReview(t *testing.T) {
 	}
 }
 
+func TestUpdateComment(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, _, job := createJobChain(t, db, "/tmp/test-repo", "abc123")
+	resp, err := db.AddCommentToJob(job.ID, "alice", "Tpyo")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+
+	if err := db.UpdateComment(resp.ID, "alice", "Typo"); err != nil {
+		t.Fatalf("UpdateComment failed: %v", err)
+	}
+	comments, err := db.GetCommentsForJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 comment, got %d", len(comments))
+	}
+	verifyComment(t, comments[0], "alice", "Typo")
+
+	if err := db.UpdateComment(resp.ID, "bob", "Hijacked"); !errors.Is(err, ErrNotCommentAuthor) {
+		t.Errorf("Expected ErrNotCommentAuthor for another responder, got: %v", err)
+	}
+	if err := db.UpdateComment(99999, "alice", "Missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for missing comment, got: %v", err)
+	}
+
+	comments, err = db.GetCommentsForJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	verifyComment(t, comments[0], "alice", "Typo")
+}
+
+func TestDeleteComment(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, _, job := createJobChain(t, db, "/tmp/test-repo", "abc123")
+	keep, err := db.AddCommentToJob(job.ID, "alice", "Keep me")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+	drop, err := db.AddCommentToJob(job.ID, "bob", "Wrong review")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+
+	if err := db.DeleteComment(drop.ID); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+	if err := db.DeleteComment(drop.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting twice, got: %v", err)
+	}
+
+	comments, err := db.GetCommentsForJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	if len(comments) != 1 || comments[0].ID != keep.ID {
+		t.Fatalf("Expected only comment %d to remain, got %+v", keep.ID, comments)
+	}
+}
+
+func TestReplyToCommentThreadsComments(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, _, job := createJobChain(t, db, "/tmp/test-repo", "abc123")
+	_, _, other := createJobChain(t, db, "/tmp/test-repo", "def456")
+	add := func(responder, text string) *Response {
+		t.Helper()
+		c, err := db.AddCommentToJob(job.ID, responder, text)
+		if err != nil {
+			t.Fatalf("AddCommentToJob failed: %v", err)
+		}
+		return c
+	}
+	reply := func(parent *Response, responder, text string) *Response {
+		t.Helper()
+		c, err := db.ReplyToComment(parent.ID, responder, text)
+		if err != nil {
+			t.Fatalf("ReplyToComment failed: %v", err)
+		}
+		return c
+	}
+
+	first := add("alice", "Is this lock needed?")
+	second := add("carol", "Unrelated nit")
+	answer := reply(first, "bob", "Yes, for the cache")
+	reply(answer, "alice", "Thanks")
+	reply(first, "carol", "Agreed")
+
+	if answer.JobID == nil || *answer.JobID != job.ID {
+		t.Errorf("reply job = %v, want %d", answer.JobID, job.ID)
+	}
+
+	comments, err := db.GetCommentsForJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	var got []string
+	for _, c := range comments {
+		got = append(got, fmt.Sprintf("%d:%s", c.Depth, c.Response))
+	}
+	want := []string{
+		"0:Is this lock needed?",
+		"1:Yes, for the cache",
+		"2:Thanks",
+		"1:Agreed",
+		"0:Unrelated nit",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("thread = %q, want %q", got, want)
+	}
+	if comments[1].ParentResponseID == nil || *comments[1].ParentResponseID != first.ID {
+		t.Errorf("reply parent = %v, want %d", comments[1].ParentResponseID, first.ID)
+	}
+
+	if _, err := db.ReplyToComment(99999, "bob", "Missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows for a missing parent, got: %v", err)
+	}
+	if _, err := db.AddReplyToJob(other.ID, first.ID, "bob", "Wrong job"); !errors.Is(err, ErrReplyParentMismatch) {
+		t.Errorf("Expected ErrReplyParentMismatch for another job's comment, got: %v", err)
+	}
+
+	// Deleting a comment removes its replies; other threads are kept
+	if err := db.DeleteComment(first.ID); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+	comments, err = db.GetCommentsForJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	if len(comments) != 1 || comments[0].ID != second.ID {
+		t.Errorf("Expected only comment %d to remain, got %+v", second.ID, comments)
+	}
+}
+
+func TestSetFindingResolved(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	_, _, job := createJobChain(t, db, "/tmp/test-repo", "abc123")
+	if err := db.SetFindingResolved(job.ID, 0, true); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows before the review exists, got: %v", err)
+	}
+	claimJob(t, db, "worker-1")
+	output := "- **High**: SQL injection in query builder\n- Low: typo in comment\n"
+	if err := db.CompleteJob(job.ID, "codex", "prompt", output); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	for _, idx := range []int{1, 0, 1} {
+		if err := db.SetFindingResolved(job.ID, idx, true); err != nil {
+			t.Fatalf("SetFindingResolved(%d) failed: %v", idx, err)
+		}
+	}
+	if err := db.SetFindingResolved(job.ID, 2, true); !errors.Is(err, ErrNoSuchFinding) {
+		t.Errorf("Expected ErrNoSuchFinding for index 2, got: %v", err)
+	}
+	resolved, err := db.GetResolvedFindings(job.ID)
+	if err != nil {
+		t.Fatalf("GetResolvedFindings failed: %v", err)
+	}
+	if !slices.Equal(resolved, []int{0, 1}) {
+		t.Errorf("resolved = %v, want [0 1]", resolved)
+	}
+
+	if err := db.SetFindingResolved(job.ID, 0, false); err != nil {
+		t.Fatalf("SetFindingResolved(unresolve) failed: %v", err)
+	}
+	if resolved, _ = db.GetResolvedFindings(job.ID); !slices.Equal(resolved, []int{1}) {
+		t.Errorf("resolved after unresolve = %v, want [1]", resolved)
+	}
+
+	// A rerun produces new findings, so earlier resolutions no longer apply
+	if err := db.ReenqueueJob(job.ID); err != nil {
+		t.Fatalf("ReenqueueJob failed: %v", err)
+	}
+	if resolved, _ = db.GetResolvedFindings(job.ID); len(resolved) != 0 {
+		t.Errorf("resolved after rerun = %v, want none", resolved)
+	}
+}
+
 func TestGetReviewByJobIDIncludesModel(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -292,6 +484,56 @@ func TestGetJobsWithReviewsByIDs(t *testing.T) {
 	})
 }
 
+func TestGetJobsWithReviewsOrdered(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/test-repo")
+	job1 := createCompletedJob(t, db, repo.ID, "abc123", "output1")
+	job3 := createCompletedJob(t, db, repo.ID, "ghi789", "output3")
+	job2 := enqueueJob(t, db, repo.ID, 0, "def456")
+
+	t.Run("preserves input order and skips missing ids", func(t *testing.T) {
+		results, err := db.GetJobsWithReviewsOrdered([]int64{job3.ID, 9999, job1.ID, job2.ID})
+		if err != nil {
+			t.Fatalf("GetJobsWithReviewsOrdered failed: %v", err)
+		}
+		var got []int64
+		for _, r := range results {
+			got = append(got, r.Job.ID)
+		}
+		if want := []int64{job3.ID, job1.ID, job2.ID}; !slices.Equal(got, want) {
+			t.Fatalf("Expected job IDs %v, got %v", want, got)
+		}
+		if results[0].Review == nil || results[0].Review.Output != "output3" {
+			t.Errorf("Expected review output 'output3' for job 3, got %+v", results[0].Review)
+		}
+		if results[2].Review != nil {
+			t.Errorf("Expected no review for job 2, got %+v", results[2].Review)
+		}
+	})
+
+	t.Run("empty id list", func(t *testing.T) {
+		results, err := db.GetJobsWithReviewsOrdered([]int64{})
+		if err != nil {
+			t.Fatalf("GetJobsWithReviewsOrdered with empty slice failed: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Expected 0 results for empty ID list, got %d", len(results))
+		}
+	})
+
+	t.Run("only non-existent ids", func(t *testing.T) {
+		results, err := db.GetJobsWithReviewsOrdered([]int64{999, 998, 997})
+		if err != nil {
+			t.Fatalf("GetJobsWithReviewsOrdered with non-existent IDs failed: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Expected 0 results for non-existent IDs, got %d", len(results))
+		}
+	})
+}
+
 func TestGetJobsWithReviewsByIDsPopulatesVerdict(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -382,6 +624,52 @@ func createCompletedJob(t *testing.T, db *DB, repoID int64, gitRef, output strin
 	return updatedJob
 }
 
+func TestGetReviewByJobIDIncludesCommitInfo(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/test-repo")
+	authored := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	commit, err := db.GetOrCreateCommit(repo.ID, "info-sha", "Ada Lovelace", "Fix the engine", authored)
+	if err != nil {
+		t.Fatalf("GetOrCreateCommit failed: %v", err)
+	}
+	commitJob := enqueueJob(t, db, repo.ID, commit.ID, "info-sha")
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(commitJob.ID, "codex", "prompt", "output"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+
+	review, err := db.GetReviewByJobID(commitJob.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID failed: %v", err)
+	}
+	job := review.Job
+	if job.CommitAuthor != "Ada Lovelace" || job.CommitSubject != "Fix the engine" {
+		t.Errorf("author/subject = %q/%q", job.CommitAuthor, job.CommitSubject)
+	}
+	if job.CommitDate == nil || !job.CommitDate.Equal(authored) {
+		t.Errorf("CommitDate = %v, want %v", job.CommitDate, authored)
+	}
+
+	// Range jobs have no commit record
+	rangeJob, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, GitRef: "aaa..bbb", Agent: "codex"})
+	if err != nil {
+		t.Fatalf("EnqueueJob failed: %v", err)
+	}
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(rangeJob.ID, "codex", "prompt", "output"); err != nil {
+		t.Fatalf("CompleteJob failed: %v", err)
+	}
+	review, err = db.GetReviewByJobID(rangeJob.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID failed: %v", err)
+	}
+	if review.Job.CommitAuthor != "" || review.Job.CommitDate != nil {
+		t.Errorf("range job commit info = %q %v, want none", review.Job.CommitAuthor, review.Job.CommitDate)
+	}
+}
+
 func TestGetReviewByJobIDUsesStoredVerdict(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -446,6 +734,88 @@ func TestGetReviewByJobIDUsesStoredVerdict(t *testing.T) {
 	})
 }
 
+func TestGetJobsByVerdict(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/verdict-filter")
+	other := createRepo(t, db, "/tmp/verdict-filter-other")
+
+	complete := func(repoID int64, sha, output string, legacy bool) int64 {
+		t.Helper()
+		job := enqueueJob(t, db, repoID, createCommit(t, db, repoID, sha).ID, sha)
+		claimJob(t, db, "w-"+sha)
+		if err := db.CompleteJob(job.ID, "codex", "prompt", output); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+		if legacy {
+			if _, err := db.Exec(`UPDATE reviews SET verdict_bool = NULL WHERE job_id = ?`, job.ID); err != nil {
+				t.Fatalf("nullify verdict_bool: %v", err)
+			}
+		}
+		return job.ID
+	}
+	pass := complete(repo.ID, "pass1", "No issues found.", false)
+	fail := complete(repo.ID, "fail1", "- High: nil dereference in handler", false)
+	legacyPass := complete(repo.ID, "pass2", "No issues found.", true)
+	legacyFail := complete(repo.ID, "fail2", "- Medium: missing error check", true)
+	complete(other.ID, "fail3", "- High: other repo", false)
+	failed := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "failed1").ID, "failed1").ID
+	claimJob(t, db, "w-failed1")
+	if _, err := db.FailJob(failed, "", "agent crashed"); err != nil {
+		t.Fatalf("FailJob: %v", err)
+	}
+	canceled := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "canceled1").ID, "canceled1").ID
+	if err := db.CancelJob(canceled); err != nil {
+		t.Fatalf("CancelJob: %v", err)
+	}
+	running := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "running1").ID, "running1").ID
+	claimJob(t, db, "w-running1")
+	queued := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "queued1").ID, "queued1").ID
+
+	ids := func(jobs []ReviewJob) []int64 {
+		var out []int64
+		for _, j := range jobs {
+			out = append(out, j.ID)
+		}
+		return out
+	}
+	tests := []struct {
+		verdict string
+		limit   int
+		want    []int64
+	}{
+		{VerdictFilterPass, 0, []int64{legacyPass, pass}},
+		{VerdictFilterFail, 0, []int64{legacyFail, fail}},
+		{VerdictFilterFail, 1, []int64{legacyFail}},
+		{VerdictFilterPending, 0, []int64{queued, running}},
+	}
+	for _, tt := range tests {
+		jobs, err := db.GetJobsByVerdict(repo.ID, tt.verdict, tt.limit)
+		if err != nil {
+			t.Fatalf("GetJobsByVerdict(%s): %v", tt.verdict, err)
+		}
+		if got := ids(jobs); !slices.Equal(got, tt.want) {
+			t.Errorf("GetJobsByVerdict(%s, limit %d) = %v, want %v", tt.verdict, tt.limit, got, tt.want)
+		}
+	}
+
+	page, err := db.ListJobs("", "", 1, 1, WithRepoID(repo.ID), WithVerdict(VerdictFilterFail))
+	if err != nil {
+		t.Fatalf("ListJobs: %v", err)
+	}
+	if got := ids(page); !slices.Equal(got, []int64{fail}) {
+		t.Errorf("second page of failing jobs = %v, want %v", got, []int64{fail})
+	}
+
+	if jobs, err := db.GetJobsByVerdict(0, VerdictFilterFail, 0); err != nil || len(jobs) != 3 {
+		t.Errorf("all repos: got %d failing jobs (err %v), want 3", len(jobs), err)
+	}
+	if _, err := db.GetJobsByVerdict(repo.ID, "maybe", 0); err == nil {
+		t.Error("expected an error for an invalid verdict filter")
+	}
+}
+
 func TestGetReviewByCommitSHAUsesStoredVerdict(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -475,6 +845,88 @@ func TestGetReviewByCommitSHAUsesStoredVerdict(t *testing.T) {
 	}
 }
 
+func TestResolveJobSHA(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/job-sha-prefix-test")
+	createCompletedJob(t, db, repo.ID, "abcd1111", "output one")
+	// Unfinished jobs are found too, so wait can find a running review
+	enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "abcd2222").ID, "abcd2222")
+
+	for _, tt := range []struct {
+		ref, want string
+	}{
+		{"abcd2", "abcd2222"},
+		{"ABCD1", "abcd1111"},
+		{"abcd1111", "abcd1111"},
+		{"HEAD", "HEAD"},
+	} {
+		got, err := db.ResolveJobSHA(tt.ref)
+		if err != nil {
+			t.Fatalf("ResolveJobSHA(%q): %v", tt.ref, err)
+		}
+		if got != tt.want {
+			t.Errorf("ResolveJobSHA(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+
+	if _, err := db.ResolveJobSHA("abcd"); !errors.Is(err, ErrAmbiguousSHA) {
+		t.Errorf("expected ErrAmbiguousSHA, got %v", err)
+	}
+	if _, err := db.ResolveJobSHA("ffff"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestGetReviewByCommitSHAPrefix(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/sha-prefix-test")
+	createCompletedJob(t, db, repo.ID, "abcd1111", "output one")
+	createCompletedJob(t, db, repo.ID, "abcd2222", "output two")
+	createCompletedJob(t, db, repo.ID, "abcd1111..ffff0000", "range output")
+
+	t.Run("exact match", func(t *testing.T) {
+		review, err := db.GetReviewByCommitSHA("abcd2222")
+		if err != nil {
+			t.Fatalf("GetReviewByCommitSHA: %v", err)
+		}
+		if review.Output != "output two" {
+			t.Errorf("output = %q", review.Output)
+		}
+	})
+
+	t.Run("unique prefix", func(t *testing.T) {
+		review, err := db.GetReviewByCommitSHA("abcd1")
+		if err != nil {
+			t.Fatalf("GetReviewByCommitSHA: %v", err)
+		}
+		if review.Job == nil || review.Job.GitRef != "abcd1111" {
+			t.Errorf("resolved to %+v, want abcd1111", review.Job)
+		}
+	})
+
+	t.Run("ambiguous prefix", func(t *testing.T) {
+		_, err := db.GetReviewByCommitSHA("abcd")
+		if !errors.Is(err, ErrAmbiguousSHA) {
+			t.Fatalf("expected ErrAmbiguousSHA, got %v", err)
+		}
+		if msg := err.Error(); !strings.Contains(msg, "abcd1111, abcd2222") || strings.Contains(msg, "..") {
+			t.Errorf("error should list the two commit candidates: %q", msg)
+		}
+	})
+
+	for _, sha := range []string{"abc", "abcdzz", "ffff"} {
+		t.Run("no match "+sha, func(t *testing.T) {
+			if _, err := db.GetReviewByCommitSHA(sha); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("expected sql.ErrNoRows, got %v", err)
+			}
+		})
+	}
+}
+
 // verifyComment helper checks if a comment matches expected values.
 func verifyComment(t *testing.T, actual Response, expectedUser, expectedMsg string) {
 	t.Helper()
@@ -485,3 +937,53 @@ func verifyComment(t *testing.T, actual Response, expectedUser, expectedMsg stri
 		t.Errorf("Expected response %q, got %q", expectedMsg, actual.Response)
 	}
 }
+
+func TestGetComparisonGroup(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/compare-repo")
+	other := createRepo(t, db, "/tmp/compare-other")
+	commit := createCommit(t, db, repo.ID, "cmp123")
+
+	// review enqueues, claims, and completes a job, returning its ID.
+	review := func(opts EnqueueOpts, output string) int64 {
+		t.Helper()
+		job, err := db.EnqueueJob(opts)
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		claimJob(t, db, "worker-1")
+		if err := db.CompleteJob(job.ID, opts.Agent, "prompt", output); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+		return job.ID
+	}
+
+	codex := review(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "cmp123", Agent: "codex"}, "No issues found.")
+	claude := review(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "cmp123", Agent: "claude-code", Model: "opus"}, "- High: bug")
+	// Not siblings: different review type, different repo, different ref.
+	review(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: "cmp123", Agent: "gemini", ReviewType: "security"}, "No issues found.")
+	review(EnqueueOpts{RepoID: other.ID, CommitID: createCommit(t, db, other.ID, "cmp999").ID, GitRef: "cmp999", Agent: "gemini"}, "No issues found.")
+	// A sibling that is still queued has no review yet.
+	enqueueJob(t, db, repo.ID, commit.ID, "cmp123")
+
+	group, err := db.GetComparisonGroup(claude)
+	if err != nil {
+		t.Fatalf("GetComparisonGroup: %v", err)
+	}
+	if len(group) != 2 || group[0].JobID != codex || group[1].JobID != claude {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if group[1].Job == nil || group[1].Job.Model != "opus" || group[1].Job.Verdict == nil || *group[1].Job.Verdict != "F" {
+		t.Errorf("sibling review missing job metadata: %+v", group[1].Job)
+	}
+
+	task := review(EnqueueOpts{RepoID: repo.ID, GitRef: "run", Agent: "codex", Prompt: "do it"}, "done")
+	if _, err := db.GetComparisonGroup(task); err != sql.ErrNoRows {
+		t.Errorf("task job: expected sql.ErrNoRows, got %v", err)
+	}
+	if _, err := db.GetComparisonGroup(99999); err != sql.ErrNoRows {
+		t.Errorf("missing job: expected sql.ErrNoRows, got %v", err)
+	}
+}
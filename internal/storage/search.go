@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Markers wrapped around matched terms in Review.Snippet. They are control
+// characters so they can't collide with review text; callers replace them
+// with whatever highlighting suits their output.
+const (
+	SnippetMatchStart = "\x02"
+	SnippetMatchEnd   = "\x03"
+)
+
+// defaultSearchLimit caps SearchReviews results when no limit is given.
+const defaultSearchLimit = 50
+
+// ErrEmptySearchQuery is returned by SearchReviews for a blank query, which
+// would otherwise match every review.
+var ErrEmptySearchQuery = errors.New("search query is empty")
+
+// SearchReviews returns reviews whose output matches query, most recently
+// finished first. Each term in query
+// must appear in the output; terms are matched literally, so punctuation
+// such as "parse(" is not treated as search syntax. Each returned review's
+// Snippet holds the matching excerpt with matched terms wrapped in
+// SnippetMatchStart and SnippetMatchEnd. A limit of zero or less means
+// defaultSearchLimit.
+func (db *DB) SearchReviews(query string, limit int) ([]Review, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	rows, err := db.Query(`
+		SELECT rv.job_id, snippet(reviews_fts, 0, ?, ?, '...', 16)
+		FROM reviews_fts
+		JOIN reviews rv ON rv.id = reviews_fts.rowid
+		JOIN review_jobs j ON j.id = rv.job_id
+		WHERE reviews_fts MATCH ?
+		ORDER BY j.finished_at DESC, rv.id DESC
+		LIMIT ?`, SnippetMatchStart, SnippetMatchEnd, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search reviews: %w", err)
+	}
+	type hit struct {
+		jobID   int64
+		snippet string
+	}
+	var hits []hit
+	for rows.Next() {
+		var h hit
+		if err := rows.Scan(&h.jobID, &h.snippet); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]Review, 0, len(hits))
+	for _, h := range hits {
+		review, err := db.GetReviewByJobID(h.jobID)
+		if err != nil {
+			return nil, fmt.Errorf("load review for job %d: %w", h.jobID, err)
+		}
+		review.Snippet = h.snippet
+		results = append(results, *review)
+	}
+	return results, nil
+}
+
+// ftsQuery converts free text into an FTS5 query that matches every term
+// literally, by quoting each whitespace-separated term.
+func ftsQuery(query string) string {
+	terms := strings.Fields(query)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchReviews(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	weak := createCompletedJob(t, db, repo.ID, "sha-weak",
+		"- Low: loadConfig is long\n\nThe rest of this review covers unrelated formatting nits in several files.")
+	strong := createCompletedJob(t, db, repo.ID, "sha-strong",
+		"- High: loadConfig ignores errors\n\nloadConfig swallows the error from loadConfig's parser.")
+	createCompletedJob(t, db, repo.ID, "sha-other", "No issues found.")
+
+	// The weaker match finished later, so recency puts it first
+	finished := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, j := range []struct {
+		id int64
+		at time.Time
+	}{{strong.ID, finished}, {weak.ID, finished.Add(time.Hour)}} {
+		if _, err := db.Exec(`UPDATE review_jobs SET finished_at = ? WHERE id = ?`, j.at.Format(time.RFC3339), j.id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := db.SearchReviews("loadConfig", 10)
+	if err != nil {
+		t.Fatalf("SearchReviews failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].JobID != weak.ID || results[1].JobID != strong.ID {
+		t.Errorf("expected job %d ordered before %d, got %d, %d", weak.ID, strong.ID, results[0].JobID, results[1].JobID)
+	}
+	want := SnippetMatchStart + "loadConfig" + SnippetMatchEnd
+	if !strings.Contains(results[0].Snippet, want) {
+		t.Errorf("snippet should highlight the match, got %q", results[0].Snippet)
+	}
+	if results[0].Job == nil || results[0].Job.GitRef != "sha-weak" {
+		t.Errorf("expected joined job, got %+v", results[0].Job)
+	}
+
+	// Search syntax characters are matched literally rather than erroring
+	if _, err := db.SearchReviews(`loadConfig( "AND`, 10); err != nil {
+		t.Errorf("SearchReviews with punctuation failed: %v", err)
+	}
+
+	if _, err := db.SearchReviews("   ", 10); !errors.Is(err, ErrEmptySearchQuery) {
+		t.Errorf("empty query error = %v, want ErrEmptySearchQuery", err)
+	}
+
+	// Deleted reviews drop out of the index
+	if _, err := db.Exec(`DELETE FROM reviews WHERE job_id = ?`, weak.ID); err != nil {
+		t.Fatal(err)
+	}
+	results, err = db.SearchReviews("loadConfig", 10)
+	if err != nil {
+		t.Fatalf("SearchReviews failed: %v", err)
+	}
+	if len(results) != 1 || results[0].JobID != strong.ID {
+		t.Errorf("expected only job %d after delete, got %d results", strong.ID, len(results))
+	}
+}
+
+func TestMigrateReviewSearchRebuildsIndex(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	job := createCompletedJob(t, db, repo.ID, "sha-legacy", "- Medium: retryBackoff never resets")
+
+	// Simulate a database from before the search index existed
+	for _, stmt := range []string{
+		`DROP TRIGGER reviews_fts_insert`,
+		`DROP TRIGGER reviews_fts_delete`,
+		`DROP TRIGGER reviews_fts_update`,
+		`DROP TABLE reviews_fts`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := db.migrate(); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	results, err := db.SearchReviews("retryBackoff", 10)
+	if err != nil {
+		t.Fatalf("SearchReviews failed: %v", err)
+	}
+	if len(results) != 1 || results[0].JobID != job.ID {
+		t.Errorf("expected existing review to be indexed, got %d results", len(results))
+	}
+}
+
+func TestSearchReviewsDefaultLimit(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	for i := range defaultSearchLimit + 5 {
+		createCompletedJob(t, db, repo.ID, fmt.Sprintf("sha-%d", i), "- Low: flakyHelper sleeps")
+	}
+
+	results, err := db.SearchReviews("flakyHelper", 0)
+	if err != nil {
+		t.Fatalf("SearchReviews failed: %v", err)
+	}
+	if len(results) != defaultSearchLimit {
+		t.Errorf("expected %d results with limit 0, got %d", defaultSearchLimit, len(results))
+	}
+}
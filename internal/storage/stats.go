@@ -0,0 +1,492 @@
+package storage
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"slices"
+	"time"
+)
+
+// StatsOptions filters AggregateStats.
+type StatsOptions struct {
+	RepoPath string    // Only jobs in this repo root (empty = all repos)
+	Since    time.Time // Only jobs that finished at or after this time (zero = any time)
+}
+
+// DurationStats summarizes review durations. Count is the number of jobs
+// with both timestamps; the other fields are zero when Count is zero.
+type DurationStats struct {
+	Count int
+	Avg   time.Duration
+	P50   time.Duration
+	P95   time.Duration
+}
+
+// MarshalJSON encodes durations as fractional seconds for dashboards.
+func (d DurationStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Count      int     `json:"count"`
+		AvgSeconds float64 `json:"avg_seconds"`
+		P50Seconds float64 `json:"p50_seconds"`
+		P95Seconds float64 `json:"p95_seconds"`
+	}{d.Count, d.Avg.Seconds(), d.P50.Seconds(), d.P95.Seconds()})
+}
+
+// AgentStats holds job totals and review timing for one agent.
+type AgentStats struct {
+	Agent  string        `json:"agent"`
+	Total  int           `json:"total"`
+	Done   int           `json:"done"`
+	Failed int           `json:"failed"`
+	Timing DurationStats `json:"timing"`
+}
+
+// AggregateStats holds job totals and review timing across finished jobs.
+type AggregateStats struct {
+	Total  int           `json:"total"`
+	Done   int           `json:"done"`
+	Failed int           `json:"failed"`
+	Timing DurationStats `json:"timing"`
+	Agents []AgentStats  `json:"agents"` // Sorted by agent name
+}
+
+// AggregateStats returns totals and duration statistics per agent for done
+// and failed jobs. Timing covers done jobs only; jobs missing started_at or
+// finished_at are counted in totals but skipped for timing.
+func (db *DB) AggregateStats(opts StatsOptions) (*AggregateStats, error) {
+	query := `
+		SELECT j.agent, j.status, j.started_at, j.finished_at
+		FROM review_jobs j
+		JOIN repos r ON r.id = j.repo_id
+		WHERE j.status IN ('done', 'failed')`
+	var args []any
+	if opts.RepoPath != "" {
+		query += ` AND r.root_path = ?`
+		args = append(args, opts.RepoPath)
+	}
+	if !opts.Since.IsZero() {
+		query += ` AND datetime(j.finished_at) >= datetime(?)`
+		args = append(args, opts.Since.UTC().Format(time.RFC3339))
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &AggregateStats{}
+	byAgent := make(map[string]*AgentStats)
+	agentDurations := make(map[string][]time.Duration)
+	var allDurations []time.Duration
+
+	for rows.Next() {
+		var agent, status string
+		var startedAt, finishedAt sql.NullString
+		if err := rows.Scan(&agent, &status, &startedAt, &finishedAt); err != nil {
+			return nil, err
+		}
+
+		as, ok := byAgent[agent]
+		if !ok {
+			as = &AgentStats{Agent: agent}
+			byAgent[agent] = as
+		}
+		stats.Total++
+		as.Total++
+		if JobStatus(status) == JobStatusFailed {
+			stats.Failed++
+			as.Failed++
+			continue
+		}
+		stats.Done++
+		as.Done++
+
+		if d, ok := jobDuration(startedAt, finishedAt); ok {
+			agentDurations[agent] = append(agentDurations[agent], d)
+			allDurations = append(allDurations, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats.Timing = ComputeDurationStats(allDurations)
+	stats.Agents = make([]AgentStats, 0, len(byAgent))
+	for agent, as := range byAgent {
+		as.Timing = ComputeDurationStats(agentDurations[agent])
+		stats.Agents = append(stats.Agents, *as)
+	}
+	slices.SortFunc(stats.Agents, func(a, b AgentStats) int {
+		return cmp.Compare(a.Agent, b.Agent)
+	})
+
+	return stats, nil
+}
+
+// jobDuration returns finished_at - started_at when both are set and valid.
+func jobDuration(startedAt, finishedAt sql.NullString) (time.Duration, bool) {
+	if !startedAt.Valid || !finishedAt.Valid {
+		return 0, false
+	}
+	start, err := time.Parse(time.RFC3339, startedAt.String)
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339, finishedAt.String)
+	if err != nil || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// ComputeDurationStats returns the count, mean, and nearest-rank p50/p95 of
+// durations. The input slice is not modified.
+func ComputeDurationStats(durations []time.Duration) DurationStats {
+	if len(durations) == 0 {
+		return DurationStats{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	return DurationStats{
+		Count: len(sorted),
+		Avg:   sum / time.Duration(len(sorted)),
+		P50:   percentile(sorted, 50),
+		P95:   percentile(sorted, 95),
+	}
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
+
+// DurationPercentiles holds p50/p90/p99 review durations. Count is the
+// number of timed jobs; the percentiles are zero when Count is zero.
+type DurationPercentiles struct {
+	Count int
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+}
+
+// MarshalJSON encodes durations as fractional seconds, like DurationStats.
+func (d DurationPercentiles) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Count      int     `json:"count"`
+		P50Seconds float64 `json:"p50_seconds"`
+		P90Seconds float64 `json:"p90_seconds"`
+		P99Seconds float64 `json:"p99_seconds"`
+	}{d.Count, d.P50.Seconds(), d.P90.Seconds(), d.P99.Seconds()})
+}
+
+// GetDurationStats returns p50/p90/p99 of finished_at - started_at across
+// completed jobs in repoID (0 = all repos). Jobs missing either timestamp
+// are ignored.
+func (db *DB) GetDurationStats(repoID int64) (*DurationPercentiles, error) {
+	query := `SELECT started_at, finished_at FROM review_jobs WHERE status = 'done'`
+	var args []any
+	if repoID != 0 {
+		query += ` AND repo_id = ?`
+		args = append(args, repoID)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var durations []time.Duration
+	for rows.Next() {
+		var startedAt, finishedAt sql.NullString
+		if err := rows.Scan(&startedAt, &finishedAt); err != nil {
+			return nil, err
+		}
+		if d, ok := jobDuration(startedAt, finishedAt); ok {
+			durations = append(durations, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	p := ComputeDurationPercentiles(durations)
+	return &p, nil
+}
+
+// ComputeDurationPercentiles returns nearest-rank p50/p90/p99 of durations.
+// With fewer than 3 samples the percentiles aren't meaningful, so all three
+// are the maximum. The input slice is not modified.
+func ComputeDurationPercentiles(durations []time.Duration) DurationPercentiles {
+	if len(durations) == 0 {
+		return DurationPercentiles{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	if len(sorted) < 3 {
+		longest := sorted[len(sorted)-1]
+		return DurationPercentiles{Count: len(sorted), P50: longest, P90: longest, P99: longest}
+	}
+	return DurationPercentiles{
+		Count: len(sorted),
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		P99:   percentile(sorted, 99),
+	}
+}
+
+// JobCounts summarizes the queue for GET /api/stats. Queued and Running are
+// current totals; the other counts, AvgDuration, and PassRate cover jobs
+// that finished inside the requested window.
+type JobCounts struct {
+	Queued      int
+	Running     int
+	Done        int
+	Failed      int
+	Canceled    int
+	AvgDuration time.Duration // mean finished_at - started_at of done jobs
+	Passed      int           // done reviews with a passing verdict
+	Rejected    int           // done reviews with a failing verdict
+}
+
+// PassRate returns the percentage of verdicts that passed, or 0 with none.
+func (c JobCounts) PassRate() float64 {
+	return VerdictCounts{Total: c.Passed + c.Rejected, Passed: c.Passed}.PassRate()
+}
+
+// MarshalJSON encodes the duration as fractional seconds and includes the
+// derived pass rate.
+func (c JobCounts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Queued             int     `json:"queued"`
+		Running            int     `json:"running"`
+		Done               int     `json:"done"`
+		Failed             int     `json:"failed"`
+		Canceled           int     `json:"canceled"`
+		AvgDurationSeconds float64 `json:"avg_duration_seconds"`
+		Passed             int     `json:"passed"`
+		Rejected           int     `json:"rejected"`
+		PassRate           float64 `json:"pass_rate"`
+	}{c.Queued, c.Running, c.Done, c.Failed, c.Canceled, c.AvgDuration.Seconds(),
+		c.Passed, c.Rejected, c.PassRate()})
+}
+
+// JobCounts returns queue counts, average review duration, and pass rate in
+// a single query. Finished jobs only count when they finished within window
+// of now; a window of zero or less covers all time.
+func (db *DB) JobCounts(window time.Duration) (*JobCounts, error) {
+	where := ``
+	var args []any
+	if window > 0 {
+		where = ` WHERE j.status IN ('queued', 'running') OR datetime(j.finished_at) >= datetime(?)`
+		args = append(args, time.Now().Add(-window).UTC().Format(time.RFC3339))
+	}
+
+	var c JobCounts
+	var avgSeconds sql.NullFloat64
+	err := db.QueryRow(`
+		SELECT COALESCE(SUM(j.status = 'queued'), 0),
+		       COALESCE(SUM(j.status = 'running'), 0),
+		       COALESCE(SUM(j.status = 'done'), 0),
+		       COALESCE(SUM(j.status = 'failed'), 0),
+		       COALESCE(SUM(j.status = 'canceled'), 0),
+		       AVG(CASE WHEN j.status = 'done' AND j.started_at IS NOT NULL AND j.finished_at IS NOT NULL
+		                THEN (julianday(j.finished_at) - julianday(j.started_at)) * 86400 END),
+		       COALESCE(SUM(j.status = 'done' AND rv.verdict_bool = 1), 0),
+		       COALESCE(SUM(j.status = 'done' AND rv.verdict_bool = 0), 0)
+		FROM review_jobs j
+		LEFT JOIN reviews rv ON rv.job_id = j.id`+where, args...).Scan(
+		&c.Queued, &c.Running, &c.Done, &c.Failed, &c.Canceled,
+		&avgSeconds, &c.Passed, &c.Rejected)
+	if err != nil {
+		return nil, err
+	}
+	if avgSeconds.Valid {
+		// julianday arithmetic is only accurate to about a millisecond.
+		c.AvgDuration = time.Duration(avgSeconds.Float64 * float64(time.Second)).Round(time.Millisecond)
+	}
+	return &c, nil
+}
+
+// VerdictCounts holds pass/fail totals for a set of reviews.
+type VerdictCounts struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+}
+
+// PassRate returns the percentage of reviews that passed, or 0 with no reviews.
+func (v VerdictCounts) PassRate() float64 {
+	if v.Total == 0 {
+		return 0
+	}
+	return float64(v.Passed) / float64(v.Total) * 100
+}
+
+func (v *VerdictCounts) add(verdict string, n int) {
+	v.Total += n
+	if verdict == "P" {
+		v.Passed += n
+	} else {
+		v.Failed += n
+	}
+}
+
+// ModelReviewStats holds verdict counts for one agent/model pair. Model is
+// empty when the agent ran with its default model.
+type ModelReviewStats struct {
+	Agent string `json:"agent"`
+	Model string `json:"model"`
+	VerdictCounts
+}
+
+// ReviewStats holds verdict counts across completed reviews.
+type ReviewStats struct {
+	VerdictCounts
+	Models []ModelReviewStats `json:"models"` // Sorted by agent, then model
+}
+
+// GetReviewStats returns pass/fail counts for completed reviews, overall and
+// per agent/model. Task and fix jobs have no verdict and are excluded. Legacy
+// reviews without a stored verdict are classified by parsing their output.
+func (db *DB) GetReviewStats(opts StatsOptions) (*ReviewStats, error) {
+	where := `
+		FROM reviews rv
+		JOIN review_jobs j ON j.id = rv.job_id
+		JOIN repos r ON r.id = j.repo_id
+		WHERE j.status = 'done' AND j.job_type NOT IN ('task', 'fix')`
+	var args []any
+	if opts.RepoPath != "" {
+		where += ` AND r.root_path = ?`
+		args = append(args, opts.RepoPath)
+	}
+	if !opts.Since.IsZero() {
+		where += ` AND datetime(j.finished_at) >= datetime(?)`
+		args = append(args, opts.Since.UTC().Format(time.RFC3339))
+	}
+
+	type key struct{ agent, model string }
+	byModel := make(map[key]*ModelReviewStats)
+	entry := func(agent, model string) *ModelReviewStats {
+		k := key{agent, model}
+		ms, ok := byModel[k]
+		if !ok {
+			ms = &ModelReviewStats{Agent: agent, Model: model}
+			byModel[k] = ms
+		}
+		return ms
+	}
+
+	rows, err := db.Query(`
+		SELECT j.agent, COALESCE(j.model, ''),
+		       SUM(CASE WHEN rv.verdict_bool = 1 THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN rv.verdict_bool = 0 THEN 1 ELSE 0 END)`+where+`
+		GROUP BY j.agent, COALESCE(j.model, '')`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var agent, model string
+		var passed, failed int
+		if err := rows.Scan(&agent, &model, &passed, &failed); err != nil {
+			return nil, err
+		}
+		ms := entry(agent, model)
+		ms.add("P", passed)
+		ms.add("F", failed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	legacy, err := db.Query(`SELECT j.agent, COALESCE(j.model, ''), rv.output`+where+`
+		AND rv.verdict_bool IS NULL`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer legacy.Close()
+	for legacy.Next() {
+		var agent, model, output string
+		if err := legacy.Scan(&agent, &model, &output); err != nil {
+			return nil, err
+		}
+		entry(agent, model).add(ParseVerdict(output), 1)
+	}
+	if err := legacy.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := &ReviewStats{Models: make([]ModelReviewStats, 0, len(byModel))}
+	for _, ms := range byModel {
+		stats.Total += ms.Total
+		stats.Passed += ms.Passed
+		stats.Failed += ms.Failed
+		stats.Models = append(stats.Models, *ms)
+	}
+	slices.SortFunc(stats.Models, func(a, b ModelReviewStats) int {
+		return cmp.Or(cmp.Compare(a.Agent, b.Agent), cmp.Compare(a.Model, b.Model))
+	})
+	return stats, nil
+}
+
+// UsageRow totals token usage and cost for one agent in one repo.
+type UsageRow struct {
+	Agent        string  `json:"agent"`
+	RepoPath     string  `json:"repo_path"`
+	RepoName     string  `json:"repo_name"`
+	Reviews      int     `json:"reviews"`  // All reviews in the period
+	Reported     int     `json:"reported"` // Reviews with recorded token usage
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// UsageStats returns token usage and cost per agent and repo for reviews of
+// jobs that finished at or after since (zero = any time), sorted by agent and
+// then repo path. Reviews stored before usage was recorded count toward
+// Reviews but add nothing to the token and cost totals.
+func (db *DB) UsageStats(since time.Time) ([]UsageRow, error) {
+	query := `
+		SELECT COALESCE(rv.effective_agent, rv.agent), r.root_path, r.name, COUNT(*), COUNT(rv.input_tokens),
+		       COALESCE(SUM(rv.input_tokens), 0), COALESCE(SUM(rv.output_tokens), 0),
+		       COALESCE(SUM(rv.cost_usd), 0)
+		FROM reviews rv
+		JOIN review_jobs j ON j.id = rv.job_id
+		JOIN repos r ON r.id = j.repo_id`
+	var args []any
+	if !since.IsZero() {
+		query += ` WHERE datetime(j.finished_at) >= datetime(?)`
+		args = append(args, since.UTC().Format(time.RFC3339))
+	}
+	query += `
+		GROUP BY COALESCE(rv.effective_agent, rv.agent), r.root_path, r.name
+		ORDER BY COALESCE(rv.effective_agent, rv.agent), r.root_path`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []UsageRow
+	for rows.Next() {
+		var u UsageRow
+		if err := rows.Scan(&u.Agent, &u.RepoPath, &u.RepoName, &u.Reviews, &u.Reported,
+			&u.InputTokens, &u.OutputTokens, &u.CostUSD); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
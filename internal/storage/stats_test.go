@@ -0,0 +1,368 @@
+package storage
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestComputeDurationStats(t *testing.T) {
+	secs := func(ns ...int) []time.Duration {
+		var out []time.Duration
+		for _, n := range ns {
+			out = append(out, time.Duration(n)*time.Second)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		in   []time.Duration
+		want DurationStats
+	}{
+		{"empty", nil, DurationStats{}},
+		{"single", secs(7), DurationStats{Count: 1, Avg: 7 * time.Second, P50: 7 * time.Second, P95: 7 * time.Second}},
+		{
+			"ten unsorted",
+			secs(10, 1, 9, 2, 8, 3, 7, 4, 6, 5),
+			DurationStats{Count: 10, Avg: 5500 * time.Millisecond, P50: 5 * time.Second, P95: 10 * time.Second},
+		},
+		{
+			"twenty",
+			secs(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20),
+			DurationStats{Count: 20, Avg: 10500 * time.Millisecond, P50: 10 * time.Second, P95: 19 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeDurationStats(tt.in); got != tt.want {
+				t.Errorf("ComputeDurationStats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateStats(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/stats-repo")
+	other := createRepo(t, db, "/tmp/stats-other")
+	now := time.Now().UTC()
+
+	// finish enqueues and finishes a job, then sets its timestamps
+	// (nil leaves the column NULL).
+	finish := func(repo *Repo, sha, agent string, fail bool, started, finished *time.Time) {
+		t.Helper()
+		commit := createCommit(t, db, repo.ID, sha)
+		job, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: agent})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		claimJob(t, db, "worker-1")
+		if fail {
+			if _, err := db.FailJob(job.ID, "", "boom"); err != nil {
+				t.Fatalf("FailJob: %v", err)
+			}
+		} else if err := db.CompleteJob(job.ID, agent, "p", "No issues found."); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+		var s, f any
+		if started != nil {
+			s = started.Format(time.RFC3339)
+		}
+		if finished != nil {
+			f = finished.Format(time.RFC3339)
+		}
+		if _, err := db.Exec(`UPDATE review_jobs SET started_at = ?, finished_at = ? WHERE id = ?`, s, f, job.ID); err != nil {
+			t.Fatalf("set timestamps: %v", err)
+		}
+	}
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	finish(repo, "c1", "codex", false, at(70*time.Second), at(10*time.Second))  // 60s
+	finish(repo, "c2", "codex", false, at(130*time.Second), at(10*time.Second)) // 120s
+	finish(repo, "c3", "codex", false, nil, at(10*time.Second))                 // untimed
+	finish(repo, "c4", "codex", true, at(20*time.Second), at(10*time.Second))   // failed
+	finish(repo, "g1", "gemini", false, at(40*time.Second), at(10*time.Second)) // 30s
+	finish(repo, "old", "gemini", false, at(49*time.Hour), at(48*time.Hour))    // outside --since
+	finish(other, "o1", "codex", false, at(20*time.Second), at(10*time.Second)) // other repo
+
+	stats, err := db.AggregateStats(StatsOptions{RepoPath: repo.RootPath, Since: now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("AggregateStats: %v", err)
+	}
+	if stats.Total != 5 || stats.Done != 4 || stats.Failed != 1 {
+		t.Errorf("totals = %d/%d/%d, want 5/4/1", stats.Total, stats.Done, stats.Failed)
+	}
+	if stats.Timing.Count != 3 {
+		t.Errorf("timed count = %d, want 3", stats.Timing.Count)
+	}
+	if len(stats.Agents) != 2 || stats.Agents[0].Agent != "codex" || stats.Agents[1].Agent != "gemini" {
+		t.Fatalf("unexpected agents: %+v", stats.Agents)
+	}
+	codex := stats.Agents[0]
+	if codex.Total != 4 || codex.Done != 3 || codex.Failed != 1 {
+		t.Errorf("codex totals = %+v", codex)
+	}
+	wantTiming := DurationStats{Count: 2, Avg: 90 * time.Second, P50: 60 * time.Second, P95: 120 * time.Second}
+	if codex.Timing != wantTiming {
+		t.Errorf("codex timing = %+v, want %+v", codex.Timing, wantTiming)
+	}
+
+	all, err := db.AggregateStats(StatsOptions{})
+	if err != nil {
+		t.Fatalf("AggregateStats: %v", err)
+	}
+	if all.Total != 7 {
+		t.Errorf("unfiltered total = %d, want 7", all.Total)
+	}
+
+	data, err := json.Marshal(codex.Timing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"count":2,"avg_seconds":90,"p50_seconds":60,"p95_seconds":120}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestComputeDurationPercentiles(t *testing.T) {
+	if got := ComputeDurationPercentiles(nil); got != (DurationPercentiles{}) {
+		t.Errorf("empty = %+v, want zero", got)
+	}
+
+	few := ComputeDurationPercentiles([]time.Duration{10 * time.Second, 30 * time.Second})
+	want := DurationPercentiles{Count: 2, P50: 30 * time.Second, P90: 30 * time.Second, P99: 30 * time.Second}
+	if few != want {
+		t.Errorf("two samples = %+v, want max for all (%+v)", few, want)
+	}
+
+	var many []time.Duration
+	for i := 100; i >= 1; i-- {
+		many = append(many, time.Duration(i)*time.Second)
+	}
+	got := ComputeDurationPercentiles(many)
+	want = DurationPercentiles{Count: 100, P50: 50 * time.Second, P90: 90 * time.Second, P99: 99 * time.Second}
+	if got != want {
+		t.Errorf("percentiles = %+v, want %+v", got, want)
+	}
+	if many[0] != 100*time.Second {
+		t.Error("input slice was reordered")
+	}
+}
+
+func TestGetDurationStats(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/duration-repo")
+	other := createRepo(t, db, "/tmp/duration-other")
+	base := time.Now().UTC().Add(-time.Hour)
+
+	// finish completes a job and sets its duration; a negative duration
+	// leaves started_at NULL.
+	finish := func(repo *Repo, sha string, d time.Duration) {
+		t.Helper()
+		job := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, sha).ID, sha)
+		claimJob(t, db, "worker-1")
+		if err := db.CompleteJob(job.ID, "codex", "p", "No issues found."); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+		var started any
+		if d >= 0 {
+			started = base.Format(time.RFC3339)
+		}
+		if _, err := db.Exec(`UPDATE review_jobs SET started_at = ?, finished_at = ? WHERE id = ?`,
+			started, base.Add(d).Format(time.RFC3339), job.ID); err != nil {
+			t.Fatalf("set timestamps: %v", err)
+		}
+	}
+
+	finish(repo, "a1", 20*time.Second)
+	finish(repo, "a2", -1) // untimed
+	finish(other, "b1", 10*time.Second)
+	finish(other, "b2", 40*time.Second)
+	finish(other, "b3", 30*time.Second)
+
+	got, err := db.GetDurationStats(repo.ID)
+	if err != nil {
+		t.Fatalf("GetDurationStats: %v", err)
+	}
+	want := DurationPercentiles{Count: 1, P50: 20 * time.Second, P90: 20 * time.Second, P99: 20 * time.Second}
+	if *got != want {
+		t.Errorf("repo stats = %+v, want %+v", *got, want)
+	}
+
+	all, err := db.GetDurationStats(0)
+	if err != nil {
+		t.Fatalf("GetDurationStats: %v", err)
+	}
+	want = DurationPercentiles{Count: 4, P50: 20 * time.Second, P90: 40 * time.Second, P99: 40 * time.Second}
+	if *all != want {
+		t.Errorf("all stats = %+v, want %+v", *all, want)
+	}
+
+	data, err := json.Marshal(all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"count":4,"p50_seconds":20,"p90_seconds":40,"p99_seconds":40}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestGetReviewStats(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/review-stats-repo")
+	other := createRepo(t, db, "/tmp/review-stats-other")
+	now := time.Now().UTC()
+
+	// complete finishes a review with the given output and finished_at.
+	complete := func(repo *Repo, sha, agent, model, output string, finished time.Time) int64 {
+		t.Helper()
+		commit := createCommit(t, db, repo.ID, sha)
+		job, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: agent, Model: model})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		claimJob(t, db, "worker-1")
+		if err := db.CompleteJob(job.ID, agent, "p", output); err != nil {
+			t.Fatalf("CompleteJob: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE review_jobs SET finished_at = ? WHERE id = ?`, finished.Format(time.RFC3339), job.ID); err != nil {
+			t.Fatalf("set finished_at: %v", err)
+		}
+		return job.ID
+	}
+
+	const pass, fail = "No issues found.", "- High: missing bounds check"
+	recent := now.Add(-time.Minute)
+	complete(repo, "a1", "codex", "", pass, recent)
+	complete(repo, "a2", "codex", "o3", pass, recent)
+	complete(repo, "a3", "codex", "o3", fail, recent)
+	legacy := complete(repo, "a4", "gemini", "", fail, recent)
+	complete(repo, "old", "gemini", "", pass, now.Add(-48*time.Hour))
+	complete(other, "b1", "codex", "", fail, recent)
+
+	// Legacy rows have no stored verdict and are classified from output.
+	if _, err := db.Exec(`UPDATE reviews SET verdict_bool = NULL WHERE job_id = ?`, legacy); err != nil {
+		t.Fatalf("clear verdict_bool: %v", err)
+	}
+
+	// Task jobs have no verdict and are excluded.
+	task, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, GitRef: "run", Agent: "codex", Prompt: "do it"})
+	if err != nil {
+		t.Fatalf("EnqueueJob task: %v", err)
+	}
+	claimJob(t, db, "worker-1")
+	if err := db.CompleteJob(task.ID, "codex", "p", "done"); err != nil {
+		t.Fatalf("CompleteJob task: %v", err)
+	}
+
+	stats, err := db.GetReviewStats(StatsOptions{RepoPath: repo.RootPath, Since: now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("GetReviewStats: %v", err)
+	}
+	if stats.VerdictCounts != (VerdictCounts{Total: 4, Passed: 2, Failed: 2}) {
+		t.Errorf("totals = %+v, want 4/2/2", stats.VerdictCounts)
+	}
+	if got := stats.PassRate(); got != 50 {
+		t.Errorf("PassRate() = %v, want 50", got)
+	}
+	want := []ModelReviewStats{
+		{Agent: "codex", Model: "", VerdictCounts: VerdictCounts{Total: 1, Passed: 1}},
+		{Agent: "codex", Model: "o3", VerdictCounts: VerdictCounts{Total: 2, Passed: 1, Failed: 1}},
+		{Agent: "gemini", Model: "", VerdictCounts: VerdictCounts{Total: 1, Failed: 1}},
+	}
+	if !slices.Equal(stats.Models, want) {
+		t.Errorf("models = %+v, want %+v", stats.Models, want)
+	}
+
+	all, err := db.GetReviewStats(StatsOptions{})
+	if err != nil {
+		t.Fatalf("GetReviewStats: %v", err)
+	}
+	if all.Total != 6 {
+		t.Errorf("unfiltered total = %d, want 6", all.Total)
+	}
+}
+
+func TestUsageStats(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, "/tmp/usage-stats-repo")
+	other := createRepo(t, db, "/tmp/usage-stats-other")
+	now := time.Now().UTC()
+
+	complete := func(repo *Repo, sha, agent string, usage *TokenUsage, finished time.Time) {
+		t.Helper()
+		commit := createCommit(t, db, repo.ID, sha)
+		job, err := db.EnqueueJob(EnqueueOpts{RepoID: repo.ID, CommitID: commit.ID, GitRef: sha, Agent: agent})
+		if err != nil {
+			t.Fatalf("EnqueueJob: %v", err)
+		}
+		claimJob(t, db, "worker-1")
+		if err := db.CompleteJobWithUsage(job.ID, agent, "p", "No issues found.", VerdictPolicy{}, usage); err != nil {
+			t.Fatalf("CompleteJobWithUsage: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE review_jobs SET finished_at = ? WHERE id = ?`, finished.Format(time.RFC3339), job.ID); err != nil {
+			t.Fatalf("set finished_at: %v", err)
+		}
+	}
+
+	recent := now.Add(-time.Minute)
+	complete(repo, "a1", "claude-code", &TokenUsage{InputTokens: 1000, OutputTokens: 200, CostUSD: 0.05}, recent)
+	complete(repo, "a2", "claude-code", &TokenUsage{InputTokens: 500, OutputTokens: 100, CostUSD: 0.02}, recent)
+	complete(repo, "a3", "codex", &TokenUsage{InputTokens: 300, OutputTokens: 30}, recent)
+	complete(repo, "a4", "codex", nil, recent) // legacy: no usage recorded
+	complete(other, "b1", "codex", &TokenUsage{InputTokens: 40, OutputTokens: 4}, recent)
+	complete(repo, "old", "codex", &TokenUsage{InputTokens: 9999, OutputTokens: 999}, now.Add(-48*time.Hour))
+
+	rows, err := db.UsageStats(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("UsageStats: %v", err)
+	}
+	want := []UsageRow{
+		{Agent: "claude-code", RepoPath: repo.RootPath, RepoName: repo.Name, Reviews: 2, Reported: 2, InputTokens: 1500, OutputTokens: 300, CostUSD: 0.07},
+		{Agent: "codex", RepoPath: other.RootPath, RepoName: other.Name, Reviews: 1, Reported: 1, InputTokens: 40, OutputTokens: 4},
+		{Agent: "codex", RepoPath: repo.RootPath, RepoName: repo.Name, Reviews: 2, Reported: 1, InputTokens: 300, OutputTokens: 30},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+	for i := range want {
+		got := rows[i]
+		if math.Abs(got.CostUSD-want[i].CostUSD) > 1e-9 {
+			t.Errorf("row %d cost = %v, want %v", i, got.CostUSD, want[i].CostUSD)
+		}
+		got.CostUSD = want[i].CostUSD
+		if got != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// Usage is stored on the review and returned with it.
+	review, err := db.GetReviewByCommitSHA("a1")
+	if err != nil {
+		t.Fatalf("GetReviewByCommitSHA: %v", err)
+	}
+	if review.InputTokens == nil || *review.InputTokens != 1000 || review.CostUSD == nil || *review.CostUSD != 0.05 {
+		t.Errorf("review usage = %v/%v, want 1000/0.05", review.InputTokens, review.CostUSD)
+	}
+	legacy, err := db.GetReviewByCommitSHA("a4")
+	if err != nil {
+		t.Fatalf("GetReviewByCommitSHA: %v", err)
+	}
+	if legacy.InputTokens != nil || legacy.OutputTokens != nil || legacy.CostUSD != nil {
+		t.Errorf("legacy review usage should be nil, got %v/%v/%v", legacy.InputTokens, legacy.OutputTokens, legacy.CostUSD)
+	}
+}
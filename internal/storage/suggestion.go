@@ -0,0 +1,50 @@
+package storage
+
+import "strings"
+
+// ExtractSuggestedPatch returns the unified diff an agent embedded in its
+// review output as a fenced ```diff or ```patch block. Multiple blocks are
+// joined into one patch. Blocks that are not unified diffs (no ---/+++ file
+// headers or no @@ hunk) are ignored, so illustrative snippets don't become
+// patches. Returns "" when the output contains no usable diff.
+func ExtractSuggestedPatch(output string) string {
+	var blocks []string
+	var current []string
+	inBlock := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if !inBlock {
+			lang := strings.ToLower(strings.TrimPrefix(trimmed, "```"))
+			if strings.HasPrefix(trimmed, "```") && (lang == "diff" || lang == "patch") {
+				inBlock = true
+				current = current[:0]
+			}
+			continue
+		}
+		if trimmed == "```" {
+			inBlock = false
+			if block := strings.Join(current, "\n"); isUnifiedDiff(block) {
+				blocks = append(blocks, block+"\n")
+			}
+			continue
+		}
+		current = append(current, strings.TrimSuffix(line, "\r"))
+	}
+	return strings.Join(blocks, "")
+}
+
+// isUnifiedDiff reports whether s has file headers and at least one hunk.
+func isUnifiedDiff(s string) bool {
+	var hasOld, hasNew, hasHunk bool
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			hasOld = true
+		case strings.HasPrefix(line, "+++ "):
+			hasNew = true
+		case strings.HasPrefix(line, "@@"):
+			hasHunk = true
+		}
+	}
+	return hasOld && hasNew && hasHunk
+}
@@ -0,0 +1,82 @@
+package storage
+
+import "testing"
+
+const suggestionReview = "- Medium: greet ignores its argument\n\n" +
+	"Suggested fix:\n\n" +
+	"```diff\n" +
+	"--- a/greet.txt\n" +
+	"+++ b/greet.txt\n" +
+	"@@ -1 +1 @@\n" +
+	"-hello\n" +
+	"+hello, world\n" +
+	"```\n"
+
+func TestExtractSuggestedPatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "fenced diff block",
+			output: suggestionReview,
+			want:   "--- a/greet.txt\n+++ b/greet.txt\n@@ -1 +1 @@\n-hello\n+hello, world\n",
+		},
+		{
+			name:   "no fenced block",
+			output: "No issues found.",
+		},
+		{
+			name:   "snippet without file headers is ignored",
+			output: "```diff\n-old\n+new\n```\n",
+		},
+		{
+			name:   "other languages are ignored",
+			output: "```go\n--- a/x\n+++ b/x\n@@ -1 +1 @@\n```\n",
+		},
+		{
+			name: "multiple blocks are joined",
+			output: "```patch\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-a\n+A\n```\n" +
+				"text\n```diff\n--- a/b.txt\n+++ b/b.txt\n@@ -1 +1 @@\n-b\n+B\n```\n",
+			want: "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-a\n+A\n" +
+				"--- a/b.txt\n+++ b/b.txt\n@@ -1 +1 @@\n-b\n+B\n",
+		},
+		{
+			name:   "unterminated block is ignored",
+			output: "```diff\n--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSuggestedPatch(tt.output); got != tt.want {
+				t.Errorf("ExtractSuggestedPatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteJobStoresSuggestedPatch(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	job := createCompletedJob(t, db, repo.ID, "suggest-sha", suggestionReview)
+
+	got, err := db.GetJobByID(job.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	if got.Patch == nil || *got.Patch != ExtractSuggestedPatch(suggestionReview) {
+		t.Errorf("expected suggested patch to be stored, got %v", got.Patch)
+	}
+
+	plain := createCompletedJob(t, db, repo.ID, "plain-sha", "No issues found.")
+	got, err = db.GetJobByID(plain.ID)
+	if err != nil {
+		t.Fatalf("GetJobByID failed: %v", err)
+	}
+	if got.Patch != nil {
+		t.Errorf("expected no patch for review without a diff, got %q", *got.Patch)
+	}
+}
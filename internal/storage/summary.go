@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSummaryLen caps the derived summary length (in runes) so listings stay
+// to a single short paragraph.
+const maxSummaryLen = 300
+
+// ExtractSummary returns a short TL;DR for review output, suitable for
+// listings. An explicit "Summary:" field or "Summary" heading produced by the
+// agent wins. Otherwise the summary is derived from the first severity-labeled
+// finding, then from the verdict line (e.g. "No issues found."), and finally
+// from the first line of content. Returns "" for empty output.
+func ExtractSummary(output string) string {
+	lines := strings.Split(output, "\n")
+
+	if s := explicitSummary(lines); s != "" {
+		return truncateSummary(s)
+	}
+	if s := firstFindingSummary(lines); s != "" {
+		return truncateSummary(s)
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" && ParseVerdict(line) == "P" {
+			return truncateSummary(cleanSummaryLine(line))
+		}
+	}
+	for _, line := range lines {
+		if s := cleanSummaryLine(line); s != "" {
+			return truncateSummary(s)
+		}
+	}
+	return ""
+}
+
+// summaryOrDerive returns the stored summary when present, otherwise derives
+// one from the review output (legacy rows and synced reviews have no summary).
+func summaryOrDerive(stored string, output string) string {
+	if stored != "" {
+		return stored
+	}
+	return ExtractSummary(output)
+}
+
+// cleanSummaryLine strips markdown emphasis, headers, and list markers from a
+// line while preserving its original case.
+func cleanSummaryLine(line string) string {
+	return stripListMarker(stripMarkdown(strings.TrimSpace(line)))
+}
+
+// explicitSummary finds a "Summary: ..." field or a "Summary" heading and
+// returns the paragraph that belongs to it.
+func explicitSummary(lines []string) string {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		cleaned := cleanSummaryLine(trimmed)
+		if !strings.HasPrefix(strings.ToLower(cleaned), "summary") {
+			continue
+		}
+		rest := strings.TrimSpace(cleaned[len("summary"):])
+		isHeading := strings.HasPrefix(trimmed, "#")
+		switch {
+		case rest == "":
+		case strings.HasPrefix(rest, ":"), strings.HasPrefix(rest, "—"),
+			strings.HasPrefix(rest, "–"), strings.HasPrefix(rest, "- "):
+			rest = strings.TrimSpace(strings.TrimLeft(rest, ":-–— "))
+		case isHeading:
+			// "## Summary of changes" - heading text is not the summary
+			rest = ""
+		default:
+			// "Summary of the change is..." prose, not a field label
+			continue
+		}
+
+		parts := []string{}
+		if rest != "" {
+			parts = append(parts, rest)
+		}
+		for _, next := range lines[i+1:] {
+			nextTrimmed := strings.TrimSpace(next)
+			if nextTrimmed == "" {
+				if len(parts) > 0 {
+					break
+				}
+				continue
+			}
+			if strings.HasPrefix(nextTrimmed, "#") {
+				break
+			}
+			// A new list item after summary text starts the next section
+			// (e.g. "2. **Review Findings**:").
+			if len(parts) > 0 && stripListMarker(nextTrimmed) != nextTrimmed {
+				break
+			}
+			parts = append(parts, cleanSummaryLine(nextTrimmed))
+		}
+		if s := strings.Join(strings.Fields(strings.Join(parts, " ")), " "); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// firstFindingSummary returns the first severity-labeled finding line. For
+// structured findings where the line is only a "Severity: High" field, the
+// rest of that finding's paragraph is included so the summary is meaningful.
+func firstFindingSummary(lines []string) string {
+	lower := make([]string, len(lines))
+	for i, line := range lines {
+		lower[i] = strings.ToLower(line)
+	}
+	for i, line := range lines {
+		if !hasSeverityLabel(line) || isLegendEntry(lower, i) {
+			continue
+		}
+		cleaned := cleanSummaryLine(line)
+		if !strings.HasPrefix(strings.ToLower(cleaned), "severity") {
+			return cleaned
+		}
+		parts := []string{cleaned}
+		for _, next := range lines[i+1:] {
+			if strings.TrimSpace(next) == "" {
+				break
+			}
+			parts = append(parts, cleanSummaryLine(next))
+		}
+		return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	}
+	return ""
+}
+
+// truncateSummary limits s to maxSummaryLen runes, appending "..." when cut.
+func truncateSummary(s string) string {
+	if utf8.RuneCountInString(s) <= maxSummaryLen {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:maxSummaryLen-3])) + "..."
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "empty output",
+			output: "",
+			want:   "",
+		},
+		{
+			name:   "explicit summary field",
+			output: "Summary: Adds retry logic to the sync worker.\n\n## Findings\n- High: missing nil check",
+			want:   "Adds retry logic to the sync worker.",
+		},
+		{
+			name:   "summary heading paragraph",
+			output: "## Summary\nRefactors the queue view\nto use fixed columns.\n\n## Findings\n- Low: unused var",
+			want:   "Refactors the queue view to use fixed columns.",
+		},
+		{
+			name:   "gemini numbered summary",
+			output: "1. **Summary**: Adds a vacuum endpoint.\n2. **Review Findings**:\n   - No issues found.",
+			want:   "Adds a vacuum endpoint.",
+		},
+		{
+			name:   "summary prose is not a field",
+			output: "Summary of the change is unclear.\n- Medium: flaky test in worker_test.go",
+			want:   "Medium: flaky test in worker_test.go",
+		},
+		{
+			name:   "first finding fallback",
+			output: "Reviewed the diff.\n\n- High — SQL injection in search handler\n- Low — typo in comment",
+			want:   "High — SQL injection in search handler",
+		},
+		{
+			name:   "structured severity field includes paragraph",
+			output: "- **Severity**: High\n- **Location**: db.go:42\n- **Problem**: Missing rollback\n\n- **Severity**: Low",
+			want:   "Severity: High Location: db.go:42 Problem: Missing rollback",
+		},
+		{
+			name:   "verdict line fallback",
+			output: "I looked at the change carefully.\n\n**No issues found.**",
+			want:   "No issues found.",
+		},
+		{
+			name:   "first line fallback",
+			output: "\n\n# Review notes\nSome text",
+			want:   "Review notes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSummary(tt.output); got != tt.want {
+				t.Errorf("ExtractSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractSummaryTruncates(t *testing.T) {
+	long := "Summary: " + strings.Repeat("é", maxSummaryLen*2)
+	got := ExtractSummary(long)
+	if n := len([]rune(got)); n != maxSummaryLen {
+		t.Errorf("expected %d runes, got %d", maxSummaryLen, n)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("expected truncated summary to end with ..., got %q", got)
+	}
+}
+
+func TestCompleteJobStoresSummary(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	output := "Summary: Tightens input validation.\n\n- Medium: missing test for empty input"
+	job := createCompletedJob(t, db, repo.ID, "summary-sha", output)
+
+	var stored string
+	if err := db.QueryRow(`SELECT summary FROM reviews WHERE job_id = ?`, job.ID).Scan(&stored); err != nil {
+		t.Fatalf("query summary: %v", err)
+	}
+	if stored != "Tightens input validation." {
+		t.Errorf("stored summary = %q", stored)
+	}
+
+	review, err := db.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID failed: %v", err)
+	}
+	if review.Summary != stored {
+		t.Errorf("review.Summary = %q, want %q", review.Summary, stored)
+	}
+	if review.Output != output {
+		t.Errorf("review.Output should be unchanged, got %q", review.Output)
+	}
+
+	jobs, err := db.ListJobs("", repo.RootPath, 10, 0)
+	if err != nil {
+		t.Fatalf("ListJobs failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Summary != stored {
+		t.Errorf("ListJobs summary mismatch: %+v", jobs)
+	}
+}
+
+func TestLegacyReviewSummaryDerived(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	job := createCompletedJob(t, db, repo.ID, "legacy-sha", "- High: leaked file handle in reader")
+
+	if _, err := db.Exec(`UPDATE reviews SET summary = NULL WHERE job_id = ?`, job.ID); err != nil {
+		t.Fatalf("clear summary: %v", err)
+	}
+
+	review, err := db.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID failed: %v", err)
+	}
+	if review.Summary != "High: leaked file handle in reader" {
+		t.Errorf("derived summary = %q", review.Summary)
+	}
+
+	jobs, err := db.ListJobs("", repo.RootPath, 10, 0)
+	if err != nil {
+		t.Fatalf("ListJobs failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Summary != review.Summary {
+		t.Errorf("ListJobs summary mismatch: %+v", jobs)
+	}
+}
@@ -11,11 +11,12 @@ import (
 
 // Sync state keys
 const (
-	SyncStateMachineID        = "machine_id"
-	SyncStateLastJobCursor    = "last_job_cursor"    // ID of last synced job
-	SyncStateLastReviewCursor = "last_review_cursor" // Composite cursor for reviews (updated_at,id)
-	SyncStateLastResponseID   = "last_response_id"   // ID of last synced response
-	SyncStateSyncTargetID     = "sync_target_id"     // Database ID of last synced Postgres
+	SyncStateMachineID          = "machine_id"
+	SyncStateLastJobCursor      = "last_job_cursor"      // ID of last synced job
+	SyncStateLastReviewCursor   = "last_review_cursor"   // Composite cursor for reviews (updated_at,id)
+	SyncStateLastResponseCursor = "last_response_cursor" // Composite cursor for responses (updated_at,id)
+	SyncStateSyncTargetID       = "sync_target_id"       // Database ID of last synced Postgres
+	SyncStateQueuePaused        = "queue_paused"         // "1" while the user has paused the queue (roborev pause)
 )
 
 // GetSyncState retrieves a value from the sync_state table.
@@ -487,26 +488,32 @@ func (db *DB) MarkReviewsSynced(reviewIDs []int64) error {
 
 // SyncableResponse contains response data needed for sync
 type SyncableResponse struct {
-	ID              int64
-	UUID            string
-	JobID           int64
-	JobUUID         string
-	Responder       string
-	Response        string
-	SourceMachineID string
-	CreatedAt       time.Time
+	ID                 int64
+	UUID               string
+	JobID              int64
+	JobUUID            string
+	Responder          string
+	Response           string
+	SourceMachineID    string
+	UpdatedByMachineID string // The pushing machine
+	ParentResponseUUID string // Comment this one replies to; empty for top-level comments
+	CreatedAt          time.Time
+	DeletedAt          *time.Time // Tombstone: set when the comment was deleted
 }
 
-// GetCommentsToSync returns comments created locally that need to be pushed.
+// GetCommentsToSync returns comments created, edited or deleted locally
+// that need to be pushed. Edits and deletes clear synced_at, so comments
+// pulled from other machines are pushed again once changed here.
 // Only returns comments whose parent job has already been synced.
 func (db *DB) GetCommentsToSync(machineID string, limit int) ([]SyncableResponse, error) {
 	rows, err := db.Query(`
 		SELECT
-			r.id, r.uuid, r.job_id, j.uuid,
-			r.responder, r.response, r.source_machine_id, r.created_at
+			r.id, r.uuid, r.job_id, j.uuid, COALESCE(p.uuid, ''),
+			r.responder, r.response, r.source_machine_id, r.created_at, r.deleted_at
 		FROM responses r
 		JOIN review_jobs j ON r.job_id = j.id
-		WHERE r.source_machine_id = ?
+		LEFT JOIN responses p ON r.parent_response_id = p.id
+		WHERE (r.source_machine_id = ? OR r.updated_at IS NOT NULL)
 		AND r.uuid IS NOT NULL
 		AND j.uuid IS NOT NULL
 		AND r.synced_at IS NULL
@@ -524,10 +531,11 @@ func (db *DB) GetCommentsToSync(machineID string, limit int) ([]SyncableResponse
 		var r SyncableResponse
 		var createdAt string
 		var jobID sql.NullInt64
+		var deletedAt sql.NullString
 
 		err := rows.Scan(
-			&r.ID, &r.UUID, &jobID, &r.JobUUID,
-			&r.Responder, &r.Response, &r.SourceMachineID, &createdAt,
+			&r.ID, &r.UUID, &jobID, &r.JobUUID, &r.ParentResponseUUID,
+			&r.Responder, &r.Response, &r.SourceMachineID, &createdAt, &deletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan response: %w", err)
@@ -536,7 +544,12 @@ func (db *DB) GetCommentsToSync(machineID string, limit int) ([]SyncableResponse
 		if jobID.Valid {
 			r.JobID = jobID.Int64
 		}
+		r.UpdatedByMachineID = machineID
 		r.CreatedAt = parseSQLiteTime(createdAt)
+		if deletedAt.Valid {
+			t := parseSQLiteTime(deletedAt.String)
+			r.DeletedAt = &t
+		}
 		responses = append(responses, r)
 	}
 	return responses, rows.Err()
@@ -625,7 +638,8 @@ func (db *DB) UpsertPulledReview(r PulledReview) error {
 	return err
 }
 
-// UpsertPulledResponse inserts a response from PostgreSQL into SQLite.
+// UpsertPulledResponse inserts a response from PostgreSQL into SQLite, or
+// applies a pulled edit or tombstone to one it already has.
 func (db *DB) UpsertPulledResponse(r PulledResponse) error {
 	// First, find the job_id by uuid
 	var jobID int64
@@ -638,13 +652,36 @@ func (db *DB) UpsertPulledResponse(r PulledResponse) error {
 		return fmt.Errorf("find job for response: %w", err)
 	}
 
+	var deletedAt any
+	if r.DeletedAt != nil {
+		deletedAt = r.DeletedAt.UTC().Format(time.RFC3339)
+	}
+	// Link a reply to its parent by UUID. A parent not pulled yet leaves
+	// the reply top-level until the reply is pulled again.
+	var parentID any
+	if r.ParentResponseUUID != "" {
+		var id int64
+		err := db.QueryRow(`SELECT id FROM responses WHERE uuid = ?`, r.ParentResponseUUID).Scan(&id)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("find parent response: %w", err)
+		}
+		if err == nil {
+			parentID = id
+		}
+	}
+
 	now := time.Now().UTC().Format(time.RFC3339)
 	_, err = db.Exec(`
 		INSERT INTO responses (
-			uuid, job_id, responder, response, source_machine_id, created_at, synced_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(uuid) DO NOTHING
-	`, r.UUID, jobID, r.Responder, r.Response, r.SourceMachineID, r.CreatedAt.Format(time.RFC3339), now)
+			uuid, job_id, parent_response_id, responder, response, source_machine_id, created_at, deleted_at, synced_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(uuid) DO UPDATE SET
+			parent_response_id = COALESCE(responses.parent_response_id, excluded.parent_response_id),
+			response = excluded.response,
+			deleted_at = excluded.deleted_at,
+			synced_at = excluded.synced_at
+		WHERE responses.synced_at IS NOT NULL
+	`, r.UUID, jobID, parentID, r.Responder, r.Response, r.SourceMachineID, r.CreatedAt.Format(time.RFC3339), deletedAt, now)
 	return err
 }
 
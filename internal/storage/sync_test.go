@@ -1983,6 +1983,163 @@ func TestGetCommentsToSync_RequiresJobSynced(t *testing.T) {
 	}
 }
 
+func TestGetCommentsToSync_EditsAndTombstones(t *testing.T) {
+	h := newSyncTestHelper(t)
+
+	job := h.createCompletedJob("comment-edit-sha")
+	if err := h.db.MarkJobSynced(job.ID); err != nil {
+		t.Fatalf("Failed to mark job synced: %v", err)
+	}
+	edited, err := h.db.AddCommentToJob(job.ID, "alice", "typo")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+	deleted, err := h.db.AddCommentToJob(job.ID, "bob", "wrong review")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+	if err := h.db.MarkCommentsSynced([]int64{edited.ID, deleted.ID}); err != nil {
+		t.Fatalf("MarkCommentsSynced failed: %v", err)
+	}
+
+	if err := h.db.UpdateComment(edited.ID, "alice", "fixed"); err != nil {
+		t.Fatalf("UpdateComment failed: %v", err)
+	}
+	if err := h.db.DeleteComment(deleted.ID); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+
+	responses, err := h.db.GetCommentsToSync(h.machineID, 100)
+	if err != nil {
+		t.Fatalf("GetCommentsToSync failed: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("Expected the edit and the tombstone to be pushed, got %+v", responses)
+	}
+	for _, r := range responses {
+		switch r.ID {
+		case edited.ID:
+			if r.Response != "fixed" || r.DeletedAt != nil {
+				t.Errorf("Expected edited text and no tombstone, got %+v", r)
+			}
+		case deleted.ID:
+			if r.DeletedAt == nil {
+				t.Errorf("Expected a tombstone for the deleted comment, got %+v", r)
+			}
+		}
+	}
+}
+
+func TestUpsertPulledResponse_AppliesTombstone(t *testing.T) {
+	h := newSyncTestHelper(t)
+
+	job := h.createCompletedJob("pulled-tombstone-sha")
+	var jobUUID string
+	if err := h.db.QueryRow(`SELECT uuid FROM review_jobs WHERE id = ?`, job.ID).Scan(&jobUUID); err != nil {
+		t.Fatalf("Failed to get job uuid: %v", err)
+	}
+	pr := PulledResponse{
+		UUID:            GenerateUUID(),
+		JobUUID:         jobUUID,
+		Responder:       "remote-user",
+		Response:        "remote comment",
+		SourceMachineID: GenerateUUID(),
+		CreatedAt:       time.Now(),
+	}
+	if err := h.db.UpsertPulledResponse(pr); err != nil {
+		t.Fatalf("UpsertPulledResponse failed: %v", err)
+	}
+	comments, err := h.db.GetCommentsForJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("Expected the pulled comment, got %+v", comments)
+	}
+
+	deletedAt := time.Now()
+	pr.DeletedAt = &deletedAt
+	if err := h.db.UpsertPulledResponse(pr); err != nil {
+		t.Fatalf("UpsertPulledResponse (tombstone) failed: %v", err)
+	}
+
+	comments, err = h.db.GetCommentsForJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	if len(comments) != 0 {
+		t.Errorf("Expected the pulled tombstone to hide the comment, got %+v", comments)
+	}
+}
+
+func TestCommentSync_PreservesReplyThreads(t *testing.T) {
+	h := newSyncTestHelper(t)
+
+	job := h.createCompletedJob("reply-sync-sha")
+	if err := h.db.MarkJobSynced(job.ID); err != nil {
+		t.Fatalf("Failed to mark job synced: %v", err)
+	}
+	parent, err := h.db.AddCommentToJob(job.ID, "alice", "why this change?")
+	if err != nil {
+		t.Fatalf("AddCommentToJob failed: %v", err)
+	}
+	reply, err := h.db.AddReply(parent.ID, "bob", "see the linked issue")
+	if err != nil {
+		t.Fatalf("AddReply failed: %v", err)
+	}
+
+	responses, err := h.db.GetCommentsToSync(h.machineID, 100)
+	if err != nil {
+		t.Fatalf("GetCommentsToSync failed: %v", err)
+	}
+	pushed := map[int64]SyncableResponse{}
+	for _, r := range responses {
+		pushed[r.ID] = r
+	}
+	if got := pushed[parent.ID].ParentResponseUUID; got != "" {
+		t.Errorf("top-level comment pushed with parent %q", got)
+	}
+	if got := pushed[reply.ID].ParentResponseUUID; got != parent.UUID {
+		t.Errorf("reply pushed with parent %q, want %q", got, parent.UUID)
+	}
+
+	// Pull the same thread into another machine's database, reply first:
+	// the reply stays top-level until it is pulled again after its parent.
+	other := newSyncTestHelper(t)
+	otherJob := other.createCompletedJob("reply-sync-sha")
+	var jobUUID string
+	if err := other.db.QueryRow(`SELECT uuid FROM review_jobs WHERE id = ?`, otherJob.ID).Scan(&jobUUID); err != nil {
+		t.Fatalf("Failed to get job uuid: %v", err)
+	}
+	pulled := func(r SyncableResponse) PulledResponse {
+		return PulledResponse{
+			UUID:               r.UUID,
+			JobUUID:            jobUUID,
+			Responder:          r.Responder,
+			Response:           r.Response,
+			SourceMachineID:    r.SourceMachineID,
+			CreatedAt:          r.CreatedAt,
+			ParentResponseUUID: r.ParentResponseUUID,
+		}
+	}
+	for _, r := range []SyncableResponse{pushed[reply.ID], pushed[parent.ID], pushed[reply.ID]} {
+		if err := other.db.UpsertPulledResponse(pulled(r)); err != nil {
+			t.Fatalf("UpsertPulledResponse failed: %v", err)
+		}
+	}
+
+	comments, err := other.db.GetCommentsForJob(otherJob.ID)
+	if err != nil {
+		t.Fatalf("GetCommentsForJob failed: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("Expected both pulled comments, got %+v", comments)
+	}
+	if comments[0].Responder != "alice" || comments[1].Responder != "bob" || comments[1].ParentResponseID == nil || *comments[1].ParentResponseID != comments[0].ID {
+		t.Errorf("Expected the reply threaded under its parent, got %+v", comments)
+	}
+}
+
 // TestGetJobsToSync_RequiresRepoIdentity verifies that jobs without a
 // repo identity are still returned (the identity check happens at push time).
 func TestGetJobsToSync_RequiresRepoIdentity(t *testing.T) {
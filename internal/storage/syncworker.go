@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -440,7 +438,7 @@ func (w *SyncWorker) connect(timeout time.Duration) (bool, error) {
 			return false, fmt.Errorf("clear synced_at: %w", err)
 		}
 		// Also clear pull cursors so we pull all data from the new database
-		for _, key := range []string{SyncStateLastJobCursor, SyncStateLastReviewCursor, SyncStateLastResponseID} {
+		for _, key := range []string{SyncStateLastJobCursor, SyncStateLastReviewCursor, SyncStateLastResponseCursor} {
 			if err := w.db.SetSyncState(key, ""); err != nil {
 				pool.Close()
 				return false, fmt.Errorf("clear %s: %w", key, err)
@@ -786,24 +784,13 @@ func (w *SyncWorker) pullChangesWithStats(ctx context.Context, pool *PgPool) (pu
 	}
 
 	// Pull responses
-	responseIDStr, err := w.db.GetSyncState(SyncStateLastResponseID)
+	responseCursor, err := w.db.GetSyncState(SyncStateLastResponseCursor)
 	if err != nil {
 		return stats, fmt.Errorf("get response cursor: %w", err)
 	}
-	var responseID int64
-	if responseIDStr != "" {
-		parsed, err := strconv.ParseInt(
-			strings.TrimSpace(responseIDStr), 10, 64,
-		)
-		if err != nil {
-			log.Printf("Sync: malformed response cursor %q, resetting to 0", responseIDStr)
-		} else {
-			responseID = parsed
-		}
-	}
 
 	for {
-		responses, newID, err := pool.PullResponses(ctx, machineID, responseID, 100)
+		responses, newCursor, err := pool.PullResponses(ctx, machineID, responseCursor, 100)
 		if err != nil {
 			return stats, fmt.Errorf("pull responses: %w", err)
 		}
@@ -813,12 +800,15 @@ func (w *SyncWorker) pullChangesWithStats(ctx context.Context, pool *PgPool) (pu
 
 		for _, r := range responses {
 			pr := PulledResponse{
-				UUID:            r.UUID,
-				JobUUID:         r.JobUUID,
-				Responder:       r.Responder,
-				Response:        r.Response,
-				SourceMachineID: r.SourceMachineID,
-				CreatedAt:       r.CreatedAt,
+				UUID:               r.UUID,
+				JobUUID:            r.JobUUID,
+				Responder:          r.Responder,
+				Response:           r.Response,
+				SourceMachineID:    r.SourceMachineID,
+				CreatedAt:          r.CreatedAt,
+				UpdatedAt:          r.UpdatedAt,
+				DeletedAt:          r.DeletedAt,
+				ParentResponseUUID: r.ParentResponseUUID,
 			}
 			if err := w.db.UpsertPulledResponse(pr); err != nil {
 				// Don't advance cursor if any upsert fails - we'll retry next sync
@@ -827,8 +817,8 @@ func (w *SyncWorker) pullChangesWithStats(ctx context.Context, pool *PgPool) (pu
 			stats.Responses++
 		}
 
-		responseID = newID
-		if err := w.db.SetSyncState(SyncStateLastResponseID, fmt.Sprintf("%d", responseID)); err != nil {
+		responseCursor = newCursor
+		if err := w.db.SetSyncState(SyncStateLastResponseCursor, responseCursor); err != nil {
 			return stats, fmt.Errorf("save response cursor: %w", err)
 		}
 
@@ -1,6 +1,9 @@
 package storage
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 const (
 	VerdictPass = "P"
@@ -981,3 +984,216 @@ func TestParseVerdict(t *testing.T) {
 		})
 	})
 }
+
+func TestParseVerdictWithPolicy(t *testing.T) {
+	mediumFinding := "## Findings\n- Medium: missing error check in handler.go\n  The error from Close is dropped."
+	tests := []struct {
+		name   string
+		output string
+		policy VerdictPolicy
+		want   string
+	}{
+		{
+			name:   "zero policy matches ParseVerdict on findings",
+			output: mediumFinding,
+			want:   VerdictFail,
+		},
+		{
+			name:   "zero policy matches ParseVerdict on pass",
+			output: "No issues found.",
+			want:   VerdictPass,
+		},
+		{
+			name:   "finding below threshold passes",
+			output: mediumFinding,
+			policy: VerdictPolicy{FailThreshold: "high"},
+			want:   VerdictPass,
+		},
+		{
+			name:   "finding at threshold fails",
+			output: mediumFinding,
+			policy: VerdictPolicy{FailThreshold: "medium"},
+			want:   VerdictFail,
+		},
+		{
+			name:   "structured severity field honors threshold",
+			output: "- **Severity**: Low\n- **Location**: main.go:10\n- **Problem**: naming",
+			policy: VerdictPolicy{FailThreshold: "medium"},
+			want:   VerdictPass,
+		},
+		{
+			name:   "ignored finding matched on continuation line",
+			output: mediumFinding,
+			policy: VerdictPolicy{FindingIgnore: []string{"CLOSE is dropped"}},
+			want:   VerdictPass,
+		},
+		{
+			name:   "ignore only drops matching findings",
+			output: mediumFinding + "\n- High: SQL injection in search.go",
+			policy: VerdictPolicy{FindingIgnore: []string{"handler.go"}},
+			want:   VerdictFail,
+		},
+		{
+			name:   "blocking keyword fails a passing review",
+			output: "No issues found. Note: contains TODO(security) marker.",
+			policy: VerdictPolicy{BlockingKeywords: []string{"todo(security)"}},
+			want:   VerdictFail,
+		},
+		{
+			name:   "blocking keyword overrides threshold",
+			output: "- Low: DO NOT MERGE until migration lands",
+			policy: VerdictPolicy{FailThreshold: "critical", BlockingKeywords: []string{"do not merge"}},
+			want:   VerdictFail,
+		},
+		{
+			name:   "empty patterns are ignored",
+			output: mediumFinding,
+			policy: VerdictPolicy{BlockingKeywords: []string{""}, FindingIgnore: []string{" "}},
+			want:   VerdictFail,
+		},
+		{
+			name:   "empty ignore rule never matches",
+			output: mediumFinding,
+			policy: VerdictPolicy{IgnoreRules: []IgnoreRule{{}}},
+			want:   VerdictFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseVerdictWithPolicy(tt.output, tt.policy); got != tt.want {
+				t.Errorf("ParseVerdictWithPolicy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	ignoredHigh := "- High: SQL injection in `internal/testdata/fixture.go:12`"
+	otherHigh := "- High: SQL injection in `internal/search/query.go:40`"
+	rules := []IgnoreRule{{Pattern: "sql injection", Path: "internal/testdata/"}}
+
+	t.Run("ignored high finding passes", func(t *testing.T) {
+		if got := ParseVerdictWithPolicy(ignoredHigh, VerdictPolicy{IgnoreRules: rules}); got != VerdictPass {
+			t.Errorf("ParseVerdictWithPolicy() = %q, want %q", got, VerdictPass)
+		}
+	})
+
+	t.Run("non-ignored high finding still fails", func(t *testing.T) {
+		output := ignoredHigh + "\n" + otherHigh
+		if got := ParseVerdictWithPolicy(output, VerdictPolicy{IgnoreRules: rules}); got != VerdictFail {
+			t.Errorf("ParseVerdictWithPolicy() = %q, want %q", got, VerdictFail)
+		}
+	})
+
+	matchTests := []struct {
+		name string
+		rule IgnoreRule
+		text string
+		want bool
+	}{
+		{"pattern only", IgnoreRule{Pattern: "Unused Variable"}, "- low: unused variable x", true},
+		{"path glob full path", IgnoreRule{Path: "internal/*/fixture.go"}, "- low: bad name in internal/testdata/fixture.go:3", true},
+		{"path glob basename", IgnoreRule{Path: "*_test.go"}, "- low: flaky sleep in (worker_test.go:88)", true},
+		{"path directory prefix", IgnoreRule{Path: "vendor"}, "- high: bug in vendor/lib/a.go", true},
+		{"path mismatch", IgnoreRule{Path: "vendor/"}, "- high: bug in internal/vendored.go", false},
+		{"both must match", IgnoreRule{Pattern: "nil check", Path: "*.go"}, "- high: race in db.go", false},
+		{"empty rule", IgnoreRule{}, "- high: anything", false},
+	}
+	for _, tt := range matchTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.matches(tt.text); got != tt.want {
+				t.Errorf("matches(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkSuppressedFindings(t *testing.T) {
+	output := "## Findings\n- High: SQL injection in testdata/q.go\n  Only in fixtures.\n- Medium: missing test"
+	policy := VerdictPolicy{IgnoreRules: []IgnoreRule{{Path: "testdata/"}}}
+
+	want := "## Findings\n- High: SQL injection in testdata/q.go [suppressed]\n  Only in fixtures.\n- Medium: missing test"
+	got := MarkSuppressedFindings(output, policy)
+	if got != want {
+		t.Errorf("MarkSuppressedFindings() = %q, want %q", got, want)
+	}
+	if again := MarkSuppressedFindings(got, policy); again != got {
+		t.Errorf("marking should be idempotent, got %q", again)
+	}
+	if unchanged := MarkSuppressedFindings(output, VerdictPolicy{}); unchanged != output {
+		t.Errorf("empty policy changed output: %q", unchanged)
+	}
+}
+
+func TestCompleteJobWithPolicyMarksSuppressed(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	job := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "suppress-sha").ID, "suppress-sha")
+	claimJob(t, db, "worker-1")
+
+	policy := VerdictPolicy{IgnoreRules: []IgnoreRule{{Pattern: "known false positive"}}}
+	if err := db.CompleteJobWithPolicy(job.ID, "codex", "p", "- High: known false positive in auth.go", policy); err != nil {
+		t.Fatalf("CompleteJobWithPolicy: %v", err)
+	}
+	review, err := db.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID: %v", err)
+	}
+	if !strings.HasSuffix(review.Output, "[suppressed]") {
+		t.Errorf("expected suppressed marker, got %q", review.Output)
+	}
+	if review.VerdictBool == nil || *review.VerdictBool != 1 {
+		t.Errorf("expected stored pass verdict, got %v", review.VerdictBool)
+	}
+}
+
+func TestCountSeverities(t *testing.T) {
+	output := `Findings:
+- Critical: SQL injection in query builder
+- High: missing auth check in handler.go
+- **Medium** — unbounded retry loop
+- Low: typo in comment
+- Low: known false positive in auth.go
+
+No issues found in the remaining files.`
+
+	got := CountSeverities(output, VerdictPolicy{FindingIgnore: []string{"known false positive"}})
+	want := SeverityCounts{Critical: 1, High: 1, Medium: 1, Low: 1}
+	if got != want {
+		t.Errorf("CountSeverities = %+v, want %+v", got, want)
+	}
+	if got.Total() != 4 {
+		t.Errorf("Total = %d, want 4", got.Total())
+	}
+
+	if got := CountSeverities("No issues found.", VerdictPolicy{}); got.Total() != 0 {
+		t.Errorf("expected no findings, got %+v", got)
+	}
+}
+
+func TestCompleteJobStoresSeverityCounts(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	repo := createRepo(t, db, t.TempDir())
+	job := enqueueJob(t, db, repo.ID, createCommit(t, db, repo.ID, "counts-sha").ID, "counts-sha")
+	claimJob(t, db, "worker-1")
+
+	output := "- Low: unused variable\n- Low: missing doc comment"
+	if err := db.CompleteJobWithPolicy(job.ID, "codex", "p", output, VerdictPolicy{FailThreshold: "medium"}); err != nil {
+		t.Fatalf("CompleteJobWithPolicy: %v", err)
+	}
+	review, err := db.GetReviewByJobID(job.ID)
+	if err != nil {
+		t.Fatalf("GetReviewByJobID: %v", err)
+	}
+	if review.VerdictBool == nil || *review.VerdictBool != 1 {
+		t.Errorf("expected low findings below threshold to pass, got %v", review.VerdictBool)
+	}
+	if review.SeverityCounts == nil || *review.SeverityCounts != (SeverityCounts{Low: 2}) {
+		t.Errorf("expected 2 low findings stored, got %+v", review.SeverityCounts)
+	}
+}